@@ -1,129 +0,0 @@
-// FILE: proto/content/v1/content.proto
-
-// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
-// versions:
-// - protoc-gen-go-grpc v1.5.1
-// - protoc             v5.29.3
-// source: proto/content/content.proto
-
-package content
-
-import (
-	context "context"
-	grpc "google.golang.org/grpc"
-	codes "google.golang.org/grpc/codes"
-	status "google.golang.org/grpc/status"
-)
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-// Requires gRPC-Go v1.64.0 or later.
-const _ = grpc.SupportPackageIsVersion9
-
-const (
-	ContentService_GetVocabularyBatch_FullMethodName = "/content.ContentService/GetVocabularyBatch"
-)
-
-// ContentServiceClient is the client API for ContentService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
-//
-// The ContentService provides access to textbook content.
-type ContentServiceClient interface {
-	// GetVocabularyBatch retrieves full details for a list of vocabulary IDs.
-	GetVocabularyBatch(ctx context.Context, in *GetVocabularyBatchRequest, opts ...grpc.CallOption) (*GetVocabularyBatchResponse, error)
-}
-
-type contentServiceClient struct {
-	cc grpc.ClientConnInterface
-}
-
-func NewContentServiceClient(cc grpc.ClientConnInterface) ContentServiceClient {
-	return &contentServiceClient{cc}
-}
-
-func (c *contentServiceClient) GetVocabularyBatch(ctx context.Context, in *GetVocabularyBatchRequest, opts ...grpc.CallOption) (*GetVocabularyBatchResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(GetVocabularyBatchResponse)
-	err := c.cc.Invoke(ctx, ContentService_GetVocabularyBatch_FullMethodName, in, out, cOpts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-// ContentServiceServer is the server API for ContentService service.
-// All implementations must embed UnimplementedContentServiceServer
-// for forward compatibility.
-//
-// The ContentService provides access to textbook content.
-type ContentServiceServer interface {
-	// GetVocabularyBatch retrieves full details for a list of vocabulary IDs.
-	GetVocabularyBatch(context.Context, *GetVocabularyBatchRequest) (*GetVocabularyBatchResponse, error)
-	mustEmbedUnimplementedContentServiceServer()
-}
-
-// UnimplementedContentServiceServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedContentServiceServer struct{}
-
-func (UnimplementedContentServiceServer) GetVocabularyBatch(context.Context, *GetVocabularyBatchRequest) (*GetVocabularyBatchResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetVocabularyBatch not implemented")
-}
-func (UnimplementedContentServiceServer) mustEmbedUnimplementedContentServiceServer() {}
-func (UnimplementedContentServiceServer) testEmbeddedByValue()                        {}
-
-// UnsafeContentServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to ContentServiceServer will
-// result in compilation errors.
-type UnsafeContentServiceServer interface {
-	mustEmbedUnimplementedContentServiceServer()
-}
-
-func RegisterContentServiceServer(s grpc.ServiceRegistrar, srv ContentServiceServer) {
-	// If the following call pancis, it indicates UnimplementedContentServiceServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
-	}
-	s.RegisterService(&ContentService_ServiceDesc, srv)
-}
-
-func _ContentService_GetVocabularyBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetVocabularyBatchRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(ContentServiceServer).GetVocabularyBatch(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: ContentService_GetVocabularyBatch_FullMethodName,
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ContentServiceServer).GetVocabularyBatch(ctx, req.(*GetVocabularyBatchRequest))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
-// ContentService_ServiceDesc is the grpc.ServiceDesc for ContentService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var ContentService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "content.ContentService",
-	HandlerType: (*ContentServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "GetVocabularyBatch",
-			Handler:    _ContentService_GetVocabularyBatch_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "proto/content/content.proto",
-}
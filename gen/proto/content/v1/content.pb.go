@@ -0,0 +1,1297 @@
+// FILE: proto/content/v1/content.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: content/v1/content.proto
+
+package contentv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+	v1 "wise-owl/gen/proto/common/v1"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// The request message containing a list of vocabulary IDs. Long lists are served in pages: each
+// call looks up the next page of the IDs and returns a token for the rest. Callers send the same
+// IDs with each page's token until the token comes back empty.
+type GetVocabularyBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VocabularyIds []string               `protobuf:"bytes,1,rep,name=vocabulary_ids,json=vocabularyIds,proto3" json:"vocabulary_ids,omitempty"`
+	Pagination    *v1.Pagination         `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"` // Pages default to 1000 IDs, at most 1000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVocabularyBatchRequest) Reset() {
+	*x = GetVocabularyBatchRequest{}
+	mi := &file_content_v1_content_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVocabularyBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVocabularyBatchRequest) ProtoMessage() {}
+
+func (x *GetVocabularyBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVocabularyBatchRequest.ProtoReflect.Descriptor instead.
+func (*GetVocabularyBatchRequest) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetVocabularyBatchRequest) GetVocabularyIds() []string {
+	if x != nil {
+		return x.VocabularyIds
+	}
+	return nil
+}
+
+func (x *GetVocabularyBatchRequest) GetPagination() *v1.Pagination {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+// The response message containing a map of vocabulary IDs to Vocabulary objects
+// for efficient lookup on the client side (the quiz-service).
+type GetVocabularyBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         map[string]*Vocabulary `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // The words of this page's IDs; unknown IDs are left out
+	PageInfo      *v1.PageInfo           `protobuf:"bytes,2,opt,name=page_info,json=pageInfo,proto3" json:"page_info,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVocabularyBatchResponse) Reset() {
+	*x = GetVocabularyBatchResponse{}
+	mi := &file_content_v1_content_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVocabularyBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVocabularyBatchResponse) ProtoMessage() {}
+
+func (x *GetVocabularyBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVocabularyBatchResponse.ProtoReflect.Descriptor instead.
+func (*GetVocabularyBatchResponse) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetVocabularyBatchResponse) GetItems() map[string]*Vocabulary {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *GetVocabularyBatchResponse) GetPageInfo() *v1.PageInfo {
+	if x != nil {
+		return x.PageInfo
+	}
+	return nil
+}
+
+// The request message for GetDistractors. At most 100 vocabulary IDs are served per call.
+type GetDistractorsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VocabularyIds []string               `protobuf:"bytes,1,rep,name=vocabulary_ids,json=vocabularyIds,proto3" json:"vocabulary_ids,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"` // Distractors per word; defaults to 3
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDistractorsRequest) Reset() {
+	*x = GetDistractorsRequest{}
+	mi := &file_content_v1_content_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDistractorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDistractorsRequest) ProtoMessage() {}
+
+func (x *GetDistractorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDistractorsRequest.ProtoReflect.Descriptor instead.
+func (*GetDistractorsRequest) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetDistractorsRequest) GetVocabularyIds() []string {
+	if x != nil {
+		return x.VocabularyIds
+	}
+	return nil
+}
+
+func (x *GetDistractorsRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// DistractorList holds the distractors for one word, most plausible first.
+type DistractorList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*Vocabulary          `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DistractorList) Reset() {
+	*x = DistractorList{}
+	mi := &file_content_v1_content_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DistractorList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DistractorList) ProtoMessage() {}
+
+func (x *DistractorList) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DistractorList.ProtoReflect.Descriptor instead.
+func (*DistractorList) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DistractorList) GetItems() []*Vocabulary {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// The response message mapping each known vocabulary ID to its distractors.
+type GetDistractorsResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Distractors   map[string]*DistractorList `protobuf:"bytes,1,rep,name=distractors,proto3" json:"distractors,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDistractorsResponse) Reset() {
+	*x = GetDistractorsResponse{}
+	mi := &file_content_v1_content_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDistractorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDistractorsResponse) ProtoMessage() {}
+
+func (x *GetDistractorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDistractorsResponse.ProtoReflect.Descriptor instead.
+func (*GetDistractorsResponse) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetDistractorsResponse) GetDistractors() map[string]*DistractorList {
+	if x != nil {
+		return x.Distractors
+	}
+	return nil
+}
+
+// Vocabulary message mirrors the structure of our Go model.
+// 'optional' is used for fields that can be null in the database.
+type GetLessonBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LessonIds     []string               `protobuf:"bytes,1,rep,name=lesson_ids,json=lessonIds,proto3" json:"lesson_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLessonBatchRequest) Reset() {
+	*x = GetLessonBatchRequest{}
+	mi := &file_content_v1_content_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLessonBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLessonBatchRequest) ProtoMessage() {}
+
+func (x *GetLessonBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLessonBatchRequest.ProtoReflect.Descriptor instead.
+func (*GetLessonBatchRequest) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetLessonBatchRequest) GetLessonIds() []string {
+	if x != nil {
+		return x.LessonIds
+	}
+	return nil
+}
+
+type GetLessonBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lessons       map[string]*Lesson     `protobuf:"bytes,1,rep,name=lessons,proto3" json:"lessons,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Lessons that do not exist are absent
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLessonBatchResponse) Reset() {
+	*x = GetLessonBatchResponse{}
+	mi := &file_content_v1_content_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLessonBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLessonBatchResponse) ProtoMessage() {}
+
+func (x *GetLessonBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLessonBatchResponse.ProtoReflect.Descriptor instead.
+func (*GetLessonBatchResponse) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetLessonBatchResponse) GetLessons() map[string]*Lesson {
+	if x != nil {
+		return x.Lessons
+	}
+	return nil
+}
+
+type Lesson struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	VocabularyCount int32                  `protobuf:"varint,2,opt,name=vocabulary_count,json=vocabularyCount,proto3" json:"vocabulary_count,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Lesson) Reset() {
+	*x = Lesson{}
+	mi := &file_content_v1_content_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Lesson) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lesson) ProtoMessage() {}
+
+func (x *Lesson) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lesson.ProtoReflect.Descriptor instead.
+func (*Lesson) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Lesson) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Lesson) GetVocabularyCount() int32 {
+	if x != nil {
+		return x.VocabularyCount
+	}
+	return 0
+}
+
+type ListCoursesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCoursesRequest) Reset() {
+	*x = ListCoursesRequest{}
+	mi := &file_content_v1_content_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCoursesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCoursesRequest) ProtoMessage() {}
+
+func (x *ListCoursesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCoursesRequest.ProtoReflect.Descriptor instead.
+func (*ListCoursesRequest) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{8}
+}
+
+type ListCoursesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Courses       []*Course              `protobuf:"bytes,1,rep,name=courses,proto3" json:"courses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCoursesResponse) Reset() {
+	*x = ListCoursesResponse{}
+	mi := &file_content_v1_content_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCoursesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCoursesResponse) ProtoMessage() {}
+
+func (x *ListCoursesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCoursesResponse.ProtoReflect.Descriptor instead.
+func (*ListCoursesResponse) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListCoursesResponse) GetCourses() []*Course {
+	if x != nil {
+		return x.Courses
+	}
+	return nil
+}
+
+// Course is the top of the curriculum; its chapters are in study order.
+type Course struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Slug, e.g. "elementary-1"
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Chapters      []*Chapter             `protobuf:"bytes,4,rep,name=chapters,proto3" json:"chapters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Course) Reset() {
+	*x = Course{}
+	mi := &file_content_v1_content_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Course) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Course) ProtoMessage() {}
+
+func (x *Course) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Course.ProtoReflect.Descriptor instead.
+func (*Course) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Course) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Course) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Course) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Course) GetChapters() []*Chapter {
+	if x != nil {
+		return x.Chapters
+	}
+	return nil
+}
+
+// Chapter is a unit of consecutive lessons within a course.
+type Chapter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Slug, e.g. "elementary-1-chapter-1"
+	CourseId      string                 `protobuf:"bytes,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	LessonIds     []string               `protobuf:"bytes,4,rep,name=lesson_ids,json=lessonIds,proto3" json:"lesson_ids,omitempty"` // Lesson identifiers in study order, e.g. "lesson-1"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Chapter) Reset() {
+	*x = Chapter{}
+	mi := &file_content_v1_content_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Chapter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Chapter) ProtoMessage() {}
+
+func (x *Chapter) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Chapter.ProtoReflect.Descriptor instead.
+func (*Chapter) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Chapter) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Chapter) GetCourseId() string {
+	if x != nil {
+		return x.CourseId
+	}
+	return ""
+}
+
+func (x *Chapter) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Chapter) GetLessonIds() []string {
+	if x != nil {
+		return x.LessonIds
+	}
+	return nil
+}
+
+type GetLessonAvailabilityRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	CompletedLessonIds []string               `protobuf:"bytes,1,rep,name=completed_lesson_ids,json=completedLessonIds,proto3" json:"completed_lesson_ids,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetLessonAvailabilityRequest) Reset() {
+	*x = GetLessonAvailabilityRequest{}
+	mi := &file_content_v1_content_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLessonAvailabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLessonAvailabilityRequest) ProtoMessage() {}
+
+func (x *GetLessonAvailabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLessonAvailabilityRequest.ProtoReflect.Descriptor instead.
+func (*GetLessonAvailabilityRequest) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetLessonAvailabilityRequest) GetCompletedLessonIds() []string {
+	if x != nil {
+		return x.CompletedLessonIds
+	}
+	return nil
+}
+
+type GetLessonAvailabilityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lessons       []*LessonAvailability  `protobuf:"bytes,1,rep,name=lessons,proto3" json:"lessons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLessonAvailabilityResponse) Reset() {
+	*x = GetLessonAvailabilityResponse{}
+	mi := &file_content_v1_content_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLessonAvailabilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLessonAvailabilityResponse) ProtoMessage() {}
+
+func (x *GetLessonAvailabilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLessonAvailabilityResponse.ProtoReflect.Descriptor instead.
+func (*GetLessonAvailabilityResponse) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetLessonAvailabilityResponse) GetLessons() []*LessonAvailability {
+	if x != nil {
+		return x.Lessons
+	}
+	return nil
+}
+
+// LessonAvailability is a lesson's place in the prerequisite graph for one learner.
+type LessonAvailability struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PrerequisiteIds []string               `protobuf:"bytes,2,rep,name=prerequisite_ids,json=prerequisiteIds,proto3" json:"prerequisite_ids,omitempty"`
+	Unlocked        bool                   `protobuf:"varint,3,opt,name=unlocked,proto3" json:"unlocked,omitempty"` // Every prerequisite has been completed
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *LessonAvailability) Reset() {
+	*x = LessonAvailability{}
+	mi := &file_content_v1_content_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LessonAvailability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LessonAvailability) ProtoMessage() {}
+
+func (x *LessonAvailability) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LessonAvailability.ProtoReflect.Descriptor instead.
+func (*LessonAvailability) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *LessonAvailability) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LessonAvailability) GetPrerequisiteIds() []string {
+	if x != nil {
+		return x.PrerequisiteIds
+	}
+	return nil
+}
+
+func (x *LessonAvailability) GetUnlocked() bool {
+	if x != nil {
+		return x.Unlocked
+	}
+	return false
+}
+
+// The request message for SearchVocabulary. Empty fields match everything.
+type SearchVocabularyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Case-insensitive prefix of the kana, kanji, or romaji, or part of the English meaning; at most 100 characters
+	Query         string             `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Filters       *VocabularyFilters `protobuf:"bytes,2,opt,name=filters,proto3" json:"filters,omitempty"`
+	Pagination    *v1.Pagination     `protobuf:"bytes,3,opt,name=pagination,proto3" json:"pagination,omitempty"` // Pages default to 50 items, at most 200
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchVocabularyRequest) Reset() {
+	*x = SearchVocabularyRequest{}
+	mi := &file_content_v1_content_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchVocabularyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchVocabularyRequest) ProtoMessage() {}
+
+func (x *SearchVocabularyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchVocabularyRequest.ProtoReflect.Descriptor instead.
+func (*SearchVocabularyRequest) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SearchVocabularyRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchVocabularyRequest) GetFilters() *VocabularyFilters {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *SearchVocabularyRequest) GetPagination() *v1.Pagination {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+// VocabularyFilters narrows a search. Each list matches any of its values.
+type VocabularyFilters struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WordClasses   []string               `protobuf:"bytes,1,rep,name=word_classes,json=wordClasses,proto3" json:"word_classes,omitempty"`
+	Lessons       []string               `protobuf:"bytes,2,rep,name=lessons,proto3" json:"lessons,omitempty"`                         // Lesson identifiers, e.g. "lesson-1"
+	JlptLevels    []string               `protobuf:"bytes,3,rep,name=jlpt_levels,json=jlptLevels,proto3" json:"jlpt_levels,omitempty"` // "N5" to "N1"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VocabularyFilters) Reset() {
+	*x = VocabularyFilters{}
+	mi := &file_content_v1_content_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VocabularyFilters) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VocabularyFilters) ProtoMessage() {}
+
+func (x *VocabularyFilters) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VocabularyFilters.ProtoReflect.Descriptor instead.
+func (*VocabularyFilters) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *VocabularyFilters) GetWordClasses() []string {
+	if x != nil {
+		return x.WordClasses
+	}
+	return nil
+}
+
+func (x *VocabularyFilters) GetLessons() []string {
+	if x != nil {
+		return x.Lessons
+	}
+	return nil
+}
+
+func (x *VocabularyFilters) GetJlptLevels() []string {
+	if x != nil {
+		return x.JlptLevels
+	}
+	return nil
+}
+
+type SearchVocabularyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*Vocabulary          `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	PageInfo      *v1.PageInfo           `protobuf:"bytes,2,opt,name=page_info,json=pageInfo,proto3" json:"page_info,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchVocabularyResponse) Reset() {
+	*x = SearchVocabularyResponse{}
+	mi := &file_content_v1_content_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchVocabularyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchVocabularyResponse) ProtoMessage() {}
+
+func (x *SearchVocabularyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchVocabularyResponse.ProtoReflect.Descriptor instead.
+func (*SearchVocabularyResponse) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SearchVocabularyResponse) GetItems() []*Vocabulary {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *SearchVocabularyResponse) GetPageInfo() *v1.PageInfo {
+	if x != nil {
+		return x.PageInfo
+	}
+	return nil
+}
+
+type Vocabulary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kana          string                 `protobuf:"bytes,2,opt,name=kana,proto3" json:"kana,omitempty"`
+	Kanji         *string                `protobuf:"bytes,3,opt,name=kanji,proto3,oneof" json:"kanji,omitempty"`
+	Furigana      *string                `protobuf:"bytes,4,opt,name=furigana,proto3,oneof" json:"furigana,omitempty"`
+	Romaji        string                 `protobuf:"bytes,5,opt,name=romaji,proto3" json:"romaji,omitempty"`
+	English       string                 `protobuf:"bytes,6,opt,name=english,proto3" json:"english,omitempty"`
+	Burmese       string                 `protobuf:"bytes,7,opt,name=burmese,proto3" json:"burmese,omitempty"`
+	Lesson        string                 `protobuf:"bytes,8,opt,name=lesson,proto3" json:"lesson,omitempty"`
+	Type          string                 `protobuf:"bytes,9,opt,name=type,proto3" json:"type,omitempty"`
+	WordClass     string                 `protobuf:"bytes,10,opt,name=word_class,json=wordClass,proto3" json:"word_class,omitempty"`
+	JlptLevel     string                 `protobuf:"bytes,11,opt,name=jlpt_level,json=jlptLevel,proto3" json:"jlpt_level,omitempty"`    // "N5" to "N1", empty if unknown
+	AudioUrl      *string                `protobuf:"bytes,12,opt,name=audio_url,json=audioUrl,proto3,oneof" json:"audio_url,omitempty"` // Pronunciation audio; absent until a recording exists
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Vocabulary) Reset() {
+	*x = Vocabulary{}
+	mi := &file_content_v1_content_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vocabulary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vocabulary) ProtoMessage() {}
+
+func (x *Vocabulary) ProtoReflect() protoreflect.Message {
+	mi := &file_content_v1_content_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vocabulary.ProtoReflect.Descriptor instead.
+func (*Vocabulary) Descriptor() ([]byte, []int) {
+	return file_content_v1_content_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Vocabulary) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Vocabulary) GetKana() string {
+	if x != nil {
+		return x.Kana
+	}
+	return ""
+}
+
+func (x *Vocabulary) GetKanji() string {
+	if x != nil && x.Kanji != nil {
+		return *x.Kanji
+	}
+	return ""
+}
+
+func (x *Vocabulary) GetFurigana() string {
+	if x != nil && x.Furigana != nil {
+		return *x.Furigana
+	}
+	return ""
+}
+
+func (x *Vocabulary) GetRomaji() string {
+	if x != nil {
+		return x.Romaji
+	}
+	return ""
+}
+
+func (x *Vocabulary) GetEnglish() string {
+	if x != nil {
+		return x.English
+	}
+	return ""
+}
+
+func (x *Vocabulary) GetBurmese() string {
+	if x != nil {
+		return x.Burmese
+	}
+	return ""
+}
+
+func (x *Vocabulary) GetLesson() string {
+	if x != nil {
+		return x.Lesson
+	}
+	return ""
+}
+
+func (x *Vocabulary) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Vocabulary) GetWordClass() string {
+	if x != nil {
+		return x.WordClass
+	}
+	return ""
+}
+
+func (x *Vocabulary) GetJlptLevel() string {
+	if x != nil {
+		return x.JlptLevel
+	}
+	return ""
+}
+
+func (x *Vocabulary) GetAudioUrl() string {
+	if x != nil && x.AudioUrl != nil {
+		return *x.AudioUrl
+	}
+	return ""
+}
+
+var File_content_v1_content_proto protoreflect.FileDescriptor
+
+const file_content_v1_content_proto_rawDesc = "" +
+	"\n" +
+	"\x18content/v1/content.proto\x12\n" +
+	"content.v1\x1a\x16common/v1/common.proto\"y\n" +
+	"\x19GetVocabularyBatchRequest\x12%\n" +
+	"\x0evocabulary_ids\x18\x01 \x03(\tR\rvocabularyIds\x125\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x15.common.v1.PaginationR\n" +
+	"pagination\"\xe9\x01\n" +
+	"\x1aGetVocabularyBatchResponse\x12G\n" +
+	"\x05items\x18\x01 \x03(\v21.content.v1.GetVocabularyBatchResponse.ItemsEntryR\x05items\x120\n" +
+	"\tpage_info\x18\x02 \x01(\v2\x13.common.v1.PageInfoR\bpageInfo\x1aP\n" +
+	"\n" +
+	"ItemsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.content.v1.VocabularyR\x05value:\x028\x01\"T\n" +
+	"\x15GetDistractorsRequest\x12%\n" +
+	"\x0evocabulary_ids\x18\x01 \x03(\tR\rvocabularyIds\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\">\n" +
+	"\x0eDistractorList\x12,\n" +
+	"\x05items\x18\x01 \x03(\v2\x16.content.v1.VocabularyR\x05items\"\xcb\x01\n" +
+	"\x16GetDistractorsResponse\x12U\n" +
+	"\vdistractors\x18\x01 \x03(\v23.content.v1.GetDistractorsResponse.DistractorsEntryR\vdistractors\x1aZ\n" +
+	"\x10DistractorsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.content.v1.DistractorListR\x05value:\x028\x01\"6\n" +
+	"\x15GetLessonBatchRequest\x12\x1d\n" +
+	"\n" +
+	"lesson_ids\x18\x01 \x03(\tR\tlessonIds\"\xb3\x01\n" +
+	"\x16GetLessonBatchResponse\x12I\n" +
+	"\alessons\x18\x01 \x03(\v2/.content.v1.GetLessonBatchResponse.LessonsEntryR\alessons\x1aN\n" +
+	"\fLessonsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12(\n" +
+	"\x05value\x18\x02 \x01(\v2\x12.content.v1.LessonR\x05value:\x028\x01\"C\n" +
+	"\x06Lesson\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10vocabulary_count\x18\x02 \x01(\x05R\x0fvocabularyCount\"\x14\n" +
+	"\x12ListCoursesRequest\"C\n" +
+	"\x13ListCoursesResponse\x12,\n" +
+	"\acourses\x18\x01 \x03(\v2\x12.content.v1.CourseR\acourses\"\x81\x01\n" +
+	"\x06Course\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12/\n" +
+	"\bchapters\x18\x04 \x03(\v2\x13.content.v1.ChapterR\bchapters\"k\n" +
+	"\aChapter\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tcourse_id\x18\x02 \x01(\tR\bcourseId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x1d\n" +
+	"\n" +
+	"lesson_ids\x18\x04 \x03(\tR\tlessonIds\"P\n" +
+	"\x1cGetLessonAvailabilityRequest\x120\n" +
+	"\x14completed_lesson_ids\x18\x01 \x03(\tR\x12completedLessonIds\"Y\n" +
+	"\x1dGetLessonAvailabilityResponse\x128\n" +
+	"\alessons\x18\x01 \x03(\v2\x1e.content.v1.LessonAvailabilityR\alessons\"k\n" +
+	"\x12LessonAvailability\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10prerequisite_ids\x18\x02 \x03(\tR\x0fprerequisiteIds\x12\x1a\n" +
+	"\bunlocked\x18\x03 \x01(\bR\bunlocked\"\x9f\x01\n" +
+	"\x17SearchVocabularyRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x127\n" +
+	"\afilters\x18\x02 \x01(\v2\x1d.content.v1.VocabularyFiltersR\afilters\x125\n" +
+	"\n" +
+	"pagination\x18\x03 \x01(\v2\x15.common.v1.PaginationR\n" +
+	"pagination\"q\n" +
+	"\x11VocabularyFilters\x12!\n" +
+	"\fword_classes\x18\x01 \x03(\tR\vwordClasses\x12\x18\n" +
+	"\alessons\x18\x02 \x03(\tR\alessons\x12\x1f\n" +
+	"\vjlpt_levels\x18\x03 \x03(\tR\n" +
+	"jlptLevels\"z\n" +
+	"\x18SearchVocabularyResponse\x12,\n" +
+	"\x05items\x18\x01 \x03(\v2\x16.content.v1.VocabularyR\x05items\x120\n" +
+	"\tpage_info\x18\x02 \x01(\v2\x13.common.v1.PageInfoR\bpageInfo\"\xe9\x02\n" +
+	"\n" +
+	"Vocabulary\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04kana\x18\x02 \x01(\tR\x04kana\x12\x19\n" +
+	"\x05kanji\x18\x03 \x01(\tH\x00R\x05kanji\x88\x01\x01\x12\x1f\n" +
+	"\bfurigana\x18\x04 \x01(\tH\x01R\bfurigana\x88\x01\x01\x12\x16\n" +
+	"\x06romaji\x18\x05 \x01(\tR\x06romaji\x12\x18\n" +
+	"\aenglish\x18\x06 \x01(\tR\aenglish\x12\x18\n" +
+	"\aburmese\x18\a \x01(\tR\aburmese\x12\x16\n" +
+	"\x06lesson\x18\b \x01(\tR\x06lesson\x12\x12\n" +
+	"\x04type\x18\t \x01(\tR\x04type\x12\x1d\n" +
+	"\n" +
+	"word_class\x18\n" +
+	" \x01(\tR\twordClass\x12\x1d\n" +
+	"\n" +
+	"jlpt_level\x18\v \x01(\tR\tjlptLevel\x12 \n" +
+	"\taudio_url\x18\f \x01(\tH\x02R\baudioUrl\x88\x01\x01B\b\n" +
+	"\x06_kanjiB\v\n" +
+	"\t_furiganaB\f\n" +
+	"\n" +
+	"_audio_url2\xc4\x04\n" +
+	"\x0eContentService\x12c\n" +
+	"\x12GetVocabularyBatch\x12%.content.v1.GetVocabularyBatchRequest\x1a&.content.v1.GetVocabularyBatchResponse\x12W\n" +
+	"\x0eGetDistractors\x12!.content.v1.GetDistractorsRequest\x1a\".content.v1.GetDistractorsResponse\x12W\n" +
+	"\x0eGetLessonBatch\x12!.content.v1.GetLessonBatchRequest\x1a\".content.v1.GetLessonBatchResponse\x12N\n" +
+	"\vListCourses\x12\x1e.content.v1.ListCoursesRequest\x1a\x1f.content.v1.ListCoursesResponse\x12l\n" +
+	"\x15GetLessonAvailability\x12(.content.v1.GetLessonAvailabilityRequest\x1a).content.v1.GetLessonAvailabilityResponse\x12]\n" +
+	"\x10SearchVocabulary\x12#.content.v1.SearchVocabularyRequest\x1a$.content.v1.SearchVocabularyResponseB)Z'wise-owl/gen/proto/content/v1;contentv1b\x06proto3"
+
+var (
+	file_content_v1_content_proto_rawDescOnce sync.Once
+	file_content_v1_content_proto_rawDescData []byte
+)
+
+func file_content_v1_content_proto_rawDescGZIP() []byte {
+	file_content_v1_content_proto_rawDescOnce.Do(func() {
+		file_content_v1_content_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_content_v1_content_proto_rawDesc), len(file_content_v1_content_proto_rawDesc)))
+	})
+	return file_content_v1_content_proto_rawDescData
+}
+
+var file_content_v1_content_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_content_v1_content_proto_goTypes = []any{
+	(*GetVocabularyBatchRequest)(nil),     // 0: content.v1.GetVocabularyBatchRequest
+	(*GetVocabularyBatchResponse)(nil),    // 1: content.v1.GetVocabularyBatchResponse
+	(*GetDistractorsRequest)(nil),         // 2: content.v1.GetDistractorsRequest
+	(*DistractorList)(nil),                // 3: content.v1.DistractorList
+	(*GetDistractorsResponse)(nil),        // 4: content.v1.GetDistractorsResponse
+	(*GetLessonBatchRequest)(nil),         // 5: content.v1.GetLessonBatchRequest
+	(*GetLessonBatchResponse)(nil),        // 6: content.v1.GetLessonBatchResponse
+	(*Lesson)(nil),                        // 7: content.v1.Lesson
+	(*ListCoursesRequest)(nil),            // 8: content.v1.ListCoursesRequest
+	(*ListCoursesResponse)(nil),           // 9: content.v1.ListCoursesResponse
+	(*Course)(nil),                        // 10: content.v1.Course
+	(*Chapter)(nil),                       // 11: content.v1.Chapter
+	(*GetLessonAvailabilityRequest)(nil),  // 12: content.v1.GetLessonAvailabilityRequest
+	(*GetLessonAvailabilityResponse)(nil), // 13: content.v1.GetLessonAvailabilityResponse
+	(*LessonAvailability)(nil),            // 14: content.v1.LessonAvailability
+	(*SearchVocabularyRequest)(nil),       // 15: content.v1.SearchVocabularyRequest
+	(*VocabularyFilters)(nil),             // 16: content.v1.VocabularyFilters
+	(*SearchVocabularyResponse)(nil),      // 17: content.v1.SearchVocabularyResponse
+	(*Vocabulary)(nil),                    // 18: content.v1.Vocabulary
+	nil,                                   // 19: content.v1.GetVocabularyBatchResponse.ItemsEntry
+	nil,                                   // 20: content.v1.GetDistractorsResponse.DistractorsEntry
+	nil,                                   // 21: content.v1.GetLessonBatchResponse.LessonsEntry
+	(*v1.Pagination)(nil),                 // 22: common.v1.Pagination
+	(*v1.PageInfo)(nil),                   // 23: common.v1.PageInfo
+}
+var file_content_v1_content_proto_depIdxs = []int32{
+	22, // 0: content.v1.GetVocabularyBatchRequest.pagination:type_name -> common.v1.Pagination
+	19, // 1: content.v1.GetVocabularyBatchResponse.items:type_name -> content.v1.GetVocabularyBatchResponse.ItemsEntry
+	23, // 2: content.v1.GetVocabularyBatchResponse.page_info:type_name -> common.v1.PageInfo
+	18, // 3: content.v1.DistractorList.items:type_name -> content.v1.Vocabulary
+	20, // 4: content.v1.GetDistractorsResponse.distractors:type_name -> content.v1.GetDistractorsResponse.DistractorsEntry
+	21, // 5: content.v1.GetLessonBatchResponse.lessons:type_name -> content.v1.GetLessonBatchResponse.LessonsEntry
+	10, // 6: content.v1.ListCoursesResponse.courses:type_name -> content.v1.Course
+	11, // 7: content.v1.Course.chapters:type_name -> content.v1.Chapter
+	14, // 8: content.v1.GetLessonAvailabilityResponse.lessons:type_name -> content.v1.LessonAvailability
+	16, // 9: content.v1.SearchVocabularyRequest.filters:type_name -> content.v1.VocabularyFilters
+	22, // 10: content.v1.SearchVocabularyRequest.pagination:type_name -> common.v1.Pagination
+	18, // 11: content.v1.SearchVocabularyResponse.items:type_name -> content.v1.Vocabulary
+	23, // 12: content.v1.SearchVocabularyResponse.page_info:type_name -> common.v1.PageInfo
+	18, // 13: content.v1.GetVocabularyBatchResponse.ItemsEntry.value:type_name -> content.v1.Vocabulary
+	3,  // 14: content.v1.GetDistractorsResponse.DistractorsEntry.value:type_name -> content.v1.DistractorList
+	7,  // 15: content.v1.GetLessonBatchResponse.LessonsEntry.value:type_name -> content.v1.Lesson
+	0,  // 16: content.v1.ContentService.GetVocabularyBatch:input_type -> content.v1.GetVocabularyBatchRequest
+	2,  // 17: content.v1.ContentService.GetDistractors:input_type -> content.v1.GetDistractorsRequest
+	5,  // 18: content.v1.ContentService.GetLessonBatch:input_type -> content.v1.GetLessonBatchRequest
+	8,  // 19: content.v1.ContentService.ListCourses:input_type -> content.v1.ListCoursesRequest
+	12, // 20: content.v1.ContentService.GetLessonAvailability:input_type -> content.v1.GetLessonAvailabilityRequest
+	15, // 21: content.v1.ContentService.SearchVocabulary:input_type -> content.v1.SearchVocabularyRequest
+	1,  // 22: content.v1.ContentService.GetVocabularyBatch:output_type -> content.v1.GetVocabularyBatchResponse
+	4,  // 23: content.v1.ContentService.GetDistractors:output_type -> content.v1.GetDistractorsResponse
+	6,  // 24: content.v1.ContentService.GetLessonBatch:output_type -> content.v1.GetLessonBatchResponse
+	9,  // 25: content.v1.ContentService.ListCourses:output_type -> content.v1.ListCoursesResponse
+	13, // 26: content.v1.ContentService.GetLessonAvailability:output_type -> content.v1.GetLessonAvailabilityResponse
+	17, // 27: content.v1.ContentService.SearchVocabulary:output_type -> content.v1.SearchVocabularyResponse
+	22, // [22:28] is the sub-list for method output_type
+	16, // [16:22] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_content_v1_content_proto_init() }
+func file_content_v1_content_proto_init() {
+	if File_content_v1_content_proto != nil {
+		return
+	}
+	file_content_v1_content_proto_msgTypes[18].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_content_v1_content_proto_rawDesc), len(file_content_v1_content_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   22,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_content_v1_content_proto_goTypes,
+		DependencyIndexes: file_content_v1_content_proto_depIdxs,
+		MessageInfos:      file_content_v1_content_proto_msgTypes,
+	}.Build()
+	File_content_v1_content_proto = out.File
+	file_content_v1_content_proto_goTypes = nil
+	file_content_v1_content_proto_depIdxs = nil
+}
@@ -0,0 +1,112 @@
+// FILE: lib/apierror/apierror.go
+// This package defines the typed API errors shared by all services and the JSON envelope
+// they are rendered in:
+//
+//	{"error": {"code": "not_found", "message": "User profile not found.", "details": ..., "trace_id": "..."}}
+
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error is an API error with the HTTP status it maps to.
+// Code is a stable, machine-readable identifier; Message is meant for humans.
+type Error struct {
+	Status  int         `json:"-"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+	TraceID string      `json:"trace_id,omitempty"`
+	Debug   string      `json:"debug,omitempty"` // The cause of a server error, in development only (see SetDebug)
+
+	cause error // Logged on the server, never sent to clients
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap returns the underlying cause, if any.
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// WithDetails returns a copy of the error carrying extra, client-visible details.
+func (e *Error) WithDetails(details interface{}) *Error {
+	clone := *e
+	clone.Details = details
+	return &clone
+}
+
+// Wrap returns a copy of the error that records cause for server-side logs.
+func (e *Error) Wrap(cause error) *Error {
+	clone := *e
+	clone.cause = cause
+	return &clone
+}
+
+// New creates an error with an explicit status.
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// BadRequest creates a 400 error.
+func BadRequest(code, message string) *Error {
+	return New(http.StatusBadRequest, code, message)
+}
+
+// InvalidRequest creates the 400 error returned when a request body or query cannot be bound.
+func InvalidRequest(err error) *Error {
+	return BadRequest("invalid_request", "The request is invalid.").WithDetails(err.Error())
+}
+
+// Unauthorized creates a 401 error.
+func Unauthorized(code, message string) *Error {
+	return New(http.StatusUnauthorized, code, message)
+}
+
+// Forbidden creates a 403 error.
+func Forbidden(code, message string) *Error {
+	return New(http.StatusForbidden, code, message)
+}
+
+// NotFound creates a 404 error.
+func NotFound(code, message string) *Error {
+	return New(http.StatusNotFound, code, message)
+}
+
+// Conflict creates a 409 error.
+func Conflict(code, message string) *Error {
+	return New(http.StatusConflict, code, message)
+}
+
+// ServiceUnavailable creates a 503 error, e.g. when a dependency cannot be reached.
+func ServiceUnavailable(code, message string, cause error) *Error {
+	return New(http.StatusServiceUnavailable, code, message).Wrap(cause)
+}
+
+// GatewayTimeout creates a 504 error, e.g. when a request ran out of time waiting on a dependency.
+func GatewayTimeout(code, message string, cause error) *Error {
+	return New(http.StatusGatewayTimeout, code, message).Wrap(cause)
+}
+
+// Internal creates a 500 error. The cause is logged but never shown to clients.
+func Internal(code string, cause error) *Error {
+	return New(http.StatusInternalServerError, code, "An internal error occurred.").Wrap(cause)
+}
+
+// From converts any error into an API error. Unknown errors become a generic 500.
+func From(err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	return Internal("internal_error", err)
+}
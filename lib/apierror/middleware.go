@@ -0,0 +1,133 @@
+// FILE: lib/apierror/middleware.go
+// Gin middleware that assigns trace IDs and renders handler errors in the standard envelope.
+
+package apierror
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"sync/atomic"
+
+	"wise-owl/lib/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TraceHeader carries the trace ID on requests and responses.
+const TraceHeader = "X-Request-ID"
+
+// traceContextKey is the Gin and request context key holding the trace ID.
+type traceContextKey struct{}
+
+const traceIDKey = "traceID"
+
+// debugResponses is set by SetDebug
+var debugResponses atomic.Bool
+
+// Middleware assigns each request a trace ID (reusing an incoming X-Request-ID) and
+// renders the last error a handler attached with c.Error, unless a response was already written.
+//
+// Handlers report failures like this:
+//
+//	c.Error(apierror.NotFound("not_found", "User profile not found."))
+//	return
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		traceID := c.GetHeader(TraceHeader)
+		if traceID == "" || len(traceID) > 128 {
+			traceID = newTraceID()
+		}
+		c.Set(traceIDKey, traceID)
+		c.Request = c.Request.WithContext(ContextWithTraceID(c.Request.Context(), traceID))
+		c.Header(TraceHeader, traceID)
+
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		Respond(c, c.Errors.Last().Err)
+	}
+}
+
+// Respond writes err in the standard envelope immediately and aborts the chain.
+// The message is translated to the language the request's Accept-Language header prefers.
+func Respond(c *gin.Context, err error) {
+	lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+	apiErr := render(From(err), TraceID(c), lang)
+	c.Header("Content-Language", lang)
+	c.AbortWithStatusJSON(apiErr.Status, gin.H{"error": apiErr})
+}
+
+// Recovery turns a panic in a handler into a 500 internal_error in the standard envelope. The
+// panic and its stack are logged, and only sent to the client when SetDebug is on.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered interface{}) {
+		Respond(c, Internal("internal_error", fmt.Errorf("panic serving %s %s: %v\n%s", c.Request.Method, c.Request.URL.Path, recovered, debug.Stack())))
+	})
+}
+
+// SetDebug controls whether server errors tell the client their cause, in a "debug" field. It is
+// meant for development, where seeing why a request failed beats reading the service's logs.
+func SetDebug(on bool) {
+	debugResponses.Store(on)
+}
+
+// WriteHTTP writes err in the standard envelope to a plain net/http response writer.
+// It is used by middleware that does not run inside a Gin handler, such as the JWT validator.
+func WriteHTTP(w http.ResponseWriter, r *http.Request, err error) {
+	traceID := TraceIDFromContext(r.Context())
+	lang := i18n.Negotiate(r.Header.Get("Accept-Language"))
+	apiErr := render(From(err), traceID, lang)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Language", lang)
+	w.WriteHeader(apiErr.Status)
+	json.NewEncoder(w).Encode(gin.H{"error": apiErr})
+}
+
+// TraceID returns the trace ID assigned to the request, or an empty string outside the middleware.
+func TraceID(c *gin.Context) string {
+	return c.GetString(traceIDKey)
+}
+
+// TraceIDFromContext returns the trace ID of the request ctx belongs to, or an empty string.
+func TraceIDFromContext(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceContextKey{}).(string)
+	return traceID
+}
+
+// ContextWithTraceID returns a copy of ctx carrying traceID, for requests that arrive other than
+// through Middleware, such as gRPC calls from another service.
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, traceID)
+}
+
+// render stamps the trace ID on a copy of the error, translates its message to lang, and logs
+// server-side failures. Codes are never translated, so clients can keep matching on them.
+func render(apiErr *Error, traceID, lang string) *Error {
+	clone := *apiErr
+	clone.TraceID = traceID
+	clone.Message = i18n.Translate(lang, clone.Message)
+	if clone.Status >= http.StatusInternalServerError {
+		log.Printf("ERROR: [%s] %v", traceID, apiErr)
+		if debugResponses.Load() && apiErr.cause != nil {
+			clone.Debug = apiErr.cause.Error()
+		}
+	}
+	return &clone
+}
+
+// newTraceID returns a random 64-bit hex identifier.
+func newTraceID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
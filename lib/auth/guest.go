@@ -0,0 +1,156 @@
+// FILE: lib/auth/guest.go
+// Short-lived tokens for anonymous guest sessions, so people can try quizzes before signing up.
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"wise-owl/lib/apierror"
+
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/go-jose/go-jose.v2"
+	"gopkg.in/go-jose/go-jose.v2/jwt"
+)
+
+const (
+	// GuestTokenIssuer is the 'iss' claim of guest tokens, which tells them apart from Auth0 tokens
+	GuestTokenIssuer = "wise-owl:guest"
+	// GuestSubjectPrefix starts the subject (and so the user ID) of every guest session
+	GuestSubjectPrefix = "guest|"
+)
+
+// ErrInvalidGuestToken is returned for guest tokens that are malformed, forged, or expired
+var ErrInvalidGuestToken = errors.New("auth: invalid guest token")
+
+// GuestIssuer signs and verifies guest tokens with a shared HMAC secret.
+// Each token identifies one anonymous session with a random guest ID.
+type GuestIssuer struct {
+	key    []byte
+	ttl    time.Duration
+	signer jose.Signer
+}
+
+// GuestToken is a freshly issued guest session
+type GuestToken struct {
+	Token     string    `json:"token"`
+	GuestID   string    `json:"guest_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewGuestIssuer creates an issuer whose tokens are valid for ttl.
+// The secret should be at least 32 random bytes and is shared by every instance of the service.
+func NewGuestIssuer(secret string, ttl time.Duration) (*GuestIssuer, error) {
+	if len(secret) < 32 {
+		return nil, errors.New("auth: guest token secret must be at least 32 bytes")
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte(secret)}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("auth: guest token signer: %v", err)
+	}
+	return &GuestIssuer{key: []byte(secret), ttl: ttl, signer: signer}, nil
+}
+
+// Issue starts a new guest session
+func (g *GuestIssuer) Issue() (GuestToken, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return GuestToken{}, err
+	}
+	now := time.Now()
+	guest := GuestToken{
+		GuestID:   GuestSubjectPrefix + hex.EncodeToString(id),
+		ExpiresAt: now.Add(g.ttl).UTC().Truncate(time.Second),
+	}
+
+	token, err := jwt.Signed(g.signer).Claims(jwt.Claims{
+		Issuer:   GuestTokenIssuer,
+		Subject:  guest.GuestID,
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(guest.ExpiresAt),
+	}).CompactSerialize()
+	if err != nil {
+		return GuestToken{}, err
+	}
+	guest.Token = token
+	return guest, nil
+}
+
+// GuestID verifies a guest token and returns its guest ID. Tokens that expired less than grace ago
+// are still accepted, so a guest who signs up after their session ended can keep its data.
+func (g *GuestIssuer) GuestID(token string, grace time.Duration) (string, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return "", ErrInvalidGuestToken
+	}
+	var claims jwt.Claims
+	if err := parsed.Claims(g.key, &claims); err != nil {
+		return "", ErrInvalidGuestToken
+	}
+	expected := jwt.Expected{Issuer: GuestTokenIssuer, Time: time.Now()}
+	if err := claims.ValidateWithLeeway(expected, time.Minute+grace); err != nil {
+		return "", ErrInvalidGuestToken
+	}
+	if !IsGuest(claims.Subject) {
+		return "", ErrInvalidGuestToken
+	}
+	return claims.Subject, nil
+}
+
+// ValidateToken satisfies TokenValidator for guest tokens.
+func (g *GuestIssuer) ValidateToken(ctx context.Context, token string) (interface{}, error) {
+	guestID, err := g.GuestID(token, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &validator.ValidatedClaims{
+		RegisteredClaims: validator.RegisteredClaims{Subject: guestID, Issuer: GuestTokenIssuer},
+		CustomClaims:     &CustomClaims{},
+	}, nil
+}
+
+// WithGuests returns a TokenValidator that accepts guest tokens from guests as well as the tokens
+// accepted by next. A nil next accepts guest tokens only.
+func WithGuests(next TokenValidator, guests *GuestIssuer) TokenValidator {
+	return guestAwareValidator{next: next, guests: guests}
+}
+
+type guestAwareValidator struct {
+	next   TokenValidator
+	guests *GuestIssuer
+}
+
+// ValidateToken sends guest tokens, recognized by their issuer, to the guest issuer and all others to next
+func (v guestAwareValidator) ValidateToken(ctx context.Context, token string) (interface{}, error) {
+	if issuer, _ := unverifiedClaim(token, "iss"); issuer == GuestTokenIssuer {
+		return v.guests.ValidateToken(ctx, token)
+	}
+	if v.next == nil {
+		return nil, errors.New("auth: only guest tokens are accepted")
+	}
+	return v.next.ValidateToken(ctx, token)
+}
+
+// IsGuest reports whether a user ID belongs to a guest session rather than an account
+func IsGuest(userID string) bool {
+	return strings.HasPrefix(userID, GuestSubjectPrefix)
+}
+
+// RejectGuests creates a Gin middleware that answers guest sessions with a 403 account_required error,
+// for routes that need a real account. It must run after the token middleware.
+func RejectGuests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if IsGuest(c.GetString("userID")) {
+			apierror.Respond(c, apierror.Forbidden("account_required", "Sign up to use this feature."))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,163 @@
+// FILE: lib/auth/jwks.go
+// Signing key cache that keeps validating tokens through JWKS outages.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/auth0/go-jwt-middleware/v2/jwks"
+	"gopkg.in/go-jose/go-jose.v2"
+)
+
+const (
+	// defaultJWKSRefresh is how long fetched keys are used before a background refresh
+	defaultJWKSRefresh = 5 * time.Minute
+	// jwksFetchTimeout bounds a background refresh
+	jwksFetchTimeout = 15 * time.Second
+)
+
+// JWKSCache serves an issuer's signing keys with stale-while-revalidate semantics: once keys
+// have been fetched they are always served, and a background refresh replaces them when they
+// are older than the refresh interval. A failed refresh keeps the previous keys.
+//
+// When a cache file is set, fetched keys are written to it and read back at startup, so a
+// service restarted during an Auth0 outage still validates tokens. The file holds public keys only.
+type JWKSCache struct {
+	provider *jwks.Provider
+	refresh  time.Duration
+	file     string
+
+	mu         sync.Mutex
+	keys       *jose.JSONWebKeySet
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+// NewJWKSCache creates a cache for the issuer's keys, discovered through its OpenID configuration.
+// A zero refresh uses five minutes; an empty file disables persistence.
+func NewJWKSCache(issuerURL *url.URL, refresh time.Duration, file string) *JWKSCache {
+	if refresh <= 0 {
+		refresh = defaultJWKSRefresh
+	}
+	c := &JWKSCache{
+		provider: jwks.NewProvider(issuerURL),
+		refresh:  refresh,
+		file:     file,
+	}
+	c.load()
+	return c
+}
+
+// KeyFunc returns the issuer's key set, in the form the go-jwt-middleware validator expects.
+// It only blocks on the network when no keys have been fetched or loaded yet.
+func (c *JWKSCache) KeyFunc(ctx context.Context) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.keys == nil {
+		keys, err := c.fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.store(keys)
+		return keys, nil
+	}
+
+	if time.Since(c.fetchedAt) > c.refresh && !c.refreshing {
+		c.refreshing = true
+		go c.revalidate()
+	}
+	return c.keys, nil
+}
+
+// revalidate refreshes the keys in the background, keeping the current ones on failure
+func (c *JWKSCache) revalidate() {
+	ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+	defer cancel()
+
+	keys, err := c.fetch(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshing = false
+	if err != nil {
+		log.Printf("WARN: JWKS refresh from %s failed, serving keys fetched %s ago: %v",
+			c.provider.IssuerURL, time.Since(c.fetchedAt).Round(time.Second), err)
+		return
+	}
+	c.store(keys)
+}
+
+func (c *JWKSCache) fetch(ctx context.Context) (*jose.JSONWebKeySet, error) {
+	keys, err := c.provider.KeyFunc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch JWKS from %s: %w", c.provider.IssuerURL, err)
+	}
+	return keys.(*jose.JSONWebKeySet), nil
+}
+
+// store replaces the keys and persists them; callers hold c.mu
+func (c *JWKSCache) store(keys *jose.JSONWebKeySet) {
+	c.keys = keys
+	c.fetchedAt = time.Now()
+	if c.file == "" {
+		return
+	}
+	if err := writeFileAtomic(c.file, keys); err != nil {
+		log.Printf("WARN: Failed to persist JWKS to %s: %v", c.file, err)
+	}
+}
+
+// load reads persisted keys. They count as stale, so the first use triggers a refresh.
+func (c *JWKSCache) load() {
+	if c.file == "" {
+		return
+	}
+	data, err := os.ReadFile(c.file)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("WARN: Failed to read persisted JWKS from %s: %v", c.file, err)
+		}
+		return
+	}
+	var keys jose.JSONWebKeySet
+	if err := json.Unmarshal(data, &keys); err != nil || len(keys.Keys) == 0 {
+		log.Printf("WARN: Ignoring unreadable persisted JWKS in %s", c.file)
+		return
+	}
+	c.keys = &keys
+	log.Printf("Loaded %d persisted signing keys from %s", len(keys.Keys), c.file)
+}
+
+// writeFileAtomic writes v as JSON through a temporary file so readers never see a partial file
+func writeFileAtomic(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,229 @@
+// FILE: lib/auth/management.go
+// A minimal Auth0 Management API client for reading and linking users with a machine-to-machine token.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// tokenRefreshMargin renews the cached token this long before Auth0 says it expires
+const tokenRefreshMargin = time.Minute
+
+var (
+	// ErrUserNotFound is returned when Auth0 has no user with the requested ID
+	ErrUserNotFound = errors.New("auth: user not found")
+	// ErrLinkRejected is returned when Auth0 refuses to link an identity, e.g. for an invalid or expired token
+	ErrLinkRejected = errors.New("auth: identity link rejected")
+)
+
+// ManagementUser is the subset of an Auth0 user record the services use
+type ManagementUser struct {
+	UserID        string                 `json:"user_id"`
+	Email         string                 `json:"email"`
+	EmailVerified bool                   `json:"email_verified"`
+	Name          string                 `json:"name"`
+	Nickname      string                 `json:"nickname"`
+	Picture       string                 `json:"picture"`
+	Locale        string                 `json:"locale"`
+	UserMetadata  map[string]interface{} `json:"user_metadata"`
+}
+
+// PreferredLocale returns the locale from the identity provider, falling back to one saved in user_metadata
+func (u *ManagementUser) PreferredLocale() string {
+	if u.Locale != "" {
+		return u.Locale
+	}
+	locale, _ := u.UserMetadata["locale"].(string)
+	return locale
+}
+
+// Identity is one of the identity provider accounts linked to an Auth0 user
+type Identity struct {
+	Provider   string `json:"provider"`
+	UserID     string `json:"user_id"` // Without the provider prefix
+	Connection string `json:"connection"`
+}
+
+// Subject returns the identity's full Auth0 ID, as it appears in the 'sub' claim
+func (i Identity) Subject() string {
+	return i.Provider + "|" + i.UserID
+}
+
+// ManagementClient reads and links users through the Auth0 Management API.
+// It obtains a token with the client credentials grant and reuses it until shortly before it expires.
+type ManagementClient struct {
+	domain       string
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewManagementClient creates a client for the tenant at domain using an M2M application's credentials.
+// The application must be authorized for the Management API with the read:users scope,
+// and update:users for LinkIdentity.
+func NewManagementClient(domain, clientID, clientSecret string) *ManagementClient {
+	return &ManagementClient{
+		domain:       domain,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// GetUser fetches the user with the given Auth0 ID (the 'sub' claim)
+func (m *ManagementClient) GetUser(ctx context.Context, userID string) (*ManagementUser, error) {
+	resp, err := m.do(ctx, http.MethodGet, "/api/v2/users/"+url.PathEscape(userID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrUserNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("auth0 management api: get user: status %d", resp.StatusCode)
+	}
+
+	var user ManagementUser
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("auth0 management api: decode user: %v", err)
+	}
+	return &user, nil
+}
+
+// LinkIdentity links the account that idToken was issued to into the primary user and returns the
+// primary user's identities afterwards. Auth0 verifies the token, so the caller proves they own both
+// accounts. The M2M application needs the update:users scope.
+func (m *ManagementClient) LinkIdentity(ctx context.Context, primaryID, idToken string) ([]Identity, error) {
+	body, err := json.Marshal(map[string]string{"link_with": idToken})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.do(ctx, http.MethodPost, "/api/v2/users/"+url.PathEscape(primaryID)+"/identities", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrUserNotFound
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusConflict:
+		return nil, ErrLinkRejected
+	case resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("auth0 management api: link identity: status %d", resp.StatusCode)
+	}
+
+	var identities []Identity
+	if err := json.NewDecoder(resp.Body).Decode(&identities); err != nil {
+		return nil, fmt.Errorf("auth0 management api: decode identities: %v", err)
+	}
+	return identities, nil
+}
+
+// do sends an authorized request to the Management API, fetching a new token once if the cached one is rejected
+func (m *ManagementClient) do(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		token, err := m.accessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, "https://"+m.domain+path, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := m.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("auth0 management api: %v", err)
+		}
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return resp, nil
+		}
+		resp.Body.Close()
+		m.forgetToken(token)
+	}
+}
+
+// accessToken returns the cached Management API token, requesting a new one when it is missing or about to expire
+func (m *ManagementClient) accessToken(ctx context.Context) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.token != "" && time.Now().Before(m.expiresAt) {
+		return m.token, nil
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"grant_type":    "client_credentials",
+		"client_id":     m.clientID,
+		"client_secret": m.clientSecret,
+		"audience":      "https://" + m.domain + "/api/v2/",
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+m.domain+"/oauth/token", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("auth0 token request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("auth0 token request: status %d", resp.StatusCode)
+	}
+
+	var grant struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"` // Seconds
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&grant); err != nil {
+		return "", fmt.Errorf("auth0 token request: decode: %v", err)
+	}
+	if grant.AccessToken == "" {
+		return "", errors.New("auth0 token request: empty access token")
+	}
+
+	m.token = grant.AccessToken
+	m.expiresAt = time.Now().Add(time.Duration(grant.ExpiresIn)*time.Second - tokenRefreshMargin)
+	return m.token, nil
+}
+
+// forgetToken drops a token the API rejected, unless another request already replaced it
+func (m *ManagementClient) forgetToken(token string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.token == token {
+		m.token = ""
+	}
+}
+
+// UnverifiedSubject reads the 'sub' claim of a JWT without checking its signature.
+// It is only suitable for early checks ahead of a call that verifies the token, such as LinkIdentity.
+func UnverifiedSubject(token string) (string, error) {
+	return unverifiedClaim(token, "sub")
+}
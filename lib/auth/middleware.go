@@ -5,13 +5,21 @@ package auth
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"net/url"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/config"
+
 	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
-	"github.com/auth0/go-jwt-middleware/v2/jwks"
 	"github.com/auth0/go-jwt-middleware/v2/validator"
 	"github.com/gin-gonic/gin"
 )
@@ -19,6 +27,8 @@ import (
 // CustomClaims contains custom data we want to be available in our JWT.
 type CustomClaims struct {
 	Scope string `json:"scope"`
+	// EmailVerified is only present when an Auth0 Action adds it to access tokens
+	EmailVerified *bool `json:"email_verified,omitempty"`
 }
 
 // Validate satisfies the validator.CustomClaims interface.
@@ -26,39 +36,100 @@ func (c CustomClaims) Validate(ctx context.Context) error {
 	return nil
 }
 
+// ValidatorOptions configures token validation.
+type ValidatorOptions struct {
+	// Issuers are the accepted Auth0 tenants, as domains ("tenant.auth0.com") or issuer URLs.
+	// Accepting more than one lets tokens from an old and a new tenant work during a migration.
+	Issuers []string
+	// Audiences are the accepted API identifiers; a token must carry at least one of them.
+	Audiences []string
+	// JWKSRefresh is how often signing keys are refreshed in the background (default 5 minutes).
+	JWKSRefresh time.Duration
+	// JWKSCacheDir, if set, persists each issuer's signing keys so tokens can be validated
+	// after a restart while Auth0 is unreachable.
+	JWKSCacheDir string
+}
+
+// issuerValidators validates tokens against the validator for the issuer they claim
+type issuerValidators map[string]*validator.Validator
+
 // EnsureValidToken creates a new Gin middleware that checks the validity of an Auth0 JWT.
+// It exits the process if the configuration is invalid; use NewTokenMiddleware to handle the error.
 func EnsureValidToken(domain, audience string) gin.HandlerFunc {
-	issuerURL, err := url.Parse("https://" + domain + "/")
+	middleware, err := NewTokenMiddleware(ValidatorOptions{
+		Issuers:   []string{domain},
+		Audiences: []string{audience},
+	})
 	if err != nil {
-		log.Fatalf("Failed to parse issuer url: %v", err)
+		log.Fatalf("Failed to set up JWT validator: %v", err)
 	}
+	return middleware
+}
 
-	// Caching provider to fetch and cache JWKS from Auth0.
-	provider := jwks.NewCachingProvider(issuerURL, 5*time.Minute)
-
-	// JWT validator with configured claims.
-	jwtValidator, err := validator.New(
-		provider.KeyFunc,
-		validator.RS256,
-		issuerURL.String(),
-		[]string{audience},
-		validator.WithCustomClaims(func() validator.CustomClaims {
-			return &CustomClaims{}
-		}),
-		validator.WithAllowedClockSkew(time.Minute),
-	)
+// TokenValidator checks a raw bearer token and returns its claims as *validator.ValidatedClaims.
+// *validator.Validator satisfies it; StaticValidator is a stand-in for tests and local development.
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (interface{}, error)
+}
+
+// NewTokenMiddleware creates a Gin middleware that checks the validity of an Auth0 JWT issued by
+// any of the accepted issuers for any of the accepted audiences.
+func NewTokenMiddleware(opts ValidatorOptions) (gin.HandlerFunc, error) {
+	tokenValidator, err := NewValidator(opts)
 	if err != nil {
-		log.Fatalf("Failed to set up JWT validator: %v", err)
+		return nil, err
+	}
+	return NewMiddleware(tokenValidator), nil
+}
+
+// NewValidator creates a TokenValidator for Auth0 JWTs, with one validator per accepted issuer.
+func NewValidator(opts ValidatorOptions) (TokenValidator, error) {
+	if len(opts.Issuers) == 0 {
+		return nil, errors.New("auth: at least one issuer is required")
+	}
+
+	validators := make(issuerValidators, len(opts.Issuers))
+	for _, issuer := range opts.Issuers {
+		issuerURL, err := parseIssuer(issuer)
+		if err != nil {
+			return nil, err
+		}
+
+		cacheFile := ""
+		if opts.JWKSCacheDir != "" {
+			cacheFile = filepath.Join(opts.JWKSCacheDir, "jwks-"+issuerURL.Hostname()+".json")
+		}
+		keys := NewJWKSCache(issuerURL, opts.JWKSRefresh, cacheFile)
+
+		// JWT validator with configured claims.
+		jwtValidator, err := validator.New(
+			keys.KeyFunc,
+			validator.RS256,
+			issuerURL.String(),
+			opts.Audiences,
+			validator.WithCustomClaims(func() validator.CustomClaims {
+				return &CustomClaims{}
+			}),
+			validator.WithAllowedClockSkew(time.Minute),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("auth: validator for %s: %v", issuerURL, err)
+		}
+		validators[issuerURL.String()] = jwtValidator
 	}
+	return validators, nil
+}
 
+// NewMiddleware creates a Gin middleware that authenticates requests with tokenValidator.
+// On success it sets "userID" from the token's subject, "scope" from its scope claim, and "emailVerified"
+// when the token carries it.
+func NewMiddleware(tokenValidator TokenValidator) gin.HandlerFunc {
 	// The actual middleware logic.
 	middleware := jwtmiddleware.New(
-		jwtValidator.ValidateToken,
+		tokenValidator.ValidateToken,
 		jwtmiddleware.WithErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
 			log.Printf("Token validation error: %v", err)
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(`{"error":"invalid_token","message":"Failed to validate token."}`))
+			apierror.WriteHTTP(w, r, apierror.Unauthorized("invalid_token", "Failed to validate token."))
 		}),
 	)
 
@@ -66,10 +137,81 @@ func EnsureValidToken(domain, audience string) gin.HandlerFunc {
 		handler := middleware.CheckJWT(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Token is valid, proceed to the next handler.
 			// Extract the user ID ('sub' claim) and set it in the Gin context.
-			claims := r.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+			claims, ok := r.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+			if !ok {
+				apierror.Respond(c, apierror.Internal("invalid_claims", fmt.Errorf("auth: validator returned %T", r.Context().Value(jwtmiddleware.ContextKey{}))))
+				return
+			}
 			c.Set("userID", claims.RegisteredClaims.Subject)
+			if custom, ok := claims.CustomClaims.(*CustomClaims); ok {
+				c.Set(scopeKey, custom.Scope)
+				if custom.EmailVerified != nil {
+					c.Set(emailVerifiedKey, *custom.EmailVerified)
+				}
+			}
 			c.Next()
 		}))
 		handler.ServeHTTP(c.Writer, c.Request)
 	}
 }
+
+// ConfigOptions builds validator options from the service configuration: the configured Auth0
+// domain and audience, plus any extra issuers and audiences.
+func ConfigOptions(cfg *config.Config) ValidatorOptions {
+	return ValidatorOptions{
+		Issuers:      append([]string{cfg.Auth0Domain}, cfg.Auth0ExtraIssuers...),
+		Audiences:    append([]string{cfg.Auth0Audience}, cfg.Auth0ExtraAudiences...),
+		JWKSCacheDir: cfg.JWKSCacheDir,
+	}
+}
+
+// ValidateToken picks the validator for the token's issuer, so only that issuer's keys are tried.
+// The issuer is read before the signature is checked, but the chosen validator verifies both.
+func (v issuerValidators) ValidateToken(ctx context.Context, token string) (interface{}, error) {
+	issuer, err := unverifiedClaim(token, "iss")
+	if err != nil {
+		return nil, err
+	}
+	jwtValidator, ok := v[issuer]
+	if !ok {
+		return nil, fmt.Errorf("issuer %q is not accepted", issuer)
+	}
+	return jwtValidator.ValidateToken(ctx, token)
+}
+
+// parseIssuer turns an Auth0 domain or issuer URL into the issuer URL tokens carry,
+// which always ends in a slash
+func parseIssuer(issuer string) (*url.URL, error) {
+	if !strings.Contains(issuer, "://") {
+		issuer = "https://" + issuer
+	}
+	issuerURL, err := url.Parse(issuer)
+	if err != nil || issuerURL.Host == "" {
+		return nil, fmt.Errorf("auth: invalid issuer %q", issuer)
+	}
+	if !strings.HasSuffix(issuerURL.Path, "/") {
+		issuerURL.Path += "/"
+	}
+	return issuerURL, nil
+}
+
+// unverifiedClaim reads a string claim from a JWT without checking its signature
+func unverifiedClaim(token, name string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("auth: malformed token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("auth: malformed token payload: %v", err)
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("auth: malformed token claims: %v", err)
+	}
+	value, _ := claims[name].(string)
+	if value == "" {
+		return "", fmt.Errorf("auth: token has no %s claim", name)
+	}
+	return value, nil
+}
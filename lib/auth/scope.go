@@ -0,0 +1,37 @@
+// FILE: lib/auth/scope.go
+// Middleware that restricts routes to tokens granted a given scope.
+
+package auth
+
+import (
+	"strings"
+
+	"wise-owl/lib/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// scopeKey is the Gin context key the token middleware sets to the token's scope claim
+const scopeKey = "scope"
+
+// HasScope reports whether the authenticated token was granted scope
+func HasScope(c *gin.Context, scope string) bool {
+	for _, granted := range strings.Fields(c.GetString(scopeKey)) {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireScope creates a Gin middleware that answers requests whose token lacks scope with a
+// 403 insufficient_scope error. It must run after the token middleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasScope(c, scope) {
+			apierror.Respond(c, apierror.Forbidden("insufficient_scope", "This action requires the "+scope+" scope."))
+			return
+		}
+		c.Next()
+	}
+}
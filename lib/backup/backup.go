@@ -0,0 +1,389 @@
+// FILE: lib/backup/backup.go
+// This package exports a service's user-scoped collections to a backup store on a schedule and
+// restores them one collection at a time, which DocumentDB's cluster snapshots cannot do. Each
+// collection is a gzip-compressed file of canonical Extended JSON lines, one per document, and
+// each backup is recorded in the service's "backups" collection with the archives' checksums.
+
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"wise-owl/lib/config"
+	"wise-owl/lib/database"
+	"wise-owl/lib/jobs"
+	"wise-owl/lib/storage"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// JobName is the lib/jobs name of the scheduled export
+	JobName = "user-data-backup"
+	// jobTimeout bounds an export and its pruning
+	jobTimeout = time.Hour
+	// restoreBatchSize is how many documents a restore compares and writes at once
+	restoreBatchSize = 500
+)
+
+// ErrNotFound is returned for backups that do not exist
+var ErrNotFound = errors.New("backup not found")
+
+// ErrCorrupt is returned when an archive does not match the checksum recorded with it
+var ErrCorrupt = errors.New("backup archive is corrupt")
+
+// Backup records one export.
+type Backup struct {
+	ID       primitive.ObjectID `bson:"_id" json:"id"`
+	Service  string             `bson:"service" json:"service"`
+	Archives []Archive          `bson:"archives" json:"archives"`
+	TakenAt  time.Time          `bson:"taken_at" json:"taken_at"`
+}
+
+// Archive is one collection of a backup.
+type Archive struct {
+	Collection string `bson:"collection" json:"collection"`
+	Key        string `bson:"key" json:"key"`
+	Count      int64  `bson:"count" json:"count"`
+	Size       int64  `bson:"size" json:"size"`
+	SHA256     string `bson:"sha256" json:"sha256"`
+}
+
+// CollectionRestore is what restoring one collection changed, or with a dry run would change.
+type CollectionRestore struct {
+	Collection string `json:"collection"`
+	Inserted   int    `json:"inserted"`  // Deleted since the backup
+	Replaced   int    `json:"replaced"`  // Changed since the backup
+	Unchanged  int    `json:"unchanged"` // Left as they are
+}
+
+// Exporter backs up and restores a fixed set of a service's collections.
+type Exporter struct {
+	cfg         config.BackupConfig
+	service     string
+	db          *mongo.Database
+	store       storage.BlobStore
+	collections []string
+	backups     *mongo.Collection
+}
+
+// New creates an exporter for collections of db. The backup store is opened even when scheduled
+// backups are disabled, so the restore command works on any instance.
+func New(ctx context.Context, cfg config.BackupConfig, service string, db *mongo.Database, collections []string) (*Exporter, error) {
+	store, err := storage.New(ctx, cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup store: %w", err)
+	}
+	return &Exporter{
+		cfg:         cfg,
+		service:     service,
+		db:          db,
+		store:       store,
+		collections: collections,
+		backups:     db.Collection("backups"),
+	}, nil
+}
+
+// Job returns the scheduled export, which also prunes backups past the retention policy.
+func (e *Exporter) Job() jobs.Job {
+	return jobs.Job{Name: JobName, Schedule: e.cfg.Schedule, Timeout: jobTimeout, Run: e.Run}
+}
+
+// Run takes a backup and prunes expired ones.
+func (e *Exporter) Run(ctx context.Context) error {
+	backup, err := e.Create(ctx)
+	if err != nil {
+		return err
+	}
+	var documents, size int64
+	for _, archive := range backup.Archives {
+		documents += archive.Count
+		size += archive.Size
+	}
+	log.Printf("Backed up %d documents of %s in %d collections (%d bytes) as %s",
+		documents, e.service, len(backup.Archives), size, backup.ID.Hex())
+
+	pruned, err := e.Prune(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to prune backups: %w", err)
+	}
+	if pruned > 0 {
+		log.Printf("Pruned %d expired backups of %s", pruned, e.service)
+	}
+	return nil
+}
+
+// Create archives every collection and records the backup. Archives of a failed backup are removed.
+func (e *Exporter) Create(ctx context.Context) (backup *Backup, err error) {
+	ctx = database.CrossUser(ctx) // Exports read every user's documents
+	backup = &Backup{ID: primitive.NewObjectID(), Service: e.service, TakenAt: time.Now().UTC()}
+	prefix := fmt.Sprintf("backups/%s/%s/", e.service, backup.TakenAt.Format("20060102T150405Z"))
+
+	defer func() {
+		if err != nil {
+			e.deleteArchives(context.WithoutCancel(ctx), backup.Archives)
+		}
+	}()
+	for _, name := range e.collections {
+		archive, err := e.export(ctx, name, prefix+name+".jsonl.gz")
+		if err != nil {
+			return backup, fmt.Errorf("backup %s: %w", name, err)
+		}
+		backup.Archives = append(backup.Archives, *archive)
+	}
+	if _, err := e.backups.InsertOne(ctx, backup); err != nil {
+		return backup, err
+	}
+	return backup, nil
+}
+
+// List returns the backups, newest first.
+func (e *Exporter) List(ctx context.Context) ([]Backup, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "taken_at", Value: -1}})
+	return database.FindAll[Backup](ctx, e.backups, bson.M{"service": e.service}, opts)
+}
+
+// Get returns a backup.
+func (e *Exporter) Get(ctx context.Context, id primitive.ObjectID) (*Backup, error) {
+	var backup Backup
+	err := e.backups.FindOne(ctx, bson.M{"_id": id, "service": e.service}).Decode(&backup)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
+
+// Prune deletes the backups the retention policy no longer keeps and returns how many it deleted.
+func (e *Exporter) Prune(ctx context.Context, now time.Time) (int, error) {
+	backups, err := e.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	pruned := 0
+	for _, backup := range expired(backups, e.cfg, now) {
+		if err := e.deleteArchives(ctx, backup.Archives); err != nil {
+			return pruned, err
+		}
+		if _, err := e.backups.DeleteOne(ctx, bson.M{"_id": backup.ID}); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+// Restore puts the documents of a backup's collections back, or of only the named ones. Documents
+// missing or changed since the backup are written back; documents created since are kept, so a
+// restore recovers lost data without undoing users' later activity. With dryRun nothing is written.
+func (e *Exporter) Restore(ctx context.Context, id primitive.ObjectID, only []string, dryRun bool) ([]CollectionRestore, error) {
+	ctx = database.CrossUser(ctx)
+	backup, err := e.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	archives := backup.Archives
+	if len(only) > 0 {
+		archives = nil
+		for _, name := range only {
+			archive, ok := findArchive(backup.Archives, name)
+			if !ok {
+				return nil, fmt.Errorf("backup %s has no %s collection", id.Hex(), name)
+			}
+			archives = append(archives, archive)
+		}
+	}
+
+	results := make([]CollectionRestore, 0, len(archives))
+	for _, archive := range archives {
+		result, err := e.restore(ctx, archive, dryRun)
+		if err != nil {
+			return results, fmt.Errorf("restore %s: %w", archive.Collection, err)
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+// export compresses a collection in memory, one collection at a time, and uploads it. The service
+// images have no writable temporary directory.
+func (e *Exporter) export(ctx context.Context, name, key string) (*Archive, error) {
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	count, err := writeCollection(ctx, gz, e.db.Collection(name))
+	if err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(archive.Bytes())
+	size := int64(archive.Len())
+	if err := e.store.Put(ctx, key, &archive, size, "application/gzip"); err != nil {
+		return nil, fmt.Errorf("failed to store archive: %w", err)
+	}
+	return &Archive{Collection: name, Key: key, Count: count, Size: size, SHA256: hex.EncodeToString(sum[:])}, nil
+}
+
+// restore downloads and checks an archive, then writes back its documents in batches
+func (e *Exporter) restore(ctx context.Context, archive Archive, dryRun bool) (*CollectionRestore, error) {
+	data, err := e.download(ctx, archive)
+	if err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	result := &CollectionRestore{Collection: archive.Collection}
+	collection := e.db.Collection(archive.Collection)
+	batch := make([]bson.Raw, 0, restoreBatchSize)
+	lines := bufio.NewReader(gz)
+	for {
+		line, err := lines.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var document bson.Raw
+			if err := bson.UnmarshalExtJSON(line, true, &document); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+			}
+			if batch = append(batch, document); len(batch) == restoreBatchSize {
+				if err := restoreBatch(ctx, collection, batch, dryRun, result); err != nil {
+					return nil, err
+				}
+				batch = batch[:0]
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+		}
+	}
+	if err := restoreBatch(ctx, collection, batch, dryRun, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// download reads an archive and checks it against its recorded checksum
+func (e *Exporter) download(ctx context.Context, archive Archive) ([]byte, error) {
+	body, err := e.store.Get(ctx, archive.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != archive.SHA256 {
+		return nil, ErrCorrupt
+	}
+	return data, nil
+}
+
+// deleteArchives removes archives from the backup store
+func (e *Exporter) deleteArchives(ctx context.Context, archives []Archive) error {
+	for _, archive := range archives {
+		if err := e.store.Delete(ctx, archive.Key); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", archive.Key, err)
+		}
+	}
+	return nil
+}
+
+// restoreBatch compares archived documents with the collection's and writes back those that differ
+func restoreBatch(ctx context.Context, collection *mongo.Collection, batch []bson.Raw, dryRun bool, result *CollectionRestore) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	ids := make(bson.A, 0, len(batch))
+	for _, document := range batch {
+		ids = append(ids, document.Lookup("_id"))
+	}
+	cursor, err := collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return err
+	}
+	current := make(map[string]bson.Raw, len(batch))
+	for cursor.Next(ctx) {
+		current[cursor.Current.Lookup("_id").String()] = bytes.Clone(cursor.Current)
+	}
+	if err := cursor.Err(); err != nil {
+		cursor.Close(ctx)
+		return err
+	}
+	cursor.Close(ctx)
+
+	var writes []mongo.WriteModel
+	for _, document := range batch {
+		id := document.Lookup("_id")
+		existing, ok := current[id.String()]
+		switch {
+		case !ok:
+			result.Inserted++
+		case bytes.Equal(existing, document):
+			result.Unchanged++
+			continue
+		default:
+			result.Replaced++
+		}
+		writes = append(writes, mongo.NewReplaceOneModel().SetFilter(bson.D{{Key: "_id", Value: id}}).SetReplacement(document).SetUpsert(true))
+	}
+	if dryRun || len(writes) == 0 {
+		return nil
+	}
+	_, err = collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// writeCollection writes every document of collection as a line and returns how many it wrote
+func writeCollection(ctx context.Context, w io.Writer, collection *mongo.Collection) (int64, error) {
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var count int64
+	for cursor.Next(ctx) {
+		line, err := bson.MarshalExtJSON(cursor.Current, true, false)
+		if err != nil {
+			return count, err
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, cursor.Err()
+}
+
+// findArchive returns the archive of a collection
+func findArchive(archives []Archive, collection string) (Archive, bool) {
+	for _, archive := range archives {
+		if archive.Collection == collection {
+			return archive, true
+		}
+	}
+	return Archive{}, false
+}
@@ -0,0 +1,123 @@
+// FILE: lib/backup/command.go
+// The restore-backup subcommand, run as a one-off task with the service's own configuration:
+//
+//	users restore-backup -list
+//	users restore-backup [-dry-run] [-collections=favorites,lesson_completions] <backup-id>
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Command is the subcommand that restores a backup instead of starting the service
+const Command = "restore-backup"
+
+// Requested reports whether the process was started with the restore-backup subcommand
+func Requested() bool {
+	return len(os.Args) > 1 && os.Args[1] == Command
+}
+
+// RunCommand runs the restore-backup subcommand with the arguments after it and returns the
+// process's exit code.
+func (e *Exporter) RunCommand(ctx context.Context, args []string) int {
+	flags := flag.NewFlagSet(Command, flag.ContinueOnError)
+	list := flags.Bool("list", false, "list backups, newest first")
+	dryRun := flags.Bool("dry-run", false, "report what would be restored without writing")
+	only := flags.String("collections", "", "comma-separated collections to restore (default: all of the backup's)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: %s %s -list\n       %s %s [-dry-run] [-collections=a,b] <backup-id>\n",
+			e.service, Command, e.service, Command)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *list {
+		if err := e.printList(ctx, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Failed to list backups: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	id, err := primitive.ObjectIDFromHex(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Backup ID must be a 24-character hex ObjectID: %s\n", flags.Arg(0))
+		return 2
+	}
+	var collections []string
+	if *only != "" {
+		for _, name := range strings.Split(*only, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				collections = append(collections, name)
+			}
+		}
+	}
+
+	results, err := e.Restore(ctx, id, collections, *dryRun)
+	printResults(os.Stdout, results, *dryRun)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		fmt.Fprintf(os.Stderr, "ERROR: No %s backup %s; see %s -list\n", e.service, id.Hex(), Command)
+		return 1
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		return 1
+	}
+	if !*dryRun {
+		log.Printf("AUDIT: Restored %s backup %s", e.service, id.Hex())
+	}
+	return 0
+}
+
+// printList writes the backups as a table
+func (e *Exporter) printList(ctx context.Context, w io.Writer) error {
+	backups, err := e.List(ctx)
+	if err != nil {
+		return err
+	}
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tTAKEN AT\tCOLLECTIONS\tDOCUMENTS\tBYTES")
+	for _, backup := range backups {
+		var documents, size int64
+		for _, archive := range backup.Archives {
+			documents += archive.Count
+			size += archive.Size
+		}
+		fmt.Fprintf(table, "%s\t%s\t%d\t%d\t%d\n",
+			backup.ID.Hex(), backup.TakenAt.Format("2006-01-02 15:04:05Z"), len(backup.Archives), documents, size)
+	}
+	return table.Flush()
+}
+
+// printResults writes what a restore changed as a table
+func printResults(w io.Writer, results []CollectionRestore, dryRun bool) {
+	if len(results) == 0 {
+		return
+	}
+	if dryRun {
+		fmt.Fprintln(w, "Dry run: nothing was written.")
+	}
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "COLLECTION\tINSERTED\tREPLACED\tUNCHANGED")
+	for _, result := range results {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\n", result.Collection, result.Inserted, result.Replaced, result.Unchanged)
+	}
+	table.Flush()
+}
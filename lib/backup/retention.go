@@ -0,0 +1,48 @@
+// FILE: lib/backup/retention.go
+// Grandfather-father-son retention: every backup of the last BACKUP_KEEP_DAILY days, the first
+// backup of each of the last BACKUP_KEEP_WEEKLY weeks, and the first of each of the last
+// BACKUP_KEEP_MONTHLY months are kept. The newest backup is never pruned.
+
+package backup
+
+import (
+	"fmt"
+	"time"
+
+	"wise-owl/lib/config"
+)
+
+// expired returns the backups the policy no longer keeps; backups must be sorted newest first
+func expired(backups []Backup, cfg config.BackupConfig, now time.Time) []Backup {
+	dailyCutoff := now.AddDate(0, 0, -cfg.KeepDaily)
+	weeklyCutoff := now.AddDate(0, 0, -7*cfg.KeepWeekly)
+	monthlyCutoff := now.AddDate(0, -cfg.KeepMonthly, 0)
+
+	// Walking oldest first, the first backup seen in a week or month is the one that period keeps
+	firstOfWeek := make(map[string]bool)
+	firstOfMonth := make(map[string]bool)
+	keep := make([]bool, len(backups))
+	for i := len(backups) - 1; i >= 0; i-- {
+		taken := backups[i].TakenAt
+		year, week := taken.ISOWeek()
+		weekKey, monthKey := fmt.Sprintf("%d-W%02d", year, week), taken.Format("2006-01")
+
+		keep[i] = i == 0 || taken.After(dailyCutoff)
+		if !firstOfWeek[weekKey] {
+			firstOfWeek[weekKey] = true
+			keep[i] = keep[i] || taken.After(weeklyCutoff)
+		}
+		if !firstOfMonth[monthKey] {
+			firstOfMonth[monthKey] = true
+			keep[i] = keep[i] || taken.After(monthlyCutoff)
+		}
+	}
+
+	var out []Backup
+	for i, backup := range backups {
+		if !keep[i] {
+			out = append(out, backup)
+		}
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+// FILE: lib/cache/cache.go
+// This package provides a small in-memory cache whose entries expire after a fixed TTL.
+
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache maps keys to values for ttl. It holds at most maxEntries values; when full, expired
+// entries are dropped first and then the entry closest to expiring. It is safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[K]entry[V]
+}
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// New creates a cache. A maxEntries of 0 or less means no size limit.
+func New[K comparable, V any](ttl time.Duration, maxEntries int) *Cache[K, V] {
+	return &Cache[K, V]{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[K]entry[V]),
+	}
+}
+
+// Get returns the value for key if it is present and has not expired.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if time.Now().After(e.expiresAt) {
+		delete(c.entries, key)
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value for key for the cache's TTL, replacing any existing value.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if _, exists := c.entries[key]; !exists && c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		c.evictLocked(now)
+	}
+	c.entries[key] = entry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+// Delete removes key from the cache.
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// Purge removes every entry.
+func (c *Cache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[K]entry[V])
+}
+
+// Len returns the number of stored entries, including ones that have expired but not been dropped yet.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// evictLocked makes room for one entry. The caller must hold c.mu.
+func (c *Cache[K, V]) evictLocked(now time.Time) {
+	var (
+		oldestKey K
+		oldest    time.Time
+		found     bool
+	)
+	for key, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, key)
+			continue
+		}
+		if !found || e.expiresAt.Before(oldest) {
+			oldestKey, oldest, found = key, e.expiresAt, true
+		}
+	}
+	if found && len(c.entries) >= c.maxEntries {
+		delete(c.entries, oldestKey)
+	}
+}
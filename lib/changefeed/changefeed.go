@@ -0,0 +1,144 @@
+// FILE: lib/changefeed/changefeed.go
+// This package helps services serve per-user change feeds to offline clients. Documents carry
+// an updated_at time that the feed filters on, deletions leave a tombstone behind, and clients
+// resume from an opaque cursor the feed hands out.
+
+package changefeed
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"wise-owl/lib/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TombstoneCollection is the collection deletions are recorded in, in each service's database
+const TombstoneCollection = "tombstones"
+
+// Retention is how long tombstones are kept. A client whose cursor is older has to resync in full,
+// as deletions it has not seen may be forgotten.
+const Retention = 30 * 24 * time.Hour
+
+// Overlap is how far before its cursor a feed starts reading. Writes that were in flight when the
+// cursor was taken can commit with an earlier updated_at; the overlap sends them again rather
+// than losing them, so clients must apply changes idempotently.
+const Overlap = 5 * time.Second
+
+// ErrInvalidCursor is returned for cursors the feed did not hand out
+var ErrInvalidCursor = errors.New("invalid sync cursor")
+
+// ErrExpiredCursor is returned for cursors older than Retention
+var ErrExpiredCursor = errors.New("sync cursor expired")
+
+// Indexes are the tombstone indexes a service serving a feed needs in its index list.
+var Indexes = []database.Index{
+	{Collection: TombstoneCollection, Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "deleted_at", Value: 1}}},
+	{Collection: TombstoneCollection, Keys: bson.D{{Key: "deleted_at", Value: 1}}, ExpireAfter: Retention},
+}
+
+// Tombstone records that a user's document was deleted.
+type Tombstone struct {
+	UserID     string    `bson:"user_id" json:"-"`
+	Collection string    `bson:"collection" json:"collection"`
+	DocumentID string    `bson:"document_id" json:"id"`
+	DeletedAt  time.Time `bson:"deleted_at" json:"deleted_at"`
+}
+
+// clock remembers the last change time handed out by Now
+var clock struct {
+	sync.Mutex
+	last time.Time
+}
+
+// Now returns the time to stamp a change with: the current time at the millisecond precision
+// MongoDB keeps, and never earlier than a stamp this process handed out before. A clock that
+// steps back therefore cannot order a change before one it follows, where a cursor taken in
+// between would skip it.
+func Now() time.Time {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	clock.Lock()
+	defer clock.Unlock()
+	if now.Before(clock.last) {
+		now = clock.last
+	}
+	clock.last = now
+	return now
+}
+
+// Cursor encodes a position in the feed for the client to send back.
+func Cursor(at time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(at.UnixNano(), 10)))
+}
+
+// Since decodes a cursor into the time the feed reads from, Overlap included. An empty cursor asks
+// for everything and yields the zero time.
+func Since(cursor string, now time.Time) (time.Time, error) {
+	if cursor == "" {
+		return time.Time{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidCursor
+	}
+	at := time.Unix(0, nanos).UTC()
+	if at.After(now) {
+		return time.Time{}, ErrInvalidCursor
+	}
+	if now.Sub(at) > Retention {
+		return time.Time{}, ErrExpiredCursor
+	}
+	return at.Add(-Overlap), nil
+}
+
+// Filter matches a user's documents changed at or after since in the given time field.
+// The zero time matches all of them.
+func Filter(userField, userID, timeField string, since time.Time) bson.M {
+	filter := bson.M{userField: userID}
+	if !since.IsZero() {
+		filter[timeField] = bson.M{"$gte": since}
+	}
+	return filter
+}
+
+// RecordDeletions leaves a tombstone for each deleted document of a user.
+func RecordDeletions(ctx context.Context, tombstones database.CollectionInterface, userID, collection string, documentIDs ...string) error {
+	if len(documentIDs) == 0 {
+		return nil
+	}
+	now := Now()
+	docs := make([]interface{}, len(documentIDs))
+	for i, id := range documentIDs {
+		docs[i] = Tombstone{UserID: userID, Collection: collection, DocumentID: id, DeletedAt: now}
+	}
+	_, err := tombstones.InsertMany(ctx, docs)
+	return err
+}
+
+// Deletions returns the user's tombstones since the given time, oldest first.
+func Deletions(ctx context.Context, tombstones database.CollectionInterface, userID string, since time.Time) ([]Tombstone, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: 1}})
+	return database.FindAll[Tombstone](ctx, tombstones, Filter("user_id", userID, "deleted_at", since), opts)
+}
+
+// DeletedAfter reports whether the user's document was deleted after at, for resolving offline
+// writes that were made before a deletion the client had not seen.
+func DeletedAfter(ctx context.Context, tombstones database.CollectionInterface, userID, collection, documentID string, at time.Time) (bool, error) {
+	count, err := tombstones.CountDocuments(ctx, bson.M{
+		"user_id":     userID,
+		"collection":  collection,
+		"document_id": documentID,
+		"deleted_at":  bson.M{"$gt": at},
+	}, options.Count().SetLimit(1))
+	return count > 0, err
+}
@@ -0,0 +1,193 @@
+// FILE: lib/compress/gzip.go
+// Gzip response compression shared by every service's HTTP router
+
+package compress
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MinSize is the smallest response worth compressing; shorter bodies are sent as-is.
+const MinSize = 1024
+
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return w
+	},
+}
+
+// Gzip compresses text and JSON responses for clients that send Accept-Encoding: gzip.
+// Bodies are buffered up to MinSize before deciding, so small responses skip the gzip overhead.
+// Event streams and responses that already set a Content-Encoding are passed through untouched.
+func Gzip() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		w := &gzipWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		defer w.finish()
+
+		c.Next()
+	}
+}
+
+// gzipWriter buffers the start of a response and switches to gzip once it is large enough.
+type gzipWriter struct {
+	gin.ResponseWriter
+	buf      []byte
+	gz       *gzip.Writer
+	decided  bool
+	compress bool
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		return w.write(data)
+	}
+
+	w.buf = append(w.buf, data...)
+	if len(w.buf) >= MinSize {
+		w.decide(true)
+		if err := w.flushBuffer(); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow is used for bodiless responses such as 204 and 304, which are never compressed.
+func (w *gzipWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.decide(false)
+		w.flushBuffer()
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush commits to a decision early so streamed responses reach the client as they are written.
+func (w *gzipWriter) Flush() {
+	if !w.decided {
+		w.decide(true)
+		w.flushBuffer()
+	}
+	if w.compress {
+		w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *gzipWriter) Written() bool {
+	return len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+func (w *gzipWriter) Size() int {
+	if !w.decided && len(w.buf) > 0 {
+		return len(w.buf)
+	}
+	return w.ResponseWriter.Size()
+}
+
+// decide fixes whether the response is compressed. large reports whether the body is big enough to be worth it.
+func (w *gzipWriter) decide(large bool) {
+	w.decided = true
+
+	header := w.Header()
+	if !large || !compressible(w.Status(), header) {
+		return
+	}
+
+	w.compress = true
+	header.Set("Content-Encoding", "gzip")
+	header.Add("Vary", "Accept-Encoding")
+	header.Del("Content-Length")
+	// The compressed bytes differ from what a strong ETag describes
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+
+	w.gz = gzipWriters.Get().(*gzip.Writer)
+	w.gz.Reset(w.ResponseWriter)
+}
+
+func (w *gzipWriter) write(data []byte) (int, error) {
+	if w.compress {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *gzipWriter) flushBuffer() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	buf := w.buf
+	w.buf = nil
+	_, err := w.write(buf)
+	return err
+}
+
+// finish writes any small buffered response and closes the gzip stream.
+func (w *gzipWriter) finish() {
+	if !w.decided {
+		w.decide(false)
+		w.flushBuffer()
+	}
+	if w.compress {
+		w.gz.Close()
+		w.gz.Reset(nil)
+		gzipWriters.Put(w.gz)
+		w.gz = nil
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip.
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(fields[0]), "gzip") {
+			continue
+		}
+		for _, param := range fields[1:] {
+			if strings.ReplaceAll(strings.TrimSpace(param), " ", "") == "q=0" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// compressible reports whether a response with this status and headers should be compressed.
+func compressible(status int, header http.Header) bool {
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	switch {
+	case strings.HasPrefix(contentType, "text/event-stream"):
+		return false
+	case strings.HasPrefix(contentType, "text/"),
+		strings.HasPrefix(contentType, "application/json"),
+		strings.HasPrefix(contentType, "application/javascript"),
+		strings.HasPrefix(contentType, "application/xml"):
+		return true
+	}
+	return false
+}
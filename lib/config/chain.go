@@ -0,0 +1,235 @@
+// FILE: lib/config/chain.go
+// An ordered chain of sources that remembers where each configuration value came from.
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// PrintConfigFlag makes LoadConfig print the resolved configuration and exit.
+const PrintConfigFlag = "--print-config"
+
+// defaultOrigin is the provenance of values no source sets
+const defaultOrigin = "default"
+
+// Chain looks keys up in its sources in order: the first source that sets a key wins, and keys
+// no source sets take the caller's fallback.
+type Chain struct {
+	sources []Source
+
+	mu      sync.Mutex
+	keys    []string          // Keys looked up, in first-lookup order
+	origins map[string]origin // Where each looked-up key's value came from
+}
+
+// origin records the value of a key and the source it came from
+type origin struct {
+	value  string
+	source string
+}
+
+// NewChain creates a chain asking sources in the given order.
+func NewChain(sources ...Source) *Chain {
+	return &Chain{sources: sources, origins: make(map[string]origin)}
+}
+
+// Secret providers selectable with SECRETS_PROVIDER
+const (
+	ProviderAWS   = "aws"   // Parameter Store and Secrets Manager
+	ProviderVault = "vault" // HashiCorp Vault KV version 2
+	ProviderGCP   = "gcp"   // Google Cloud Secret Manager
+	ProviderNone  = "none"
+)
+
+// DefaultChain is the chain LoadConfig reads for service: command-line flags, then environment
+// variables, then the service's .env files (see DotEnvProfile), then the secrets of the provider
+// SECRETS_PROVIDER names, which defaults to aws when running in AWS and none elsewhere. Every
+// provider reads the secret named by GetSecretName. A source that cannot be read is logged and left
+// out, so the service starts on what the others provide.
+func DefaultChain(service string) *Chain {
+	sources := []Source{FlagSource(os.Args[1:]), EnvSource()}
+	for _, path := range DotEnvProfile(service) {
+		dotEnv, err := DotEnvSource(path)
+		if err != nil {
+			log.Printf("WARN: Skipping config file: %v", err)
+			continue
+		}
+		sources = append(sources, dotEnv)
+	}
+
+	// The provider and its settings come from the local sources
+	chain := NewChain(sources...)
+	defaultProvider := ProviderNone
+	if isRunningInAWS() {
+		defaultProvider = ProviderAWS
+	}
+	chain.sources = append(chain.sources, secretSources(chain, chain.Get("SECRETS_PROVIDER", defaultProvider))...)
+	return chain
+}
+
+// secretSources returns the sources of a secrets provider, reading its settings from chain
+func secretSources(chain *Chain, provider string) []Source {
+	switch provider {
+	case ProviderNone:
+		return nil
+	case ProviderAWS:
+		log.Println("Adding Parameter Store and Secrets Manager to the config chain")
+		loader, err := NewAWSConfigLoader()
+		if err != nil {
+			log.Printf("WARN: Skipping AWS config sources: %v", err)
+			return nil
+		}
+		var sources []Source
+		if params, err := SSMSource(loader, GetParameterPrefix()); err != nil {
+			log.Printf("WARN: Skipping Parameter Store config: %v", err)
+		} else {
+			sources = append(sources, params)
+		}
+		if secrets, err := SecretsManagerSource(loader, GetSecretName()); err != nil {
+			log.Printf("WARN: Skipping Secrets Manager config: %v", err)
+		} else {
+			sources = append(sources, secrets)
+		}
+		return sources
+	case ProviderVault:
+		log.Println("Adding Vault to the config chain")
+		secrets, err := VaultSource(VaultConfig{
+			Address:   chain.Get("VAULT_ADDR", ""),
+			Token:     chain.Get("VAULT_TOKEN", ""),
+			Namespace: chain.Get("VAULT_NAMESPACE", ""),
+			Mount:     chain.Get("VAULT_MOUNT", "secret"),
+			Path:      GetSecretName(),
+		})
+		if err != nil {
+			log.Printf("WARN: Skipping Vault config: %v", err)
+			return nil
+		}
+		return []Source{secrets}
+	case ProviderGCP:
+		log.Println("Adding Secret Manager to the config chain")
+		// Secret IDs cannot contain slashes, so wise-owl/production is wise-owl-production
+		secrets, err := GCPSecretSource(chain.Get("GCP_PROJECT_ID", ""), strings.ReplaceAll(GetSecretName(), "/", "-"))
+		if err != nil {
+			log.Printf("WARN: Skipping Secret Manager config: %v", err)
+			return nil
+		}
+		return []Source{secrets}
+	default:
+		log.Printf("WARN: Unknown SECRETS_PROVIDER %q, reading no secrets (expected aws, vault, gcp, or none)", provider)
+		return nil
+	}
+}
+
+// Get returns the value of key from the first source that sets it, or fallback.
+func (c *Chain) Get(key, fallback string) string {
+	found := origin{value: fallback, source: defaultOrigin}
+	for _, source := range c.sources {
+		if value, ok := source.Lookup(key); ok {
+			found = origin{value: value, source: source.Name()}
+			break
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, seen := c.origins[key]; !seen {
+		c.keys = append(c.keys, key)
+	}
+	c.origins[key] = found
+	return found.value
+}
+
+// List splits a comma-separated value, dropping empty items
+func (c *Chain) List(key string) []string {
+	var items []string
+	for _, item := range strings.Split(c.Get(key, ""), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ExportFiles sets the environment variables the chain's .env files define and the environment
+// does not, the most specific file winning, so settings a service reads with os.Getenv (such as
+// other services' URLs) come from the files too.
+func (c *Chain) ExportFiles() {
+	for _, source := range c.sources {
+		file, ok := source.(*mapSource)
+		if !ok || !file.file {
+			continue
+		}
+		for key, value := range file.values {
+			if _, set := os.LookupEnv(key); !set && value != "" {
+				os.Setenv(key, value)
+			}
+		}
+	}
+}
+
+// LogProvenance logs which keys each source provided, one line per source. Values are not logged.
+func (c *Chain) LogProvenance() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bySource := make(map[string][]string)
+	for _, key := range c.keys {
+		if source := c.origins[key].source; source != defaultOrigin {
+			bySource[source] = append(bySource[source], key)
+		}
+	}
+	for _, source := range c.sources {
+		if keys := bySource[source.Name()]; len(keys) > 0 {
+			log.Printf("Config from %s: %s", source.Name(), strings.Join(keys, ", "))
+		}
+	}
+}
+
+// Print writes every key looked up so far with its value and source, hiding secrets.
+func (c *Chain) Print(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	for _, key := range c.keys {
+		found := c.origins[key]
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", key, redact(key, found.value), found.source)
+	}
+	tw.Flush()
+}
+
+// redact hides secret values: keys naming a secret, key, password, or token are masked, and
+// passwords in URLs such as MONGODB_URI are replaced.
+func redact(key, value string) string {
+	if value == "" {
+		return ""
+	}
+	for _, word := range []string{"SECRET", "KEY", "PASSWORD", "TOKEN"} {
+		if strings.Contains(key, word) {
+			return "********"
+		}
+	}
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		return u.Redacted()
+	}
+	return value
+}
+
+// printConfigRequested reports whether the process was started with --print-config
+func printConfigRequested() bool {
+	for _, arg := range os.Args[1:] {
+		if arg == PrintConfigFlag {
+			return true
+		}
+	}
+	return false
+}
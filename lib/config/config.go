@@ -3,10 +3,12 @@ package config
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"runtime"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -26,7 +28,36 @@ type Config struct {
 	Auth0Domain   string
 	Auth0Audience string
 	JWT_SECRET    string
-	Environment   string // Added for AWS environment detection
+	Environment   string // development, staging, or production; picks the service's preset (see lib/preset)
+	GRPCDebugLog  bool   // Log gRPC payload metadata (counts and missing IDs, never payloads)
+	SwaggerUI     bool   // Serve the Swagger UI at /docs (development only)
+	Storage       StorageConfig
+	TTS           TTSConfig     // Speech synthesis for vocabulary audio (content only)
+	STT           STTConfig     // Speech recognition for pronunciation scoring (quiz only)
+	Sessions      SessionConfig // Where solo quiz sessions are kept (quiz only)
+	Mongo         MongoOptions  // Connection pool and timeout tuning
+	Metrics       MetricsConfig // CloudWatch metrics (see lib/metrics)
+	Backup        BackupConfig  // Scheduled exports of user data (see lib/backup)
+
+	// Machine-to-machine credentials for the Auth0 Management API (onboarding, account linking,
+	// and email verification lookups)
+	Auth0ClientID     string
+	Auth0ClientSecret string
+	// Shared secret Auth0 Actions sign webhooks with (users service only)
+	Auth0WebhookSecret string
+
+	// Additional accepted token issuers and audiences, e.g. while migrating Auth0 tenants
+	Auth0ExtraIssuers   []string
+	Auth0ExtraAudiences []string
+	JWKSCacheDir        string // Persists Auth0 signing keys across restarts; empty keeps them in memory only
+
+	// Signs anonymous guest session tokens (quiz only); empty disables guest mode
+	GuestTokenSecret string
+
+	// Per-call budgets for gRPC and database calls made while serving a request; zero uses lib/ctxutil's defaults
+	GRPCCallTimeout time.Duration
+	DBQueryTimeout  time.Duration
+	RequestTimeout  time.Duration // Overall deadline of a request; zero uses lib/ctxutil's default
 }
 
 // AppConfig provides a more structured configuration approach for AWS deployments
@@ -38,12 +69,34 @@ type AppConfig struct {
 	Database    DatabaseConfig
 	JWT         JWTConfig
 	Auth0       Auth0Config
+	Storage     StorageConfig
+	Metrics     MetricsConfig
+	Backup      BackupConfig
+
+	RequestTimeout time.Duration // Overall deadline of a request; zero uses lib/ctxutil's default
 }
 
 type DatabaseConfig struct {
-	URI  string
-	Name string
-	Type string
+	URI     string
+	Name    string
+	Type    string
+	Options MongoOptions
+}
+
+// MongoOptions tunes the MongoDB/DocumentDB client. Zero values keep the backend's defaults (see lib/database).
+type MongoOptions struct {
+	MaxPoolSize    uint64
+	MinPoolSize    uint64
+	ConnectTimeout time.Duration
+	SocketTimeout  time.Duration
+	ReadPreference string // primary, primaryPreferred, secondary, secondaryPreferred, or nearest
+	ReplicaSet     string
+	// How often Secrets Manager is checked for rotated credentials; zero disables rotation handling
+	CredentialRefresh time.Duration
+	// Commands taking at least this long are logged with their filters redacted
+	SlowQueryThreshold time.Duration
+	// off, log, or enforce: what happens to queries on user-scoped collections that do not filter by user
+	TenantFilter string
 }
 
 type JWTConfig struct {
@@ -51,8 +104,62 @@ type JWTConfig struct {
 }
 
 type Auth0Config struct {
-	Domain   string
-	Audience string
+	Domain        string
+	Audience      string
+	ClientID      string // Management API M2M application
+	ClientSecret  string
+	WebhookSecret string // Signs webhooks pushed by Auth0 Actions
+
+	ExtraIssuers   []string // Accepted alongside Domain, e.g. during a tenant migration
+	ExtraAudiences []string // Accepted alongside Audience
+	JWKSCacheDir   string
+}
+
+// StorageConfig selects and configures the object store (see lib/storage)
+type StorageConfig struct {
+	Backend   string // "local" or "s3"
+	LocalDir  string // Root directory for the local backend
+	Bucket    string // Bucket for the s3 backend
+	PublicURL string // Base URL objects are served from; empty means the service's /media path or the bucket's S3 URL
+}
+
+// TTSConfig selects and configures the speech synthesizer (see lib/tts)
+type TTSConfig struct {
+	Backend string // "polly", or empty to generate no audio
+	Voice   string // Voice ID, e.g. Takumi or Kazuha for Japanese
+	Engine  string // "neural" or "standard"
+}
+
+// STTConfig selects and configures the speech recognizer (see lib/stt)
+type STTConfig struct {
+	Backend string // "google", or empty to turn pronunciation scoring off
+	APIKey  string // Google Cloud API key allowed to call the Speech-to-Text API
+}
+
+// SessionConfig selects where short-lived quiz sessions are kept (see services/quiz/internal/sessions)
+type SessionConfig struct {
+	Backend  string        // "mongo" or "redis"
+	RedisURL string        // redis:// or rediss:// URL of the redis backend
+	TTL      time.Duration // How long a session lives after it was last answered
+}
+
+// MetricsConfig turns on CloudWatch metrics published as Embedded Metric Format log lines
+type MetricsConfig struct {
+	EMF       bool   // Write metrics to standard output for CloudWatch Logs to extract
+	Namespace string // CloudWatch namespace, "WiseOwl" when empty
+}
+
+// BackupConfig schedules the export of a service's user data (see lib/backup). Backups go to their
+// own store rather than Storage, whose local directory is served publicly.
+type BackupConfig struct {
+	Enabled  bool
+	Schedule string // lib/jobs schedule of the export
+	Storage  StorageConfig
+
+	// How many daily, weekly, and monthly backups pruning keeps
+	KeepDaily   int
+	KeepWeekly  int
+	KeepMonthly int
 }
 
 // AWSConfigLoader handles loading configuration from AWS services
@@ -121,32 +228,16 @@ func isRunningInAWS() bool {
 	return false
 }
 
-// LoadConfig loads configuration from environment variables with sensible defaults
-// Maintains backward compatibility for local development
-func LoadConfig() (*Config, error) {
-	config := &Config{
-		ServerPort:  getEnv("SERVER_PORT", "8080"),
-		GRPCPort:    getEnv("GRPC_PORT", "50051"),
-		LogLevel:    getEnv("LOG_LEVEL", "info"),
-		MONGODB_URI: getEnv("MONGODB_URI", "mongodb://localhost:27017"),
-		DB_NAME:     getEnv("DB_NAME", ""),
-		DB_TYPE:     getEnv("DB_TYPE", "mongodb"),
-		JWT_SECRET:  getEnv("JWT_SECRET", ""),
-	}
-
-	// Auth0 config (optional, only for services that need it)
-	config.Auth0Domain = os.Getenv("AUTH0_DOMAIN")
-	config.Auth0Audience = os.Getenv("AUTH0_AUDIENCE")
-
-	// Try to load from AWS if running in AWS environment
-	if isRunningInAWS() {
-		log.Println("AWS environment detected, attempting to load configuration from AWS services...")
-		if err := loadAWSConfig(config); err != nil {
-			log.Printf("Warning: Failed to load AWS config, falling back to environment variables: %v", err)
-		}
-	} else {
-		log.Println("Local environment detected, using environment variables and defaults")
+// LoadConfig loads the configuration of service (e.g. "quiz") from DefaultChain with sensible
+// defaults, logging which source each value came from. Started with --print-config, it prints
+// the configuration and exits.
+func LoadConfig(service string) (*Config, error) {
+	chain := DefaultChain(service)
+	config, err := loadConfig(chain)
+	if err != nil {
+		return nil, err
 	}
+	finishLoading(chain)
 
 	log.Printf("Configuration loaded - Server Port: %s, GRPC Port: %s, DB Type: %s, DB: %s",
 		config.ServerPort, config.GRPCPort, config.DB_TYPE, config.DB_NAME)
@@ -154,128 +245,161 @@ func LoadConfig() (*Config, error) {
 	return config, nil
 }
 
-// loadAWSConfig attempts to load configuration from AWS services
-func loadAWSConfig(cfg *Config) error {
-	awsLoader, err := NewAWSConfigLoader()
-	if err != nil {
-		return fmt.Errorf("failed to initialize AWS config loader: %v", err)
+// loadConfig reads a Config from chain, failing on any invalid typed value
+func loadConfig(chain *Chain) (*Config, error) {
+	typed := typedReader{chain: chain}
+	config := &Config{
+		ServerPort:  chain.Get("SERVER_PORT", "8080"),
+		GRPCPort:    chain.Get("GRPC_PORT", "50051"),
+		LogLevel:    chain.Get("LOG_LEVEL", "info"),
+		MONGODB_URI: chain.Get("MONGODB_URI", "mongodb://localhost:27017"),
+		DB_NAME:     chain.Get("DB_NAME", ""),
+		DB_TYPE:     chain.Get("DB_TYPE", "mongodb"),
+		JWT_SECRET:  chain.Get("JWT_SECRET", ""),
+		Environment: chain.Get("ENVIRONMENT", defaultEnvironment()),
+	}
+
+	// Auth0 config (optional, only for services that need it)
+	config.Auth0Domain = chain.Get("AUTH0_DOMAIN", "")
+	config.Auth0Audience = chain.Get("AUTH0_AUDIENCE", "")
+	config.Auth0ClientID = chain.Get("AUTH0_M2M_CLIENT_ID", "")
+	config.Auth0ClientSecret = chain.Get("AUTH0_M2M_CLIENT_SECRET", "")
+	config.Auth0WebhookSecret = chain.Get("AUTH0_WEBHOOK_SECRET", "")
+	config.Auth0ExtraIssuers = chain.List("AUTH0_EXTRA_ISSUERS")
+	config.Auth0ExtraAudiences = chain.List("AUTH0_EXTRA_AUDIENCES")
+	config.JWKSCacheDir = chain.Get("AUTH0_JWKS_CACHE_DIR", "")
+	config.GuestTokenSecret = chain.Get("GUEST_TOKEN_SECRET", "")
+
+	// Opt-in gRPC payload metadata logging for diagnosing hydration gaps
+	config.GRPCDebugLog = typed.bool("GRPC_DEBUG_LOG")
+
+	// Swagger UI is a development aid; the raw /openapi.json is always served
+	config.SwaggerUI = typed.bool("SWAGGER_UI_ENABLED")
+
+	// Object storage: local disk in development, S3 in AWS
+	config.Storage = loadStorageConfig(chain, "local")
+
+	// Speech synthesis stays off unless a backend is configured, so development needs no AWS account
+	config.TTS = TTSConfig{
+		Backend: chain.Get("TTS_BACKEND", ""),
+		Voice:   chain.Get("TTS_VOICE", "Takumi"),
+		Engine:  chain.Get("TTS_ENGINE", "neural"),
 	}
+	config.STT = STTConfig{Backend: chain.Get("STT_BACKEND", ""), APIKey: chain.Get("STT_API_KEY", "")}
+	config.Sessions = SessionConfig{
+		Backend:  chain.Get("SESSION_STORE", "mongo"),
+		RedisURL: chain.Get("REDIS_URL", ""), // In AWS the URL carries the ElastiCache auth token
+		TTL:      typed.duration("SESSION_TTL"),
+	}
+
+	// Connection pool and timeouts, tunable per environment
+	config.Mongo = loadMongoOptions(&typed)
+	config.GRPCCallTimeout = typed.duration("GRPC_CALL_TIMEOUT")
+	config.DBQueryTimeout = typed.duration("DB_QUERY_TIMEOUT")
+	config.RequestTimeout = typed.duration("REQUEST_TIMEOUT")
+	config.Metrics = loadMetricsConfig(&typed)
+	config.Backup = loadBackupConfig(&typed)
 
-	// Get environment-specific secret name
-	secretName := GetSecretName()
-	paramPrefix := GetParameterPrefix()
+	return config, typed.err()
+}
+
+// typedReader reads typed keys from a chain, collecting every invalid value so they can be
+// reported together
+type typedReader struct {
+	chain *Chain
+	errs  []error
+}
+
+func (r *typedReader) duration(key string) time.Duration {
+	d, err := r.chain.GetDuration(key)
+	r.check(err)
+	return d
+}
+
+func (r *typedReader) int(key string) int {
+	n, err := r.chain.GetInt(key)
+	r.check(err)
+	return n
+}
+
+func (r *typedReader) bool(key string) bool {
+	b, err := r.chain.GetBool(key)
+	r.check(err)
+	return b
+}
 
-	// Load secrets from AWS Secrets Manager
-	secrets, err := awsLoader.LoadSecrets(secretName)
+func (r *typedReader) check(err error) {
 	if err != nil {
-		log.Printf("Failed to load AWS secrets from %s: %v", secretName, err)
-	} else {
-		// Only override if the secret value exists and is not already set from environment
-		if mongoURI, ok := secrets["MONGODB_URI"]; ok && mongoURI != "" {
-			if cfg.MONGODB_URI == "mongodb://localhost:27017" || cfg.MONGODB_URI == "" {
-				cfg.MONGODB_URI = mongoURI
-				log.Println("Loaded MONGODB_URI from AWS Secrets Manager")
-			}
-		}
-		if jwtSecret, ok := secrets["JWT_SECRET"]; ok && jwtSecret != "" {
-			if cfg.JWT_SECRET == "" {
-				cfg.JWT_SECRET = jwtSecret
-				log.Println("Loaded JWT_SECRET from AWS Secrets Manager")
-			}
-		}
-		if auth0Domain, ok := secrets["AUTH0_DOMAIN"]; ok && auth0Domain != "" {
-			if cfg.Auth0Domain == "" {
-				cfg.Auth0Domain = auth0Domain
-				log.Println("Loaded AUTH0_DOMAIN from AWS Secrets Manager")
-			}
-		}
-		if auth0Audience, ok := secrets["AUTH0_AUDIENCE"]; ok && auth0Audience != "" {
-			if cfg.Auth0Audience == "" {
-				cfg.Auth0Audience = auth0Audience
-				log.Println("Loaded AUTH0_AUDIENCE from AWS Secrets Manager")
-			}
-		}
+		r.errs = append(r.errs, err)
 	}
+}
 
-	// Load parameters from AWS Systems Manager Parameter Store
-	if dbType, err := awsLoader.LoadParameter(paramPrefix + "/DB_TYPE"); err == nil && dbType != "" {
-		if cfg.DB_TYPE == "mongodb" { // Only override default
-			cfg.DB_TYPE = dbType
-			log.Printf("Loaded DB_TYPE from AWS Parameter Store: %s", dbType)
-		}
+// err returns the invalid values found, if any
+func (r *typedReader) err() error {
+	if len(r.errs) == 0 {
+		return nil
 	}
+	return fmt.Errorf("invalid configuration: %w", errors.Join(r.errs...))
+}
 
-	// Load log level parameter
-	if logLevel, err := awsLoader.LoadParameter(paramPrefix + "/LOG_LEVEL"); err == nil && logLevel != "" {
-		if cfg.LogLevel == "info" { // Only override default
-			cfg.LogLevel = logLevel
-			log.Printf("Loaded LOG_LEVEL from AWS Parameter Store: %s", logLevel)
-		}
+// defaultEnvironment is the ENVIRONMENT of a service that does not set one: production in AWS,
+// development elsewhere
+func defaultEnvironment() string {
+	if isRunningInAWS() {
+		return "production"
 	}
+	return "development"
+}
 
-	return nil
+// finishLoading logs where the configuration came from, or prints it and exits for --print-config,
+// and exports the .env files' other settings
+func finishLoading(chain *Chain) {
+	if printConfigRequested() {
+		chain.Print(os.Stdout)
+		os.Exit(0)
+	}
+	chain.LogProvenance()
+	chain.ExportFiles()
+	setLoaded(chain)
 }
 
-// LoadConfigAWS provides enhanced AWS-aware configuration loading
-func LoadConfigAWS() (*AppConfig, error) {
+// LoadConfigAWS provides enhanced AWS-aware configuration loading. It reads the same chain as
+// LoadConfig, with the defaults of a production deployment.
+func LoadConfigAWS(service string) (*AppConfig, error) {
+	chain := DefaultChain(service)
+	typed := typedReader{chain: chain}
 	cfg := &AppConfig{
-		Port:        getEnv("PORT", "8080"),
-		GRPCPort:    getEnv("GRPC_PORT", "50051"),
-		LogLevel:    getEnv("LOG_LEVEL", "info"),
-		Environment: getEnv("ENVIRONMENT", "production"),
+		Port:        chain.Get("PORT", "8080"),
+		GRPCPort:    chain.Get("GRPC_PORT", "50051"),
+		LogLevel:    chain.Get("LOG_LEVEL", "info"),
+		Environment: chain.Get("ENVIRONMENT", "production"),
+
+		RequestTimeout: typed.duration("REQUEST_TIMEOUT"),
 	}
 
-	// Initialize database config with defaults
-	cfg.Database.URI = getEnv("MONGODB_URI", "mongodb://localhost:27017")
-	cfg.Database.Type = getEnv("DB_TYPE", "mongodb")
-	cfg.Database.Name = getEnv("DB_NAME", "")
-
-	// Initialize JWT config
-	cfg.JWT.Secret = getEnv("JWT_SECRET", "")
-
-	// Initialize Auth0 config
-	cfg.Auth0.Domain = getEnv("AUTH0_DOMAIN", "")
-	cfg.Auth0.Audience = getEnv("AUTH0_AUDIENCE", "")
-
-	// Load from AWS if running in AWS environment
-	if getEnv("AWS_EXECUTION_ENV", "") != "" {
-		log.Println("AWS execution environment detected, loading configuration from AWS services...")
-		awsLoader, err := NewAWSConfigLoader()
-		if err != nil {
-			log.Printf("Failed to initialize AWS config loader: %v", err)
-			return convertToAppConfig(LoadConfig()) // Fallback to existing config
-		}
-
-		// Load secrets
-		secrets, err := awsLoader.LoadSecrets("wise-owl/production")
-		if err != nil {
-			log.Printf("Failed to load AWS secrets: %v", err)
-		} else {
-			if mongoURI, ok := secrets["MONGODB_URI"]; ok && mongoURI != "" {
-				cfg.Database.URI = mongoURI
-				log.Println("Loaded MONGODB_URI from AWS Secrets Manager")
-			}
-			if jwtSecret, ok := secrets["JWT_SECRET"]; ok && jwtSecret != "" {
-				cfg.JWT.Secret = jwtSecret
-				log.Println("Loaded JWT_SECRET from AWS Secrets Manager")
-			}
-			if auth0Domain, ok := secrets["AUTH0_DOMAIN"]; ok && auth0Domain != "" {
-				cfg.Auth0.Domain = auth0Domain
-				log.Println("Loaded AUTH0_DOMAIN from AWS Secrets Manager")
-			}
-			if auth0Audience, ok := secrets["AUTH0_AUDIENCE"]; ok && auth0Audience != "" {
-				cfg.Auth0.Audience = auth0Audience
-				log.Println("Loaded AUTH0_AUDIENCE from AWS Secrets Manager")
-			}
-		}
-
-		// Load parameters from Systems Manager
-		paramPrefix := GetParameterPrefix()
-		if dbType, err := awsLoader.LoadParameter(paramPrefix + "/DB_TYPE"); err == nil && dbType != "" {
-			cfg.Database.Type = dbType
-			log.Printf("Loaded DB_TYPE from AWS Parameter Store: %s", dbType)
-		}
+	cfg.Database.URI = chain.Get("MONGODB_URI", "mongodb://localhost:27017")
+	cfg.Database.Type = chain.Get("DB_TYPE", "mongodb")
+	cfg.Database.Name = chain.Get("DB_NAME", "")
+	cfg.Database.Options = loadMongoOptions(&typed)
+	cfg.Metrics = loadMetricsConfig(&typed)
+
+	cfg.JWT.Secret = chain.Get("JWT_SECRET", "")
+
+	cfg.Auth0.Domain = chain.Get("AUTH0_DOMAIN", "")
+	cfg.Auth0.Audience = chain.Get("AUTH0_AUDIENCE", "")
+	cfg.Auth0.ClientID = chain.Get("AUTH0_M2M_CLIENT_ID", "")
+	cfg.Auth0.ClientSecret = chain.Get("AUTH0_M2M_CLIENT_SECRET", "")
+	cfg.Auth0.WebhookSecret = chain.Get("AUTH0_WEBHOOK_SECRET", "")
+	cfg.Auth0.ExtraIssuers = chain.List("AUTH0_EXTRA_ISSUERS")
+	cfg.Auth0.ExtraAudiences = chain.List("AUTH0_EXTRA_AUDIENCES")
+	cfg.Auth0.JWKSCacheDir = chain.Get("AUTH0_JWKS_CACHE_DIR", "")
+
+	cfg.Storage = loadStorageConfig(chain, "s3")
+	cfg.Backup = loadBackupConfig(&typed)
+	if err := typed.err(); err != nil {
+		return nil, err
 	}
+	finishLoading(chain)
 
 	log.Printf("AWS Configuration loaded - Port: %s, GRPC Port: %s, DB Type: %s, Environment: %s",
 		cfg.Port, cfg.GRPCPort, cfg.Database.Type, cfg.Environment)
@@ -283,46 +407,55 @@ func LoadConfigAWS() (*AppConfig, error) {
 	return cfg, nil
 }
 
-// convertToAppConfig converts legacy Config to new AppConfig structure
-func convertToAppConfig(oldCfg *Config, err error) (*AppConfig, error) {
-	if err != nil {
-		return nil, err
+// loadStorageConfig reads the STORAGE_* keys, using defaultBackend when STORAGE_BACKEND is unset
+func loadStorageConfig(chain *Chain, defaultBackend string) StorageConfig {
+	return StorageConfig{
+		Backend:   chain.Get("STORAGE_BACKEND", defaultBackend),
+		LocalDir:  chain.Get("STORAGE_LOCAL_DIR", "./data/storage"),
+		Bucket:    chain.Get("STORAGE_BUCKET", ""),
+		PublicURL: chain.Get("STORAGE_PUBLIC_URL", ""),
 	}
+}
 
-	return &AppConfig{
-		Port:        oldCfg.ServerPort,
-		GRPCPort:    oldCfg.GRPCPort,
-		LogLevel:    oldCfg.LogLevel,
-		Environment: getEnv("ENVIRONMENT", "development"),
-		Database: DatabaseConfig{
-			URI:  oldCfg.MONGODB_URI,
-			Name: oldCfg.DB_NAME,
-			Type: oldCfg.DB_TYPE,
-		},
-		JWT: JWTConfig{
-			Secret: oldCfg.JWT_SECRET,
-		},
-		Auth0: Auth0Config{
-			Domain:   oldCfg.Auth0Domain,
-			Audience: oldCfg.Auth0Audience,
-		},
-	}, nil
+// loadMongoOptions reads the MONGODB_* pool and timeout keys
+func loadMongoOptions(typed *typedReader) MongoOptions {
+	return MongoOptions{
+		MaxPoolSize:    uint64(typed.int("MONGODB_MAX_POOL_SIZE")),
+		MinPoolSize:    uint64(typed.int("MONGODB_MIN_POOL_SIZE")),
+		ConnectTimeout: typed.duration("MONGODB_CONNECT_TIMEOUT"),
+		SocketTimeout:  typed.duration("MONGODB_SOCKET_TIMEOUT"),
+		ReadPreference: typed.chain.Get("MONGODB_READ_PREFERENCE", ""),
+		ReplicaSet:     typed.chain.Get("MONGODB_REPLICA_SET", ""),
+
+		CredentialRefresh:  typed.duration("MONGODB_CREDENTIAL_REFRESH"),
+		SlowQueryThreshold: typed.duration("MONGODB_SLOW_QUERY_THRESHOLD"),
+		TenantFilter:       typed.chain.Get("MONGODB_TENANT_FILTER", ""),
+	}
 }
 
-// getEnvWithDefault gets environment variable with fallback (exported version)
-func getEnvWithDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// loadMetricsConfig reads the EMF metrics keys
+func loadMetricsConfig(typed *typedReader) MetricsConfig {
+	return MetricsConfig{
+		EMF:       typed.bool("EMF_METRICS_ENABLED"),
+		Namespace: typed.chain.Get("METRICS_NAMESPACE", ""),
 	}
-	return defaultValue
 }
 
-// getEnv gets environment variable with fallback
-func getEnv(key, fallback string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// loadBackupConfig reads the BACKUP_* keys. Backups go to S3 when BACKUP_BUCKET is set and to a
+// local directory otherwise.
+func loadBackupConfig(typed *typedReader) BackupConfig {
+	storage := StorageConfig{Backend: "local", LocalDir: typed.chain.Get("BACKUP_LOCAL_DIR", "./data/backups")}
+	if bucket := typed.chain.Get("BACKUP_BUCKET", ""); bucket != "" {
+		storage = StorageConfig{Backend: "s3", Bucket: bucket}
+	}
+	return BackupConfig{
+		Enabled:     typed.bool("BACKUP_ENABLED"),
+		Schedule:    typed.chain.Get("BACKUP_SCHEDULE", "0 3 * * *"),
+		Storage:     storage,
+		KeepDaily:   typed.int("BACKUP_KEEP_DAILY"),
+		KeepWeekly:  typed.int("BACKUP_KEEP_WEEKLY"),
+		KeepMonthly: typed.int("BACKUP_KEEP_MONTHLY"),
 	}
-	return fallback
 }
 
 // GetMemoryUsage returns current memory usage statistics
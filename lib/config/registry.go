@@ -0,0 +1,181 @@
+// FILE: lib/config/registry.go
+// The keys read as durations, integers, or booleans, with their defaults, and the typed accessors
+// that parse and check them.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Key describes a configuration key.
+type Key struct {
+	Name        string
+	Default     string // Used when no source sets the key; empty leaves the type's zero value
+	Description string
+}
+
+// knownKeys are the typed keys of every service. Zero durations and sizes leave the choice to the
+// package using them (lib/ctxutil, lib/database).
+var knownKeys = []Key{
+	{Name: "GRPC_DEBUG_LOG", Default: "false", Description: "Log gRPC payload metadata"},
+	{Name: "SWAGGER_UI_ENABLED", Default: "false", Description: "Serve the Swagger UI at /docs"},
+	{Name: "SESSION_TTL", Default: "1h", Description: "How long a quiz session lives after it was last answered"},
+	{Name: "GRPC_CALL_TIMEOUT", Description: "Per-request gRPC call budget"},
+	{Name: "DB_QUERY_TIMEOUT", Description: "Per-request database call budget"},
+	{Name: "REQUEST_TIMEOUT", Description: "Overall request deadline"},
+	{Name: "MONGODB_MAX_POOL_SIZE", Description: "Max connections per server"},
+	{Name: "MONGODB_MIN_POOL_SIZE", Description: "Connections kept open per server"},
+	{Name: "MONGODB_CONNECT_TIMEOUT", Description: "Database connect timeout"},
+	{Name: "MONGODB_SOCKET_TIMEOUT", Description: "Database socket read/write timeout"},
+	{Name: "MONGODB_CREDENTIAL_REFRESH", Description: "How often to look for rotated database credentials"},
+	{Name: "MONGODB_SLOW_QUERY_THRESHOLD", Description: "Database commands at least this slow are logged"},
+	{Name: "EMF_METRICS_ENABLED", Default: "false", Description: "Publish CloudWatch metrics as Embedded Metric Format log lines"},
+	{Name: "BACKUP_ENABLED", Default: "false", Description: "Export user data to the backup store on BACKUP_SCHEDULE"},
+	{Name: "BACKUP_KEEP_DAILY", Default: "7", Description: "Days of daily backups kept"},
+	{Name: "BACKUP_KEEP_WEEKLY", Default: "4", Description: "Weeks of weekly backups kept"},
+	{Name: "BACKUP_KEEP_MONTHLY", Default: "6", Description: "Months of monthly backups kept"},
+	{Name: "HEALTH_PROBE_INTERVAL", Default: "10s", Description: "How often health checks are refreshed"},
+	{Name: "USAGE_LIMIT_FREE", Default: "2000", Description: "Daily API calls per service on the free tier"},
+	{Name: "USAGE_LIMIT_PREMIUM", Default: "0", Description: "Daily API calls per service on the premium tier; 0 is unlimited"},
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Key)
+)
+
+func init() {
+	Register(knownKeys...)
+}
+
+// Register adds keys to the registry, replacing keys of the same name. Packages with typed
+// settings of their own register them from init.
+func Register(keys ...Key) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, key := range keys {
+		registry[key.Name] = key
+	}
+}
+
+// Keys returns the registered keys, sorted by name.
+func Keys() []Key {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	keys := make([]Key, 0, len(registry))
+	for _, key := range registry {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	return keys
+}
+
+// KeyError reports a value that is not valid for its key.
+type KeyError struct {
+	Key   string
+	Value string
+	Err   error
+}
+
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %v", e.Key, e.Value, e.Err)
+}
+
+func (e *KeyError) Unwrap() error { return e.Err }
+
+// GetDuration returns a registered key as a duration such as "10s". Negative durations are invalid.
+// An invalid value returns the key's default along with a *KeyError.
+func (c *Chain) GetDuration(key string) (time.Duration, error) {
+	return getTyped(c, key, func(value string) (time.Duration, error) {
+		d, err := time.ParseDuration(value)
+		if err == nil && d < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+		return d, err
+	})
+}
+
+// GetInt returns a registered key as a non-negative integer. An invalid value returns the key's
+// default along with a *KeyError.
+func (c *Chain) GetInt(key string) (int, error) {
+	return getTyped(c, key, func(value string) (int, error) {
+		n, err := strconv.Atoi(value)
+		if err == nil && n < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+		return n, err
+	})
+}
+
+// GetBool returns a registered key as a boolean: true, false, 1, 0, and the other forms
+// strconv.ParseBool accepts. An invalid value returns the key's default along with a *KeyError.
+func (c *Chain) GetBool(key string) (bool, error) {
+	return getTyped(c, key, strconv.ParseBool)
+}
+
+// getTyped looks key up in c and parses it, falling back to the registered default. Keys must be
+// registered, so a misspelled name fails loudly instead of silently reading nothing.
+func getTyped[T any](c *Chain, key string, parse func(string) (T, error)) (T, error) {
+	var zero T
+	registryMu.RLock()
+	known, ok := registry[key]
+	registryMu.RUnlock()
+	if !ok {
+		return zero, fmt.Errorf("config key %s is not registered", key)
+	}
+
+	fallback := zero
+	if known.Default != "" {
+		var err error
+		if fallback, err = parse(known.Default); err != nil {
+			return zero, fmt.Errorf("registered default of %s: %w", key, &KeyError{Key: key, Value: known.Default, Err: err})
+		}
+	}
+	value := c.Get(key, known.Default)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := parse(value)
+	if err != nil {
+		return fallback, &KeyError{Key: key, Value: value, Err: err}
+	}
+	return parsed, nil
+}
+
+// Process-wide accessors, for packages that are not handed a Config
+
+var (
+	loadedMu sync.RWMutex
+	loaded   *Chain // The chain of the last LoadConfig or LoadConfigAWS
+)
+
+// setLoaded makes chain the one the package-level accessors read
+func setLoaded(chain *Chain) {
+	loadedMu.Lock()
+	defer loadedMu.Unlock()
+	loaded = chain
+}
+
+// current returns the chain LoadConfig read, or one of the environment alone before it has run
+func current() *Chain {
+	loadedMu.RLock()
+	defer loadedMu.RUnlock()
+	if loaded != nil {
+		return loaded
+	}
+	return NewChain(EnvSource())
+}
+
+// GetDuration returns a registered key as a duration from the configuration the service loaded.
+func GetDuration(key string) (time.Duration, error) { return current().GetDuration(key) }
+
+// GetInt returns a registered key as an integer from the configuration the service loaded.
+func GetInt(key string) (int, error) { return current().GetInt(key) }
+
+// GetBool returns a registered key as a boolean from the configuration the service loaded.
+func GetBool(key string) (bool, error) { return current().GetBool(key) }
@@ -0,0 +1,56 @@
+// FILE: lib/config/rotation.go
+// Watching Secrets Manager for rotated secrets while a service runs.
+
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// WatchSecret watches key of the JSON secret secretName in AWS Secrets Manager for a new value,
+// such as MONGODB_URI after its credentials are rotated. The secret is read every interval, and at
+// once when the process receives SIGHUP; a value other than current is passed to onChange. If
+// onChange fails, the value is offered again on the next check. It returns when ctx is cancelled,
+// or at once if AWS cannot be reached.
+func WatchSecret(ctx context.Context, secretName, key, current string, every time.Duration, onChange func(value string) error) error {
+	loader, err := NewAWSConfigLoader()
+	if err != nil {
+		return err
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-hangup:
+			log.Printf("SIGHUP received, checking %s for a new %s", secretName, key)
+		}
+
+		secrets, err := loader.LoadSecrets(secretName)
+		if err != nil {
+			log.Printf("WARN: Could not check %s for a new %s: %v", secretName, key, err)
+			continue
+		}
+		value := secrets[key]
+		if value == "" || value == current {
+			continue
+		}
+		if err := onChange(value); err != nil {
+			log.Printf("ERROR: Could not apply the new %s from %s, retrying on the next check: %v", key, secretName, err)
+			continue
+		}
+		current = value
+	}
+}
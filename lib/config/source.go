@@ -0,0 +1,180 @@
+// FILE: lib/config/source.go
+// The places configuration values are read from. Each is a Source; a Chain asks them in order.
+
+package config
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// remoteSourceTimeout bounds fetching a remote source's values at startup
+const remoteSourceTimeout = 10 * time.Second
+
+// Source is one place configuration values can come from.
+type Source interface {
+	// Name identifies the source in provenance logs and --print-config, e.g. "env" or "ssm:/wise-owl"
+	Name() string
+	// Lookup returns the value of key, and whether the source sets it. Empty values count as unset.
+	Lookup(key string) (string, bool)
+}
+
+// mapSource is a source whose values were all read up front
+type mapSource struct {
+	name   string
+	values map[string]string
+	file   bool // Read from a .env file; see Chain.ExportFiles
+}
+
+// MapSource creates a source serving fixed values.
+func MapSource(name string, values map[string]string) Source {
+	return &mapSource{name: name, values: values}
+}
+
+func (s *mapSource) Name() string { return s.name }
+
+func (s *mapSource) Lookup(key string) (string, bool) {
+	value, ok := s.values[key]
+	return value, ok && value != ""
+}
+
+// envSource reads the process environment
+type envSource struct{}
+
+// EnvSource creates a source reading environment variables.
+func EnvSource() Source { return envSource{} }
+
+func (envSource) Name() string { return "env" }
+
+func (envSource) Lookup(key string) (string, bool) {
+	value := os.Getenv(key)
+	return value, value != ""
+}
+
+// FlagSource creates a source from command-line arguments of the form --server-port=8081, which
+// sets SERVER_PORT. Other arguments, such as --print-config, are ignored.
+func FlagSource(args []string) Source {
+	values := make(map[string]string)
+	for _, arg := range args {
+		name, value, ok := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !ok || !strings.HasPrefix(arg, "-") || name == "" {
+			continue
+		}
+		values[strings.ToUpper(strings.ReplaceAll(name, "-", "_"))] = value
+	}
+	return MapSource("flags", values)
+}
+
+// DotEnvSource creates a source from a file of KEY=value lines, such as .env. Blank lines and lines
+// starting with # are skipped, an "export " prefix is allowed, and values may be quoted. A missing
+// file is an empty source.
+func DotEnvSource(path string) (Source, error) {
+	values := make(map[string]string)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &mapSource{name: path, values: values, file: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(text, "export "), "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected KEY=value", path, line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %v", path, err)
+	}
+	return &mapSource{name: path, values: values, file: true}, nil
+}
+
+// DotEnvProfile returns the .env files read for service, most specific first: .env.<service>,
+// .env.local, and .env in the working directory, then the same files in the workspace root (the
+// nearest parent directory with a go.work file), so a service run from its own directory or from
+// the root finds them. Missing files are skipped.
+func DotEnvProfile(service string) []string {
+	names := []string{".env.local", ".env"}
+	if service != "" {
+		names = append([]string{".env." + service}, names...)
+	}
+
+	var dirs []string
+	if cwd, err := os.Getwd(); err == nil {
+		dirs = append(dirs, cwd)
+		for dir := cwd; ; dir = filepath.Dir(dir) {
+			if _, err := os.Stat(filepath.Join(dir, "go.work")); err == nil {
+				if dir != cwd {
+					dirs = append(dirs, dir)
+				}
+				break
+			}
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	}
+
+	var paths []string
+	for _, dir := range dirs {
+		for _, name := range names {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
+
+// SSMSource creates a source from the parameters directly under prefix in AWS Systems Manager
+// Parameter Store; /wise-owl/DB_TYPE sets DB_TYPE. SecureString parameters are decrypted.
+func SSMSource(loader *AWSConfigLoader, prefix string) (Source, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteSourceTimeout)
+	defer cancel()
+
+	values := make(map[string]string)
+	paginator := ssm.NewGetParametersByPathPaginator(loader.ssmClient, &ssm.GetParametersByPathInput{
+		Path:           aws.String(prefix),
+		WithDecryption: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list parameters under %s: %v", prefix, err)
+		}
+		for _, param := range page.Parameters {
+			values[strings.TrimPrefix(aws.ToString(param.Name), strings.TrimSuffix(prefix, "/")+"/")] = aws.ToString(param.Value)
+		}
+	}
+	return MapSource("ssm:"+prefix, values), nil
+}
+
+// SecretsManagerSource creates a source from the keys of a JSON secret in AWS Secrets Manager.
+func SecretsManagerSource(loader *AWSConfigLoader, secretName string) (Source, error) {
+	secrets, err := loader.LoadSecrets(secretName)
+	if err != nil {
+		return nil, err
+	}
+	return MapSource("secretsmanager:"+secretName, secrets), nil
+}
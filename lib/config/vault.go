@@ -0,0 +1,76 @@
+// FILE: lib/config/vault.go
+// HashiCorp Vault as a configuration source, read over Vault's HTTP API
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// VaultConfig locates a secret in a Vault KV version 2 secrets engine.
+type VaultConfig struct {
+	Address   string // e.g. https://vault.example.com:8200
+	Token     string
+	Namespace string // Vault Enterprise namespace; empty for none
+	Mount     string // Path the KV engine is mounted at, "secret" when empty
+	Path      string // Path of the secret within the mount, e.g. wise-owl/production
+}
+
+// vaultResponse is the part of a KV version 2 read response used here
+type vaultResponse struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+}
+
+// VaultSource creates a source from the keys of the latest version of a secret in Vault. Values
+// that are not strings, such as numbers, are formatted as text.
+func VaultSource(cfg VaultConfig) (Source, error) {
+	if cfg.Address == "" || cfg.Token == "" {
+		return nil, fmt.Errorf("VAULT_ADDR and VAULT_TOKEN must be set to read secrets from Vault")
+	}
+	if cfg.Mount == "" {
+		cfg.Mount = "secret"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteSourceTimeout)
+	defer cancel()
+
+	secretURL := strings.TrimSuffix(cfg.Address, "/") + "/v1/" + strings.Trim(cfg.Mount, "/") + "/data/" + strings.Trim(cfg.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, secretURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", cfg.Token)
+	if cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", cfg.Namespace)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from Vault: %v", cfg.Path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("failed to read %s from Vault: %s: %s", cfg.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var secret vaultResponse
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("failed to parse Vault secret %s: %v", cfg.Path, err)
+	}
+	values := make(map[string]string, len(secret.Data.Data))
+	for key, value := range secret.Data.Data {
+		if s, ok := value.(string); ok {
+			values[key] = s
+		} else if value != nil {
+			values[key] = fmt.Sprint(value)
+		}
+	}
+	return MapSource("vault:"+cfg.Path, values), nil
+}
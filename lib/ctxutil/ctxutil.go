@@ -0,0 +1,83 @@
+// FILE: lib/ctxutil/ctxutil.go
+// This package derives bounded contexts for the calls a handler makes while serving a request,
+// so the caller's deadline and cancellation reach Mongo and downstream gRPC services.
+
+package ctxutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultGRPCBudget bounds one outgoing gRPC call when no budget is configured
+	DefaultGRPCBudget = 5 * time.Second
+	// DefaultDatabaseBudget bounds one database operation when no budget is configured
+	DefaultDatabaseBudget = 5 * time.Second
+)
+
+// Budgets caps how long a single call made on behalf of a request may take. A call never outlives
+// the request itself: whichever of the budget and the request's deadline ends first wins.
+// Zero values use the defaults.
+type Budgets struct {
+	GRPC     time.Duration
+	Database time.Duration
+}
+
+type budgetsKey struct{}
+
+// withDefaults fills in unset budgets
+func (b Budgets) withDefaults() Budgets {
+	if b.GRPC <= 0 {
+		b.GRPC = DefaultGRPCBudget
+	}
+	if b.Database <= 0 {
+		b.Database = DefaultDatabaseBudget
+	}
+	return b
+}
+
+// Middleware creates a Gin middleware that makes budgets apply to every call derived from the request
+// through GRPC and Database.
+func Middleware(budgets Budgets) gin.HandlerFunc {
+	budgets = budgets.withDefaults()
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), budgetsKey{}, budgets))
+		c.Next()
+	}
+}
+
+// Request returns the context to derive calls from. For a *gin.Context that is the HTTP request's
+// context: the Gin context itself only reports the request's deadline and cancellation when the
+// engine enables ContextWithFallback. Any other context is returned as is.
+func Request(ctx context.Context) context.Context {
+	if c, ok := ctx.(*gin.Context); ok && c.Request != nil {
+		return c.Request.Context()
+	}
+	return ctx
+}
+
+// WithBudget derives a child of ctx that ends after budget, or earlier if ctx does.
+func WithBudget(ctx context.Context, budget time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(Request(ctx), budget)
+}
+
+// GRPC derives a context for one outgoing gRPC call.
+func GRPC(ctx context.Context) (context.Context, context.CancelFunc) {
+	return WithBudget(ctx, budgetsFrom(ctx).GRPC)
+}
+
+// Database derives a context for one database operation, or a handler's sequence of them.
+func Database(ctx context.Context) (context.Context, context.CancelFunc) {
+	return WithBudget(ctx, budgetsFrom(ctx).Database)
+}
+
+// budgetsFrom returns the budgets installed by Middleware, or the defaults
+func budgetsFrom(ctx context.Context) Budgets {
+	if budgets, ok := Request(ctx).Value(budgetsKey{}).(Budgets); ok {
+		return budgets
+	}
+	return Budgets{}.withDefaults()
+}
@@ -0,0 +1,64 @@
+// FILE: lib/ctxutil/timeout.go
+// Gin middleware that puts an overall deadline on each request.
+
+package ctxutil
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"wise-owl/lib/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultRequestTimeout is the overall deadline of a request when none is configured
+const DefaultRequestTimeout = 10 * time.Second
+
+// Timeout creates a Gin middleware that gives each request an overall deadline, so a slow query or
+// downstream call cannot hold a worker indefinitely. A zero timeout uses DefaultRequestTimeout.
+//
+// routes overrides the timeout for route groups, keyed by path prefix (e.g. "/internal/v1/admin");
+// the longest matching prefix wins, and zero lifts the deadline, e.g. for event streams.
+//
+// The deadline is cooperative: calls derived from the request (see GRPC and Database) stop when it
+// passes, and a handler that then fails with a server error is answered with a 504 request_timeout
+// error instead. It must run after apierror.Middleware.
+func Timeout(timeout time.Duration, routes map[string]time.Duration) gin.HandlerFunc {
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	return func(c *gin.Context) {
+		d := routeTimeout(c.FullPath(), timeout, routes)
+		if d <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if ctx.Err() != context.DeadlineExceeded || c.Writer.Written() || len(c.Errors) == 0 {
+			return
+		}
+		if err := c.Errors.Last().Err; apierror.From(err).Status >= http.StatusInternalServerError {
+			apierror.Respond(c, apierror.GatewayTimeout("request_timeout", "The request took too long to complete.", err))
+		}
+	}
+}
+
+// routeTimeout returns the timeout for a route: the override with the longest prefix of path, or fallback
+func routeTimeout(path string, fallback time.Duration, routes map[string]time.Duration) time.Duration {
+	timeout, longest := fallback, -1
+	for prefix, d := range routes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			timeout, longest = d, len(prefix)
+		}
+	}
+	return timeout
+}
@@ -0,0 +1,290 @@
+// FILE: lib/database/cached.go
+// Read-through caching decorator for collections that are read far more often than they change
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"wise-owl/lib/cache"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CacheOptions controls a CachedCollection
+type CacheOptions struct {
+	TTL        time.Duration // How long a read result is served from memory
+	MaxEntries int           // Distinct queries kept; 0 means no limit
+}
+
+// cachedRead is a stored result: the documents of a Find or Aggregate, the document of a
+// FindOne (nil when nothing matched), or the number from a CountDocuments.
+type cachedRead struct {
+	documents []bson.Raw
+	count     int64
+}
+
+// CachedCollection wraps a collection with TTL-based read-through caching.
+//
+// Find, FindOne, CountDocuments, and Aggregate results are cached per operation, filter, and
+// options. Any write made through the wrapper clears the whole cache, so the wrapper should be
+// the only writer in the process; writes from other processes show up once entries expire.
+// Aggregations must be read-only.
+type CachedCollection struct {
+	CollectionInterface
+	cache *cache.Cache[string, cachedRead]
+	// generation is bumped by every write so a read that raced with it does not store a stale result.
+	generation atomic.Uint64
+}
+
+// Ensure CachedCollection implements CollectionInterface
+var _ CollectionInterface = (*CachedCollection)(nil)
+
+// NewCachedCollection wraps collection with a read-through cache
+func NewCachedCollection(collection CollectionInterface, opts CacheOptions) *CachedCollection {
+	return &CachedCollection{
+		CollectionInterface: collection,
+		cache:               cache.New[string, cachedRead](opts.TTL, opts.MaxEntries),
+	}
+}
+
+// Invalidate clears every cached result
+func (cc *CachedCollection) Invalidate() {
+	cc.generation.Add(1)
+	cc.cache.Purge()
+}
+
+// Find serves the matching documents from the cache, querying the collection on a miss
+func (cc *CachedCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	read, err := cc.read("find", filter, opts, func() (cachedRead, error) {
+		cursor, err := cc.CollectionInterface.Find(ctx, filter, opts...)
+		if err != nil {
+			return cachedRead{}, err
+		}
+		return drain(ctx, cursor)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cursorFrom(read.documents)
+}
+
+// FindOne serves the matching document from the cache, querying the collection on a miss.
+// A miss that matches nothing is cached as well and reported as mongo.ErrNoDocuments.
+func (cc *CachedCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	read, err := cc.read("findOne", filter, opts, func() (cachedRead, error) {
+		raw, err := cc.CollectionInterface.FindOne(ctx, filter, opts...).Raw()
+		if err == mongo.ErrNoDocuments {
+			return cachedRead{}, nil
+		}
+		if err != nil {
+			return cachedRead{}, err
+		}
+		return cachedRead{documents: []bson.Raw{raw}}, nil
+	})
+	if err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	if len(read.documents) == 0 {
+		return mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil)
+	}
+	return mongo.NewSingleResultFromDocument(read.documents[0], nil, nil)
+}
+
+// CountDocuments serves the count from the cache, counting on a miss
+func (cc *CachedCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	read, err := cc.read("count", filter, opts, func() (cachedRead, error) {
+		count, err := cc.CollectionInterface.CountDocuments(ctx, filter, opts...)
+		return cachedRead{count: count}, err
+	})
+	return read.count, err
+}
+
+// Aggregate serves the pipeline output from the cache, running it on a miss
+func (cc *CachedCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	read, err := cc.read("aggregate", pipeline, opts, func() (cachedRead, error) {
+		cursor, err := cc.CollectionInterface.Aggregate(ctx, pipeline, opts...)
+		if err != nil {
+			return cachedRead{}, err
+		}
+		return drain(ctx, cursor)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cursorFrom(read.documents)
+}
+
+// InsertOne inserts through the wrapped collection and clears the cache
+func (cc *CachedCollection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	defer cc.Invalidate()
+	return cc.CollectionInterface.InsertOne(ctx, document, opts...)
+}
+
+// UpdateOne updates through the wrapped collection and clears the cache
+func (cc *CachedCollection) UpdateOne(ctx context.Context, filter, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	defer cc.Invalidate()
+	return cc.CollectionInterface.UpdateOne(ctx, filter, update, opts...)
+}
+
+// DeleteOne deletes through the wrapped collection and clears the cache
+func (cc *CachedCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	defer cc.Invalidate()
+	return cc.CollectionInterface.DeleteOne(ctx, filter, opts...)
+}
+
+// FindOneAndUpdate updates through the wrapped collection and clears the cache
+func (cc *CachedCollection) FindOneAndUpdate(ctx context.Context, filter, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	defer cc.Invalidate()
+	return cc.CollectionInterface.FindOneAndUpdate(ctx, filter, update, opts...)
+}
+
+// FindOneAndDelete deletes through the wrapped collection and clears the cache
+func (cc *CachedCollection) FindOneAndDelete(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) *mongo.SingleResult {
+	defer cc.Invalidate()
+	return cc.CollectionInterface.FindOneAndDelete(ctx, filter, opts...)
+}
+
+// InsertMany inserts through the wrapped collection and clears the cache
+func (cc *CachedCollection) InsertMany(ctx context.Context, documents []interface{}, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error) {
+	defer cc.Invalidate()
+	return cc.CollectionInterface.InsertMany(ctx, documents, opts...)
+}
+
+// UpdateMany updates through the wrapped collection and clears the cache
+func (cc *CachedCollection) UpdateMany(ctx context.Context, filter, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	defer cc.Invalidate()
+	return cc.CollectionInterface.UpdateMany(ctx, filter, update, opts...)
+}
+
+// DeleteMany deletes through the wrapped collection and clears the cache
+func (cc *CachedCollection) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	defer cc.Invalidate()
+	return cc.CollectionInterface.DeleteMany(ctx, filter, opts...)
+}
+
+// BulkWrite writes through the wrapped collection and clears the cache
+func (cc *CachedCollection) BulkWrite(ctx context.Context, models []mongo.WriteModel, opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
+	defer cc.Invalidate()
+	return cc.CollectionInterface.BulkWrite(ctx, models, opts...)
+}
+
+// read returns the cached result for an operation, or runs load and caches what it returns.
+// Queries whose filter or options cannot be encoded into a key bypass the cache.
+func (cc *CachedCollection) read(op string, filter, opts interface{}, load func() (cachedRead, error)) (cachedRead, error) {
+	key, ok := cacheKey(op, filter, opts)
+	if !ok {
+		return load()
+	}
+	if read, hit := cc.cache.Get(key); hit {
+		return read, nil
+	}
+
+	generation := cc.generation.Load()
+	read, err := load()
+	if err != nil {
+		return read, err
+	}
+	if cc.generation.Load() == generation {
+		cc.cache.Set(key, read)
+	}
+	return read, nil
+}
+
+// cacheKey encodes an operation and its arguments. Filters are encoded as extended JSON so
+// ObjectIDs and dates keep their types; options are plain structs of pointers and encode as JSON.
+func cacheKey(op string, filter, opts interface{}) (string, bool) {
+	filterJSON, err := bson.MarshalExtJSON(bson.D{{Key: "q", Value: canonical(filter)}}, true, false)
+	if err != nil {
+		return "", false
+	}
+	optsJSON, err := json.Marshal(opts)
+	if err != nil {
+		return "", false
+	}
+	return op + "|" + string(filterJSON) + "|" + string(optsJSON), true
+}
+
+// canonical rewrites maps as documents with sorted keys, since map iteration order would
+// otherwise give the same filter a different key on every call
+func canonical(v interface{}) interface{} {
+	switch t := v.(type) {
+	case bson.M:
+		return canonicalMap(t)
+	case map[string]interface{}:
+		return canonicalMap(t)
+	case bson.D:
+		doc := make(bson.D, len(t))
+		for i, e := range t {
+			doc[i] = bson.E{Key: e.Key, Value: canonical(e.Value)}
+		}
+		return doc
+	case bson.A:
+		return canonicalSlice(t)
+	case []interface{}:
+		return canonicalSlice(t)
+	case mongo.Pipeline:
+		stages := make(bson.A, len(t))
+		for i, stage := range t {
+			stages[i] = canonical(stage)
+		}
+		return stages
+	case []bson.D:
+		stages := make(bson.A, len(t))
+		for i, stage := range t {
+			stages[i] = canonical(stage)
+		}
+		return stages
+	default:
+		return v
+	}
+}
+
+func canonicalMap(m map[string]interface{}) bson.D {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	doc := make(bson.D, len(keys))
+	for i, key := range keys {
+		doc[i] = bson.E{Key: key, Value: canonical(m[key])}
+	}
+	return doc
+}
+
+func canonicalSlice(values []interface{}) bson.A {
+	out := make(bson.A, len(values))
+	for i, value := range values {
+		out[i] = canonical(value)
+	}
+	return out
+}
+
+// drain reads every document from cursor
+func drain(ctx context.Context, cursor *mongo.Cursor) (cachedRead, error) {
+	defer cursor.Close(ctx)
+
+	documents := []bson.Raw{}
+	for cursor.Next(ctx) {
+		// cursor.Current is only valid until the next call to Next
+		documents = append(documents, append(bson.Raw(nil), cursor.Current...))
+	}
+	return cachedRead{documents: documents}, cursor.Err()
+}
+
+// cursorFrom builds a cursor over cached documents
+func cursorFrom(documents []bson.Raw) (*mongo.Cursor, error) {
+	docs := make([]interface{}, len(documents))
+	for i, doc := range documents {
+		docs[i] = doc
+	}
+	return mongo.NewCursorFromDocuments(docs, nil, nil)
+}
@@ -5,10 +5,8 @@ package database
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"log"
-	"net"
 	"sync"
 	"time"
 
@@ -44,7 +42,16 @@ type CollectionInterface interface {
 	InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error)
 	UpdateOne(ctx context.Context, filter, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
 	DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+	FindOneAndUpdate(ctx context.Context, filter, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult
+	FindOneAndDelete(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) *mongo.SingleResult
 	CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error)
+	Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error)
+
+	// Bulk operations, for seeders, backfills, and multi-document deletes
+	InsertMany(ctx context.Context, documents []interface{}, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error)
+	UpdateMany(ctx context.Context, filter, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
+	DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+	BulkWrite(ctx context.Context, models []mongo.WriteModel, opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error)
 }
 
 // MongoCollection wraps mongo.Collection to implement CollectionInterface
@@ -58,79 +65,72 @@ var _ CollectionInterface = (*MongoCollection)(nil)
 // MongoDatabase implements DatabaseInterface for MongoDB/DocumentDB
 type MongoDatabase struct {
 	Client *mongo.Client
+	// Options tunes the connection pool and timeouts; unset fields use the backend's defaults.
+	Options config.MongoOptions
+
+	documentDB   bool
+	credentials  *rotatingCredentials // Set when Options.CredentialRefresh enables rotation
+	stopWatching context.CancelFunc   // Stops checking for rotated credentials
 }
 
 // Connect establishes a connection to MongoDB/DocumentDB
 func (mdb *MongoDatabase) Connect(uri string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	client, creds, err := connectMongo(uri, mdb.Options)
 	if err != nil {
 		return err
 	}
 
-	if err := client.Ping(ctx, readpref.Primary()); err != nil {
-		return err
-	}
-
 	mdb.Client = client
-	log.Println("Successfully connected and pinged database.")
+	mdb.credentials = creds
+	if mdb.Options.CredentialRefresh > 0 {
+		mdb.watchCredentials(uri)
+	}
 	return nil
 }
 
 // ConnectDocumentDB establishes a connection specifically to AWS DocumentDB
 func (mdb *MongoDatabase) ConnectDocumentDB(uri string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-
-	// DocumentDB requires TLS
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: false,
+	client, creds, err := connectDocumentDB(uri, mdb.Options)
+	if err != nil {
+		return err
 	}
 
-	// Custom dialer for DocumentDB with retries
-	dialer := &net.Dialer{
-		Timeout:   10 * time.Second,
-		KeepAlive: 30 * time.Second,
+	mdb.Client = client
+	mdb.documentDB = true
+	mdb.credentials = creds
+	log.Println("Successfully connected to AWS DocumentDB.")
+	if mdb.Options.CredentialRefresh > 0 {
+		mdb.watchCredentials(uri)
 	}
+	return nil
+}
 
-	clientOptions := options.Client().
-		ApplyURI(uri).
-		SetTLSConfig(tlsConfig).
-		SetDialer(dialer).
-		SetReplicaSet("rs0").
-		SetReadPreference(readpref.SecondaryPreferred()).
-		SetMaxConnIdleTime(30 * time.Second).
-		SetMaxPoolSize(10).
-		SetRetryWrites(false) // DocumentDB doesn't support retryable writes
-
-	client, err := mongo.Connect(ctx, clientOptions)
+// connectMongo connects to self-hosted MongoDB and pings it. Rotating credentials are returned when
+// opts.CredentialRefresh is set.
+func connectMongo(uri string, opts config.MongoOptions) (*mongo.Client, *rotatingCredentials, error) {
+	opts = withDefaults(opts, mongoDefaults)
+	clientOpts, creds, err := rotatableClientOptions(uri, opts)
 	if err != nil {
-		return fmt.Errorf("failed to connect to DocumentDB: %v", err)
+		return nil, nil, err
 	}
+	pref, _ := readPreference(opts)
 
-	// Test the connection with retry
-	var pingErr error
-	for i := 0; i < 3; i++ {
-		pingCtx, pingCancel := context.WithTimeout(context.Background(), 5*time.Second)
-		pingErr = client.Ping(pingCtx, readpref.SecondaryPreferred())
-		pingCancel()
+	ctx, cancel := context.WithTimeout(context.Background(), opts.ConnectTimeout)
+	defer cancel()
 
-		if pingErr == nil {
-			break
-		}
-		log.Printf("DocumentDB ping attempt %d failed: %v", i+1, pingErr)
-		time.Sleep(time.Second)
+	client, err := mongo.Connect(ctx, clientOpts)
+	if err == nil {
+		err = client.Ping(ctx, pref)
 	}
-
-	if pingErr != nil {
-		return fmt.Errorf("failed to ping DocumentDB after retries: %v", pingErr)
+	if err != nil {
+		if creds != nil {
+			creds.close()
+		}
+		return nil, nil, err
 	}
 
-	mdb.Client = client
-	log.Println("Successfully connected to AWS DocumentDB.")
-	return nil
+	log.Printf("Successfully connected and pinged database (max pool size: %d, read preference: %s).", opts.MaxPoolSize, pref.Mode())
+	return client, creds, nil
 }
 
 // GetClient returns the underlying mongo client
@@ -146,6 +146,12 @@ func (mdb *MongoDatabase) GetCollection(dbName, collectionName string) Collectio
 
 // Close closes the database connection
 func (mdb *MongoDatabase) Close() error {
+	if mdb.stopWatching != nil {
+		mdb.stopWatching()
+	}
+	if mdb.credentials != nil {
+		defer mdb.credentials.close()
+	}
 	if mdb.Client != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
@@ -172,13 +178,18 @@ var (
 	onceNew sync.Once
 )
 
-// NewDatabase creates a new database instance based on the database type
+// NewDatabase creates a new database instance based on the database type, using default connection options
 func NewDatabase(dbType DatabaseType, uri string) (DatabaseInterface, error) {
+	return NewDatabaseWithOptions(dbType, uri, config.MongoOptions{})
+}
+
+// NewDatabaseWithOptions creates a new database instance with tuned pool and timeout options
+func NewDatabaseWithOptions(dbType DatabaseType, uri string, opts config.MongoOptions) (DatabaseInterface, error) {
 	log.Printf("Creating database connection - Type: %s", dbType)
 
 	switch dbType {
 	case MongoDB:
-		db := &MongoDatabase{}
+		db := &MongoDatabase{Options: opts}
 		err := db.Connect(uri)
 		if err != nil {
 			log.Printf("Failed to connect to MongoDB: %v", err)
@@ -187,7 +198,7 @@ func NewDatabase(dbType DatabaseType, uri string) (DatabaseInterface, error) {
 		log.Println("Successfully connected to MongoDB")
 		return db, nil
 	case DocumentDB:
-		db := &MongoDatabase{}
+		db := &MongoDatabase{Options: opts}
 		err := db.ConnectDocumentDB(uri)
 		if err != nil {
 			log.Printf("Failed to connect to DocumentDB: %v", err)
@@ -198,7 +209,7 @@ func NewDatabase(dbType DatabaseType, uri string) (DatabaseInterface, error) {
 	default:
 		log.Printf("Unsupported database type: %s, falling back to MongoDB", dbType)
 		// Fallback to MongoDB for unknown types
-		db := &MongoDatabase{}
+		db := &MongoDatabase{Options: opts}
 		err := db.Connect(uri)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to fallback MongoDB: %v", err)
@@ -208,10 +219,18 @@ func NewDatabase(dbType DatabaseType, uri string) (DatabaseInterface, error) {
 	}
 }
 
-// NewDatabaseSingleton creates a singleton database instance
-func NewDatabaseSingleton(dbType DatabaseType, uri string) DatabaseInterface {
+// NewDatabaseSingleton creates a process-wide database instance on first call and returns it afterwards.
+//
+// Deprecated: a process-global connection cannot be replaced in tests or shared by two services in one
+// binary, and later calls silently ignore their arguments. Use NewDatabaseWithOptions or CreateDatabase
+// and pass the instance to whatever needs it.
+func NewDatabaseSingleton(dbType DatabaseType, uri string, opts ...config.MongoOptions) DatabaseInterface {
 	onceNew.Do(func() {
-		db, err := NewDatabase(dbType, uri)
+		var dbOpts config.MongoOptions
+		if len(opts) > 0 {
+			dbOpts = opts[0]
+		}
+		db, err := NewDatabaseWithOptions(dbType, uri, dbOpts)
 		if err != nil {
 			log.Fatalf("FATAL: Failed to create database instance: %v", err)
 		}
@@ -220,14 +239,17 @@ func NewDatabaseSingleton(dbType DatabaseType, uri string) DatabaseInterface {
 	return dbInstance
 }
 
-// CreateDatabaseSingleton creates a singleton database instance using config
-// This function maintains backward compatibility with existing code
+// CreateDatabaseSingleton creates the process-wide database instance using config.
+//
+// Deprecated: use CreateDatabase, which returns an independent instance.
 func CreateDatabaseSingleton(cfg *config.Config) DatabaseInterface {
 	dbType := DatabaseType(cfg.DB_TYPE)
-	return NewDatabaseSingleton(dbType, cfg.MONGODB_URI)
+	return NewDatabaseSingleton(dbType, cfg.MONGODB_URI, cfg.Mongo)
 }
 
-// GetDatabaseInstance returns the singleton database instance
+// GetDatabaseInstance returns the process-wide database instance.
+//
+// Deprecated: hold the DatabaseInterface returned by CreateDatabase instead of looking it up globally.
 func GetDatabaseInstance() DatabaseInterface {
 	if dbInstance == nil {
 		log.Fatal("FATAL: Database has not been initialized. Call NewDatabaseSingleton() first.")
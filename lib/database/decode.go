@@ -0,0 +1,87 @@
+// FILE: lib/database/decode.go
+// Typed helpers that run a query or pipeline and decode every result
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindAll runs a find and decodes every matching document into a T.
+// No matches give an empty, non-nil slice so results encode as [] rather than null.
+func FindAll[T any](ctx context.Context, collection CollectionInterface, filter interface{}, opts ...*options.FindOptions) ([]T, error) {
+	cursor, err := collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeAll[T](ctx, cursor)
+}
+
+// AggregateAll runs an aggregation pipeline and decodes every output document into a T.
+// No output gives an empty, non-nil slice.
+func AggregateAll[T any](ctx context.Context, collection CollectionInterface, pipeline interface{}) ([]T, error) {
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	return decodeAll[T](ctx, cursor)
+}
+
+// AggregateOne runs a pipeline expected to produce at most one document, such as a $group on
+// _id: nil or a $count stage. The boolean is false when the pipeline produced nothing.
+func AggregateOne[T any](ctx context.Context, collection CollectionInterface, pipeline interface{}) (T, bool, error) {
+	var result T
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return result, false, err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		return result, false, cursor.Err()
+	}
+	if err := cursor.Decode(&result); err != nil {
+		return result, false, fmt.Errorf("failed to decode aggregation result: %v", err)
+	}
+	return result, true, nil
+}
+
+// DistinctStrings returns the distinct string values of field among documents matching filter,
+// in no particular order. Non-string values are skipped.
+func DistinctStrings(ctx context.Context, collection CollectionInterface, field string, filter interface{}) ([]string, error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{"_id": "$" + field}}},
+	}
+	rows, err := AggregateAll[struct {
+		Value interface{} `bson:"_id"`
+	}](ctx, collection, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if value, ok := row.Value.(string); ok {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// decodeAll drains the cursor into a slice of T
+func decodeAll[T any](ctx context.Context, cursor *mongo.Cursor) ([]T, error) {
+	results := []T{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode results: %v", err)
+	}
+	return results, nil
+}
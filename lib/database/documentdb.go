@@ -7,50 +7,95 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"log"
 	"net"
+	"time"
+
+	"wise-owl/lib/config"
 
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
-// CreateDocumentDBConnection creates a connection specifically configured for AWS DocumentDB
-func CreateDocumentDBConnection(uri string) (*mongo.Client, error) {
+// CreateDocumentDBConnection creates a connection specifically configured for AWS DocumentDB.
+// Options left unset fall back to the DocumentDB defaults (pool size 10, rs0, secondaryPreferred).
+func CreateDocumentDBConnection(uri string, opts ...config.MongoOptions) (*mongo.Client, error) {
+	var dbOpts config.MongoOptions
+	if len(opts) > 0 {
+		dbOpts = opts[0]
+	}
+	dbOpts.CredentialRefresh = 0 // Only a MongoDatabase watches for rotated credentials
+	client, _, err := connectDocumentDB(uri, dbOpts)
+	return client, err
+}
+
+// CreateDocumentDBDatabase creates a database connection using DocumentDB-specific settings
+func CreateDocumentDBDatabase(uri, dbName string) (*mongo.Database, error) {
+	client, err := CreateDocumentDBConnection(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Database(dbName), nil
+}
+
+// connectDocumentDB connects over TLS and pings with retries, since a cluster can take a moment to
+// accept connections. Rotating credentials are returned when opts.CredentialRefresh is set.
+func connectDocumentDB(uri string, opts config.MongoOptions) (*mongo.Client, *rotatingCredentials, error) {
+	opts = withDefaults(opts, documentDBDefaults)
+	clientOpts, creds, err := rotatableClientOptions(uri, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	pref, _ := readPreference(opts)
+
 	// DocumentDB requires TLS
 	tlsConfig := &tls.Config{
 		InsecureSkipVerify: false,
 	}
 
-	// Custom dialer for DocumentDB
-	dialer := &net.Dialer{}
+	// Custom dialer for DocumentDB with retries
+	dialer := &net.Dialer{
+		Timeout:   opts.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
 
-	clientOptions := options.Client().
-		ApplyURI(uri).
+	clientOpts.
 		SetTLSConfig(tlsConfig).
 		SetDialer(dialer).
-		SetReplicaSet("rs0").
-		SetReadPreference(readpref.SecondaryPreferred())
+		SetMaxConnIdleTime(30 * time.Second).
+		SetRetryWrites(false) // DocumentDB doesn't support retryable writes
 
-	client, err := mongo.Connect(context.TODO(), clientOptions)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to DocumentDB: %v", err)
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), opts.ConnectTimeout)
+	defer cancel()
 
-	// Test the connection
-	err = client.Ping(context.TODO(), nil)
+	client, err := mongo.Connect(ctx, clientOpts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to ping DocumentDB: %v", err)
+		if creds != nil {
+			creds.close()
+		}
+		return nil, nil, fmt.Errorf("failed to connect to DocumentDB: %v", err)
 	}
 
-	return client, nil
-}
+	// Test the connection with retry
+	var pingErr error
+	for i := 0; i < 3; i++ {
+		pingCtx, pingCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pingErr = client.Ping(pingCtx, pref)
+		pingCancel()
 
-// CreateDocumentDBDatabase creates a database connection using DocumentDB-specific settings
-func CreateDocumentDBDatabase(uri, dbName string) (*mongo.Database, error) {
-	client, err := CreateDocumentDBConnection(uri)
-	if err != nil {
-		return nil, err
+		if pingErr == nil {
+			break
+		}
+		log.Printf("DocumentDB ping attempt %d failed: %v", i+1, pingErr)
+		time.Sleep(time.Second)
 	}
 
-	return client.Database(dbName), nil
+	if pingErr != nil {
+		client.Disconnect(context.Background())
+		if creds != nil {
+			creds.close()
+		}
+		return nil, nil, fmt.Errorf("failed to ping DocumentDB after retries: %v", pingErr)
+	}
+	return client, creds, nil
 }
@@ -7,8 +7,9 @@ import (
 
 // DatabaseConfig holds database-specific configuration
 type DatabaseConfig struct {
-	Type DatabaseType
-	URI  string
+	Type    DatabaseType
+	URI     string
+	Options config.MongoOptions
 }
 
 // LoadDatabaseConfig loads database configuration from the main config
@@ -25,16 +26,18 @@ func LoadDatabaseConfig(cfg *config.Config) *DatabaseConfig {
 	}
 
 	return &DatabaseConfig{
-		Type: dbType,
-		URI:  cfg.MONGODB_URI,
+		Type:    dbType,
+		URI:     cfg.MONGODB_URI,
+		Options: cfg.Mongo,
 	}
 }
 
-// CreateDatabase creates a database instance based on configuration
+// CreateDatabase connects a new database instance based on configuration.
+// Every call returns an independent connection pool that the caller owns and should Close.
 func CreateDatabase(cfg *config.Config) (DatabaseInterface, error) {
 	dbConfig := LoadDatabaseConfig(cfg)
 
 	log.Printf("Initializing database connection - Type: %s", dbConfig.Type)
 
-	return NewDatabase(dbConfig.Type, dbConfig.URI)
+	return NewDatabaseWithOptions(dbConfig.Type, dbConfig.URI, dbConfig.Options)
 }
@@ -0,0 +1,185 @@
+// FILE: lib/database/indexes.go
+// Declarative index management: services list the indexes they rely on and reconcile them at startup
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Index declares an index a service relies on
+type Index struct {
+	Collection string
+	Keys       bson.D
+	Unique     bool
+	// ExpireAfter makes a TTL index; Keys must be a single date field.
+	ExpireAfter time.Duration
+	// Name defaults to MongoDB's generated name, e.g. "user_id_1_date_-1".
+	Name string
+}
+
+// IndexName returns the declared name, or the name MongoDB would generate from the keys
+func (idx Index) IndexName() string {
+	if idx.Name != "" {
+		return idx.Name
+	}
+	parts := make([]string, 0, len(idx.Keys)*2)
+	for _, key := range idx.Keys {
+		parts = append(parts, key.Key, fmt.Sprint(key.Value))
+	}
+	return strings.Join(parts, "_")
+}
+
+// model converts the declaration into the driver's index model
+func (idx Index) model() mongo.IndexModel {
+	opts := options.Index().SetName(idx.IndexName())
+	if idx.Unique {
+		opts.SetUnique(true)
+	}
+	if idx.ExpireAfter > 0 {
+		opts.SetExpireAfterSeconds(int32(idx.ExpireAfter.Seconds()))
+	}
+	return mongo.IndexModel{Keys: idx.Keys, Options: opts}
+}
+
+// EnsureIndexes reconciles the database with the declared indexes. It is safe to call on every startup.
+//
+// Missing indexes are created. An index whose keys or options differ from its declaration is
+// logged as drift, dropped, and rebuilt. Indexes that exist but are not declared are logged and
+// left in place, so removing one stays a deliberate manual step.
+func EnsureIndexes(ctx context.Context, db *mongo.Database, indexes []Index) error {
+	var collections []string
+	byCollection := make(map[string][]Index)
+	for _, idx := range indexes {
+		if _, seen := byCollection[idx.Collection]; !seen {
+			collections = append(collections, idx.Collection)
+		}
+		byCollection[idx.Collection] = append(byCollection[idx.Collection], idx)
+	}
+
+	var errs []error
+	for _, name := range collections {
+		if err := ensureCollectionIndexes(ctx, db.Collection(name), byCollection[name]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ensureCollectionIndexes reconciles the indexes of a single collection
+func ensureCollectionIndexes(ctx context.Context, collection *mongo.Collection, declared []Index) error {
+	specs, err := collection.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		// A collection that does not exist yet has no indexes
+		var cmdErr mongo.CommandError
+		if !errors.As(err, &cmdErr) || cmdErr.Name != "NamespaceNotFound" {
+			return fmt.Errorf("failed to list indexes: %v", err)
+		}
+		specs = nil
+	}
+
+	existing := make(map[string]*mongo.IndexSpecification, len(specs))
+	for _, spec := range specs {
+		existing[spec.Name] = spec
+	}
+
+	var errs []error
+	for _, idx := range declared {
+		name := idx.IndexName()
+		spec, found := existing[name]
+		delete(existing, name)
+
+		if found {
+			drift := indexDrift(idx, spec)
+			if drift == "" {
+				continue
+			}
+			log.Printf("WARN: Index %s.%s drifted from its declaration (%s); rebuilding", collection.Name(), name, drift)
+			if _, err := collection.Indexes().DropOne(ctx, name); err != nil {
+				errs = append(errs, fmt.Errorf("failed to drop index %s: %v", name, err))
+				continue
+			}
+		}
+
+		if _, err := collection.Indexes().CreateOne(ctx, idx.model()); err != nil {
+			errs = append(errs, fmt.Errorf("failed to create index %s: %v", name, err))
+			continue
+		}
+		log.Printf("Created index %s.%s", collection.Name(), name)
+	}
+
+	for name := range existing {
+		if name == "_id_" {
+			continue
+		}
+		log.Printf("WARN: Index %s.%s is not declared by the service; leaving it in place", collection.Name(), name)
+	}
+	return errors.Join(errs...)
+}
+
+// indexDrift describes how an existing index differs from its declaration, or returns "" if it matches
+func indexDrift(idx Index, spec *mongo.IndexSpecification) string {
+	var keys bson.D
+	if err := bson.Unmarshal(spec.KeysDocument, &keys); err != nil {
+		return fmt.Sprintf("unreadable keys: %v", err)
+	}
+	if !sameKeys(idx.Keys, keys) {
+		return fmt.Sprintf("keys %v, declared %v", keys, idx.Keys)
+	}
+
+	unique := spec.Unique != nil && *spec.Unique
+	if unique != idx.Unique {
+		return fmt.Sprintf("unique %t, declared %t", unique, idx.Unique)
+	}
+
+	var ttl, declaredTTL int64 = -1, -1
+	if spec.ExpireAfterSeconds != nil {
+		ttl = int64(*spec.ExpireAfterSeconds)
+	}
+	if idx.ExpireAfter > 0 {
+		declaredTTL = int64(idx.ExpireAfter.Seconds())
+	}
+	if ttl != declaredTTL {
+		return fmt.Sprintf("expireAfterSeconds %d, declared %d", ttl, declaredTTL)
+	}
+	return ""
+}
+
+// sameKeys compares key documents field by field. Directions are compared by value so that
+// 1, int64(1), and 1.0 match, since the server may not return the numeric type it was given.
+func sameKeys(declared, actual bson.D) bool {
+	if len(declared) != len(actual) {
+		return false
+	}
+	for i := range declared {
+		if declared[i].Key != actual[i].Key || keyValue(declared[i].Value) != keyValue(actual[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// keyValue normalizes an index key direction or type for comparison
+func keyValue(v interface{}) string {
+	switch n := v.(type) {
+	case int:
+		return fmt.Sprint(float64(n))
+	case int32:
+		return fmt.Sprint(float64(n))
+	case int64:
+		return fmt.Sprint(float64(n))
+	case float64:
+		return fmt.Sprint(n)
+	default:
+		return fmt.Sprint(v)
+	}
+}
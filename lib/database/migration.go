@@ -0,0 +1,219 @@
+// FILE: lib/database/migration.go
+// Toolkit for moving a field to a new representation while services keep running
+
+package database
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxReportedErrors caps how many per-document errors a backfill keeps for its result.
+const maxReportedErrors = 20
+
+// FieldMigration describes moving documents from a legacy field to a new target field.
+//
+// A migration is rolled out in phases: writers dual-write both fields, readers
+// dual-read either field, a backfill populates the target on existing documents,
+// and a verification report confirms every document agrees before the legacy
+// field is dropped.
+type FieldMigration struct {
+	Name        string
+	LegacyField string
+	TargetField string
+	// Transform converts a legacy value into its new representation.
+	Transform func(legacy interface{}) (interface{}, error)
+}
+
+// BackfillOptions controls how a backfill walks the collection
+type BackfillOptions struct {
+	BatchSize int32
+	DryRun    bool // Only count what would change
+}
+
+// BackfillResult summarizes a backfill run
+type BackfillResult struct {
+	Migration string        `json:"migration"`
+	Scanned   int64         `json:"scanned"`
+	Updated   int64         `json:"updated"`
+	Failed    int64         `json:"failed"`
+	DryRun    bool          `json:"dry_run"`
+	Duration  time.Duration `json:"duration"`
+	Errors    []string      `json:"errors,omitempty"`
+}
+
+// VerificationReport describes how far a migration has progressed
+type VerificationReport struct {
+	Migration     string        `json:"migration"`
+	Total         int64         `json:"total"`
+	Migrated      int64         `json:"migrated"`
+	Pending       int64         `json:"pending"`
+	Mismatched    int64         `json:"mismatched"`
+	MismatchedIDs []interface{} `json:"mismatched_ids,omitempty"`
+	CheckedAt     time.Time     `json:"checked_at"`
+}
+
+// Complete reports whether every document carries a target value that matches its legacy value
+func (r *VerificationReport) Complete() bool {
+	return r.Pending == 0 && r.Mismatched == 0
+}
+
+// String formats the report for logs
+func (r *VerificationReport) String() string {
+	return fmt.Sprintf("migration %s: %d/%d migrated, %d pending, %d mismatched",
+		r.Migration, r.Migrated, r.Total, r.Pending, r.Mismatched)
+}
+
+// DualWriteFields returns the fields a writer should $set so both representations stay in sync
+func (m FieldMigration) DualWriteFields(legacy interface{}) (bson.M, error) {
+	target, err := m.Transform(legacy)
+	if err != nil {
+		return nil, fmt.Errorf("migration %s: failed to transform %v: %v", m.Name, legacy, err)
+	}
+
+	return bson.M{
+		m.LegacyField: legacy,
+		m.TargetField: target,
+	}, nil
+}
+
+// DualReadFilter returns a filter that matches documents by either representation.
+// If the legacy value cannot be transformed, only the legacy field is matched.
+func (m FieldMigration) DualReadFilter(legacy interface{}) bson.M {
+	target, err := m.Transform(legacy)
+	if err != nil {
+		return bson.M{m.LegacyField: legacy}
+	}
+
+	return bson.M{"$or": bson.A{
+		bson.M{m.LegacyField: legacy},
+		bson.M{m.TargetField: target},
+	}}
+}
+
+// Backfill populates the target field on every document that only has the legacy field
+func (m FieldMigration) Backfill(ctx context.Context, collection CollectionInterface, opts BackfillOptions) (*BackfillResult, error) {
+	ctx = CrossUser(ctx) // A backfill walks every user's documents
+	start := time.Now()
+	result := &BackfillResult{Migration: m.Name, DryRun: opts.DryRun}
+
+	filter := bson.M{
+		m.LegacyField: bson.M{"$exists": true},
+		m.TargetField: bson.M{"$exists": false},
+	}
+	findOpts := options.Find().SetProjection(bson.M{m.LegacyField: 1})
+	if opts.BatchSize > 0 {
+		findOpts.SetBatchSize(opts.BatchSize)
+	}
+
+	cursor, err := collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, fmt.Errorf("migration %s: failed to query pending documents: %v", m.Name, err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		result.Scanned++
+
+		id := cursor.Current.Lookup("_id")
+		var legacy interface{}
+		if err := cursor.Current.Lookup(m.LegacyField).Unmarshal(&legacy); err != nil {
+			result.fail(fmt.Sprintf("%v: failed to decode %s: %v", id, m.LegacyField, err))
+			continue
+		}
+
+		target, err := m.Transform(legacy)
+		if err != nil {
+			result.fail(fmt.Sprintf("%v: failed to transform %v: %v", id, legacy, err))
+			continue
+		}
+
+		if opts.DryRun {
+			result.Updated++
+			continue
+		}
+
+		// Only touch documents that are still pending, so a concurrent dual-write wins.
+		updateFilter := bson.M{"_id": id, m.TargetField: bson.M{"$exists": false}}
+		update := bson.M{"$set": bson.M{m.TargetField: target}}
+		if _, err := collection.UpdateOne(ctx, updateFilter, update); err != nil {
+			result.fail(fmt.Sprintf("%v: failed to update: %v", id, err))
+			continue
+		}
+		result.Updated++
+	}
+	if err := cursor.Err(); err != nil {
+		return result, fmt.Errorf("migration %s: cursor error after %d documents: %v", m.Name, result.Scanned, err)
+	}
+
+	result.Duration = time.Since(start)
+	log.Printf("Migration %s backfill finished - scanned: %d, updated: %d, failed: %d, dry run: %t",
+		m.Name, result.Scanned, result.Updated, result.Failed, result.DryRun)
+	return result, nil
+}
+
+// Verify checks every document that has the legacy field and reports pending and mismatched ones.
+// At most sampleLimit mismatched document IDs are included in the report.
+func (m FieldMigration) Verify(ctx context.Context, collection CollectionInterface, sampleLimit int) (*VerificationReport, error) {
+	ctx = CrossUser(ctx)
+	report := &VerificationReport{Migration: m.Name}
+
+	filter := bson.M{m.LegacyField: bson.M{"$exists": true}}
+	projection := options.Find().SetProjection(bson.M{m.LegacyField: 1, m.TargetField: 1})
+	cursor, err := collection.Find(ctx, filter, projection)
+	if err != nil {
+		return nil, fmt.Errorf("migration %s: failed to query documents: %v", m.Name, err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		report.Total++
+
+		stored, err := cursor.Current.LookupErr(m.TargetField)
+		if err != nil {
+			report.Pending++
+			continue
+		}
+
+		var legacy interface{}
+		matches := false
+		if err := cursor.Current.Lookup(m.LegacyField).Unmarshal(&legacy); err == nil {
+			if expected, err := m.Transform(legacy); err == nil {
+				if expectedType, expectedBytes, err := bson.MarshalValue(expected); err == nil {
+					matches = stored.Type == expectedType && bytes.Equal(stored.Value, expectedBytes)
+				}
+			}
+		}
+
+		if !matches {
+			report.Mismatched++
+			if len(report.MismatchedIDs) < sampleLimit {
+				var id interface{}
+				cursor.Current.Lookup("_id").Unmarshal(&id)
+				report.MismatchedIDs = append(report.MismatchedIDs, id)
+			}
+			continue
+		}
+		report.Migrated++
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("migration %s: cursor error: %v", m.Name, err)
+	}
+
+	report.CheckedAt = time.Now().UTC()
+	return report, nil
+}
+
+// fail records a per-document error, keeping only the first few messages
+func (r *BackfillResult) fail(message string) {
+	r.Failed++
+	if len(r.Errors) < maxReportedErrors {
+		r.Errors = append(r.Errors, message)
+	}
+}
@@ -0,0 +1,187 @@
+// FILE: lib/database/monitor.go
+// Command monitoring: every command's duration goes to lib/metrics, and commands slower than
+// Options.SlowQueryThreshold are logged with the shape of their filters, never their values.
+// Unscoped queries on user-scoped collections are logged the same way.
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/metrics"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+const (
+	// maxRedactedDepth is how deeply nested documents are followed before they are elided
+	maxRedactedDepth = 6
+	// maxRedactedElements is how many stages, updates, or other documents of an array are shown
+	maxRedactedElements = 10
+	// maxRedactedLength caps the logged command
+	maxRedactedLength = 2048
+)
+
+// unmonitoredCommands are handshakes and keepalives, which are never slow in a way worth logging
+var unmonitoredCommands = map[string]bool{
+	"hello": true, "isMaster": true, "ismaster": true, "ping": true, "endSessions": true,
+	"saslStart": true, "saslContinue": true, "authenticate": true, "getnonce": true,
+}
+
+// sessionFields are command fields the driver adds to every command; they say nothing about the query
+var sessionFields = map[string]bool{
+	"lsid": true, "$clusterTime": true, "$db": true, "txnNumber": true, "$readPreference": true,
+	"autocommit": true, "startTransaction": true, "readConcern": true, "writeConcern": true,
+	"apiVersion": true, "apiStrict": true, "apiDeprecationErrors": true,
+}
+
+// startedCommand is what a finished event needs from its started event, which alone carries the command
+type startedCommand struct {
+	database string
+	command  bson.Raw
+}
+
+// commandKey identifies a command in flight
+type commandKey struct {
+	connection string
+	requestID  int64
+}
+
+// slowQueryMonitor logs commands taking at least threshold
+type slowQueryMonitor struct {
+	threshold time.Duration
+	inFlight  sync.Map // commandKey -> startedCommand
+}
+
+// newCommandMonitor returns the driver command monitor of a client: durations for lib/metrics,
+// slow-query logging unless threshold is zero, and logging of unscoped queries on user-scoped
+// collections unless tenantFilter is off (see tenant.go)
+func newCommandMonitor(threshold time.Duration, tenantFilter TenantFilterMode) *event.CommandMonitor {
+	timings := metrics.MongoMonitor()
+	audit := tenantFilter != TenantFilterOff
+	if threshold <= 0 && !audit {
+		return timings
+	}
+	slow := &slowQueryMonitor{threshold: threshold}
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			if audit {
+				auditTenantFilter(ctx, e)
+			}
+			if threshold <= 0 || unmonitoredCommands[e.CommandName] {
+				return
+			}
+			slow.inFlight.Store(commandKey{e.ConnectionID, e.RequestID}, startedCommand{database: e.DatabaseName, command: e.Command})
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			timings.Succeeded(ctx, e)
+			slow.finished(ctx, e.CommandFinishedEvent, "")
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			timings.Failed(ctx, e)
+			slow.finished(ctx, e.CommandFinishedEvent, e.Failure)
+		},
+	}
+}
+
+// finished logs a command if it was slow; failure is the error of a failed command
+func (m *slowQueryMonitor) finished(ctx context.Context, e event.CommandFinishedEvent, failure string) {
+	value, ok := m.inFlight.LoadAndDelete(commandKey{e.ConnectionID, e.RequestID})
+	if !ok || e.Duration < m.threshold {
+		return
+	}
+	started := value.(startedCommand)
+
+	prefix := "WARN: "
+	if traceID := apierror.TraceIDFromContext(ctx); traceID != "" {
+		prefix += "[" + traceID + "] "
+	}
+	outcome := ""
+	if failure != "" {
+		outcome = " and failed (" + failure + ")"
+	}
+	log.Printf("%sSlow MongoDB %s on %s took %s%s: %s",
+		prefix, e.CommandName, started.database, e.Duration.Round(time.Millisecond), outcome, redactCommand(started.command))
+}
+
+// redactCommand renders a command as extended JSON with every value replaced by "?", so the log
+// shows which fields were filtered, sorted, and projected on without any user's data. The
+// collection name, and the size of value lists and inserted documents, are kept.
+func redactCommand(command bson.Raw) string {
+	elements, err := command.Elements()
+	if err != nil || len(elements) == 0 {
+		return "(command unavailable)"
+	}
+
+	redacted := bson.D{}
+	for i, element := range elements {
+		key, value := element.Key(), element.Value()
+		switch {
+		case sessionFields[key]:
+			continue
+		case i == 0:
+			// The command name's value is the collection, e.g. {"find": "sessions"}
+			redacted = append(redacted, bson.E{Key: key, Value: value})
+		case key == "documents":
+			values, _ := value.Array().Values()
+			redacted = append(redacted, bson.E{Key: key, Value: fmt.Sprintf("[%d documents]", len(values))})
+		default:
+			redacted = append(redacted, bson.E{Key: key, Value: redactValue(value, 1)})
+		}
+	}
+
+	out, err := bson.MarshalExtJSON(redacted, false, false)
+	if err != nil {
+		return "(command unavailable)"
+	}
+	if len(out) > maxRedactedLength {
+		return string(out[:maxRedactedLength]) + "..."
+	}
+	return string(out)
+}
+
+// redactValue replaces the values in v with "?", keeping the field names of documents and the
+// structure of arrays of documents, such as aggregation stages
+func redactValue(v bson.RawValue, depth int) interface{} {
+	if depth > maxRedactedDepth {
+		return "..."
+	}
+	switch v.Type {
+	case bson.TypeEmbeddedDocument:
+		elements, err := v.Document().Elements()
+		if err != nil {
+			return "?"
+		}
+		redacted := bson.D{}
+		for _, element := range elements {
+			redacted = append(redacted, bson.E{Key: element.Key(), Value: redactValue(element.Value(), depth+1)})
+		}
+		return redacted
+	case bson.TypeArray:
+		values, err := v.Array().Values()
+		if err != nil {
+			return "?"
+		}
+		if len(values) == 0 || values[0].Type != bson.TypeEmbeddedDocument {
+			// Value lists such as $in are only shown by size; their length often explains the slowness
+			return fmt.Sprintf("[%d values]", len(values))
+		}
+		redacted := bson.A{}
+		for i, value := range values {
+			if i == maxRedactedElements {
+				redacted = append(redacted, fmt.Sprintf("... %d more", len(values)-i))
+				break
+			}
+			redacted = append(redacted, redactValue(value, depth+1))
+		}
+		return redacted
+	default:
+		return "?"
+	}
+}
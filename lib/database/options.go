@@ -0,0 +1,119 @@
+// FILE: lib/database/options.go
+// Client options shared by the MongoDB and DocumentDB connection paths
+
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"wise-owl/lib/config"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// mongoDefaults fill in options left unset for self-hosted MongoDB; the driver's defaults cover the rest
+var mongoDefaults = config.MongoOptions{
+	ConnectTimeout:     10 * time.Second,
+	SlowQueryThreshold: 100 * time.Millisecond,
+	TenantFilter:       string(TenantFilterLog),
+}
+
+// documentDBDefaults fill in options left unset for AWS DocumentDB
+var documentDBDefaults = config.MongoOptions{
+	MaxPoolSize:    10,
+	ConnectTimeout: 10 * time.Second,
+	ReadPreference: "secondaryPreferred",
+	ReplicaSet:     "rs0",
+
+	SlowQueryThreshold: 100 * time.Millisecond,
+	TenantFilter:       string(TenantFilterLog),
+}
+
+// withDefaults returns opts with every unset field taken from defaults
+func withDefaults(opts, defaults config.MongoOptions) config.MongoOptions {
+	if opts.MaxPoolSize == 0 {
+		opts.MaxPoolSize = defaults.MaxPoolSize
+	}
+	if opts.MinPoolSize == 0 {
+		opts.MinPoolSize = defaults.MinPoolSize
+	}
+	if opts.ConnectTimeout == 0 {
+		opts.ConnectTimeout = defaults.ConnectTimeout
+	}
+	if opts.SocketTimeout == 0 {
+		opts.SocketTimeout = defaults.SocketTimeout
+	}
+	if opts.ReadPreference == "" {
+		opts.ReadPreference = defaults.ReadPreference
+	}
+	if opts.ReplicaSet == "" {
+		opts.ReplicaSet = defaults.ReplicaSet
+	}
+	if opts.SlowQueryThreshold == 0 {
+		opts.SlowQueryThreshold = defaults.SlowQueryThreshold
+	}
+	if opts.TenantFilter == "" {
+		opts.TenantFilter = defaults.TenantFilter
+	}
+	return opts
+}
+
+// clientOptions builds driver options for uri. Explicit options override the same settings in the URI.
+func clientOptions(uri string, opts config.MongoOptions) (*options.ClientOptions, error) {
+	tenantFilter := tenantFilterMode(opts.TenantFilter)
+	// UserScopedCollections are created from the client later, so they read the mode from here
+	enforceTenantFilter.Store(tenantFilter == TenantFilterEnforce)
+	clientOpts := options.Client().ApplyURI(uri).SetMonitor(newCommandMonitor(opts.SlowQueryThreshold, tenantFilter))
+
+	if opts.MaxPoolSize > 0 {
+		clientOpts.SetMaxPoolSize(opts.MaxPoolSize)
+	}
+	if opts.MinPoolSize > 0 {
+		clientOpts.SetMinPoolSize(opts.MinPoolSize)
+	}
+	if opts.ConnectTimeout > 0 {
+		clientOpts.SetConnectTimeout(opts.ConnectTimeout)
+	}
+	if opts.SocketTimeout > 0 {
+		clientOpts.SetSocketTimeout(opts.SocketTimeout)
+	}
+	if opts.ReplicaSet != "" {
+		clientOpts.SetReplicaSet(opts.ReplicaSet)
+	}
+	if opts.ReadPreference != "" {
+		pref, err := readPreference(opts)
+		if err != nil {
+			return nil, err
+		}
+		clientOpts.SetReadPreference(pref)
+	}
+	return clientOpts, nil
+}
+
+// rotatableClientOptions builds driver options for uri, authenticating through rotating
+// credentials when opts.CredentialRefresh is set
+func rotatableClientOptions(uri string, opts config.MongoOptions) (*options.ClientOptions, *rotatingCredentials, error) {
+	clientOpts, err := clientOptions(uri, opts)
+	if err != nil || opts.CredentialRefresh == 0 {
+		return clientOpts, nil, err
+	}
+	creds, err := enableRotation(clientOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return clientOpts, creds, nil
+}
+
+// readPreference parses the configured read preference, defaulting to primary
+func readPreference(opts config.MongoOptions) (*readpref.ReadPref, error) {
+	if opts.ReadPreference == "" {
+		return readpref.Primary(), nil
+	}
+	mode, err := readpref.ModeFromString(opts.ReadPreference)
+	if err != nil {
+		return nil, fmt.Errorf("invalid read preference %q: %v", opts.ReadPreference, err)
+	}
+	return readpref.New(mode)
+}
@@ -0,0 +1,178 @@
+// FILE: lib/database/rotation.go
+// Picking up rotated database credentials without restarting. Every service holds collections of
+// one long-lived client, so instead of replacing the client, the credentials its new connections
+// authenticate with are replaced. Connections already open stay authenticated until they are
+// closed; the pool replaces them with connections using the new credentials.
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"wise-owl/lib/config"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/auth"
+)
+
+const (
+	// rotatingMechanism is registered with the driver to authenticate through rotatingCredentials.
+	// It never reaches the server, which sees the mechanism of the credentials in use.
+	rotatingMechanism = "WISE-OWL-ROTATING"
+	// rotationIDProp carries the ID of a client's rotatingCredentials to the driver's factory
+	rotationIDProp = "WISE_OWL_ROTATION_ID"
+)
+
+var (
+	// rotations holds every client's rotatingCredentials by ID
+	rotations  sync.Map
+	rotationID atomic.Uint64
+)
+
+func init() {
+	auth.RegisterAuthenticatorFactory(rotatingMechanism, func(cred *auth.Cred, _ *http.Client) (auth.Authenticator, error) {
+		creds, ok := rotations.Load(cred.Props[rotationIDProp])
+		if !ok {
+			return nil, fmt.Errorf("no rotating credentials with ID %q", cred.Props[rotationIDProp])
+		}
+		return creds.(*rotatingCredentials), nil
+	})
+}
+
+// rotatingCredentials authenticates new connections with whichever credentials were set last.
+// It authenticates with the mechanism of the connection string, or SCRAM-SHA-1 when none is
+// given, since the mechanisms a server supports are only negotiated for the driver's own.
+type rotatingCredentials struct {
+	id        string
+	mechanism string
+
+	mu      sync.RWMutex
+	current auth.Authenticator
+}
+
+// enableRotation makes the client of clientOpts authenticate through rotating credentials,
+// starting with those of its connection string. It returns nil when there are none to rotate.
+func enableRotation(clientOpts *options.ClientOptions) (*rotatingCredentials, error) {
+	if clientOpts.Auth == nil || clientOpts.Auth.Username == "" {
+		return nil, nil
+	}
+	creds := &rotatingCredentials{
+		id:        strconv.FormatUint(rotationID.Add(1), 10),
+		mechanism: clientOpts.Auth.AuthMechanism,
+	}
+	if err := creds.set(*clientOpts.Auth); err != nil {
+		return nil, err
+	}
+	rotations.Store(creds.id, creds)
+
+	clientOpts.SetAuth(options.Credential{
+		AuthMechanism:           rotatingMechanism,
+		AuthMechanismProperties: map[string]string{rotationIDProp: creds.id},
+		AuthSource:              clientOpts.Auth.AuthSource,
+		Username:                clientOpts.Auth.Username,
+	})
+	return creds, nil
+}
+
+// set replaces the credentials new connections authenticate with.
+func (r *rotatingCredentials) set(cred options.Credential) error {
+	authenticator, err := auth.CreateAuthenticator(r.mechanism, &auth.Cred{
+		Source:      cred.AuthSource,
+		Username:    cred.Username,
+		Password:    cred.Password,
+		PasswordSet: cred.PasswordSet,
+		Props:       cred.AuthMechanismProperties,
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current = authenticator
+	return nil
+}
+
+// authenticator returns the authenticator of the current credentials
+func (r *rotatingCredentials) authenticator() auth.Authenticator {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current
+}
+
+// Auth authenticates a new connection with the current credentials.
+func (r *rotatingCredentials) Auth(ctx context.Context, cfg *auth.Config) error {
+	return r.authenticator().Auth(ctx, cfg)
+}
+
+// Reauth reauthenticates a connection with the current credentials.
+func (r *rotatingCredentials) Reauth(ctx context.Context, cfg *auth.Config) error {
+	return r.authenticator().Reauth(ctx, cfg)
+}
+
+// close forgets the credentials once their client is disconnected
+func (r *rotatingCredentials) close() {
+	rotations.Delete(r.id)
+}
+
+// RotateCredentials switches the connection pool to the credentials in uri, after checking that a
+// new connection can authenticate with them. Connections already open are kept. It needs
+// Options.CredentialRefresh to have been set when the database was connected.
+func (mdb *MongoDatabase) RotateCredentials(uri string) error {
+	if mdb.credentials == nil {
+		return fmt.Errorf("credential rotation is not enabled for this database")
+	}
+	clientOpts, err := clientOptions(uri, mdb.Options)
+	if err != nil {
+		return err
+	}
+	if clientOpts.Auth == nil || clientOpts.Auth.Username == "" {
+		return fmt.Errorf("the new connection string has no credentials")
+	}
+
+	// A short-lived client proves the credentials work before the pool depends on them
+	connect := connectMongo
+	if mdb.documentDB {
+		connect = connectDocumentDB
+	}
+	verifyOpts := mdb.Options
+	verifyOpts.CredentialRefresh = 0
+	client, _, err := connect(uri, verifyOpts)
+	if err != nil {
+		return fmt.Errorf("new credentials rejected: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), withDefaults(mdb.Options, mongoDefaults).ConnectTimeout)
+	defer cancel()
+	client.Disconnect(ctx)
+
+	if err := mdb.credentials.set(*clientOpts.Auth); err != nil {
+		return err
+	}
+	log.Printf("AUDIT: Database credentials rotated; new connections authenticate as %s", clientOpts.Auth.Username)
+	return nil
+}
+
+// watchCredentials applies rotations of the MONGODB_URI in the environment's Secrets Manager
+// secret, checking every Options.CredentialRefresh and on SIGHUP, until the database is closed.
+func (mdb *MongoDatabase) watchCredentials(uri string) {
+	if mdb.credentials == nil {
+		log.Println("WARN: MONGODB_CREDENTIAL_REFRESH is set but the connection string has no credentials to rotate")
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	mdb.stopWatching = cancel
+
+	secretName := config.GetSecretName()
+	log.Printf("Checking %s for rotated database credentials every %s and on SIGHUP", secretName, mdb.Options.CredentialRefresh)
+	go func() {
+		if err := config.WatchSecret(ctx, secretName, "MONGODB_URI", uri, mdb.Options.CredentialRefresh, mdb.RotateCredentials); err != nil {
+			log.Printf("WARN: Database credential rotation disabled: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,433 @@
+// FILE: lib/database/tenant.go
+// Guardrail against cross-user data leaks: queries on collections holding one document set per user
+// must name the user. The command monitor logs every query that does not; collections wrapped in a
+// UserScopedCollection reject them when MONGODB_TENANT_FILTER is "enforce".
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"wise-owl/lib/apierror"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TenantFilterMode is how unscoped queries on user-scoped collections are handled
+type TenantFilterMode string
+
+const (
+	TenantFilterOff     TenantFilterMode = "off"
+	TenantFilterLog     TenantFilterMode = "log"     // Log them (the default)
+	TenantFilterEnforce TenantFilterMode = "enforce" // Also reject them in UserScopedCollections
+)
+
+// unscopedLogInterval is how often the same unscoped query is logged again
+const unscopedLogInterval = time.Minute
+
+// ErrUnscopedQuery is returned by a UserScopedCollection for a filter that does not name a user
+var ErrUnscopedQuery = errors.New("query on a user-scoped collection does not filter by user")
+
+// userScopedCollections maps each collection holding per-user documents to the fields that
+// identify the user; a filter must pin at least one of them
+var userScopedCollections = map[string][]string{
+	"users":              {"_id", "auth0_ids", "auth0_id"},
+	"incorrect_words":    {"user_id"},
+	"answer_stats":       {"user_id"},
+	"mastery":            {"user_id"}, // SRS review state
+	"favorites":          {"user_id"},
+	"lesson_completions": {"user_id"},
+}
+
+var (
+	// enforceTenantFilter is set when the database was connected with TenantFilterEnforce
+	enforceTenantFilter atomic.Bool
+	// unscopedLogged holds when each unscoped query was last logged, so a hot path logs once a minute
+	unscopedLogged sync.Map // string -> time.Time
+)
+
+// crossUserKey marks a context whose queries deliberately span users
+type crossUserKey struct{}
+
+// CrossUser returns a context whose queries may read or change many users' documents without
+// being logged or rejected, for admin tools, migrations, and aggregates over everyone.
+func CrossUser(ctx context.Context) context.Context {
+	return context.WithValue(ctx, crossUserKey{}, true)
+}
+
+// isCrossUser reports whether ctx was marked by CrossUser
+func isCrossUser(ctx context.Context) bool {
+	crossUser, _ := ctx.Value(crossUserKey{}).(bool)
+	return crossUser
+}
+
+// tenantFilterMode parses MONGODB_TENANT_FILTER, defaulting to TenantFilterLog
+func tenantFilterMode(value string) TenantFilterMode {
+	switch mode := TenantFilterMode(strings.ToLower(value)); mode {
+	case TenantFilterOff, TenantFilterLog, TenantFilterEnforce:
+		return mode
+	case "":
+		return TenantFilterLog
+	default:
+		log.Printf("WARN: Unknown MONGODB_TENANT_FILTER %q; logging unscoped queries", value)
+		return TenantFilterLog
+	}
+}
+
+// UserScopedCollection wraps a user-scoped collection so that, in enforce mode, a read, update, or
+// delete whose filter does not pin the user fails with ErrUnscopedQuery before reaching the database.
+// Aggregations must start with such a $match. Inserts are not checked.
+type UserScopedCollection struct {
+	CollectionInterface
+	name   string
+	fields []string
+}
+
+// Ensure UserScopedCollection implements CollectionInterface
+var _ CollectionInterface = (*UserScopedCollection)(nil)
+
+// NewUserScopedCollection wraps collection, which must be one of the user-scoped collections.
+func NewUserScopedCollection(collection *mongo.Collection) *UserScopedCollection {
+	fields, ok := userScopedCollections[collection.Name()]
+	if !ok {
+		panic(fmt.Sprintf("database: %s is not a user-scoped collection", collection.Name()))
+	}
+	return &UserScopedCollection{CollectionInterface: collection, name: collection.Name(), fields: fields}
+}
+
+// Find finds documents once filter is checked
+func (sc *UserScopedCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	if err := sc.check(ctx, "find", filter); err != nil {
+		return nil, err
+	}
+	return sc.CollectionInterface.Find(ctx, filter, opts...)
+}
+
+// FindOne finds a document once filter is checked
+func (sc *UserScopedCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	if err := sc.check(ctx, "findOne", filter); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return sc.CollectionInterface.FindOne(ctx, filter, opts...)
+}
+
+// UpdateOne updates a document once filter is checked
+func (sc *UserScopedCollection) UpdateOne(ctx context.Context, filter, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	if err := sc.check(ctx, "updateOne", filter); err != nil {
+		return nil, err
+	}
+	return sc.CollectionInterface.UpdateOne(ctx, filter, update, opts...)
+}
+
+// DeleteOne deletes a document once filter is checked
+func (sc *UserScopedCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	if err := sc.check(ctx, "deleteOne", filter); err != nil {
+		return nil, err
+	}
+	return sc.CollectionInterface.DeleteOne(ctx, filter, opts...)
+}
+
+// FindOneAndUpdate updates a document and returns it once filter is checked
+func (sc *UserScopedCollection) FindOneAndUpdate(ctx context.Context, filter, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	if err := sc.check(ctx, "findOneAndUpdate", filter); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return sc.CollectionInterface.FindOneAndUpdate(ctx, filter, update, opts...)
+}
+
+// FindOneAndDelete deletes a document and returns it once filter is checked
+func (sc *UserScopedCollection) FindOneAndDelete(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) *mongo.SingleResult {
+	if err := sc.check(ctx, "findOneAndDelete", filter); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return sc.CollectionInterface.FindOneAndDelete(ctx, filter, opts...)
+}
+
+// CountDocuments counts documents once filter is checked
+func (sc *UserScopedCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	if err := sc.check(ctx, "count", filter); err != nil {
+		return 0, err
+	}
+	return sc.CollectionInterface.CountDocuments(ctx, filter, opts...)
+}
+
+// Aggregate runs pipeline once its leading $match is checked
+func (sc *UserScopedCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	if err := sc.check(ctx, "aggregate", firstMatch(pipeline)); err != nil {
+		return nil, err
+	}
+	return sc.CollectionInterface.Aggregate(ctx, pipeline, opts...)
+}
+
+// UpdateMany updates documents once filter is checked
+func (sc *UserScopedCollection) UpdateMany(ctx context.Context, filter, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	if err := sc.check(ctx, "updateMany", filter); err != nil {
+		return nil, err
+	}
+	return sc.CollectionInterface.UpdateMany(ctx, filter, update, opts...)
+}
+
+// DeleteMany deletes documents once filter is checked
+func (sc *UserScopedCollection) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	if err := sc.check(ctx, "deleteMany", filter); err != nil {
+		return nil, err
+	}
+	return sc.CollectionInterface.DeleteMany(ctx, filter, opts...)
+}
+
+// BulkWrite writes models once the filter of every update, replace, and delete is checked
+func (sc *UserScopedCollection) BulkWrite(ctx context.Context, models []mongo.WriteModel, opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
+	for _, model := range models {
+		var filter interface{}
+		switch m := model.(type) {
+		case *mongo.InsertOneModel:
+			continue
+		case *mongo.UpdateOneModel:
+			filter = m.Filter
+		case *mongo.UpdateManyModel:
+			filter = m.Filter
+		case *mongo.ReplaceOneModel:
+			filter = m.Filter
+		case *mongo.DeleteOneModel:
+			filter = m.Filter
+		case *mongo.DeleteManyModel:
+			filter = m.Filter
+		}
+		if err := sc.check(ctx, "bulkWrite", filter); err != nil {
+			return nil, err
+		}
+	}
+	return sc.CollectionInterface.BulkWrite(ctx, models, opts...)
+}
+
+// check returns ErrUnscopedQuery when enforcing and filter does not pin the user. Outside enforce
+// mode the command monitor logs the query instead.
+func (sc *UserScopedCollection) check(ctx context.Context, op string, filter interface{}) error {
+	if !enforceTenantFilter.Load() || isCrossUser(ctx) {
+		return nil
+	}
+	if userScoped(encodeFilter(filter), sc.fields) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s on %s", ErrUnscopedQuery, op, sc.name)
+}
+
+// encodeFilter encodes a filter given to the driver, or returns nil when it is not a document
+func encodeFilter(filter interface{}) bson.Raw {
+	if filter == nil {
+		return nil
+	}
+	kind, data, err := bson.MarshalValue(filter)
+	if err != nil {
+		return nil
+	}
+	doc, _ := bson.RawValue{Type: kind, Value: data}.DocumentOK()
+	return doc
+}
+
+// firstMatch returns the $match of a pipeline's first stage, or nil when it starts otherwise
+func firstMatch(pipeline interface{}) bson.Raw {
+	kind, data, err := bson.MarshalValue(pipeline)
+	if err != nil {
+		return nil
+	}
+	return leadingMatch(bson.RawValue{Type: kind, Value: data})
+}
+
+// leadingMatch returns the $match of the first stage of an encoded pipeline
+func leadingMatch(pipeline bson.RawValue) bson.Raw {
+	stages, ok := pipeline.ArrayOK()
+	if !ok {
+		return nil
+	}
+	first, err := stages.IndexErr(0)
+	if err != nil {
+		return nil
+	}
+	stage, ok := first.Value().DocumentOK()
+	if !ok {
+		return nil
+	}
+	match, _ := stage.Lookup("$match").DocumentOK()
+	return match
+}
+
+// userScoped reports whether filter pins one of fields to a value or list of values, directly, in
+// any clause of an $and, or in every clause of an $or
+func userScoped(filter bson.Raw, fields []string) bool {
+	elements, err := filter.Elements()
+	if err != nil {
+		return false
+	}
+	for _, element := range elements {
+		key, value := element.Key(), element.Value()
+		switch key {
+		case "$and":
+			if clauses := documents(value); anyScoped(clauses, fields) {
+				return true
+			}
+		case "$or":
+			if clauses := documents(value); len(clauses) > 0 && allScoped(clauses, fields) {
+				return true
+			}
+		default:
+			if contains(fields, key) && pinsValue(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pinsValue reports whether a field's condition matches only given values: an equality, $eq, or
+// $in. Conditions such as $ne, $nin, $exists, and regular expressions match other users too.
+func pinsValue(v bson.RawValue) bool {
+	switch v.Type {
+	case bson.TypeNull, bson.TypeUndefined, bson.TypeRegex:
+		return false
+	case bson.TypeEmbeddedDocument:
+		elements, err := v.Document().Elements()
+		if err != nil {
+			return false
+		}
+		if len(elements) == 0 || !strings.HasPrefix(elements[0].Key(), "$") {
+			// An exact match on a whole document
+			return true
+		}
+		for _, element := range elements {
+			switch element.Key() {
+			case "$eq":
+				if pinsValue(element.Value()) {
+					return true
+				}
+			case "$in":
+				if element.Value().Type == bson.TypeArray {
+					return true
+				}
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// anyScoped reports whether one of clauses is scoped
+func anyScoped(clauses []bson.Raw, fields []string) bool {
+	for _, clause := range clauses {
+		if userScoped(clause, fields) {
+			return true
+		}
+	}
+	return false
+}
+
+// allScoped reports whether every one of clauses is scoped
+func allScoped(clauses []bson.Raw, fields []string) bool {
+	for _, clause := range clauses {
+		if !userScoped(clause, fields) {
+			return false
+		}
+	}
+	return true
+}
+
+// documents returns the documents of an array value
+func documents(v bson.RawValue) []bson.Raw {
+	array, ok := v.ArrayOK()
+	if !ok {
+		return nil
+	}
+	values, err := array.Values()
+	if err != nil {
+		return nil
+	}
+	docs := make([]bson.Raw, 0, len(values))
+	for _, value := range values {
+		if doc, ok := value.DocumentOK(); ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs
+}
+
+// contains reports whether fields includes key
+func contains(fields []string, key string) bool {
+	for _, field := range fields {
+		if field == key {
+			return true
+		}
+	}
+	return false
+}
+
+// auditTenantFilter logs a command on a user-scoped collection that reads, updates, or deletes
+// documents without pinning the user, unless its context was marked by CrossUser
+func auditTenantFilter(ctx context.Context, e *event.CommandStartedEvent) {
+	collection, ok := e.Command.Lookup(e.CommandName).StringValueOK()
+	if !ok {
+		return
+	}
+	fields, ok := userScopedCollections[collection]
+	if !ok || isCrossUser(ctx) {
+		return
+	}
+	filters, ok := commandFilters(e.CommandName, e.Command)
+	if !ok {
+		return
+	}
+	for _, filter := range filters {
+		if userScoped(filter, fields) {
+			continue
+		}
+		redacted := redactCommand(e.Command)
+		key := e.DatabaseName + "|" + redacted
+		now := time.Now()
+		if last, seen := unscopedLogged.Load(key); seen && now.Sub(last.(time.Time)) < unscopedLogInterval {
+			return
+		}
+		unscopedLogged.Store(key, now)
+
+		prefix := "WARN: "
+		if traceID := apierror.TraceIDFromContext(ctx); traceID != "" {
+			prefix += "[" + traceID + "] "
+		}
+		log.Printf("%sMongoDB %s on %s.%s does not filter by %s: %s",
+			prefix, e.CommandName, e.DatabaseName, collection, strings.Join(fields, " or "), redacted)
+		return
+	}
+}
+
+// commandFilters returns the filters of a command that reads, updates, or deletes documents, and
+// false for any other command. A missing filter is returned as nil, which matches everything.
+func commandFilters(name string, command bson.Raw) ([]bson.Raw, bool) {
+	switch name {
+	case "find":
+		filter, _ := command.Lookup("filter").DocumentOK()
+		return []bson.Raw{filter}, true
+	case "count", "distinct", "findAndModify":
+		filter, _ := command.Lookup("query").DocumentOK()
+		return []bson.Raw{filter}, true
+	case "aggregate":
+		return []bson.Raw{leadingMatch(command.Lookup("pipeline"))}, true
+	case "update", "delete":
+		statements := documents(command.Lookup(name + "s"))
+		filters := make([]bson.Raw, 0, len(statements))
+		for _, statement := range statements {
+			filter, _ := statement.Lookup("q").DocumentOK()
+			filters = append(filters, filter)
+		}
+		return filters, true
+	}
+	return nil, false
+}
@@ -0,0 +1,26 @@
+// FILE: lib/database/unique.go
+// Insert helpers that rely on unique indexes instead of check-then-insert races
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrDuplicate is returned when an insert collides with a unique index
+var ErrDuplicate = errors.New("document already exists")
+
+// InsertUnique inserts document and lets a unique index decide whether it already exists.
+// A collision returns an error wrapping ErrDuplicate, so callers can answer 409 or treat a
+// redelivery as done without checking for the document first.
+func InsertUnique(ctx context.Context, collection CollectionInterface, document interface{}) error {
+	_, err := collection.InsertOne(ctx, document)
+	if err != nil && mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %v", ErrDuplicate, err)
+	}
+	return err
+}
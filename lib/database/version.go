@@ -0,0 +1,31 @@
+// FILE: lib/database/version.go
+// Optimistic concurrency: versioned documents count their writes, and an update that names the
+// version it was based on applies only if no other write got there first
+
+package database
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// BumpVersion adds the version increment to update, which every write of a versioned document must carry.
+func BumpVersion(update bson.M) bson.M {
+	inc, _ := update["$inc"].(bson.M)
+	if inc == nil {
+		inc = bson.M{}
+		update["$inc"] = inc
+	}
+	inc["version"] = 1
+	return update
+}
+
+// MatchVersion narrows filter to documents still at version. Documents written before they were
+// versioned have no version field and count as version 0.
+func MatchVersion(filter bson.M, version int64) bson.M {
+	if version == 0 {
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	} else {
+		filter["version"] = version
+	}
+	return filter
+}
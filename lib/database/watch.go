@@ -0,0 +1,94 @@
+// FILE: lib/database/watch.go
+// Cross-process cache invalidation: a change stream reports writes made by any process, so caches
+// of a collection are cleared within moments instead of when their entries expire
+
+package database
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Retry delays after a change stream fails to open or breaks
+const (
+	watchRetryMin = 5 * time.Second
+	watchRetryMax = 5 * time.Minute
+)
+
+// changeEvent is the part of a change event the watcher reads
+type changeEvent struct {
+	NS struct {
+		Coll string `bson:"coll"`
+	} `bson:"ns"`
+}
+
+// WatchChanges calls onChange[name] whenever a document of collection name changes, whoever made
+// the change, until ctx is cancelled. It is meant for clearing caches such as a CachedCollection's
+// Invalidate, so every function is also called when the stream (re)opens, as changes made while
+// it was down are unknown; events that do not name a collection call all of them.
+//
+// Change streams need a replica set, and DocumentDB must have them enabled for the collections.
+// Where they are unavailable the failure is logged, opening is retried with backoff, and caches
+// are left to expire by their TTL.
+func WatchChanges(ctx context.Context, db *mongo.Database, onChange map[string]func()) {
+	names := make([]string, 0, len(onChange))
+	for name := range onChange {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	all := func() {
+		for _, name := range names {
+			onChange[name]()
+		}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"ns.coll": bson.M{"$in": names}},
+			bson.M{"operationType": bson.M{"$in": bson.A{"dropDatabase", "invalidate"}}},
+		}}}},
+		{{Key: "$project", Value: bson.M{"ns": 1, "operationType": 1}}},
+	}
+
+	retry := watchRetryMin
+	for {
+		stream, err := db.Watch(ctx, pipeline, options.ChangeStream())
+		if err == nil {
+			// Opened before clearing, so no change falls between the two unseen
+			all()
+			retry = watchRetryMin
+			log.Printf("Watching %s %v for changes", db.Name(), names)
+			for stream.Next(ctx) {
+				var event changeEvent
+				if err := stream.Decode(&event); err != nil || event.NS.Coll == "" {
+					all()
+					continue
+				}
+				if invalidate, ok := onChange[event.NS.Coll]; ok {
+					invalidate()
+				}
+			}
+			err = stream.Err()
+			stream.Close(context.Background())
+			if err == nil && ctx.Err() == nil {
+				continue // Invalidated by a drop or rename; reopen straight away
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("WARN: Change stream on %s unavailable, caches expire by TTL until it reopens (retrying in %s): %v", db.Name(), retry, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+		retry = min(retry*2, watchRetryMax)
+	}
+}
@@ -0,0 +1,175 @@
+// FILE: lib/events/events.go
+// This package provides a lightweight domain event bus shared by all services.
+// Events are delivered over HTTP to every configured subscriber endpoint.
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"wise-owl/lib/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Well-known event types published by the services.
+const (
+	TypeQuizAnswer   = "quiz.answer"
+	TypeSRSReview    = "srs.review"
+	TypeLessonViewed = "lesson.viewed"
+
+	// Raised by the users service for its webhooks
+	TypeLessonCompleted = "lesson.completed"
+	TypeStreakMilestone = "streak.milestone"
+
+	// Raised by the quiz service for the notifications service, which reminds the student
+	TypeAssignmentDue = "assignment.due_soon"
+)
+
+// Event is a single domain event. ID is unique per event so consumers can deduplicate retries.
+type Event struct {
+	ID         string                 `json:"id" binding:"required"`
+	Type       string                 `json:"type" binding:"required"`
+	UserID     string                 `json:"user_id"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+}
+
+// New creates an event with a random ID and the current time
+func New(eventType, userID string, data map[string]interface{}) Event {
+	return Event{
+		ID:         newID(),
+		Type:       eventType,
+		UserID:     userID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+// Publisher delivers events to interested services
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// HTTPPublisher POSTs each event as JSON to every subscriber URL
+type HTTPPublisher struct {
+	subscribers []string
+	client      *http.Client
+}
+
+// NewHTTPPublisher creates a publisher for the given subscriber URLs
+func NewHTTPPublisher(subscribers []string) *HTTPPublisher {
+	return &HTTPPublisher{
+		subscribers: subscribers,
+		client:      &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Publish sends the event to all subscribers and returns the combined delivery errors
+func (p *HTTPPublisher) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %s: %v", event.ID, err)
+	}
+
+	var errs []error
+	for _, url := range p.subscribers {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", url, err))
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", url, err))
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			errs = append(errs, fmt.Errorf("%s: unexpected status %s", url, resp.Status))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// NoopPublisher discards events, used when no subscribers are configured
+type NoopPublisher struct{}
+
+// Publish does nothing
+func (NoopPublisher) Publish(ctx context.Context, event Event) error {
+	return nil
+}
+
+// NewPublisherFromEnv builds a publisher from the comma-separated EVENT_SUBSCRIBERS variable
+func NewPublisherFromEnv() Publisher {
+	var subscribers []string
+	for _, url := range strings.Split(os.Getenv("EVENT_SUBSCRIBERS"), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			subscribers = append(subscribers, url)
+		}
+	}
+
+	if len(subscribers) == 0 {
+		log.Println("No EVENT_SUBSCRIBERS configured, domain events will be discarded")
+		return NoopPublisher{}
+	}
+
+	log.Printf("Publishing domain events to %d subscribers", len(subscribers))
+	return NewHTTPPublisher(subscribers)
+}
+
+// PublishAsync delivers an event in the background so request handlers don't wait on subscribers
+func PublishAsync(p Publisher, event Event) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := p.Publish(ctx, event); err != nil {
+			log.Printf("WARN: Failed to publish %s event %s: %v", event.Type, event.ID, err)
+		}
+	}()
+}
+
+// Handler returns a Gin handler that decodes an incoming event and passes it to fn
+func Handler(fn func(ctx context.Context, event Event) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var event Event
+		if err := c.ShouldBindJSON(&event); err != nil {
+			c.Error(apierror.BadRequest("invalid_event", "The event is invalid.").WithDetails(err.Error()))
+			return
+		}
+		if event.OccurredAt.IsZero() {
+			event.OccurredAt = time.Now().UTC()
+		}
+
+		if err := fn(c.Request.Context(), event); err != nil {
+			c.Error(apierror.Internal("event_handling_failed", fmt.Errorf("handling %s event %s: %v", event.Type, event.ID, err)))
+			return
+		}
+
+		c.Status(http.StatusAccepted)
+	}
+}
+
+// newID returns a random 128-bit hex identifier
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
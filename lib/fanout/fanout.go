@@ -0,0 +1,62 @@
+// FILE: lib/fanout/fanout.go
+// This package runs independent dependency calls at the same time, each with its own budget, and
+// collects how every one of them ended, so a caller can serve what succeeded.
+
+package fanout
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Call is one call made by Run.
+type Call struct {
+	Name string
+	// Timeout is the call's own budget; zero leaves it only the deadline of Run's context
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// Result is how one call ended.
+type Result struct {
+	Name    string
+	Err     error
+	Latency time.Duration
+}
+
+// Run makes the calls concurrently and waits for all of them. Each call gets a context that ends
+// after its Timeout or when ctx does, whichever comes first. A failing call does not cancel the
+// others; its error is kept in its Result. The results are in the order of calls.
+func Run(ctx context.Context, calls ...Call) []Result {
+	results := make([]Result, len(calls))
+	var group errgroup.Group
+	for i, call := range calls {
+		group.Go(func() error {
+			callCtx, cancel := ctx, context.CancelFunc(func() {})
+			if call.Timeout > 0 {
+				callCtx, cancel = context.WithTimeout(ctx, call.Timeout)
+			}
+			defer cancel()
+
+			start := time.Now()
+			err := call.Run(callCtx)
+			results[i] = Result{Name: call.Name, Err: err, Latency: time.Since(start)}
+			return nil
+		})
+	}
+	group.Wait()
+	return results
+}
+
+// Failed returns the names of the calls that failed, in order.
+func Failed(results []Result) []string {
+	names := []string{}
+	for _, result := range results {
+		if result.Err != nil {
+			names = append(names, result.Name)
+		}
+	}
+	return names
+}
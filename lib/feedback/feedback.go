@@ -0,0 +1,208 @@
+// FILE: lib/feedback/feedback.go
+// This package keeps what users report from the app: feedback about the app itself, and errors
+// they spot in its content, such as a wrong Burmese translation or a broken audio clip. The users
+// service takes reports and the admin service triages them.
+
+package feedback
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"wise-owl/lib/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Collection holds the reports, in the users service's database
+	Collection = "feedback"
+	// MaxPerDay caps the reports a user can send in 24 hours
+	MaxPerDay = 20
+)
+
+// Report categories. Translation and audio reports are about a word and usually name it.
+const (
+	CategoryTranslation = "translation"
+	CategoryAudio       = "audio"
+	CategoryContent     = "content" // Any other error in a word or lesson
+	CategoryBug         = "bug"
+	CategorySuggestion  = "suggestion"
+	CategoryOther       = "other"
+)
+
+// Triage statuses.
+const (
+	StatusOpen       = "open"        // Not looked at yet
+	StatusInProgress = "in_progress" // Being fixed
+	StatusResolved   = "resolved"    // Fixed
+	StatusDismissed  = "dismissed"   // Not an error, or not acted on
+)
+
+// Indexes lists the indexes the feedback collection relies on.
+var Indexes = []database.Index{
+	// Triage lists reports by status, newest first, optionally of one category or word
+	{Collection: Collection, Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+	{Collection: Collection, Keys: bson.D{{Key: "vocabulary_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	// The daily cap counts a user's latest reports
+	{Collection: Collection, Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	// Deciding a correction settles the reports linked to it
+	{Collection: Collection, Keys: bson.D{{Key: "review_id", Value: 1}}},
+}
+
+var (
+	// ErrLimit is returned when a user already sent MaxPerDay reports in the last 24 hours
+	ErrLimit = errors.New("feedback limit reached")
+	// ErrNotAbout is returned when linking reports that are missing, closed, or about another word
+	ErrNotAbout = errors.New("reports are not open reports about this word")
+)
+
+// Feedback is one report from a user.
+type Feedback struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID       string             `bson:"user_id" json:"user_id"` // Auth0 ID
+	Category     string             `bson:"category" json:"category"`
+	Message      string             `bson:"message" json:"message"`
+	VocabularyID string             `bson:"vocabulary_id,omitempty" json:"vocabulary_id,omitempty"` // The word reported, if any
+	UserAgent    string             `bson:"user_agent,omitempty" json:"user_agent,omitempty"`       // The reporting client, for bug reports
+	Status       string             `bson:"status" json:"status"`
+	Note         string             `bson:"note,omitempty" json:"note,omitempty"`           // Left by the admin who triaged it
+	ReviewID     string             `bson:"review_id,omitempty" json:"review_id,omitempty"` // The correction proposed for it in the admin review queue
+	Revision     int64              `bson:"revision,omitempty" json:"revision,omitempty"`   // The word's revision that fixed it
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Filter narrows a listing of reports; empty fields match every report.
+type Filter struct {
+	Status       string
+	Category     string
+	VocabularyID string
+}
+
+// Store records and triages reports.
+type Store struct {
+	collection *mongo.Collection
+}
+
+// NewStore creates a store over the users service's database.
+func NewStore(db *mongo.Database) *Store {
+	return &Store{collection: db.Collection(Collection)}
+}
+
+// Create records a new open report and returns it.
+func (s *Store) Create(ctx context.Context, report Feedback) (Feedback, error) {
+	now := time.Now().UTC()
+	count, err := s.collection.CountDocuments(ctx, bson.M{"user_id": report.UserID, "created_at": bson.M{"$gt": now.Add(-24 * time.Hour)}})
+	if err != nil {
+		return Feedback{}, err
+	}
+	if count >= MaxPerDay {
+		return Feedback{}, ErrLimit
+	}
+
+	report.ID = primitive.NewObjectID()
+	report.Status = StatusOpen
+	report.CreatedAt = now
+	report.UpdatedAt = now
+	if _, err := s.collection.InsertOne(ctx, report); err != nil {
+		return Feedback{}, err
+	}
+	return report, nil
+}
+
+// List returns up to limit reports matching filter after skipping offset, newest first, and how
+// many match in total.
+func (s *Store) List(ctx context.Context, filter Filter, limit, offset int) ([]Feedback, int64, error) {
+	query := bson.M{}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.Category != "" {
+		query["category"] = filter.Category
+	}
+	if filter.VocabularyID != "" {
+		query["vocabulary_id"] = filter.VocabularyID
+	}
+
+	total, err := s.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	reports, err := database.FindAll[Feedback](ctx, s.collection, query, opts)
+	return reports, total, err
+}
+
+// Triage sets a report's status and, when note is not nil, its note, and returns the report
+// afterwards. It returns mongo.ErrNoDocuments when there is no such report.
+func (s *Store) Triage(ctx context.Context, id primitive.ObjectID, status string, note *string) (Feedback, error) {
+	set := bson.M{"status": status, "updated_at": time.Now().UTC()}
+	if note != nil {
+		set["note"] = *note
+	}
+	var report Feedback
+	err := s.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&report)
+	return report, err
+}
+
+// WordCount is how many open reports there are about one word.
+type WordCount struct {
+	VocabularyID string    `bson:"_id" json:"vocabulary_id"`
+	Reports      int64     `bson:"reports" json:"reports"`
+	LatestAt     time.Time `bson:"latest_at" json:"latest_at"`
+}
+
+// Words returns the words with open reports, the most reported first.
+func (s *Store) Words(ctx context.Context, limit int) ([]WordCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": StatusOpen, "vocabulary_id": bson.M{"$exists": true, "$ne": ""}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$vocabulary_id", "reports": bson.M{"$sum": 1}, "latest_at": bson.M{"$max": "$created_at"}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "reports", Value: -1}, {Key: "latest_at", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+	return database.AggregateAll[WordCount](ctx, s.collection, pipeline)
+}
+
+// Link marks reports as in progress under a correction proposed in the review queue. Every report
+// must be an open or in-progress report about vocabularyID, or none is linked and it returns ErrNotAbout.
+func (s *Store) Link(ctx context.Context, ids []primitive.ObjectID, vocabularyID, reviewID string) error {
+	filter := bson.M{
+		"_id":           bson.M{"$in": ids},
+		"vocabulary_id": vocabularyID,
+		"status":        bson.M{"$in": bson.A{StatusOpen, StatusInProgress}},
+	}
+	count, err := s.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if count != int64(len(ids)) {
+		return ErrNotAbout
+	}
+	_, err = s.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{
+		"status":     StatusInProgress,
+		"review_id":  reviewID,
+		"updated_at": time.Now().UTC(),
+	}})
+	return err
+}
+
+// Settle closes out the reports linked to a correction once it is decided: resolved by revision
+// when it was published, or back to open for another look when it was rejected (revision 0).
+func (s *Store) Settle(ctx context.Context, reviewID string, revision int64, note string) error {
+	now := time.Now().UTC()
+	update := bson.M{"$set": bson.M{"status": StatusOpen, "updated_at": now}, "$unset": bson.M{"review_id": ""}}
+	if revision > 0 {
+		update = bson.M{"$set": bson.M{"status": StatusResolved, "revision": revision, "note": note, "updated_at": now}}
+	}
+	_, err := s.collection.UpdateMany(ctx, bson.M{"review_id": reviewID, "status": StatusInProgress}, update)
+	return err
+}
@@ -0,0 +1,137 @@
+// FILE: lib/flags/flags.go
+// This package reads the feature flags managed through the admin service.
+
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Path is where the admin service serves the current flags to other services
+const Path = "/internal/v1/flags"
+
+const (
+	// defaultRefresh is how long fetched flags are used before a background refresh
+	defaultRefresh = 30 * time.Second
+	// fetchTimeout bounds one refresh
+	fetchTimeout = 5 * time.Second
+)
+
+// Flag is a feature flag as stored and served by the admin service
+type Flag struct {
+	Name        string    `json:"name" bson:"_id"`
+	Enabled     bool      `json:"enabled" bson:"enabled"`
+	Description string    `json:"description,omitempty" bson:"description,omitempty"`
+	UpdatedBy   string    `json:"updated_by,omitempty" bson:"updated_by,omitempty"` // Auth0 ID of the last admin to change it
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Client caches the flags served by the admin service. Lookups never wait on the network: stale flags
+// are refreshed in the background, and a failed refresh keeps the previous values.
+// A nil *Client answers every lookup with its fallback.
+type Client struct {
+	url        string
+	refresh    time.Duration
+	httpClient *http.Client
+
+	mu         sync.Mutex
+	flags      map[string]bool
+	checkedAt  time.Time // Last refresh attempt; failed attempts also wait out the interval
+	refreshing bool
+}
+
+// NewClient creates a client for the admin service at baseURL (e.g. "http://admin-service:8080").
+// A zero refresh uses thirty seconds.
+func NewClient(baseURL string, refresh time.Duration) *Client {
+	if refresh <= 0 {
+		refresh = defaultRefresh
+	}
+	return &Client{
+		url:        strings.TrimSuffix(baseURL, "/") + Path,
+		refresh:    refresh,
+		httpClient: &http.Client{Timeout: fetchTimeout},
+	}
+}
+
+// NewClientFromEnv creates a client for the admin service at ADMIN_SERVICE_URL, or returns nil when it is unset
+func NewClientFromEnv() *Client {
+	url := os.Getenv("ADMIN_SERVICE_URL")
+	if url == "" {
+		log.Println("No ADMIN_SERVICE_URL configured, feature flags will use their defaults")
+		return nil
+	}
+	return NewClient(url, 0)
+}
+
+// Enabled reports whether the named flag is on. Flags that do not exist, or have not been fetched yet,
+// report fallback.
+func (c *Client) Enabled(name string, fallback bool) bool {
+	if c == nil {
+		return fallback
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.checkedAt) > c.refresh && !c.refreshing {
+		c.refreshing = true
+		go c.revalidate()
+	}
+	if enabled, ok := c.flags[name]; ok {
+		return enabled
+	}
+	return fallback
+}
+
+// revalidate refreshes the flags in the background, keeping the current ones on failure
+func (c *Client) revalidate() {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	flags, err := c.fetch(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshing = false
+	c.checkedAt = time.Now()
+	if err != nil {
+		log.Printf("WARN: Failed to refresh feature flags from %s: %v", c.url, err)
+		return
+	}
+	c.flags = flags
+}
+
+func (c *Client) fetch(ctx context.Context) (map[string]bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Flags []Flag `json:"flags"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode flags: %v", err)
+	}
+	flags := make(map[string]bool, len(body.Flags))
+	for _, flag := range body.Flags {
+		flags[flag.Name] = flag.Enabled
+	}
+	return flags, nil
+}
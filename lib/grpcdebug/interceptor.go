@@ -0,0 +1,177 @@
+// FILE: lib/grpcdebug/interceptor.go
+// Opt-in gRPC interceptors that log payload metadata as JSON for debugging
+
+package grpcdebug
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"time"
+
+	"wise-owl/lib/apierror"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// maxMissingIDs caps how many missing IDs a single log entry lists
+const maxMissingIDs = 50
+
+// Entry is the JSON document logged for every call.
+// It only carries counts and identifiers, never message contents.
+type Entry struct {
+	Side           string         `json:"side"`
+	Method         string         `json:"method"`
+	Code           string         `json:"code"`
+	TraceID        string         `json:"trace_id,omitempty"`
+	DurationMs     int64          `json:"duration_ms"`
+	RequestCounts  map[string]int `json:"request_counts,omitempty"`
+	ResponseCounts map[string]int `json:"response_counts,omitempty"`
+	MissingCount   int            `json:"missing_count"`
+	MissingIDs     []string       `json:"missing_ids,omitempty"`
+}
+
+// UnaryServerInterceptor logs metadata for every unary call handled by a server
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logged := resp
+		if err != nil {
+			logged = nil
+		}
+		logCall(ctx, "server", info.FullMethod, req, logged, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// UnaryClientInterceptor logs metadata for every unary call made by a client
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err != nil {
+			// The reply is not populated on failure
+			reply = nil
+		}
+		logCall(ctx, "client", method, req, reply, err, time.Since(start))
+		return err
+	}
+}
+
+// ServerOptions returns the server options that enable debug logging, or none when disabled
+func ServerOptions(enabled bool) []grpc.ServerOption {
+	if !enabled {
+		return nil
+	}
+	log.Println("gRPC debug logging enabled for server calls")
+	return []grpc.ServerOption{grpc.ChainUnaryInterceptor(UnaryServerInterceptor())}
+}
+
+// DialOptions returns the dial options that enable debug logging, or none when disabled
+func DialOptions(enabled bool) []grpc.DialOption {
+	if !enabled {
+		return nil
+	}
+	log.Println("gRPC debug logging enabled for client calls")
+	return []grpc.DialOption{grpc.WithChainUnaryInterceptor(UnaryClientInterceptor())}
+}
+
+// logCall builds and prints the JSON entry for a single call
+func logCall(ctx context.Context, side, method string, req, resp interface{}, err error, duration time.Duration) {
+	entry := Entry{
+		Side:       side,
+		Method:     method,
+		Code:       status.Code(err).String(),
+		TraceID:    apierror.TraceIDFromContext(ctx),
+		DurationMs: duration.Milliseconds(),
+	}
+
+	reqMsg, _ := req.(proto.Message)
+	respMsg, _ := resp.(proto.Message)
+	if reqMsg != nil {
+		entry.RequestCounts = fieldCounts(reqMsg.ProtoReflect())
+	}
+	if respMsg != nil {
+		entry.ResponseCounts = fieldCounts(respMsg.ProtoReflect())
+	}
+	if reqMsg != nil && respMsg != nil {
+		missing := missingIDs(reqMsg.ProtoReflect(), respMsg.ProtoReflect())
+		entry.MissingCount = len(missing)
+		if len(missing) > maxMissingIDs {
+			missing = missing[:maxMissingIDs]
+		}
+		entry.MissingIDs = missing
+	}
+
+	data, marshalErr := json.Marshal(entry)
+	if marshalErr != nil {
+		log.Printf("WARN: Failed to encode gRPC debug entry for %s: %v", method, marshalErr)
+		return
+	}
+	log.Printf("grpc_debug %s", data)
+}
+
+// fieldCounts returns the number of elements in every repeated and map field of a message
+func fieldCounts(msg protoreflect.Message) map[string]int {
+	counts := make(map[string]int)
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		switch {
+		case field.IsMap():
+			counts[string(field.Name())] = msg.Get(field).Map().Len()
+		case field.IsList():
+			counts[string(field.Name())] = msg.Get(field).List().Len()
+		}
+	}
+	return counts
+}
+
+// missingIDs returns the requested IDs that are absent from the response.
+// Requested IDs are the values of repeated string fields in the request; returned IDs
+// are the keys of string-keyed map fields in the response. Batch lookups that share
+// this shape (e.g. GetVocabularyBatch) are diagnosed without per-method code.
+func missingIDs(req, resp protoreflect.Message) []string {
+	returned := make(map[string]bool)
+	hasKeyedResponse := false
+	respFields := resp.Descriptor().Fields()
+	for i := 0; i < respFields.Len(); i++ {
+		field := respFields.Get(i)
+		if !field.IsMap() || field.MapKey().Kind() != protoreflect.StringKind {
+			continue
+		}
+		hasKeyedResponse = true
+		resp.Get(field).Map().Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+			returned[key.String()] = true
+			return true
+		})
+	}
+	if !hasKeyedResponse {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var missing []string
+	reqFields := req.Descriptor().Fields()
+	for i := 0; i < reqFields.Len(); i++ {
+		field := reqFields.Get(i)
+		if !field.IsList() || field.Kind() != protoreflect.StringKind {
+			continue
+		}
+		list := req.Get(field).List()
+		for j := 0; j < list.Len(); j++ {
+			id := list.Get(j).String()
+			if !returned[id] && !seen[id] {
+				seen[id] = true
+				missing = append(missing, id)
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,179 @@
+// FILE: lib/grpcutil/metrics.go
+// Prometheus metrics for gRPC servers, in the text exposition format and with the metric names
+// of go-grpc-prometheus, so existing dashboards work unchanged.
+
+package grpcutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the handling time histogram
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// rpc identifies a method; handledRPC adds the status code it finished with
+type rpc struct{ service, method string }
+
+type handledRPC struct {
+	rpc
+	code string
+}
+
+// histogram is a cumulative-on-export latency histogram
+type histogram struct {
+	buckets []uint64 // Per-bucket counts, not cumulative
+	count   uint64
+	sum     float64
+}
+
+// Metrics counts gRPC calls and their latencies. It is safe for concurrent use.
+type Metrics struct {
+	mu      sync.Mutex
+	started map[rpc]uint64
+	handled map[handledRPC]uint64
+	latency map[rpc]*histogram
+}
+
+// NewMetrics creates an empty set of server metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		started: make(map[rpc]uint64),
+		handled: make(map[handledRPC]uint64),
+		latency: make(map[rpc]*histogram),
+	}
+}
+
+// UnaryServerInterceptor records every unary call handled by a server
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		key := splitMethod(info.FullMethod)
+		m.mu.Lock()
+		m.started[key]++
+		m.mu.Unlock()
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observe(key, status.Code(err).String(), time.Since(start))
+		return resp, err
+	}
+}
+
+// observe records a finished call
+func (m *Metrics) observe(key rpc, code string, elapsed time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.handled[handledRPC{rpc: key, code: code}]++
+	h, ok := m.latency[key]
+	if !ok {
+		h = &histogram{buckets: make([]uint64, len(latencyBuckets))}
+		m.latency[key] = h
+	}
+	seconds := elapsed.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			h.buckets[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// Handler serves the metrics in the Prometheus text exposition format
+func (m *Metrics) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		c.Status(http.StatusOK)
+		m.WriteTo(c.Writer)
+	}
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format, sorted by labels
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP grpc_server_started_total Total number of RPCs started on the server.\n")
+	b.WriteString("# TYPE grpc_server_started_total counter\n")
+	for _, key := range sortedRPCs(m.started) {
+		fmt.Fprintf(&b, "grpc_server_started_total{%s} %d\n", key.labels(), m.started[key])
+	}
+
+	b.WriteString("# HELP grpc_server_handled_total Total number of RPCs completed on the server, regardless of success or failure.\n")
+	b.WriteString("# TYPE grpc_server_handled_total counter\n")
+	handled := make([]handledRPC, 0, len(m.handled))
+	for key := range m.handled {
+		handled = append(handled, key)
+	}
+	sort.Slice(handled, func(i, j int) bool {
+		if handled[i].rpc != handled[j].rpc {
+			return handled[i].rpc.less(handled[j].rpc)
+		}
+		return handled[i].code < handled[j].code
+	})
+	for _, key := range handled {
+		fmt.Fprintf(&b, "grpc_server_handled_total{grpc_code=%q,%s} %d\n", key.code, key.labels(), m.handled[key])
+	}
+
+	b.WriteString("# HELP grpc_server_handling_seconds Histogram of response latency (seconds) of gRPC that had been application-level handled by the server.\n")
+	b.WriteString("# TYPE grpc_server_handling_seconds histogram\n")
+	for _, key := range sortedRPCs(m.latency) {
+		h := m.latency[key]
+		var cumulative uint64
+		for i, bound := range latencyBuckets {
+			cumulative += h.buckets[i]
+			fmt.Fprintf(&b, "grpc_server_handling_seconds_bucket{%s,le=\"%g\"} %d\n", key.labels(), bound, cumulative)
+		}
+		fmt.Fprintf(&b, "grpc_server_handling_seconds_bucket{%s,le=\"+Inf\"} %d\n", key.labels(), h.count)
+		fmt.Fprintf(&b, "grpc_server_handling_seconds_sum{%s} %g\n", key.labels(), h.sum)
+		fmt.Fprintf(&b, "grpc_server_handling_seconds_count{%s} %d\n", key.labels(), h.count)
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// labels renders the method labels shared by every series
+func (r rpc) labels() string {
+	return fmt.Sprintf("grpc_method=%q,grpc_service=%q,grpc_type=\"unary\"", r.method, r.service)
+}
+
+func (r rpc) less(other rpc) bool {
+	if r.service != other.service {
+		return r.service < other.service
+	}
+	return r.method < other.method
+}
+
+// sortedRPCs returns the keys of a per-method map in label order
+func sortedRPCs[V any](m map[rpc]V) []rpc {
+	keys := make([]rpc, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+	return keys
+}
+
+// splitMethod splits "/package.Service/Method" into its service and method
+func splitMethod(fullMethod string) rpc {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return rpc{service: "unknown", method: fullMethod}
+	}
+	return rpc{service: service, method: method}
+}
@@ -0,0 +1,232 @@
+// FILE: lib/grpcutil/resolver.go
+// Client-side service discovery: a gRPC resolver that keeps re-resolving a service's DNS records
+// (AWS Cloud Map, or docker-compose's embedded DNS), so new tasks receive traffic as soon as they register.
+
+package grpcutil
+
+import (
+	"context"
+	"log"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"wise-owl/lib/metrics"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+)
+
+const (
+	// DiscoveryScheme is the target scheme handled by the discovery resolver
+	DiscoveryScheme = "discovery"
+	// DefaultRefreshInterval is how often the discovery resolver re-resolves a target. Cloud Map
+	// records have a short TTL, so this bounds how long a new task waits for its first call.
+	DefaultRefreshInterval = 30 * time.Second
+	// minResolveInterval rate-limits the re-resolution gRPC requests when connections fail
+	minResolveInterval = 5 * time.Second
+	// lookupTimeout bounds one round of DNS queries
+	lookupTimeout = 10 * time.Second
+)
+
+// roundRobinServiceConfig spreads calls over every resolved address instead of pinning the
+// channel to the first one (gRPC's default pick_first)
+const roundRobinServiceConfig = `{"loadBalancingConfig":[{"round_robin":{}}]}`
+
+func init() {
+	resolver.Register(&discoveryBuilder{refresh: DefaultRefreshInterval, lookup: net.DefaultResolver})
+}
+
+// ClientTarget turns a service URL from configuration into a dial target. A plain "host:port" is
+// resolved through its A records, and a bare "host" through its SRV records, which carry each
+// task's port (Cloud Map registers both). Either way the records are re-resolved periodically.
+// URLs that already name a scheme (e.g. "dns:///..." or "passthrough:///...") are returned as is.
+func ClientTarget(serviceURL string) string {
+	if strings.Contains(serviceURL, "://") {
+		return serviceURL
+	}
+	return DiscoveryScheme + ":///" + serviceURL
+}
+
+// ClientOptions returns the dial options that balance calls round-robin across every address
+// a target resolves to, e.g. all running tasks of an ECS service, send the calling request's
+// trace ID along, and count failed calls for CloudWatch (see lib/metrics)
+func ClientOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithDefaultServiceConfig(roundRobinServiceConfig),
+		grpc.WithChainUnaryInterceptor(UnaryClientTrace(), metrics.UnaryClientInterceptor()),
+	}
+}
+
+// hostLookup is the part of *net.Resolver the discovery resolver uses
+type hostLookup interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// discoveryBuilder creates resolvers for the discovery scheme
+type discoveryBuilder struct {
+	refresh time.Duration
+	lookup  hostLookup
+}
+
+func (b *discoveryBuilder) Scheme() string { return DiscoveryScheme }
+
+func (b *discoveryBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &discoveryResolver{
+		endpoint: target.Endpoint(),
+		cc:       cc,
+		refresh:  b.refresh,
+		lookup:   b.lookup,
+		resolve:  make(chan struct{}, 1),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	r.wg.Add(1)
+	go r.watch()
+	return r, nil
+}
+
+// discoveryResolver re-resolves one target on a timer and whenever gRPC asks
+type discoveryResolver struct {
+	endpoint string
+	cc       resolver.ClientConn
+	refresh  time.Duration
+	lookup   hostLookup
+	resolve  chan struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	addrs []string // Last addresses sent to gRPC, sorted; nil until the first successful lookup
+}
+
+// ResolveNow schedules a re-resolution, typically because a connection to a task failed
+func (r *discoveryResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.resolve <- struct{}{}:
+	default: // One is already pending
+	}
+}
+
+func (r *discoveryResolver) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// watch resolves the target until the resolver is closed
+func (r *discoveryResolver) watch() {
+	defer r.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	var last time.Time
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-timer.C:
+		case <-r.resolve:
+			// Failing connections trigger a burst of requests; one lookup per interval is enough
+			if wait := minResolveInterval - time.Since(last); wait > 0 {
+				select {
+				case <-r.ctx.Done():
+					return
+				case <-time.After(wait):
+				}
+			}
+		}
+
+		last = time.Now()
+		r.update()
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(r.refresh)
+	}
+}
+
+// update looks the target up and passes any change of addresses to gRPC
+func (r *discoveryResolver) update() {
+	ctx, cancel := context.WithTimeout(r.ctx, lookupTimeout)
+	addrs, err := resolveEndpoint(ctx, r.lookup, r.endpoint)
+	cancel()
+	if err == nil && len(addrs) == 0 {
+		err = &net.DNSError{Err: "no addresses", Name: r.endpoint, IsNotFound: true}
+	}
+	if err != nil {
+		if r.ctx.Err() != nil {
+			return
+		}
+		if r.addrs == nil {
+			r.cc.ReportError(err)
+			return
+		}
+		// Keep calling the tasks we know about rather than failing over a DNS hiccup
+		log.Printf("WARN: Failed to re-resolve %s, keeping %d known addresses: %v", r.endpoint, len(r.addrs), err)
+		return
+	}
+
+	slices.Sort(addrs)
+	if slices.Equal(addrs, r.addrs) {
+		return
+	}
+	r.addrs = addrs
+
+	state := resolver.State{Addresses: make([]resolver.Address, len(addrs))}
+	for i, addr := range addrs {
+		state.Addresses[i] = resolver.Address{Addr: addr}
+	}
+	if err := r.cc.UpdateState(state); err != nil {
+		log.Printf("WARN: gRPC rejected addresses for %s: %v", r.endpoint, err)
+		return
+	}
+	log.Printf("Resolved %s to %d addresses: %s", r.endpoint, len(addrs), strings.Join(addrs, ", "))
+}
+
+// resolveEndpoint returns the "ip:port" addresses of an endpoint: from its A/AAAA records when it
+// carries a port, otherwise from its SRV records
+func resolveEndpoint(ctx context.Context, lookup hostLookup, endpoint string) ([]string, error) {
+	if host, port, err := net.SplitHostPort(endpoint); err == nil {
+		return resolveHost(ctx, lookup, host, port)
+	}
+
+	_, records, err := lookup.LookupSRV(ctx, "", "", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var addrs []string
+	for _, srv := range records {
+		hostAddrs, err := resolveHost(ctx, lookup, strings.TrimSuffix(srv.Target, "."), strconv.Itoa(int(srv.Port)))
+		if err != nil {
+			// A task that deregistered between the two lookups; the others still serve
+			log.Printf("WARN: Failed to resolve SRV target %s of %s: %v", srv.Target, endpoint, err)
+			continue
+		}
+		addrs = append(addrs, hostAddrs...)
+	}
+	return addrs, nil
+}
+
+// resolveHost returns the addresses of host with port appended
+func resolveHost(ctx context.Context, lookup hostLookup, host, port string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{net.JoinHostPort(host, port)}, nil
+	}
+	ips, err := lookup.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, net.JoinHostPort(ip, port))
+	}
+	return addrs, nil
+}
@@ -0,0 +1,122 @@
+// FILE: lib/grpcutil/server.go
+// The standard interceptor chain for gRPC servers: trace IDs, request logging, metrics, panic
+// recovery, and message size limits.
+
+package grpcutil
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/metrics"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultMaxRecvMsgSize caps incoming messages. Requests are ID lists and filters, so 1 MiB is generous.
+	DefaultMaxRecvMsgSize = 1 << 20
+	// DefaultMaxSendMsgSize caps outgoing messages at the default receive limit of gRPC clients,
+	// so oversized responses fail (and are logged) on the server instead of in the caller.
+	DefaultMaxSendMsgSize = 4 << 20
+)
+
+// healthServicePrefix starts the methods of the standard gRPC health service, which are not logged
+const healthServicePrefix = "/grpc.health.v1.Health/"
+
+// Options configures the server interceptor chain. Zero sizes use the defaults.
+type Options struct {
+	Metrics        *Metrics // Records call counts and latencies; nil disables metrics
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+}
+
+// ServerOptions returns the server options that install the standard interceptors:
+// calls take on their caller's trace ID, are logged and measured, and a panicking handler fails its call with codes.Internal
+// instead of crashing the process.
+func ServerOptions(opts Options) []grpc.ServerOption {
+	if opts.MaxRecvMsgSize <= 0 {
+		opts.MaxRecvMsgSize = DefaultMaxRecvMsgSize
+	}
+	if opts.MaxSendMsgSize <= 0 {
+		opts.MaxSendMsgSize = DefaultMaxSendMsgSize
+	}
+
+	// The trace ID is set first so every later interceptor can log it. Recovery runs innermost so
+	// logging and metrics see a recovered panic as an Internal error.
+	interceptors := []grpc.UnaryServerInterceptor{UnaryServerTrace(), UnaryServerLogging(), metrics.UnaryServerInterceptor()}
+	if opts.Metrics != nil {
+		interceptors = append(interceptors, opts.Metrics.UnaryServerInterceptor())
+	}
+	interceptors = append(interceptors, UnaryServerRecovery())
+
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.MaxRecvMsgSize(opts.MaxRecvMsgSize),
+		grpc.MaxSendMsgSize(opts.MaxSendMsgSize),
+	}
+}
+
+// UnaryServerRecovery turns a panic in a handler into a codes.Internal error and logs its stack
+func UnaryServerRecovery() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("ERROR: [%s] panic in gRPC handler %s: %v\n%s", apierror.TraceIDFromContext(ctx), info.FullMethod, r, debug.Stack())
+				resp, err = nil, status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// logEntry is the JSON document logged for every call
+type logEntry struct {
+	Method     string  `json:"method"`
+	Code       string  `json:"code"`
+	DurationMs float64 `json:"duration_ms"`
+	Peer       string  `json:"peer,omitempty"`
+	TraceID    string  `json:"trace_id,omitempty"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// UnaryServerLogging logs every call as one JSON line with its status code and latency.
+// Health checks are not logged, since dependents probe them continuously.
+func UnaryServerLogging() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return resp, err
+		}
+
+		entry := logEntry{
+			Method:     info.FullMethod,
+			Code:       status.Code(err).String(),
+			DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+			TraceID:    apierror.TraceIDFromContext(ctx),
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			entry.Peer = p.Addr.String()
+		}
+		if err != nil {
+			entry.Error = status.Convert(err).Message()
+		}
+
+		data, marshalErr := json.Marshal(entry)
+		if marshalErr != nil {
+			log.Printf("WARN: Failed to encode gRPC log entry for %s: %v", info.FullMethod, marshalErr)
+			return resp, err
+		}
+		log.Printf("grpc_request %s", data)
+		return resp, err
+	}
+}
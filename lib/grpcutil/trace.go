@@ -0,0 +1,50 @@
+// FILE: lib/grpcutil/trace.go
+// Carrying a request's trace ID across gRPC calls, so the logs of every service a request passes
+// through (e.g. quiz, then content) can be matched up by one ID.
+
+package grpcutil
+
+import (
+	"context"
+
+	"wise-owl/lib/apierror"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// TraceMetadataKey is the gRPC metadata key of the trace ID, the X-Request-ID header of HTTP.
+const TraceMetadataKey = "x-request-id"
+
+// maxTraceIDLength bounds accepted trace IDs, as apierror.Middleware does for the header
+const maxTraceIDLength = 128
+
+// UnaryClientTrace sends the trace ID of the calling request, if there is one, with every call
+func UnaryClientTrace() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if traceID := apierror.TraceIDFromContext(ctx); traceID != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, TraceMetadataKey, traceID)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// UnaryServerTrace puts the trace ID a caller sent into the call's context, where
+// apierror.TraceIDFromContext finds it and UnaryClientTrace passes it on.
+func UnaryServerTrace() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if traceID := incomingTraceID(ctx); traceID != "" {
+			ctx = apierror.ContextWithTraceID(ctx, traceID)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// incomingTraceID returns the trace ID in a call's metadata, or an empty string
+func incomingTraceID(ctx context.Context) string {
+	values := metadata.ValueFromIncomingContext(ctx, TraceMetadataKey)
+	if len(values) == 0 || len(values[0]) > maxTraceIDLength {
+		return ""
+	}
+	return values[0]
+}
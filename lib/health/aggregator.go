@@ -0,0 +1,206 @@
+// FILE: lib/health/aggregator.go
+// Aggregates the health endpoints of several services into one component view
+
+package health
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ComponentStatus uses the status vocabulary understood by common status-page frontends
+type ComponentStatus string
+
+const (
+	ComponentOperational   ComponentStatus = "operational"
+	ComponentDegraded      ComponentStatus = "degraded_performance"
+	ComponentPartialOutage ComponentStatus = "partial_outage"
+	ComponentMajorOutage   ComponentStatus = "major_outage"
+)
+
+// Component describes a service whose health endpoint should be polled
+type Component struct {
+	Name string
+	URL  string
+}
+
+// ComponentState holds the most recent probe result for a component
+type ComponentState struct {
+	Name        string          `json:"name"`
+	Status      ComponentStatus `json:"status"`
+	LatencyMs   int64           `json:"latency_ms"`
+	LastChecked time.Time       `json:"last_checked"`
+	LastChanged time.Time       `json:"last_changed"`
+	Error       string          `json:"error,omitempty"` // Published; never the raw error, which names internal hosts
+
+	cause string // The raw probe error, logged on the server only
+}
+
+// Aggregator periodically probes component health endpoints and caches the results
+type Aggregator struct {
+	components    []Component
+	client        *http.Client
+	interval      time.Duration
+	slowThreshold time.Duration
+
+	mu     sync.RWMutex
+	states map[string]*ComponentState
+}
+
+// NewAggregator creates an aggregator for the given components
+func NewAggregator(components []Component, interval time.Duration) *Aggregator {
+	states := make(map[string]*ComponentState, len(components))
+	for _, component := range components {
+		states[component.Name] = &ComponentState{
+			Name:   component.Name,
+			Status: ComponentMajorOutage,
+			Error:  "not checked yet",
+		}
+	}
+
+	return &Aggregator{
+		components:    components,
+		client:        &http.Client{Timeout: 5 * time.Second},
+		interval:      interval,
+		slowThreshold: 2 * time.Second,
+		states:        states,
+	}
+}
+
+// Start probes all components immediately and then on every interval until ctx is cancelled
+func (a *Aggregator) Start(ctx context.Context) {
+	a.Refresh(ctx)
+
+	ticker := time.NewTicker(a.interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.Refresh(ctx)
+			}
+		}
+	}()
+}
+
+// Refresh probes every component concurrently and updates the cached states
+func (a *Aggregator) Refresh(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, component := range a.components {
+		wg.Add(1)
+		go func(component Component) {
+			defer wg.Done()
+			a.record(component.Name, a.probe(ctx, component))
+		}(component)
+	}
+	wg.Wait()
+}
+
+// States returns a snapshot of all component states in registration order
+func (a *Aggregator) States() []ComponentState {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	states := make([]ComponentState, 0, len(a.components))
+	for _, component := range a.components {
+		states = append(states, *a.states[component.Name])
+	}
+	return states
+}
+
+// OverallStatus reduces all component states to a single status
+func (a *Aggregator) OverallStatus() ComponentStatus {
+	states := a.States()
+	if len(states) == 0 {
+		return ComponentOperational
+	}
+
+	down := 0
+	degraded := 0
+	for _, state := range states {
+		switch state.Status {
+		case ComponentMajorOutage:
+			down++
+		case ComponentDegraded, ComponentPartialOutage:
+			degraded++
+		}
+	}
+
+	switch {
+	case down == len(states):
+		return ComponentMajorOutage
+	case down > 0:
+		return ComponentPartialOutage
+	case degraded > 0:
+		return ComponentDegraded
+	default:
+		return ComponentOperational
+	}
+}
+
+// probe performs a single health request against a component
+func (a *Aggregator) probe(ctx context.Context, component Component) ComponentState {
+	state := ComponentState{
+		Name:        component.Name,
+		LastChecked: time.Now().UTC(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, component.URL, nil)
+	if err != nil {
+		state.Status = ComponentMajorOutage
+		state.Error = "misconfigured"
+		state.cause = err.Error()
+		return state
+	}
+
+	start := time.Now()
+	resp, err := a.client.Do(req)
+	state.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		state.Status = ComponentMajorOutage
+		state.Error = "unreachable"
+		state.cause = err.Error()
+		return state
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusServiceUnavailable:
+		state.Status = ComponentMajorOutage
+		state.Error = resp.Status
+	case resp.StatusCode >= 300:
+		state.Status = ComponentPartialOutage
+		state.Error = resp.Status
+	case time.Since(start) > a.slowThreshold:
+		state.Status = ComponentDegraded
+	default:
+		state.Status = ComponentOperational
+	}
+
+	return state
+}
+
+// record stores a probe result, keeping track of when the status last changed
+func (a *Aggregator) record(name string, state ComponentState) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	previous, ok := a.states[name]
+	if !ok || previous.Status != state.Status || previous.LastChanged.IsZero() {
+		state.LastChanged = state.LastChecked
+		if ok && previous.Status != state.Status {
+			log.Printf("Component %s changed status: %s -> %s", name, previous.Status, state.Status)
+		}
+		if state.cause != "" {
+			log.Printf("WARN: Health probe of %s failed: %s", name, state.cause)
+		}
+	} else {
+		state.LastChanged = previous.LastChanged
+	}
+	a.states[name] = &state
+}
@@ -0,0 +1,306 @@
+// FILE: lib/health/checker.go
+// The health checker every service mounts under /health
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"wise-owl/lib/config"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// databaseTimeout bounds a database ping
+const databaseTimeout = 2 * time.Second
+
+// Checker is the health surface services mount and register dependencies on
+type Checker interface {
+	RegisterRoutes(*gin.Engine)
+	Handler() gin.HandlerFunc
+	ReadyHandler() gin.HandlerFunc
+	AddDependency(DependencyConfig) error
+	Warmup(name string) *WarmupTask
+	Start(context.Context)
+}
+
+// Options configures a HealthChecker
+type Options struct {
+	// Database is pinged by the health and readiness checks; nil skips the database check
+	Database *mongo.Database
+	// DeepChecks serves /health/deep with database latency, dependencies, memory, and uptime
+	DeepChecks bool
+	// AWS turns on deep checks and adds the ECS execution environment and task metadata to them
+	AWS bool
+	// Dependencies are downstream services to probe; more can be added with AddDependency
+	Dependencies []DependencyConfig
+	// ProbeInterval is how often Start refreshes the cached results; defaults to HEALTH_PROBE_INTERVAL, or 10s
+	ProbeInterval time.Duration
+	// HistorySize is how many recent results /health/metrics keeps per check; defaults to 20
+	HistorySize int
+}
+
+// HealthChecker serves the liveness, readiness, health, and optional deep check endpoints
+type HealthChecker struct {
+	serviceName   string
+	startTime     time.Time
+	db            *mongo.Database
+	deepChecks    bool
+	aws           bool
+	ecs           *ecsMetadataReader // nil outside ECS
+	probeInterval time.Duration
+	metrics       *HealthMetrics
+
+	mu           sync.RWMutex
+	dependencies []*dependency
+	latest       *probeResult // Most recent background probe; nil until Start runs
+	probing      bool
+	warmup       []*WarmupTask
+}
+
+// Ensure HealthChecker implements Checker
+var _ Checker = (*HealthChecker)(nil)
+
+// HealthResponse represents a health check response
+type HealthResponse struct {
+	Status    string    `json:"status"`
+	Service   string    `json:"service"`
+	Timestamp time.Time `json:"timestamp"`
+	Uptime    string    `json:"uptime"`
+	Database  string    `json:"database,omitempty"`
+
+	Dependencies []DependencyStatus `json:"dependencies,omitempty"`
+	Warmup       []WarmupStatus     `json:"warmup,omitempty"`
+	Freshness
+}
+
+// ReadinessResponse reports whether the service should receive traffic
+type ReadinessResponse struct {
+	Ready        bool               `json:"ready"`
+	Checks       map[string]bool    `json:"checks"`
+	Dependencies []DependencyStatus `json:"dependencies,omitempty"`
+	Warmup       []WarmupStatus     `json:"warmup,omitempty"`
+	Timestamp    time.Time          `json:"timestamp"`
+	Freshness
+}
+
+// New creates a health checker for serviceName. Invalid dependencies are logged and skipped.
+func New(serviceName string, opts Options) *HealthChecker {
+	hc := &HealthChecker{
+		serviceName:   serviceName,
+		startTime:     time.Now(),
+		db:            opts.Database,
+		deepChecks:    opts.DeepChecks || opts.AWS,
+		aws:           opts.AWS,
+		probeInterval: opts.ProbeInterval,
+		metrics:       newHealthMetrics(opts.HistorySize),
+	}
+	if hc.aws {
+		hc.ecs = newECSMetadataReader()
+	}
+	if hc.probeInterval <= 0 {
+		interval, err := config.GetDuration("HEALTH_PROBE_INTERVAL")
+		if err != nil || interval <= 0 {
+			if err != nil {
+				log.Printf("WARN: %v, probing every %s", err, defaultProbeInterval)
+			}
+			interval = defaultProbeInterval
+		}
+		hc.probeInterval = interval
+	}
+	for _, dep := range opts.Dependencies {
+		if err := hc.AddDependency(dep); err != nil {
+			log.Printf("WARN: Skipping health dependency: %v", err)
+		}
+	}
+	return hc
+}
+
+// AddDependency registers a downstream service to probe with the health and readiness checks.
+// A failing critical dependency makes the service not ready; others are only reported.
+func (hc *HealthChecker) AddDependency(cfg DependencyConfig) error {
+	if cfg.Name == "" {
+		return fmt.Errorf("dependency name is required")
+	}
+	if cfg.Target == "" && cfg.Conn == nil {
+		return fmt.Errorf("dependency %s: a target or connection is required", cfg.Name)
+	}
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	hc.dependencies = append(hc.dependencies, &dependency{DependencyConfig: cfg})
+	return nil
+}
+
+// RegisterRoutes mounts the health endpoints under /health
+func (hc *HealthChecker) RegisterRoutes(router *gin.Engine) {
+	health := router.Group("/health")
+	{
+		health.GET("/", hc.Handler())
+		health.HEAD("/", hc.Handler())
+		health.GET("/ready", hc.ReadyHandler())
+		health.HEAD("/ready", hc.ReadyHandler())
+		health.GET("/live", hc.LiveHandler())
+		health.HEAD("/live", hc.LiveHandler())
+		health.GET("/startup", hc.StartupHandler())
+		health.HEAD("/startup", hc.StartupHandler())
+		health.GET("/metrics", hc.CreateMetricsHandler())
+		if hc.deepChecks {
+			health.GET("/deep", hc.DeepHandler())
+		}
+	}
+}
+
+// Handler reports overall health. A database failure is unhealthy (503); a failing
+// dependency only degrades the status, and a service still warming up reports "starting",
+// since readiness decides routing.
+func (hc *HealthChecker) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response := HealthResponse{
+			Service:   hc.serviceName,
+			Timestamp: time.Now(),
+			Uptime:    time.Since(hc.startTime).String(),
+		}
+
+		result, freshness := hc.results(c.Request.Context())
+		response.Freshness = freshness
+
+		if hc.db != nil {
+			if result.DatabaseErr != nil {
+				response.Status = "unhealthy"
+				response.Database = "disconnected"
+				c.JSON(http.StatusServiceUnavailable, response)
+				return
+			}
+			response.Database = "connected"
+		}
+
+		response.Status = "healthy"
+		response.Dependencies = result.Dependencies
+		for _, dep := range response.Dependencies {
+			if !dep.Healthy {
+				response.Status = "degraded"
+			}
+		}
+		warmup, started := hc.warmupStatus()
+		if !started {
+			response.Status = "starting"
+			response.Warmup = warmup
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// ReadyHandler returns 503 until warmup has finished and the database and every critical
+// dependency are reachable
+func (hc *HealthChecker) ReadyHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, freshness := hc.results(c.Request.Context())
+		response := ReadinessResponse{
+			Checks:       map[string]bool{},
+			Dependencies: result.Dependencies,
+			Timestamp:    time.Now().UTC(),
+			Freshness:    freshness,
+		}
+		if hc.db != nil {
+			response.Checks["database"] = result.DatabaseErr == nil
+		}
+		for _, dep := range response.Dependencies {
+			response.Checks[dep.Name] = dep.Healthy || !dep.Critical
+		}
+		if warmup, started := hc.warmupStatus(); len(warmup) > 0 {
+			response.Checks["warmup"] = started
+			response.Warmup = warmup
+		}
+
+		response.Ready = true
+		for _, ok := range response.Checks {
+			response.Ready = response.Ready && ok
+		}
+
+		status := http.StatusOK
+		if !response.Ready {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, response)
+	}
+}
+
+// LiveHandler reports that the process is up, without checking anything else
+func (hc *HealthChecker) LiveHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status":    "alive",
+			"service":   hc.serviceName,
+			"timestamp": time.Now().UTC(),
+		})
+	}
+}
+
+// DeepHandler reports detailed status for monitoring
+func (hc *HealthChecker) DeepHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, freshness := hc.results(c.Request.Context())
+		checks := map[string]interface{}{
+			"database":     result.databaseStatus(hc.db != nil),
+			"dependencies": result.Dependencies,
+			"memory":       memoryUsage(),
+			"uptime":       time.Since(hc.startTime).Seconds(),
+		}
+		if hc.aws {
+			checks["environment"] = environmentInfo()
+		}
+		if hc.ecs != nil {
+			checks["ecs"] = hc.ecs.status(c.Request.Context())
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"service":    hc.serviceName,
+			"status":     "healthy",
+			"checks":     checks,
+			"timestamp":  time.Now().UTC(),
+			"checked_at": freshness.CheckedAt,
+			"age_ms":     freshness.AgeMs,
+			"stale":      freshness.Stale,
+		})
+	}
+}
+
+// pingDatabase pings the database using the client's read preference; the caller bounds it by databaseTimeout
+func (hc *HealthChecker) pingDatabase(ctx context.Context) error {
+	return hc.db.Client().Ping(ctx, nil)
+}
+
+// memoryUsage returns current memory usage statistics in MB
+func memoryUsage() map[string]interface{} {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	return map[string]interface{}{
+		"alloc_mb":       m.Alloc / 1024 / 1024,
+		"total_alloc_mb": m.TotalAlloc / 1024 / 1024,
+		"sys_mb":         m.Sys / 1024 / 1024,
+		"num_gc":         m.NumGC,
+	}
+}
+
+// environmentInfo returns the runtime and ECS environment
+func environmentInfo() map[string]interface{} {
+	return map[string]interface{}{
+		"aws_execution_env": os.Getenv("AWS_EXECUTION_ENV"),
+		"ecs_container":     os.Getenv("ECS_CONTAINER_METADATA_URI_V4") != "" || os.Getenv("ECS_CONTAINER_METADATA_URI") != "",
+		"go_version":        runtime.Version(),
+		"num_cpu":           runtime.NumCPU(),
+		"gomaxprocs":        runtime.GOMAXPROCS(0), // Compare with the task's CPU limit
+		"goroutines":        runtime.NumGoroutine(),
+		"arch":              runtime.GOARCH,
+		"os":                runtime.GOOS,
+	}
+}
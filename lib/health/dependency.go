@@ -0,0 +1,163 @@
+// FILE: lib/health/dependency.go
+// Probes for downstream services a service depends on
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"wise-owl/lib/fanout"
+	"wise-owl/lib/resilience"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// CheckType selects how a dependency is probed
+type CheckType string
+
+const (
+	CheckHTTP CheckType = "http" // GET the target URL; any 2xx is healthy
+	CheckGRPC CheckType = "grpc" // Call grpc.health.v1.Health/Check on the target
+)
+
+// defaultDependencyTimeout bounds a probe when the dependency does not set its own timeout
+const defaultDependencyTimeout = 2 * time.Second
+
+// DependencyConfig describes a downstream service to probe
+type DependencyConfig struct {
+	Name      string
+	CheckType CheckType
+	// Target is a URL for HTTP checks and a host:port for gRPC checks
+	Target string
+	// Service is the gRPC health service name; empty asks about the server as a whole
+	Service string
+	// Conn reuses an existing client connection for gRPC checks instead of dialing Target
+	Conn    *grpc.ClientConn
+	Timeout time.Duration
+	// Critical dependencies must be up for the service to report ready
+	Critical bool
+	// Breaker is the circuit breaker the service's clients use for this dependency, if any;
+	// its state is reported with each probe
+	Breaker *resilience.CircuitBreaker
+}
+
+// DependencyStatus is the result of probing one dependency
+type DependencyStatus struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Healthy   bool   `json:"healthy"`
+	Critical  bool   `json:"critical"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+
+	Circuit *resilience.Metrics `json:"circuit,omitempty"`
+}
+
+// dependency pairs a configuration with the gRPC connection dialed for it, if any
+type dependency struct {
+	DependencyConfig
+
+	dialOnce sync.Once
+	conn     *grpc.ClientConn
+	dialErr  error
+}
+
+// httpProbeClient is shared by HTTP dependency probes; each probe sets its own deadline
+var httpProbeClient = &http.Client{}
+
+// timeout is the budget of one probe of the dependency
+func (d *dependency) timeout() time.Duration {
+	if d.Timeout > 0 {
+		return d.Timeout
+	}
+	return defaultDependencyTimeout
+}
+
+// probe checks the dependency with the method its configuration asks for
+func (d *dependency) probe(ctx context.Context) error {
+	switch d.CheckType {
+	case CheckGRPC:
+		return d.probeGRPC(ctx)
+	case CheckHTTP, "":
+		return probeHTTP(ctx, d.Target)
+	default:
+		return fmt.Errorf("unknown check type %q", d.CheckType)
+	}
+}
+
+// status describes how a probe of the dependency ended and records it in metrics
+func (d *dependency) status(result fanout.Result, checkedAt time.Time, metrics *HealthMetrics) DependencyStatus {
+	status := DependencyStatus{
+		Name:      d.Name,
+		Type:      string(d.CheckType),
+		Critical:  d.Critical,
+		LatencyMs: result.Latency.Milliseconds(),
+		Healthy:   result.Err == nil,
+	}
+	if status.Type == "" {
+		status.Type = string(CheckHTTP)
+	}
+	if result.Err != nil {
+		status.Error = result.Err.Error()
+	}
+	if d.Breaker != nil {
+		circuit := d.Breaker.Metrics()
+		status.Circuit = &circuit
+	}
+
+	metrics.record(d.Name, CheckResult{
+		Healthy:   status.Healthy,
+		LatencyMs: status.LatencyMs,
+		Error:     status.Error,
+		CheckedAt: checkedAt,
+	})
+	return status
+}
+
+// probeGRPC asks the dependency's health service whether it is serving
+func (d *dependency) probeGRPC(ctx context.Context) error {
+	conn := d.Conn
+	if conn == nil {
+		// Connections are created lazily and reused, so repeated probes do not redial
+		d.dialOnce.Do(func() {
+			d.conn, d.dialErr = grpc.NewClient(d.Target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		})
+		if d.dialErr != nil {
+			return fmt.Errorf("dial %s: %v", d.Target, d.dialErr)
+		}
+		conn = d.conn
+	}
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: d.Service})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("status %s", resp.GetStatus())
+	}
+	return nil
+}
+
+// probeHTTP expects a 2xx response from url
+func probeHTTP(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpProbeClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
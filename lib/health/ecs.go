@@ -0,0 +1,159 @@
+// FILE: lib/health/ecs.go
+// The ECS task metadata endpoint, read for /health/deep so an incident can be narrowed down to a
+// task, its availability zone, and the limits it runs under
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// ecsMetadataTimeout bounds a request to the task metadata endpoint, which is local to the task
+const ecsMetadataTimeout = 2 * time.Second
+
+// ECSMetadata describes the ECS task and container a service runs in
+type ECSMetadata struct {
+	Cluster          string        `json:"cluster,omitempty"`
+	TaskARN          string        `json:"task_arn"`
+	Family           string        `json:"family,omitempty"`
+	Revision         string        `json:"revision,omitempty"`
+	LaunchType       string        `json:"launch_type,omitempty"`
+	AvailabilityZone string        `json:"availability_zone,omitempty"`
+	CPU              float64       `json:"cpu_vcpu,omitempty"`  // Task CPU limit
+	MemoryMB         int64         `json:"memory_mb,omitempty"` // Task memory limit
+	Container        *ECSContainer `json:"container,omitempty"`
+}
+
+// ECSContainer describes the container a service runs in
+type ECSContainer struct {
+	Name      string     `json:"name"`
+	DockerID  string     `json:"docker_id,omitempty"`
+	Image     string     `json:"image,omitempty"`
+	ImageID   string     `json:"image_id,omitempty"`
+	CPUUnits  float64    `json:"cpu_units,omitempty"` // 1024 units are one vCPU
+	MemoryMB  int64      `json:"memory_mb,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+}
+
+// ecsLimits are the limits of a task (CPU in vCPUs) or container (CPU in units)
+type ecsLimits struct {
+	CPU    float64 `json:"CPU"`
+	Memory int64   `json:"Memory"`
+}
+
+// ecsTaskResponse is the part of the task metadata response used here
+type ecsTaskResponse struct {
+	Cluster          string    `json:"Cluster"`
+	TaskARN          string    `json:"TaskARN"`
+	Family           string    `json:"Family"`
+	Revision         string    `json:"Revision"`
+	LaunchType       string    `json:"LaunchType"`
+	AvailabilityZone string    `json:"AvailabilityZone"`
+	Limits           ecsLimits `json:"Limits"`
+}
+
+// ecsContainerResponse is the part of the container metadata response used here
+type ecsContainerResponse struct {
+	Name      string     `json:"Name"`
+	DockerID  string     `json:"DockerId"`
+	Image     string     `json:"Image"`
+	ImageID   string     `json:"ImageID"`
+	Limits    ecsLimits  `json:"Limits"`
+	StartedAt *time.Time `json:"StartedAt"`
+}
+
+// ecsMetadataReader fetches a task's metadata once it is first asked for and keeps it, since
+// none of it changes for the life of the task. Failures are retried on the next request.
+type ecsMetadataReader struct {
+	endpoint string
+	client   *http.Client
+
+	mu       sync.Mutex
+	metadata *ECSMetadata
+}
+
+// newECSMetadataReader returns a reader for the task the process runs in, or nil outside ECS.
+// ECS sets the version 4 endpoint on Fargate and current container agents, version 3 on older ones.
+func newECSMetadataReader() *ecsMetadataReader {
+	endpoint := os.Getenv("ECS_CONTAINER_METADATA_URI_V4")
+	if endpoint == "" {
+		endpoint = os.Getenv("ECS_CONTAINER_METADATA_URI")
+	}
+	if endpoint == "" {
+		return nil
+	}
+	return &ecsMetadataReader{endpoint: endpoint, client: &http.Client{Timeout: ecsMetadataTimeout}}
+}
+
+// status returns the task's metadata, or the error reading it, for /health/deep
+func (r *ecsMetadataReader) status(ctx context.Context) interface{} {
+	metadata, err := r.read(ctx)
+	if err != nil {
+		return map[string]string{"error": err.Error()}
+	}
+	return metadata
+}
+
+// read returns the task's metadata, fetching it the first time
+func (r *ecsMetadataReader) read(ctx context.Context) (*ECSMetadata, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.metadata != nil {
+		return r.metadata, nil
+	}
+
+	var task ecsTaskResponse
+	if err := r.get(ctx, r.endpoint+"/task", &task); err != nil {
+		return nil, err
+	}
+	var container ecsContainerResponse
+	if err := r.get(ctx, r.endpoint, &container); err != nil {
+		return nil, err
+	}
+	r.metadata = &ECSMetadata{
+		Cluster:          task.Cluster,
+		TaskARN:          task.TaskARN,
+		Family:           task.Family,
+		Revision:         task.Revision,
+		LaunchType:       task.LaunchType,
+		AvailabilityZone: task.AvailabilityZone,
+		CPU:              task.Limits.CPU,
+		MemoryMB:         task.Limits.Memory,
+		Container: &ECSContainer{
+			Name:      container.Name,
+			DockerID:  container.DockerID,
+			Image:     container.Image,
+			ImageID:   container.ImageID,
+			CPUUnits:  container.Limits.CPU,
+			MemoryMB:  container.Limits.Memory,
+			StartedAt: container.StartedAt,
+		},
+	}
+	return r.metadata, nil
+}
+
+// get decodes the JSON document at url into v
+func (r *ecsMetadataReader) get(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("task metadata unavailable: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("task metadata unavailable: %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse task metadata: %v", err)
+	}
+	return nil
+}
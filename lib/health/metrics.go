@@ -0,0 +1,166 @@
+// FILE: lib/health/metrics.go
+// Counters and recent history for every check the health checker runs
+
+package health
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultHistorySize is how many recent results are kept per check when Options.HistorySize is not set
+const defaultHistorySize = 20
+
+// databaseCheck is the name database pings are recorded under
+const databaseCheck = "database"
+
+// CheckResult is a single run of one check
+type CheckResult struct {
+	Healthy   bool      `json:"healthy"`
+	LatencyMs int64     `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// CheckMetrics summarizes every run of one check, with the most recent results oldest first
+type CheckMetrics struct {
+	TotalChecks   int64         `json:"total_checks"`
+	FailedChecks  int64         `json:"failed_checks"`
+	LastLatencyMs int64         `json:"last_latency_ms"`
+	AvgLatencyMs  float64       `json:"avg_latency_ms"`
+	MaxLatencyMs  int64         `json:"max_latency_ms"`
+	LastSuccess   *time.Time    `json:"last_success,omitempty"`
+	LastFailure   *time.Time    `json:"last_failure,omitempty"`
+	LastError     string        `json:"last_error,omitempty"`
+	Recent        []CheckResult `json:"recent"`
+}
+
+// MetricsSnapshot is a copy of the health metrics at one point in time
+type MetricsSnapshot struct {
+	Service      string                  `json:"service"`
+	Since        time.Time               `json:"since"`
+	TotalChecks  int64                   `json:"total_checks"`  // Rounds of checks run
+	FailedChecks int64                   `json:"failed_checks"` // Rounds in which any check failed
+	Checks       map[string]CheckMetrics `json:"checks"`
+	Timestamp    time.Time               `json:"timestamp"`
+}
+
+// HealthMetrics accumulates check results. It is safe for concurrent use.
+type HealthMetrics struct {
+	mu           sync.Mutex
+	since        time.Time
+	historySize  int
+	totalChecks  int64
+	failedChecks int64
+	checks       map[string]*checkRecord
+}
+
+// checkRecord holds the counters and result history of one check
+type checkRecord struct {
+	metrics        CheckMetrics
+	totalLatencyMs int64
+	history        []CheckResult // Ring buffer; next is the slot the next result overwrites
+	next           int
+}
+
+// newHealthMetrics creates empty metrics keeping historySize recent results per check
+func newHealthMetrics(historySize int) *HealthMetrics {
+	if historySize <= 0 {
+		historySize = defaultHistorySize
+	}
+	return &HealthMetrics{
+		since:       time.Now().UTC(),
+		historySize: historySize,
+		checks:      make(map[string]*checkRecord),
+	}
+}
+
+// recordRound counts one run of performHealthCheck
+func (m *HealthMetrics) recordRound(failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.totalChecks++
+	if failed {
+		m.failedChecks++
+	}
+}
+
+// record adds the result of one check
+func (m *HealthMetrics) record(name string, result CheckResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rec, ok := m.checks[name]
+	if !ok {
+		rec = &checkRecord{history: make([]CheckResult, 0, m.historySize)}
+		m.checks[name] = rec
+	}
+
+	rec.metrics.TotalChecks++
+	rec.totalLatencyMs += result.LatencyMs
+	rec.metrics.LastLatencyMs = result.LatencyMs
+	rec.metrics.AvgLatencyMs = float64(rec.totalLatencyMs) / float64(rec.metrics.TotalChecks)
+	if result.LatencyMs > rec.metrics.MaxLatencyMs {
+		rec.metrics.MaxLatencyMs = result.LatencyMs
+	}
+
+	checkedAt := result.CheckedAt
+	if result.Healthy {
+		rec.metrics.LastSuccess = &checkedAt
+	} else {
+		rec.metrics.FailedChecks++
+		rec.metrics.LastFailure = &checkedAt
+		rec.metrics.LastError = result.Error
+	}
+
+	if len(rec.history) < m.historySize {
+		rec.history = append(rec.history, result)
+	} else {
+		rec.history[rec.next] = result
+	}
+	rec.next = (rec.next + 1) % m.historySize
+}
+
+// GetMetrics returns a copy of the counters and recent results of every check
+func (m *HealthMetrics) GetMetrics() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := MetricsSnapshot{
+		Since:        m.since,
+		TotalChecks:  m.totalChecks,
+		FailedChecks: m.failedChecks,
+		Checks:       make(map[string]CheckMetrics, len(m.checks)),
+		Timestamp:    time.Now().UTC(),
+	}
+	for name, rec := range m.checks {
+		metrics := rec.metrics
+		metrics.Recent = make([]CheckResult, 0, len(rec.history))
+		if len(rec.history) == m.historySize {
+			// Once full, the oldest result is the one about to be overwritten
+			metrics.Recent = append(metrics.Recent, rec.history[rec.next:]...)
+			metrics.Recent = append(metrics.Recent, rec.history[:rec.next]...)
+		} else {
+			metrics.Recent = append(metrics.Recent, rec.history...)
+		}
+		snapshot.Checks[name] = metrics
+	}
+	return snapshot
+}
+
+// Metrics returns the checker's accumulated metrics
+func (hc *HealthChecker) Metrics() *HealthMetrics {
+	return hc.metrics
+}
+
+// CreateMetricsHandler serves the check counters and recent results per check
+func (hc *HealthChecker) CreateMetricsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		snapshot := hc.metrics.GetMetrics()
+		snapshot.Service = hc.serviceName
+		c.JSON(http.StatusOK, snapshot)
+	}
+}
@@ -0,0 +1,145 @@
+// FILE: lib/health/prober.go
+// Background probing so health endpoints serve cached results instead of pinging on every request
+
+package health
+
+import (
+	"context"
+	"time"
+
+	"wise-owl/lib/fanout"
+)
+
+const (
+	// defaultProbeInterval is used when neither Options.ProbeInterval nor HEALTH_PROBE_INTERVAL is set
+	defaultProbeInterval = 10 * time.Second
+	// staleIntervals is how many missed refreshes mark a cached result as stale
+	staleIntervals = 3
+)
+
+// probeResult is one round of database and dependency checks
+type probeResult struct {
+	DatabaseErr     error
+	DatabaseLatency time.Duration
+	Dependencies    []DependencyStatus
+	CheckedAt       time.Time
+}
+
+// Freshness tells clients how old the check results in a response are
+type Freshness struct {
+	CheckedAt time.Time `json:"checked_at"`
+	AgeMs     int64     `json:"age_ms"`
+	// Stale is set when the background prober has missed several refreshes
+	Stale bool `json:"stale"`
+}
+
+// Start probes immediately and then on every interval until ctx is cancelled. Until Start is
+// called, and after ctx is cancelled, handlers probe on each request instead.
+func (hc *HealthChecker) Start(ctx context.Context) {
+	hc.refresh(ctx)
+
+	hc.mu.Lock()
+	hc.probing = true
+	hc.mu.Unlock()
+
+	ticker := time.NewTicker(hc.probeInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				hc.mu.Lock()
+				hc.probing = false
+				hc.mu.Unlock()
+				return
+			case <-ticker.C:
+				hc.refresh(ctx)
+			}
+		}
+	}()
+}
+
+// refresh probes everything and stores the result for the handlers
+func (hc *HealthChecker) refresh(ctx context.Context) {
+	result := hc.performHealthCheck(ctx)
+
+	hc.mu.Lock()
+	hc.latest = &result
+	hc.mu.Unlock()
+}
+
+// results returns the latest background probe, or probes now if the prober is not running
+func (hc *HealthChecker) results(ctx context.Context) (probeResult, Freshness) {
+	hc.mu.RLock()
+	latest, probing := hc.latest, hc.probing
+	hc.mu.RUnlock()
+
+	if !probing || latest == nil {
+		result := hc.performHealthCheck(ctx)
+		return result, Freshness{CheckedAt: result.CheckedAt}
+	}
+
+	age := time.Since(latest.CheckedAt)
+	return *latest, Freshness{
+		CheckedAt: latest.CheckedAt,
+		AgeMs:     age.Milliseconds(),
+		Stale:     age > staleIntervals*hc.probeInterval,
+	}
+}
+
+// performHealthCheck pings the database and probes every dependency at the same time, each within
+// its own timeout, recording each result in the metrics
+func (hc *HealthChecker) performHealthCheck(ctx context.Context) probeResult {
+	hc.mu.RLock()
+	deps := append([]*dependency(nil), hc.dependencies...)
+	hc.mu.RUnlock()
+
+	result := probeResult{CheckedAt: time.Now().UTC()}
+	calls := make([]fanout.Call, 0, len(deps)+1)
+	for _, dep := range deps {
+		calls = append(calls, fanout.Call{Name: dep.Name, Timeout: dep.timeout(), Run: dep.probe})
+	}
+	if hc.db != nil {
+		calls = append(calls, fanout.Call{Name: databaseCheck, Timeout: databaseTimeout, Run: hc.pingDatabase})
+	}
+	results := fanout.Run(ctx, calls...)
+
+	for i, dep := range deps {
+		result.Dependencies = append(result.Dependencies, dep.status(results[i], result.CheckedAt, hc.metrics))
+	}
+	if hc.db != nil {
+		ping := results[len(deps)]
+		result.DatabaseErr = ping.Err
+		result.DatabaseLatency = ping.Latency
+
+		check := CheckResult{
+			Healthy:   ping.Err == nil,
+			LatencyMs: ping.Latency.Milliseconds(),
+			CheckedAt: result.CheckedAt,
+		}
+		if ping.Err != nil {
+			check.Error = ping.Err.Error()
+		}
+		hc.metrics.record(databaseCheck, check)
+	}
+
+	failed := result.DatabaseErr != nil
+	for _, dep := range result.Dependencies {
+		failed = failed || !dep.Healthy
+	}
+	hc.metrics.recordRound(failed)
+	return result
+}
+
+// databaseStatus returns whether the database answered a ping and how long it took
+func (r probeResult) databaseStatus(configured bool) map[string]interface{} {
+	status := map[string]interface{}{
+		"connected": false,
+		"latency":   0,
+	}
+	if configured && r.DatabaseErr == nil {
+		status["connected"] = true
+		status["latency"] = r.DatabaseLatency.Milliseconds()
+	}
+	return status
+}
@@ -0,0 +1,134 @@
+// FILE: lib/health/warmup.go
+// Startup tasks that must finish before a service reports ready
+
+package health
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WarmupState is where a warmup task is in its lifecycle
+type WarmupState string
+
+const (
+	WarmupPending WarmupState = "pending"
+	WarmupRunning WarmupState = "running"
+	WarmupDone    WarmupState = "done"
+	WarmupFailed  WarmupState = "failed"
+)
+
+// WarmupTask is a startup step such as seeding or index creation. The service is not ready,
+// and /health/startup fails, until every registered task is done. A failed task keeps it that way.
+type WarmupTask struct {
+	hc   *HealthChecker
+	name string
+
+	// Guarded by hc.mu
+	state      WarmupState
+	done       int
+	total      int
+	err        error
+	startedAt  time.Time
+	finishedAt time.Time
+}
+
+// WarmupStatus reports the progress of one warmup task
+type WarmupStatus struct {
+	Name       string      `json:"name"`
+	State      WarmupState `json:"state"`
+	Done       int         `json:"done,omitempty"`
+	Total      int         `json:"total,omitempty"`
+	DurationMs int64       `json:"duration_ms"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// Warmup registers a pending startup task. Register every task before the HTTP server starts
+// so readiness cannot pass in the gap before a task exists.
+func (hc *HealthChecker) Warmup(name string) *WarmupTask {
+	task := &WarmupTask{hc: hc, name: name, state: WarmupPending}
+
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	hc.warmup = append(hc.warmup, task)
+	return task
+}
+
+// Run marks the task running, calls fn, and finishes the task with its error
+func (t *WarmupTask) Run(fn func() error) error {
+	t.Start()
+	err := fn()
+	t.Finish(err)
+	return err
+}
+
+// Start marks the task running
+func (t *WarmupTask) Start() {
+	t.hc.mu.Lock()
+	defer t.hc.mu.Unlock()
+	t.state = WarmupRunning
+	t.startedAt = time.Now()
+}
+
+// Progress records how many of total units of work are done, e.g. documents seeded
+func (t *WarmupTask) Progress(done, total int) {
+	t.hc.mu.Lock()
+	defer t.hc.mu.Unlock()
+	t.done, t.total = done, total
+}
+
+// Finish marks the task done, or failed if err is not nil
+func (t *WarmupTask) Finish(err error) {
+	t.hc.mu.Lock()
+	defer t.hc.mu.Unlock()
+	t.finishedAt = time.Now()
+	t.err = err
+	t.state = WarmupDone
+	if err != nil {
+		t.state = WarmupFailed
+	}
+}
+
+// warmupStatus returns the progress of every task in registration order and whether all are done
+func (hc *HealthChecker) warmupStatus() ([]WarmupStatus, bool) {
+	hc.mu.RLock()
+	defer hc.mu.RUnlock()
+
+	statuses := make([]WarmupStatus, 0, len(hc.warmup))
+	started := true
+	for _, task := range hc.warmup {
+		status := WarmupStatus{Name: task.name, State: task.state, Done: task.done, Total: task.total}
+		switch {
+		case !task.finishedAt.IsZero():
+			status.DurationMs = task.finishedAt.Sub(task.startedAt).Milliseconds()
+		case !task.startedAt.IsZero():
+			status.DurationMs = time.Since(task.startedAt).Milliseconds()
+		}
+		if task.err != nil {
+			status.Error = task.err.Error()
+		}
+		started = started && task.state == WarmupDone
+		statuses = append(statuses, status)
+	}
+	return statuses, started
+}
+
+// StartupHandler returns 503 with per-task progress until every warmup task is done
+func (hc *HealthChecker) StartupHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tasks, started := hc.warmupStatus()
+
+		status := http.StatusOK
+		if !started {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{
+			"started":   started,
+			"service":   hc.serviceName,
+			"warmup":    tasks,
+			"timestamp": time.Now().UTC(),
+		})
+	}
+}
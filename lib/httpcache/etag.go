@@ -0,0 +1,59 @@
+// FILE: lib/httpcache/etag.go
+// Conditional GET support: responses carry a content-hash ETag and matching If-None-Match requests get 304 Not Modified
+
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"wise-owl/lib/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JSON writes obj as JSON with an ETag derived from the encoded body.
+// If the request's If-None-Match header already names that ETag, it responds 304 with no body instead.
+// Clients are told to revalidate on every use so changed content is never served stale.
+func JSON(c *gin.Context, status int, obj interface{}) {
+	body, err := json.Marshal(obj)
+	if err != nil {
+		c.Error(apierror.Internal("encoding_error", err))
+		return
+	}
+
+	etag := ETag(body)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+
+	if status == http.StatusOK && Matches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(status, "application/json; charset=utf-8", body)
+}
+
+// ETag returns a strong entity tag for body.
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// Matches reports whether an If-None-Match header value matches etag.
+// Comparison is weak, as RFC 9110 requires for If-None-Match, so W/ prefixes added by proxies are ignored.
+func Matches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
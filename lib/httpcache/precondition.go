@@ -0,0 +1,51 @@
+// FILE: lib/httpcache/precondition.go
+// Conditional update support: versioned documents are served with their version as the ETag, and
+// clients send it back in If-Match so an update made from a stale copy is refused instead of
+// overwriting someone else's change
+
+package httpcache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"wise-owl/lib/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VersionETag returns the entity tag of a document at version.
+func VersionETag(version int64) string {
+	return `"v` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ExpectedVersion returns the version an update is based on: the If-Match header's, or else
+// bodyVersion when the client sent one in the body. ok is false when the client sent neither, or
+// If-Match: *, and the update applies to whatever version is current. An If-Match naming anything
+// other than a version tag can never match and is refused with 412.
+func ExpectedVersion(c *gin.Context, bodyVersion *int64) (version int64, ok bool, err *apierror.Error) {
+	ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+	switch ifMatch {
+	case "":
+		if bodyVersion == nil {
+			return 0, false, nil
+		}
+		return *bodyVersion, true, nil
+	case "*":
+		return 0, false, nil
+	}
+
+	// Only a single strong tag can name a version; lists and weak tags never match
+	tag := strings.TrimSuffix(strings.TrimPrefix(ifMatch, `"v`), `"`)
+	version, parseErr := strconv.ParseInt(tag, 10, 64)
+	if parseErr != nil || VersionETag(version) != ifMatch {
+		return 0, false, VersionConflict()
+	}
+	return version, true, nil
+}
+
+// VersionConflict is the error for an update based on a version that is no longer current.
+func VersionConflict() *apierror.Error {
+	return apierror.New(http.StatusPreconditionFailed, "version_conflict", "This was changed on another device. Reload it and try again.")
+}
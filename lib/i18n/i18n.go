@@ -0,0 +1,89 @@
+// FILE: lib/i18n/i18n.go
+// This package picks the language of a request from its Accept-Language header and translates
+// user-facing texts. English is the source language: texts are written in English in the code and
+// looked up in the catalog of the requested language, falling back to the English text.
+
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Supported languages, as the primary subtag of a BCP 47 tag
+const (
+	English = "en"
+	Burmese = "my"
+)
+
+// Default is used when a request names no supported language
+const Default = English
+
+// Supported lists the languages with a catalog, plus the source language
+var Supported = []string{English, Burmese}
+
+//go:embed catalogs/*.json
+var catalogFiles embed.FS
+
+// catalogs maps a language to its translations, keyed by the English text
+var catalogs = map[string]map[string]string{}
+
+func init() {
+	for _, lang := range Supported {
+		data, err := catalogFiles.ReadFile("catalogs/" + lang + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalog for %s: %v", lang, err))
+		}
+		catalog := map[string]string{}
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog for %s: %v", lang, err))
+		}
+		catalogs[lang] = catalog
+	}
+}
+
+// IsSupported reports whether lang is one of Supported
+func IsSupported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Negotiate returns the supported language an Accept-Language header prefers most, e.g. "my" for
+// "my-MM,en;q=0.8". Tags are matched on their primary subtag; without a match it returns Default.
+// A single tag such as a profile locale ("my-MM") works too.
+func Negotiate(acceptLanguage string) string {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(strings.TrimSpace(fields[0]), "-")
+		primary = strings.ToLower(primary)
+		if !IsSupported(primary) {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.ReplaceAll(strings.TrimSpace(param), " ", ""), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		// Earlier tags win ties, as the header lists them in order of preference
+		if q > bestQ {
+			best, bestQ = primary, q
+		}
+	}
+	return best
+}
+
+// Translate returns text in lang, or text itself when the catalog has no translation for it
+func Translate(lang, text string) string {
+	if translated := catalogs[lang][text]; translated != "" {
+		return translated
+	}
+	return text
+}
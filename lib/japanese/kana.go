@@ -0,0 +1,43 @@
+// FILE: lib/japanese/kana.go
+// This package converts between hiragana, katakana, and romaji and compares readings loosely,
+// so learners can answer in whichever script or spelling they type most easily.
+
+package japanese
+
+import "strings"
+
+const (
+	// katakanaOffset is the distance between a hiragana and its katakana in Unicode.
+	katakanaOffset = 'ァ' - 'ぁ'
+)
+
+// ToHiragana converts katakana in s to hiragana. Other characters are kept.
+// Half-width katakana is widened first.
+func ToHiragana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ァ' && r <= 'ヶ' {
+			return r - katakanaOffset
+		}
+		return r
+	}, NormalizeWidth(s))
+}
+
+// ToKatakana converts hiragana in s to katakana. Other characters are kept.
+func ToKatakana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ぁ' && r <= 'ゖ' {
+			return r + katakanaOffset
+		}
+		return r
+	}, NormalizeWidth(s))
+}
+
+// IsKana reports whether r is a hiragana or katakana character, including the long vowel mark.
+func IsKana(r rune) bool {
+	return (r >= 'ぁ' && r <= 'ゖ') || (r >= 'ァ' && r <= 'ヺ') || r == 'ー'
+}
+
+// ContainsKana reports whether s has at least one kana character.
+func ContainsKana(s string) bool {
+	return strings.IndexFunc(NormalizeWidth(s), IsKana) >= 0
+}
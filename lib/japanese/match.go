@@ -0,0 +1,88 @@
+// FILE: lib/japanese/match.go
+// Loose comparison of readings typed in kana or romaji, or heard by speech recognition.
+
+package japanese
+
+import (
+	"strings"
+	"unicode"
+)
+
+// readingReplacer folds spellings of the same sound together in a romaji key.
+var readingReplacer = strings.NewReplacer(
+	// Long vowels: おお, おう, and ō all read as a long o
+	"ou", "o", "oo", "o", "aa", "a", "ii", "i", "uu", "u", "ee", "e",
+	// Hepburn "m" before labials (shimbun) is the same ん as "n"
+	"mb", "nb", "mp", "np", "mm", "nm",
+)
+
+// ReadingKey reduces a reading in kana or romaji to a key that is equal for
+// spellings learners treat as the same: script, width, case, spaces and punctuation,
+// macrons, and how long vowels are written are all ignored.
+// For example "ookii", "ōkii", "Ōkii", "おおきい", and "オーキー" all give "oki".
+func ReadingKey(s string) string {
+	romaji := strings.ToLower(KanaToRomaji(expandMacrons(NormalizeWidth(s))))
+
+	var b strings.Builder
+	b.Grow(len(romaji))
+	for _, r := range romaji {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	key := b.String()
+	for {
+		folded := readingReplacer.Replace(key)
+		if folded == key {
+			return key
+		}
+		key = folded
+	}
+}
+
+// FuzzyMatch reports whether two readings are the same once script, width, and
+// long vowel spelling are ignored. Empty readings never match.
+func FuzzyMatch(a, b string) bool {
+	keyA := ReadingKey(a)
+	return keyA != "" && keyA == ReadingKey(b)
+}
+
+// ReadingSimilarity scores how close two readings are once compared as by FuzzyMatch, from 0
+// (nothing in common, or either is empty) to 1 (the same reading): one minus the edits between
+// their keys over the length of the longer key.
+func ReadingSimilarity(a, b string) float64 {
+	keyA, keyB := ReadingKey(a), ReadingKey(b)
+	if keyA == "" || keyB == "" {
+		return 0
+	}
+	longest := max(len([]rune(keyA)), len([]rune(keyB)))
+	return 1 - float64(EditDistance(keyA, keyB))/float64(longest)
+}
+
+// EditDistance returns the number of single-character edits between a and b.
+// Swapping two neighbouring characters ("mael" for "meal") counts as one edit.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(ra)][len(rb)]
+}
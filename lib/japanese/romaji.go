@@ -0,0 +1,261 @@
+// FILE: lib/japanese/romaji.go
+// Hepburn romanization of kana, its Kunrei-shiki respelling, and the reverse conversion of typed romaji.
+
+package japanese
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// hiraganaRomaji is the modified Hepburn reading of each hiragana.
+var hiraganaRomaji = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ゔ': "vu",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+	'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo", 'ゎ': "wa",
+}
+
+// romajiKana maps typed romaji syllables to hiragana. Besides Hepburn it accepts the
+// common keyboard spellings (si, tu, zi, sya, ...) and x/l prefixes for small kana.
+var romajiKana = map[string]string{}
+
+// maxRomajiSyllable is the length of the longest key in romajiKana.
+var maxRomajiSyllable int
+
+func init() {
+	for kana, romaji := range hiraganaRomaji {
+		if strings.ContainsRune("ぁぃぅぇぉゃゅょゎゐゑぢづを", kana) {
+			continue // Small kana and duplicate readings are added below
+		}
+		romajiKana[romaji] = string(kana)
+	}
+
+	extra := map[string]string{
+		"si": "し", "ti": "ち", "tu": "つ", "hu": "ふ", "zi": "じ", "di": "ぢ", "du": "づ", "wo": "を",
+		"xa": "ぁ", "xi": "ぃ", "xu": "ぅ", "xe": "ぇ", "xo": "ぉ", "xya": "ゃ", "xyu": "ゅ", "xyo": "ょ", "xtu": "っ", "xtsu": "っ", "xwa": "ゎ",
+		"la": "ぁ", "li": "ぃ", "lu": "ぅ", "le": "ぇ", "lo": "ぉ", "lya": "ゃ", "lyu": "ゅ", "lyo": "ょ", "ltu": "っ", "ltsu": "っ", "lwa": "ゎ",
+		"fa": "ふぁ", "fi": "ふぃ", "fe": "ふぇ", "fo": "ふぉ", "va": "ゔぁ", "vi": "ゔぃ", "ve": "ゔぇ", "vo": "ゔぉ",
+		"she": "しぇ", "che": "ちぇ", "je": "じぇ", "tsa": "つぁ", "wi": "うぃ", "we": "うぇ",
+	}
+	for romaji, kana := range extra {
+		romajiKana[romaji] = kana
+	}
+
+	// Contracted sounds (yōon): ki + ya = kya, shi + ya = sha, and the kunrei-style spellings
+	yoon := map[string]string{"ya": "ゃ", "yu": "ゅ", "yo": "ょ"}
+	for _, base := range []string{"き", "し", "ち", "に", "ひ", "み", "り", "ぎ", "じ", "び", "ぴ"} {
+		stem := strings.TrimSuffix(hiraganaRomaji[[]rune(base)[0]], "i")
+		for small, kana := range yoon {
+			switch stem {
+			case "sh", "ch", "j":
+				romajiKana[stem+small[1:]] = base + kana
+			default:
+				romajiKana[stem+small] = base + kana
+			}
+		}
+	}
+	for _, stem := range []string{"sy", "ty", "zy", "jy", "cy"} {
+		base := map[string]string{"sy": "し", "ty": "ち", "zy": "じ", "jy": "じ", "cy": "ち"}[stem]
+		for small, kana := range yoon {
+			romajiKana[stem+small[1:]] = base + kana
+		}
+	}
+
+	for romaji := range romajiKana {
+		if len(romaji) > maxRomajiSyllable {
+			maxRomajiSyllable = len(romaji)
+		}
+	}
+}
+
+// KanaToRomaji romanizes hiragana and katakana using modified Hepburn, e.g. がっこう -> gakkou
+// and コーヒー -> koohii. Long vowels are spelled out rather than marked with macrons.
+// Characters that are not kana are kept as they are.
+func KanaToRomaji(s string) string {
+	runes := []rune(ToHiragana(s))
+	var b strings.Builder
+	b.Grow(len(runes) * 2)
+
+	geminate := false // A small tsu doubles the next consonant
+	lastVowel := byte(0)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch r {
+		case 'っ':
+			geminate = true
+			continue
+		case 'ー':
+			if lastVowel != 0 {
+				b.WriteByte(lastVowel)
+			}
+			continue
+		}
+
+		syllable, ok := hiraganaRomaji[r]
+		if !ok {
+			if geminate {
+				geminate = false
+			}
+			lastVowel = 0
+			b.WriteRune(r)
+			continue
+		}
+
+		// Combine with a following small kana: き+ゃ = kya, し+ゃ = sha, ふ+ぁ = fa
+		if i+1 < len(runes) {
+			next := runes[i+1]
+			switch {
+			case strings.ContainsRune("ゃゅょ", next) && strings.HasSuffix(syllable, "i") && len(syllable) > 1:
+				stem := strings.TrimSuffix(syllable, "i")
+				vowel := hiraganaRomaji[next][1:]
+				if stem == "sh" || stem == "ch" || stem == "j" {
+					syllable = stem + vowel
+				} else {
+					syllable = stem + "y" + vowel
+				}
+				i++
+			case strings.ContainsRune("ぁぃぅぇぉ", next) && len(syllable) > 1:
+				syllable = syllable[:len(syllable)-1] + hiraganaRomaji[next]
+				i++
+			case strings.ContainsRune("ぃぇ", next) && r == 'う':
+				syllable = "w" + hiraganaRomaji[next]
+				i++
+			}
+		}
+
+		if geminate {
+			geminate = false
+			if strings.HasPrefix(syllable, "ch") {
+				b.WriteByte('t')
+			} else if c := syllable[0]; !isVowel(c) && c != 'n' {
+				b.WriteByte(c)
+			}
+		}
+		b.WriteString(syllable)
+		lastVowel = syllable[len(syllable)-1]
+		if !isVowel(lastVowel) {
+			lastVowel = 0
+		}
+	}
+	return b.String()
+}
+
+// kunreiReplacer respells the Hepburn syllables that differ in Kunrei-shiki. Longer spellings come
+// first so "tchi" (っち) and "ffu" (っふ) keep their doubled consonant.
+var kunreiReplacer = strings.NewReplacer(
+	"tchi", "tti", "tcha", "ttya", "tchu", "ttyu", "tche", "ttye", "tcho", "ttyo",
+	"shi", "si", "sha", "sya", "shu", "syu", "she", "sye", "sho", "syo",
+	"chi", "ti", "cha", "tya", "chu", "tyu", "che", "tye", "cho", "tyo",
+	"ji", "zi", "ja", "zya", "ju", "zyu", "je", "zye", "jo", "zyo",
+	"tsu", "tu", "ffu", "hhu", "fu", "hu",
+)
+
+// HepburnToKunrei respells lowercase Hepburn romaji, as produced by KanaToRomaji, in Kunrei-shiki,
+// e.g. "chotto matte" -> "tyotto matte" and "shinbun" -> "sinbun". Kunrei spells じ and ぢ alike
+// ("zi"), as Hepburn does ("ji"), so the kana are not needed.
+func HepburnToKunrei(s string) string {
+	return kunreiReplacer.Replace(s)
+}
+
+// RomajiToKana converts typed romaji to hiragana, e.g. "gakkou" -> がっこう and "kon'nichiha" -> こんにちは.
+// Macrons are read as long vowels (ō -> おう). Characters that cannot be read as romaji, including kana, are kept.
+func RomajiToKana(s string) string {
+	s = strings.ToLower(expandMacrons(NormalizeWidth(s)))
+	var b strings.Builder
+	b.Grow(len(s) * 3)
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		if c == 'n' {
+			next := byte(0)
+			if i+1 < len(s) {
+				next = s[i+1]
+			}
+			switch {
+			case next == '\'':
+				b.WriteString("ん")
+				i += 2
+				continue
+			case next == 'n':
+				b.WriteString("ん")
+				i++
+				continue
+			case !isVowel(next) && next != 'y':
+				b.WriteString("ん")
+				i++
+				continue
+			}
+		}
+
+		// A doubled consonant (or "tch") is written with a small tsu
+		if i+1 < len(s) && isConsonant(c) && (s[i+1] == c || (c == 't' && s[i+1] == 'c')) {
+			b.WriteString("っ")
+			i++
+			continue
+		}
+
+		matched := false
+		for size := maxRomajiSyllable; size > 0; size-- {
+			if i+size > len(s) {
+				continue
+			}
+			if kana, ok := romajiKana[s[i:i+size]]; ok {
+				b.WriteString(kana)
+				i += size
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		if c == '-' {
+			b.WriteString("ー")
+			i++
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(s[i:])
+		b.WriteString(s[i : i+size])
+		i += size
+	}
+	return b.String()
+}
+
+// macronReplacer spells long vowels written with macrons or circumflexes in full.
+// ō and ô become "ou", which is how most long o sounds are written in kana.
+var macronReplacer = strings.NewReplacer(
+	"ā", "aa", "ī", "ii", "ū", "uu", "ē", "ee", "ō", "ou",
+	"â", "aa", "î", "ii", "û", "uu", "ê", "ee", "ô", "ou",
+	"Ā", "aa", "Ī", "ii", "Ū", "uu", "Ē", "ee", "Ō", "ou",
+)
+
+// expandMacrons applies macronReplacer.
+func expandMacrons(s string) string {
+	return macronReplacer.Replace(s)
+}
+
+func isVowel(c byte) bool {
+	return c == 'a' || c == 'i' || c == 'u' || c == 'e' || c == 'o'
+}
+
+func isConsonant(c byte) bool {
+	return c >= 'a' && c <= 'z' && !isVowel(c) && c != 'n'
+}
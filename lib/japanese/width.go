@@ -0,0 +1,78 @@
+// FILE: lib/japanese/width.go
+// Full-width and half-width normalization, so text typed on any keyboard compares equal.
+
+package japanese
+
+import "strings"
+
+// halfWidthKatakana maps U+FF66-U+FF9D to their full-width katakana.
+var halfWidthKatakana = []rune("ヲァィゥェォャュョッーアイウエオカキクケコサシスセソタチツテトナニヌネノハヒフヘホマミムメモヤユヨラリルレロワン")
+
+const (
+	halfWidthVoiced     = 'ﾞ' // U+FF9E
+	halfWidthSemiVoiced = 'ﾟ' // U+FF9F
+)
+
+// NormalizeWidth converts half-width katakana to full-width katakana and full-width ASCII
+// (letters, digits, punctuation, and the ideographic space) to plain ASCII.
+// Half-width voicing marks are combined with the preceding kana, so ｶﾞ becomes ガ.
+func NormalizeWidth(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r >= 0xFF01 && r <= 0xFF5E:
+			b.WriteRune(r - 0xFEE0)
+		case r == '　':
+			b.WriteRune(' ')
+		case r >= 0xFF66 && r <= 0xFF9D:
+			kana := halfWidthKatakana[r-0xFF66]
+			if i+1 < len(runes) {
+				if voiced, ok := voice(kana, runes[i+1]); ok {
+					kana = voiced
+					i++
+				}
+			}
+			b.WriteRune(kana)
+		case r == halfWidthVoiced:
+			b.WriteRune('゛')
+		case r == halfWidthSemiVoiced:
+			b.WriteRune('゜')
+		case r == 0xFF61:
+			b.WriteRune('。')
+		case r == 0xFF62:
+			b.WriteRune('「')
+		case r == 0xFF63:
+			b.WriteRune('」')
+		case r == 0xFF64:
+			b.WriteRune('、')
+		case r == 0xFF65:
+			b.WriteRune('・')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// voice applies a half-width voicing mark to a full-width katakana, if the pair is valid.
+func voice(kana, mark rune) (rune, bool) {
+	switch mark {
+	case halfWidthVoiced:
+		switch {
+		case kana == 'ウ':
+			return 'ヴ', true
+		case strings.ContainsRune("カキクケコサシスセソタチツテトハヒフヘホ", kana):
+			// In Unicode the voiced form directly follows each of these kana.
+			return kana + 1, true
+		}
+	case halfWidthSemiVoiced:
+		if strings.ContainsRune("ハヒフヘホ", kana) {
+			return kana + 2, true
+		}
+	}
+	return kana, false
+}
@@ -0,0 +1,188 @@
+// FILE: lib/jobs/cron.go
+// Cron expression parsing. Schedules are evaluated in UTC.
+
+package jobs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes when a job next runs
+type Schedule interface {
+	// Next returns the first run time strictly after t, or the zero time if there is none
+	Next(t time.Time) time.Time
+}
+
+// cronField is the set of allowed values of one field, indexed by value
+type cronField []bool
+
+// cronSchedule is a parsed five-field cron expression
+type cronSchedule struct {
+	minute, hour, dom, month, dow cronField
+	// Day of month and day of week are ORed when both are restricted, as in Vixie cron
+	domAny, dowAny bool
+}
+
+// everySchedule runs at fixed intervals aligned to a fixed origin, so every instance of a
+// service agrees on the run times regardless of when it started
+type everySchedule struct {
+	interval time.Duration
+}
+
+// descriptors are the named shorthands for common expressions
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses a standard five-field cron expression ("minute hour day-of-month month
+// day-of-week", supporting *, lists, ranges, and steps), a descriptor such as @daily, or
+// "@every <duration>" with a duration of at least a minute.
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %v", spec, err)
+		}
+		if interval < time.Minute {
+			return nil, fmt.Errorf("invalid schedule %q: interval must be at least a minute", spec)
+		}
+		return everySchedule{interval: interval}, nil
+	}
+	if expr, ok := descriptors[spec]; ok {
+		spec = expr
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	var s cronSchedule
+	var err error
+	if s.minute, err = parseField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("invalid minute in %q: %v", spec, err)
+	}
+	if s.hour, err = parseField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("invalid hour in %q: %v", spec, err)
+	}
+	if s.dom, err = parseField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("invalid day of month in %q: %v", spec, err)
+	}
+	if s.month, err = parseField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("invalid month in %q: %v", spec, err)
+	}
+	// 7 is accepted as Sunday and folded into 0
+	if s.dow, err = parseField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("invalid day of week in %q: %v", spec, err)
+	}
+	if s.dow[7] {
+		s.dow[0] = true
+	}
+	s.domAny = strings.HasPrefix(fields[2], "*")
+	s.dowAny = strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// parseField parses a comma-separated list of "*", "n", "a-b", each optionally followed by "/step"
+func parseField(field string, min, max int) (cronField, error) {
+	allowed := make(cronField, max+1)
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return nil, fmt.Errorf("bad step %q", stepPart)
+			}
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var errA, errB error
+			lo, errA = strconv.Atoi(a)
+			hi, errB = strconv.Atoi(b)
+			if errA != nil || errB != nil || lo > hi {
+				return nil, fmt.Errorf("bad range %q", rangePart)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return nil, fmt.Errorf("bad value %q", rangePart)
+			}
+			lo = n
+			if !hasStep {
+				hi = n
+			}
+		}
+		if lo < min || hi > max {
+			return nil, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			allowed[v] = true
+		}
+	}
+	return allowed, nil
+}
+
+// Next finds the next matching minute, skipping whole months, days, and hours that cannot match
+func (s cronSchedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	// Expressions such as "0 0 30 2 *" never match; give up after five years
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !s.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.hour[t.Hour()] {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if !s.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches applies the day-of-month and day-of-week fields
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom[t.Day()]
+	dow := s.dow[int(t.Weekday())]
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// Next returns the next time that is a whole number of intervals since Go's zero time, which
+// lines up with midnight UTC for intervals that divide a day
+func (s everySchedule) Next(t time.Time) time.Time {
+	return t.UTC().Truncate(s.interval).Add(s.interval)
+}
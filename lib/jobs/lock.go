@@ -0,0 +1,75 @@
+// FILE: lib/jobs/lock.go
+// Leases that let only one instance of a service run each scheduled job
+
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"wise-owl/lib/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LeaseCollection is the collection MongoLocker keeps its leases in
+const LeaseCollection = "job_leases"
+
+// Locker grants the run of a job for one scheduled time to a single instance
+type Locker interface {
+	// Acquire claims the run of job for scheduledFor. It returns false when another instance
+	// already claimed that run or still holds an unexpired lease from an earlier one.
+	Acquire(ctx context.Context, job, owner string, scheduledFor time.Time, ttl time.Duration) (bool, error)
+	// Release ends the lease early once the run has finished
+	Release(ctx context.Context, job, owner string) error
+}
+
+// MongoLocker keeps one lease document per job in LeaseCollection:
+//
+//	{_id: job, owner, scheduled_for, acquired_at, expires_at}
+//
+// A run is claimed by moving the document's
+// scheduled_for forward; the unique _id makes concurrent claims for the same run fail.
+type MongoLocker struct {
+	collection database.CollectionInterface
+}
+
+// NewMongoLocker creates a locker over the lease collection
+func NewMongoLocker(collection database.CollectionInterface) *MongoLocker {
+	return &MongoLocker{collection: collection}
+}
+
+// Acquire claims the run if no earlier run's lease is still live and nobody claimed this one
+func (l *MongoLocker) Acquire(ctx context.Context, job, owner string, scheduledFor time.Time, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	filter := bson.M{
+		"_id":           job,
+		"scheduled_for": bson.M{"$lt": scheduledFor},
+		"expires_at":    bson.M{"$lte": now},
+	}
+	// _id comes from the filter on insert and cannot be set on an existing document
+	update := bson.M{"$set": bson.M{
+		"owner":         owner,
+		"scheduled_for": scheduledFor,
+		"acquired_at":   now,
+		"expires_at":    now.Add(ttl),
+	}}
+
+	// With no matching document the upsert inserts one, which fails on _id if the job already has a lease
+	_, err := l.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Release expires the lease if owner still holds it. The run stays claimed.
+func (l *MongoLocker) Release(ctx context.Context, job, owner string) error {
+	_, err := l.collection.UpdateOne(ctx,
+		bson.M{"_id": job, "owner": owner},
+		bson.M{"$set": bson.M{"expires_at": time.Now().UTC()}},
+	)
+	return err
+}
@@ -0,0 +1,146 @@
+// FILE: lib/jobs/scheduler.go
+// Runs background jobs on cron schedules, one instance per run, with panic recovery
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// defaultJobTimeout bounds a run when the job does not set its own timeout
+const defaultJobTimeout = 10 * time.Minute
+
+// Job is a unit of background work
+type Job struct {
+	Name string
+	// Schedule is a cron expression, a descriptor such as @daily, or "@every 1h"; see ParseSchedule
+	Schedule string
+	// Timeout bounds a run and is also how long its lease lasts (default 10 minutes)
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs on their schedules. With a Locker, each scheduled run happens
+// on only one of the service's instances; without one, every instance runs every job.
+type Scheduler struct {
+	locker Locker
+	owner  string
+
+	mu   sync.Mutex
+	jobs []scheduledJob
+}
+
+// scheduledJob is a job with its parsed schedule
+type scheduledJob struct {
+	Job
+	schedule Schedule
+}
+
+// NewScheduler creates a scheduler. locker may be nil.
+func NewScheduler(locker Locker) *Scheduler {
+	hostname, _ := os.Hostname()
+	return &Scheduler{
+		locker: locker,
+		owner:  fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}
+}
+
+// Add registers a job. Jobs added after Start are not run.
+func (s *Scheduler) Add(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return fmt.Errorf("job name and run function are required")
+	}
+	schedule, err := ParseSchedule(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("job %s: %v", job.Name, err)
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = defaultJobTimeout
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, scheduledJob{Job: job, schedule: schedule})
+	return nil
+}
+
+// Start runs every registered job on its schedule until ctx is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	jobs := append([]scheduledJob(nil), s.jobs...)
+	s.mu.Unlock()
+
+	for _, job := range jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+// loop waits for each scheduled time of a job and runs it
+func (s *Scheduler) loop(ctx context.Context, job scheduledJob) {
+	for {
+		next := job.schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("WARN: Job %s has no upcoming run time; stopping it", job.Name)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.runScheduled(ctx, job, next)
+	}
+}
+
+// runScheduled claims the run for scheduledFor and runs the job if this instance got it
+func (s *Scheduler) runScheduled(ctx context.Context, job scheduledJob, scheduledFor time.Time) {
+	if s.locker != nil {
+		acquired, err := s.locker.Acquire(ctx, job.Name, s.owner, scheduledFor, job.Timeout)
+		if err != nil {
+			log.Printf("WARN: Job %s: failed to acquire lease: %v", job.Name, err)
+			return
+		}
+		if !acquired {
+			return
+		}
+		defer func() {
+			if err := s.locker.Release(context.Background(), job.Name, s.owner); err != nil {
+				log.Printf("WARN: Job %s: failed to release lease: %v", job.Name, err)
+			}
+		}()
+	}
+
+	start := time.Now()
+	if err := s.RunNow(ctx, job.Job); err != nil {
+		log.Printf("WARN: Job %s failed after %s: %v", job.Name, time.Since(start).Round(time.Millisecond), err)
+		return
+	}
+	log.Printf("Job %s completed in %s", job.Name, time.Since(start).Round(time.Millisecond))
+}
+
+// RunNow runs a job once under its timeout, turning a panic into an error. It does not take a lease.
+func (s *Scheduler) RunNow(ctx context.Context, job Job) (err error) {
+	timeout := job.Timeout
+	if timeout <= 0 {
+		timeout = defaultJobTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("ERROR: Job %s panicked: %v\n%s", job.Name, r, debug.Stack())
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
@@ -0,0 +1,174 @@
+// FILE: lib/metrics/emf.go
+// CloudWatch Embedded Metric Format: metrics written as structured log lines, which CloudWatch Logs
+// turns into CloudWatch metrics. In ECS the awslogs driver ships them like any other output, so no
+// agent or Prometheus server is needed.
+
+package metrics
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultNamespace is the CloudWatch namespace metrics are published under
+	DefaultNamespace = "WiseOwl"
+	// flushInterval matches CloudWatch's standard one-minute resolution
+	flushInterval = time.Minute
+	// maxValues is the most values EMF accepts for one metric in one line
+	maxValues = 100
+)
+
+// Unit is a CloudWatch metric unit.
+type Unit string
+
+const (
+	Milliseconds Unit = "Milliseconds"
+	Count        Unit = "Count"
+)
+
+// Dimension is a name and value a metric is broken down by.
+type Dimension struct {
+	Name  string
+	Value string
+}
+
+// series is the values of one metric and set of dimensions recorded since the last flush
+type series struct {
+	name   string
+	unit   Unit
+	dims   []Dimension
+	values []float64
+}
+
+// EMF collects metrics and writes them as Embedded Metric Format lines every minute. Every
+// metric carries a Service dimension, and is also published with Service as its only dimension,
+// so a service's totals can be graphed without summing every breakdown. It is safe for
+// concurrent use.
+type EMF struct {
+	namespace string
+	service   string
+	out       io.Writer
+
+	mu     sync.Mutex
+	series map[string]*series
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewEMF creates an emitter that writes service's metrics to standard output, and starts
+// flushing them every minute until Close.
+func NewEMF(namespace, service string) *EMF {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	e := &EMF{
+		namespace: namespace,
+		service:   service,
+		out:       os.Stdout,
+		series:    make(map[string]*series),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go e.run()
+	log.Printf("Publishing CloudWatch metrics to namespace %s", namespace)
+	return e
+}
+
+// Record adds one value of a metric. Values of the same metric and dimensions are written
+// together, as one line per flush.
+func (e *EMF) Record(name string, unit Unit, value float64, dims ...Dimension) {
+	key := seriesKey(name, dims)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	s, ok := e.series[key]
+	if !ok {
+		s = &series{name: name, unit: unit, dims: dims}
+		e.series[key] = s
+	}
+	s.values = append(s.values, value)
+	if len(s.values) == maxValues {
+		e.write(s)
+		s.values = s.values[:0]
+	}
+}
+
+// Flush writes every metric recorded since the last flush.
+func (e *EMF) Flush() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for key, s := range e.series {
+		if len(s.values) > 0 {
+			e.write(s)
+		}
+		delete(e.series, key)
+	}
+}
+
+// Close stops the periodic flush and writes what is left.
+func (e *EMF) Close() {
+	close(e.stop)
+	<-e.done
+	e.Flush()
+}
+
+// run flushes every flushInterval until Close
+func (e *EMF) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			e.Flush()
+		case <-e.stop:
+			return
+		}
+	}
+}
+
+// write prints one EMF line for s; the caller holds e.mu
+func (e *EMF) write(s *series) {
+	names := []string{"Service"}
+	doc := map[string]interface{}{"Service": e.service, s.name: s.values}
+	for _, dim := range s.dims {
+		names = append(names, dim.Name)
+		doc[dim.Name] = dim.Value
+	}
+	dimensionSets := [][]string{names}
+	if len(names) > 1 {
+		dimensionSets = append(dimensionSets, []string{"Service"})
+	}
+	doc["_aws"] = map[string]interface{}{
+		"Timestamp": time.Now().UnixMilli(),
+		"CloudWatchMetrics": []map[string]interface{}{{
+			"Namespace":  e.namespace,
+			"Dimensions": dimensionSets,
+			"Metrics":    []map[string]string{{"Name": s.name, "Unit": string(s.unit)}},
+		}},
+	}
+
+	line, err := json.Marshal(doc)
+	if err != nil {
+		log.Printf("WARN: Failed to encode metric %s: %v", s.name, err)
+		return
+	}
+	e.out.Write(append(line, '\n'))
+}
+
+// seriesKey identifies a metric and its dimension values, in any order
+func seriesKey(name string, dims []Dimension) string {
+	parts := make([]string, 0, len(dims))
+	for _, dim := range dims {
+		parts = append(parts, dim.Name+"="+dim.Value)
+	}
+	sort.Strings(parts)
+	return name + "|" + strings.Join(parts, "|")
+}
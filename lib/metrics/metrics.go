@@ -0,0 +1,122 @@
+// FILE: lib/metrics/metrics.go
+// The metrics every service publishes when EMF_METRICS_ENABLED is set: HTTP request latency,
+// MongoDB command latency, and gRPC errors. The hooks are installed unconditionally and do
+// nothing until Enable is called.
+
+package metrics
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"wise-owl/lib/config"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/event"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// active is the emitter set by Enable; nil while metrics are off
+var active atomic.Pointer[EMF]
+
+// Enable publishes the process's metrics through e.
+func Enable(e *EMF) {
+	active.Store(e)
+}
+
+// Start enables EMF metrics for service when cfg turns them on, and returns the function that
+// writes the last of them on shutdown.
+func Start(cfg config.MetricsConfig, service string) (stop func()) {
+	if !cfg.EMF {
+		return func() {}
+	}
+	e := NewEMF(cfg.Namespace, service)
+	Enable(e)
+	return e.Close
+}
+
+// record adds a value to the active emitter, if there is one
+func record(name string, unit Unit, value float64, dims ...Dimension) {
+	if e := active.Load(); e != nil {
+		e.Record(name, unit, value, dims...)
+	}
+}
+
+// milliseconds converts d to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// Middleware records RequestLatency for every request by route pattern (e.g. /api/v1/users/:id),
+// so IDs in paths do not each become a metric. Health checks are left out, since load balancers
+// call them continuously.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if active.Load() == nil {
+			c.Next()
+			return
+		}
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		if strings.HasPrefix(route, "/health") {
+			return
+		}
+		record("RequestLatency", Milliseconds, milliseconds(time.Since(start)), Dimension{Name: "Route", Value: route})
+	}
+}
+
+// MongoMonitor returns a driver command monitor that records MongoLatency for every command by
+// command name (find, insert, aggregate, ...) and counts failed ones as MongoErrors.
+func MongoMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			record("MongoLatency", Milliseconds, milliseconds(e.Duration), Dimension{Name: "Command", Value: e.CommandName})
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			command := Dimension{Name: "Command", Value: e.CommandName}
+			record("MongoLatency", Milliseconds, milliseconds(e.Duration), command)
+			record("MongoErrors", Count, 1, command)
+		},
+	}
+}
+
+// UnaryServerInterceptor counts calls a server fails as GRPCErrors, by method and status code
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		recordGRPCError("server", info.FullMethod, err)
+		return resp, err
+	}
+}
+
+// UnaryClientInterceptor counts failed calls to other services as GRPCErrors, by method and
+// status code
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		recordGRPCError("client", method, err)
+		return err
+	}
+}
+
+// recordGRPCError counts err unless the call succeeded
+func recordGRPCError(side, method string, err error) {
+	code := status.Code(err)
+	if code == codes.OK {
+		return
+	}
+	record("GRPCErrors", Count, 1,
+		Dimension{Name: "Side", Value: side},
+		Dimension{Name: "Method", Value: method},
+		Dimension{Name: "Code", Value: code.String()},
+	)
+}
@@ -0,0 +1,364 @@
+// FILE: lib/metrics/slo.go
+// Service level objectives per route: each service declares how available and how fast its key
+// routes must be, and SLOs tracks how much of the error budget the last 24 hours used and how fast
+// it is burning, for /health/slo and /metrics.
+
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// budgetWindow is the rolling window error budgets are measured over. Counts are kept per
+	// process, so each task measures its own share of the traffic since it started.
+	budgetWindow = 24 * time.Hour
+	// budgetBuckets is the number of one-minute buckets budgetWindow is kept in
+	budgetBuckets = int(budgetWindow / time.Minute)
+)
+
+// burnWindows are the windows burn rates are reported for
+var burnWindows = []time.Duration{5 * time.Minute, 30 * time.Minute, time.Hour, 2 * time.Hour, 6 * time.Hour, 24 * time.Hour}
+
+// burnAlert fires when the burn rate exceeds Rate over both windows: the long one shows the
+// budget is really being spent, and the short one that it still is. The rates are the
+// multiwindow thresholds of the Google SRE workbook for a 30-day objective.
+type burnAlert struct {
+	severity    string
+	long, short time.Duration
+	rate        float64
+}
+
+var burnAlerts = []burnAlert{
+	{severity: "page", long: time.Hour, short: 5 * time.Minute, rate: 14.4},
+	{severity: "page", long: 6 * time.Hour, short: 30 * time.Minute, rate: 6},
+	{severity: "ticket", long: 24 * time.Hour, short: 2 * time.Hour, rate: 3},
+}
+
+// Objective is what a route promises. Either target may be left zero to leave it untracked.
+type Objective struct {
+	Method string // HTTP method, e.g. GET
+	Route  string // Gin route pattern, e.g. /api/v1/lessons/:lessonId
+
+	// Availability is the fraction of requests that must not fail with a 5xx, e.g. 0.999.
+	// Client errors are the caller's doing and do not spend the budget.
+	Availability float64
+	// LatencyTarget is the fraction of requests that must finish within Latency, e.g. 0.99
+	// within 300ms
+	Latency       time.Duration
+	LatencyTarget float64
+}
+
+// sloCounts are the requests to a route and how many missed each objective
+type sloCounts struct {
+	total  uint64
+	errors uint64
+	slow   uint64
+}
+
+func (c *sloCounts) add(other sloCounts) {
+	c.total += other.total
+	c.errors += other.errors
+	c.slow += other.slow
+}
+
+// sloBucket holds the counts of one minute
+type sloBucket struct {
+	minute int64 // Unix minute the counts belong to
+	sloCounts
+}
+
+// routeSLO tracks one objective
+type routeSLO struct {
+	Objective
+	buckets  []sloBucket // Ring of the last budgetBuckets minutes
+	lifetime sloCounts   // Since the process started, for Prometheus counters
+}
+
+// record counts one request finished at now
+func (r *routeSLO) record(now time.Time, failed, slow bool) {
+	minute := now.Unix() / 60
+	b := &r.buckets[minute%int64(budgetBuckets)]
+	if b.minute != minute {
+		*b = sloBucket{minute: minute}
+	}
+	counts := sloCounts{total: 1}
+	if failed {
+		counts.errors = 1
+	}
+	if slow {
+		counts.slow = 1
+	}
+	b.add(counts)
+	r.lifetime.add(counts)
+}
+
+// counts sums the requests of the window ending at now
+func (r *routeSLO) counts(now time.Time, window time.Duration) sloCounts {
+	var sum sloCounts
+	minute := now.Unix() / 60
+	for i := int64(0); i < int64(window/time.Minute) && i < int64(budgetBuckets); i++ {
+		b := r.buckets[(minute-i)%int64(budgetBuckets)]
+		if b.minute == minute-i {
+			sum.add(b.sloCounts)
+		}
+	}
+	return sum
+}
+
+// SLOs tracks the objectives of one service's routes. It is safe for concurrent use.
+type SLOs struct {
+	service string
+	now     func() time.Time
+
+	mu     sync.Mutex
+	routes map[string]*routeSLO // By "METHOD route"
+	order  []*routeSLO          // In declaration order, for stable output
+}
+
+// NewSLOs tracks objectives for service. Objectives with targets outside (0, 1) are logged and
+// skipped.
+func NewSLOs(service string, objectives ...Objective) *SLOs {
+	s := &SLOs{service: service, now: time.Now, routes: make(map[string]*routeSLO)}
+	for _, objective := range objectives {
+		if err := objective.validate(); err != nil {
+			log.Printf("WARN: Skipping objective for %s %s: %v", objective.Method, objective.Route, err)
+			continue
+		}
+		r := &routeSLO{Objective: objective, buckets: make([]sloBucket, budgetBuckets)}
+		s.routes[objective.Method+" "+objective.Route] = r
+		s.order = append(s.order, r)
+	}
+	return s
+}
+
+// validate checks the targets are fractions that leave some budget
+func (o Objective) validate() error {
+	if o.Availability == 0 && o.Latency == 0 {
+		return fmt.Errorf("no availability or latency target")
+	}
+	if o.Availability < 0 || o.Availability >= 1 {
+		return fmt.Errorf("availability %v must be between 0 and 1", o.Availability)
+	}
+	if o.Latency > 0 && (o.LatencyTarget <= 0 || o.LatencyTarget >= 1) {
+		return fmt.Errorf("latency target %v must be between 0 and 1", o.LatencyTarget)
+	}
+	return nil
+}
+
+// Middleware records every request to a route with an objective. It must run outside
+// apierror.Middleware, which renders handler errors after the handler returns, so the status it
+// records is the one sent. A panicking handler counts as a failed request.
+func (s *SLOs) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				s.record(c.Request.Method, c.FullPath(), http.StatusInternalServerError, time.Since(start))
+				panic(recovered)
+			}
+			s.record(c.Request.Method, c.FullPath(), c.Writer.Status(), time.Since(start))
+		}()
+		c.Next()
+	}
+}
+
+// record counts a finished request if its route has an objective
+func (s *SLOs) record(method, route string, status int, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.routes[method+" "+route]
+	if !ok {
+		return
+	}
+	r.record(s.now(), status >= http.StatusInternalServerError, r.Latency > 0 && elapsed > r.Latency)
+}
+
+// SLIStatus is how one objective of a route is doing over the budget window.
+type SLIStatus struct {
+	Objective       float64            `json:"objective"`
+	ThresholdMs     float64            `json:"threshold_ms,omitempty"` // Latency objectives only
+	Requests        uint64             `json:"requests"`
+	Bad             uint64             `json:"bad"`              // Failed, or slower than the threshold
+	BudgetRemaining float64            `json:"budget_remaining"` // 1 is untouched; 0 or less is spent
+	BurnRates       map[string]float64 `json:"burn_rates"`       // 1 spends the budget exactly over the window
+	Alert           string             `json:"alert"`            // "none", "ticket", or "page"
+}
+
+// SLOStatus is how a route is doing against its objectives.
+type SLOStatus struct {
+	Method       string     `json:"method"`
+	Route        string     `json:"route"`
+	Availability *SLIStatus `json:"availability,omitempty"`
+	Latency      *SLIStatus `json:"latency,omitempty"`
+}
+
+// Status reports every objective, in the order they were declared.
+func (s *SLOs) Status() []SLOStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	statuses := make([]SLOStatus, 0, len(s.order))
+	for _, r := range s.order {
+		status := SLOStatus{Method: r.Method, Route: r.Route}
+		if r.Availability > 0 {
+			status.Availability = sliStatus(r, now, r.Availability, func(c sloCounts) uint64 { return c.errors })
+		}
+		if r.Latency > 0 {
+			status.Latency = sliStatus(r, now, r.LatencyTarget, func(c sloCounts) uint64 { return c.slow })
+			status.Latency.ThresholdMs = milliseconds(r.Latency)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// sliStatus measures one objective of r, counting the requests bad picks as missing it
+func sliStatus(r *routeSLO, now time.Time, objective float64, bad func(sloCounts) uint64) *SLIStatus {
+	allowed := 1 - objective
+	burnRate := func(window time.Duration) float64 {
+		counts := r.counts(now, window)
+		if counts.total == 0 {
+			return 0
+		}
+		return float64(bad(counts)) / float64(counts.total) / allowed
+	}
+
+	budget := r.counts(now, budgetWindow)
+	status := &SLIStatus{
+		Objective:       objective,
+		Requests:        budget.total,
+		Bad:             bad(budget),
+		BudgetRemaining: 1,
+		BurnRates:       make(map[string]float64, len(burnWindows)),
+		Alert:           "none",
+	}
+	if budget.total > 0 {
+		status.BudgetRemaining = 1 - float64(bad(budget))/(float64(budget.total)*allowed)
+	}
+	for _, window := range burnWindows {
+		status.BurnRates[windowLabel(window)] = burnRate(window)
+	}
+	for _, alert := range burnAlerts {
+		if status.BurnRates[windowLabel(alert.long)] > alert.rate && status.BurnRates[windowLabel(alert.short)] > alert.rate {
+			status.Alert = alert.severity
+			break
+		}
+	}
+	return status
+}
+
+// StatusHandler serves Status as JSON, for /health/slo
+func (s *SLOs) StatusHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"service":    s.service,
+			"window":     windowLabel(budgetWindow),
+			"objectives": s.Status(),
+			"timestamp":  time.Now().UTC(),
+		})
+	}
+}
+
+// RegisterRoutes mounts StatusHandler at /health/slo
+func (s *SLOs) RegisterRoutes(router *gin.Engine) {
+	router.GET("/health/slo", s.StatusHandler())
+}
+
+// WriteTo writes the objectives in the Prometheus text exposition format: lifetime request
+// counters, for burn-rate alert rules across every task of a service, and this process's budget
+// and burn rates as gauges.
+func (s *SLOs) WriteTo(w io.Writer) (int64, error) {
+	statuses := s.Status()
+	s.mu.Lock()
+	lifetime := make([]sloCounts, len(s.order))
+	for i, r := range s.order {
+		lifetime[i] = r.lifetime
+	}
+	s.mu.Unlock()
+
+	var b strings.Builder
+	counters := []struct {
+		name, help string
+		value      func(sloCounts) uint64
+	}{
+		{"slo_requests_total", "Requests to routes with an objective.", func(c sloCounts) uint64 { return c.total }},
+		{"slo_errors_total", "Requests to routes with an objective that failed with a 5xx.", func(c sloCounts) uint64 { return c.errors }},
+		{"slo_slow_requests_total", "Requests to routes with a latency objective that took longer than its threshold.", func(c sloCounts) uint64 { return c.slow }},
+	}
+	for _, counter := range counters {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", counter.name, counter.help, counter.name)
+		for i, status := range statuses {
+			fmt.Fprintf(&b, "%s{method=%q,route=%q} %d\n", counter.name, status.Method, status.Route, counter.value(lifetime[i]))
+		}
+	}
+
+	b.WriteString("# HELP slo_objective Target fraction of good requests.\n# TYPE slo_objective gauge\n")
+	forEachSLI(statuses, func(labels string, sli *SLIStatus) {
+		fmt.Fprintf(&b, "slo_objective{%s} %g\n", labels, sli.Objective)
+	})
+	b.WriteString("# HELP slo_error_budget_remaining Fraction of the error budget left over the last 24 hours.\n# TYPE slo_error_budget_remaining gauge\n")
+	forEachSLI(statuses, func(labels string, sli *SLIStatus) {
+		fmt.Fprintf(&b, "slo_error_budget_remaining{%s} %g\n", labels, sli.BudgetRemaining)
+	})
+	b.WriteString("# HELP slo_burn_rate Rate the error budget is spent at; 1 spends it exactly.\n# TYPE slo_burn_rate gauge\n")
+	forEachSLI(statuses, func(labels string, sli *SLIStatus) {
+		for _, window := range burnWindows {
+			label := windowLabel(window)
+			fmt.Fprintf(&b, "slo_burn_rate{%s,window=%q} %g\n", labels, label, sli.BurnRates[label])
+		}
+	})
+	b.WriteString("# HELP slo_alert Whether the burn rate calls for a page or a ticket.\n# TYPE slo_alert gauge\n")
+	forEachSLI(statuses, func(labels string, sli *SLIStatus) {
+		for _, severity := range []string{"page", "ticket"} {
+			firing := 0
+			if sli.Alert == severity {
+				firing = 1
+			}
+			fmt.Fprintf(&b, "slo_alert{%s,severity=%q} %d\n", labels, severity, firing)
+		}
+	})
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// forEachSLI calls fn with the labels and status of every tracked objective
+func forEachSLI(statuses []SLOStatus, fn func(labels string, sli *SLIStatus)) {
+	for _, status := range statuses {
+		if status.Availability != nil {
+			fn(fmt.Sprintf("method=%q,route=%q,sli=\"availability\"", status.Method, status.Route), status.Availability)
+		}
+		if status.Latency != nil {
+			fn(fmt.Sprintf("method=%q,route=%q,sli=\"latency\"", status.Method, status.Route), status.Latency)
+		}
+	}
+}
+
+// windowLabel formats a window as Prometheus does, e.g. 5m or 6h
+func windowLabel(window time.Duration) string {
+	if window%time.Hour == 0 {
+		return fmt.Sprintf("%dh", window/time.Hour)
+	}
+	return fmt.Sprintf("%dm", window/time.Minute)
+}
+
+// Handler serves sources in the Prometheus text exposition format, one after another, e.g. a
+// service's gRPC metrics and its SLOs on one /metrics endpoint.
+func Handler(sources ...io.WriterTo) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		c.Status(http.StatusOK)
+		for _, source := range sources {
+			source.WriteTo(c.Writer)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// FILE: lib/openapi/openapi.go
+// Serves a service's OpenAPI document and, in development, a Swagger UI page
+
+package openapi
+
+import (
+	"encoding/json"
+	"html/template"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// swaggerUIVersion pins the Swagger UI assets loaded from the CDN
+const swaggerUIVersion = "5.17.14"
+
+var swaggerUIPage = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{.Title}} - API Docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@{{.Version}}/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@{{.Version}}/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`))
+
+// RegisterRoutes serves spec at /openapi.json and, when enableUI is set, a Swagger UI at /docs.
+// The spec is validated as JSON once at startup so a broken document fails fast.
+func RegisterRoutes(router *gin.Engine, spec []byte, enableUI bool) {
+	var document struct {
+		Info struct {
+			Title string `json:"title"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(spec, &document); err != nil {
+		log.Fatalf("FATAL: invalid OpenAPI document: %v", err)
+	}
+
+	router.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+	})
+
+	if !enableUI {
+		return
+	}
+	router.GET("/docs", func(c *gin.Context) {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusOK)
+		swaggerUIPage.Execute(c.Writer, gin.H{"Title": document.Info.Title, "Version": swaggerUIVersion})
+	})
+	log.Printf("Swagger UI enabled at /docs for %s", document.Info.Title)
+}
@@ -0,0 +1,129 @@
+// FILE: lib/preset/preset.go
+// This package sets up a service's HTTP router and gRPC server for the environment it runs in:
+// verbose and revealing in development, quiet and guarded everywhere else.
+
+package preset
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"wise-owl/lib/apierror"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// Preset is how a service behaves in one kind of environment.
+type Preset struct {
+	Name    string
+	GinMode string
+	// JSONLogs writes one JSON object per request instead of Gin's text lines, for CloudWatch Logs Insights
+	JSONLogs bool
+	// DebugErrors sends the cause of server errors, and a panic's stack, to the client
+	DebugErrors bool
+	// Reflection lets tools such as grpcurl list and call the gRPC API without its .proto files
+	Reflection bool
+}
+
+var (
+	// Development is for running services on a laptop or in docker compose.
+	Development = Preset{
+		Name:        "development",
+		GinMode:     gin.DebugMode,
+		DebugErrors: true,
+		Reflection:  true,
+	}
+	// Production is for every deployed environment, staging included, so staging behaves like production.
+	Production = Preset{
+		Name:     "production",
+		GinMode:  gin.ReleaseMode,
+		JSONLogs: true,
+	}
+)
+
+// For returns the preset of an ENVIRONMENT: Development for "development" and "local", and
+// Production for anything else.
+func For(environment string) Preset {
+	switch environment {
+	case "development", "local":
+		return Development
+	default:
+		return Production
+	}
+}
+
+// Router creates a Gin engine with the preset's mode, request logging, and error detail. Panics
+// in handlers are answered with a 500 in the standard error envelope in every preset.
+func (p Preset) Router() *gin.Engine {
+	gin.SetMode(p.GinMode)
+	apierror.SetDebug(p.DebugErrors)
+	log.Printf("Using the %s preset", p.Name)
+
+	router := gin.New()
+	if p.JSONLogs {
+		router.Use(gin.LoggerWithFormatter(jsonRequestLog))
+	} else {
+		router.Use(gin.LoggerWithFormatter(textRequestLog))
+	}
+	router.Use(apierror.Recovery())
+	return router
+}
+
+// RegisterReflection registers gRPC server reflection on s when the preset allows it.
+func (p Preset) RegisterReflection(s *grpc.Server) {
+	if p.Reflection {
+		reflection.Register(s)
+	}
+}
+
+// textRequestLog formats a request like Gin's default logger, followed by its trace ID
+func textRequestLog(param gin.LogFormatterParams) string {
+	line := fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		param.StatusCodeColor(), param.StatusCode, param.ResetColor(),
+		param.Latency,
+		param.ClientIP,
+		param.MethodColor(), param.Method, param.ResetColor(),
+		param.Path,
+	)
+	if traceID := apierror.TraceIDFromContext(param.Request.Context()); traceID != "" {
+		line += " trace_id=" + traceID
+	}
+	if param.ErrorMessage != "" {
+		line += "\n" + param.ErrorMessage
+	}
+	return line + "\n"
+}
+
+// requestLog is one line of the JSON request log
+type requestLog struct {
+	Time      string  `json:"time"`
+	Method    string  `json:"method"`
+	Path      string  `json:"path"`
+	Status    int     `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	ClientIP  string  `json:"client_ip"`
+	BodySize  int     `json:"body_size"`
+	TraceID   string  `json:"trace_id,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// jsonRequestLog formats a request as a JSON line
+func jsonRequestLog(param gin.LogFormatterParams) string {
+	line, _ := json.Marshal(requestLog{
+		Time:      param.TimeStamp.UTC().Format(time.RFC3339Nano),
+		Method:    param.Method,
+		Path:      param.Path,
+		Status:    param.StatusCode,
+		LatencyMS: float64(param.Latency.Microseconds()) / 1000,
+		ClientIP:  param.ClientIP,
+		BodySize:  param.BodySize,
+		TraceID:   apierror.TraceIDFromContext(param.Request.Context()),
+		Error:     param.ErrorMessage,
+	})
+	return string(line) + "\n"
+}
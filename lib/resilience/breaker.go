@@ -0,0 +1,225 @@
+// FILE: lib/resilience/breaker.go
+// Circuit breaker that fails calls to a dependency fast once it keeps failing
+
+package resilience
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// State is the position of a circuit breaker
+type State string
+
+const (
+	StateClosed   State = "closed"    // Calls pass through
+	StateOpen     State = "open"      // Calls are rejected until OpenTimeout passes
+	StateHalfOpen State = "half_open" // A limited number of trial calls decide whether to close again
+)
+
+// ErrOpen is returned instead of calling through while the breaker is open
+var ErrOpen = errors.New("circuit breaker is open")
+
+// Options configures a CircuitBreaker. Zero values fall back to the defaults noted on each field.
+type Options struct {
+	// FailureThreshold is how many consecutive failures open the breaker (default 5)
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before allowing trial calls (default 30s)
+	OpenTimeout time.Duration
+	// HalfOpenMaxCalls is how many trial calls may run at once while half-open (default 1)
+	HalfOpenMaxCalls int
+	// IsFailure decides which errors count against the dependency (default: any non-nil error)
+	IsFailure func(error) bool
+}
+
+// Metrics is a snapshot of a breaker's state and counters
+type Metrics struct {
+	Name                string     `json:"name"`
+	State               State      `json:"state"`
+	TotalCalls          int64      `json:"total_calls"`
+	Successes           int64      `json:"successes"`
+	Failures            int64      `json:"failures"`
+	Rejections          int64      `json:"rejections"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	LastStateChange     time.Time  `json:"last_state_change"`
+}
+
+// CircuitBreaker guards calls to one dependency. It is safe for concurrent use; share one
+// breaker between every client of the same dependency.
+type CircuitBreaker struct {
+	name string
+	opts Options
+
+	mu                  sync.Mutex
+	state               State
+	consecutiveFailures int
+	halfOpenInFlight    int
+	openedAt            time.Time
+	lastStateChange     time.Time
+	totalCalls          int64
+	successes           int64
+	failures            int64
+	rejections          int64
+}
+
+// NewCircuitBreaker creates a closed breaker for the named dependency
+func NewCircuitBreaker(name string, opts Options) *CircuitBreaker {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = 5
+	}
+	if opts.OpenTimeout <= 0 {
+		opts.OpenTimeout = 30 * time.Second
+	}
+	if opts.HalfOpenMaxCalls <= 0 {
+		opts.HalfOpenMaxCalls = 1
+	}
+	if opts.IsFailure == nil {
+		opts.IsFailure = func(err error) bool { return err != nil }
+	}
+	return &CircuitBreaker{
+		name:            name,
+		opts:            opts,
+		state:           StateClosed,
+		lastStateChange: time.Now().UTC(),
+	}
+}
+
+// Name returns the dependency the breaker guards
+func (cb *CircuitBreaker) Name() string {
+	return cb.name
+}
+
+// Call runs fn if the breaker allows it and records the outcome.
+// It returns ErrOpen without calling fn while the breaker is open.
+func (cb *CircuitBreaker) Call(fn func() error) error {
+	halfOpen, err := cb.allow()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	cb.record(halfOpen, err)
+	return err
+}
+
+// Execute runs fn through the breaker and returns its result
+func Execute[T any](cb *CircuitBreaker, fn func() (T, error)) (T, error) {
+	var result T
+	err := cb.Call(func() error {
+		var err error
+		result, err = fn()
+		return err
+	})
+	return result, err
+}
+
+// State returns the current state, moving an expired open breaker to half-open
+func (cb *CircuitBreaker) State() State {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.expireOpen(time.Now())
+	return cb.state
+}
+
+// Metrics returns a snapshot of the breaker's state and counters
+func (cb *CircuitBreaker) Metrics() Metrics {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.expireOpen(time.Now())
+
+	metrics := Metrics{
+		Name:                cb.name,
+		State:               cb.state,
+		TotalCalls:          cb.totalCalls,
+		Successes:           cb.successes,
+		Failures:            cb.failures,
+		Rejections:          cb.rejections,
+		ConsecutiveFailures: cb.consecutiveFailures,
+		LastStateChange:     cb.lastStateChange,
+	}
+	if cb.state != StateClosed {
+		openedAt := cb.openedAt
+		metrics.OpenedAt = &openedAt
+	}
+	return metrics
+}
+
+// allow reports whether a call may proceed and whether it is a half-open trial
+func (cb *CircuitBreaker) allow() (bool, error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.expireOpen(time.Now())
+
+	switch cb.state {
+	case StateOpen:
+		cb.rejections++
+		return false, ErrOpen
+	case StateHalfOpen:
+		if cb.halfOpenInFlight >= cb.opts.HalfOpenMaxCalls {
+			cb.rejections++
+			return false, ErrOpen
+		}
+		cb.halfOpenInFlight++
+		cb.totalCalls++
+		return true, nil
+	default:
+		cb.totalCalls++
+		return false, nil
+	}
+}
+
+// record updates the counters and state after a call finishes
+func (cb *CircuitBreaker) record(halfOpen bool, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if halfOpen {
+		cb.halfOpenInFlight--
+	}
+
+	if !cb.opts.IsFailure(err) {
+		cb.successes++
+		cb.consecutiveFailures = 0
+		if halfOpen && cb.state == StateHalfOpen {
+			cb.setState(StateClosed)
+		}
+		return
+	}
+
+	cb.failures++
+	cb.consecutiveFailures++
+	switch {
+	case halfOpen && cb.state == StateHalfOpen:
+		cb.open()
+	case cb.state == StateClosed && cb.consecutiveFailures >= cb.opts.FailureThreshold:
+		cb.open()
+	}
+}
+
+// expireOpen moves an open breaker to half-open once its timeout has passed
+func (cb *CircuitBreaker) expireOpen(now time.Time) {
+	if cb.state == StateOpen && now.Sub(cb.openedAt) >= cb.opts.OpenTimeout {
+		cb.setState(StateHalfOpen)
+	}
+}
+
+// open trips the breaker
+func (cb *CircuitBreaker) open() {
+	cb.openedAt = time.Now().UTC()
+	cb.setState(StateOpen)
+}
+
+// setState changes state and logs the transition
+func (cb *CircuitBreaker) setState(state State) {
+	if cb.state == state {
+		return
+	}
+	log.Printf("Circuit breaker %s: %s -> %s", cb.name, cb.state, state)
+	cb.state = state
+	cb.lastStateChange = time.Now().UTC()
+	if state == StateClosed {
+		cb.consecutiveFailures = 0
+	}
+}
@@ -0,0 +1,43 @@
+// FILE: lib/resilience/grpc.go
+// Circuit breaking for gRPC clients
+
+package resilience
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IsGRPCFailure counts errors that mean the dependency is down or overloaded. Application
+// errors such as NotFound or InvalidArgument show the dependency is answering and do not count.
+func IsGRPCFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// UnaryClientInterceptor runs every unary call on a connection through cb. Rejected calls fail
+// with codes.Unavailable, so callers handle an open breaker like an unreachable server.
+// Create cb with IsFailure set to IsGRPCFailure.
+func UnaryClientInterceptor(cb *CircuitBreaker) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := cb.Call(func() error {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		})
+		if err == ErrOpen {
+			return status.Errorf(codes.Unavailable, "%s: %v", cb.Name(), err)
+		}
+		return err
+	}
+}
+
+// DialOptions returns the dial options that put cb in front of every unary call
+func DialOptions(cb *CircuitBreaker) []grpc.DialOption {
+	return []grpc.DialOption{grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(cb))}
+}
@@ -0,0 +1,232 @@
+// FILE: lib/romanize/middleware.go
+// Response middleware that rewrites the romaji in JSON bodies to the romanization the user chose in
+// their profile, so content and quiz handlers only ever produce Hepburn.
+
+package romanize
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"wise-owl/lib/japanese"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Header carries the caller's romanization preference. Clients copy it from the users service
+// profile (its romanization field) onto every request.
+const Header = "X-Romanization"
+
+// Romanization styles. Hepburn is what the services store and the default when no header is sent.
+const (
+	Hepburn = "hepburn"
+	Kunrei  = "kunrei"
+	None    = "none" // Hide romaji entirely
+)
+
+// styleKey is the Gin context key Middleware stores the request's style under
+const styleKey = "romanization"
+
+// field is the JSON key whose values are rewritten
+const field = "romaji"
+
+// Middleware rewrites every "romaji" field of JSON responses according to the request's X-Romanization
+// header: Kunrei-shiki respells it and "none" removes it. Requests without the header, or with an
+// unknown value, are passed through untouched, as are responses that are not JSON.
+//
+// ETags of rewritten responses get the style appended ("abc" -> "abc-kunrei"), and the suffix is
+// stripped from If-None-Match before the handler sees it, so conditional requests keep working.
+// Register it after compress.Gzip so it sees the uncompressed body.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", Header)
+
+		style := strings.ToLower(strings.TrimSpace(c.GetHeader(Header)))
+		if style != Kunrei && style != None {
+			c.Set(styleKey, Hepburn)
+			c.Next()
+			return
+		}
+		c.Set(styleKey, style)
+
+		if inm := c.GetHeader("If-None-Match"); inm != "" {
+			c.Request.Header.Set("If-None-Match", stripStyle(inm, style))
+		}
+
+		original := c.Writer
+		w := &rewriter{ResponseWriter: original, style: style}
+		c.Writer = w
+		c.Next()
+		w.finish()
+		c.Writer = original
+	}
+}
+
+// Style returns the romanization of the current request, for handlers that put romaji in fields
+// other than "romaji" (e.g. quiz choices). It is Hepburn when Middleware is not installed.
+func Style(c *gin.Context) string {
+	if style := c.GetString(styleKey); style != "" {
+		return style
+	}
+	return Hepburn
+}
+
+// Apply returns Hepburn romaji s in the given style; None yields an empty string.
+func Apply(s, style string) string {
+	switch style {
+	case Kunrei:
+		return japanese.HepburnToKunrei(s)
+	case None:
+		return ""
+	}
+	return s
+}
+
+// rewriter buffers a JSON response so its romaji can be rewritten before anything is sent.
+// Other responses are passed straight through once their first byte is written.
+type rewriter struct {
+	gin.ResponseWriter
+	style   string
+	buf     bytes.Buffer
+	decided bool
+	buffer  bool
+}
+
+func (w *rewriter) Write(data []byte) (int, error) {
+	w.decide()
+	if w.buffer {
+		return w.buf.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *rewriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow is used for bodiless responses such as 204 and 304, which still need their ETag tagged.
+func (w *rewriter) WriteHeaderNow() {
+	w.decide()
+	if !w.buffer {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+// Flush gives up on rewriting: a streamed response must reach the client as it is written.
+func (w *rewriter) Flush() {
+	if !w.decided {
+		w.decided = true
+		w.tagETag()
+	} else if w.buffer {
+		w.buffer = false
+		w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *rewriter) Written() bool {
+	return w.buf.Len() > 0 || w.ResponseWriter.Written()
+}
+
+func (w *rewriter) Size() int {
+	if w.buffer {
+		return w.buf.Len()
+	}
+	return w.ResponseWriter.Size()
+}
+
+// decide fixes, on the first write, whether the body is buffered for rewriting
+func (w *rewriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	w.tagETag()
+	w.buffer = strings.HasPrefix(strings.ToLower(w.Header().Get("Content-Type")), "application/json")
+	if w.buffer {
+		w.Header().Del("Content-Length")
+	}
+}
+
+// tagETag marks the response's ETag with the style, since the body sent differs from the one it hashes
+func (w *rewriter) tagETag() {
+	header := w.Header()
+	if etag := header.Get("ETag"); strings.HasSuffix(etag, `"`) {
+		header.Set("ETag", strings.TrimSuffix(etag, `"`)+"-"+w.style+`"`)
+	}
+}
+
+// finish rewrites and sends a buffered body. A body that does not parse is sent as it was.
+func (w *rewriter) finish() {
+	if !w.decided {
+		// Nothing was written yet; gin sends the status and headers after the handlers return
+		w.decided = true
+		w.tagETag()
+		return
+	}
+	if !w.buffer {
+		return
+	}
+	w.buffer = false
+	body := w.buf.Bytes()
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err == nil && rewrite(doc, w.style) {
+		var out bytes.Buffer
+		encoder := json.NewEncoder(&out)
+		if err := encoder.Encode(doc); err == nil {
+			body = bytes.TrimSuffix(out.Bytes(), []byte("\n"))
+		}
+	}
+	w.ResponseWriter.Write(body)
+}
+
+// rewrite applies style to every "romaji" string in a decoded JSON document and reports whether anything changed
+func rewrite(v interface{}, style string) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if s, ok := value.(string); ok && key == field {
+				if style == None {
+					delete(v, key)
+				} else {
+					v[key] = Apply(s, style)
+				}
+				changed = true
+				continue
+			}
+			if rewrite(value, style) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if rewrite(item, style) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// stripStyle removes the style suffix that tagETag added from each tag of an If-None-Match header.
+// Tags of other styles are dropped: they describe a different body than the handler will produce.
+func stripStyle(ifNoneMatch, style string) string {
+	suffix := "-" + style + `"`
+	var tags []string
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		switch {
+		case tag == "*":
+			tags = append(tags, tag)
+		case strings.HasSuffix(tag, suffix):
+			tags = append(tags, strings.TrimSuffix(tag, suffix)+`"`)
+		}
+	}
+	return strings.Join(tags, ", ")
+}
@@ -0,0 +1,121 @@
+// FILE: lib/storage/local.go
+// Local-disk BlobStore for development
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocalStore keeps objects as files under a root directory.
+type LocalStore struct {
+	root    string
+	baseURL string
+}
+
+// NewLocalStore creates the root directory if needed. Objects are served from baseURL (see Handler),
+// which defaults to LocalMediaPath on the serving service.
+func NewLocalStore(root, baseURL string) (*LocalStore, error) {
+	if root == "" {
+		return nil, fmt.Errorf("local storage directory is not set")
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("creating storage directory %s: %v", root, err)
+	}
+	if baseURL == "" {
+		baseURL = LocalMediaPath
+	}
+	return &LocalStore{root: root, baseURL: baseURL}, nil
+}
+
+// Put writes the object to a temporary file and renames it into place so readers never see partial files.
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get opens the object's file.
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+// Delete removes the object's file.
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// URL returns the object's URL under the configured base URL.
+func (s *LocalStore) URL(key string) string {
+	return joinURL(s.baseURL, key)
+}
+
+// PresignURL returns the plain URL. Everything under Handler is readable in development,
+// so there is no access to grant; expires is ignored.
+func (s *LocalStore) PresignURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	return s.URL(key), nil
+}
+
+// Handler serves stored objects by key; mount it at the path of the base URL with the prefix stripped.
+// Directory listings are refused so keys cannot be enumerated.
+func (s *LocalStore) Handler() http.Handler {
+	files := http.FileServer(http.Dir(s.root))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if validKey(strings.TrimPrefix(r.URL.Path, "/")) != nil {
+			http.NotFound(w, r)
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,124 @@
+// FILE: lib/storage/s3.go
+// S3-backed BlobStore for AWS deployments
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"wise-owl/lib/config"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Store keeps objects in a single S3 bucket.
+type S3Store struct {
+	client    *s3.Client
+	presigner *s3.PresignClient
+	bucket    string
+	baseURL   string
+}
+
+// NewS3Store uses the default AWS credential chain. When baseURL is empty (no CDN in front of the bucket),
+// URLs point at the bucket's virtual-hosted endpoint.
+func NewS3Store(ctx context.Context, bucket, baseURL string) (*S3Store, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("storage bucket is not set")
+	}
+
+	region := config.GetAWSRegion()
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load AWS config: %v", err)
+	}
+
+	if baseURL == "" {
+		baseURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, region)
+	}
+	client := s3.NewFromConfig(awsCfg)
+	return &S3Store{
+		client:    client,
+		presigner: s3.NewPresignClient(client),
+		bucket:    bucket,
+		baseURL:   baseURL,
+	}, nil
+}
+
+// Put uploads the object. size must be exact because S3 needs the content length up front.
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		Body:          r,
+		ContentLength: aws.Int64(size),
+		ContentType:   aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("uploading %s to s3://%s: %v", key, s.bucket, err)
+	}
+	return nil
+}
+
+// Get streams the object from S3.
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("downloading %s from s3://%s: %v", key, s.bucket, err)
+	}
+	return out.Body, nil
+}
+
+// Delete removes the object. S3 reports success for missing keys.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("deleting %s from s3://%s: %v", key, s.bucket, err)
+	}
+	return nil
+}
+
+// URL returns the object's public URL.
+func (s *S3Store) URL(key string) string {
+	return joinURL(s.baseURL, key)
+}
+
+// PresignURL returns a signed GET URL valid for expires. It does not check that the object exists.
+func (s *S3Store) PresignURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	req, err := s.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", fmt.Errorf("presigning %s in s3://%s: %v", key, s.bucket, err)
+	}
+	return req.URL, nil
+}
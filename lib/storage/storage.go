@@ -0,0 +1,73 @@
+// FILE: lib/storage/storage.go
+// Object storage shared by services: S3 in AWS and a local directory in development
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"wise-owl/lib/config"
+)
+
+// Supported storage backends, selected with STORAGE_BACKEND.
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+
+	// LocalMediaPath is where a service serves a LocalStore's objects.
+	LocalMediaPath = "/media"
+)
+
+// ErrNotFound is returned by Get when a key does not exist.
+var ErrNotFound = errors.New("storage: object not found")
+
+// BlobStore stores opaque objects under slash-separated keys such as "avatars/<id>/<name>.jpg".
+// Services share one store and keep their objects apart with a key prefix per kind
+// (e.g. "avatars/", "audio/", "exports/").
+type BlobStore interface {
+	// Put stores size bytes from r under key, replacing any existing object.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object for reading. The caller must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL the object is served from.
+	URL(key string) string
+	// PresignURL returns a URL that grants temporary read access to a private object.
+	PresignURL(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// New creates the BlobStore selected by the configuration.
+func New(ctx context.Context, cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Backend {
+	case "", BackendLocal:
+		return NewLocalStore(cfg.LocalDir, cfg.PublicURL)
+	case BackendS3:
+		return NewS3Store(ctx, cfg.Bucket, cfg.PublicURL)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
+
+// validKey rejects keys that could escape the store's root or address a directory.
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.HasSuffix(key, "/") {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid storage key %q", key)
+		}
+	}
+	return nil
+}
+
+// joinURL appends key to a base URL.
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + key
+}
@@ -0,0 +1,158 @@
+// FILE: lib/stt/google.go
+// Google Cloud Speech-to-Text Recognizer, called over its REST API
+
+package stt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	googleRecognizeURL = "https://speech.googleapis.com/v1/speech:recognize"
+	// googleAlternatives is how many transcripts to ask for; the right reading is often not the first
+	googleAlternatives = 5
+	// opusSampleRate is the rate browsers and phones record Opus at
+	opusSampleRate = 48000
+)
+
+// googleEncodings maps content types to Speech-to-Text encodings. WAV and FLAC carry their
+// encoding and sample rate in their headers, so none is sent for them.
+var googleEncodings = map[string]string{
+	"audio/wav":    "",
+	"audio/x-wav":  "",
+	"audio/wave":   "",
+	"audio/flac":   "",
+	"audio/x-flac": "",
+	"audio/ogg":    "OGG_OPUS",
+	"audio/webm":   "WEBM_OPUS",
+}
+
+// Google recognizes speech with Google Cloud Speech-to-Text's synchronous API.
+type Google struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewGoogle creates a recognizer authenticated with an API key restricted to the Speech-to-Text API.
+func NewGoogle(apiKey string) (*Google, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("stt api key is not set")
+	}
+	return &Google{
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// googleRequest is the body of speech:recognize
+type googleRequest struct {
+	Config struct {
+		Encoding        string                `json:"encoding,omitempty"`
+		SampleRateHertz int                   `json:"sampleRateHertz,omitempty"`
+		LanguageCode    string                `json:"languageCode"`
+		MaxAlternatives int                   `json:"maxAlternatives"`
+		SpeechContexts  []googleSpeechContext `json:"speechContexts,omitempty"`
+	} `json:"config"`
+	Audio struct {
+		Content string `json:"content"`
+	} `json:"audio"`
+}
+
+// googleSpeechContext lists phrases recognition should favour
+type googleSpeechContext struct {
+	Phrases []string `json:"phrases"`
+}
+
+// googleResponse is the part of the speech:recognize response used here. Each result is a
+// consecutive stretch of the audio.
+type googleResponse struct {
+	Results []struct {
+		Alternatives []struct {
+			Transcript string `json:"transcript"`
+		} `json:"alternatives"`
+	} `json:"results"`
+}
+
+// Transcribe sends the clip to speech:recognize with the hints as phrases to favour.
+func (g *Google) Transcribe(ctx context.Context, audio Audio, hints []string) ([]string, error) {
+	mediaType, _, err := mime.ParseMediaType(audio.ContentType)
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	encoding, ok := googleEncodings[strings.ToLower(mediaType)]
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+
+	var body googleRequest
+	body.Config.Encoding = encoding
+	if encoding != "" {
+		body.Config.SampleRateHertz = audio.SampleRate
+		if body.Config.SampleRateHertz == 0 {
+			body.Config.SampleRateHertz = opusSampleRate
+		}
+	}
+	body.Config.LanguageCode = "ja-JP"
+	body.Config.MaxAlternatives = googleAlternatives
+	if len(hints) > 0 {
+		body.Config.SpeechContexts = []googleSpeechContext{{Phrases: hints}}
+	}
+	body.Audio.Content = base64.StdEncoding.EncodeToString(audio.Data)
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, googleRecognizeURL+"?key="+url.QueryEscape(g.apiKey), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling Speech-to-Text: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		// Bad encodings and sample rates are reported as invalid arguments
+		if resp.StatusCode == http.StatusBadRequest {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, detail)
+		}
+		return nil, fmt.Errorf("speech-to-text returned status %d: %s", resp.StatusCode, detail)
+	}
+
+	var result googleResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding Speech-to-Text response: %v", err)
+	}
+	switch len(result.Results) {
+	case 0:
+		return nil, nil
+	case 1:
+		transcripts := make([]string, 0, len(result.Results[0].Alternatives))
+		for _, alternative := range result.Results[0].Alternatives {
+			transcripts = append(transcripts, alternative.Transcript)
+		}
+		return transcripts, nil
+	default:
+		// A pause split the speech; only the best reading of each stretch can be joined up
+		var joined strings.Builder
+		for _, stretch := range result.Results {
+			if len(stretch.Alternatives) > 0 {
+				joined.WriteString(stretch.Alternatives[0].Transcript)
+			}
+		}
+		return []string{joined.String()}, nil
+	}
+}
@@ -0,0 +1,53 @@
+// FILE: lib/stt/stt.go
+// Speech recognition for pronunciation practice: Google Cloud Speech-to-Text, or none at all
+
+package stt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"wise-owl/lib/config"
+)
+
+// Supported recognition backends, selected with STT_BACKEND.
+const (
+	BackendGoogle = "google"
+
+	// MaxAudioBytes caps a clip; a word or short phrase takes a few seconds
+	MaxAudioBytes = 1 << 20
+)
+
+var (
+	// ErrDisabled is returned by New when no backend is configured.
+	ErrDisabled = errors.New("stt: no speech recognition backend configured")
+	// ErrUnsupportedFormat is returned for audio the backend cannot decode.
+	ErrUnsupportedFormat = errors.New("stt: unsupported audio format")
+)
+
+// Audio is a recorded clip.
+type Audio struct {
+	Data        []byte
+	ContentType string // e.g. audio/wav, audio/flac, audio/ogg, or audio/webm
+	SampleRate  int    // Hz; needed for Opus in Ogg or WebM when the recorder did not use 48 kHz
+}
+
+// Recognizer transcribes spoken Japanese.
+type Recognizer interface {
+	// Transcribe returns what may have been said in audio, most likely first, or none if no
+	// speech was heard. hints are phrases the speaker is expected to say.
+	Transcribe(ctx context.Context, audio Audio, hints []string) ([]string, error)
+}
+
+// New creates the Recognizer selected by the configuration, or returns ErrDisabled.
+func New(cfg config.STTConfig) (Recognizer, error) {
+	switch cfg.Backend {
+	case "":
+		return nil, ErrDisabled
+	case BackendGoogle:
+		return NewGoogle(cfg.APIKey)
+	default:
+		return nil, fmt.Errorf("unknown stt backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,68 @@
+// FILE: lib/tts/polly.go
+// Amazon Polly Synthesizer for AWS deployments
+
+package tts
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"wise-owl/lib/config"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/polly"
+	"github.com/aws/aws-sdk-go-v2/service/polly/types"
+)
+
+// maxAudioBytes caps the audio read back for one text; a word or sentence is far smaller
+const maxAudioBytes = 4 << 20
+
+// Polly synthesizes speech with one Japanese voice of Amazon Polly.
+type Polly struct {
+	client *polly.Client
+	voice  types.VoiceId
+	engine types.Engine
+}
+
+// NewPolly uses the default AWS credential chain. voice is a Polly voice ID such as Takumi and
+// engine is "neural" or "standard"; the voice must support the engine.
+func NewPolly(ctx context.Context, voice, engine string) (*Polly, error) {
+	if voice == "" {
+		return nil, fmt.Errorf("tts voice is not set")
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(config.GetAWSRegion()))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load AWS config: %v", err)
+	}
+	return &Polly{
+		client: polly.NewFromConfig(awsCfg),
+		voice:  types.VoiceId(voice),
+		engine: types.Engine(engine),
+	}, nil
+}
+
+// Synthesize reads text aloud as MP3.
+func (p *Polly) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	out, err := p.client.SynthesizeSpeech(ctx, &polly.SynthesizeSpeechInput{
+		Text:         aws.String(text),
+		OutputFormat: types.OutputFormatMp3,
+		VoiceId:      p.voice,
+		Engine:       p.engine,
+		LanguageCode: types.LanguageCodeJaJp,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("synthesizing speech with Polly: %v", err)
+	}
+	defer out.AudioStream.Close()
+
+	audio, err := io.ReadAll(io.LimitReader(out.AudioStream, maxAudioBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading Polly audio: %v", err)
+	}
+	if len(audio) > maxAudioBytes {
+		return nil, fmt.Errorf("polly audio is larger than %d bytes", maxAudioBytes)
+	}
+	return audio, nil
+}
@@ -0,0 +1,43 @@
+// FILE: lib/tts/tts.go
+// Speech synthesis for pronunciation audio: Amazon Polly in AWS, or none at all
+
+package tts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"wise-owl/lib/config"
+)
+
+// Supported synthesis backends, selected with TTS_BACKEND.
+const (
+	BackendPolly = "polly"
+
+	// ContentType is the format every Synthesizer returns
+	ContentType = "audio/mpeg"
+	// Extension is the file extension of ContentType
+	Extension = ".mp3"
+)
+
+// ErrDisabled is returned by New when no backend is configured.
+var ErrDisabled = errors.New("tts: no speech synthesis backend configured")
+
+// Synthesizer turns Japanese text into spoken audio.
+type Synthesizer interface {
+	// Synthesize returns text read aloud as MP3 audio.
+	Synthesize(ctx context.Context, text string) ([]byte, error)
+}
+
+// New creates the Synthesizer selected by the configuration, or returns ErrDisabled.
+func New(ctx context.Context, cfg config.TTSConfig) (Synthesizer, error) {
+	switch cfg.Backend {
+	case "":
+		return nil, ErrDisabled
+	case BackendPolly:
+		return NewPolly(ctx, cfg.Voice, cfg.Engine)
+	default:
+		return nil, fmt.Errorf("unknown tts backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,121 @@
+// FILE: lib/usage/meter.go
+// Middleware that counts a service's API calls and enforces the daily limits.
+
+package usage
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/auth"
+	"wise-owl/lib/cache"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// tierTTL is how long a looked-up tier is used; an upgrade applies within it
+	tierTTL = 5 * time.Minute
+	// tierLookupTimeout bounds a tier lookup made on the request path
+	tierLookupTimeout = 2 * time.Second
+)
+
+// TierSource looks up users' subscription tiers. Users without a profile are on the free tier.
+type TierSource interface {
+	Tier(ctx context.Context, userID string) (string, error)
+}
+
+// Meter counts the API calls made to one service.
+type Meter struct {
+	store   *Store
+	service string // e.g. "quiz"
+	tiers   TierSource
+	limits  Limits
+	cache   *cache.Cache[string, string]
+}
+
+// NewMeter creates a meter for service that looks tiers up in tiers.
+func NewMeter(store *Store, service string, tiers TierSource, limits Limits) *Meter {
+	return &Meter{
+		store:   store,
+		service: service,
+		tiers:   tiers,
+		limits:  limits,
+		cache:   cache.New[string, string](tierTTL, 10000),
+	}
+}
+
+// Middleware creates a Gin middleware that counts each call of the authenticated user and answers
+// calls over their tier's daily limit with a 429 quota_exceeded error. The X-RateLimit-Limit,
+// X-RateLimit-Remaining, and X-RateLimit-Reset headers report the quota when there is one. It
+// must run after the token middleware; requests without a user pass through uncounted.
+//
+// A call whose count or tier cannot be read is let through, so an outage of the users database
+// or service does not take the API down with it.
+func (m *Meter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("userID")
+		if userID == "" {
+			c.Next()
+			return
+		}
+
+		now := time.Now().UTC()
+		calls, err := m.store.Record(c.Request.Context(), userID, m.service, now)
+		if err != nil {
+			log.Printf("WARN: Failed to count %s API call by %s: %v", m.service, userID, err)
+			c.Next()
+			return
+		}
+		tier, err := m.tier(c.Request.Context(), userID)
+		if err != nil {
+			log.Printf("WARN: Failed to look up the tier of %s, not enforcing its quota: %v", userID, err)
+			c.Next()
+			return
+		}
+		limit := m.limits.For(tier)
+		if limit == 0 {
+			c.Next()
+			return
+		}
+
+		resetAt := ResetAt(now)
+		header := c.Writer.Header()
+		header.Set("X-RateLimit-Limit", strconv.FormatInt(limit, 10))
+		header.Set("X-RateLimit-Remaining", strconv.FormatInt(max(limit-calls, 0), 10))
+		header.Set("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+		if calls > limit {
+			header.Set("Retry-After", strconv.Itoa(int(resetAt.Sub(now).Seconds())+1))
+			apierror.Respond(c, apierror.New(http.StatusTooManyRequests, "quota_exceeded", "You have made today's maximum number of requests. Try again tomorrow."))
+			return
+		}
+		c.Next()
+	}
+}
+
+// tier returns userID's tier, from the cache when it was looked up recently. Guest sessions
+// have no profile and are on the free tier.
+func (m *Meter) tier(ctx context.Context, userID string) (string, error) {
+	if auth.IsGuest(userID) {
+		return TierFree, nil
+	}
+	if tier, ok := m.cache.Get(userID); ok {
+		return tier, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tierLookupTimeout)
+	defer cancel()
+	tier, err := m.tiers.Tier(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if tier == "" {
+		tier = TierFree
+	}
+	m.cache.Set(userID, tier)
+	return tier, nil
+}
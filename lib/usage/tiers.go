@@ -0,0 +1,29 @@
+// FILE: lib/usage/tiers.go
+// Tier lookups for services other than the users service, which keeps the user profiles.
+
+package usage
+
+import (
+	"context"
+
+	usersv1 "wise-owl/gen/proto/users/v1"
+)
+
+// GRPCTiers asks the users service for users' tiers over its gRPC API.
+type GRPCTiers struct {
+	client usersv1.UsersServiceClient
+}
+
+// NewGRPCTiers creates a tier source backed by the users service's gRPC client.
+func NewGRPCTiers(client usersv1.UsersServiceClient) *GRPCTiers {
+	return &GRPCTiers{client: client}
+}
+
+// Tier returns userID's current tier. Users the users service has no profile for are on the free tier.
+func (t *GRPCTiers) Tier(ctx context.Context, userID string) (string, error) {
+	res, err := t.client.GetSubscription(ctx, &usersv1.GetSubscriptionRequest{UserId: userID})
+	if err != nil {
+		return "", err
+	}
+	return res.GetTier(), nil
+}
@@ -0,0 +1,127 @@
+// FILE: lib/usage/usage.go
+// This package counts each user's API calls per service and UTC day, and holds users to a daily
+// number of calls per service that depends on their subscription tier. Every service counts into
+// one collection in the users database, which the users service reports usage from.
+
+package usage
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"wise-owl/lib/config"
+	"wise-owl/lib/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection is where the daily counts are kept, in the users database
+const Collection = "api_usage"
+
+// Subscription tiers. Users without one are on the free tier.
+const (
+	TierFree    = "free"
+	TierPremium = "premium"
+)
+
+const (
+	// retention is how long daily counts are kept
+	retention = 90 * 24 * time.Hour
+	// dayLayout formats the UTC day a count is for
+	dayLayout = "2006-01-02"
+	// defaultFreeLimit is the free tier's daily calls per service unless USAGE_LIMIT_FREE is set
+	defaultFreeLimit = 2000
+)
+
+// Indexes lists the indexes the usage collection relies on.
+var Indexes = []database.Index{
+	// One count per user, day, and service; reports read a user's latest days
+	{Collection: Collection, Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "day", Value: -1}, {Key: "service", Value: 1}}, Unique: true},
+	{Collection: Collection, Keys: bson.D{{Key: "created_at", Value: 1}}, ExpireAfter: retention},
+}
+
+// Count is one user's calls to one service on one UTC day.
+type Count struct {
+	UserID    string    `bson:"user_id" json:"-"` // Auth0 ID, or a guest session's ID
+	Service   string    `bson:"service" json:"service"`
+	Day       string    `bson:"day" json:"day"` // "YYYY-MM-DD" in UTC
+	Calls     int64     `bson:"calls" json:"calls"`
+	CreatedAt time.Time `bson:"created_at" json:"-"`
+}
+
+// Limits maps each tier to the calls a user may make to one service per UTC day; 0 means unlimited.
+type Limits map[string]int64
+
+// For returns the daily limit of tier. Unknown tiers get the free tier's limit.
+func (l Limits) For(tier string) int64 {
+	if limit, ok := l[tier]; ok {
+		return limit
+	}
+	return l[TierFree]
+}
+
+// LimitsFromEnv reads the daily limits from USAGE_LIMIT_FREE (default 2000) and
+// USAGE_LIMIT_PREMIUM (default 0, unlimited).
+func LimitsFromEnv() Limits {
+	return Limits{
+		TierFree:    envLimit("USAGE_LIMIT_FREE", defaultFreeLimit),
+		TierPremium: envLimit("USAGE_LIMIT_PREMIUM", 0),
+	}
+}
+
+func envLimit(name string, fallback int64) int64 {
+	limit, err := config.GetInt(name)
+	if err != nil {
+		log.Printf("WARN: %v, using %d", err, fallback)
+		return fallback
+	}
+	return int64(limit)
+}
+
+// Day returns the UTC day t falls on, as counts are keyed.
+func Day(t time.Time) string {
+	return t.UTC().Format(dayLayout)
+}
+
+// ResetAt returns when the counts of the day t falls on stop applying: the next midnight UTC.
+func ResetAt(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+}
+
+// Store reads and writes the daily counts.
+type Store struct {
+	collection *mongo.Collection
+}
+
+// NewStore creates a store over the users database.
+func NewStore(usersDB *mongo.Database) *Store {
+	return &Store{collection: usersDB.Collection(Collection)}
+}
+
+// Record counts one call by userID to service at now and returns the calls they made to it that day.
+func (s *Store) Record(ctx context.Context, userID, service string, now time.Time) (int64, error) {
+	key := bson.M{"user_id": userID, "day": Day(now), "service": service}
+	update := bson.M{
+		"$inc":         bson.M{"calls": 1},
+		"$setOnInsert": bson.M{"created_at": now},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var count Count
+	err := s.collection.FindOneAndUpdate(ctx, key, update, opts).Decode(&count)
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent first call of the day created the count; it matches now
+		err = s.collection.FindOneAndUpdate(ctx, key, update, opts).Decode(&count)
+	}
+	return count.Calls, err
+}
+
+// Since returns userID's counts from the day since falls on, newest day first.
+func (s *Store) Since(ctx context.Context, userID string, since time.Time) ([]Count, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "day", Value: -1}, {Key: "service", Value: 1}})
+	return database.FindAll[Count](ctx, s.collection, bson.M{"user_id": userID, "day": bson.M{"$gte": Day(since)}}, opts)
+}
@@ -0,0 +1,172 @@
+// FILE: lib/validation/limits.go
+// Gin middleware that turns away oversized request bodies before they are decoded.
+
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"wise-owl/lib/apierror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Unlimited lifts a limit in a route override.
+const Unlimited = -1
+
+// DefaultLimits apply to requests when none are configured.
+var DefaultLimits = Limits{MaxBytes: 1 << 20, MaxItems: 1000}
+
+// maxCheckDepth is how deeply nested arrays are looked for; no request body is nested this deep
+const maxCheckDepth = 32
+
+// Limits bounds a request body.
+type Limits struct {
+	MaxBytes int64 // Size of the body, answered with a 413 above it
+	MaxItems int   // Elements in any one array of a JSON body, answered with a 422 above it
+}
+
+// SizeLimits creates a Gin middleware that enforces limits on request bodies, so a pathological
+// payload is rejected before it reaches MongoDB or a gRPC call. Zero fields of limits use
+// DefaultLimits.
+//
+// routes overrides limits for route groups, keyed by path prefix (e.g. "/api/v1/srs/import"); the
+// longest matching prefix wins. Zero fields of an override keep the overall limit and Unlimited
+// lifts it, e.g. for uploads whose handlers limit their own bodies.
+//
+// A body over MaxBytes is answered with a 413 request_too_large error, whether its length is
+// declared or found while reading. An array over MaxItems is answered with a 422 too_many_items
+// error naming the field, in the caller's language. It must run after apierror.Middleware.
+func SizeLimits(limits Limits, routes map[string]Limits) gin.HandlerFunc {
+	if limits.MaxBytes == 0 {
+		limits.MaxBytes = DefaultLimits.MaxBytes
+	}
+	if limits.MaxItems == 0 {
+		limits.MaxItems = DefaultLimits.MaxItems
+	}
+	return func(c *gin.Context) {
+		l := routeLimits(c.FullPath(), limits, routes)
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		if l.MaxBytes > 0 {
+			if c.Request.ContentLength > l.MaxBytes {
+				apierror.Respond(c, tooLarge(l.MaxBytes))
+				return
+			}
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, l.MaxBytes)
+		}
+
+		if l.MaxItems > 0 && c.ContentType() == binding.MIMEJSON {
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				var maxErr *http.MaxBytesError
+				if errors.As(err, &maxErr) {
+					apierror.Respond(c, tooLarge(maxErr.Limit))
+				} else {
+					apierror.Respond(c, apierror.InvalidRequest(err))
+				}
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+			if field, found := oversizedArray(body, l.MaxItems); found {
+				lang := Language(c)
+				apierror.Respond(c, apierror.New(http.StatusUnprocessableEntity, "too_many_items", requestMessage(lang)).
+					WithDetails([]FieldError{{
+						Field:   field,
+						Rule:    "max",
+						Message: strings.NewReplacer("{field}", field, "{param}", strconv.Itoa(l.MaxItems)).Replace(lookup(lang, "max.slice")),
+					}}))
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// routeLimits returns the limits for a route: the override with the longest prefix of path
+// applied over fallback
+func routeLimits(path string, fallback Limits, routes map[string]Limits) Limits {
+	var override Limits
+	longest := -1
+	for prefix, l := range routes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			override, longest = l, len(prefix)
+		}
+	}
+	if override.MaxBytes != 0 {
+		fallback.MaxBytes = override.MaxBytes
+	}
+	if override.MaxItems != 0 {
+		fallback.MaxItems = override.MaxItems
+	}
+	return fallback
+}
+
+// tooLarge is the error for a body over limit bytes.
+func tooLarge(limit int64) *apierror.Error {
+	return apierror.New(http.StatusRequestEntityTooLarge, "request_too_large", "The request body is too large.").
+		WithDetails(gin.H{"max_bytes": limit})
+}
+
+// oversizedArray returns the dotted path of the first array in a JSON document with more than max
+// elements ("request" for the document itself). Invalid JSON is left for binding to report.
+func oversizedArray(body []byte, max int) (string, bool) {
+	field, found, _ := walkJSON(json.NewDecoder(bytes.NewReader(body)), "", max, 0)
+	if found && field == "" {
+		field = "request"
+	}
+	return field, found
+}
+
+// walkJSON reads one JSON value from dec, looking for an array with more than max elements.
+func walkJSON(dec *json.Decoder, path string, max, depth int) (string, bool, error) {
+	if depth > maxCheckDepth {
+		return "", false, fmt.Errorf("nested more than %d levels deep", maxCheckDepth)
+	}
+	token, err := dec.Token()
+	if err != nil {
+		return "", false, err
+	}
+	switch token {
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if i == max {
+				return path, true, nil
+			}
+			if field, found, err := walkJSON(dec, fmt.Sprintf("%s[%d]", path, i), max, depth+1); found || err != nil {
+				return field, found, err
+			}
+		}
+	case json.Delim('{'):
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return "", false, err
+			}
+			name, _ := key.(string)
+			if path != "" {
+				name = path + "." + name
+			}
+			if field, found, err := walkJSON(dec, name, max, depth+1); found || err != nil {
+				return field, found, err
+			}
+		}
+	default:
+		return "", false, nil
+	}
+	_, err = dec.Token() // The closing delimiter
+	return "", false, err
+}
@@ -0,0 +1,84 @@
+// FILE: lib/validation/messages.go
+// English and Burmese messages for validation rules.
+
+package validation
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// messages maps language -> rule -> template. {field} and {param} are substituted.
+// Length rules have ".string" and ".slice" variants so the message can say what is counted.
+var messages = map[string]map[string]string{
+	LangEnglish: {
+		"request":    "The request is invalid.",
+		"default":    "{field} is invalid.",
+		"required":   "{field} is required.",
+		"email":      "{field} must be a valid email address.",
+		"min":        "{field} must be at least {param}.",
+		"min.string": "{field} must be at least {param} characters long.",
+		"min.slice":  "{field} must contain at least {param} items.",
+		"max":        "{field} must be at most {param}.",
+		"max.string": "{field} must be at most {param} characters long.",
+		"max.slice":  "{field} must contain at most {param} items.",
+		"oneof":      "{field} must be one of: {param}.",
+		"timezone":   "{field} must be an IANA timezone name, e.g. Asia/Yangon.",
+		"kana":       "{field} must contain only hiragana or katakana.",
+		"hhmm":       "{field} must be a time in HH:MM format.",
+		"objectid":   "{field} must be a 24-character hex ID.",
+	},
+	LangBurmese: {
+		"request":    "တောင်းဆိုချက် မမှန်ကန်ပါ။",
+		"default":    "{field} မမှန်ကန်ပါ။",
+		"required":   "{field} ကို ဖြည့်ရန် လိုအပ်ပါသည်။",
+		"email":      "{field} သည် မှန်ကန်သော အီးမေးလ်လိပ်စာ ဖြစ်ရပါမည်။",
+		"min":        "{field} သည် အနည်းဆုံး {param} ဖြစ်ရပါမည်။",
+		"min.string": "{field} သည် အနည်းဆုံး စာလုံး {param} လုံး ရှိရပါမည်။",
+		"min.slice":  "{field} တွင် အနည်းဆုံး {param} ခု ပါဝင်ရပါမည်။",
+		"max":        "{field} သည် အများဆုံး {param} ဖြစ်ရပါမည်။",
+		"max.string": "{field} သည် အများဆုံး စာလုံး {param} လုံးသာ ရှိရပါမည်။",
+		"max.slice":  "{field} တွင် အများဆုံး {param} ခုသာ ပါဝင်ရပါမည်။",
+		"oneof":      "{field} သည် {param} ထဲမှ တစ်ခု ဖြစ်ရပါမည်။",
+		"timezone":   "{field} သည် Asia/Yangon ကဲ့သို့ IANA အချိန်ဇုန်အမည် ဖြစ်ရပါမည်။",
+		"kana":       "{field} တွင် ဟီရဂါနာ သို့မဟုတ် ခတခါနာ သာ ပါဝင်ရပါမည်။",
+		"hhmm":       "{field} သည် HH:MM ပုံစံဖြင့် အချိန် ဖြစ်ရပါမည်။",
+		"objectid":   "{field} သည် စာလုံး ၂၄ လုံးပါ hex ID ဖြစ်ရပါမည်။",
+	},
+}
+
+// requestMessage returns the top-level message for an invalid request.
+func requestMessage(lang string) string {
+	return lookup(lang, "request")
+}
+
+// message renders the message for a single field error.
+func message(lang string, fe validator.FieldError, field string) string {
+	key := fe.Tag()
+	switch fe.Kind() {
+	case reflect.String:
+		if _, ok := messages[LangEnglish][key+".string"]; ok {
+			key += ".string"
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if _, ok := messages[LangEnglish][key+".slice"]; ok {
+			key += ".slice"
+		}
+	}
+	if _, ok := messages[LangEnglish][key]; !ok {
+		key = "default"
+	}
+
+	return strings.NewReplacer("{field}", field, "{param}", strings.ReplaceAll(fe.Param(), " ", ", ")).
+		Replace(lookup(lang, key))
+}
+
+// lookup returns the template for key, falling back to English.
+func lookup(lang, key string) string {
+	if template, ok := messages[lang][key]; ok {
+		return template
+	}
+	return messages[LangEnglish][key]
+}
@@ -0,0 +1,181 @@
+// FILE: lib/validation/validation.go
+// This package wraps Gin's go-playground/validator engine with the project's custom rules
+// and turns validation failures into API errors with English or Burmese messages.
+
+package validation
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"wise-owl/lib/apierror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Supported message languages.
+const (
+	LangEnglish = "en"
+	LangBurmese = "my"
+)
+
+var (
+	registerOnce sync.Once
+	hhmmPattern  = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+)
+
+// FieldError describes a single field that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Register installs the custom rules on Gin's validator and reports fields by their JSON names.
+// It is safe to call more than once; the Bind functions call it automatically.
+//
+// Custom rules:
+//   - kana: hiragana, katakana, the prolonged sound mark, and spaces only
+//   - hhmm: a 24-hour "HH:MM" time, as used by NotificationPreferences.TimeUTC
+//   - objectid: a 24-character hex MongoDB ObjectID
+func Register() {
+	registerOnce.Do(func() {
+		engine, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+
+		engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+			for _, tag := range []string{"json", "form"} {
+				name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+				if name == "-" {
+					return ""
+				}
+				if name != "" {
+					return name
+				}
+			}
+			return field.Name
+		})
+
+		engine.RegisterValidation("kana", func(fl validator.FieldLevel) bool {
+			return IsKana(fl.Field().String())
+		})
+		engine.RegisterValidation("hhmm", func(fl validator.FieldLevel) bool {
+			return hhmmPattern.MatchString(fl.Field().String())
+		})
+		engine.RegisterValidation("objectid", func(fl validator.FieldLevel) bool {
+			return primitive.IsValidObjectID(fl.Field().String())
+		})
+	})
+}
+
+// BindJSON decodes and validates the request body into obj.
+// Failures are returned as an invalid_request API error in the caller's language.
+func BindJSON(c *gin.Context, obj interface{}) *apierror.Error {
+	Register()
+	if err := c.ShouldBindJSON(obj); err != nil {
+		return requestError(err, Language(c), obj)
+	}
+	return nil
+}
+
+// BindQuery decodes and validates the query string into obj.
+func BindQuery(c *gin.Context, obj interface{}) *apierror.Error {
+	Register()
+	if err := c.ShouldBindQuery(obj); err != nil {
+		return requestError(err, Language(c), obj)
+	}
+	return nil
+}
+
+// BindForm decodes and validates the fields of a URL-encoded or multipart form body into obj.
+func BindForm(c *gin.Context, obj interface{}) *apierror.Error {
+	Register()
+	if err := c.ShouldBindWith(obj, binding.Form); err != nil {
+		return requestError(err, Language(c), obj)
+	}
+	return nil
+}
+
+// Language picks the message language from the Accept-Language header, defaulting to English.
+func Language(c *gin.Context) string {
+	for _, part := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		tag := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		switch {
+		case tag == LangBurmese || strings.HasPrefix(tag, LangBurmese+"-"):
+			return LangBurmese
+		case tag == LangEnglish || strings.HasPrefix(tag, LangEnglish+"-"):
+			return LangEnglish
+		}
+	}
+	return LangEnglish
+}
+
+// Translate converts validator errors for obj into field errors in the given language.
+// Fields are reported by their dotted JSON path. It returns nil if err is not a validation error.
+func Translate(err error, lang string, obj interface{}) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	// The validator prefixes namespaces with the struct's type name, which is empty for anonymous request structs
+	root := reflect.TypeOf(obj)
+	for root != nil && root.Kind() == reflect.Ptr {
+		root = root.Elem()
+	}
+	prefix := ""
+	if root != nil && root.Name() != "" {
+		prefix = root.Name() + "."
+	}
+
+	fieldErrs := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		field := strings.TrimPrefix(fe.Namespace(), prefix)
+		fieldErrs = append(fieldErrs, FieldError{
+			Field:   field,
+			Rule:    fe.Tag(),
+			Message: message(lang, fe, field),
+		})
+	}
+	return fieldErrs
+}
+
+// IsKana reports whether s is non-empty and written only in hiragana or katakana.
+func IsKana(s string) bool {
+	if strings.TrimSpace(s) == "" {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 0x3041 && r <= 0x309F: // Hiragana
+		case r >= 0x30A0 && r <= 0x30FF: // Katakana, including ー and ・
+		case r >= 0x31F0 && r <= 0x31FF: // Katakana phonetic extensions
+		case r == ' ' || r == '　':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// requestError builds the API error for a failed bind. Malformed bodies carry the decoder message.
+func requestError(err error, lang string, obj interface{}) *apierror.Error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return tooLarge(maxErr.Limit)
+	}
+	apiErr := apierror.BadRequest("invalid_request", requestMessage(lang))
+	if fieldErrs := Translate(err, lang, obj); fieldErrs != nil {
+		return apiErr.WithDetails(fieldErrs)
+	}
+	return apiErr.WithDetails(err.Error())
+}
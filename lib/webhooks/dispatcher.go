@@ -0,0 +1,256 @@
+// FILE: lib/webhooks/dispatcher.go
+// Delivery: events are logged once per subscribed webhook, then POSTed, with failures retried on
+// an exponential backoff by a scheduled job
+
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"wise-owl/lib/auth"
+	"wise-owl/lib/events"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Delivery headers, besides the signature and timestamp headers of auth.SignWebhook
+	EventHeader    = "X-Webhook-Event"
+	DeliveryHeader = "X-Webhook-Delivery"
+
+	// RetrySchedule is how often RetryDue should run
+	RetrySchedule = "@every 1m"
+
+	// maxAttempts is how many times a delivery is tried before it is marked failed; with the
+	// backoff below the last try is about an hour after the first
+	maxAttempts = 8
+	// firstBackoff is the wait after the first failure, doubled after each further one
+	firstBackoff = 30 * time.Second
+	// deliveryTimeout bounds one attempt, and is also how long an attempt holds its claim
+	deliveryTimeout = 10 * time.Second
+	// retryBatch caps the deliveries one RetryDue run attempts
+	retryBatch = 100
+	// maxErrorLength caps the error text kept in the delivery log
+	maxErrorLength = 300
+)
+
+// Dispatcher delivers events to webhooks. It implements events.Publisher, so services publish
+// to it with events.PublishAsync.
+type Dispatcher struct {
+	store  *Store
+	client *http.Client
+}
+
+// NewDispatcher creates a dispatcher over the users service's database.
+func NewDispatcher(db *mongo.Database) *Dispatcher {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: refusePrivate}
+	return &Dispatcher{
+		store: NewStore(db),
+		client: &http.Client{
+			Timeout:   deliveryTimeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 5 * time.Second},
+			// A redirect could lead anywhere; receivers must answer at the registered URL
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+	}
+}
+
+// Publish logs a delivery of event to each webhook subscribed to it and attempts them in the
+// background. Failed attempts are left for RetryDue.
+func (d *Dispatcher) Publish(ctx context.Context, event events.Event) error {
+	subscribers, err := d.store.subscribers(ctx, event.UserID, event.Type)
+	if err != nil || len(subscribers) == 0 {
+		return err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %s: %v", event.ID, err)
+	}
+
+	now := time.Now().UTC()
+	deliveries := make([]interface{}, 0, len(subscribers))
+	ids := make([]primitive.ObjectID, 0, len(subscribers))
+	for _, webhook := range subscribers {
+		delivery := Delivery{
+			ID:            primitive.NewObjectID(),
+			WebhookID:     webhook.ID,
+			OwnerID:       webhook.OwnerID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			UserID:        event.UserID,
+			Payload:       string(payload),
+			Status:        StatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+		deliveries = append(deliveries, delivery)
+		ids = append(ids, delivery.ID)
+	}
+	if _, err := d.store.deliveries.InsertMany(ctx, deliveries); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		go d.attempt(context.Background(), id)
+	}
+	return nil
+}
+
+// RetryDue attempts the pending deliveries whose retry time has come. It is meant to run as a
+// scheduled job on one instance.
+func (d *Dispatcher) RetryDue(ctx context.Context) error {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetLimit(retryBatch).
+		SetProjection(bson.M{"_id": 1})
+	cursor, err := d.store.deliveries.Find(ctx, bson.M{
+		"status":          StatusPending,
+		"next_attempt_at": bson.M{"$lte": time.Now().UTC()},
+	}, opts)
+	if err != nil {
+		return err
+	}
+	var due []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &due); err != nil {
+		return err
+	}
+
+	for _, delivery := range due {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		d.attempt(ctx, delivery.ID)
+	}
+	return nil
+}
+
+// attempt makes one delivery attempt and records its outcome. The delivery is claimed first, by
+// pushing its retry time past the attempt, so an instant delivery and the retry job never send
+// it twice at once.
+func (d *Dispatcher) attempt(ctx context.Context, id primitive.ObjectID) {
+	now := time.Now().UTC()
+	var delivery Delivery
+	err := d.store.deliveries.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "status": StatusPending, "next_attempt_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"next_attempt_at": now.Add(2 * deliveryTimeout)}, "$inc": bson.M{"attempts": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&delivery)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return // Claimed elsewhere, or no longer pending
+	}
+	if err != nil {
+		log.Printf("WARN: Failed to claim webhook delivery %s: %v", id.Hex(), err)
+		return
+	}
+
+	var webhook Webhook
+	if err := d.store.webhooks.FindOne(ctx, bson.M{"_id": delivery.WebhookID}).Decode(&webhook); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// The webhook was deleted after the event; its log went with it
+			d.store.deliveries.DeleteOne(ctx, bson.M{"_id": id})
+			return
+		}
+		log.Printf("WARN: Failed to load webhook %s: %v", delivery.WebhookID.Hex(), err)
+		return
+	}
+
+	status, sendErr := d.send(ctx, webhook, delivery)
+	update := bson.M{"last_status": status}
+	switch {
+	case sendErr == nil:
+		update["status"] = StatusDelivered
+		update["delivered_at"] = time.Now().UTC()
+	case delivery.Attempts >= maxAttempts:
+		update["status"] = StatusFailed
+	default:
+		update["next_attempt_at"] = time.Now().UTC().Add(backoff(delivery.Attempts))
+	}
+	change := bson.M{"$set": update}
+	if sendErr != nil {
+		update["last_error"] = truncate(sendErr.Error(), maxErrorLength)
+	} else {
+		change["$unset"] = bson.M{"last_error": ""}
+	}
+	if _, err := d.store.deliveries.UpdateByID(ctx, id, change); err != nil {
+		log.Printf("WARN: Failed to record webhook delivery %s: %v", id.Hex(), err)
+	}
+}
+
+// send POSTs the delivery's payload, signed with the webhook's secret, and returns the response
+// status. Any status outside 2xx is an error.
+func (d *Dispatcher) send(ctx context.Context, webhook Webhook, delivery Delivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "WiseOwl-Webhooks/1.0")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID.Hex())
+	req.Header.Set(auth.WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(auth.WebhookSignatureHeader, auth.SignWebhook(webhook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096)) // Let the connection be reused
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the wait after a delivery's attempts-th failed attempt
+func backoff(attempts int) time.Duration {
+	return firstBackoff << (attempts - 1)
+}
+
+// refusePrivate is a dialer control that refuses connections to private, loopback and
+// link-local addresses, whatever the webhook's hostname resolved to
+func refusePrivate(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return fmt.Errorf("refusing to deliver to non-public address %s", host)
+	}
+	return nil
+}
+
+// publicIP reports whether ip is routable on the public internet
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() && !ip.IsMulticast()
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
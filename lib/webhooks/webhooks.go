@@ -0,0 +1,187 @@
+// FILE: lib/webhooks/webhooks.go
+// This package lets third parties, such as a teacher's dashboard, follow learners' progress.
+// A webhook is a URL registered by a user for their own events, or by an admin app for every
+// user's; each event it subscribes to is POSTed to it, signed with its secret, retried with
+// backoff until the receiver accepts it, and kept in a delivery log.
+
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/url"
+	"slices"
+	"time"
+
+	"wise-owl/lib/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Collection holds the registered webhooks, in the users service's database
+	Collection = "webhooks"
+	// DeliveryCollection is the delivery log
+	DeliveryCollection = "webhook_deliveries"
+	// MaxPerOwner caps the webhooks a user or app can register
+	MaxPerOwner = 10
+	// deliveryRetention is how long the delivery log is kept
+	deliveryRetention = 30 * 24 * time.Hour
+)
+
+// Indexes lists the indexes the webhook collections rely on.
+var Indexes = []database.Index{
+	// Events are matched to the webhooks of the user they are about and of every app
+	{Collection: Collection, Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "events", Value: 1}}},
+	// Logs are listed per webhook, newest first; the retry job picks up pending deliveries that are due
+	{Collection: DeliveryCollection, Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	{Collection: DeliveryCollection, Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+	{Collection: DeliveryCollection, Keys: bson.D{{Key: "created_at", Value: 1}}, ExpireAfter: deliveryRetention},
+}
+
+// ErrLimit is returned when an owner already has MaxPerOwner webhooks
+var ErrLimit = errors.New("webhook limit reached")
+
+// ErrInvalidURL is returned for URLs deliveries cannot be made to
+var ErrInvalidURL = errors.New("webhook URL must be https and name a public host")
+
+// Webhook is a registered receiver of events.
+type Webhook struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	OwnerID   string             `bson:"owner_id" json:"-"`                  // Auth0 ID of the registering user; empty for app webhooks, which get every user's events
+	App       string             `bson:"app,omitempty" json:"app,omitempty"` // Name of the admin app that registered it
+	URL       string             `bson:"url" json:"url"`
+	Secret    string             `bson:"secret" json:"-"` // Signs deliveries; shown only when the webhook is created
+	Events    []string           `bson:"events" json:"events"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"   // Waiting for its first or next attempt
+	StatusDelivered = "delivered" // Accepted with a 2xx response
+	StatusFailed    = "failed"    // Given up on after maxAttempts
+)
+
+// Delivery is one event sent, or to be sent, to one webhook.
+type Delivery struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WebhookID     primitive.ObjectID `bson:"webhook_id" json:"webhook_id"`
+	OwnerID       string             `bson:"owner_id" json:"-"` // The webhook's owner
+	EventID       string             `bson:"event_id" json:"event_id"`
+	EventType     string             `bson:"event_type" json:"event_type"`
+	UserID        string             `bson:"user_id" json:"-"` // The user the event is about
+	Payload       string             `bson:"payload" json:"-"` // The signed body, kept so retries send the same bytes
+	Status        string             `bson:"status" json:"status"`
+	Attempts      int                `bson:"attempts" json:"attempts"`
+	LastStatus    int                `bson:"last_status,omitempty" json:"last_status,omitempty"` // HTTP status of the last attempt
+	LastError     string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at" json:"next_attempt_at"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	DeliveredAt   *time.Time         `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+}
+
+// Store registers webhooks and reads their delivery logs.
+type Store struct {
+	webhooks   *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+// NewStore creates a store over the users service's database.
+func NewStore(db *mongo.Database) *Store {
+	return &Store{
+		webhooks:   db.Collection(Collection),
+		deliveries: db.Collection(DeliveryCollection),
+	}
+}
+
+// ValidateURL checks that deliveries may be made to rawURL: https, with no credentials, and not
+// addressed to a private or loopback IP. Hostnames are checked again when delivering, since
+// they can resolve anywhere.
+func ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return ErrInvalidURL
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !publicIP(ip) {
+		return ErrInvalidURL
+	}
+	return nil
+}
+
+// Create registers a webhook for ownerID, or an app webhook named app when ownerID is empty, and
+// returns it with its generated secret.
+func (s *Store) Create(ctx context.Context, ownerID, app, rawURL string, eventTypes []string) (Webhook, error) {
+	if err := ValidateURL(rawURL); err != nil {
+		return Webhook{}, err
+	}
+	count, err := s.webhooks.CountDocuments(ctx, bson.M{"owner_id": ownerID})
+	if err != nil {
+		return Webhook{}, err
+	}
+	if count >= MaxPerOwner {
+		return Webhook{}, ErrLimit
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return Webhook{}, err
+	}
+	webhook := Webhook{
+		ID:        primitive.NewObjectID(),
+		OwnerID:   ownerID,
+		App:       app,
+		URL:       rawURL,
+		Secret:    "whsec_" + hex.EncodeToString(secret),
+		Events:    slices.Compact(slices.Sorted(slices.Values(eventTypes))),
+		CreatedAt: time.Now().UTC(),
+	}
+	if _, err := s.webhooks.InsertOne(ctx, webhook); err != nil {
+		return Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// List returns ownerID's webhooks, or the app webhooks when ownerID is empty, oldest first.
+func (s *Store) List(ctx context.Context, ownerID string) ([]Webhook, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	return database.FindAll[Webhook](ctx, s.webhooks, bson.M{"owner_id": ownerID}, opts)
+}
+
+// Delete removes one of ownerID's webhooks and its delivery log, reporting whether it existed.
+func (s *Store) Delete(ctx context.Context, ownerID string, id primitive.ObjectID) (bool, error) {
+	result, err := s.webhooks.DeleteOne(ctx, bson.M{"_id": id, "owner_id": ownerID})
+	if err != nil || result.DeletedCount == 0 {
+		return false, err
+	}
+	_, err = s.deliveries.DeleteMany(ctx, bson.M{"webhook_id": id})
+	return true, err
+}
+
+// Deliveries returns up to limit of the latest deliveries to one of ownerID's webhooks, newest
+// first. It returns mongo.ErrNoDocuments when ownerID has no such webhook.
+func (s *Store) Deliveries(ctx context.Context, ownerID string, id primitive.ObjectID, limit int) ([]Delivery, error) {
+	count, err := s.webhooks.CountDocuments(ctx, bson.M{"_id": id, "owner_id": ownerID})
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
+	return database.FindAll[Delivery](ctx, s.deliveries, bson.M{"webhook_id": id}, opts)
+}
+
+// subscribers returns the webhooks that receive eventType for userID: theirs and every app's
+func (s *Store) subscribers(ctx context.Context, userID, eventType string) ([]Webhook, error) {
+	return database.FindAll[Webhook](ctx, s.webhooks, bson.M{
+		"owner_id": bson.M{"$in": bson.A{userID, ""}},
+		"events":   eventType,
+	})
+}
@@ -0,0 +1,213 @@
+// FILE: services/admin/cmd/main.go
+// Entry point for the Wise Owl Admin Service, which gives support staff and operators
+// internal endpoints for user lookups, account deletion, seeding, feature flags, and content review.
+
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/auth"
+	"wise-owl/lib/compress"
+	"wise-owl/lib/config"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/database"
+	"wise-owl/lib/flags"
+	"wise-owl/lib/health"
+	"wise-owl/lib/metrics"
+	"wise-owl/lib/preset"
+	"wise-owl/lib/storage"
+	"wise-owl/lib/validation"
+	"wise-owl/services/admin/internal/handlers"
+	"wise-owl/services/admin/internal/snapshots"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AdminScope must be granted to a token for it to call the admin endpoints
+const AdminScope = "admin"
+
+func main() {
+	// 1. Load Configuration (supports both local and AWS environments)
+	cfg, err := config.LoadConfig("admin")
+	if err != nil {
+		log.Fatalf("FATAL: could not load config: %v", err)
+	}
+	stopMetrics := metrics.Start(cfg.Metrics, "admin") // CloudWatch EMF metrics, when EMF_METRICS_ENABLED is set
+	defer stopMetrics()
+
+	dbName := cfg.DB_NAME
+	if dbName == "" {
+		dbName = "admin_db"
+	}
+	log.Printf("Configuration loaded. Using database: %s (Type: %s)", dbName, cfg.DB_TYPE)
+
+	// 2. Connect to Database (supports MongoDB and DocumentDB)
+	// The admin service shares the cluster with the other services and reads their databases directly.
+	db, err := database.CreateDatabase(cfg)
+	if err != nil {
+		log.Fatalf("FATAL: Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+	mongoClient := db.GetClient().(*mongo.Client)
+	mongoDatabase := mongoClient.Database(dbName)
+	dbs := handlers.Databases{
+		Users:       mongoClient.Database(getEnv("USERS_DB_NAME", "users_db")),
+		Quiz:        mongoClient.Database(getEnv("QUIZ_DB_NAME", "quiz_db")),
+		Leaderboard: mongoClient.Database(getEnv("LEADERBOARD_DB_NAME", "leaderboard_db")),
+		Analytics:   mongoClient.Database(getEnv("ANALYTICS_DB_NAME", "analytics_db")),
+		Content:     mongoClient.Database(getEnv("CONTENT_DB_NAME", "content_db")),
+	}
+	log.Println("Database connection established.")
+
+	// 3. Initialize health checker
+	healthChecker := health.New("Admin Service", health.Options{
+		Database: mongoDatabase,
+		AWS:      config.IsAWSEnvironment(), // Deep checks and environment details for ECS
+	})
+
+	// 4. Avatars are deleted along with force-deleted accounts; content snapshots are kept here too
+	mediaStore, err := storage.New(context.Background(), cfg.Storage)
+	if err != nil {
+		log.Fatalf("FATAL: could not initialize storage: %v", err)
+	}
+
+	// 5. Initialize HTTP Router and Middleware
+	// Route objectives, reported at /health/slo and /metrics: support staff search users all day
+	slos := metrics.NewSLOs("admin",
+		metrics.Objective{Method: http.MethodGet, Route: "/internal/v1/admin/users", Availability: 0.99, Latency: time.Second, LatencyTarget: 0.99},
+	)
+	// Gin mode, request logs, and panic recovery suited to the environment (see lib/preset)
+	router := preset.For(cfg.Environment).Router()
+	router.ContextWithFallback = true // Handlers pass c to Mongo; let it carry the request's deadline
+	router.Use(slos.Middleware())     // Outside apierror.Middleware, so it records the status sent
+	router.Use(apierror.Middleware()) // Trace IDs and the standard error envelope
+	router.Use(metrics.Middleware())  // Request latency for CloudWatch
+	router.Use(compress.Gzip())       // Compress larger text and JSON responses
+	// Overall request deadline, answered with a 504 when it passes
+	router.Use(ctxutil.Timeout(cfg.RequestTimeout, map[string]time.Duration{
+		"/internal/v1/admin":         time.Minute, // Deleting a user touches every service's database
+		"/internal/v1/admin/seeders": 2 * time.Minute,
+		// Snapshots archive, and restores rewrite, the whole content database
+		"/internal/v1/admin/content/snapshots": 5 * time.Minute,
+	}))
+	router.Use(validation.SizeLimits(validation.DefaultLimits, nil)) // Reject oversized bodies and arrays before they are decoded
+
+	// Every admin endpoint needs a token granted the admin scope (skip if Auth0 not configured)
+	var adminAuth []gin.HandlerFunc
+	if cfg.Auth0Domain != "" && cfg.Auth0Audience != "" {
+		authMiddleware, err := auth.NewTokenMiddleware(auth.ConfigOptions(cfg))
+		if err != nil {
+			log.Fatalf("FATAL: could not set up token validation: %v", err)
+		}
+		adminAuth = []gin.HandlerFunc{authMiddleware, auth.RequireScope(AdminScope)}
+		log.Println("Auth0 authentication enabled")
+	} else {
+		log.Println("Authentication disabled for development")
+	}
+
+	userHandler := handlers.NewUserHandler(dbs, mediaStore)
+	flagHandler := handlers.NewFlagHandler(mongoDatabase)
+	seedHandler := handlers.NewSeedHandler(getContentHTTPURL())
+	audioHandler := handlers.NewAudioHandler(getContentHTTPURL())
+	reviewHandler := handlers.NewReviewHandler(mongoDatabase, dbs.Users, getContentHTTPURL())
+	webhookHandler := handlers.NewWebhookHandler(dbs.Users)
+	feedbackHandler := handlers.NewFeedbackHandler(dbs.Users)
+	snapshotHandler := handlers.NewSnapshotHandler(snapshots.NewStore(mongoDatabase, dbs.Content, mediaStore))
+
+	// 6. Register health check routes; results are refreshed in the background
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	defer stopHealth()
+	healthChecker.Start(healthCtx)
+	healthChecker.RegisterRoutes(router)
+	slos.RegisterRoutes(router)
+	// Prometheus scrape endpoint for SLOs; the gateway does not route it
+	router.GET("/metrics", metrics.Handler(slos))
+
+	// 7. Define Internal Routes (not routed through the gateway)
+	// The other services poll the flags through lib/flags
+	router.GET(flags.Path, flagHandler.ListFlags)
+
+	internal := router.Group("/internal/v1")
+	{
+		admin := internal.Group("/admin")
+		admin.Use(adminAuth...)
+		{
+			admin.GET("/users", userHandler.SearchUsers)
+			admin.GET("/users/:userId", userHandler.GetUser)
+			admin.GET("/users/:userId/quiz", userHandler.GetUserQuiz)
+			admin.DELETE("/users/:userId", userHandler.DeleteUser)
+
+			admin.POST("/seeders/content", seedHandler.RunContentSeeders)
+			admin.POST("/lessons/:lessonId/audio", audioHandler.RegenerateLessonAudio)
+
+			// Roll back bad bulk imports: snapshot content first, restore it if the import goes wrong
+			admin.GET("/content/snapshots", snapshotHandler.ListSnapshots)
+			admin.POST("/content/snapshots", snapshotHandler.CreateSnapshot)
+			admin.POST("/content/snapshots/:snapshotId/restore", snapshotHandler.RestoreSnapshot)
+
+			admin.GET("/flags", flagHandler.ListFlags)
+			admin.PUT("/flags/:name", flagHandler.SetFlag)
+			admin.DELETE("/flags/:name", flagHandler.DeleteFlag)
+
+			admin.GET("/webhooks", webhookHandler.ListWebhooks)
+			admin.POST("/webhooks", webhookHandler.CreateWebhook)
+			admin.DELETE("/webhooks/:webhookId", webhookHandler.DeleteWebhook)
+			admin.GET("/webhooks/:webhookId/deliveries", webhookHandler.GetWebhookDeliveries)
+
+			admin.GET("/feedback", feedbackHandler.ListFeedback)
+			admin.PATCH("/feedback/:feedbackId", feedbackHandler.UpdateFeedback)
+
+			// Corrections to reported words, published as revisions through the content service
+			admin.GET("/reviews/words", reviewHandler.ListReportedWords)
+			admin.GET("/reviews", reviewHandler.ListCorrections)
+			admin.POST("/reviews", reviewHandler.ProposeCorrection)
+			admin.POST("/reviews/:reviewId/accept", reviewHandler.AcceptCorrection)
+			admin.POST("/reviews/:reviewId/reject", reviewHandler.RejectCorrection)
+		}
+	}
+
+	// 8. Start HTTP Server with Graceful Shutdown
+	srv := &http.Server{Addr: ":" + cfg.ServerPort, Handler: router}
+	go func() {
+		log.Printf("Admin HTTP server listening on port %s", cfg.ServerPort)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("FATAL: listen: %s\n", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down Admin Service...")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv.Shutdown(ctx)
+}
+
+// getContentHTTPURL returns the base URL of the content service's HTTP API
+func getContentHTTPURL() string {
+	if url := os.Getenv("CONTENT_HTTP_URL"); url != "" {
+		return url
+	}
+	if config.IsAWSEnvironment() {
+		// Default for ECS service discovery
+		return "http://content-service.wise-owl-cluster.local:8080"
+	}
+	return "http://content-service:8080"
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,72 @@
+// FILE: services/admin/internal/handlers/audio_handlers.go
+// This file has lesson audio voiced again through the content service's internal API.
+
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"wise-owl/lib/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AudioHandler asks the content service to regenerate pronunciation audio.
+type AudioHandler struct {
+	contentURL string
+	httpClient *http.Client
+}
+
+// NewAudioHandler creates a handler for the content service's HTTP API at contentURL.
+func NewAudioHandler(contentURL string) *AudioHandler {
+	return &AudioHandler{
+		contentURL: strings.TrimSuffix(contentURL, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second}, // Generation runs after the content service answers
+	}
+}
+
+// RegenerateLessonAudio queues every word of a lesson to be voiced again with speech synthesis and
+// returns the content service's count of words queued. The audio is replaced in the background.
+func (h *AudioHandler) RegenerateLessonAudio(c *gin.Context) {
+	lessonID := c.Param("lessonId")
+	path := "/internal/v1/lessons/" + url.PathEscape(lessonID) + "/audio"
+	req, err := http.NewRequestWithContext(c, http.MethodPost, h.contentURL+path, nil)
+	if err != nil {
+		c.Error(apierror.Internal("regeneration_failed", err))
+		return
+	}
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+	case http.StatusNotFound:
+		c.Error(apierror.NotFound("lesson_not_found", "Lesson not found."))
+		return
+	case http.StatusServiceUnavailable:
+		c.Error(apierror.ServiceUnavailable("audio_disabled", "Audio generation is not configured.",
+			fmt.Errorf("content service: %s", body)))
+		return
+	default:
+		c.Error(apierror.Internal("regeneration_failed", fmt.Errorf("content service: status %d: %s", resp.StatusCode, body)))
+		return
+	}
+
+	log.Printf("AUDIT: %s queued the audio of lesson %s for regeneration", c.GetString("userID"), lessonID)
+	c.Data(http.StatusAccepted, "application/json; charset=utf-8", body)
+}
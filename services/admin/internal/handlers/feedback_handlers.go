@@ -0,0 +1,80 @@
+// FILE: services/admin/internal/handlers/feedback_handlers.go
+// This file triages users' feedback and content error reports, which the users service takes
+// and keeps in its database.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/feedback"
+	"wise-owl/lib/validation"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// FeedbackHandler holds the feedback store.
+type FeedbackHandler struct {
+	store *feedback.Store
+}
+
+// NewFeedbackHandler creates a new handler over the users service's database.
+func NewFeedbackHandler(usersDB *mongo.Database) *FeedbackHandler {
+	return &FeedbackHandler{store: feedback.NewStore(usersDB)}
+}
+
+// ListFeedback pages through reports, newest first, optionally narrowed to one status, category,
+// or vocabulary word.
+func (h *FeedbackHandler) ListFeedback(c *gin.Context) {
+	query := struct {
+		Status       string `form:"status" binding:"omitempty,oneof=open in_progress resolved dismissed"`
+		Category     string `form:"category" binding:"omitempty,oneof=translation audio content bug suggestion other"`
+		VocabularyID string `form:"vocabulary_id" binding:"omitempty,max=64"`
+		Limit        int    `form:"limit" binding:"omitempty,min=1,max=100"`
+		Offset       int    `form:"offset" binding:"omitempty,min=0"`
+	}{Limit: 20}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	filter := feedback.Filter{Status: query.Status, Category: query.Category, VocabularyID: query.VocabularyID}
+	reports, total, err := h.store.List(c, filter, query.Limit, query.Offset)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"feedback": reports, "total": total, "limit": query.Limit, "offset": query.Offset})
+}
+
+// UpdateFeedback moves a report to another status, optionally leaving a note on it.
+func (h *FeedbackHandler) UpdateFeedback(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("feedbackId"))
+	if err != nil {
+		c.Error(apierror.BadRequest("invalid_feedback_id", "Feedback ID must be a 24-character hex ObjectID."))
+		return
+	}
+	var req struct {
+		Status string  `json:"status" binding:"required,oneof=open in_progress resolved dismissed"`
+		Note   *string `json:"note" binding:"omitempty,max=2000"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	report, err := h.store.Triage(c, id, req.Status, req.Note)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.Error(apierror.NotFound("not_found", "Feedback not found."))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("update_failed", err))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,98 @@
+// FILE: services/admin/internal/handlers/flag_handlers.go
+// This file manages the feature flags other services read through lib/flags.
+
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/database"
+	"wise-owl/lib/flags"
+	"wise-owl/lib/validation"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// flagName restricts flag names to short lowercase identifiers such as "guest_mode" or "quiz.timer"
+var flagName = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// FlagHandler holds the feature flag collection.
+type FlagHandler struct {
+	flags *mongo.Collection
+}
+
+// NewFlagHandler creates a new handler with its dependencies.
+func NewFlagHandler(db *mongo.Database) *FlagHandler {
+	return &FlagHandler{
+		flags: db.Collection("feature_flags"),
+	}
+}
+
+// ListFlags returns every flag, sorted by name. Services poll it through lib/flags.
+func (h *FlagHandler) ListFlags(c *gin.Context) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	list, err := database.FindAll[flags.Flag](c, h.flags, bson.M{}, opts)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"flags": list})
+}
+
+// SetFlag creates or updates a flag. Services pick up the change on their next refresh.
+func (h *FlagHandler) SetFlag(c *gin.Context) {
+	name := c.Param("name")
+	if !flagName.MatchString(name) {
+		c.Error(apierror.BadRequest("invalid_flag_name", "Flag names are 1-64 lowercase letters, digits, '_', '.', or '-'."))
+		return
+	}
+
+	var req struct {
+		Enabled     *bool  `json:"enabled" binding:"required"`
+		Description string `json:"description" binding:"max=200"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	flag := flags.Flag{
+		Name:        name,
+		Enabled:     *req.Enabled,
+		Description: req.Description,
+		UpdatedBy:   c.GetString("userID"),
+		UpdatedAt:   time.Now().UTC(),
+	}
+	opts := options.Replace().SetUpsert(true)
+	if _, err := h.flags.ReplaceOne(c, bson.M{"_id": name}, flag, opts); err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	log.Printf("AUDIT: %s set feature flag %s to %t", flag.UpdatedBy, name, flag.Enabled)
+	c.JSON(http.StatusOK, flag)
+}
+
+// DeleteFlag removes a flag, so services fall back to its default.
+func (h *FlagHandler) DeleteFlag(c *gin.Context) {
+	name := c.Param("name")
+	res, err := h.flags.DeleteOne(c, bson.M{"_id": name})
+	if err != nil {
+		c.Error(apierror.Internal("delete_failed", err))
+		return
+	}
+	if res.DeletedCount == 0 {
+		c.Error(apierror.NotFound("not_found", "Feature flag not found."))
+		return
+	}
+
+	log.Printf("AUDIT: %s deleted feature flag %s", c.GetString("userID"), name)
+	c.Status(http.StatusNoContent)
+}
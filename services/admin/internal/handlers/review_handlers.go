@@ -0,0 +1,349 @@
+// FILE: services/admin/internal/handlers/review_handlers.go
+// This file runs the content review queue. Editors pick words users reported, propose corrections
+// that answer the reports, and accept or reject them. Accepted corrections are published as
+// revisions of the word by the content service, and the reports they answer are resolved with
+// the revision that fixed them.
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/database"
+	"wise-owl/lib/feedback"
+	"wise-owl/lib/validation"
+	"wise-owl/services/admin/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReviewHandler holds dependencies for the review queue handlers.
+type ReviewHandler struct {
+	corrections *mongo.Collection
+	feedback    *feedback.Store
+	contentURL  string
+	httpClient  *http.Client
+}
+
+// NewReviewHandler creates a handler keeping corrections in db, reading reports from the users
+// service's database, and publishing through the content service's HTTP API at contentURL.
+func NewReviewHandler(db, usersDB *mongo.Database, contentURL string) *ReviewHandler {
+	return &ReviewHandler{
+		corrections: db.Collection("corrections"),
+		feedback:    feedback.NewStore(usersDB),
+		contentURL:  strings.TrimSuffix(contentURL, "/"),
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ListReportedWords returns the words with open reports, the most reported first, for editors to
+// pick from. The reports themselves are listed by /feedback?vocabulary_id=.
+func (h *ReviewHandler) ListReportedWords(c *gin.Context) {
+	query := struct {
+		Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+	}{Limit: 20}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	words, err := h.feedback.Words(c, query.Limit)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"words": words})
+}
+
+// ListCorrections pages through corrections, newest first, optionally narrowed to one status or word.
+func (h *ReviewHandler) ListCorrections(c *gin.Context) {
+	query := struct {
+		Status       string `form:"status" binding:"omitempty,oneof=pending accepted rejected"`
+		VocabularyID string `form:"vocabulary_id" binding:"omitempty,max=64"`
+		Limit        int    `form:"limit" binding:"omitempty,min=1,max=100"`
+		Offset       int    `form:"offset" binding:"omitempty,min=0"`
+	}{Limit: 20}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	filter := bson.M{}
+	if query.Status != "" {
+		filter["status"] = query.Status
+	}
+	if query.VocabularyID != "" {
+		filter["vocabulary_id"] = query.VocabularyID
+	}
+	total, err := h.corrections.CountDocuments(c, filter)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(query.Offset)).
+		SetLimit(int64(query.Limit))
+	corrections, err := database.FindAll[models.Correction](c, h.corrections, filter, opts)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"corrections": corrections, "total": total, "limit": query.Limit, "offset": query.Offset})
+}
+
+// ProposeCorrection queues a correction to a word at its current version, and marks the reports it
+// answers as in progress.
+func (h *ReviewHandler) ProposeCorrection(c *gin.Context) {
+	var req struct {
+		VocabularyID string                   `json:"vocabulary_id" binding:"required"`
+		Version      int64                    `json:"version" binding:"min=0"`
+		Changes      models.VocabularyChanges `json:"changes"`
+		FeedbackIDs  []string                 `json:"feedback_ids" binding:"max=100"`
+		Note         string                   `json:"note" binding:"max=2000"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+	if !primitive.IsValidObjectID(req.VocabularyID) {
+		c.Error(apierror.BadRequest("invalid_vocabulary_id", "Vocabulary ID must be a 24-character hex ID."))
+		return
+	}
+	if req.Changes.Empty() {
+		c.Error(apierror.BadRequest("no_changes", "A correction must change at least one field."))
+		return
+	}
+	feedbackIDs := make([]primitive.ObjectID, 0, len(req.FeedbackIDs))
+	for _, hex := range req.FeedbackIDs {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			c.Error(apierror.BadRequest("invalid_feedback_id", "Feedback ID must be a 24-character hex ObjectID."))
+			return
+		}
+		feedbackIDs = append(feedbackIDs, id)
+	}
+
+	// Catch corrections written against an old copy of the word before they are queued
+	var current struct {
+		Vocabulary struct {
+			Version int64 `json:"version"`
+		} `json:"vocabulary"`
+	}
+	status, err := h.callContent(c, http.MethodGet, "/internal/v1/vocabulary/"+req.VocabularyID+"/revisions", nil, &current)
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+	switch {
+	case status == http.StatusNotFound:
+		c.Error(apierror.NotFound("not_found", "Vocabulary not found."))
+		return
+	case status != http.StatusOK:
+		c.Error(apierror.Internal("content_error", fmt.Errorf("content service: status %d", status)))
+		return
+	case current.Vocabulary.Version != req.Version:
+		c.Error(apierror.Conflict("version_conflict", "The word has changed since this version.").
+			WithDetails(gin.H{"version": current.Vocabulary.Version}))
+		return
+	}
+
+	now := time.Now().UTC()
+	correction := models.Correction{
+		ID:           primitive.NewObjectID(),
+		VocabularyID: req.VocabularyID,
+		Version:      req.Version,
+		Changes:      req.Changes,
+		FeedbackIDs:  feedbackIDs,
+		Status:       models.CorrectionPending,
+		ProposedBy:   c.GetString("userID"),
+		Note:         req.Note,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if _, err := h.corrections.InsertOne(c, correction); err != nil {
+		c.Error(apierror.Internal("create_failed", err))
+		return
+	}
+	if len(feedbackIDs) > 0 {
+		if err := h.feedback.Link(c, feedbackIDs, req.VocabularyID, correction.ID.Hex()); err != nil {
+			if _, deleteErr := h.corrections.DeleteOne(c, bson.M{"_id": correction.ID}); deleteErr != nil {
+				log.Printf("ERROR: Failed to withdraw correction %s: %v", correction.ID.Hex(), deleteErr)
+			}
+			if errors.Is(err, feedback.ErrNotAbout) {
+				c.Error(apierror.BadRequest("invalid_feedback_ids", "Every report must be an open report about this word."))
+				return
+			}
+			c.Error(apierror.Internal("create_failed", err))
+			return
+		}
+	}
+	c.JSON(http.StatusCreated, correction)
+}
+
+// AcceptCorrection publishes a pending correction as a revision of its word and resolves the
+// reports it answers. A word changed since the correction was proposed answers 409; the
+// correction stays pending, to be rejected and proposed again against the new version.
+func (h *ReviewHandler) AcceptCorrection(c *gin.Context) {
+	correction, note, ok := h.pendingCorrection(c)
+	if !ok {
+		return
+	}
+	reviewer := c.GetString("userID")
+
+	body := gin.H{
+		"version": correction.Version,
+		"changes": correction.Changes,
+		"source":  "review:" + correction.ID.Hex(),
+		"author":  reviewer,
+	}
+	var revision struct {
+		Version int64 `json:"version"`
+	}
+	status, err := h.callContent(c, http.MethodPost, "/internal/v1/vocabulary/"+correction.VocabularyID+"/revisions", body, &revision)
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+	switch status {
+	case http.StatusCreated:
+	case http.StatusNotFound:
+		c.Error(apierror.NotFound("not_found", "Vocabulary not found."))
+		return
+	case http.StatusConflict:
+		c.Error(apierror.Conflict("version_conflict", "The word has changed since this correction was proposed."))
+		return
+	default:
+		c.Error(apierror.Internal("publish_failed", fmt.Errorf("content service: status %d", status)))
+		return
+	}
+
+	correction, err = h.decide(c, correction.ID, models.CorrectionAccepted, revision.Version, reviewer, note)
+	if err != nil {
+		c.Error(apierror.Internal("update_failed", err))
+		return
+	}
+	if note == "" {
+		note = fmt.Sprintf("Fixed in revision %d.", revision.Version)
+	}
+	if err := h.feedback.Settle(c, correction.ID.Hex(), revision.Version, note); err != nil {
+		log.Printf("ERROR: Failed to resolve the reports of correction %s: %v", correction.ID.Hex(), err)
+	}
+
+	log.Printf("AUDIT: %s accepted correction %s, publishing revision %d of vocabulary %s", reviewer, correction.ID.Hex(), revision.Version, correction.VocabularyID)
+	c.JSON(http.StatusOK, correction)
+}
+
+// RejectCorrection closes a pending correction without publishing it. The reports it answered
+// go back to open.
+func (h *ReviewHandler) RejectCorrection(c *gin.Context) {
+	correction, note, ok := h.pendingCorrection(c)
+	if !ok {
+		return
+	}
+
+	correction, err := h.decide(c, correction.ID, models.CorrectionRejected, 0, c.GetString("userID"), note)
+	if err != nil {
+		c.Error(apierror.Internal("update_failed", err))
+		return
+	}
+	if err := h.feedback.Settle(c, correction.ID.Hex(), 0, ""); err != nil {
+		log.Printf("ERROR: Failed to reopen the reports of correction %s: %v", correction.ID.Hex(), err)
+	}
+	c.JSON(http.StatusOK, correction)
+}
+
+// pendingCorrection reads the :reviewId correction and the decision's note, answering the request
+// itself when the correction is missing or already decided.
+func (h *ReviewHandler) pendingCorrection(c *gin.Context) (models.Correction, string, bool) {
+	var correction models.Correction
+	id, err := primitive.ObjectIDFromHex(c.Param("reviewId"))
+	if err != nil {
+		c.Error(apierror.BadRequest("invalid_review_id", "Review ID must be a 24-character hex ObjectID."))
+		return correction, "", false
+	}
+	var req struct {
+		Note string `json:"note" binding:"max=2000"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := validation.BindJSON(c, &req); err != nil {
+			c.Error(err)
+			return correction, "", false
+		}
+	}
+
+	if err := h.corrections.FindOne(c, bson.M{"_id": id}).Decode(&correction); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.Error(apierror.NotFound("not_found", "Correction not found."))
+			return correction, "", false
+		}
+		c.Error(apierror.Internal("database_error", err))
+		return correction, "", false
+	}
+	if correction.Status != models.CorrectionPending {
+		c.Error(apierror.Conflict("already_decided", "This correction has already been accepted or rejected."))
+		return correction, "", false
+	}
+	return correction, req.Note, true
+}
+
+// decide records the decision on a correction and returns it afterwards.
+func (h *ReviewHandler) decide(c *gin.Context, id primitive.ObjectID, status string, revision int64, reviewer, note string) (models.Correction, error) {
+	set := bson.M{"status": status, "reviewed_by": reviewer, "updated_at": time.Now().UTC()}
+	if revision > 0 {
+		set["revision"] = revision
+	}
+	if note != "" {
+		set["note"] = note
+	}
+	var correction models.Correction
+	err := h.corrections.FindOneAndUpdate(c, bson.M{"_id": id}, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&correction)
+	return correction, err
+}
+
+// callContent sends a JSON request to the content service's internal API and decodes a successful
+// response into out. It returns the response status; errors are for requests that got no response.
+func (h *ReviewHandler) callContent(c *gin.Context, method, path string, body, out interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(c, method, h.contentURL+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, nil
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out); err != nil {
+		return 0, fmt.Errorf("content service: %w", err)
+	}
+	return resp.StatusCode, nil
+}
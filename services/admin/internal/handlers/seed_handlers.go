@@ -0,0 +1,61 @@
+// FILE: services/admin/internal/handlers/seed_handlers.go
+// This file re-runs the content seeders through the content service's internal API.
+
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"wise-owl/lib/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SeedHandler asks the content service to re-run its seeders.
+type SeedHandler struct {
+	contentURL string
+	httpClient *http.Client
+}
+
+// NewSeedHandler creates a handler for the content service's HTTP API at contentURL
+// (e.g. "http://content-service:8080").
+func NewSeedHandler(contentURL string) *SeedHandler {
+	return &SeedHandler{
+		contentURL: strings.TrimSuffix(contentURL, "/"),
+		httpClient: &http.Client{Timeout: 2 * time.Minute}, // Seeding an empty collection inserts every document
+	}
+}
+
+// RunContentSeeders re-seeds empty vocabulary, kana, course, and lesson collections and ensures the content indexes,
+// returning the content service's report of how many documents were inserted.
+func (h *SeedHandler) RunContentSeeders(c *gin.Context) {
+	req, err := http.NewRequestWithContext(c, http.MethodPost, h.contentURL+"/internal/v1/seed", nil)
+	if err != nil {
+		c.Error(apierror.Internal("seed_failed", err))
+		return
+	}
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		c.Error(apierror.Internal("seed_failed", fmt.Errorf("content service: status %d: %s", resp.StatusCode, body)))
+		return
+	}
+
+	log.Printf("AUDIT: %s re-ran the content seeders: %s", c.GetString("userID"), body)
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
@@ -0,0 +1,102 @@
+// FILE: services/admin/internal/handlers/snapshot_handlers.go
+// This file snapshots and restores the content database, to roll back bad bulk imports.
+
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/validation"
+	"wise-owl/services/admin/internal/snapshots"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SnapshotHandler holds the content snapshot store.
+type SnapshotHandler struct {
+	snapshots *snapshots.Store
+}
+
+// NewSnapshotHandler creates a new handler with its dependencies.
+func NewSnapshotHandler(store *snapshots.Store) *SnapshotHandler {
+	return &SnapshotHandler{snapshots: store}
+}
+
+// CreateSnapshot archives the vocabulary, kana, courses, chapters, and lessons to object storage.
+// Take one before a bulk import or seeder change.
+func (h *SnapshotHandler) CreateSnapshot(c *gin.Context) {
+	var req struct {
+		Note string `json:"note" binding:"max=200"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	snapshot, err := h.snapshots.Create(c, c.GetString("userID"), req.Note)
+	if err != nil {
+		c.Error(apierror.Internal("snapshot_failed", err))
+		return
+	}
+
+	log.Printf("AUDIT: %s snapshotted the content database as %s (%v)", snapshot.CreatedBy, snapshot.ID.Hex(), snapshot.Counts)
+	c.JSON(http.StatusCreated, snapshot)
+}
+
+// ListSnapshots returns the most recent snapshots, newest first.
+func (h *SnapshotHandler) ListSnapshots(c *gin.Context) {
+	query := struct {
+		Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+	}{Limit: 20}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	list, err := h.snapshots.List(c, query.Limit)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"snapshots": list})
+}
+
+// RestoreSnapshot rolls the content database back to a snapshot. With ?dry_run=true it only
+// reports, per collection, which documents would be put back, reverted, or deleted. A restore
+// first snapshots the current content; its ID is returned as "backup" to undo the restore.
+func (h *SnapshotHandler) RestoreSnapshot(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("snapshotId"))
+	if err != nil {
+		c.Error(apierror.BadRequest("invalid_snapshot_id", "Snapshot ID must be a 24-character hex ObjectID."))
+		return
+	}
+	query := struct {
+		DryRun bool `form:"dry_run"`
+	}{}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	restore, err := h.snapshots.Restore(c, id, query.DryRun, c.GetString("userID"))
+	switch {
+	case errors.Is(err, snapshots.ErrNotFound):
+		c.Error(apierror.NotFound("not_found", "Snapshot not found."))
+		return
+	case errors.Is(err, snapshots.ErrCorrupt):
+		c.Error(apierror.New(http.StatusUnprocessableEntity, "snapshot_corrupt", "The snapshot's archive is damaged and cannot be restored."))
+		return
+	case err != nil:
+		c.Error(apierror.Internal("restore_failed", err))
+		return
+	}
+
+	if !restore.DryRun {
+		log.Printf("AUDIT: %s restored the content database to snapshot %s (backup %s)", c.GetString("userID"), id.Hex(), restore.Backup.Hex())
+	}
+	c.JSON(http.StatusOK, restore)
+}
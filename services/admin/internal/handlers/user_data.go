@@ -0,0 +1,66 @@
+// FILE: services/admin/internal/handlers/user_data.go
+// This file lists where the other services keep per-user documents.
+
+package handlers
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Databases holds the databases of the services whose data the admin service reads and deletes.
+type Databases struct {
+	Users       *mongo.Database
+	Quiz        *mongo.Database
+	Leaderboard *mongo.Database
+	Analytics   *mongo.Database
+	Content     *mongo.Database // Snapshotted and restored, never changed otherwise
+}
+
+// userCollection is a collection holding per-user documents, keyed by Auth0 ID in one or more fields
+type userCollection struct {
+	collection *mongo.Collection
+	fields     []string
+}
+
+// name identifies the collection in deletion reports, e.g. "quiz_db.incorrect_words"
+func (u userCollection) name() string {
+	return u.collection.Database().Name() + "." + u.collection.Name()
+}
+
+// filter matches the documents that belong to any of the user's identities
+func (u userCollection) filter(auth0IDs []string) bson.M {
+	if len(u.fields) == 1 {
+		return bson.M{u.fields[0]: bson.M{"$in": auth0IDs}}
+	}
+	or := make(bson.A, len(u.fields))
+	for i, field := range u.fields {
+		or[i] = bson.M{field: bson.M{"$in": auth0IDs}}
+	}
+	return bson.M{"$or": or}
+}
+
+// userData lists every collection outside the users collection that holds per-user documents.
+// Keep it in step with the services' models when they start storing new per-user data.
+func (d Databases) userData() []userCollection {
+	return []userCollection{
+		{d.Users.Collection("activity"), []string{"user_id"}},
+		{d.Users.Collection("lesson_completions"), []string{"user_id"}},
+		{d.Users.Collection("tombstones"), []string{"user_id"}},
+		{d.Users.Collection("webhooks"), []string{"owner_id"}},
+		{d.Users.Collection("webhook_deliveries"), []string{"owner_id", "user_id"}},
+		{d.Users.Collection("api_usage"), []string{"user_id"}},
+		{d.Users.Collection("purchases"), []string{"user_id"}},
+		{d.Users.Collection("feedback"), []string{"user_id"}},
+		{d.Quiz.Collection("incorrect_words"), []string{"user_id"}},
+		{d.Quiz.Collection("answer_stats"), []string{"user_id"}},
+		{d.Quiz.Collection("mastery"), []string{"user_id"}},
+		{d.Quiz.Collection("synced_answers"), []string{"user_id"}},
+		{d.Quiz.Collection("tombstones"), []string{"user_id"}},
+		{d.Quiz.Collection("classrooms"), []string{"teacher_id"}},
+		{d.Quiz.Collection("class_members"), []string{"user_id"}},
+		{d.Leaderboard.Collection("xp_awards"), []string{"user_id"}},
+		{d.Leaderboard.Collection("friendships"), []string{"user_id", "friend_id"}},
+		{d.Analytics.Collection("user_daily"), []string{"user_id"}},
+	}
+}
@@ -0,0 +1,212 @@
+// FILE: services/admin/internal/handlers/user_handlers.go
+// This package serves the support and operations endpoints of the admin service.
+
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/database"
+	"wise-owl/lib/storage"
+	"wise-owl/lib/validation"
+	"wise-owl/services/admin/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UserHandler looks up and deletes user data across the services' databases.
+type UserHandler struct {
+	dbs   Databases
+	users *mongo.Collection
+	store storage.BlobStore // Holds avatars uploaded through the users service
+}
+
+// NewUserHandler creates a new handler with its dependencies.
+func NewUserHandler(dbs Databases, store storage.BlobStore) *UserHandler {
+	return &UserHandler{
+		dbs:   dbs,
+		users: dbs.Users.Collection("users"),
+		store: store,
+	}
+}
+
+// userKeys are the fields of a user document needed to find and delete the user's data
+type userKeys struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	Auth0ID   string             `bson:"auth0_id"`
+	Auth0IDs  []string           `bson:"auth0_ids"`
+	AvatarKey string             `bson:"avatar_key"`
+}
+
+// identities returns every Auth0 ID the user's data may be stored under
+func (k userKeys) identities() []string {
+	if len(k.Auth0IDs) == 0 {
+		return []string{k.Auth0ID}
+	}
+	return k.Auth0IDs
+}
+
+// SearchUsers lists users, newest first. With ?q= it matches an exact Auth0 ID or document ID,
+// or a case-insensitive prefix of the username or email.
+func (h *UserHandler) SearchUsers(c *gin.Context) {
+	query := struct {
+		Q      string `form:"q" binding:"omitempty,max=100"`
+		Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+		Offset int    `form:"offset" binding:"omitempty,min=0"`
+	}{Limit: 20}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	filter := bson.M{}
+	if query.Q != "" {
+		prefix := primitive.Regex{Pattern: "^" + regexp.QuoteMeta(query.Q), Options: "i"}
+		or := bson.A{
+			bson.M{"auth0_ids": query.Q},
+			bson.M{"username": prefix},
+			bson.M{"email": prefix},
+		}
+		if id, err := primitive.ObjectIDFromHex(query.Q); err == nil {
+			or = append(or, bson.M{"_id": id})
+		}
+		filter["$or"] = or
+	}
+
+	// Searching spans every user, which the tenant filter would otherwise log
+	ctx := database.CrossUser(c)
+	total, err := h.users.CountDocuments(ctx, filter)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(query.Offset)).
+		SetLimit(int64(query.Limit))
+	users, err := database.FindAll[models.UserSummary](ctx, h.users, filter, opts)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": users, "total": total, "limit": query.Limit, "offset": query.Offset})
+}
+
+// GetUser returns a user's full profile document. The :userId parameter is an Auth0 ID or document ID.
+func (h *UserHandler) GetUser(c *gin.Context) {
+	raw, _, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	var user bson.M
+	if err := bson.Unmarshal(raw, &user); err != nil {
+		c.Error(apierror.Internal("deserialization_error", err))
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// GetUserQuiz returns a user's quiz answer totals and incorrect words list, across all linked identities.
+func (h *UserHandler) GetUserQuiz(c *gin.Context) {
+	_, keys, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+	byIdentity := bson.M{"user_id": bson.M{"$in": keys.identities()}}
+
+	perIdentity, err := database.FindAll[models.AnswerStats](c, h.dbs.Quiz.Collection("answer_stats"), byIdentity)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	var stats models.AnswerStats
+	for _, s := range perIdentity {
+		stats.Total += s.Total
+		stats.Correct += s.Correct
+		stats.Incorrect += s.Incorrect
+		if s.LastAnsweredAt.After(stats.LastAnsweredAt) {
+			stats.LastAnsweredAt = s.LastAnsweredAt
+		}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "miss_count", Value: -1}, {Key: "created_at", Value: 1}})
+	words, err := database.FindAll[models.IncorrectWord](c, h.dbs.Quiz.Collection("incorrect_words"), byIdentity, opts)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"auth0_ids": keys.identities(), "answers": stats, "incorrect_words": words})
+}
+
+// DeleteUser force-deletes a user and every per-user document the services hold, then reports how many
+// documents were removed from each collection. The profile is deleted last, so a failed run can be retried.
+// The Auth0 account itself is left in place.
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	_, keys, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+	identities := keys.identities()
+
+	deleted := map[string]int64{}
+	for _, data := range h.dbs.userData() {
+		res, err := data.collection.DeleteMany(c, data.filter(identities))
+		if err != nil {
+			c.Error(apierror.Internal("delete_failed", err).WithDetails(gin.H{"deleted": deleted}))
+			return
+		}
+		deleted[data.name()] = res.DeletedCount
+	}
+
+	if keys.AvatarKey != "" {
+		if err := h.store.Delete(c, keys.AvatarKey); err != nil {
+			log.Printf("WARN: Failed to delete avatar %s: %v", keys.AvatarKey, err)
+		}
+	}
+
+	res, err := h.users.DeleteOne(c, bson.M{"_id": keys.ID})
+	if err != nil {
+		c.Error(apierror.Internal("delete_failed", err).WithDetails(gin.H{"deleted": deleted}))
+		return
+	}
+	deleted[h.users.Database().Name()+"."+h.users.Name()] = res.DeletedCount
+
+	log.Printf("AUDIT: %s force-deleted user %s (%v)", c.GetString("userID"), keys.ID.Hex(), identities)
+	c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+}
+
+// findUser loads the user named by the :userId parameter, writing a 404 when there is none
+func (h *UserHandler) findUser(c *gin.Context) (bson.Raw, userKeys, bool) {
+	userID := c.Param("userId")
+	filter := bson.M{"auth0_ids": userID}
+	if id, err := primitive.ObjectIDFromHex(userID); err == nil {
+		filter = bson.M{"_id": id}
+	}
+
+	raw, err := h.users.FindOne(c, filter).Raw()
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.Error(apierror.NotFound("not_found", "User not found."))
+			return nil, userKeys{}, false
+		}
+		c.Error(apierror.Internal("database_error", err))
+		return nil, userKeys{}, false
+	}
+
+	var keys userKeys
+	if err := bson.Unmarshal(raw, &keys); err != nil {
+		c.Error(apierror.Internal("deserialization_error", err))
+		return nil, userKeys{}, false
+	}
+	return raw, keys, true
+}
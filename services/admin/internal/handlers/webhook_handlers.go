@@ -0,0 +1,116 @@
+// FILE: services/admin/internal/handlers/webhook_handlers.go
+// This file manages app webhooks: integrations registered by an admin that receive the lesson and
+// streak events of every user. They live in the users service's database, which delivers them.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/validation"
+	"wise-owl/lib/webhooks"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// appOwner is the owner ID of app webhooks
+const appOwner = ""
+
+// WebhookHandler holds the app webhook store.
+type WebhookHandler struct {
+	store *webhooks.Store
+}
+
+// NewWebhookHandler creates a new handler over the users service's database.
+func NewWebhookHandler(usersDB *mongo.Database) *WebhookHandler {
+	return &WebhookHandler{store: webhooks.NewStore(usersDB)}
+}
+
+// ListWebhooks returns every app webhook.
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	list, err := h.store.List(c, appOwner)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"webhooks": list})
+}
+
+// CreateWebhook registers an app webhook. The response is the only time its signing secret is shown.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req struct {
+		App    string   `json:"app" binding:"required,max=64"`
+		URL    string   `json:"url" binding:"required,url,max=2048"`
+		Events []string `json:"events" binding:"required,min=1,dive,oneof=lesson.completed streak.milestone"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	webhook, err := h.store.Create(c, appOwner, req.App, req.URL, req.Events)
+	switch {
+	case errors.Is(err, webhooks.ErrInvalidURL):
+		c.Error(apierror.BadRequest("invalid_webhook_url", "Webhook URLs must use https and a public host."))
+		return
+	case errors.Is(err, webhooks.ErrLimit):
+		c.Error(apierror.Conflict("webhook_limit_reached", "The maximum number of app webhooks is registered."))
+		return
+	case err != nil:
+		c.Error(apierror.Internal("create_failed", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"webhook": webhook, "secret": webhook.Secret})
+}
+
+// DeleteWebhook removes an app webhook and its delivery log.
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("webhookId"))
+	if err != nil {
+		c.Error(apierror.BadRequest("invalid_webhook_id", "Webhook ID must be a 24-character hex ObjectID."))
+		return
+	}
+
+	deleted, err := h.store.Delete(c, appOwner, id)
+	if err != nil {
+		c.Error(apierror.Internal("delete_failed", err))
+		return
+	}
+	if !deleted {
+		c.Error(apierror.NotFound("not_found", "Webhook not found."))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries returns the latest deliveries to an app webhook, newest first.
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("webhookId"))
+	if err != nil {
+		c.Error(apierror.BadRequest("invalid_webhook_id", "Webhook ID must be a 24-character hex ObjectID."))
+		return
+	}
+	query := struct {
+		Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+	}{Limit: 20}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	deliveries, err := h.store.Deliveries(c, appOwner, id, query.Limit)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.Error(apierror.NotFound("not_found", "Webhook not found."))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
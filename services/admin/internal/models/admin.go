@@ -0,0 +1,88 @@
+// FILE: services/admin/internal/models/admin.go
+
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserSummary is a row in user search results, read from the users service's database.
+type UserSummary struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id"`
+	Auth0IDs      []string           `json:"auth0_ids" bson:"auth0_ids"` // Every linked Auth0 identity
+	Username      string             `json:"username" bson:"username"`
+	Email         string             `json:"email" bson:"email"`
+	EmailVerified bool               `json:"email_verified" bson:"email_verified"`
+	Provisional   bool               `json:"provisional,omitempty" bson:"provisional,omitempty"` // Created by the Auth0 webhook, not yet onboarded
+	CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// AnswerStats is a user's quiz answer totals, read from the quiz service's database.
+type AnswerStats struct {
+	Total          int64     `json:"total" bson:"total"`
+	Correct        int64     `json:"correct" bson:"correct"`
+	Incorrect      int64     `json:"incorrect" bson:"incorrect"`
+	LastAnsweredAt time.Time `json:"last_answered_at,omitempty" bson:"last_answered_at"`
+}
+
+// IncorrectWord is an entry on a user's incorrect words list, their review queue in the quiz service.
+type IncorrectWord struct {
+	UserID       string    `json:"user_id" bson:"user_id"` // The identity the word was recorded under
+	VocabularyID string    `json:"vocabulary_id" bson:"vocabulary_id"`
+	MissCount    int64     `json:"miss_count" bson:"miss_count"` // Absent on older records (count as 1)
+	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
+}
+
+// Correction statuses in the review queue.
+const (
+	CorrectionPending  = "pending"
+	CorrectionAccepted = "accepted" // Published as a revision of the word
+	CorrectionRejected = "rejected"
+)
+
+// VocabularyChanges are the editorial fields of a word a correction sets; nil fields are left as
+// they are. The content service publishes them as a revision of the word.
+type VocabularyChanges struct {
+	Kana     *string `json:"kana,omitempty" bson:"kana,omitempty"`
+	Kanji    *string `json:"kanji,omitempty" bson:"kanji,omitempty"`
+	Furigana *string `json:"furigana,omitempty" bson:"furigana,omitempty"`
+	Romaji   *string `json:"romaji,omitempty" bson:"romaji,omitempty"`
+	English  *string `json:"english,omitempty" bson:"english,omitempty"`
+	Burmese  *string `json:"burmese,omitempty" bson:"burmese,omitempty"`
+}
+
+// Empty reports whether the changes set no field.
+func (c VocabularyChanges) Empty() bool {
+	return c.Kana == nil && c.Kanji == nil && c.Furigana == nil && c.Romaji == nil && c.English == nil && c.Burmese == nil
+}
+
+// Correction is a proposed fix to a word in the review queue, usually prompted by users' reports.
+type Correction struct {
+	ID           primitive.ObjectID   `json:"id" bson:"_id"`
+	VocabularyID string               `json:"vocabulary_id" bson:"vocabulary_id"`
+	Version      int64                `json:"version" bson:"version"` // The word's version the correction was written against
+	Changes      VocabularyChanges    `json:"changes" bson:"changes"`
+	FeedbackIDs  []primitive.ObjectID `json:"feedback_ids" bson:"feedback_ids"` // The reports it answers
+	Status       string               `json:"status" bson:"status"`
+	Revision     int64                `json:"revision,omitempty" bson:"revision,omitempty"` // The word's version once accepted
+	ProposedBy   string               `json:"proposed_by" bson:"proposed_by"`
+	ReviewedBy   string               `json:"reviewed_by,omitempty" bson:"reviewed_by,omitempty"`
+	Note         string               `json:"note,omitempty" bson:"note,omitempty"`
+	CreatedAt    time.Time            `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at" bson:"updated_at"`
+}
+
+// ContentSnapshot is an archive of the content service's database in object storage, taken before
+// risky bulk changes so they can be rolled back.
+type ContentSnapshot struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	Key       string             `json:"key" bson:"key"`       // Object storage key of the archive
+	Size      int64              `json:"size" bson:"size"`     // Compressed bytes
+	SHA256    string             `json:"sha256" bson:"sha256"` // Of the archive, checked before a restore
+	Counts    map[string]int64   `json:"counts" bson:"counts"` // Documents per collection
+	Note      string             `json:"note,omitempty" bson:"note,omitempty"`
+	CreatedBy string             `json:"created_by" bson:"created_by"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,311 @@
+// FILE: services/admin/internal/snapshots/snapshots.go
+// This package snapshots the content service's database to object storage and restores it, so a
+// bad bulk import or seeder run can be rolled back. An archive is a gzip-compressed file of
+// canonical Extended JSON lines, one per document, which keeps ObjectIDs and dates intact.
+
+package snapshots
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"wise-owl/lib/database"
+	"wise-owl/lib/storage"
+	"wise-owl/services/admin/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collections are the content collections a snapshot holds. Derived data such as per-word answer
+// counts and lesson difficulty is left out; the content service rebuilds it from answers.
+var Collections = []string{"vocabulary", "kana", "courses", "chapters", "lessons"}
+
+const (
+	// keyPrefix is where archives are kept in object storage
+	keyPrefix = "snapshots/content/"
+	// maxSampleIDs caps the document IDs listed per change in a restore report
+	maxSampleIDs = 20
+	// restoreBatchSize is how many writes a restore sends at once
+	restoreBatchSize = 500
+)
+
+// ErrNotFound is returned for snapshots that do not exist
+var ErrNotFound = errors.New("snapshot not found")
+
+// ErrCorrupt is returned when an archive does not match the checksum recorded with it
+var ErrCorrupt = errors.New("snapshot archive is corrupt")
+
+// archiveLine is one document of an archive
+type archiveLine struct {
+	Collection string   `bson:"collection"`
+	Document   bson.Raw `bson:"document"`
+}
+
+// CollectionDiff is what restoring a snapshot changes in one collection.
+type CollectionDiff struct {
+	Collection  string   `json:"collection"`
+	Inserted    int      `json:"inserted"`  // Deleted since the snapshot
+	Replaced    int      `json:"replaced"`  // Changed since the snapshot
+	Deleted     int      `json:"deleted"`   // Created since the snapshot
+	Unchanged   int      `json:"unchanged"` // Left as they are
+	InsertedIDs []string `json:"inserted_ids,omitempty"`
+	ReplacedIDs []string `json:"replaced_ids,omitempty"`
+	DeletedIDs  []string `json:"deleted_ids,omitempty"`
+
+	writes []mongo.WriteModel
+}
+
+// Restore reports a restore, or with DryRun what a restore would change.
+type Restore struct {
+	Snapshot    primitive.ObjectID  `json:"snapshot"`
+	DryRun      bool                `json:"dry_run"`
+	Collections []CollectionDiff    `json:"collections"`
+	Backup      *primitive.ObjectID `json:"backup,omitempty"` // Snapshot taken just before, to undo the restore
+}
+
+// Store takes and restores snapshots.
+type Store struct {
+	content   *mongo.Database
+	snapshots *mongo.Collection
+	blobs     storage.BlobStore
+}
+
+// NewStore creates a store that snapshots content into blobs and records the snapshots in the
+// admin database.
+func NewStore(admin, content *mongo.Database, blobs storage.BlobStore) *Store {
+	return &Store{content: content, snapshots: admin.Collection("content_snapshots"), blobs: blobs}
+}
+
+// Create archives every content collection and records the snapshot.
+func (s *Store) Create(ctx context.Context, createdBy, note string) (*models.ContentSnapshot, error) {
+	snapshot := &models.ContentSnapshot{
+		ID:        primitive.NewObjectID(),
+		Counts:    make(map[string]int64, len(Collections)),
+		Note:      note,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now().UTC(),
+	}
+	snapshot.Key = keyPrefix + snapshot.ID.Hex() + ".jsonl.gz"
+
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	for _, name := range Collections {
+		count, err := writeCollection(ctx, gz, s.content.Collection(name))
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %s: %w", name, err)
+		}
+		snapshot.Counts[name] = count
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(archive.Bytes())
+	snapshot.SHA256 = hex.EncodeToString(sum[:])
+	snapshot.Size = int64(archive.Len())
+	if err := s.blobs.Put(ctx, snapshot.Key, &archive, snapshot.Size, "application/gzip"); err != nil {
+		return nil, fmt.Errorf("failed to store archive: %w", err)
+	}
+	if _, err := s.snapshots.InsertOne(ctx, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// List returns the snapshots, newest first.
+func (s *Store) List(ctx context.Context, limit int) ([]models.ContentSnapshot, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
+	return database.FindAll[models.ContentSnapshot](ctx, s.snapshots, bson.M{}, opts)
+}
+
+// Get returns a snapshot.
+func (s *Store) Get(ctx context.Context, id primitive.ObjectID) (*models.ContentSnapshot, error) {
+	var snapshot models.ContentSnapshot
+	err := s.snapshots.FindOne(ctx, bson.M{"_id": id}).Decode(&snapshot)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// Restore brings every content collection back to the snapshot: documents created since are
+// deleted, and changed or deleted ones are put back. With dryRun it only reports what would
+// change. Otherwise the current content is snapshotted first, so the restore can itself be undone.
+func (s *Store) Restore(ctx context.Context, id primitive.ObjectID, dryRun bool, restoredBy string) (*Restore, error) {
+	snapshot, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := s.read(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Restore{Snapshot: id, DryRun: dryRun, Collections: make([]CollectionDiff, 0, len(Collections))}
+	for _, name := range Collections {
+		diff, err := diffCollection(ctx, s.content.Collection(name), archived[name])
+		if err != nil {
+			return nil, fmt.Errorf("diff %s: %w", name, err)
+		}
+		result.Collections = append(result.Collections, diff)
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	backup, err := s.Create(ctx, restoredBy, "Before restoring snapshot "+id.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot current content: %w", err)
+	}
+	result.Backup = &backup.ID
+
+	for _, diff := range result.Collections {
+		collection := s.content.Collection(diff.Collection)
+		for start := 0; start < len(diff.writes); start += restoreBatchSize {
+			batch := diff.writes[start:min(start+restoreBatchSize, len(diff.writes))]
+			if _, err := collection.BulkWrite(ctx, batch, options.BulkWrite().SetOrdered(false)); err != nil {
+				return nil, fmt.Errorf("restore %s: %w", diff.Collection, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// read downloads a snapshot's archive, checks it, and returns its documents by collection and ID
+func (s *Store) read(ctx context.Context, snapshot *models.ContentSnapshot) (map[string]map[string]bson.Raw, error) {
+	body, err := s.blobs.Get(ctx, snapshot.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer body.Close()
+	archive, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	if sum := sha256.Sum256(archive); hex.EncodeToString(sum[:]) != snapshot.SHA256 {
+		return nil, ErrCorrupt
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	documents := make(map[string]map[string]bson.Raw, len(Collections))
+	for _, name := range Collections {
+		documents[name] = make(map[string]bson.Raw)
+	}
+	lines := bufio.NewReader(gz)
+	for {
+		line, err := lines.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var entry archiveLine
+			if err := bson.UnmarshalExtJSON(line, true, &entry); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+			}
+			if byID, ok := documents[entry.Collection]; ok {
+				byID[entry.Document.Lookup("_id").String()] = entry.Document
+			}
+		}
+		if err == io.EOF {
+			return documents, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+		}
+	}
+}
+
+// writeCollection appends every document of collection to an archive and returns how many it wrote
+func writeCollection(ctx context.Context, w io.Writer, collection *mongo.Collection) (int64, error) {
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var count int64
+	for cursor.Next(ctx) {
+		line, err := bson.MarshalExtJSON(archiveLine{Collection: collection.Name(), Document: cursor.Current}, true, false)
+		if err != nil {
+			return count, err
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, cursor.Err()
+}
+
+// diffCollection compares a collection with its archived documents and prepares the writes that
+// restore them
+func diffCollection(ctx context.Context, collection *mongo.Collection, archived map[string]bson.Raw) (CollectionDiff, error) {
+	diff := CollectionDiff{Collection: collection.Name()}
+	remaining := make(map[string]bson.Raw, len(archived))
+	for id, document := range archived {
+		remaining[id] = document
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		return diff, err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		current := bson.Raw(bytes.Clone(cursor.Current)) // The writes outlive the cursor's batch
+		id := current.Lookup("_id")
+		document, ok := remaining[id.String()]
+		delete(remaining, id.String())
+		switch {
+		case !ok:
+			diff.Deleted++
+			diff.DeletedIDs = sample(diff.DeletedIDs, id)
+			diff.writes = append(diff.writes, mongo.NewDeleteOneModel().SetFilter(bson.D{{Key: "_id", Value: id}}))
+		case bytes.Equal(document, current):
+			diff.Unchanged++
+		default:
+			diff.Replaced++
+			diff.ReplacedIDs = sample(diff.ReplacedIDs, id)
+			diff.writes = append(diff.writes, mongo.NewReplaceOneModel().SetFilter(bson.D{{Key: "_id", Value: id}}).SetReplacement(document))
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return diff, err
+	}
+
+	for _, document := range remaining {
+		id := document.Lookup("_id")
+		diff.Inserted++
+		diff.InsertedIDs = sample(diff.InsertedIDs, id)
+		diff.writes = append(diff.writes, mongo.NewReplaceOneModel().SetFilter(bson.D{{Key: "_id", Value: id}}).SetReplacement(document).SetUpsert(true))
+	}
+	return diff, nil
+}
+
+// sample adds a document ID to a report's list until it holds maxSampleIDs
+func sample(ids []string, id bson.RawValue) []string {
+	if len(ids) == maxSampleIDs {
+		return ids
+	}
+	if oid, ok := id.ObjectIDOK(); ok {
+		return append(ids, oid.Hex())
+	}
+	if str, ok := id.StringValueOK(); ok {
+		return append(ids, str)
+	}
+	return append(ids, id.String())
+}
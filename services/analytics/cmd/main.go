@@ -0,0 +1,150 @@
+// FILE: services/analytics/cmd/main.go
+// Entry point for the Wise Owl Analytics Service.
+
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/compress"
+	"wise-owl/lib/config"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/database"
+	"wise-owl/lib/events"
+	"wise-owl/lib/grpcdebug"
+	"wise-owl/lib/grpcutil"
+	"wise-owl/lib/health"
+	"wise-owl/lib/metrics"
+	"wise-owl/lib/preset"
+	"wise-owl/lib/validation"
+	"wise-owl/services/analytics/internal/handlers"
+	"wise-owl/services/analytics/internal/seeder"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func main() {
+	// 1. Load Configuration (supports both local and AWS environments)
+	cfg, err := config.LoadConfig("analytics")
+	if err != nil {
+		log.Fatalf("FATAL: could not load config: %v", err)
+	}
+	stopMetrics := metrics.Start(cfg.Metrics, "analytics") // CloudWatch EMF metrics, when EMF_METRICS_ENABLED is set
+	defer stopMetrics()
+
+	dbName := cfg.DB_NAME
+	if dbName == "" {
+		dbName = "analytics_db"
+	}
+	log.Printf("Configuration loaded. Using database: %s (Type: %s)", dbName, cfg.DB_TYPE)
+
+	// 2. Connect to Database (supports MongoDB and DocumentDB)
+	db, err := database.CreateDatabase(cfg)
+	if err != nil {
+		log.Fatalf("FATAL: Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+	mongoClient := db.GetClient().(*mongo.Client)
+	mongoDatabase := mongoClient.Database(dbName)
+	log.Println("Database connection established.")
+
+	// 3. Create indexes
+	seeder.SeedDatabase(mongoDatabase)
+
+	// 4. Initialize health checker
+	healthChecker := health.New("Analytics Service", health.Options{
+		Database: mongoDatabase,
+		AWS:      config.IsAWSEnvironment(), // Deep checks and environment details for ECS
+	})
+
+	// 5. gRPC Client Setup for Content Service (vocabulary -> lesson lookups)
+	contentServiceURL := getContentServiceURL()
+	dialOpts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, grpcdebug.DialOptions(cfg.GRPCDebugLog)...)
+	dialOpts = append(dialOpts, grpcutil.ClientOptions()...)
+	conn, err := grpc.Dial(grpcutil.ClientTarget(contentServiceURL), dialOpts...)
+	if err != nil {
+		log.Fatalf("Did not connect to content-service: %v", err)
+	}
+	defer conn.Close()
+	contentClient := pb_content.NewContentServiceClient(conn)
+	log.Printf("Successfully connected to content-service gRPC at %s", contentServiceURL)
+
+	// 6. Initialize HTTP Router and Middleware
+	// Route objectives, reported at /health/slo and /metrics: every other service publishes events here
+	slos := metrics.NewSLOs("analytics",
+		metrics.Objective{Method: http.MethodPost, Route: "/internal/v1/events", Availability: 0.999, Latency: 500 * time.Millisecond, LatencyTarget: 0.99},
+	)
+	// Gin mode, request logs, and panic recovery suited to the environment (see lib/preset)
+	router := preset.For(cfg.Environment).Router()
+	router.ContextWithFallback = true // Handlers pass c to Mongo; let it carry the request's deadline
+	router.Use(slos.Middleware())     // Outside apierror.Middleware, so it records the status sent
+	router.Use(apierror.Middleware()) // Trace IDs and the standard error envelope
+	router.Use(metrics.Middleware())  // Request latency for CloudWatch
+	router.Use(compress.Gzip())       // Compress larger text and JSON responses
+	// Overall request deadline, answered with a 504 when it passes
+	router.Use(ctxutil.Timeout(cfg.RequestTimeout, nil))
+	router.Use(validation.SizeLimits(validation.DefaultLimits, nil)) // Reject oversized bodies and arrays before they are decoded
+
+	analyticsHandler := handlers.NewAnalyticsHandler(mongoDatabase, contentClient)
+
+	// 7. Register health check routes; results are refreshed in the background
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	defer stopHealth()
+	healthChecker.Start(healthCtx)
+	healthChecker.RegisterRoutes(router)
+	slos.RegisterRoutes(router)
+	// Prometheus scrape endpoint for SLOs; the gateway does not route it
+	router.GET("/metrics", metrics.Handler(slos))
+
+	// 8. Define Internal Routes (event ingestion and dashboard reports; not routed through the gateway)
+	internal := router.Group("/internal/v1")
+	{
+		internal.POST("/events", events.Handler(analyticsHandler.HandleEvent))
+
+		reports := internal.Group("/analytics")
+		{
+			reports.GET("/words/most-missed", analyticsHandler.GetMostMissedWords)
+			reports.GET("/lessons/difficulty", analyticsHandler.GetLessonDifficulty)
+			reports.GET("/users/:userId/daily", analyticsHandler.GetUserDaily)
+		}
+	}
+
+	// 9. Start HTTP Server with Graceful Shutdown
+	srv := &http.Server{Addr: ":" + cfg.ServerPort, Handler: router}
+	go func() {
+		log.Printf("Analytics HTTP server listening on port %s", cfg.ServerPort)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("FATAL: listen: %s\n", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down Analytics Service...")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv.Shutdown(ctx)
+}
+
+// getContentServiceURL returns the appropriate content service URL based on environment
+func getContentServiceURL() string {
+	if url := os.Getenv("CONTENT_SERVICE_URL"); url != "" {
+		return url
+	}
+	if config.IsAWSEnvironment() {
+		// Default for ECS service discovery
+		return "content-service.wise-owl-cluster.local:50052"
+	}
+	return "content-service:50052"
+}
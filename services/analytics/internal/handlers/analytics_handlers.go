@@ -0,0 +1,262 @@
+// FILE: services/analytics/internal/handlers/analytics_handlers.go
+// This package rolls domain events up into daily per-user, per-lesson, and per-word counters
+// and serves the internal reporting endpoints built on them.
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/database"
+	"wise-owl/lib/events"
+	"wise-owl/lib/validation"
+	"wise-owl/services/analytics/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// dateLayout is the format of rollup dates. Rollups use UTC days.
+const dateLayout = "2006-01-02"
+
+// AnalyticsHandler holds the rollup collections.
+type AnalyticsHandler struct {
+	processed   *mongo.Collection
+	userDaily   *mongo.Collection
+	lessonDaily *mongo.Collection
+	wordDaily   *mongo.Collection
+	lessons     *lessonResolver
+}
+
+// NewAnalyticsHandler creates a new handler with its dependencies.
+// The content client is used to find the lesson of answered and reviewed words.
+func NewAnalyticsHandler(db *mongo.Database, contentClient pb_content.ContentServiceClient) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		processed:   db.Collection("processed_events"),
+		userDaily:   db.Collection("user_daily"),
+		lessonDaily: db.Collection("lesson_daily"),
+		wordDaily:   db.Collection("word_daily"),
+		lessons:     newLessonResolver(contentClient),
+	}
+}
+
+// HandleEvent adds a domain event to the daily rollups. Redelivered events are ignored.
+func (h *AnalyticsHandler) HandleEvent(ctx context.Context, event events.Event) error {
+	switch event.Type {
+	case events.TypeQuizAnswer, events.TypeSRSReview, events.TypeLessonViewed:
+	default:
+		return nil
+	}
+
+	marker := models.ProcessedEvent{EventID: event.ID, EventType: event.Type, ProcessedAt: time.Now().UTC()}
+	if err := database.InsertUnique(ctx, h.processed, marker); err != nil {
+		if errors.Is(err, database.ErrDuplicate) {
+			return nil
+		}
+		return err
+	}
+
+	if err := h.apply(ctx, event); err != nil {
+		// Forget the event so the publisher's retry can count it
+		if _, delErr := h.processed.DeleteOne(ctx, bson.M{"_id": event.ID}); delErr != nil {
+			log.Printf("WARN: Failed to release event %s after error: %v", event.ID, delErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// apply increments the rollups an event contributes to.
+func (h *AnalyticsHandler) apply(ctx context.Context, event events.Event) error {
+	date := event.OccurredAt.UTC().Format(dateLayout)
+	vocabularyID, _ := event.Data["vocabulary_id"].(string)
+
+	switch event.Type {
+	case events.TypeQuizAnswer:
+		if vocabularyID == "" {
+			return nil
+		}
+		correct, _ := event.Data["correct"].(bool)
+		outcome, missed := "correct", 0
+		if !correct {
+			outcome, missed = "incorrect", 1
+		}
+		lesson := h.lessonOf(ctx, vocabularyID)
+
+		if err := h.incUser(ctx, event.UserID, date, bson.M{"answers": 1, outcome: 1}); err != nil {
+			return err
+		}
+		if err := h.incLesson(ctx, lesson, date, bson.M{"answers": 1, outcome: 1}); err != nil {
+			return err
+		}
+		wordUpdate := bson.M{"$inc": bson.M{"answers": 1, "incorrect": missed}}
+		if lesson != "" {
+			wordUpdate["$set"] = bson.M{"lesson": lesson}
+		}
+		return h.upsert(ctx, h.wordDaily, bson.M{"vocabulary_id": vocabularyID, "date": date}, wordUpdate)
+
+	case events.TypeSRSReview:
+		if err := h.incUser(ctx, event.UserID, date, bson.M{"reviews": 1}); err != nil {
+			return err
+		}
+		if vocabularyID == "" {
+			return nil
+		}
+		return h.incLesson(ctx, h.lessonOf(ctx, vocabularyID), date, bson.M{"reviews": 1})
+
+	case events.TypeLessonViewed:
+		lesson, _ := event.Data["lesson"].(string)
+		if err := h.incUser(ctx, event.UserID, date, bson.M{"lessons_viewed": 1}); err != nil {
+			return err
+		}
+		return h.incLesson(ctx, lesson, date, bson.M{"views": 1})
+	}
+	return nil
+}
+
+// lessonOf looks up a word's lesson. Lookup failures only cost the lesson rollup, so they are logged.
+func (h *AnalyticsHandler) lessonOf(ctx context.Context, vocabularyID string) string {
+	lesson, err := h.lessons.Lesson(ctx, vocabularyID)
+	if err != nil {
+		log.Printf("WARN: Failed to resolve lesson for vocabulary %s: %v", vocabularyID, err)
+	}
+	return lesson
+}
+
+func (h *AnalyticsHandler) incUser(ctx context.Context, userID, date string, inc bson.M) error {
+	if userID == "" {
+		return nil
+	}
+	return h.upsert(ctx, h.userDaily, bson.M{"user_id": userID, "date": date}, bson.M{"$inc": inc})
+}
+
+func (h *AnalyticsHandler) incLesson(ctx context.Context, lesson, date string, inc bson.M) error {
+	if lesson == "" {
+		return nil
+	}
+	return h.upsert(ctx, h.lessonDaily, bson.M{"lesson": lesson, "date": date}, bson.M{"$inc": inc})
+}
+
+func (h *AnalyticsHandler) upsert(ctx context.Context, collection *mongo.Collection, filter, update bson.M) error {
+	_, err := collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// reportQuery holds the common report parameters.
+type reportQuery struct {
+	Days       int `form:"days" binding:"omitempty,min=1,max=365"`
+	Limit      int `form:"limit" binding:"omitempty,min=1,max=100"`
+	MinAnswers int `form:"min_answers" binding:"omitempty,min=1"`
+}
+
+// bindReportQuery parses the report parameters and applies their defaults.
+func bindReportQuery(c *gin.Context, defaultMinAnswers int) (reportQuery, string, bool) {
+	q := reportQuery{Days: 30, Limit: 20, MinAnswers: defaultMinAnswers}
+	if err := validation.BindQuery(c, &q); err != nil {
+		c.Error(err)
+		return q, "", false
+	}
+	since := time.Now().UTC().AddDate(0, 0, -(q.Days - 1)).Format(dateLayout)
+	return q, since, true
+}
+
+// missRate computes incorrect / answers in an aggregation stage.
+var missRate = bson.M{"$cond": bson.A{
+	bson.M{"$gt": bson.A{"$answers", 0}},
+	bson.M{"$divide": bson.A{"$incorrect", "$answers"}},
+	0,
+}}
+
+// GetMostMissedWords ranks words by how often they were answered incorrectly across all users.
+func (h *AnalyticsHandler) GetMostMissedWords(c *gin.Context) {
+	q, since, ok := bindReportQuery(c, 1)
+	if !ok {
+		return
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"date": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$vocabulary_id",
+			"lesson":    bson.M{"$max": "$lesson"},
+			"answers":   bson.M{"$sum": "$answers"},
+			"incorrect": bson.M{"$sum": "$incorrect"},
+		}}},
+		{{Key: "$match", Value: bson.M{"answers": bson.M{"$gte": q.MinAnswers}, "incorrect": bson.M{"$gt": 0}}}},
+		{{Key: "$addFields", Value: bson.M{"miss_rate": missRate}}},
+		{{Key: "$sort", Value: bson.D{{Key: "incorrect", Value: -1}, {Key: "miss_rate", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: q.Limit}},
+	}
+	words, err := database.AggregateAll[models.MissedWord](c, h.wordDaily, pipeline)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"since": since, "words": words})
+}
+
+// GetLessonDifficulty ranks lessons from hardest to easiest by the share of incorrect answers.
+// Lessons with fewer than min_answers answers in the window are left out as too noisy to rank.
+func (h *AnalyticsHandler) GetLessonDifficulty(c *gin.Context) {
+	q, since, ok := bindReportQuery(c, 20)
+	if !ok {
+		return
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"date": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$lesson",
+			"answers":   bson.M{"$sum": "$answers"},
+			"incorrect": bson.M{"$sum": "$incorrect"},
+			"views":     bson.M{"$sum": "$views"},
+		}}},
+		{{Key: "$match", Value: bson.M{"answers": bson.M{"$gte": q.MinAnswers}}}},
+		{{Key: "$addFields", Value: bson.M{"miss_rate": missRate}}},
+		{{Key: "$sort", Value: bson.D{{Key: "miss_rate", Value: -1}, {Key: "answers", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: q.Limit}},
+	}
+	lessons, err := database.AggregateAll[models.LessonDifficulty](c, h.lessonDaily, pipeline)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	for i := range lessons {
+		lessons[i].Rank = i + 1
+	}
+
+	c.JSON(http.StatusOK, gin.H{"since": since, "lessons": lessons})
+}
+
+// GetUserDaily returns a user's daily rollups, oldest first.
+func (h *AnalyticsHandler) GetUserDaily(c *gin.Context) {
+	_, since, ok := bindReportQuery(c, 1)
+	if !ok {
+		return
+	}
+
+	filter := bson.M{"user_id": c.Param("userId"), "date": bson.M{"$gte": since}}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}}).SetProjection(bson.M{"_id": 0})
+	cursor, err := h.userDaily.Find(c, filter, opts)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	days := []models.UserDaily{}
+	if err := cursor.All(c, &days); err != nil {
+		c.Error(apierror.Internal("deserialization_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"since": since, "days": days})
+}
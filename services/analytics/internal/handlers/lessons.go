@@ -0,0 +1,54 @@
+// FILE: services/analytics/internal/handlers/lessons.go
+// This file resolves vocabulary IDs to their lessons through the content service.
+
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb_content "wise-owl/gen/proto/content/v1"
+)
+
+// maxCachedLessons bounds the lookup cache; it is simply cleared when full.
+const maxCachedLessons = 20000
+
+// lessonResolver caches vocabulary-to-lesson lookups. Lesson assignments are seed data and rarely change.
+type lessonResolver struct {
+	client pb_content.ContentServiceClient
+
+	mu      sync.RWMutex
+	lessons map[string]string
+}
+
+func newLessonResolver(client pb_content.ContentServiceClient) *lessonResolver {
+	return &lessonResolver{client: client, lessons: make(map[string]string)}
+}
+
+// Lesson returns the lesson of a vocabulary item, or "" if it is unknown.
+func (r *lessonResolver) Lesson(ctx context.Context, vocabularyID string) (string, error) {
+	r.mu.RLock()
+	lesson, ok := r.lessons[vocabularyID]
+	r.mu.RUnlock()
+	if ok {
+		return lesson, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	res, err := r.client.GetVocabularyBatch(ctx, &pb_content.GetVocabularyBatchRequest{VocabularyIds: []string{vocabularyID}})
+	if err != nil {
+		return "", err
+	}
+	lesson = res.GetItems()[vocabularyID].GetLesson()
+
+	r.mu.Lock()
+	if len(r.lessons) >= maxCachedLessons {
+		r.lessons = make(map[string]string)
+	}
+	r.lessons[vocabularyID] = lesson
+	r.mu.Unlock()
+	return lesson, nil
+}
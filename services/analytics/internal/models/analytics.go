@@ -0,0 +1,62 @@
+// FILE: services/analytics/internal/models/analytics.go
+
+package models
+
+import "time"
+
+// ProcessedEvent marks a domain event as counted so redelivered events are not counted twice.
+type ProcessedEvent struct {
+	EventID     string    `bson:"_id"`
+	EventType   string    `bson:"event_type"`
+	ProcessedAt time.Time `bson:"processed_at"` // Expired by a TTL index once redelivery is no longer possible
+}
+
+// UserDaily is one user's activity on one UTC day.
+type UserDaily struct {
+	UserID        string `json:"user_id" bson:"user_id"`
+	Date          string `json:"date" bson:"date"` // "YYYY-MM-DD" (UTC)
+	Answers       int64  `json:"answers" bson:"answers"`
+	Correct       int64  `json:"correct" bson:"correct"`
+	Incorrect     int64  `json:"incorrect" bson:"incorrect"`
+	Reviews       int64  `json:"reviews" bson:"reviews"`
+	LessonsViewed int64  `json:"lessons_viewed" bson:"lessons_viewed"`
+}
+
+// LessonDaily is the activity on one lesson on one UTC day, across all users.
+type LessonDaily struct {
+	Lesson    string `json:"lesson" bson:"lesson"`
+	Date      string `json:"date" bson:"date"`
+	Answers   int64  `json:"answers" bson:"answers"`
+	Correct   int64  `json:"correct" bson:"correct"`
+	Incorrect int64  `json:"incorrect" bson:"incorrect"`
+	Reviews   int64  `json:"reviews" bson:"reviews"`
+	Views     int64  `json:"views" bson:"views"`
+}
+
+// WordDaily is the answer tally for one vocabulary item on one UTC day, across all users.
+type WordDaily struct {
+	VocabularyID string `json:"vocabulary_id" bson:"vocabulary_id"`
+	Lesson       string `json:"lesson,omitempty" bson:"lesson,omitempty"`
+	Date         string `json:"date" bson:"date"`
+	Answers      int64  `json:"answers" bson:"answers"`
+	Incorrect    int64  `json:"incorrect" bson:"incorrect"`
+}
+
+// MissedWord is a row in the most-missed words report.
+type MissedWord struct {
+	VocabularyID string  `json:"vocabulary_id" bson:"_id"`
+	Lesson       string  `json:"lesson,omitempty" bson:"lesson"`
+	Answers      int64   `json:"answers" bson:"answers"`
+	Incorrect    int64   `json:"incorrect" bson:"incorrect"`
+	MissRate     float64 `json:"miss_rate" bson:"miss_rate"`
+}
+
+// LessonDifficulty is a row in the lesson difficulty ranking.
+type LessonDifficulty struct {
+	Rank      int     `json:"rank" bson:"-"`
+	Lesson    string  `json:"lesson" bson:"_id"`
+	Answers   int64   `json:"answers" bson:"answers"`
+	Incorrect int64   `json:"incorrect" bson:"incorrect"`
+	MissRate  float64 `json:"miss_rate" bson:"miss_rate"`
+	Views     int64   `json:"views" bson:"views"`
+}
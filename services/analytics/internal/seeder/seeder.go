@@ -0,0 +1,42 @@
+// FILE: services/analytics/internal/seeder/seeder.go
+
+package seeder
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"wise-owl/lib/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// processedEventRetention is how long event IDs are remembered for deduplication.
+const processedEventRetention = 7 * 24 * time.Hour
+
+// Indexes lists every index the rollups rely on.
+var Indexes = []database.Index{
+	{Collection: "processed_events", Keys: bson.D{{Key: "processed_at", Value: 1}}, ExpireAfter: processedEventRetention},
+
+	// One rollup document per key per day; upserts rely on it
+	{Collection: "user_daily", Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "date", Value: 1}}, Unique: true},
+	{Collection: "lesson_daily", Keys: bson.D{{Key: "lesson", Value: 1}, {Key: "date", Value: 1}}, Unique: true},
+	{Collection: "word_daily", Keys: bson.D{{Key: "vocabulary_id", Value: 1}, {Key: "date", Value: 1}}, Unique: true},
+
+	// Support the date-windowed reports
+	{Collection: "user_daily", Keys: bson.D{{Key: "date", Value: 1}}},
+	{Collection: "lesson_daily", Keys: bson.D{{Key: "date", Value: 1}}},
+	{Collection: "word_daily", Keys: bson.D{{Key: "date", Value: 1}}},
+}
+
+// SeedDatabase ensures the declared indexes exist.
+// There is no seed data; rollups fill in as events arrive.
+func SeedDatabase(db *mongo.Database) {
+	if err := database.EnsureIndexes(context.Background(), db, Indexes); err != nil {
+		log.Printf("WARN: Failed to ensure analytics indexes: %v", err)
+	}
+
+	log.Println("Analytics service initialized successfully")
+}
@@ -4,34 +4,60 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/compress"
 	"wise-owl/lib/config"
+	"wise-owl/lib/ctxutil"
 	"wise-owl/lib/database"
+	"wise-owl/lib/events"
+	"wise-owl/lib/grpcdebug"
+	"wise-owl/lib/grpcutil"
 	"wise-owl/lib/health"
+	"wise-owl/lib/jobs"
+	"wise-owl/lib/metrics"
+	"wise-owl/lib/openapi"
+	"wise-owl/lib/preset"
+	"wise-owl/lib/romanize"
+	"wise-owl/lib/storage"
+	"wise-owl/lib/tts"
+	"wise-owl/lib/validation"
+	"wise-owl/services/content/internal/apidocs"
+	"wise-owl/services/content/internal/audio"
+	"wise-owl/services/content/internal/curriculum"
+	"wise-owl/services/content/internal/difficulty"
+	"wise-owl/services/content/internal/furigana"
 	content_grpc "wise-owl/services/content/internal/grpc"
 	"wise-owl/services/content/internal/handlers"
+	"wise-owl/services/content/internal/migrations"
+	"wise-owl/services/content/internal/revisions"
 	"wise-owl/services/content/internal/seeder"
 
-	pb "wise-owl/gen/proto/content"
+	pb "wise-owl/gen/proto/content/v1"
 
-	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
 	// 1. Load Configuration (supports both local and AWS environments)
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadConfig("content")
 	if err != nil {
 		log.Fatalf("FATAL: could not load config: %v", err)
 	}
+	stopMetrics := metrics.Start(cfg.Metrics, "content") // CloudWatch EMF metrics, when EMF_METRICS_ENABLED is set
+	defer stopMetrics()
 
 	dbName := cfg.DB_NAME
 	if dbName == "" {
@@ -40,48 +66,124 @@ func main() {
 	log.Printf("Configuration loaded. Using database: %s (Type: %s)", dbName, cfg.DB_TYPE)
 
 	// 2. Connect to Database (supports MongoDB and DocumentDB)
-	db := database.CreateDatabaseSingleton(cfg)
+	db, err := database.CreateDatabase(cfg)
+	if err != nil {
+		log.Fatalf("FATAL: Failed to connect to database: %v", err)
+	}
+	defer db.Close()
 	mongoClient := db.GetClient().(*mongo.Client)
 	mongoDatabase := mongoClient.Database(dbName)
 	log.Println("Database connection established.")
 
-	// 3. Seed data
-	seeder.SeedData(dbName, mongoClient)
+	// 3. Initialize health checker
+	healthChecker := health.New("Content Service", health.Options{
+		Database: mongoDatabase,
+		AWS:      config.IsAWSEnvironment(), // Deep checks and environment details for ECS
+	})
 
-	// 4. Initialize health checker (choose based on environment)
-	var healthChecker interface {
-		RegisterRoutes(*gin.Engine)
-		Handler() gin.HandlerFunc
-		ReadyHandler() gin.HandlerFunc
-	}
+	// Vocabulary is effectively read-only, so reads are cached in memory. The difficulty scorer
+	// writes through the same wrapper, which clears the cache when scores change.
+	vocabulary := database.NewCachedCollection(db.GetCollection(dbName, "vocabulary"), database.CacheOptions{
+		TTL:        10 * time.Minute,
+		MaxEntries: 1000,
+	})
+	// Lesson prerequisites are read for every unlock check
+	lessons := database.NewCachedCollection(db.GetCollection(dbName, "lessons"), database.CacheOptions{
+		TTL:        10 * time.Minute,
+		MaxEntries: 100,
+	})
+	// Edits published by other instances, and writes made outside the service, clear both caches
+	// as soon as the change stream reports them
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	go database.WatchChanges(watchCtx, mongoDatabase, map[string]func(){
+		"vocabulary": vocabulary.Invalidate,
+		"lessons":    lessons.Invalidate,
+	})
 
-	// Use AWS health checker if running in AWS environment
-	if config.IsAWSEnvironment() {
-		log.Println("AWS environment detected, using enhanced health checks")
-		awsHealthChecker := health.NewAWSHealthChecker("Content Service", mongoDatabase)
-		healthChecker = awsHealthChecker
-	} else {
-		log.Println("Local environment detected, using simple health checks")
-		simpleHealthChecker := health.NewSimpleHealthChecker("Content Service")
-		simpleHealthChecker.SetMongoClient(mongoClient, dbName)
-		healthChecker = simpleHealthChecker
-	}
+	// 4. Seed data, ensure indexes, and backfill pending migrations in the background.
+	// The servers come up straight away, but /health/ready stays 503 until these finish.
+	seedVocabulary := healthChecker.Warmup("seed-vocabulary")
+	seedKana := healthChecker.Warmup("seed-kana")
+	seedCourses := healthChecker.Warmup("seed-courses")
+	seedLessons := healthChecker.Warmup("seed-lessons")
+	ensureIndexes := healthChecker.Warmup("ensure-indexes")
+	runMigrations := healthChecker.Warmup("migrations")
+	go func() {
+		seedVocabulary.Run(func() error {
+			_, err := seeder.SeedData(db.GetCollection(dbName, "vocabulary"))
+			return err
+		})
+		seedKana.Run(func() error {
+			_, err := seeder.SeedKana(db.GetCollection(dbName, "kana"))
+			return err
+		})
+		seedCourses.Run(func() error {
+			_, err := seeder.SeedCourses(db.GetCollection(dbName, "courses"), db.GetCollection(dbName, "chapters"))
+			return err
+		})
+		seedLessons.Run(func() error {
+			_, err := seeder.SeedLessons(db.GetCollection(dbName, "lessons"))
+			return err
+		})
+		ensureIndexes.Run(func() error {
+			seeder.EnsureIndexes(dbName, mongoClient)
+			return nil
+		})
+		runMigrations.Run(func() error {
+			migrations.RunLessonRefs(context.Background(), db.GetCollection(dbName, "vocabulary"))
+			migrations.RunJLPTLevels(context.Background(), mongoDatabase.Collection("vocabulary"))
+			return nil
+		})
+		// Reads served during warmup may have cached documents from before the backfill
+		vocabulary.Invalidate()
+		lessons.Invalidate()
+		log.Println("Warmup complete.")
+	}()
+
+	// The furigana dictionary loads alongside seeding; furigana requests get a 503 until it is ready
+	var annotator atomic.Pointer[furigana.Annotator]
+	loadDictionary := healthChecker.Warmup("load-dictionary")
+	go loadDictionary.Run(func() error {
+		a, err := furigana.NewAnnotator()
+		if err != nil {
+			return err
+		}
+		annotator.Store(a)
+		return nil
+	})
+
+	// Courses and chapters group the lessons; their lesson counts come from the cached vocabulary
+	curriculumStore := curriculum.NewStore(mongoDatabase, lessons, vocabulary)
 
 	// 5. Start gRPC Server (for internal communication)
+	// Vocabulary audio lives in the shared object storage; the server hands out its URLs
+	mediaStore, err := storage.New(context.Background(), cfg.Storage)
+	if err != nil {
+		log.Fatalf("FATAL: could not initialize storage: %v", err)
+	}
+
 	grpcPort := cfg.GRPCPort
 	if grpcPort == "" {
 		grpcPort = "50052" // Default for content service
 	}
 
+	// Logging, metrics, panic recovery, and message size limits for every gRPC call
+	grpcMetrics := grpcutil.NewMetrics()
+	serverOpts := append(grpcutil.ServerOptions(grpcutil.Options{Metrics: grpcMetrics}), grpcdebug.ServerOptions(cfg.GRPCDebugLog)...)
+
 	go func() {
 		lis, err := net.Listen("tcp", ":"+grpcPort)
 		if err != nil {
 			log.Fatalf("FATAL: Failed to listen for gRPC: %v", err)
 		}
-		s := grpc.NewServer()
+		s := grpc.NewServer(serverOpts...)
 
 		// Register content service with mongo database
-		pb.RegisterContentServiceServer(s, content_grpc.NewServer(mongoDatabase))
+		pb.RegisterContentServiceServer(s, content_grpc.NewServer(vocabulary, mediaStore, curriculumStore))
+		// Standard gRPC health service, probed by services that depend on content
+		healthpb.RegisterHealthServer(s, grpchealth.NewServer())
+		preset.For(cfg.Environment).RegisterReflection(s) // grpcurl in development
 
 		log.Printf("Content gRPC server listening at %v", lis.Addr())
 		if err := s.Serve(lis); err != nil {
@@ -90,14 +192,79 @@ func main() {
 	}()
 
 	// 6. Initialize and Start Gin HTTP Server
-	router := gin.Default()
+	// Route objectives, reported at /health/slo and /metrics: every study session starts with a lesson
+	slos := metrics.NewSLOs("content",
+		metrics.Objective{Method: http.MethodGet, Route: "/api/v1/lessons", Availability: 0.999, Latency: 300 * time.Millisecond, LatencyTarget: 0.99},
+		metrics.Objective{Method: http.MethodGet, Route: "/api/v1/lessons/:lessonId", Availability: 0.999, Latency: 300 * time.Millisecond, LatencyTarget: 0.99},
+	)
+	// Gin mode, request logs, and panic recovery suited to the environment (see lib/preset)
+	router := preset.For(cfg.Environment).Router()
+	router.ContextWithFallback = true // Handlers pass c to Mongo; let it carry the request's deadline
+	router.Use(slos.Middleware())     // Outside apierror.Middleware, so it records the status sent
+	router.Use(apierror.Middleware()) // Trace IDs and the standard error envelope
+	router.Use(metrics.Middleware())  // Request latency for CloudWatch
+	router.Use(compress.Gzip())       // Compress larger text and JSON responses
+	router.Use(romanize.Middleware()) // Romaji in the style the caller sends in X-Romanization
+	// Overall request deadline, answered with a 504 when it passes
+	router.Use(ctxutil.Timeout(cfg.RequestTimeout, map[string]time.Duration{
+		"/internal/v1/seed": 2 * time.Minute, // Seeding inserts the whole dataset
+	}))
+	router.Use(validation.SizeLimits(validation.DefaultLimits, nil)) // Reject oversized bodies and arrays before they are decoded
+	// Bound each gRPC and database call a handler makes; calls never outlive the request itself
+	router.Use(ctxutil.Middleware(ctxutil.Budgets{GRPC: cfg.GRPCCallTimeout, Database: cfg.DBQueryTimeout}))
+
+	// Difficulty scores are recomputed from quiz answer counts once a day, on one instance only
+	scorer := difficulty.NewScorer(mongoDatabase, vocabulary)
+	scheduler := jobs.NewScheduler(jobs.NewMongoLocker(db.GetCollection(dbName, jobs.LeaseCollection)))
+	if err := scheduler.Add(jobs.Job{
+		Name:     "difficulty-recompute",
+		Schedule: difficulty.RecomputeSchedule,
+		Run:      scorer.Recompute,
+	}); err != nil {
+		log.Fatalf("FATAL: Failed to schedule difficulty recompute: %v", err)
+	}
+	// Words without pronunciation audio are voiced in batches when speech synthesis is configured
+	var audioGenerator *audio.Generator
+	audioJob := jobs.Job{Name: "vocabulary-audio", Schedule: audio.GenerateSchedule, Timeout: audio.GenerateTimeout}
+	synthesizer, err := tts.New(context.Background(), cfg.TTS)
+	switch {
+	case errors.Is(err, tts.ErrDisabled):
+		log.Println("Speech synthesis not configured; vocabulary audio will not be generated")
+	case err != nil:
+		log.Fatalf("FATAL: could not initialize speech synthesis: %v", err)
+	default:
+		audioGenerator = audio.NewGenerator(mongoDatabase, vocabulary, mediaStore, synthesizer)
+		audioJob.Run = audioGenerator.Run
+		if err := scheduler.Add(audioJob); err != nil {
+			log.Fatalf("FATAL: Failed to schedule audio generation: %v", err)
+		}
+	}
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	scheduler.Start(schedulerCtx)
 
 	// Initialize content handler
 	var contentHandler *handlers.ContentHandler
-	contentHandler = handlers.NewContentHandler(mongoDatabase)
+	contentHandler = handlers.NewContentHandler(vocabulary, scorer)
+	kanaHandler := handlers.NewKanaHandler(mongoDatabase)
+	seedHandler := handlers.NewSeedHandler(mongoDatabase, vocabulary)
+	furiganaHandler := handlers.NewFuriganaHandler(&annotator)
+	curriculumHandler := handlers.NewCurriculumHandler(curriculumStore, scorer)
+	revisionHandler := handlers.NewRevisionHandler(revisions.NewStore(mongoDatabase, vocabulary))
+	audioHandler := handlers.NewAudioHandler(audioGenerator, scheduler, audioJob)
 
-	// 7. Register health check routes
+	// 7. Register health check routes; results are refreshed in the background
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	defer stopHealth()
+	healthChecker.Start(healthCtx)
 	healthChecker.RegisterRoutes(router)
+	slos.RegisterRoutes(router)
+
+	// Prometheus scrape endpoint for the gRPC server and SLOs; the gateway does not route it
+	router.GET("/metrics", metrics.Handler(grpcMetrics, slos))
+
+	// Serve the API contract (and Swagger UI in development)
+	openapi.RegisterRoutes(router, apidocs.Spec, cfg.SwaggerUI)
 
 	// 8. Define API Routes
 	apiV1 := router.Group("/api/v1")
@@ -107,6 +274,30 @@ func main() {
 			lessonRoutes.GET("", contentHandler.GetLessons)
 			lessonRoutes.GET("/:lessonId", contentHandler.GetLessonContent)
 		}
+
+		kanaRoutes := apiV1.Group("/kana")
+		{
+			kanaRoutes.GET("", kanaHandler.GetKana)
+			kanaRoutes.GET("/quiz", kanaHandler.GetKanaQuiz)
+		}
+
+		apiV1.GET("/courses", curriculumHandler.GetCourses)
+		apiV1.GET("/chapters/:chapterId/lessons", curriculumHandler.GetChapterLessons)
+
+		apiV1.GET("/jlpt/:level", contentHandler.GetJLPTVocabulary)
+		apiV1.GET("/content/word-of-the-day", contentHandler.GetWordOfTheDay)
+		apiV1.POST("/content/furigana", furiganaHandler.Annotate)
+	}
+
+	// Internal event ingestion and operations (not routed through the gateway)
+	internal := router.Group("/internal/v1")
+	{
+		internal.POST("/events", events.Handler(scorer.HandleEvent))
+		internal.POST("/seed", seedHandler.RunSeeders) // Called by the admin service
+		// Corrections accepted in the admin service's review queue
+		internal.GET("/vocabulary/:vocabId/revisions", revisionHandler.GetRevisions)
+		internal.POST("/vocabulary/:vocabId/revisions", revisionHandler.PublishRevision)
+		internal.POST("/lessons/:lessonId/audio", audioHandler.RegenerateLessonAudio) // Called by the admin service
 	}
 
 	// 9. Graceful Shutdown Logic
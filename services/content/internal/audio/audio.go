@@ -0,0 +1,147 @@
+// FILE: services/content/internal/audio/audio.go
+// This package generates pronunciation audio for vocabulary with speech synthesis. A scheduled job
+// voices every word without audio, a batch per run, and editors can have a lesson's words voiced
+// again, e.g. after fixing their readings or changing the voice.
+
+package audio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"wise-owl/lib/database"
+	"wise-owl/lib/storage"
+	"wise-owl/lib/tts"
+	"wise-owl/services/content/internal/migrations"
+	"wise-owl/services/content/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// GenerateSchedule is when the content service's scheduler voices words missing audio.
+	GenerateSchedule = "@every 1h"
+	// GenerateTimeout bounds a run; a full batch takes a few minutes at Polly's request rate.
+	GenerateTimeout = 30 * time.Minute
+	// batchSize is how many words a run voices at most, leaving the rest for the next run
+	batchSize = 500
+)
+
+// Generator voices vocabulary and stores the audio in the shared object storage.
+type Generator struct {
+	vocabulary *mongo.Collection
+	cache      *database.CachedCollection // Cleared once a run has changed audio
+	media      storage.BlobStore
+	synth      tts.Synthesizer
+	running    atomic.Bool
+}
+
+// NewGenerator creates a generator over the content database.
+func NewGenerator(db *mongo.Database, cache *database.CachedCollection, media storage.BlobStore, synth tts.Synthesizer) *Generator {
+	return &Generator{vocabulary: db.Collection("vocabulary"), cache: cache, media: media, synth: synth}
+}
+
+// pending matches words without audio and words marked for regeneration
+var pending = bson.M{"$or": bson.A{bson.M{"audio": nil}, bson.M{"audio_stale": true}}}
+
+// Regenerate marks the words of a lesson to be voiced again and returns how many there are.
+// Their current audio stays until the new audio replaces it.
+func (g *Generator) Regenerate(ctx context.Context, lessonID string) (int64, error) {
+	result, err := g.vocabulary.UpdateMany(ctx, migrations.LessonRefs.DualReadFilter(lessonID),
+		bson.M{"$set": bson.M{"audio_stale": true}})
+	if err != nil {
+		return 0, err
+	}
+	return result.MatchedCount, nil
+}
+
+// Run voices up to batchSize pending words. A word that fails is logged and left pending for the
+// next run; the error reports how many failed. A run already in progress on this instance makes
+// Run return straight away.
+func (g *Generator) Run(ctx context.Context) error {
+	if !g.running.CompareAndSwap(false, true) {
+		log.Println("Audio generation is already running; skipping this run")
+		return nil
+	}
+	defer g.running.Store(false)
+
+	opts := options.Find().SetProjection(bson.M{"kana": 1, "audio": 1}).SetLimit(batchSize)
+	words, err := database.FindAll[models.Vocabulary](ctx, g.vocabulary, pending, opts)
+	if err != nil {
+		return err
+	}
+
+	var generated, failed int
+	var lastErr error
+	for _, word := range words {
+		if ctx.Err() != nil {
+			break
+		}
+		switch err := g.generate(ctx, word); {
+		case err == nil:
+			generated++
+		case errors.Is(err, errChanged):
+		default:
+			failed++
+			lastErr = err
+			log.Printf("WARN: Failed to generate audio for vocabulary %s: %v", word.ID.Hex(), err)
+		}
+	}
+	if generated > 0 {
+		g.cache.Invalidate()
+	}
+	log.Printf("Audio generation: %d of %d pending words voiced, %d failed", generated, len(words), failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d words failed, last: %v", failed, len(words), lastErr)
+	}
+	return ctx.Err()
+}
+
+// errChanged is returned when a word's audio changed while it was being voiced
+var errChanged = errors.New("audio changed concurrently")
+
+// generate voices one word by its kana, which unlike kanji has only one reading, and points the
+// word at the new audio. Each recording gets a new key so cached copies of the old one are never
+// served for the new, and the old recording is deleted once nothing refers to it.
+func (g *Generator) generate(ctx context.Context, word models.Vocabulary) error {
+	audio, err := g.synth.Synthesize(ctx, word.Kana)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("audio/vocabulary/%s/%d%s", word.ID.Hex(), time.Now().UnixNano(), tts.Extension)
+	if err := g.media.Put(ctx, key, bytes.NewReader(audio), int64(len(audio)), tts.ContentType); err != nil {
+		return err
+	}
+
+	// Only replace the audio the word had when it was read, so an upload made meanwhile is kept
+	filter := bson.M{"_id": word.ID, "audio": word.Audio}
+	update := bson.M{"$set": bson.M{"audio": key}, "$unset": bson.M{"audio_stale": ""}}
+	result, err := g.vocabulary.UpdateOne(ctx, filter, update)
+	if err == nil && result.MatchedCount == 0 {
+		err = errChanged
+	}
+	if err != nil {
+		g.deleteMedia(key)
+		return err
+	}
+	if word.Audio != nil {
+		g.deleteMedia(*word.Audio)
+	}
+	return nil
+}
+
+// deleteMedia removes a recording nothing refers to; failures only leave an orphaned object.
+func (g *Generator) deleteMedia(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := g.media.Delete(ctx, key); err != nil {
+		log.Printf("WARN: Failed to delete audio %s: %v", key, err)
+	}
+}
@@ -0,0 +1,116 @@
+// FILE: services/content/internal/curriculum/curriculum.go
+// This package serves the curriculum hierarchy above lessons: courses made of chapters, each of which
+// lists its lessons in study order. Lessons themselves are still identified by their vocabulary's
+// lesson string, so the hierarchy can be reshaped without touching the vocabulary.
+
+package curriculum
+
+import (
+	"context"
+	"errors"
+
+	"wise-owl/lib/database"
+	"wise-owl/services/content/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrNotFound is returned for chapters that do not exist
+var ErrNotFound = errors.New("chapter not found")
+
+// Store reads courses, chapters, and lesson prerequisites, and counts the vocabulary of lessons.
+type Store struct {
+	courses    database.CollectionInterface
+	chapters   database.CollectionInterface
+	lessons    database.CollectionInterface
+	vocabulary database.CollectionInterface
+}
+
+// NewStore creates a store over the content database's courses and chapters, the lessons, and the vocabulary.
+func NewStore(db *mongo.Database, lessons, vocabulary database.CollectionInterface) *Store {
+	return &Store{
+		courses:    db.Collection("courses"),
+		chapters:   db.Collection("chapters"),
+		lessons:    lessons,
+		vocabulary: vocabulary,
+	}
+}
+
+// Courses returns every course in curriculum order, each with its chapters in order.
+func (s *Store) Courses(ctx context.Context) ([]models.Course, error) {
+	courses, err := database.FindAll[models.Course](ctx, s.courses, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "order", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	chapters, err := database.FindAll[models.Chapter](ctx, s.chapters, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "course_id", Value: 1}, {Key: "order", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	byCourse := make(map[string][]models.Chapter, len(courses))
+	for _, chapter := range chapters {
+		byCourse[chapter.CourseID] = append(byCourse[chapter.CourseID], chapter)
+	}
+	for i := range courses {
+		courses[i].Chapters = byCourse[courses[i].ID]
+		if courses[i].Chapters == nil {
+			courses[i].Chapters = []models.Chapter{}
+		}
+	}
+	return courses, nil
+}
+
+// Chapter returns one chapter by its ID.
+func (s *Store) Chapter(ctx context.Context, id string) (models.Chapter, error) {
+	var chapter models.Chapter
+	err := s.chapters.FindOne(ctx, bson.M{"_id": id}).Decode(&chapter)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return chapter, ErrNotFound
+	}
+	return chapter, err
+}
+
+// lessonCount is a lesson with the number of vocabulary items in it
+type lessonCount struct {
+	Lesson string `bson:"_id"`
+	Count  int    `bson:"count"`
+}
+
+// LessonCounts returns how many words each of the given lessons has. Lessons without vocabulary are absent.
+func (s *Store) LessonCounts(ctx context.Context, lessons []string) (map[string]int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"lesson": bson.M{"$in": lessons}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$lesson", "count": bson.M{"$sum": 1}}}},
+	}
+	counts, err := database.AggregateAll[lessonCount](ctx, s.vocabulary, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int, len(counts))
+	for _, count := range counts {
+		result[count.Lesson] = count.Count
+	}
+	return result, nil
+}
+
+// Lessons returns the metadata of every lesson in curriculum order.
+func (s *Store) Lessons(ctx context.Context) ([]models.Lesson, error) {
+	return database.FindAll[models.Lesson](ctx, s.lessons, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "order", Value: 1}}))
+}
+
+// Unlocked reports whether a learner who completed the given lessons may study lesson: every
+// prerequisite must be completed. Lessons without prerequisites are always unlocked.
+func Unlocked(lesson models.Lesson, completed map[string]bool) bool {
+	for _, prerequisite := range lesson.Prerequisites {
+		if !completed[prerequisite] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,181 @@
+// FILE: services/content/internal/difficulty/difficulty.go
+// This package scores how hard each vocabulary item and lesson is from quiz miss rates.
+// Quiz answer events are counted as they arrive; scores are recomputed from the counts once a day.
+
+package difficulty
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"wise-owl/lib/database"
+	"wise-owl/lib/events"
+	"wise-owl/services/content/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// RecomputeSchedule is when the content service's scheduler refreshes the stored scores.
+	RecomputeSchedule = "0 3 * * *" // Daily at 03:00 UTC
+	// priorAnswers is how many answers at the global miss rate each word starts with,
+	// so a word missed once out of one answer does not jump to the top.
+	priorAnswers = 10
+)
+
+// Scorer counts answers per word and stores difficulty scores in the content database.
+type Scorer struct {
+	vocabulary database.CollectionInterface
+	answers    database.CollectionInterface
+	lessons    database.CollectionInterface
+}
+
+// NewScorer creates a scorer over the content database.
+// Scores are written to vocabulary, which may be a cached wrapper of the vocabulary collection.
+func NewScorer(db *mongo.Database, vocabulary database.CollectionInterface) *Scorer {
+	return &Scorer{
+		vocabulary: vocabulary,
+		answers:    db.Collection("vocabulary_answers"),
+		lessons:    db.Collection("lesson_difficulty"),
+	}
+}
+
+// HandleEvent counts quiz answers published by the quiz service. Other events are ignored.
+func (s *Scorer) HandleEvent(ctx context.Context, event events.Event) error {
+	if event.Type != events.TypeQuizAnswer {
+		return nil
+	}
+	vocabularyID, _ := event.Data["vocabulary_id"].(string)
+	if !primitive.IsValidObjectID(vocabularyID) {
+		return nil
+	}
+
+	missed := 0
+	if correct, _ := event.Data["correct"].(bool); !correct {
+		missed = 1
+	}
+	update := bson.M{"$inc": bson.M{"answers": 1, "incorrect": missed}}
+	_, err := s.answers.UpdateOne(ctx, bson.M{"_id": vocabularyID}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// Recompute scores every vocabulary item and lesson from the answer counts.
+//
+// A word's score is its miss rate smoothed toward the global miss rate, from 0 (never missed)
+// to 1 (always missed). A lesson's score is the mean of its words' scores, so words nobody
+// has answered yet count as average. Nothing is written until at least one answer is recorded.
+func (s *Scorer) Recompute(ctx context.Context) error {
+	var counts []models.VocabularyAnswers
+	cursor, err := s.answers.Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return err
+	}
+
+	var totalAnswers, totalIncorrect int64
+	byWord := make(map[string]models.VocabularyAnswers, len(counts))
+	for _, count := range counts {
+		byWord[count.VocabularyID] = count
+		totalAnswers += count.Answers
+		totalIncorrect += count.Incorrect
+	}
+	if totalAnswers == 0 {
+		return nil
+	}
+	globalRate := float64(totalIncorrect) / float64(totalAnswers)
+
+	var words []struct {
+		ID     primitive.ObjectID `bson:"_id"`
+		Lesson string             `bson:"lesson"`
+	}
+	cursor, err = s.vocabulary.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"lesson": 1}))
+	if err != nil {
+		return err
+	}
+	if err := cursor.All(ctx, &words); err != nil {
+		return err
+	}
+
+	type lessonTotals struct {
+		scoreSum float64
+		words    int
+		answers  int64
+	}
+	lessons := make(map[string]*lessonTotals)
+	wordWrites := make([]mongo.WriteModel, 0, len(words))
+	for _, word := range words {
+		count := byWord[word.ID.Hex()]
+		score := round(score(count.Answers, count.Incorrect, globalRate))
+		wordWrites = append(wordWrites, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": word.ID}).
+			SetUpdate(bson.M{"$set": bson.M{"difficulty": score}}))
+
+		totals, ok := lessons[word.Lesson]
+		if !ok {
+			totals = &lessonTotals{}
+			lessons[word.Lesson] = totals
+		}
+		totals.scoreSum += score
+		totals.words++
+		totals.answers += count.Answers
+	}
+	if len(wordWrites) > 0 {
+		if _, err := s.vocabulary.BulkWrite(ctx, wordWrites, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now().UTC()
+	lessonWrites := make([]mongo.WriteModel, 0, len(lessons))
+	for lesson, totals := range lessons {
+		doc := models.LessonDifficulty{
+			Lesson:    lesson,
+			Score:     round(totals.scoreSum / float64(totals.words)),
+			Answers:   totals.answers,
+			UpdatedAt: now,
+		}
+		lessonWrites = append(lessonWrites, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": lesson}).
+			SetReplacement(doc).
+			SetUpsert(true))
+	}
+	if len(lessonWrites) > 0 {
+		if _, err := s.lessons.BulkWrite(ctx, lessonWrites, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Recomputed difficulty scores for %d words in %d lessons", len(words), len(lessons))
+	return nil
+}
+
+// LessonScores returns the stored difficulty score of every scored lesson.
+func (s *Scorer) LessonScores(ctx context.Context) (map[string]float64, error) {
+	docs, err := database.FindAll[models.LessonDifficulty](ctx, s.lessons, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[string]float64, len(docs))
+	for _, doc := range docs {
+		scores[doc.Lesson] = doc.Score
+	}
+	return scores, nil
+}
+
+// score is the miss rate with priorAnswers answers at the global rate mixed in.
+func score(answers, incorrect int64, globalRate float64) float64 {
+	return (float64(incorrect) + priorAnswers*globalRate) / (float64(answers) + priorAnswers)
+}
+
+// round keeps three decimal places so scores are stable across recomputes.
+func round(x float64) float64 {
+	return math.Round(x*1000) / 1000
+}
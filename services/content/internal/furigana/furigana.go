@@ -0,0 +1,135 @@
+// FILE: services/content/internal/furigana/furigana.go
+// This package splits Japanese text into words and adds readings above their kanji, using the
+// kagome morphological analyzer with the bundled IPA dictionary, so every client renders ruby
+// text the same way.
+
+package furigana
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"wise-owl/lib/japanese"
+
+	"github.com/ikawaha/kagome-dict/ipa"
+	"github.com/ikawaha/kagome/v2/tokenizer"
+)
+
+// Segment is a run of a token's text. Reading is set on runs of kanji and empty on kana,
+// so a client puts ruby over 食 in 食べる but not over べる.
+type Segment struct {
+	Text    string `json:"text"`
+	Reading string `json:"reading,omitempty"`
+}
+
+// Token is one word of the analyzed text. Reading is in hiragana and only set for words with kanji.
+type Token struct {
+	Surface  string    `json:"surface"`
+	Reading  string    `json:"reading,omitempty"`
+	BaseForm string    `json:"base_form,omitempty"` // Dictionary form, e.g. 食べる for 食べ
+	POS      string    `json:"pos,omitempty"`       // Top-level part of speech, e.g. 動詞
+	Segments []Segment `json:"segments"`
+}
+
+// Annotator adds furigana to text. It is safe for concurrent use.
+type Annotator struct {
+	tokenizer *tokenizer.Tokenizer
+}
+
+// NewAnnotator loads the IPA dictionary, which takes around a second; load it once at startup.
+func NewAnnotator() (*Annotator, error) {
+	t, err := tokenizer.New(ipa.Dict(), tokenizer.OmitBosEos())
+	if err != nil {
+		return nil, err
+	}
+	return &Annotator{tokenizer: t}, nil
+}
+
+// Annotate splits text into tokens with readings. Whitespace tokens are kept so the text
+// can be rebuilt by joining the surfaces.
+func (a *Annotator) Annotate(text string) []Token {
+	analyzed := a.tokenizer.Tokenize(text)
+	tokens := make([]Token, 0, len(analyzed))
+	for _, t := range analyzed {
+		token := Token{Surface: t.Surface}
+		if pos := t.POS(); len(pos) > 0 && pos[0] != "*" {
+			token.POS = pos[0]
+		}
+		if base, ok := t.BaseForm(); ok && base != "*" && base != t.Surface {
+			token.BaseForm = base
+		}
+		if reading, ok := t.Reading(); ok && reading != "*" && hasKanji(t.Surface) {
+			token.Reading = japanese.ToHiragana(reading)
+		}
+		token.Segments = segments(t.Surface, token.Reading)
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// segments splits surface into kanji and non-kanji runs and assigns each kanji run its part of
+// reading, by matching the kana runs against the reading. When they cannot be lined up, as with
+// irregular readings, the whole surface gets the whole reading.
+func segments(surface, reading string) []Segment {
+	runs := splitRuns(surface)
+	if reading == "" {
+		return []Segment{{Text: surface}}
+	}
+	if len(runs) == 1 {
+		return []Segment{{Text: surface, Reading: reading}}
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for _, run := range runs {
+		if hasKanji(run) {
+			pattern.WriteString("(.+?)")
+		} else {
+			pattern.WriteString(regexp.QuoteMeta(japanese.ToHiragana(run)))
+		}
+	}
+	pattern.WriteString("$")
+
+	match := regexp.MustCompile(pattern.String()).FindStringSubmatch(reading)
+	if match == nil {
+		return []Segment{{Text: surface, Reading: reading}}
+	}
+
+	result := make([]Segment, 0, len(runs))
+	group := 1
+	for _, run := range runs {
+		if hasKanji(run) {
+			result = append(result, Segment{Text: run, Reading: match[group]})
+			group++
+		} else {
+			result = append(result, Segment{Text: run})
+		}
+	}
+	return result
+}
+
+// splitRuns splits s where it changes between kanji and other characters
+func splitRuns(s string) []string {
+	var runs []string
+	start, prevKanji := 0, false
+	for i, r := range s {
+		kanji := isKanji(r)
+		if i > 0 && kanji != prevKanji {
+			runs = append(runs, s[start:i])
+			start = i
+		}
+		prevKanji = kanji
+	}
+	return append(runs, s[start:])
+}
+
+// hasKanji reports whether s contains a kanji
+func hasKanji(s string) bool {
+	return strings.IndexFunc(s, isKanji) >= 0
+}
+
+// isKanji reports whether r is read as part of a kanji word, including the repetition mark 々
+func isKanji(r rune) bool {
+	return unicode.Is(unicode.Han, r) || r == '々' || r == '〆'
+}
@@ -0,0 +1,64 @@
+// FILE: services/content/internal/grpc/courses.go
+// The curriculum hierarchy for services that group lessons into chapters and courses, and the
+// lesson prerequisites that decide when each lesson unlocks.
+
+package grpc
+
+import (
+	"context"
+
+	pb "wise-owl/gen/proto/content/v1"
+	"wise-owl/services/content/internal/curriculum"
+)
+
+// ListCourses returns every course with its chapters and their lesson identifiers, in study order.
+func (s *Server) ListCourses(ctx context.Context, _ *pb.ListCoursesRequest) (*pb.ListCoursesResponse, error) {
+	courses, err := s.curriculum.Courses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ListCoursesResponse{Courses: make([]*pb.Course, len(courses))}
+	for i, course := range courses {
+		chapters := make([]*pb.Chapter, len(course.Chapters))
+		for j, chapter := range course.Chapters {
+			chapters[j] = &pb.Chapter{
+				Id:        chapter.ID,
+				CourseId:  chapter.CourseID,
+				Title:     chapter.Title,
+				LessonIds: chapter.Lessons,
+			}
+		}
+		res.Courses[i] = &pb.Course{
+			Id:          course.ID,
+			Title:       course.Title,
+			Description: course.Description,
+			Chapters:    chapters,
+		}
+	}
+	return res, nil
+}
+
+// GetLessonAvailability returns every lesson in study order with its prerequisites, marking which
+// ones a learner who completed the given lessons has unlocked.
+func (s *Server) GetLessonAvailability(ctx context.Context, req *pb.GetLessonAvailabilityRequest) (*pb.GetLessonAvailabilityResponse, error) {
+	lessons, err := s.curriculum.Lessons(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	completed := make(map[string]bool, len(req.CompletedLessonIds))
+	for _, id := range req.CompletedLessonIds {
+		completed[id] = true
+	}
+
+	res := &pb.GetLessonAvailabilityResponse{Lessons: make([]*pb.LessonAvailability, len(lessons))}
+	for i, lesson := range lessons {
+		res.Lessons[i] = &pb.LessonAvailability{
+			Id:              lesson.ID,
+			PrerequisiteIds: lesson.Prerequisites,
+			Unlocked:        curriculum.Unlocked(lesson, completed),
+		}
+	}
+	return res, nil
+}
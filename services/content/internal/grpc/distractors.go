@@ -0,0 +1,148 @@
+// FILE: services/content/internal/grpc/distractors.go
+// Picks plausible wrong answers for multiple-choice questions.
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"unicode/utf8"
+
+	pb "wise-owl/gen/proto/content/v1"
+	"wise-owl/services/content/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// defaultDistractors is used when the request does not set a count.
+	defaultDistractors = 3
+	// maxDistractors caps the count a caller may ask for.
+	maxDistractors = 10
+	// maxDistractorTargets caps the vocabulary IDs served per call.
+	maxDistractorTargets = 100
+)
+
+// Weights of the similarities that make a distractor plausible. Sharing a word class
+// matters most: a verb among noun choices gives the answer away.
+const (
+	weightWordClass   = 4
+	weightLesson      = 2
+	weightSameLength  = 2
+	weightCloseLength = 1
+)
+
+// GetDistractors returns, for each requested word, other words a learner could mistake for it.
+// Candidates share the word's class or lesson; ties are broken at random so repeated
+// quizzes vary. Distractors never share the word's English meaning or kana.
+func (s *Server) GetDistractors(ctx context.Context, req *pb.GetDistractorsRequest) (*pb.GetDistractorsResponse, error) {
+	if len(req.VocabularyIds) > maxDistractorTargets {
+		return nil, invalidArgument("too_many_ids", "vocabulary_ids", fmt.Sprintf("at most %d vocabulary IDs per call", maxDistractorTargets))
+	}
+	count := int(req.Count)
+	if count <= 0 {
+		count = defaultDistractors
+	}
+	count = min(count, maxDistractors)
+
+	var objectIDs []primitive.ObjectID
+	for _, idStr := range req.VocabularyIds {
+		if id, err := primitive.ObjectIDFromHex(idStr); err == nil {
+			objectIDs = append(objectIDs, id)
+		}
+	}
+	targets, err := s.findVocabulary(ctx, bson.M{"_id": bson.M{"$in": objectIDs}})
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return &pb.GetDistractorsResponse{Distractors: map[string]*pb.DistractorList{}}, nil
+	}
+
+	// One query covers every target: anything sharing a word class or lesson with one of them
+	var wordClasses, lessons []string
+	for _, target := range targets {
+		wordClasses = append(wordClasses, target.WordClass)
+		lessons = append(lessons, target.Lesson)
+	}
+	candidates, err := s.findVocabulary(ctx, bson.M{"$or": bson.A{
+		bson.M{"word-class": bson.M{"$in": wordClasses}},
+		bson.M{"lesson": bson.M{"$in": lessons}},
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.GetDistractorsResponse{Distractors: make(map[string]*pb.DistractorList, len(targets))}
+	for _, target := range targets {
+		list := &pb.DistractorList{}
+		for _, vocab := range pickDistractors(target, candidates, count) {
+			list.Items = append(list.Items, s.toProto(vocab))
+		}
+		response.Distractors[target.ID.Hex()] = list
+	}
+	return response, nil
+}
+
+// findVocabulary returns the vocabulary matching filter.
+func (s *Server) findVocabulary(ctx context.Context, filter bson.M) ([]models.Vocabulary, error) {
+	cursor, err := s.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var results []models.Vocabulary
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// pickDistractors ranks candidates by similarity to target and returns the best count of them.
+func pickDistractors(target models.Vocabulary, candidates []models.Vocabulary, count int) []models.Vocabulary {
+	type scored struct {
+		vocab models.Vocabulary
+		score float64
+	}
+
+	targetLength := utf8.RuneCountInString(target.Kana)
+	ranked := make([]scored, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.ID == target.ID || candidate.English == target.English || candidate.Kana == target.Kana {
+			continue
+		}
+
+		score := rand.Float64() // Random tie-breaker below one point
+		if candidate.WordClass == target.WordClass {
+			score += weightWordClass
+		}
+		if candidate.Lesson == target.Lesson {
+			score += weightLesson
+		}
+		switch diff := utf8.RuneCountInString(candidate.Kana) - targetLength; {
+		case diff == 0:
+			score += weightSameLength
+		case diff >= -1 && diff <= 1:
+			score += weightCloseLength
+		}
+		ranked = append(ranked, scored{vocab: candidate, score: score})
+	}
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+
+	// Skip candidates whose meaning is already among the picks so every choice is distinct
+	picked := make([]models.Vocabulary, 0, count)
+	meanings := map[string]bool{target.English: true}
+	for _, candidate := range ranked {
+		if len(picked) == count {
+			break
+		}
+		if meanings[candidate.vocab.English] {
+			continue
+		}
+		meanings[candidate.vocab.English] = true
+		picked = append(picked, candidate.vocab)
+	}
+	return picked
+}
@@ -0,0 +1,46 @@
+// FILE: services/content/internal/grpc/lessons.go
+// Lesson lookups for services that store references to lessons.
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	pb "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maxLessonLookups caps the lesson IDs served per call.
+const maxLessonLookups = 100
+
+// lessonCount is a lesson with the number of vocabulary items in it.
+type lessonCount struct {
+	Lesson string `bson:"_id"`
+	Count  int32  `bson:"count"`
+}
+
+// GetLessonBatch returns the requested lessons that exist, with how many words each has.
+func (s *Server) GetLessonBatch(ctx context.Context, req *pb.GetLessonBatchRequest) (*pb.GetLessonBatchResponse, error) {
+	if len(req.LessonIds) > maxLessonLookups {
+		return nil, invalidArgument("too_many_ids", "lesson_ids", fmt.Sprintf("at most %d lesson IDs per call", maxLessonLookups))
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"lesson": bson.M{"$in": req.LessonIds}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$lesson", "count": bson.M{"$sum": 1}}}},
+	}
+	counts, err := database.AggregateAll[lessonCount](ctx, s.collection, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	lessons := make(map[string]*pb.Lesson, len(counts))
+	for _, count := range counts {
+		lessons[count.Lesson] = &pb.Lesson{Id: count.Lesson, VocabularyCount: count.Count}
+	}
+	return &pb.GetLessonBatchResponse{Lessons: lessons}, nil
+}
@@ -0,0 +1,116 @@
+// FILE: services/content/internal/grpc/search.go
+// Paginated vocabulary search for services that need more than a batch lookup by ID.
+
+package grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	pb_common "wise-owl/gen/proto/common/v1"
+	pb "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/database"
+	"wise-owl/services/content/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// defaultSearchPageSize is used when the request does not set a page size.
+	defaultSearchPageSize = 50
+	// maxSearchPageSize caps the page size a caller may ask for.
+	maxSearchPageSize = 200
+	// maxSearchQuery caps the length of the text query, in characters.
+	maxSearchQuery = 100
+)
+
+// SearchVocabulary returns one page of the vocabulary matching the query and filters, ordered by ID.
+// Pages are keyed by the last ID served, so words added while a caller pages through are not skipped
+// or repeated.
+func (s *Server) SearchVocabulary(ctx context.Context, req *pb.SearchVocabularyRequest) (*pb.SearchVocabularyResponse, error) {
+	query := strings.TrimSpace(req.Query)
+	if utf8.RuneCountInString(query) > maxSearchQuery {
+		return nil, invalidArgument("query_too_long", "query", fmt.Sprintf("query must be at most %d characters", maxSearchQuery))
+	}
+	pageSize := int(req.GetPagination().GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultSearchPageSize
+	}
+	pageSize = min(pageSize, maxSearchPageSize)
+
+	filter := searchFilter(query, req.Filters)
+	if token := req.GetPagination().GetPageToken(); token != "" {
+		after, err := decodePageToken(token)
+		if err != nil {
+			return nil, invalidArgument("invalid_page_token", "pagination.page_token", "invalid page token")
+		}
+		filter["_id"] = bson.M{"$gt": after}
+	}
+
+	// One extra document tells whether another page follows
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(pageSize + 1))
+	results, err := database.FindAll[models.Vocabulary](ctx, s.collection, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.SearchVocabularyResponse{PageInfo: &pb_common.PageInfo{}}
+	if len(results) > pageSize {
+		results = results[:pageSize]
+		response.PageInfo.NextPageToken = encodePageToken(results[pageSize-1].ID)
+	}
+	response.Items = make([]*pb.Vocabulary, 0, len(results))
+	for _, vocab := range results {
+		response.Items = append(response.Items, s.toProto(vocab))
+	}
+	return response, nil
+}
+
+// searchFilter builds the MongoDB filter for a text query and filters. Each empty part matches everything.
+func searchFilter(query string, filters *pb.VocabularyFilters) bson.M {
+	filter := bson.M{}
+	if query != "" {
+		prefix := primitive.Regex{Pattern: "^" + regexp.QuoteMeta(query), Options: "i"}
+		filter["$or"] = bson.A{
+			bson.M{"kana": prefix},
+			bson.M{"kanji": prefix},
+			bson.M{"romaji": prefix},
+			bson.M{"english": primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}},
+		}
+	}
+	if len(filters.GetWordClasses()) > 0 {
+		filter["word-class"] = bson.M{"$in": filters.GetWordClasses()}
+	}
+	if len(filters.GetLessons()) > 0 {
+		filter["lesson"] = bson.M{"$in": filters.GetLessons()}
+	}
+	if len(filters.GetJlptLevels()) > 0 {
+		filter["jlpt_level"] = bson.M{"$in": filters.GetJlptLevels()}
+	}
+	return filter
+}
+
+// encodePageToken makes the opaque token for the page after the given ID.
+func encodePageToken(last primitive.ObjectID) string {
+	return base64.RawURLEncoding.EncodeToString(last[:])
+}
+
+// decodePageToken returns the ID a page token continues after.
+func decodePageToken(token string) (primitive.ObjectID, error) {
+	var id primitive.ObjectID
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return id, err
+	}
+	if len(raw) != len(id) {
+		return id, base64.CorruptInputError(len(raw))
+	}
+	copy(id[:], raw)
+	return id, nil
+}
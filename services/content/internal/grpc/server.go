@@ -4,72 +4,146 @@ package grpc
 
 import (
 	"context"
+	"encoding/base64"
+	"strconv"
 
-	pb "wise-owl/gen/proto/content"
+	pb_common "wise-owl/gen/proto/common/v1"
+	pb "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/database"
+	"wise-owl/lib/storage"
+	"wise-owl/services/content/internal/curriculum"
 	"wise-owl/services/content/internal/models"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Server implements the gRPC ContentServiceServer interface.
 type Server struct {
 	pb.UnimplementedContentServiceServer
-	collection *mongo.Collection
+	collection database.CollectionInterface
+	media      storage.BlobStore // Resolves audio media keys to URLs
+	curriculum *curriculum.Store
 }
 
-// NewServer creates a new gRPC server with its database dependency.
-func NewServer(db *mongo.Database) *Server {
+// NewServer creates a new gRPC server over the vocabulary collection, media storage, and curriculum.
+func NewServer(vocabulary database.CollectionInterface, media storage.BlobStore, store *curriculum.Store) *Server {
 	return &Server{
-		collection: db.Collection("vocabulary"),
+		collection: vocabulary,
+		media:      media,
+		curriculum: store,
 	}
 }
 
-// GetVocabularyBatch fetches vocabulary details for a list of provided IDs.
+const (
+	// maxBatchPageSize caps the IDs looked up per GetVocabularyBatch call, which keeps each response
+	// far below the gRPC message size limit.
+	maxBatchPageSize = 1000
+	// batchChunkSize caps the IDs of one $in query.
+	batchChunkSize = 200
+)
+
+// GetVocabularyBatch fetches vocabulary details for one page of the provided IDs. The page token
+// is the position in vocabulary_ids the next page starts at, so callers must resend the same IDs.
 func (s *Server) GetVocabularyBatch(ctx context.Context, req *pb.GetVocabularyBatchRequest) (*pb.GetVocabularyBatchResponse, error) {
-	// Convert the slice of string IDs from the request into MongoDB ObjectIDs.
+	start := 0
+	if token := req.GetPagination().GetPageToken(); token != "" {
+		offset, err := decodeOffsetToken(token)
+		if err != nil || offset > len(req.VocabularyIds) {
+			return nil, invalidArgument("invalid_page_token", "pagination.page_token", "invalid page token")
+		}
+		start = offset
+	}
+	pageSize := int(req.GetPagination().GetPageSize())
+	if pageSize <= 0 {
+		pageSize = maxBatchPageSize
+	}
+	end := min(start+min(pageSize, maxBatchPageSize), len(req.VocabularyIds))
+
+	// Convert the page's string IDs into MongoDB ObjectIDs, skipping malformed ones.
 	var objectIDs []primitive.ObjectID
-	for _, idStr := range req.VocabularyIds {
+	for _, idStr := range req.VocabularyIds[start:end] {
 		id, err := primitive.ObjectIDFromHex(idStr)
 		if err == nil {
 			objectIDs = append(objectIDs, id)
 		}
 	}
 
-	// Query the database for all documents with an _id in our list.
-	filter := bson.M{"_id": bson.M{"$in": objectIDs}}
-	cursor, err := s.collection.Find(ctx, filter)
-	if err != nil {
-		return nil, err
+	// Query in chunks so no single $in grows with the page size.
+	responseItems := make(map[string]*pb.Vocabulary, len(objectIDs))
+	for len(objectIDs) > 0 {
+		chunk := objectIDs[:min(batchChunkSize, len(objectIDs))]
+		objectIDs = objectIDs[len(chunk):]
+
+		results, err := database.FindAll[models.Vocabulary](ctx, s.collection, bson.M{"_id": bson.M{"$in": chunk}})
+		if err != nil {
+			return nil, err
+		}
+		for _, vocab := range results {
+			pbVocab := s.toProto(vocab)
+			responseItems[pbVocab.Id] = pbVocab
+		}
 	}
 
-	var results []models.Vocabulary
-	if err = cursor.All(ctx, &results); err != nil {
-		return nil, err
+	response := &pb.GetVocabularyBatchResponse{Items: responseItems, PageInfo: &pb_common.PageInfo{}}
+	if end < len(req.VocabularyIds) {
+		response.PageInfo.NextPageToken = encodeOffsetToken(end)
 	}
+	return response, nil
+}
 
-	// Convert the database models to protobuf messages and put them in a map.
-	responseItems := make(map[string]*pb.Vocabulary)
-	for _, vocab := range results {
-		pbVocab := &pb.Vocabulary{
-			Id:        vocab.ID.Hex(),
-			Kana:      vocab.Kana,
-			Romaji:    vocab.Romaji,
-			English:   vocab.English,
-			Burmese:   vocab.Burmese,
-			Lesson:    vocab.Lesson,
-			Type:      vocab.Type,
-			WordClass: vocab.WordClass,
-		}
-		if vocab.Kanji != nil {
-			pbVocab.Kanji = vocab.Kanji
-		}
-		if vocab.Furigana != nil {
-			pbVocab.Furigana = vocab.Furigana
-		}
-		responseItems[pbVocab.Id] = pbVocab
+// toProto converts a vocabulary model to its protobuf message.
+func (s *Server) toProto(vocab models.Vocabulary) *pb.Vocabulary {
+	pbVocab := &pb.Vocabulary{
+		Id:        vocab.ID.Hex(),
+		Kana:      vocab.Kana,
+		Romaji:    vocab.Romaji,
+		English:   vocab.English,
+		Burmese:   vocab.Burmese,
+		Lesson:    vocab.Lesson,
+		Type:      vocab.Type,
+		WordClass: vocab.WordClass,
+		JlptLevel: vocab.JLPTLevel,
 	}
+	if vocab.Kanji != nil {
+		pbVocab.Kanji = vocab.Kanji
+	}
+	if vocab.Furigana != nil {
+		pbVocab.Furigana = vocab.Furigana
+	}
+	if vocab.Audio != nil && *vocab.Audio != "" {
+		audioURL := s.media.URL(*vocab.Audio)
+		pbVocab.AudioUrl = &audioURL
+	}
+	return pbVocab
+}
 
-	return &pb.GetVocabularyBatchResponse{Items: responseItems}, nil
+// invalidArgument returns an InvalidArgument status carrying an ErrorDetail, so callers can tell
+// failures apart by code instead of parsing the message.
+func invalidArgument(code, field, message string) error {
+	st, err := status.New(codes.InvalidArgument, message).WithDetails(&pb_common.ErrorDetail{Code: code, Field: field})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, message)
+	}
+	return st.Err()
+}
+
+// encodeOffsetToken makes the opaque token for a page starting at offset.
+func encodeOffsetToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// decodeOffsetToken returns the offset a page token starts at.
+func decodeOffsetToken(token string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err == nil && offset < 0 {
+		err = strconv.ErrRange
+	}
+	return offset, err
 }
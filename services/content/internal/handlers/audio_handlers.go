@@ -0,0 +1,64 @@
+// FILE: services/content/internal/handlers/audio_handlers.go
+// This file has lessons voiced again with speech synthesis. The admin service calls it for editors.
+
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/jobs"
+	"wise-owl/services/content/internal/audio"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AudioHandler holds dependencies for the audio generation handlers.
+type AudioHandler struct {
+	generator *audio.Generator // nil when no speech synthesis backend is configured
+	scheduler *jobs.Scheduler
+	job       jobs.Job
+}
+
+// NewAudioHandler creates a new handler with its dependencies. job is the scheduled generation
+// job, run straight away once a lesson is marked.
+func NewAudioHandler(generator *audio.Generator, scheduler *jobs.Scheduler, job jobs.Job) *AudioHandler {
+	return &AudioHandler{generator: generator, scheduler: scheduler, job: job}
+}
+
+// RegenerateLessonAudio marks every word of a lesson to be voiced again and starts a generation
+// run in the background, answering 202 with the number of words marked. Words keep their current
+// audio until the new recording replaces it; any the run does not get to are voiced by the next
+// scheduled one.
+func (h *AudioHandler) RegenerateLessonAudio(c *gin.Context) {
+	if h.generator == nil {
+		c.Error(apierror.New(http.StatusServiceUnavailable, "audio_disabled", "Audio generation is not configured."))
+		return
+	}
+	lessonID := c.Param("lessonId")
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	words, err := h.generator.Regenerate(ctx, lessonID)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	if words == 0 {
+		c.Error(apierror.NotFound("lesson_not_found", "Lesson not found."))
+		return
+	}
+
+	// The run outlives the request, so it gets its own context
+	go func() {
+		if err := h.scheduler.RunNow(context.Background(), h.job); err != nil {
+			log.Printf("WARN: Audio generation for lesson %s failed: %v", lessonID, err)
+		}
+	}()
+
+	log.Printf("AUDIT: audio of lesson %s (%d words) queued for regeneration", lessonID, words)
+	c.JSON(http.StatusAccepted, gin.H{"lesson": lessonID, "words": words})
+}
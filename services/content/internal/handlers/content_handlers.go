@@ -4,74 +4,192 @@ package handlers
 
 import (
 	"net/http"
+	"slices"
 	"sort"
+	"strings"
 
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/database"
+	"wise-owl/lib/httpcache"
+	"wise-owl/lib/validation"
+	"wise-owl/services/content/internal/difficulty"
+	"wise-owl/services/content/internal/migrations"
 	"wise-owl/services/content/internal/models"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // ContentHandler holds the database collection handle.
 type ContentHandler struct {
-	vocabulary *mongo.Collection
+	vocabulary database.CollectionInterface
+	scorer     *difficulty.Scorer
+	wotd       wordOfTheDayCache
 }
 
-// NewContentHandler creates a new handler with its dependencies.
-func NewContentHandler(db *mongo.Database) *ContentHandler {
+// NewContentHandler creates a new handler over the vocabulary collection.
+func NewContentHandler(vocabulary database.CollectionInterface, scorer *difficulty.Scorer) *ContentHandler {
 	return &ContentHandler{
-		vocabulary: db.Collection("vocabulary"),
+		vocabulary: vocabulary,
+		scorer:     scorer,
 	}
 }
 
-// GetLessons retrieves a sorted list of all unique lesson identifiers.
+// GetLessons retrieves a sorted list of all unique lesson identifiers along with
+// each scored lesson's difficulty, so clients can order study material.
+// Like GetLessonContent, it answers If-None-Match with 304 when the list has not changed.
 func (h *ContentHandler) GetLessons(c *gin.Context) {
-	// Use the Distinct function to get all unique lesson strings (e.g., "lesson-1", "lesson-2").
-	results, err := h.vocabulary.Distinct(c, "lesson", bson.M{})
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	// Get all unique lesson strings (e.g., "lesson-1", "lesson-2").
+	lessonStrings, err := database.DistinctStrings(ctx, h.vocabulary, "lesson", bson.M{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "database_error"})
+		c.Error(apierror.Internal("database_error", err))
 		return
 	}
 
-	// Convert the []interface{} from MongoDB to a []string for sorting.
-	var lessonStrings []string
-	for _, res := range results {
-		if lessonStr, ok := res.(string); ok {
-			lessonStrings = append(lessonStrings, lessonStr)
+	// Sort by lesson number so "lesson-2" comes before "lesson-10"; unrecognized identifiers go last.
+	sort.SliceStable(lessonStrings, func(i, j int) bool {
+		refI, errI := migrations.ParseLessonRef(lessonStrings[i])
+		refJ, errJ := migrations.ParseLessonRef(lessonStrings[j])
+		switch {
+		case errI == nil && errJ == nil:
+			return refI.Number < refJ.Number
+		case errI == nil || errJ == nil:
+			return errI == nil
+		default:
+			return lessonStrings[i] < lessonStrings[j]
 		}
-	}
+	})
 
-	sort.Strings(lessonStrings) // Sort the lesson strings alphabetically.
+	scores, err := h.scorer.LessonScores(ctx)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"lessons": lessonStrings})
+	httpcache.JSON(c, http.StatusOK, gin.H{"lessons": lessonStrings, "difficulty": scores})
 }
 
 // GetLessonContent retrieves all vocabulary for a specific lesson identifier.
+// An optional ?fields=kana,english query trims each item to the named fields; _id is always included.
+// An optional ?jlpt=N5 query keeps only vocabulary of that JLPT level.
 func (h *ContentHandler) GetLessonContent(c *gin.Context) {
 	// Get the lesson identifier directly from the URL parameter (e.g., "lesson-1").
 	lessonID := c.Param("lessonId")
 
-	opts := options.Find().SetSort(bson.D{{Key: "kana", Value: 1}}) // Sort alphabetically by kana
-	cursor, err := h.vocabulary.Find(c, bson.M{"lesson": lessonID}, opts)
+	var query struct {
+		JLPT string `form:"jlpt" binding:"omitempty,oneof=N5 N4 N3 N2 N1"`
+	}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Match either the legacy lesson string or the structured lesson reference while the migration runs.
+	filter := migrations.LessonRefs.DualReadFilter(lessonID)
+	if query.JLPT != "" {
+		filter["jlpt_level"] = query.JLPT
+	}
+
+	h.respondVocabulary(c, filter, bson.D{{Key: "kana", Value: 1}}) // Sort alphabetically by kana
+}
+
+// GetJLPTVocabulary lists all vocabulary of a JLPT level (e.g. /jlpt/N5), in lesson order.
+// Like GetLessonContent, it accepts ?fields= to trim each item.
+func (h *ContentHandler) GetJLPTVocabulary(c *gin.Context) {
+	level := strings.ToUpper(c.Param("level"))
+	if !slices.Contains(models.JLPTLevels, level) {
+		c.Error(apierror.BadRequest("invalid_jlpt_level", "Unknown JLPT level.").
+			WithDetails(gin.H{"allowed": models.JLPTLevels}))
+		return
+	}
+
+	h.respondVocabulary(c, bson.M{"jlpt_level": level}, bson.D{{Key: "lesson_ref.number", Value: 1}, {Key: "kana", Value: 1}})
+}
+
+// respondVocabulary writes the vocabulary matching filter in the given order, honouring ?fields=.
+func (h *ContentHandler) respondVocabulary(c *gin.Context, filter bson.M, order bson.D) {
+	projection, fieldsErr := fieldProjection(c.Query("fields"))
+	if fieldsErr != nil {
+		c.Error(fieldsErr)
+		return
+	}
+
+	opts := options.Find().SetSort(order)
+	if projection != nil {
+		opts.SetProjection(projection)
+	}
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	cursor, err := h.vocabulary.Find(ctx, filter, opts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "database_error"})
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	if projection != nil {
+		// Vocabulary's JSON and BSON field names match, so the projected documents are returned as-is.
+		trimmed := []bson.M{}
+		if err = cursor.All(ctx, &trimmed); err != nil {
+			c.Error(apierror.Internal("deserialization_error", err))
+			return
+		}
+		httpcache.JSON(c, http.StatusOK, trimmed)
 		return
 	}
 
 	var vocabList []models.Vocabulary
-	if err = cursor.All(c, &vocabList); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "deserialization_error"})
+	if err = cursor.All(ctx, &vocabList); err != nil {
+		c.Error(apierror.Internal("deserialization_error", err))
 		return
 	}
 
 	if len(vocabList) == 0 {
-		// This could mean the lesson identifier is invalid, or the lesson has no vocab.
+		// This could mean the lesson identifier or level is invalid, or nothing matched.
 		// Returning an empty list is a safe and predictable response for the client.
-		c.JSON(http.StatusOK, []models.Vocabulary{})
+		httpcache.JSON(c, http.StatusOK, []models.Vocabulary{})
 		return
 	}
 
-	c.JSON(http.StatusOK, vocabList)
+	httpcache.JSON(c, http.StatusOK, vocabList)
+}
+
+// fieldProjection builds a MongoDB projection from a comma-separated ?fields= value.
+// It returns nil when no fields were requested and an error naming any unknown fields.
+func fieldProjection(fields string) (bson.M, *apierror.Error) {
+	if strings.TrimSpace(fields) == "" {
+		return nil, nil
+	}
+
+	allowed := make(map[string]bool, len(models.VocabularyFields))
+	for _, field := range models.VocabularyFields {
+		allowed[field] = true
+	}
+
+	projection := bson.M{}
+	var unknown []string
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		switch {
+		case field == "":
+			continue
+		case allowed[field]:
+			projection[field] = 1
+		default:
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, apierror.BadRequest("invalid_fields", "Unknown fields requested.").
+			WithDetails(gin.H{"unknown": unknown, "allowed": models.VocabularyFields})
+	}
+	if len(projection) == 0 {
+		return nil, nil
+	}
+	return projection, nil
 }
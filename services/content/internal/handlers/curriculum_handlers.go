@@ -0,0 +1,94 @@
+// FILE: services/content/internal/handlers/curriculum_handlers.go
+// This file serves the curriculum above lessons: courses, their chapters, and the lessons of a chapter.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/httpcache"
+	"wise-owl/services/content/internal/curriculum"
+	"wise-owl/services/content/internal/difficulty"
+	"wise-owl/services/content/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CurriculumHandler holds dependencies for the course and chapter handlers.
+type CurriculumHandler struct {
+	store  *curriculum.Store
+	scorer *difficulty.Scorer
+}
+
+// NewCurriculumHandler creates a new handler with its dependencies.
+func NewCurriculumHandler(store *curriculum.Store, scorer *difficulty.Scorer) *CurriculumHandler {
+	return &CurriculumHandler{store: store, scorer: scorer}
+}
+
+// GetCourses lists every course with its chapters, and the lesson identifiers of each chapter, in study order.
+// It answers If-None-Match with 304 when the curriculum has not changed.
+func (h *CurriculumHandler) GetCourses(c *gin.Context) {
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	courses, err := h.store.Courses(ctx)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	httpcache.JSON(c, http.StatusOK, gin.H{"courses": courses})
+}
+
+// GetChapterLessons returns a chapter with its lessons in study order, each with its word count,
+// difficulty, and prerequisites. The words themselves come from /lessons/:lessonId.
+func (h *CurriculumHandler) GetChapterLessons(c *gin.Context) {
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	chapter, err := h.store.Chapter(ctx, c.Param("chapterId"))
+	if err != nil {
+		if errors.Is(err, curriculum.ErrNotFound) {
+			c.Error(apierror.NotFound("not_found", "Chapter not found."))
+			return
+		}
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	counts, err := h.store.LessonCounts(ctx, chapter.Lessons)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	scores, err := h.scorer.LessonScores(ctx)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	metadata, err := h.store.Lessons(ctx)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	prerequisites := make(map[string][]string, len(metadata))
+	for _, lesson := range metadata {
+		prerequisites[lesson.ID] = lesson.Prerequisites
+	}
+
+	lessons := make([]models.ChapterLesson, len(chapter.Lessons))
+	for i, id := range chapter.Lessons {
+		lessons[i] = models.ChapterLesson{ID: id, VocabularyCount: counts[id], Prerequisites: prerequisites[id]}
+		if lessons[i].Prerequisites == nil {
+			lessons[i].Prerequisites = []string{}
+		}
+		if score, ok := scores[id]; ok {
+			lessons[i].Difficulty = &score
+		}
+	}
+
+	httpcache.JSON(c, http.StatusOK, gin.H{"chapter": chapter, "lessons": lessons})
+}
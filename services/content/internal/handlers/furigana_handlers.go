@@ -0,0 +1,45 @@
+// FILE: services/content/internal/handlers/furigana_handlers.go
+// This file serves furigana for arbitrary Japanese text, such as example sentences.
+
+package handlers
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/validation"
+	"wise-owl/services/content/internal/furigana"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FuriganaHandler holds the annotator, which is loaded in the background at startup.
+type FuriganaHandler struct {
+	annotator *atomic.Pointer[furigana.Annotator]
+}
+
+// NewFuriganaHandler creates a new handler with its dependencies.
+func NewFuriganaHandler(annotator *atomic.Pointer[furigana.Annotator]) *FuriganaHandler {
+	return &FuriganaHandler{annotator: annotator}
+}
+
+// Annotate splits the text into words and returns the reading of each word with kanji,
+// with its kanji and kana parts separated for ruby rendering.
+func (h *FuriganaHandler) Annotate(c *gin.Context) {
+	var req struct {
+		Text string `json:"text" binding:"required,max=1000"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	annotator := h.annotator.Load()
+	if annotator == nil {
+		c.Error(apierror.ServiceUnavailable("furigana_unavailable", "Furigana is still loading. Try again shortly.", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tokens": annotator.Annotate(req.Text)})
+}
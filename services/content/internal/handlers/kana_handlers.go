@@ -0,0 +1,207 @@
+// FILE: services/content/internal/handlers/kana_handlers.go
+// This file serves the kana chart and generates kana recognition drills for beginners.
+
+package handlers
+
+import (
+	"math/rand/v2"
+	"net/http"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/httpcache"
+	"wise-owl/lib/japanese"
+	"wise-owl/lib/romanize"
+	"wise-owl/lib/validation"
+	"wise-owl/services/content/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// kanaChoicesPerQuestion is the number of answers offered for each drill question.
+	kanaChoicesPerQuestion = 4
+	// defaultKanaQuestions is used when the client does not ask for a specific number of questions.
+	defaultKanaQuestions = 10
+)
+
+// Drill directions.
+const (
+	DirectionKanaToRomaji = "kana_to_romaji"
+	DirectionRomajiToKana = "romaji_to_kana"
+)
+
+// KanaHandler holds the kana collection handle.
+type KanaHandler struct {
+	kana *mongo.Collection
+}
+
+// NewKanaHandler creates a new handler with its dependencies.
+func NewKanaHandler(db *mongo.Database) *KanaHandler {
+	return &KanaHandler{
+		kana: db.Collection("kana"),
+	}
+}
+
+// kanaQuery selects part of the chart. Empty fields match everything.
+type kanaQuery struct {
+	Script string `form:"script" binding:"omitempty,oneof=hiragana katakana"`
+	Group  string `form:"group" binding:"omitempty,oneof=basic dakuten combination"`
+	Row    string `form:"row" binding:"omitempty,max=3"`
+}
+
+func (q kanaQuery) filter() bson.M {
+	filter := bson.M{}
+	if q.Script != "" {
+		filter["script"] = q.Script
+	}
+	if q.Group != "" {
+		filter["group"] = q.Group
+	}
+	if q.Row != "" {
+		filter["row"] = q.Row
+	}
+	return filter
+}
+
+// GetKana returns the kana chart in chart order, optionally narrowed by ?script=, ?group=, and ?row=.
+func (h *KanaHandler) GetKana(c *gin.Context) {
+	var query kanaQuery
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	kana, err := h.find(c, query)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	httpcache.JSON(c, http.StatusOK, kana)
+}
+
+// GetKanaQuiz generates multiple-choice recognition questions from the selected part of the chart.
+// By default the prompt is the kana and the choices are romaji; ?direction=romaji_to_kana reverses it.
+func (h *KanaHandler) GetKanaQuiz(c *gin.Context) {
+	var selection kanaQuery
+	if err := validation.BindQuery(c, &selection); err != nil {
+		c.Error(err)
+		return
+	}
+	var query struct {
+		Count     int    `form:"count" binding:"omitempty,min=1,max=50"`
+		Direction string `form:"direction" binding:"omitempty,oneof=kana_to_romaji romaji_to_kana"`
+	}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+	if query.Count == 0 {
+		query.Count = defaultKanaQuestions
+	}
+	if query.Direction == "" {
+		query.Direction = DirectionKanaToRomaji
+	}
+
+	kana, err := h.find(c, selection)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	// The choices are romaji in no "romaji" field, so the romanize middleware cannot respell them.
+	// A caller who hides romaji still gets Hepburn here: the drill is about romaji.
+	if romanize.Style(c) == romanize.Kunrei {
+		for i := range kana {
+			kana[i].Romaji = japanese.HepburnToKunrei(kana[i].Romaji)
+		}
+	}
+
+	questions := buildKanaQuestions(kana, query.Count, query.Direction)
+	if len(questions) == 0 {
+		c.Error(apierror.BadRequest("not_enough_kana", "Select more kana to build a quiz."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"direction": query.Direction, "questions": questions})
+}
+
+// find returns the kana matching the query in chart order.
+func (h *KanaHandler) find(c *gin.Context, query kanaQuery) ([]models.Kana, error) {
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "order", Value: 1}})
+	cursor, err := h.kana.Find(ctx, query.filter(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	kana := []models.Kana{}
+	if err := cursor.All(ctx, &kana); err != nil {
+		return nil, err
+	}
+	return kana, nil
+}
+
+// buildKanaQuestions turns kana into multiple-choice questions with distractors drawn from the same selection.
+// Kana that share a reading (じ and ぢ are both "ji") are only used once so every question has one right answer.
+func buildKanaQuestions(kana []models.Kana, count int, direction string) []models.KanaQuestion {
+	var pool []models.Kana
+	readings := make(map[string]bool)
+	for _, k := range kana {
+		if readings[k.Romaji] {
+			continue
+		}
+		readings[k.Romaji] = true
+		pool = append(pool, k)
+	}
+	if len(pool) < kanaChoicesPerQuestion {
+		return nil
+	}
+
+	rand.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+	if count > len(pool) {
+		count = len(pool)
+	}
+
+	prompt, answer := func(k models.Kana) string { return k.Character }, func(k models.Kana) string { return k.Romaji }
+	if direction == DirectionRomajiToKana {
+		prompt, answer = answer, prompt
+	}
+
+	questions := make([]models.KanaQuestion, 0, count)
+	for i := 0; i < count; i++ {
+		target := pool[i]
+
+		choices := []string{answer(target)}
+		for _, j := range rand.Perm(len(pool)) {
+			if len(choices) == kanaChoicesPerQuestion {
+				break
+			}
+			if j != i {
+				choices = append(choices, answer(pool[j]))
+			}
+		}
+		rand.Shuffle(len(choices), func(a, b int) { choices[a], choices[b] = choices[b], choices[a] })
+
+		correct := 0
+		for k, choice := range choices {
+			if choice == answer(target) {
+				correct = k
+			}
+		}
+
+		questions = append(questions, models.KanaQuestion{
+			KanaID:  target.ID.Hex(),
+			Prompt:  prompt(target),
+			Choices: choices,
+			Answer:  correct,
+			Audio:   target.Audio,
+		})
+	}
+	return questions
+}
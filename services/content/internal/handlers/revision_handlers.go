@@ -0,0 +1,105 @@
+// FILE: services/content/internal/handlers/revision_handlers.go
+// This file publishes corrections to vocabulary as revisions. The admin service calls it when an
+// editor accepts a correction from its review queue.
+
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/validation"
+	"wise-owl/services/content/internal/revisions"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RevisionHandler holds the revision store.
+type RevisionHandler struct {
+	store *revisions.Store
+}
+
+// NewRevisionHandler creates a new handler with its dependencies.
+func NewRevisionHandler(store *revisions.Store) *RevisionHandler {
+	return &RevisionHandler{store: store}
+}
+
+// GetRevisions returns a word as it is now, with its version, and its revisions, latest first.
+func (h *RevisionHandler) GetRevisions(c *gin.Context) {
+	id, ok := vocabularyID(c)
+	if !ok {
+		return
+	}
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	word, err := h.store.Word(ctx, id)
+	if errors.Is(err, revisions.ErrNotFound) {
+		c.Error(apierror.NotFound("not_found", "Vocabulary not found."))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	list, err := h.store.List(ctx, id)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"vocabulary": word, "revisions": list})
+}
+
+// PublishRevision applies a change to a word, provided it is still at the version the change was
+// based on, and returns the revision it became.
+func (h *RevisionHandler) PublishRevision(c *gin.Context) {
+	id, ok := vocabularyID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Version int64             `json:"version" binding:"min=0"`
+		Changes revisions.Changes `json:"changes"`
+		Source  string            `json:"source" binding:"max=100"`
+		Author  string            `json:"author" binding:"max=200"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	revision, err := h.store.Publish(ctx, id, req.Version, req.Changes, req.Source, req.Author)
+	switch {
+	case errors.Is(err, revisions.ErrNoChanges):
+		c.Error(apierror.BadRequest("no_changes", "A revision must change at least one field."))
+		return
+	case errors.Is(err, revisions.ErrNotFound):
+		c.Error(apierror.NotFound("not_found", "Vocabulary not found."))
+		return
+	case errors.Is(err, revisions.ErrConflict):
+		c.Error(apierror.Conflict("version_conflict", "The word has changed since this version."))
+		return
+	case err != nil:
+		c.Error(apierror.Internal("publish_failed", err))
+		return
+	}
+
+	log.Printf("AUDIT: %s published revision %d of vocabulary %s (%s)", req.Author, revision.Version, revision.VocabularyID, req.Source)
+	c.JSON(http.StatusCreated, revision)
+}
+
+// vocabularyID parses the :vocabId path parameter, answering 400 when it is not an ObjectID.
+func vocabularyID(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("vocabId"))
+	if err != nil {
+		c.Error(apierror.BadRequest("invalid_vocabulary_id", "Vocabulary ID must be a 24-character hex ID."))
+		return id, false
+	}
+	return id, true
+}
@@ -0,0 +1,65 @@
+// FILE: services/content/internal/handlers/seed_handlers.go
+// This file lets operators re-run the content seeders without restarting the service.
+
+package handlers
+
+import (
+	"net/http"
+	"sync"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/database"
+	"wise-owl/services/content/internal/seeder"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SeedHandler re-runs the vocabulary, kana, and course seeders on request.
+type SeedHandler struct {
+	db    *mongo.Database
+	cache *database.CachedCollection // Cleared after vocabulary is seeded
+	mu    sync.Mutex                 // One run at a time, so two runs cannot both find a collection empty
+}
+
+// NewSeedHandler creates a new handler with its dependencies.
+func NewSeedHandler(db *mongo.Database, cache *database.CachedCollection) *SeedHandler {
+	return &SeedHandler{
+		db:    db,
+		cache: cache,
+	}
+}
+
+// RunSeeders seeds vocabulary, kana, courses, and lessons into empty collections and ensures the indexes, then reports
+// how many documents were inserted. Collections that already hold data are left alone, so it is safe to repeat.
+func (h *SeedHandler) RunSeeders(c *gin.Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	vocabulary, err := seeder.SeedData(h.db.Collection("vocabulary"))
+	if err != nil {
+		c.Error(apierror.Internal("seed_failed", err))
+		return
+	}
+	kana, err := seeder.SeedKana(h.db.Collection("kana"))
+	if err != nil {
+		c.Error(apierror.Internal("seed_failed", err))
+		return
+	}
+	courses, err := seeder.SeedCourses(h.db.Collection("courses"), h.db.Collection("chapters"))
+	if err != nil {
+		c.Error(apierror.Internal("seed_failed", err))
+		return
+	}
+	lessons, err := seeder.SeedLessons(h.db.Collection("lessons"))
+	if err != nil {
+		c.Error(apierror.Internal("seed_failed", err))
+		return
+	}
+	seeder.EnsureIndexes(h.db.Name(), h.db.Client())
+	if vocabulary > 0 {
+		h.cache.Invalidate()
+	}
+
+	c.JSON(http.StatusOK, gin.H{"vocabulary": vocabulary, "kana": kana, "courses": courses, "lessons": lessons})
+}
@@ -0,0 +1,101 @@
+// FILE: services/content/internal/handlers/word_of_the_day.go
+// This file serves the word of the day, chosen deterministically from the calendar date.
+
+package handlers
+
+import (
+	"context"
+	"hash/fnv"
+	"net/http"
+	"sync"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/httpcache"
+	"wise-owl/lib/validation"
+	"wise-owl/services/content/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxCachedDays bounds the word of the day cache. Clients in different timezones can be on
+// neighbouring dates, so a few days are kept at once.
+const maxCachedDays = 4
+
+// wordOfTheDayCache remembers the word picked for each recent date.
+type wordOfTheDayCache struct {
+	mu    sync.Mutex
+	words map[string]models.Vocabulary
+}
+
+// GetWordOfTheDay returns the same vocabulary item to every caller on a given calendar day.
+// The day is taken in UTC unless ?tz= names an IANA timezone.
+func (h *ContentHandler) GetWordOfTheDay(c *gin.Context) {
+	var query struct {
+		Timezone string `form:"tz" binding:"omitempty,timezone"`
+	}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	loc := time.UTC
+	if query.Timezone != "" {
+		loc, _ = time.LoadLocation(query.Timezone)
+	}
+	date := time.Now().In(loc).Format("2006-01-02")
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	word, err := h.wordOfTheDay(ctx, date)
+	if err == mongo.ErrNoDocuments {
+		c.Error(apierror.NotFound("not_found", "No vocabulary is available."))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	httpcache.JSON(c, http.StatusOK, gin.H{"date": date, "word": word})
+}
+
+// wordOfTheDay picks the word for date by hashing the date into the vocabulary ordered by ID.
+// The pick only changes for a date if vocabulary is added or removed.
+func (h *ContentHandler) wordOfTheDay(ctx context.Context, date string) (models.Vocabulary, error) {
+	h.wotd.mu.Lock()
+	defer h.wotd.mu.Unlock()
+
+	if word, ok := h.wotd.words[date]; ok {
+		return word, nil
+	}
+
+	count, err := h.vocabulary.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return models.Vocabulary{}, err
+	}
+	if count == 0 {
+		return models.Vocabulary{}, mongo.ErrNoDocuments
+	}
+
+	seed := fnv.New64a()
+	seed.Write([]byte("word-of-the-day:" + date))
+	index := int64(seed.Sum64() % uint64(count))
+
+	var word models.Vocabulary
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: 1}}).SetSkip(index)
+	if err := h.vocabulary.FindOne(ctx, bson.M{}, opts).Decode(&word); err != nil {
+		return models.Vocabulary{}, err
+	}
+
+	if h.wotd.words == nil || len(h.wotd.words) >= maxCachedDays {
+		h.wotd.words = make(map[string]models.Vocabulary, maxCachedDays)
+	}
+	h.wotd.words[date] = word
+	return word, nil
+}
@@ -0,0 +1,61 @@
+// FILE: services/content/internal/migrations/jlpt_levels.go
+// Tags vocabulary with a default JLPT level derived from its lesson.
+
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// lastN5Lesson is the final lesson of the first textbook volume, which covers JLPT N5.
+// The second volume (lessons 26-50) covers N4.
+const lastN5Lesson = 25
+
+// DefaultJLPTLevel returns the JLPT level a lesson's vocabulary is taught for,
+// or "" if the lesson identifier is not recognized.
+func DefaultJLPTLevel(lesson string) string {
+	ref, err := ParseLessonRef(lesson)
+	if err != nil {
+		return ""
+	}
+	if ref.Number <= lastN5Lesson {
+		return "N5"
+	}
+	return "N4"
+}
+
+// RunJLPTLevels sets the default level on vocabulary that has none.
+// Levels already present, including ones given in the seed file, are left alone.
+func RunJLPTLevels(ctx context.Context, collection *mongo.Collection) {
+	lessons, err := collection.Distinct(ctx, "lesson", bson.M{"jlpt_level": bson.M{"$exists": false}})
+	if err != nil {
+		log.Printf("WARN: JLPT level backfill failed: %v", err)
+		return
+	}
+
+	byLevel := make(map[string][]interface{})
+	for _, lesson := range lessons {
+		lessonStr, _ := lesson.(string)
+		if level := DefaultJLPTLevel(lessonStr); level != "" {
+			byLevel[level] = append(byLevel[level], lessonStr)
+		}
+	}
+
+	var tagged int64
+	for level, levelLessons := range byLevel {
+		filter := bson.M{"jlpt_level": bson.M{"$exists": false}, "lesson": bson.M{"$in": levelLessons}}
+		result, err := collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"jlpt_level": level}})
+		if err != nil {
+			log.Printf("WARN: JLPT level backfill failed for %s: %v", level, err)
+			return
+		}
+		tagged += result.ModifiedCount
+	}
+	if tagged > 0 {
+		log.Printf("Tagged %d vocabulary items with a default JLPT level", tagged)
+	}
+}
@@ -0,0 +1,70 @@
+// FILE: services/content/internal/migrations/lesson_refs.go
+// Moves vocabulary from raw lesson strings ("lesson-12") to structured lesson references.
+
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"wise-owl/lib/database"
+	"wise-owl/services/content/internal/models"
+)
+
+// preliminaryLesson is the only lesson identifier that doesn't follow the "lesson-N" pattern.
+const preliminaryLesson = "preliminary-lesson"
+
+// LessonRefs dual-writes vocabulary.lesson into the structured vocabulary.lesson_ref field.
+var LessonRefs = database.FieldMigration{
+	Name:        "vocabulary-lesson-refs",
+	LegacyField: "lesson",
+	TargetField: "lesson_ref",
+	Transform: func(legacy interface{}) (interface{}, error) {
+		lesson, ok := legacy.(string)
+		if !ok {
+			return nil, fmt.Errorf("lesson is %T, not a string", legacy)
+		}
+		return ParseLessonRef(lesson)
+	},
+}
+
+// ParseLessonRef converts a lesson identifier such as "lesson-12" into a LessonRef.
+// The preliminary lesson is numbered 0 so it sorts before lesson 1.
+func ParseLessonRef(lesson string) (models.LessonRef, error) {
+	if lesson == preliminaryLesson {
+		return models.LessonRef{Slug: lesson, Number: 0}, nil
+	}
+
+	numberStr, ok := strings.CutPrefix(lesson, "lesson-")
+	if !ok {
+		return models.LessonRef{}, fmt.Errorf("unrecognized lesson identifier %q", lesson)
+	}
+	number, err := strconv.Atoi(numberStr)
+	if err != nil || number <= 0 {
+		return models.LessonRef{}, fmt.Errorf("unrecognized lesson number in %q", lesson)
+	}
+
+	return models.LessonRef{Slug: lesson, Number: number}, nil
+}
+
+// RunLessonRefs backfills lesson references on existing vocabulary and logs a verification report.
+func RunLessonRefs(ctx context.Context, collection database.CollectionInterface) {
+	if _, err := LessonRefs.Backfill(ctx, collection, database.BackfillOptions{BatchSize: 500}); err != nil {
+		log.Printf("WARN: Lesson reference backfill failed: %v", err)
+		return
+	}
+
+	report, err := LessonRefs.Verify(ctx, collection, 10)
+	if err != nil {
+		log.Printf("WARN: Lesson reference verification failed: %v", err)
+		return
+	}
+	if !report.Complete() {
+		log.Printf("WARN: %s (sample mismatches: %v)", report, report.MismatchedIDs)
+		return
+	}
+	log.Println(report)
+}
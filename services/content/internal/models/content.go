@@ -2,18 +2,55 @@
 
 package models
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // Vocabulary represents a single vocabulary item from the seed file.
 type Vocabulary struct {
-	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	Kana      string             `json:"kana" bson:"kana"`
-	Kanji     *string            `json:"kanji" bson:"kanji"`
-	Furigana  *string            `json:"furigana" bson:"furigana"`
-	Romaji    string             `json:"romaji" bson:"romaji"`
-	English   string             `json:"english" bson:"english"`
-	Burmese   string             `json:"burmese" bson:"burmese"`
-	Lesson    string             `json:"lesson" bson:"lesson"`
-	Type      string             `json:"type" bson:"type"`
-	WordClass string             `json:"word-class" bson:"word-class"`
+	ID         primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Kana       string             `json:"kana" bson:"kana"`
+	Kanji      *string            `json:"kanji" bson:"kanji"`
+	Furigana   *string            `json:"furigana" bson:"furigana"`
+	Romaji     string             `json:"romaji" bson:"romaji"`
+	English    string             `json:"english" bson:"english"`
+	Burmese    string             `json:"burmese" bson:"burmese"`
+	Lesson     string             `json:"lesson" bson:"lesson"`
+	LessonRef  *LessonRef         `json:"lesson_ref,omitempty" bson:"lesson_ref,omitempty"`
+	Type       string             `json:"type" bson:"type"`
+	WordClass  string             `json:"word-class" bson:"word-class"`
+	JLPTLevel  string             `json:"jlpt_level,omitempty" bson:"jlpt_level,omitempty"` // "N5" (easiest) to "N1"
+	Audio      *string            `json:"audio,omitempty" bson:"audio,omitempty"`           // Media key of the pronunciation audio
+	AudioStale bool               `json:"-" bson:"audio_stale,omitempty"`                   // Audio is to be generated again (see internal/audio)
+	Difficulty *float64           `json:"difficulty,omitempty" bson:"difficulty,omitempty"` // Quiz miss score, 0 (easy) to 1 (hard)
+	Version    int64              `json:"version,omitempty" bson:"version,omitempty"`       // Published revisions; 0 for the word as seeded
+}
+
+// VocabularyFields lists the JSON field names of Vocabulary that clients may select with ?fields=.
+var VocabularyFields = []string{"_id", "kana", "kanji", "furigana", "romaji", "english", "burmese", "lesson", "lesson_ref", "type", "word-class", "jlpt_level", "audio", "difficulty", "version"}
+
+// JLPTLevels lists the JLPT certification levels from easiest to hardest.
+var JLPTLevels = []string{"N5", "N4", "N3", "N2", "N1"}
+
+// LessonRef is the structured replacement for the raw lesson string on Vocabulary.
+type LessonRef struct {
+	Slug   string `json:"slug" bson:"slug"`     // The legacy identifier, e.g. "lesson-12"
+	Number int    `json:"number" bson:"number"` // 0 for the preliminary lesson
+}
+
+// VocabularyAnswers counts quiz answers for one vocabulary item across all users.
+type VocabularyAnswers struct {
+	VocabularyID string `bson:"_id"`
+	Answers      int64  `bson:"answers"`
+	Incorrect    int64  `bson:"incorrect"`
+}
+
+// LessonDifficulty is the stored difficulty score of a lesson.
+type LessonDifficulty struct {
+	Lesson    string    `json:"lesson" bson:"_id"`
+	Score     float64   `json:"score" bson:"score"`
+	Answers   int64     `json:"answers" bson:"answers"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
@@ -0,0 +1,38 @@
+// FILE: services/content/internal/models/course.go
+
+package models
+
+// Course is the top of the curriculum: a textbook or track whose chapters are studied in order.
+// Chapters are stored in their own collection and attached when a course is served.
+type Course struct {
+	ID          string    `json:"id" bson:"_id"` // Slug, e.g. "elementary-1"
+	Title       string    `json:"title" bson:"title"`
+	Description string    `json:"description,omitempty" bson:"description,omitempty"`
+	Order       int       `json:"order" bson:"order"` // Position in the curriculum
+	Chapters    []Chapter `json:"chapters" bson:"-"`
+}
+
+// Chapter is a unit of consecutive lessons within a course.
+type Chapter struct {
+	ID       string   `json:"id" bson:"_id"` // Slug, e.g. "elementary-1-chapter-1"
+	CourseID string   `json:"course_id" bson:"course_id"`
+	Title    string   `json:"title" bson:"title"`
+	Order    int      `json:"order" bson:"order"`     // Position within the course
+	Lessons  []string `json:"lessons" bson:"lessons"` // Lesson identifiers in study order, e.g. "lesson-1"
+}
+
+// Lesson is the curriculum metadata of a lesson. Its prerequisites form a graph over lessons: a
+// learner unlocks a lesson once they have completed every lesson it lists.
+type Lesson struct {
+	ID            string   `json:"id" bson:"_id"`      // Lesson identifier, e.g. "lesson-1"
+	Order         int      `json:"order" bson:"order"` // Position in the whole curriculum
+	Prerequisites []string `json:"prerequisites" bson:"prerequisites"`
+}
+
+// ChapterLesson is a lesson of a chapter with the number of words in it.
+type ChapterLesson struct {
+	ID              string   `json:"id"`
+	VocabularyCount int      `json:"vocabulary_count"`
+	Difficulty      *float64 `json:"difficulty,omitempty"` // Absent until the lesson has been scored
+	Prerequisites   []string `json:"prerequisites"`        // Lessons to complete before this one unlocks
+}
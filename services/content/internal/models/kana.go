@@ -0,0 +1,28 @@
+// FILE: services/content/internal/models/kana.go
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Kana is one hiragana or katakana character (or combination such as きゃ) from the kana chart.
+// StrokeOrderImage and Audio are media keys relative to the media base URL.
+type Kana struct {
+	ID               primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Character        string             `json:"character" bson:"character"`
+	Romaji           string             `json:"romaji" bson:"romaji"`
+	Script           string             `json:"script" bson:"script"` // "hiragana" or "katakana"
+	Group            string             `json:"group" bson:"group"`   // "basic", "dakuten", or "combination"
+	Row              string             `json:"row" bson:"row"`       // The chart row, e.g. "ka"
+	Order            int                `json:"order" bson:"order"`   // Position in the chart
+	StrokeOrderImage string             `json:"stroke_order_image" bson:"stroke_order_image"`
+	Audio            string             `json:"audio" bson:"audio"`
+}
+
+// KanaQuestion is a recognition drill question. Answer is the index of the correct choice.
+type KanaQuestion struct {
+	KanaID  string   `json:"kana_id"`
+	Prompt  string   `json:"prompt"`
+	Choices []string `json:"choices"`
+	Answer  int      `json:"answer"`
+	Audio   string   `json:"audio"`
+}
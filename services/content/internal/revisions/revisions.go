@@ -0,0 +1,195 @@
+// FILE: services/content/internal/revisions/revisions.go
+// This package publishes editorial corrections to vocabulary. Each published change is a numbered
+// revision of the word: the word's version counts them, and the revisions collection keeps what
+// every one changed and replaced, so a bad fix can be traced and put back by hand.
+
+package revisions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"wise-owl/lib/database"
+	"wise-owl/services/content/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection holds the revisions, in the content database
+const Collection = "vocabulary_revisions"
+
+var (
+	// ErrNotFound is returned for words that do not exist
+	ErrNotFound = errors.New("vocabulary not found")
+	// ErrConflict is returned when the word is no longer at the version a change was based on
+	ErrConflict = errors.New("vocabulary changed since this version")
+	// ErrNoChanges is returned for changes that set no field
+	ErrNoChanges = errors.New("no fields changed")
+)
+
+// Index keeps one revision per word and version, which also stops two changes based on the same
+// version from both being published.
+var Index = database.Index{
+	Collection: Collection,
+	Keys:       bson.D{{Key: "vocabulary_id", Value: 1}, {Key: "version", Value: -1}},
+	Unique:     true,
+}
+
+// Changes are the editorial fields of a word a revision sets; nil fields are left as they are.
+type Changes struct {
+	Kana     *string `json:"kana,omitempty" bson:"kana,omitempty"`
+	Kanji    *string `json:"kanji,omitempty" bson:"kanji,omitempty"`
+	Furigana *string `json:"furigana,omitempty" bson:"furigana,omitempty"`
+	Romaji   *string `json:"romaji,omitempty" bson:"romaji,omitempty"`
+	English  *string `json:"english,omitempty" bson:"english,omitempty"`
+	Burmese  *string `json:"burmese,omitempty" bson:"burmese,omitempty"`
+}
+
+// set returns the $set document for the changed fields.
+func (c Changes) set() bson.M {
+	set := bson.M{}
+	for field, value := range map[string]*string{
+		"kana": c.Kana, "kanji": c.Kanji, "furigana": c.Furigana,
+		"romaji": c.Romaji, "english": c.English, "burmese": c.Burmese,
+	} {
+		if value != nil {
+			set[field] = *value
+		}
+	}
+	return set
+}
+
+// replaced returns word's values of the fields c changes.
+func (c Changes) replaced(word models.Vocabulary) Changes {
+	var previous Changes
+	if c.Kana != nil {
+		previous.Kana = &word.Kana
+	}
+	if c.Kanji != nil {
+		previous.Kanji = orEmpty(word.Kanji)
+	}
+	if c.Furigana != nil {
+		previous.Furigana = orEmpty(word.Furigana)
+	}
+	if c.Romaji != nil {
+		previous.Romaji = &word.Romaji
+	}
+	if c.English != nil {
+		previous.English = &word.English
+	}
+	if c.Burmese != nil {
+		previous.Burmese = &word.Burmese
+	}
+	return previous
+}
+
+func orEmpty(s *string) *string {
+	if s == nil {
+		empty := ""
+		return &empty
+	}
+	return s
+}
+
+// Revision is one published change to a word.
+type Revision struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	VocabularyID string             `json:"vocabulary_id" bson:"vocabulary_id"`
+	Version      int64              `json:"version" bson:"version"` // The word's version once the change was published
+	Changes      Changes            `json:"changes" bson:"changes"`
+	Previous     Changes            `json:"previous" bson:"previous"`                 // The values the change replaced
+	Source       string             `json:"source,omitempty" bson:"source,omitempty"` // What the change came from, e.g. "review:<id>"
+	Author       string             `json:"author,omitempty" bson:"author,omitempty"` // Who published it
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// Store publishes and lists revisions.
+type Store struct {
+	vocabulary *mongo.Collection          // Read directly: a revision must be based on the stored word
+	cache      *database.CachedCollection // Cleared after a revision is published
+	revisions  *mongo.Collection
+}
+
+// NewStore creates a store over the content database and the cached vocabulary reads.
+func NewStore(db *mongo.Database, cache *database.CachedCollection) *Store {
+	return &Store{
+		vocabulary: db.Collection("vocabulary"),
+		cache:      cache,
+		revisions:  db.Collection(Collection),
+	}
+}
+
+// Word returns the word with vocabularyID as it is now.
+func (s *Store) Word(ctx context.Context, vocabularyID primitive.ObjectID) (models.Vocabulary, error) {
+	var word models.Vocabulary
+	err := s.vocabulary.FindOne(ctx, bson.M{"_id": vocabularyID}).Decode(&word)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return word, ErrNotFound
+	}
+	return word, err
+}
+
+// List returns a word's revisions, latest first.
+func (s *Store) List(ctx context.Context, vocabularyID primitive.ObjectID) ([]Revision, error) {
+	return database.FindAll[Revision](ctx, s.revisions, bson.M{"vocabulary_id": vocabularyID.Hex()},
+		options.Find().SetSort(bson.D{{Key: "version", Value: -1}}))
+}
+
+// Publish applies changes to the word if it is still at version, and returns the new revision.
+//
+// The revision is recorded first, under the version it creates: the unique index lets only one
+// change based on a version through. The word is then updated only if it is still at version,
+// and the revision is withdrawn if it is not.
+func (s *Store) Publish(ctx context.Context, vocabularyID primitive.ObjectID, version int64, changes Changes, source, author string) (Revision, error) {
+	set := changes.set()
+	if len(set) == 0 {
+		return Revision{}, ErrNoChanges
+	}
+
+	var word models.Vocabulary
+	if err := s.vocabulary.FindOne(ctx, bson.M{"_id": vocabularyID}).Decode(&word); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return Revision{}, ErrNotFound
+		}
+		return Revision{}, err
+	}
+	if word.Version != version {
+		return Revision{}, ErrConflict
+	}
+
+	revision := Revision{
+		ID:           primitive.NewObjectID(),
+		VocabularyID: vocabularyID.Hex(),
+		Version:      version + 1,
+		Changes:      changes,
+		Previous:     changes.replaced(word),
+		Source:       source,
+		Author:       author,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if err := database.InsertUnique(ctx, s.revisions, revision); err != nil {
+		if errors.Is(err, database.ErrDuplicate) {
+			return Revision{}, ErrConflict
+		}
+		return Revision{}, err
+	}
+
+	filter := database.MatchVersion(bson.M{"_id": vocabularyID}, version)
+	result, err := s.vocabulary.UpdateOne(ctx, filter, database.BumpVersion(bson.M{"$set": set}))
+	if err == nil && result.MatchedCount == 0 {
+		err = ErrConflict
+	}
+	if err != nil {
+		if _, deleteErr := s.revisions.DeleteOne(ctx, bson.M{"_id": revision.ID}); deleteErr != nil {
+			return Revision{}, fmt.Errorf("%w (withdrawing revision %s: %v)", err, revision.ID.Hex(), deleteErr)
+		}
+		return Revision{}, err
+	}
+	s.cache.Invalidate()
+	return revision, nil
+}
@@ -0,0 +1,39 @@
+// FILE: services/content/internal/seeder/indexes.go
+
+package seeder
+
+import (
+	"context"
+	"log"
+
+	"wise-owl/lib/database"
+	"wise-owl/services/content/internal/revisions"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Indexes lists every index the content queries rely on.
+// vocabulary_answers and lesson_difficulty are keyed by _id and need none.
+var Indexes = []database.Index{
+	// Lesson pages and distractor candidates filter by lesson
+	{Collection: "vocabulary", Keys: bson.D{{Key: "lesson", Value: 1}, {Key: "kana", Value: 1}}},
+	// JLPT lists are sorted by lesson number, then kana
+	{Collection: "vocabulary", Keys: bson.D{{Key: "jlpt_level", Value: 1}, {Key: "lesson_ref.number", Value: 1}, {Key: "kana", Value: 1}}},
+	{Collection: "vocabulary", Keys: bson.D{{Key: "word-class", Value: 1}}},
+	{Collection: "kana", Keys: bson.D{{Key: "order", Value: 1}}},
+	{Collection: "courses", Keys: bson.D{{Key: "order", Value: 1}}},
+	// Courses list their chapters in order
+	{Collection: "chapters", Keys: bson.D{{Key: "course_id", Value: 1}, {Key: "order", Value: 1}}},
+	// Lesson prerequisites are read in curriculum order
+	{Collection: "lessons", Keys: bson.D{{Key: "order", Value: 1}}},
+	// A word's revisions, latest first
+	revisions.Index,
+}
+
+// EnsureIndexes creates or rebuilds the declared indexes and logs any drift.
+func EnsureIndexes(dbName string, client *mongo.Client) {
+	if err := database.EnsureIndexes(context.Background(), client.Database(dbName), Indexes); err != nil {
+		log.Printf("WARN: Failed to ensure content indexes: %v", err)
+	}
+}
@@ -5,30 +5,40 @@ package seeder
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
 
+	"wise-owl/lib/database"
+	"wise-owl/services/content/internal/migrations"
 	"wise-owl/services/content/internal/models"
 
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 const seedFilePathInContainer = "/app/seed/vocabulary.json"
 const seedFilePathForLocal = "services/content/seed/vocabulary.json"
 
-// SeedData checks if the vocabulary collection is empty and populates it from the JSON file.
-func SeedData(dbName string, client *mongo.Client) {
-	collection := client.Database(dbName).Collection("vocabulary")
+const kanaSeedFilePathInContainer = "/app/seed/kana.json"
+const kanaSeedFilePathForLocal = "services/content/seed/kana.json"
+
+const coursesSeedFilePathInContainer = "/app/seed/courses.json"
+const coursesSeedFilePathForLocal = "services/content/seed/courses.json"
+
+const lessonsSeedFilePathInContainer = "/app/seed/lessons.json"
+const lessonsSeedFilePathForLocal = "services/content/seed/lessons.json"
 
+// SeedData checks if the vocabulary collection is empty and populates it from the JSON file.
+// It returns the number of documents inserted, which is zero when data already exists.
+func SeedData(collection database.CollectionInterface) (int, error) {
 	count, err := collection.CountDocuments(context.Background(), bson.M{})
 	if err != nil {
-		log.Fatalf("FATAL: Failed to count documents in vocabulary collection: %v", err)
+		return 0, fmt.Errorf("count vocabulary: %v", err)
 	}
 
 	if count > 0 {
 		log.Println("Vocabulary data already exists. Skipping seed.")
-		return
+		return 0, nil
 	}
 
 	log.Println("No vocabulary data found. Seeding database from vocabulary.json...")
@@ -38,26 +48,193 @@ func SeedData(dbName string, client *mongo.Client) {
 		jsonFile, err = os.ReadFile(seedFilePathForLocal)
 		if err != nil {
 			log.Printf("WARN: Could not read seed file. Skipping seed. Error: %v", err)
-			return
+			return 0, nil
 		}
 	}
 
 	var vocabList []models.Vocabulary
 	if err := json.Unmarshal(jsonFile, &vocabList); err != nil {
-		log.Fatalf("FATAL: Failed to unmarshal seed JSON: %v", err)
+		return 0, fmt.Errorf("unmarshal seed JSON: %v", err)
 	}
 
 	if len(vocabList) > 0 {
 		documents := make([]interface{}, len(vocabList))
 		for i, vocab := range vocabList {
+			// Dual-write the structured lesson reference alongside the legacy lesson string.
+			if ref, err := migrations.ParseLessonRef(vocab.Lesson); err == nil {
+				vocab.LessonRef = &ref
+			}
+			if vocab.JLPTLevel == "" {
+				vocab.JLPTLevel = migrations.DefaultJLPTLevel(vocab.Lesson)
+			}
 			documents[i] = vocab
 		}
 
 		_, err = collection.InsertMany(context.Background(), documents)
 		if err != nil {
-			log.Fatalf("FATAL: Failed to seed vocabulary: %v", err)
+			return 0, fmt.Errorf("seed vocabulary: %v", err)
 		}
 	}
 
 	log.Println("Successfully seeded database with vocabulary content.")
+	return len(vocabList), nil
+}
+
+// SeedKana checks if the kana collection is empty and populates it from the kana chart JSON file.
+// It returns the number of documents inserted, which is zero when data already exists.
+func SeedKana(collection database.CollectionInterface) (int, error) {
+	count, err := collection.CountDocuments(context.Background(), bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("count kana: %v", err)
+	}
+
+	if count > 0 {
+		log.Println("Kana data already exists. Skipping seed.")
+		return 0, nil
+	}
+
+	log.Println("No kana data found. Seeding database from kana.json...")
+
+	jsonFile, err := os.ReadFile(kanaSeedFilePathInContainer)
+	if err != nil {
+		jsonFile, err = os.ReadFile(kanaSeedFilePathForLocal)
+		if err != nil {
+			log.Printf("WARN: Could not read kana seed file. Skipping seed. Error: %v", err)
+			return 0, nil
+		}
+	}
+
+	var kanaList []models.Kana
+	if err := json.Unmarshal(jsonFile, &kanaList); err != nil {
+		return 0, fmt.Errorf("unmarshal kana seed JSON: %v", err)
+	}
+
+	if len(kanaList) > 0 {
+		documents := make([]interface{}, len(kanaList))
+		for i, kana := range kanaList {
+			documents[i] = kana
+		}
+
+		_, err = collection.InsertMany(context.Background(), documents)
+		if err != nil {
+			return 0, fmt.Errorf("seed kana: %v", err)
+		}
+	}
+
+	log.Println("Successfully seeded database with kana chart.")
+	return len(kanaList), nil
+}
+
+// SeedCourses checks if the courses collection is empty and populates the courses and their chapters
+// from the curriculum JSON file, where each course nests its chapters in order.
+// It returns the number of courses inserted, which is zero when data already exists.
+func SeedCourses(courses, chapters database.CollectionInterface) (int, error) {
+	count, err := courses.CountDocuments(context.Background(), bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("count courses: %v", err)
+	}
+
+	if count > 0 {
+		log.Println("Course data already exists. Skipping seed.")
+		return 0, nil
+	}
+
+	log.Println("No course data found. Seeding database from courses.json...")
+
+	jsonFile, err := os.ReadFile(coursesSeedFilePathInContainer)
+	if err != nil {
+		jsonFile, err = os.ReadFile(coursesSeedFilePathForLocal)
+		if err != nil {
+			log.Printf("WARN: Could not read course seed file. Skipping seed. Error: %v", err)
+			return 0, nil
+		}
+	}
+
+	var courseList []models.Course
+	if err := json.Unmarshal(jsonFile, &courseList); err != nil {
+		return 0, fmt.Errorf("unmarshal course seed JSON: %v", err)
+	}
+
+	if len(courseList) > 0 {
+		var chapterDocs []interface{}
+		courseDocs := make([]interface{}, len(courseList))
+		courseIDs := make([]string, len(courseList))
+		for i, course := range courseList {
+			course.Order = i + 1
+			for j, chapter := range course.Chapters {
+				chapter.CourseID = course.ID
+				chapter.Order = j + 1
+				chapterDocs = append(chapterDocs, chapter)
+			}
+			courseDocs[i] = course
+			courseIDs[i] = course.ID
+		}
+
+		// Chapters go in first, since the courses collection decides whether to seed; clear any left
+		// by a run that failed before inserting the courses
+		if _, err := chapters.DeleteMany(context.Background(), bson.M{"course_id": bson.M{"$in": courseIDs}}); err != nil {
+			return 0, fmt.Errorf("clear chapters: %v", err)
+		}
+		if len(chapterDocs) > 0 {
+			if _, err := chapters.InsertMany(context.Background(), chapterDocs); err != nil {
+				return 0, fmt.Errorf("seed chapters: %v", err)
+			}
+		}
+		if _, err := courses.InsertMany(context.Background(), courseDocs); err != nil {
+			return 0, fmt.Errorf("seed courses: %v", err)
+		}
+	}
+
+	log.Println("Successfully seeded database with courses and chapters.")
+	return len(courseList), nil
+}
+
+// SeedLessons checks if the lessons collection is empty and populates the lesson prerequisites from
+// the lessons JSON file, which lists the lessons in curriculum order.
+// It returns the number of documents inserted, which is zero when data already exists.
+func SeedLessons(collection database.CollectionInterface) (int, error) {
+	count, err := collection.CountDocuments(context.Background(), bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("count lessons: %v", err)
+	}
+
+	if count > 0 {
+		log.Println("Lesson data already exists. Skipping seed.")
+		return 0, nil
+	}
+
+	log.Println("No lesson data found. Seeding database from lessons.json...")
+
+	jsonFile, err := os.ReadFile(lessonsSeedFilePathInContainer)
+	if err != nil {
+		jsonFile, err = os.ReadFile(lessonsSeedFilePathForLocal)
+		if err != nil {
+			log.Printf("WARN: Could not read lesson seed file. Skipping seed. Error: %v", err)
+			return 0, nil
+		}
+	}
+
+	var lessonList []models.Lesson
+	if err := json.Unmarshal(jsonFile, &lessonList); err != nil {
+		return 0, fmt.Errorf("unmarshal lesson seed JSON: %v", err)
+	}
+
+	if len(lessonList) > 0 {
+		documents := make([]interface{}, len(lessonList))
+		for i, lesson := range lessonList {
+			lesson.Order = i + 1
+			if lesson.Prerequisites == nil {
+				lesson.Prerequisites = []string{}
+			}
+			documents[i] = lesson
+		}
+
+		_, err = collection.InsertMany(context.Background(), documents)
+		if err != nil {
+			return 0, fmt.Errorf("seed lessons: %v", err)
+		}
+	}
+
+	log.Println("Successfully seeded database with lesson prerequisites.")
+	return len(lessonList), nil
 }
@@ -0,0 +1,219 @@
+// FILE: services/leaderboard/cmd/main.go
+// Entry point for the Wise Owl Leaderboard Service.
+
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	pb_users "wise-owl/gen/proto/users/v1"
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/auth"
+	"wise-owl/lib/backup"
+	"wise-owl/lib/compress"
+	"wise-owl/lib/config"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/database"
+	"wise-owl/lib/events"
+	"wise-owl/lib/grpcdebug"
+	"wise-owl/lib/grpcutil"
+	"wise-owl/lib/health"
+	"wise-owl/lib/jobs"
+	"wise-owl/lib/metrics"
+	"wise-owl/lib/preset"
+	"wise-owl/lib/resilience"
+	"wise-owl/lib/usage"
+	"wise-owl/lib/validation"
+	"wise-owl/services/leaderboard/internal/handlers"
+	"wise-owl/services/leaderboard/internal/seeder"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func main() {
+	// 1. Load Configuration (supports both local and AWS environments)
+	cfg, err := config.LoadConfig("leaderboard")
+	if err != nil {
+		log.Fatalf("FATAL: could not load config: %v", err)
+	}
+	stopMetrics := metrics.Start(cfg.Metrics, "leaderboard") // CloudWatch EMF metrics, when EMF_METRICS_ENABLED is set
+	defer stopMetrics()
+
+	dbName := cfg.DB_NAME
+	if dbName == "" {
+		dbName = "leaderboard_db"
+	}
+	log.Printf("Configuration loaded. Using database: %s (Type: %s)", dbName, cfg.DB_TYPE)
+
+	// 2. Connect to Database (supports MongoDB and DocumentDB)
+	db, err := database.CreateDatabase(cfg)
+	if err != nil {
+		log.Fatalf("FATAL: Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+	mongoClient := db.GetClient().(*mongo.Client)
+	mongoDatabase := mongoClient.Database(dbName)
+	log.Println("Database connection established.")
+
+	// 3. Create indexes
+	seeder.SeedDatabase(mongoDatabase)
+
+	// Scheduled exports of XP and friendships; `leaderboard restore-backup` restores one and exits
+	// (see lib/backup)
+	backups, err := backup.New(context.Background(), cfg.Backup, "leaderboard", mongoDatabase, []string{"xp_awards", "friendships"})
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
+	if backup.Requested() {
+		os.Exit(backups.RunCommand(context.Background(), os.Args[2:]))
+	}
+	if cfg.Backup.Enabled {
+		scheduler := jobs.NewScheduler(jobs.NewMongoLocker(mongoDatabase.Collection(jobs.LeaseCollection)))
+		if err := scheduler.Add(backups.Job()); err != nil {
+			log.Fatalf("FATAL: Failed to schedule backups: %v", err)
+		}
+		schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+		defer stopScheduler()
+		scheduler.Start(schedulerCtx)
+	}
+
+	// 4. Initialize health checker
+	healthChecker := health.New("Leaderboard Service", health.Options{
+		Database: mongoDatabase,
+		AWS:      config.IsAWSEnvironment(), // Deep checks and environment details for ECS
+	})
+
+	// 5. Initialize HTTP Router and Middleware
+	// Route objectives, reported at /health/slo and /metrics
+	slos := metrics.NewSLOs("leaderboard",
+		metrics.Objective{Method: http.MethodGet, Route: "/api/v1/leaderboard", Availability: 0.995, Latency: 500 * time.Millisecond, LatencyTarget: 0.99},
+	)
+	// Gin mode, request logs, and panic recovery suited to the environment (see lib/preset)
+	router := preset.For(cfg.Environment).Router()
+	router.ContextWithFallback = true // Handlers pass c to Mongo; let it carry the request's deadline
+	router.Use(slos.Middleware())     // Outside apierror.Middleware, so it records the status sent
+	router.Use(apierror.Middleware()) // Trace IDs and the standard error envelope
+	router.Use(metrics.Middleware())  // Request latency for CloudWatch
+	router.Use(compress.Gzip())       // Compress larger text and JSON responses
+	// Overall request deadline, answered with a 504 when it passes
+	router.Use(ctxutil.Timeout(cfg.RequestTimeout, nil))
+	router.Use(validation.SizeLimits(validation.DefaultLimits, nil)) // Reject oversized bodies and arrays before they are decoded
+
+	// Initialize auth middleware (skip if Auth0 not configured)
+	var authMiddleware gin.HandlerFunc
+	if cfg.Auth0Domain != "" && cfg.Auth0Audience != "" {
+		authMiddleware, err = auth.NewTokenMiddleware(auth.ConfigOptions(cfg))
+		if err != nil {
+			log.Fatalf("FATAL: could not set up token validation: %v", err)
+		}
+		log.Println("Auth0 authentication enabled")
+	} else {
+		// No-op middleware for development
+		authMiddleware = func(c *gin.Context) {
+			c.Next()
+		}
+		log.Println("Authentication disabled for development")
+	}
+
+	leaderboardHandler := handlers.NewLeaderboardHandler(mongoDatabase)
+
+	// Users' tiers come from the users service; without it quotas go unenforced
+	usersServiceURL := getUsersServiceURL()
+	usersBreaker := resilience.NewCircuitBreaker("users-grpc", resilience.Options{
+		IsFailure: resilience.IsGRPCFailure,
+	})
+	dialOpts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, resilience.DialOptions(usersBreaker)...)
+	dialOpts = append(dialOpts, grpcdebug.DialOptions(cfg.GRPCDebugLog)...)
+	dialOpts = append(dialOpts, grpcutil.ClientOptions()...)
+	usersConn, err := grpc.Dial(grpcutil.ClientTarget(usersServiceURL), dialOpts...)
+	if err != nil {
+		log.Fatalf("Did not connect to users-service: %v", err)
+	}
+	defer usersConn.Close()
+	if err := healthChecker.AddDependency(health.DependencyConfig{
+		Name:      "users-grpc",
+		CheckType: health.CheckGRPC,
+		Target:    usersServiceURL,
+		Conn:      usersConn,
+		Breaker:   usersBreaker,
+	}); err != nil {
+		log.Printf("WARN: Failed to register users-service health dependency: %v", err)
+	}
+
+	// API calls are counted into the users database and held to the daily limit of the user's tier
+	meter := usage.NewMeter(usage.NewStore(mongoClient.Database(getUsersDBName())), "leaderboard", usage.NewGRPCTiers(pb_users.NewUsersServiceClient(usersConn)), usage.LimitsFromEnv())
+
+	// 6. Register health check routes; results are refreshed in the background
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	defer stopHealth()
+	healthChecker.Start(healthCtx)
+	healthChecker.RegisterRoutes(router)
+	slos.RegisterRoutes(router)
+	// Prometheus scrape endpoint for SLOs; the gateway does not route it
+	router.GET("/metrics", metrics.Handler(slos))
+
+	// 7. Define API Routes
+	apiV1 := router.Group("/api/v1")
+	{
+		leaderboardRoutes := apiV1.Group("/leaderboard")
+		leaderboardRoutes.Use(authMiddleware, meter.Middleware())
+		{
+			leaderboardRoutes.GET("", leaderboardHandler.GetLeaderboard)
+			leaderboardRoutes.GET("/friends", leaderboardHandler.ListFriends)
+			leaderboardRoutes.POST("/friends/:friendId", leaderboardHandler.AddFriend)
+			leaderboardRoutes.DELETE("/friends/:friendId", leaderboardHandler.RemoveFriend)
+		}
+	}
+
+	// Internal event ingestion (not routed through the gateway)
+	internal := router.Group("/internal/v1")
+	{
+		internal.POST("/events", events.Handler(leaderboardHandler.HandleEvent))
+	}
+
+	// 8. Start HTTP Server with Graceful Shutdown
+	srv := &http.Server{Addr: ":" + cfg.ServerPort, Handler: router}
+	go func() {
+		log.Printf("Leaderboard HTTP server listening on port %s", cfg.ServerPort)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("FATAL: listen: %s\n", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down Leaderboard Service...")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv.Shutdown(ctx)
+}
+
+// getUsersServiceURL returns the address of the users service's gRPC API
+func getUsersServiceURL() string {
+	if url := os.Getenv("USERS_SERVICE_URL"); url != "" {
+		return url
+	}
+	if config.IsAWSEnvironment() {
+		// Default for ECS service discovery
+		return "users-service.wise-owl-cluster.local:50051"
+	}
+	return "users-service:50051"
+}
+
+// getUsersDBName returns the name of the users service's database
+func getUsersDBName() string {
+	if name := os.Getenv("USERS_DB_NAME"); name != "" {
+		return name
+	}
+	return "users_db"
+}
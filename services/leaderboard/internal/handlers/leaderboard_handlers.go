@@ -0,0 +1,339 @@
+// FILE: services/leaderboard/internal/handlers/leaderboard_handlers.go
+// This package awards XP from domain events and serves the leaderboard endpoints.
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/database"
+	"wise-owl/lib/events"
+	"wise-owl/services/leaderboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// XP awarded per event type.
+const (
+	xpCorrectAnswer   = 10
+	xpIncorrectAnswer = 2
+	xpSRSReview       = 5
+)
+
+// Supported leaderboard periods.
+const (
+	PeriodDaily   = "daily"
+	PeriodWeekly  = "weekly"
+	PeriodAllTime = "all-time"
+)
+
+// AnswerClaimsCollection records which vocabulary items each user has earned answer and review XP for today
+const AnswerClaimsCollection = "answer_xp_claims"
+
+// LeaderboardHandler holds the XP and friendship collections.
+type LeaderboardHandler struct {
+	awards       *mongo.Collection
+	answerClaims *mongo.Collection
+	friendships  *mongo.Collection
+}
+
+// NewLeaderboardHandler creates a new handler with its dependencies.
+func NewLeaderboardHandler(db *mongo.Database) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		awards:       db.Collection("xp_awards"),
+		answerClaims: db.Collection(AnswerClaimsCollection),
+		friendships:  db.Collection("friendships"),
+	}
+}
+
+// HandleEvent awards XP for a domain event. Redelivered events are ignored, and answers and reviews
+// each earn XP once per user, vocabulary item, and day, so repeating one word does not climb the board.
+func (h *LeaderboardHandler) HandleEvent(ctx context.Context, event events.Event) error {
+	points := xpForEvent(event)
+	if points == 0 || event.UserID == "" {
+		return nil
+	}
+
+	claim := xpClaim(event)
+	if claim != "" {
+		err := database.InsertUnique(ctx, h.answerClaims, bson.M{"_id": claim, "event_id": event.ID, "claimed_at": time.Now().UTC()})
+		if errors.Is(err, database.ErrDuplicate) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	award := models.XPAward{
+		ID:         primitive.NewObjectID(),
+		EventID:    event.ID,
+		EventType:  event.Type,
+		UserID:     event.UserID,
+		Points:     points,
+		OccurredAt: event.OccurredAt,
+	}
+	if err := database.InsertUnique(ctx, h.awards, award); err != nil {
+		if errors.Is(err, database.ErrDuplicate) {
+			return nil
+		}
+		if claim != "" {
+			// Release the claim so the redelivered event can award it
+			if _, delErr := h.answerClaims.DeleteOne(ctx, bson.M{"_id": claim}); delErr != nil {
+				return fmt.Errorf("%v (and releasing the claim: %v)", err, delErr)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// GetLeaderboard returns the top users for a period, optionally limited to the caller and their friends.
+func (h *LeaderboardHandler) GetLeaderboard(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDStr, _ := userID.(string)
+
+	period := c.DefaultQuery("period", PeriodWeekly)
+	since, ok := periodStart(period, time.Now().UTC())
+	if !ok {
+		c.Error(apierror.BadRequest("invalid_period", "Period must be daily, weekly, or all-time."))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.Error(apierror.BadRequest("invalid_limit", "Limit must be between 1 and 100."))
+		return
+	}
+
+	match := bson.M{}
+	if !since.IsZero() {
+		match["occurred_at"] = bson.M{"$gte": since}
+	}
+
+	scope := c.DefaultQuery("scope", "global")
+	switch scope {
+	case "global":
+	case "friends":
+		members, err := h.friendIDs(c, userIDStr)
+		if err != nil {
+			c.Error(apierror.Internal("database_error", err))
+			return
+		}
+		match["user_id"] = bson.M{"$in": append(members, userIDStr)}
+	default:
+		c.Error(apierror.BadRequest("invalid_scope", "Scope must be global or friends."))
+		return
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{"_id": "$user_id", "xp": bson.M{"$sum": "$points"}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "xp", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+	entries, err := database.AggregateAll[models.LeaderboardEntry](c, h.awards, pipeline)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+
+	me, err := h.userStanding(c, match, userIDStr)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"period":  period,
+		"scope":   scope,
+		"entries": entries,
+		"me":      me,
+	})
+}
+
+// ListFriends returns the users the caller follows on the leaderboard.
+func (h *LeaderboardHandler) ListFriends(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	cursor, err := h.friendships.Find(c, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	friends := []models.Friendship{}
+	if err := cursor.All(c, &friends); err != nil {
+		c.Error(apierror.Internal("deserialization_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"friends": friends})
+}
+
+// AddFriend adds a user to the caller's friend-scoped leaderboard.
+func (h *LeaderboardHandler) AddFriend(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDStr, _ := userID.(string)
+	friendID := c.Param("friendId")
+
+	if friendID == "" || friendID == userIDStr {
+		c.Error(apierror.BadRequest("invalid_friend_id", "Friend ID must be another user's ID."))
+		return
+	}
+
+	filter := bson.M{"user_id": userIDStr, "friend_id": friendID}
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"_id":        primitive.NewObjectID(),
+			"created_at": time.Now().UTC(),
+		},
+	}
+	if _, err := h.friendships.UpdateOne(c, filter, update, options.Update().SetUpsert(true)); err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.Status(http.StatusCreated)
+}
+
+// RemoveFriend removes a user from the caller's friend-scoped leaderboard.
+func (h *LeaderboardHandler) RemoveFriend(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	_, err := h.friendships.DeleteOne(c, bson.M{"user_id": userID, "friend_id": c.Param("friendId")})
+	if err != nil {
+		c.Error(apierror.Internal("delete_failed", err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// friendIDs returns the IDs of the users the given user follows.
+func (h *LeaderboardHandler) friendIDs(ctx context.Context, userID string) ([]string, error) {
+	cursor, err := h.friendships.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+
+	var friends []models.Friendship
+	if err := cursor.All(ctx, &friends); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(friends))
+	for _, friend := range friends {
+		ids = append(ids, friend.FriendID)
+	}
+	return ids, nil
+}
+
+// userStanding returns the caller's XP and rank among the users matched by match.
+func (h *LeaderboardHandler) userStanding(ctx context.Context, match bson.M, userID string) (models.LeaderboardEntry, error) {
+	standing := models.LeaderboardEntry{UserID: userID}
+
+	userMatch := bson.M{"user_id": userID}
+	if since, ok := match["occurred_at"]; ok {
+		userMatch["occurred_at"] = since
+	}
+	xp, err := h.sumXP(ctx, userMatch)
+	if err != nil {
+		return standing, err
+	}
+	standing.XP = xp
+
+	// Rank is one more than the number of users with strictly more XP.
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{"_id": "$user_id", "xp": bson.M{"$sum": "$points"}}}},
+		{{Key: "$match", Value: bson.M{"xp": bson.M{"$gt": xp}}}},
+		{{Key: "$count", Value: "ahead"}},
+	}
+	count, _, err := database.AggregateOne[struct {
+		Ahead int `bson:"ahead"`
+	}](ctx, h.awards, pipeline)
+	if err != nil {
+		return standing, err
+	}
+
+	standing.Rank = 1 + count.Ahead
+	return standing, nil
+}
+
+// sumXP totals the points of all awards matching filter.
+func (h *LeaderboardHandler) sumXP(ctx context.Context, filter bson.M) (int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "xp": bson.M{"$sum": "$points"}}}},
+	}
+	total, _, err := database.AggregateOne[struct {
+		XP int `bson:"xp"`
+	}](ctx, h.awards, pipeline)
+	return total.XP, err
+}
+
+// xpForEvent applies the XP rules to a domain event. Unknown events are worth nothing, and so are
+// answers the quiz service did not grade itself, whose correctness the client asserted.
+func xpForEvent(event events.Event) int {
+	switch event.Type {
+	case events.TypeQuizAnswer:
+		if graded, _ := event.Data["graded"].(bool); !graded {
+			return 0
+		}
+		if correct, _ := event.Data["correct"].(bool); correct {
+			return xpCorrectAnswer
+		}
+		return xpIncorrectAnswer
+	case events.TypeSRSReview:
+		return xpSRSReview
+	default:
+		return 0
+	}
+}
+
+// xpClaim returns the key under which an event's XP is claimed for the day, or "" for events
+// that are not limited. Reviews are claimed apart from answers, so a word can earn both.
+func xpClaim(event events.Event) string {
+	vocabularyID, _ := event.Data["vocabulary_id"].(string)
+	day := event.OccurredAt.UTC().Format("2006-01-02")
+	switch event.Type {
+	case events.TypeQuizAnswer:
+		return event.UserID + "|" + vocabularyID + "|" + day
+	case events.TypeSRSReview:
+		return "review|" + event.UserID + "|" + vocabularyID + "|" + day
+	default:
+		return ""
+	}
+}
+
+// periodStart returns the start of the leaderboard window containing now.
+// All-time leaderboards have no start and return the zero time.
+func periodStart(period string, now time.Time) (time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case PeriodDaily:
+		return today, true
+	case PeriodWeekly:
+		// Weeks start on Monday.
+		offset := (int(today.Weekday()) + 6) % 7
+		return today.AddDate(0, 0, -offset), true
+	case PeriodAllTime:
+		return time.Time{}, true
+	default:
+		return time.Time{}, false
+	}
+}
@@ -0,0 +1,34 @@
+// FILE: services/leaderboard/internal/models/leaderboard.go
+
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// XPAward records the experience points granted to a user for a single domain event.
+type XPAward struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	EventID    string             `bson:"event_id"` // ID of the source event; unique so retries don't double-award
+	EventType  string             `bson:"event_type"`
+	UserID     string             `bson:"user_id"` // The Auth0 ID of the user
+	Points     int                `bson:"points"`
+	OccurredAt time.Time          `bson:"occurred_at"`
+}
+
+// Friendship is a one-directional link used for friend-scoped leaderboards.
+type Friendship struct {
+	ID        primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	UserID    string             `json:"-" bson:"user_id"`
+	FriendID  string             `json:"friend_id" bson:"friend_id"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// LeaderboardEntry is a single ranked row in a leaderboard response.
+type LeaderboardEntry struct {
+	Rank   int    `json:"rank"`
+	UserID string `json:"user_id" bson:"_id"`
+	XP     int    `json:"xp" bson:"xp"`
+}
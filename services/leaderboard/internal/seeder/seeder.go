@@ -0,0 +1,35 @@
+// FILE: services/leaderboard/internal/seeder/seeder.go
+
+package seeder
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"wise-owl/lib/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Indexes lists every index the leaderboard queries rely on.
+var Indexes = []database.Index{
+	// Deduplicates redelivered events
+	{Collection: "xp_awards", Keys: bson.D{{Key: "event_id", Value: 1}}, Unique: true},
+	// Supports the time-windowed leaderboard aggregations
+	{Collection: "xp_awards", Keys: bson.D{{Key: "occurred_at", Value: 1}, {Key: "user_id", Value: 1}}},
+	// Answer XP claims only matter on the day they are made
+	{Collection: "answer_xp_claims", Keys: bson.D{{Key: "claimed_at", Value: 1}}, ExpireAfter: 48 * time.Hour},
+	{Collection: "friendships", Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "friend_id", Value: 1}}, Unique: true},
+}
+
+// SeedDatabase ensures the declared indexes exist.
+// There is no seed data; XP is awarded as events arrive.
+func SeedDatabase(db *mongo.Database) {
+	if err := database.EnsureIndexes(context.Background(), db, Indexes); err != nil {
+		log.Printf("WARN: Failed to ensure leaderboard indexes: %v", err)
+	}
+
+	log.Println("Leaderboard service initialized successfully")
+}
@@ -5,19 +5,56 @@ package main
 
 import (
 	"context"
+	"errors"
+	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	pb_content "wise-owl/gen/proto/content"
+	pb_content "wise-owl/gen/proto/content/v1"
+	pb_quiz "wise-owl/gen/proto/quiz/v1"
+	pb_users "wise-owl/gen/proto/users/v1"
+	"wise-owl/lib/apierror"
 	"wise-owl/lib/auth"
+	"wise-owl/lib/backup"
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/compress"
 	"wise-owl/lib/config"
+	"wise-owl/lib/ctxutil"
 	"wise-owl/lib/database"
+	"wise-owl/lib/events"
+	"wise-owl/lib/grpcdebug"
+	"wise-owl/lib/grpcutil"
 	"wise-owl/lib/health"
+	"wise-owl/lib/jobs"
+	"wise-owl/lib/metrics"
+	"wise-owl/lib/openapi"
+	"wise-owl/lib/preset"
+	"wise-owl/lib/resilience"
+	"wise-owl/lib/romanize"
+	"wise-owl/lib/storage"
+	"wise-owl/lib/stt"
+	"wise-owl/lib/usage"
+	"wise-owl/lib/validation"
+	"wise-owl/services/quiz/internal/apidocs"
+	"wise-owl/services/quiz/internal/classrooms"
+	"wise-owl/services/quiz/internal/grading"
+	quiz_grpc "wise-owl/services/quiz/internal/grpc"
 	"wise-owl/services/quiz/internal/handlers"
+	"wise-owl/services/quiz/internal/live"
+	"wise-owl/services/quiz/internal/mastery"
+	"wise-owl/services/quiz/internal/offline"
+	"wise-owl/services/quiz/internal/questions"
+	"wise-owl/services/quiz/internal/seeder"
+	"wise-owl/services/quiz/internal/sessions"
+	"wise-owl/services/quiz/internal/stats"
+	"wise-owl/services/quiz/internal/studyplan"
+	"wise-owl/services/quiz/internal/studysets"
+	"wise-owl/services/quiz/internal/unlocks"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -27,10 +64,12 @@ import (
 
 func main() {
 	// 1. Load Configuration (supports both local and AWS environments)
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadConfig("quiz")
 	if err != nil {
 		log.Fatalf("FATAL: could not load config: %v", err)
 	}
+	stopMetrics := metrics.Start(cfg.Metrics, "quiz") // CloudWatch EMF metrics, when EMF_METRICS_ENABLED is set
+	defer stopMetrics()
 
 	dbName := cfg.DB_NAME
 	if dbName == "" {
@@ -39,33 +78,47 @@ func main() {
 	log.Printf("Configuration loaded. Using database: %s (Type: %s)", dbName, cfg.DB_TYPE)
 
 	// 2. Connect to Database (supports MongoDB and DocumentDB)
-	db := database.CreateDatabaseSingleton(cfg)
+	db, err := database.CreateDatabase(cfg)
+	if err != nil {
+		log.Fatalf("FATAL: Failed to connect to database: %v", err)
+	}
+	defer db.Close()
 	mongoClient := db.GetClient().(*mongo.Client)
 	mongoDatabase := mongoClient.Database(dbName)
 	log.Println("Database connection established.")
+	seeder.SeedDatabase(mongoDatabase)
 
-	// 3. Initialize health checker (choose based on environment)
-	var healthChecker interface {
-		RegisterRoutes(*gin.Engine)
-		Handler() gin.HandlerFunc
-		ReadyHandler() gin.HandlerFunc
+	// Scheduled exports of learners' and teachers' data; `quiz restore-backup` restores one and
+	// exits (see lib/backup). Quiz sessions are short-lived and left out.
+	backups, err := backup.New(context.Background(), cfg.Backup, "quiz", mongoDatabase, []string{
+		"incorrect_words", "answer_stats", "mastery", "synced_answers", "study_sets", "study_set_likes",
+		"classrooms", "class_members", "class_assignments", changefeed.TombstoneCollection,
+	})
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
 	}
-
-	// Use AWS health checker if running in AWS environment
-	if config.IsAWSEnvironment() {
-		log.Println("AWS environment detected, using enhanced health checks")
-		awsHealthChecker := health.NewAWSHealthChecker("Quiz Service", mongoDatabase)
-		healthChecker = awsHealthChecker
-	} else {
-		log.Println("Local environment detected, using simple health checks")
-		simpleHealthChecker := health.NewSimpleHealthChecker("Quiz Service")
-		simpleHealthChecker.SetMongoClient(mongoClient, dbName)
-		healthChecker = simpleHealthChecker
+	if backup.Requested() {
+		os.Exit(backups.RunCommand(context.Background(), os.Args[2:]))
 	}
 
+	// 3. Initialize health checker
+	healthChecker := health.New("Quiz Service", health.Options{
+		Database: mongoDatabase,
+		AWS:      config.IsAWSEnvironment(), // Deep checks and environment details for ECS
+	})
+
 	// 4. gRPC Client Setup for Content Service
 	contentServiceURL := getContentServiceURL()
-	conn, err := grpc.Dial(contentServiceURL, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// Calls fail fast with Unavailable once content keeps timing out, instead of each request
+	// waiting out its own deadline. The health probe shares the breaker and reports its state.
+	contentBreaker := resilience.NewCircuitBreaker("content-grpc", resilience.Options{
+		IsFailure: resilience.IsGRPCFailure,
+	})
+	dialOpts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, resilience.DialOptions(contentBreaker)...)
+	dialOpts = append(dialOpts, grpcdebug.DialOptions(cfg.GRPCDebugLog)...)
+	// Round-robin across every content task discovery returns, re-resolved as the service scales
+	dialOpts = append(dialOpts, grpcutil.ClientOptions()...)
+	conn, err := grpc.Dial(grpcutil.ClientTarget(contentServiceURL), dialOpts...)
 	if err != nil {
 		log.Fatalf("Did not connect to content-service: %v", err)
 	}
@@ -73,13 +126,122 @@ func main() {
 	contentClient := pb_content.NewContentServiceClient(conn)
 	log.Printf("Successfully connected to content-service gRPC at %s", contentServiceURL)
 
-	// 5. Initialize HTTP Router and Middleware
-	router := gin.Default()
+	// Report content's gRPC health alongside ours. Grading and rooms already created keep
+	// working without it, so it does not take the quiz service out of rotation.
+	if err := healthChecker.AddDependency(health.DependencyConfig{
+		Name:      "content-grpc",
+		CheckType: health.CheckGRPC,
+		Target:    contentServiceURL,
+		Conn:      conn,
+		Breaker:   contentBreaker,
+	}); err != nil {
+		log.Printf("WARN: Failed to register content-service health dependency: %v", err)
+	}
+
+	// Users' tiers come from the users service, for quotas and premium-only features
+	usersServiceURL := getUsersServiceURL()
+	usersBreaker := resilience.NewCircuitBreaker("users-grpc", resilience.Options{
+		IsFailure: resilience.IsGRPCFailure,
+	})
+	usersDialOpts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, resilience.DialOptions(usersBreaker)...)
+	usersDialOpts = append(usersDialOpts, grpcdebug.DialOptions(cfg.GRPCDebugLog)...)
+	usersDialOpts = append(usersDialOpts, grpcutil.ClientOptions()...)
+	usersConn, err := grpc.Dial(grpcutil.ClientTarget(usersServiceURL), usersDialOpts...)
+	if err != nil {
+		log.Fatalf("Did not connect to users-service: %v", err)
+	}
+	defer usersConn.Close()
+	// Without users, quotas go unenforced rather than requests failing, so it is not critical either
+	if err := healthChecker.AddDependency(health.DependencyConfig{
+		Name:      "users-grpc",
+		CheckType: health.CheckGRPC,
+		Target:    usersServiceURL,
+		Conn:      usersConn,
+		Breaker:   usersBreaker,
+	}); err != nil {
+		log.Printf("WARN: Failed to register users-service health dependency: %v", err)
+	}
+
+	// 5. Start gRPC Server (quiz statistics and mastery levels for other services)
+	statsStore := stats.NewStore(mongoDatabase)
+	masteryStore := mastery.NewStore(mongoDatabase)
+
+	grpcPort := cfg.GRPCPort
+	if grpcPort == "" {
+		grpcPort = "50053" // Default for quiz service
+	}
+
+	// Logging, metrics, panic recovery, and message size limits for every gRPC call
+	grpcMetrics := grpcutil.NewMetrics()
+	serverOpts := append(grpcutil.ServerOptions(grpcutil.Options{Metrics: grpcMetrics}), grpcdebug.ServerOptions(cfg.GRPCDebugLog)...)
+
+	go func() {
+		lis, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			log.Fatalf("FATAL: Failed to listen for gRPC: %v", err)
+		}
+		s := grpc.NewServer(serverOpts...)
+		pb_quiz.RegisterQuizServiceServer(s, quiz_grpc.NewServer(statsStore, masteryStore))
+		preset.For(cfg.Environment).RegisterReflection(s) // grpcurl in development
+
+		log.Printf("Quiz gRPC server listening at %v", lis.Addr())
+		if err := s.Serve(lis); err != nil {
+			log.Fatalf("FATAL: Failed to serve gRPC: %v", err)
+		}
+	}()
+
+	// 6. Initialize HTTP Router and Middleware
+	// Route objectives, reported at /health/slo and /metrics: slow answers are what learners notice first
+	slos := metrics.NewSLOs("quiz",
+		metrics.Objective{Method: http.MethodPost, Route: "/api/v1/quiz/answers", Availability: 0.999, Latency: 300 * time.Millisecond, LatencyTarget: 0.99},
+		metrics.Objective{Method: http.MethodPost, Route: "/api/v1/quiz/sessions/:sessionId/answers", Availability: 0.999, Latency: 300 * time.Millisecond, LatencyTarget: 0.99},
+		metrics.Objective{Method: http.MethodGet, Route: "/api/v1/study-plan", Availability: 0.995, Latency: time.Second, LatencyTarget: 0.99},
+	)
+	// Gin mode, request logs, and panic recovery suited to the environment (see lib/preset)
+	router := preset.For(cfg.Environment).Router()
+	router.ContextWithFallback = true // Handlers pass c to Mongo; let it carry the request's deadline
+	router.Use(slos.Middleware())     // Outside apierror.Middleware, so it records the status sent
+	router.Use(apierror.Middleware()) // Trace IDs and the standard error envelope
+	router.Use(metrics.Middleware())  // Request latency for CloudWatch
+	router.Use(compress.Gzip())       // Compress larger text and JSON responses
+	router.Use(romanize.Middleware()) // Romaji in the style the caller sends in X-Romanization
+	// Overall request deadline, answered with a 504 when it passes
+	router.Use(ctxutil.Timeout(cfg.RequestTimeout, map[string]time.Duration{
+		"/api/v1/quiz/rooms/:roomId/events": 0,                // Server-sent event streams stay open
+		"/api/v1/srs/import":                2 * time.Minute,  // Uploading and matching a large deck
+		"/api/v1/quiz/pronunciation":        30 * time.Second, // Uploading a recording and waiting on speech recognition
+	}))
+	// Reject oversized bodies and arrays before they are decoded; uploads limit their own size
+	router.Use(validation.SizeLimits(validation.DefaultLimits, map[string]validation.Limits{
+		"/api/v1/srs/import":           {MaxBytes: validation.Unlimited},
+		"/api/v1/quiz/pronunciation":   {MaxBytes: validation.Unlimited},
+		"/api/v1/quiz/incorrect-words": {MaxItems: 500}, // Each deleted word is its own write
+	}))
+	// Bound each gRPC and database call a handler makes; calls never outlive the request itself
+	router.Use(ctxutil.Middleware(ctxutil.Budgets{GRPC: cfg.GRPCCallTimeout, Database: cfg.DBQueryTimeout}))
+
+	// Guest sessions let people try quizzes before signing up. They need a signing secret and Auth0,
+	// since guest data is only worth keeping if it can be merged into an account later.
+	var guestIssuer *auth.GuestIssuer
+	if cfg.GuestTokenSecret != "" && cfg.Auth0Domain != "" && cfg.Auth0Audience != "" {
+		guestIssuer, err = auth.NewGuestIssuer(cfg.GuestTokenSecret, guestSessionTTL)
+		if err != nil {
+			log.Fatalf("FATAL: could not set up guest sessions: %v", err)
+		}
+		log.Println("Guest sessions enabled")
+	}
 
 	// Initialize auth middleware (skip if Auth0 not configured)
 	var authMiddleware gin.HandlerFunc
 	if cfg.Auth0Domain != "" && cfg.Auth0Audience != "" {
-		authMiddleware = auth.EnsureValidToken(cfg.Auth0Domain, cfg.Auth0Audience)
+		tokenValidator, err := auth.NewValidator(auth.ConfigOptions(cfg))
+		if err != nil {
+			log.Fatalf("FATAL: could not set up token validation: %v", err)
+		}
+		if guestIssuer != nil {
+			tokenValidator = auth.WithGuests(tokenValidator, guestIssuer)
+		}
+		authMiddleware = auth.NewMiddleware(tokenValidator)
 		log.Println("Auth0 authentication enabled")
 	} else {
 		// No-op middleware for development
@@ -89,26 +251,209 @@ func main() {
 		log.Println("Authentication disabled for development")
 	}
 
+	// Only accounts with a verified email may record answers, so spam sign-ups cannot skew quiz data.
+	// Tokens without an email_verified claim are checked against Auth0 when M2M credentials are set.
+	var emailLookup auth.UserLookup
+	if cfg.Auth0Domain != "" && cfg.Auth0ClientID != "" && cfg.Auth0ClientSecret != "" {
+		emailLookup = auth.NewManagementClient(cfg.Auth0Domain, cfg.Auth0ClientID, cfg.Auth0ClientSecret)
+	}
+	requireVerifiedEmail := auth.RequireVerifiedEmail(auth.VerifiedEmailOptions{Lookup: emailLookup})
+
 	// Initialize quiz handler
 	var quizHandler *handlers.QuizHandler
-	quizHandler = handlers.NewQuizHandler(mongoDatabase, contentClient)
+	publisher := events.NewPublisherFromEnv()
+	quizHandler = handlers.NewQuizHandler(mongoDatabase, contentClient, publisher, statsStore, grading.NewGrader(nil))
+
+	// Live quiz rooms are held in memory by the instance that created them; the registry lets the
+	// other instances pass room requests on to it
+	liveURL := getLiveAdvertiseURL(cfg.ServerPort)
+	log.Printf("Live quiz rooms created here are reached at %s", liveURL)
+	hub := live.NewHub(live.NewRegistry(mongoDatabase, liveURL))
+	hubCtx, stopHub := context.WithCancel(context.Background())
+	defer stopHub()
+	go hub.Run(hubCtx)
+	bank := questions.NewBank(contentClient)
+	// Quizzes are kept to the lessons the learner has unlocked, which the users service works out
+	usersURL := getUsersHTTPURL()
+	unlockChecker := unlocks.NewChecker(usersURL, contentClient)
+	roomHandler := handlers.NewRoomHandler(hub, bank, publisher, statsStore, unlockChecker)
+	questionHandler := handlers.NewQuestionHandler(bank, unlockChecker)
+	// Exported Anki decks are stored in the shared object storage and downloaded from there
+	exportStore, err := storage.New(context.Background(), cfg.Storage)
+	if err != nil {
+		log.Fatalf("FATAL: could not initialize storage: %v", err)
+	}
+	studyPlanHandler := handlers.NewStudyPlanHandler(studyplan.NewPlanner(masteryStore, statsStore, unlockChecker, bank, contentClient))
+	guestHandler := handlers.NewGuestHandler(guestIssuer, statsStore)
+	studySetStore := studysets.NewStore(mongoDatabase)
+	studySetHandler := handlers.NewStudySetHandler(studySetStore, bank)
+	classroomStore := classrooms.NewStore(mongoDatabase, contentClient)
+	classroomHandler := handlers.NewClassroomHandler(classroomStore, studySetStore, contentClient)
+	srsHandler := handlers.NewSRSHandler(masteryStore, contentClient, exportStore)
+	// Pronunciation practice needs a speech recognition backend; without one it answers 503
+	recognizer, err := stt.New(cfg.STT)
+	switch {
+	case errors.Is(err, stt.ErrDisabled):
+		log.Println("Speech recognition not configured; pronunciation practice disabled")
+	case err != nil:
+		log.Fatalf("FATAL: could not initialize speech recognition: %v", err)
+	}
+	pronunciationHandler := handlers.NewPronunciationHandler(recognizer, contentClient, publisher)
+	// Solo quiz sessions are short-lived and written on every answer; Redis keeps them off the database
+	sessionStore, err := sessions.NewStore(context.Background(), cfg.Sessions, mongoDatabase)
+	if err != nil {
+		log.Fatalf("FATAL: could not initialize the quiz session store: %v", err)
+	}
+	if closer, ok := sessionStore.(io.Closer); ok {
+		defer closer.Close()
+	}
+	log.Printf("Quiz sessions kept in %s (expiring after %s)", cfg.Sessions.Backend, cfg.Sessions.TTL)
+	sessionHandler := handlers.NewSessionHandler(sessionStore, bank, unlockChecker, statsStore, publisher)
+	syncHandler := handlers.NewSyncHandler(offline.NewSyncer(mongoDatabase, statsStore, masteryStore, publisher, usersURL))
 
-	// 6. Register health check routes
+	// API calls are counted into the users database and held to the daily limit of the user's tier
+	meter := usage.NewMeter(usage.NewStore(mongoClient.Database(getUsersDBName())), "quiz", usage.NewGRPCTiers(pb_users.NewUsersServiceClient(usersConn)), usage.LimitsFromEnv())
+
+	// Students are reminded of assignments coming due, on one instance at a time
+	scheduler := jobs.NewScheduler(jobs.NewMongoLocker(mongoDatabase.Collection(jobs.LeaseCollection)))
+	if err := scheduler.Add(jobs.Job{
+		Name:     "assignment-reminders",
+		Schedule: classrooms.ReminderSchedule,
+		Run:      classrooms.NewReminder(classroomStore, publisher).RemindDue,
+	}); err != nil {
+		log.Fatalf("FATAL: Failed to schedule assignment reminders: %v", err)
+	}
+	if cfg.Backup.Enabled {
+		if err := scheduler.Add(backups.Job()); err != nil {
+			log.Fatalf("FATAL: Failed to schedule backups: %v", err)
+		}
+	}
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	scheduler.Start(schedulerCtx)
+
+	// 7. Register health check routes; results are refreshed in the background
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	defer stopHealth()
+	healthChecker.Start(healthCtx)
 	healthChecker.RegisterRoutes(router)
+	slos.RegisterRoutes(router)
+
+	// Prometheus scrape endpoint for the gRPC server and SLOs; the gateway does not route it
+	router.GET("/metrics", metrics.Handler(grpcMetrics, slos))
 
-	// 7. Define API Routes
+	// Serve the API contract (and Swagger UI in development)
+	openapi.RegisterRoutes(router, apidocs.Spec, cfg.SwaggerUI)
+
+	// 8. Define API Routes
 	apiV1 := router.Group("/api/v1")
 	{
 		quizRoutes := apiV1.Group("/quiz")
-		quizRoutes.Use(authMiddleware)
+		quizRoutes.Use(authMiddleware, requireVerifiedEmail, meter.Middleware())
 		{
+			quizRoutes.POST("/answers", quizHandler.RecordAnswer)
 			quizRoutes.POST("/incorrect-words", quizHandler.RecordIncorrectWord)
 			quizRoutes.GET("/incorrect-words", quizHandler.GetIncorrectWords)
 			quizRoutes.DELETE("/incorrect-words", quizHandler.DeleteIncorrectWords)
+			quizRoutes.POST("/questions", questionHandler.BuildQuestions)
+			quizRoutes.POST("/sessions", sessionHandler.StartSession)
+			quizRoutes.GET("/sessions/:sessionId", sessionHandler.GetSession)
+			quizRoutes.POST("/sessions/:sessionId/answers", sessionHandler.AnswerSession)
+			quizRoutes.DELETE("/sessions/:sessionId", sessionHandler.EndSession)
+			// Scored attempts are spaced repetition reviews, which guests do not keep
+			quizRoutes.POST("/pronunciation", auth.RejectGuests(), pronunciationHandler.ScorePronunciation)
+
+		}
+
+		// Live rooms show players to each other, so they are for accounts only. A room is served by
+		// the instance holding it, which requests reaching another instance are passed on to.
+		roomRoutes := apiV1.Group("/quiz/rooms")
+		roomRoutes.Use(roomHandler.RouteToOwner, authMiddleware, requireVerifiedEmail, meter.Middleware(), auth.RejectGuests())
+		{
+			roomRoutes.POST("", roomHandler.CreateRoom)
+			roomRoutes.GET("/:roomId", roomHandler.GetRoom)
+			roomRoutes.POST("/:roomId/join", roomHandler.JoinRoom)
+			roomRoutes.POST("/:roomId/leave", roomHandler.LeaveRoom)
+			roomRoutes.POST("/:roomId/start", roomHandler.StartRoom)
+			roomRoutes.POST("/:roomId/answers", roomHandler.SubmitRoomAnswer)
+			roomRoutes.GET("/:roomId/events", roomHandler.StreamRoom)
+		}
+
+		// The daily plan is built from an account's answer history, which guests do not keep
+		apiV1.GET("/study-plan", authMiddleware, auth.RejectGuests(), requireVerifiedEmail, meter.Middleware(), studyPlanHandler.GetStudyPlan)
+
+		// Spaced repetition progress moves in from Anki and is kept per account
+		srsRoutes := apiV1.Group("/srs")
+		srsRoutes.Use(authMiddleware, auth.RejectGuests(), requireVerifiedEmail, meter.Middleware())
+		{
+			srsRoutes.POST("/import", srsHandler.ImportDeck)
+			srsRoutes.GET("/export", srsHandler.ExportDeck)
+		}
+
+		// Offline sync covers account data kept across services, so it is for accounts only
+		syncRoutes := apiV1.Group("/sync")
+		syncRoutes.Use(authMiddleware, auth.RejectGuests(), requireVerifiedEmail, meter.Middleware())
+		{
+			syncRoutes.GET("", syncHandler.GetChanges)
+			syncRoutes.POST("", syncHandler.PushAnswers)
+		}
+
+		// Study sets are kept per account; public ones can be browsed and opened by slug without signing in
+		studySetRoutes := apiV1.Group("/study-sets")
+		studySetRoutes.GET("/public", studySetHandler.SearchPublicStudySets)
+		studySetRoutes.GET("/shared/:slug", studySetHandler.GetSharedStudySet)
+		studySetRoutes.Use(authMiddleware, auth.RejectGuests(), requireVerifiedEmail, meter.Middleware())
+		{
+			studySetRoutes.POST("", studySetHandler.CreateStudySet)
+			studySetRoutes.GET("", studySetHandler.ListStudySets)
+			studySetRoutes.GET("/:setId", studySetHandler.GetStudySet)
+			studySetRoutes.PATCH("/:setId", studySetHandler.UpdateStudySet)
+			studySetRoutes.DELETE("/:setId", studySetHandler.DeleteStudySet)
+			studySetRoutes.POST("/:setId/quiz", studySetHandler.BuildStudySetQuiz)
+			studySetRoutes.POST("/:setId/clone", studySetHandler.CloneStudySet)
+			studySetRoutes.POST("/:setId/like", studySetHandler.LikeStudySet)
+			studySetRoutes.DELETE("/:setId/like", studySetHandler.UnlikeStudySet)
+		}
+
+		// Classes show students to their teacher, so they are for accounts only
+		classRoutes := apiV1.Group("/classes")
+		classRoutes.Use(authMiddleware, auth.RejectGuests(), requireVerifiedEmail, meter.Middleware())
+		{
+			classRoutes.POST("", classroomHandler.CreateClass)
+			classRoutes.GET("", classroomHandler.ListClasses)
+			classRoutes.POST("/join", classroomHandler.JoinClass)
+			classRoutes.GET("/:classId", classroomHandler.GetClass)
+			classRoutes.DELETE("/:classId", classroomHandler.DeleteClass)
+			classRoutes.POST("/:classId/join-code", classroomHandler.ResetJoinCode)
+			classRoutes.DELETE("/:classId/membership", classroomHandler.LeaveClass)
+			classRoutes.GET("/:classId/students", classroomHandler.ListStudents)
+			classRoutes.DELETE("/:classId/students/:studentId", classroomHandler.RemoveStudent)
+			classRoutes.POST("/:classId/assignments", classroomHandler.CreateAssignment)
+			classRoutes.GET("/:classId/assignments", classroomHandler.ListAssignments)
+			classRoutes.PATCH("/:classId/assignments/:assignmentId", classroomHandler.RescheduleAssignment)
+			classRoutes.DELETE("/:classId/assignments/:assignmentId", classroomHandler.DeleteAssignment)
+			classRoutes.GET("/:classId/progress", classroomHandler.GetClassProgress)
+		}
+		// Students see the assignments of every class they joined, with how far they got
+		apiV1.GET("/assignments", authMiddleware, auth.RejectGuests(), requireVerifiedEmail, meter.Middleware(), classroomHandler.ListMyAssignments)
+
+		if guestIssuer != nil {
+			guestRoutes := apiV1.Group("/quiz/guest")
+			{
+				guestRoutes.POST("/session", guestHandler.StartSession)
+				guestRoutes.POST("/merge", authMiddleware, auth.RejectGuests(), guestHandler.MergeSession)
+			}
 		}
 	}
 
-	// 8. Start HTTP Server with Graceful Shutdown
+	// Internal event ingestion (not routed through the gateway); answers and reviews move mastery levels
+	eventHandler := handlers.NewEventHandler(mongoDatabase, masteryStore)
+	internal := router.Group("/internal/v1")
+	{
+		internal.POST("/events", events.Handler(eventHandler.HandleEvent))
+	}
+
+	// 9. Start HTTP Server with Graceful Shutdown
 	srv := &http.Server{Addr: ":" + cfg.ServerPort, Handler: router}
 	go func() {
 		log.Printf("Quiz HTTP server listening on port %s", cfg.ServerPort)
@@ -126,6 +471,9 @@ func main() {
 	srv.Shutdown(ctx)
 }
 
+// guestSessionTTL is how long a guest token is valid; guests start a new session after it expires
+const guestSessionTTL = 24 * time.Hour
+
 // getContentServiceURL returns the appropriate content service URL based on environment
 func getContentServiceURL() string {
 	// In AWS/ECS, services communicate via service discovery or load balancer
@@ -144,3 +492,52 @@ func getContentServiceURL() string {
 	}
 	return "content-service:50052"
 }
+
+// getUsersServiceURL returns the address of the users service's gRPC API
+func getUsersServiceURL() string {
+	if url := os.Getenv("USERS_SERVICE_URL"); url != "" {
+		return url
+	}
+	if config.IsAWSEnvironment() {
+		// Default for ECS service discovery
+		return "users-service.wise-owl-cluster.local:50051"
+	}
+	return "users-service:50051"
+}
+
+// getUsersHTTPURL returns the base URL of the users service's HTTP API
+func getUsersHTTPURL() string {
+	if url := os.Getenv("USERS_HTTP_URL"); url != "" {
+		return url
+	}
+	if config.IsAWSEnvironment() {
+		// Default for ECS service discovery
+		return "http://users-service.wise-owl-cluster.local:8080"
+	}
+	return "http://users-service:8080"
+}
+
+// getLiveAdvertiseURL returns the address other quiz instances reach this one's HTTP server at,
+// from LIVE_ADVERTISE_URL or else this host's first non-loopback IPv4 address, such as an ECS task's
+func getLiveAdvertiseURL(port string) string {
+	if url := os.Getenv("LIVE_ADVERTISE_URL"); url != "" {
+		return url
+	}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+				return "http://" + net.JoinHostPort(ipNet.IP.String(), port)
+			}
+		}
+	}
+	log.Printf("WARN: No network address found for live rooms; set LIVE_ADVERTISE_URL when running several quiz instances")
+	return "http://" + net.JoinHostPort("localhost", port)
+}
+
+// getUsersDBName returns the name of the users service's database
+func getUsersDBName() string {
+	if name := os.Getenv("USERS_DB_NAME"); name != "" {
+		return name
+	}
+	return "users_db"
+}
@@ -0,0 +1,348 @@
+// FILE: services/quiz/internal/anki/anki.go
+// This package reads decks exported from Anki, so learners moving to Wise Owl can bring their
+// review history with them, and writes decks Anki can import (see export.go). It understands .apkg packages (and .colpkg collection backups) in
+// the format Anki writes with "Support older Anki versions" checked, and plain CSV or tab-separated
+// text with optional scheduling columns.
+
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // Registers the "sqlite" driver for reading collections
+)
+
+const (
+	// MaxUploadBytes caps an uploaded deck; packages carry their media, so allow for audio
+	MaxUploadBytes = 100 << 20
+	// maxCollectionBytes caps the collection database unpacked from a package
+	maxCollectionBytes = 256 << 20
+)
+
+var (
+	// ErrInvalid is returned for files that are not a readable deck
+	ErrInvalid = errors.New("not a readable Anki deck")
+	// ErrUnsupported is returned for packages only newer Anki versions can read
+	ErrUnsupported = errors.New("deck uses the newer Anki package format")
+)
+
+// Card is one card of a deck with the fields of its note and its scheduling state.
+type Card struct {
+	Fields   []string      // The note's fields as plain text
+	New      bool          // Never studied
+	Interval time.Duration // Time between its last two reviews; 0 if unknown
+	Ease     float64       // SM-2 ease factor, e.g. 2.5; 0 if unknown
+	Due      time.Time     // Next review; zero if unknown
+	Reviews  int64
+	Lapses   int64 // Times it was forgotten after being learned
+}
+
+// Anki card types and queues, as stored in its collection
+const (
+	cardTypeNew   = 0
+	queueLearning = 1 // Intraday learning: due is a Unix timestamp
+)
+
+// ReadPackage reads the cards of an .apkg or .colpkg file of size bytes.
+func ReadPackage(ctx context.Context, r io.ReaderAt, size int64) ([]Card, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+	// Packages in the newer format keep a placeholder collection.anki2 telling old versions to upgrade
+	collection := files["collection.anki21"]
+	if collection == nil {
+		if files["collection.anki21b"] != nil {
+			return nil, ErrUnsupported
+		}
+		collection = files["collection.anki2"]
+	}
+	if collection == nil {
+		return nil, fmt.Errorf("%w: no collection in package", ErrInvalid)
+	}
+
+	// SQLite reads from a file, so the collection is unpacked to a temporary one
+	tmp, err := os.CreateTemp("", "anki-*.db")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	src, err := collection.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	n, err := io.Copy(tmp, io.LimitReader(src, maxCollectionBytes+1))
+	src.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if n > maxCollectionBytes {
+		return nil, fmt.Errorf("%w: collection is larger than %d bytes", ErrInvalid, maxCollectionBytes)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+tmp.Name()+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	cards, err := readCollection(ctx, db)
+	if err != nil && ctx.Err() == nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return cards, err
+}
+
+// readCollection reads every card of an Anki collection database with its note's fields.
+func readCollection(ctx context.Context, db *sql.DB) ([]Card, error) {
+	// Review due dates count days from the collection's creation
+	var created int64
+	if err := db.QueryRowContext(ctx, "SELECT crt FROM col").Scan(&created); err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, `SELECT n.flds, c.type, c.queue, c.due, c.ivl, c.factor, c.reps, c.lapses
+		FROM cards c JOIN notes n ON n.id = c.nid ORDER BY c.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cards []Card
+	for rows.Next() {
+		var (
+			fields                                 string
+			cardType, queue, due, interval, factor int64
+			reviews, lapses                        int64
+		)
+		if err := rows.Scan(&fields, &cardType, &queue, &due, &interval, &factor, &reviews, &lapses); err != nil {
+			return nil, err
+		}
+		card := Card{
+			Fields:  plainFields(strings.Split(fields, "\x1f")),
+			New:     cardType == cardTypeNew,
+			Ease:    float64(factor) / 1000,
+			Reviews: reviews,
+			Lapses:  lapses,
+		}
+		// Positive intervals are days; learning cards have negative ones in seconds
+		if interval >= 0 {
+			card.Interval = time.Duration(interval) * 24 * time.Hour
+		} else {
+			card.Interval = time.Duration(-interval) * time.Second
+		}
+		if !card.New {
+			if queue == queueLearning {
+				card.Due = time.Unix(due, 0).UTC()
+			} else {
+				card.Due = time.Unix(created, 0).UTC().AddDate(0, 0, int(due))
+			}
+		}
+		cards = append(cards, card)
+	}
+	return cards, rows.Err()
+}
+
+// Column names a CSV header may use, matched case-insensitively. Other columns are note fields.
+var (
+	intervalColumns = []string{"interval", "ivl"}
+	easeColumns     = []string{"ease", "factor"}
+	dueColumns      = []string{"due", "due_at"}
+	reviewColumns   = []string{"reviews", "reps"}
+	lapseColumns    = []string{"lapses"}
+	fieldColumns    = []string{"kanji", "kana", "expression", "reading", "word", "front", "back", "meaning", "english"}
+)
+
+// separators are the names Anki's text export uses in its "#separator:" header
+var separators = map[string]rune{"tab": '\t', "comma": ',', "semicolon": ';', "pipe": '|', "space": ' '}
+
+// ReadCSV reads cards from comma- or tab-separated text, one note per line, such as Anki's "Notes
+// in Plain Text" export. A header row naming its columns may add scheduling: interval (days),
+// ease (2.5 or Anki's 2500), due (a date), reviews, and lapses. Lines starting with '#' are skipped.
+func ReadCSV(r io.Reader) ([]Card, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = separator(data)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: no rows", ErrInvalid)
+	}
+
+	columns := headerColumns(records[0])
+	if columns != nil {
+		records = records[1:]
+	}
+	cards := make([]Card, 0, len(records))
+	for line, record := range records {
+		card, err := csvCard(record, columns)
+		if err != nil {
+			return nil, fmt.Errorf("%w: row %d: %v", ErrInvalid, line+1, err)
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// separator returns the separator named by a "#separator:" header, or tab when the first row has
+// one, or comma.
+func separator(data []byte) rune {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if name, ok := strings.CutPrefix(line, "#separator:"); ok {
+			if sep, ok := separators[strings.ToLower(name)]; ok {
+				return sep
+			}
+			if runes := []rune(name); len(runes) == 1 {
+				return runes[0]
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.Contains(line, "\t") {
+			return '\t'
+		}
+		return ','
+	}
+	return ','
+}
+
+// headerColumns maps the scheduling column names of a header row to their index and every other
+// column to "field". It returns nil when the row names no known column, so it is a card.
+func headerColumns(row []string) []string {
+	columns := make([]string, len(row))
+	known := false
+	for i, name := range row {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case slices.Contains(intervalColumns, name):
+			columns[i] = "interval"
+		case slices.Contains(easeColumns, name):
+			columns[i] = "ease"
+		case slices.Contains(dueColumns, name):
+			columns[i] = "due"
+		case slices.Contains(reviewColumns, name):
+			columns[i] = "reviews"
+		case slices.Contains(lapseColumns, name):
+			columns[i] = "lapses"
+		default:
+			columns[i] = "field"
+			if !slices.Contains(fieldColumns, name) {
+				continue
+			}
+		}
+		known = true
+	}
+	if !known {
+		return nil
+	}
+	return columns
+}
+
+// csvCard builds a card from a row; columns is nil for files without a header.
+func csvCard(record []string, columns []string) (Card, error) {
+	var card Card
+	for i, value := range record {
+		value = strings.TrimSpace(value)
+		column := "field"
+		if i < len(columns) {
+			column = columns[i]
+		}
+		if column != "field" && value == "" {
+			continue
+		}
+		switch column {
+		case "interval":
+			days, err := strconv.ParseFloat(value, 64)
+			if err != nil || days < 0 {
+				return card, fmt.Errorf("invalid interval %q", value)
+			}
+			card.Interval = time.Duration(days * float64(24*time.Hour))
+		case "ease":
+			ease, err := strconv.ParseFloat(value, 64)
+			if err != nil || ease < 0 {
+				return card, fmt.Errorf("invalid ease %q", value)
+			}
+			if ease > 100 {
+				ease /= 1000 // Anki stores ease in permille
+			}
+			card.Ease = ease
+		case "due":
+			due, err := parseDate(value)
+			if err != nil {
+				return card, fmt.Errorf("invalid due date %q", value)
+			}
+			card.Due = due
+		case "reviews":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || n < 0 {
+				return card, fmt.Errorf("invalid reviews %q", value)
+			}
+			card.Reviews = n
+		case "lapses":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || n < 0 {
+				return card, fmt.Errorf("invalid lapses %q", value)
+			}
+			card.Lapses = n
+		default:
+			card.Fields = append(card.Fields, value)
+		}
+	}
+	card.Fields = plainFields(card.Fields)
+	return card, nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+var (
+	htmlTag      = regexp.MustCompile(`<[^>]*>`)
+	soundTag     = regexp.MustCompile(`\[sound:[^\]]*\]`)
+	lineBreakTag = regexp.MustCompile(`(?i)<br\s*/?>|<div>`)
+)
+
+// plainFields strips the HTML and audio references Anki keeps in note fields.
+func plainFields(fields []string) []string {
+	plain := make([]string, len(fields))
+	for i, field := range fields {
+		field = soundTag.ReplaceAllString(field, "")
+		field = lineBreakTag.ReplaceAllString(field, " ")
+		field = htmlTag.ReplaceAllString(field, "")
+		plain[i] = strings.TrimSpace(html.UnescapeString(field))
+	}
+	return plain
+}
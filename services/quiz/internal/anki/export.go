@@ -0,0 +1,273 @@
+// FILE: services/quiz/internal/anki/export.go
+
+package anki
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFields names the note fields of exported cards, in the order of Card.Fields. The ID field
+// keeps a note tied to its word when the deck is imported back.
+var ExportFields = []string{"Expression", "Reading", "Romaji", "English", "Burmese", "Wise Owl ID"}
+
+const (
+	// DeckName is the deck exported cards go in
+	DeckName = "Wise Owl"
+
+	// Fixed IDs, so importing a newer export into Anki updates the note type and deck of the last one.
+	// Notes likewise keep their word's ID as their GUID.
+	modelID = 1712345678901
+	deckID  = 1712345678902
+
+	// collectionVersion is the collection schema Anki reads when "Support older Anki versions" is checked
+	collectionVersion = 11
+	// defaultFactor is the ease Anki gives new cards, in permille
+	defaultFactor = 2500
+)
+
+// Anki card type and queue of review cards
+const (
+	cardTypeReview = 2
+	queueReview    = 2
+)
+
+// collectionSchema creates the tables of a version 11 Anki collection.
+const collectionSchema = `
+CREATE TABLE col (id integer primary key, crt integer not null, mod integer not null, scm integer not null,
+	ver integer not null, dty integer not null, usn integer not null, ls integer not null, conf text not null,
+	models text not null, decks text not null, dconf text not null, tags text not null);
+CREATE TABLE notes (id integer primary key, guid text not null, mid integer not null, mod integer not null,
+	usn integer not null, tags text not null, flds text not null, sfld integer not null, csum integer not null,
+	flags integer not null, data text not null);
+CREATE TABLE cards (id integer primary key, nid integer not null, did integer not null, ord integer not null,
+	mod integer not null, usn integer not null, type integer not null, queue integer not null, due integer not null,
+	ivl integer not null, factor integer not null, reps integer not null, lapses integer not null, left integer not null,
+	odue integer not null, odid integer not null, flags integer not null, data text not null);
+CREATE TABLE revlog (id integer primary key, cid integer not null, usn integer not null, ease integer not null,
+	ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null, type integer not null);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+CREATE INDEX ix_notes_usn ON notes (usn);
+CREATE INDEX ix_cards_usn ON cards (usn);
+CREATE INDEX ix_revlog_usn ON revlog (usn);
+CREATE INDEX ix_cards_nid ON cards (nid);
+CREATE INDEX ix_cards_sched ON cards (did, queue, due);
+CREATE INDEX ix_revlog_cid ON revlog (cid);
+CREATE INDEX ix_notes_csum ON notes (csum);
+`
+
+// WritePackage writes cards to w as an .apkg package holding one deck of review cards. Each card's
+// fields follow ExportFields, and its interval, ease, and due date carry over to Anki's schedule.
+func WritePackage(ctx context.Context, w io.Writer, cards []Card, now time.Time) error {
+	// SQLite writes to a file, so the collection is built in a temporary one
+	tmp, err := os.CreateTemp("", "anki-*.db")
+	if err != nil {
+		return err
+	}
+	path := tmp.Name()
+	tmp.Close()
+	defer os.Remove(path)
+
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		return err
+	}
+	err = writeCollection(ctx, db, cards, now)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	collection, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer collection.Close()
+	archive := zip.NewWriter(w)
+	entry, err := archive.Create("collection.anki2")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(entry, collection); err != nil {
+		return err
+	}
+	// The package carries no media files
+	media, err := archive.Create("media")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(media, "{}"); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// writeCollection creates the collection's tables and fills them with the deck.
+func writeCollection(ctx context.Context, db *sql.DB, cards []Card, now time.Time) error {
+	if _, err := db.ExecContext(ctx, collectionSchema); err != nil {
+		return err
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Review due dates count days from the collection's creation, which is taken to be today
+	created := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	models, decks, dconf, conf, err := collectionConfig(now)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO col VALUES (1, ?, ?, ?, ?, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+		created.Unix(), now.UnixMilli(), now.UnixMilli(), collectionVersion, conf, models, decks, dconf); err != nil {
+		return err
+	}
+
+	note, err := tx.PrepareContext(ctx, `INSERT INTO notes VALUES (?, ?, ?, ?, -1, '', ?, ?, ?, 0, '')`)
+	if err != nil {
+		return err
+	}
+	defer note.Close()
+	card, err := tx.PrepareContext(ctx, `INSERT INTO cards VALUES (?, ?, ?, 0, ?, -1, ?, ?, ?, ?, ?, ?, ?, 0, 0, 0, 0, '')`)
+	if err != nil {
+		return err
+	}
+	defer card.Close()
+
+	// Note and card IDs are creation times in milliseconds, one apart
+	base := now.UnixMilli()
+	for i, c := range cards {
+		// Anki renders fields as HTML, which plainFields undoes on import
+		fields := make([]string, len(ExportFields))
+		for j := range min(len(c.Fields), len(fields)) {
+			fields[j] = html.EscapeString(c.Fields[j])
+		}
+		id := base + int64(i)
+		if _, err := note.ExecContext(ctx, id, "wise-owl-"+fields[len(fields)-1], modelID, now.Unix(),
+			strings.Join(fields, "\x1f"), fields[0], fieldChecksum(fields[0])); err != nil {
+			return err
+		}
+
+		interval := max(int64(math.Round(c.Interval.Hours()/24)), 1)
+		factor := defaultFactor
+		if c.Ease > 0 {
+			factor = int(math.Round(c.Ease * 1000))
+		}
+		due := int64(math.Floor(c.Due.Sub(created).Hours() / 24))
+		if _, err := card.ExecContext(ctx, id, id, deckID, now.Unix(), cardTypeReview, queueReview,
+			due, interval, factor, c.Reviews, c.Lapses); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// fieldChecksum is Anki's duplicate check of a note: the first 32 bits of the SHA-1 of its first field.
+func fieldChecksum(field string) int64 {
+	sum := sha1.Sum([]byte(field))
+	return int64(binary.BigEndian.Uint32(sum[:4]))
+}
+
+// collectionConfig returns the JSON of the collection's note types, decks, deck options, and
+// settings: one note type with a recognition card, and the Wise Owl deck beside Anki's default one.
+func collectionConfig(now time.Time) (models, decks, dconf, conf string, err error) {
+	fields := make([]map[string]any, len(ExportFields))
+	for i, name := range ExportFields {
+		fields[i] = map[string]any{"name": name, "ord": i, "sticky": false, "rtl": false, "font": "Arial", "size": 20, "media": []any{}}
+	}
+	model := map[string]any{
+		"id": modelID, "name": "Wise Owl Vocabulary", "type": 0, "mod": now.Unix(), "usn": -1, "sortf": 0, "did": deckID,
+		"flds": fields,
+		"tmpls": []map[string]any{{
+			"name": "Recognition", "ord": 0, "did": nil, "bqfmt": "", "bafmt": "",
+			"qfmt": `<div class="expression">{{Expression}}</div>`,
+			"afmt": "{{FrontSide}}<hr id=answer>{{Reading}}<br>{{Romaji}}<br><br>{{English}}<br>{{Burmese}}",
+		}},
+		"css":       ".card { font-family: sans-serif; font-size: 24px; text-align: center; }\n.expression { font-size: 48px; }",
+		"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+		"latexPost": "\\end{document}",
+		"tags":      []any{}, "vers": []any{},
+		"req": []any{[]any{0, "any", []any{0}}},
+	}
+	deck := func(id int64, name string) map[string]any {
+		return map[string]any{
+			"id": id, "name": name, "mod": now.Unix(), "usn": -1, "desc": "", "dyn": 0, "conf": 1,
+			"collapsed": false, "browserCollapsed": false, "extendNew": 0, "extendRev": 0,
+			"newToday": []int{0, 0}, "revToday": []int{0, 0}, "lrnToday": []int{0, 0}, "timeToday": []int{0, 0},
+		}
+	}
+	options := map[string]any{
+		"id": 1, "name": "Default", "mod": 0, "usn": 0, "maxTaken": 60, "autoplay": true, "timer": 0, "replayq": true, "dyn": false,
+		"new":   map[string]any{"delays": []float64{1, 10}, "ints": []int{1, 4, 0}, "initialFactor": defaultFactor, "order": 1, "perDay": 20, "bury": false},
+		"rev":   map[string]any{"perDay": 200, "ease4": 1.3, "ivlFct": 1, "maxIvl": 36500, "hardFactor": 1.2, "bury": false},
+		"lapse": map[string]any{"delays": []float64{10}, "mult": 0, "minInt": 1, "leechFails": 8, "leechAction": 1},
+	}
+	settings := map[string]any{
+		"nextPos": 1, "estTimes": true, "activeDecks": []int64{1}, "sortType": "noteFld", "timeLim": 0, "sortBackwards": false,
+		"addToCur": true, "curDeck": deckID, "newSpread": 0, "dueCounts": true, "curModel": modelID, "collapseTime": 1200,
+	}
+
+	values := []any{
+		map[string]any{strconv.FormatInt(modelID, 10): model},
+		map[string]any{"1": deck(1, "Default"), strconv.FormatInt(deckID, 10): deck(deckID, DeckName)},
+		map[string]any{"1": options},
+		settings,
+	}
+	encoded := make([]string, len(values))
+	for i, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", "", "", "", err
+		}
+		encoded[i] = string(data)
+	}
+	return encoded[0], encoded[1], encoded[2], encoded[3], nil
+}
+
+// WriteCSV writes cards to w as comma-separated text with a header, which ReadCSV and spreadsheet
+// apps read back: the ExportFields, then interval (days), ease, due (a date), reviews, and lapses.
+func WriteCSV(w io.Writer, cards []Card) error {
+	out := csv.NewWriter(w)
+	header := make([]string, 0, len(ExportFields)+5)
+	for _, name := range ExportFields {
+		header = append(header, strings.ToLower(strings.ReplaceAll(name, " ", "_")))
+	}
+	if err := out.Write(append(header, "interval", "ease", "due", "reviews", "lapses")); err != nil {
+		return err
+	}
+	for _, c := range cards {
+		row := make([]string, len(ExportFields), len(ExportFields)+5)
+		copy(row, c.Fields)
+		ease := ""
+		if c.Ease > 0 {
+			ease = strconv.FormatFloat(c.Ease, 'f', -1, 64)
+		}
+		row = append(row,
+			strconv.FormatFloat(math.Round(c.Interval.Hours()/24), 'f', -1, 64),
+			ease,
+			c.Due.UTC().Format(time.DateOnly),
+			strconv.FormatInt(c.Reviews, 10),
+			strconv.FormatInt(c.Lapses, 10))
+		if err := out.Write(row); err != nil {
+			return fmt.Errorf("writing row: %w", err)
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,98 @@
+// FILE: services/quiz/internal/anki/match.go
+
+package anki
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	pb_common "wise-owl/gen/proto/common/v1"
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/ctxutil"
+)
+
+// vocabularyPageSize is the largest page the content service serves
+const vocabularyPageSize = 200
+
+// Matcher finds the Wise Owl word a card is about from the kana and kanji in its fields.
+type Matcher struct {
+	byKanji map[string][]string
+	byKana  map[string][]string
+}
+
+// LoadMatcher reads the whole vocabulary from the content service.
+func LoadMatcher(ctx context.Context, contentClient pb_content.ContentServiceClient) (*Matcher, error) {
+	m := &Matcher{byKanji: make(map[string][]string), byKana: make(map[string][]string)}
+	pageToken := ""
+	for {
+		grpcCtx, cancel := ctxutil.GRPC(ctx)
+		page, err := contentClient.SearchVocabulary(grpcCtx, &pb_content.SearchVocabularyRequest{
+			Pagination: &pb_common.Pagination{PageSize: vocabularyPageSize, PageToken: pageToken},
+		})
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Items {
+			m.byKana[item.Kana] = append(m.byKana[item.Kana], item.Id)
+			if kanji := item.GetKanji(); kanji != "" {
+				m.byKanji[kanji] = append(m.byKanji[kanji], item.Id)
+			}
+		}
+		pageToken = page.GetPageInfo().GetNextPageToken()
+		if pageToken == "" {
+			return m, nil
+		}
+	}
+}
+
+// Match returns the ID of the word card is about. A word matching by kanji beats one matching
+// only by kana, and one matching by both beats either, which tells homophones apart when the
+// card has both. ambiguous is true when the best match is shared by several words.
+func (m *Matcher) Match(card Card) (id string, ambiguous bool) {
+	scores := make(map[string]int)
+	for _, term := range terms(card.Fields) {
+		for _, vocabID := range m.byKanji[term] {
+			scores[vocabID] |= 2
+		}
+		for _, vocabID := range m.byKana[term] {
+			scores[vocabID] |= 1
+		}
+	}
+
+	best := 0
+	for vocabID, score := range scores {
+		switch {
+		case score > best:
+			id, best, ambiguous = vocabID, score, false
+		case score == best:
+			ambiguous = true
+		}
+	}
+	if ambiguous {
+		return "", true
+	}
+	return id, false
+}
+
+// rubyReading matches Anki's furigana syntax, a space and the reading in brackets after the kanji: " 日本[にほん]"
+var rubyReading = regexp.MustCompile(`\s?([^\s\[\]]*)\[([^\]]*)\]`)
+
+// terms returns the strings to look words up by: each field as written, and for fields with
+// Anki furigana, the field without the readings and the readings spelled out in full.
+func terms(fields []string) []string {
+	var list []string
+	for _, field := range fields {
+		if field == "" {
+			continue
+		}
+		list = append(list, field)
+		if strings.Contains(field, "[") {
+			list = append(list,
+				rubyReading.ReplaceAllString(field, "$1"),
+				rubyReading.ReplaceAllString(field, "$2"))
+		}
+	}
+	return list
+}
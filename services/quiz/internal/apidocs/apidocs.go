@@ -0,0 +1,11 @@
+// FILE: services/quiz/internal/apidocs/apidocs.go
+// Embeds the Quiz Service OpenAPI document. Update openapi.json alongside any route change.
+
+package apidocs
+
+import _ "embed"
+
+// Spec is the OpenAPI 3 document served at /openapi.json
+//
+//go:embed openapi.json
+var Spec []byte
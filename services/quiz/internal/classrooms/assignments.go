@@ -0,0 +1,227 @@
+// FILE: services/quiz/internal/classrooms/assignments.go
+// A student completes an assignment by practicing each of its words after it was given, in
+// quizzes or SRS reviews. Students who have not completed an assignment are reminded once as
+// its due date approaches.
+
+package classrooms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"wise-owl/lib/database"
+	"wise-owl/lib/events"
+	"wise-owl/services/quiz/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// ReminderSchedule is how often assignments coming due are looked for
+	ReminderSchedule = "@every 15m"
+	// ReminderLead is how long before its due date students are reminded of an assignment
+	ReminderLead = 24 * time.Hour
+	// reminderBatch caps the assignments reminded of per run; the rest wait for the next run
+	reminderBatch = 100
+)
+
+// StudentAssignment is an assignment of a class the student joined, with how far they got.
+type StudentAssignment struct {
+	models.ClassAssignment
+	ClassName      string `json:"class_name"`
+	Words          int    `json:"words"`
+	WordsPracticed int    `json:"words_practiced"` // Words practiced since the assignment was given
+	Status         string `json:"status"`          // models.AssignmentPending, AssignmentCompleted, or AssignmentOverdue
+}
+
+// ForStudent lists the assignments of every class userID joined: those with a due date first,
+// soonest due first, then the rest, newest first.
+func (s *Store) ForStudent(ctx context.Context, userID string, now time.Time) ([]StudentAssignment, error) {
+	classIDs, err := s.joinedIDs(ctx, userID)
+	if err != nil || len(classIDs) == 0 {
+		return []StudentAssignment{}, err
+	}
+	classes, err := database.FindAll[models.Classroom](ctx, s.classes, bson.M{"_id": bson.M{"$in": classIDs}})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(classes))
+	for _, class := range classes {
+		names[class.ID.Hex()] = class.Name
+	}
+	assignments, err := database.FindAll[models.ClassAssignment](ctx, s.assignments, bson.M{"class_id": bson.M{"$in": classIDs}})
+	if err != nil {
+		return nil, err
+	}
+
+	// Classes often share a lesson or set, so each one's words are listed once
+	wordsByTarget := make(map[string][]string)
+	list := make([]StudentAssignment, len(assignments))
+	for i, assignment := range assignments {
+		key := assignment.Kind + ":" + assignment.TargetID
+		words, ok := wordsByTarget[key]
+		if !ok {
+			if words, err = s.words(ctx, assignment); err != nil {
+				return nil, err
+			}
+			wordsByTarget[key] = words
+		}
+		practiced, err := s.practiced(ctx, words, []string{userID}, assignment.AssignedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		list[i] = StudentAssignment{
+			ClassAssignment: assignment,
+			ClassName:       names[assignment.ClassID.Hex()],
+			Words:           len(words),
+			WordsPracticed:  practiced[userID],
+			Status:          status(len(words), practiced[userID], assignment.DueAt, now),
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if (a.DueAt == nil) != (b.DueAt == nil) {
+			return a.DueAt != nil
+		}
+		if a.DueAt != nil && !a.DueAt.Equal(*b.DueAt) {
+			return a.DueAt.Before(*b.DueAt)
+		}
+		return a.AssignedAt.After(b.AssignedAt)
+	})
+	return list, nil
+}
+
+// practiced counts, for each student of userIDs, how many of words they practiced at or after since
+func (s *Store) practiced(ctx context.Context, words, userIDs []string, since time.Time) (map[string]int, error) {
+	counts := make(map[string]int, len(userIDs))
+	if len(words) == 0 || len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	type practiceCount struct {
+		UserID string `bson:"_id"`
+		Words  int    `bson:"words"`
+	}
+	rows, err := database.AggregateAll[practiceCount](ctx, s.mastery, bson.A{
+		bson.M{"$match": bson.M{
+			"user_id":       bson.M{"$in": userIDs},
+			"vocabulary_id": bson.M{"$in": words},
+			"updated_at":    bson.M{"$gte": since},
+		}},
+		bson.M{"$group": bson.M{"_id": "$user_id", "words": bson.M{"$sum": 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.UserID] = row.Words
+	}
+	return counts, nil
+}
+
+// status is a student's completion status of an assignment of words at now. An assignment
+// without words cannot be completed.
+func status(words, practiced int, dueAt *time.Time, now time.Time) string {
+	switch {
+	case words > 0 && practiced >= words:
+		return models.AssignmentCompleted
+	case dueAt != nil && !now.Before(*dueAt):
+		return models.AssignmentOverdue
+	default:
+		return models.AssignmentPending
+	}
+}
+
+// Reminder reminds students of assignments coming due that they have not completed. Reminders
+// are published as assignment.due_soon events for the notifications service to deliver.
+type Reminder struct {
+	store     *Store
+	publisher events.Publisher
+}
+
+// NewReminder creates a reminder over the classroom store.
+func NewReminder(store *Store, publisher events.Publisher) *Reminder {
+	return &Reminder{store: store, publisher: publisher}
+}
+
+// RemindDue reminds the students of each assignment due within ReminderLead that has not been
+// reminded of yet. It is run on ReminderSchedule.
+func (r *Reminder) RemindDue(ctx context.Context) error {
+	now := time.Now().UTC()
+	filter := bson.M{
+		"due_at":      bson.M{"$gt": now, "$lte": now.Add(ReminderLead)},
+		"reminded_at": bson.M{"$exists": false},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "due_at", Value: 1}}).SetLimit(reminderBatch)
+	due, err := database.FindAll[models.ClassAssignment](ctx, r.store.assignments, filter, opts)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, assignment := range due {
+		if err := r.remind(ctx, assignment, now); err != nil {
+			errs = append(errs, fmt.Errorf("assignment %s: %w", assignment.ID.Hex(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// remind publishes a reminder to each student of the assignment's class who has not completed it.
+// The assignment is marked reminded only once everything needed was read, so a failed run is retried.
+func (r *Reminder) remind(ctx context.Context, assignment models.ClassAssignment, now time.Time) error {
+	class, err := r.store.findOne(ctx, bson.M{"_id": assignment.ClassID})
+	if errors.Is(err, ErrNotFound) {
+		return nil // The class is being deleted along with its assignments
+	}
+	if err != nil {
+		return err
+	}
+	students, err := r.store.students(ctx, class.ID)
+	if err != nil || len(students) == 0 {
+		return err
+	}
+	words, err := r.store.words(ctx, assignment)
+	if err != nil {
+		return err
+	}
+	userIDs := make([]string, len(students))
+	for i, student := range students {
+		userIDs[i] = student.UserID
+	}
+	practiced, err := r.store.practiced(ctx, words, userIDs, assignment.AssignedAt)
+	if err != nil {
+		return err
+	}
+
+	// Claim the reminder; a teacher moving the due date in the meantime unsets reminded_at again
+	res, err := r.store.assignments.UpdateOne(ctx,
+		bson.M{"_id": assignment.ID, "due_at": assignment.DueAt, "reminded_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"reminded_at": now}})
+	if err != nil || res.ModifiedCount == 0 {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		if status(len(words), practiced[userID], assignment.DueAt, now) == models.AssignmentCompleted {
+			continue
+		}
+		events.PublishAsync(r.publisher, events.New(events.TypeAssignmentDue, userID, map[string]interface{}{
+			"assignment_id": assignment.ID.Hex(),
+			"class_id":      class.ID.Hex(),
+			"class_name":    class.Name,
+			"kind":          assignment.Kind,
+			"target_id":     assignment.TargetID,
+			"title":         assignment.Title,
+			"due_at":        assignment.DueAt,
+			"words_left":    len(words) - practiced[userID],
+		}))
+	}
+	return nil
+}
@@ -0,0 +1,380 @@
+// FILE: services/quiz/internal/classrooms/classrooms.go
+// This package runs classrooms: a teacher creates a class, students join it with its code, and
+// the teacher assigns lessons and study sets and follows the class's progress on them.
+
+package classrooms
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/database"
+	"wise-owl/services/quiz/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// MaxTaught caps the classes one teacher can run
+	MaxTaught = 20
+	// MaxJoined caps the classes one student can be in
+	MaxJoined = 20
+	// MaxStudents caps a class's size
+	MaxStudents = 200
+	// MaxAssignments caps the assignments a class can have at once
+	MaxAssignments = 50
+
+	// codeAlphabet leaves out characters that are easy to confuse when a code is read out in class
+	codeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+	codeLength   = 8
+	// codeAttempts bounds retries after a join code collision, which the unique index reports
+	codeAttempts = 3
+)
+
+var (
+	// ErrNotFound is returned for classes that do not exist or that the caller may not see
+	ErrNotFound = errors.New("class not found")
+	// ErrStudentNotFound is returned when removing a student who is not in the class
+	ErrStudentNotFound = errors.New("student not found")
+	// ErrAssignmentNotFound is returned when removing an assignment the class does not have
+	ErrAssignmentNotFound = errors.New("assignment not found")
+	// ErrLimit is returned when a teacher, student, or class is at one of the caps above
+	ErrLimit = errors.New("class limit reached")
+	// ErrClassFull is returned when joining a class that has MaxStudents students
+	ErrClassFull = errors.New("class is full")
+	// ErrOwnClass is returned when a teacher tries to join their own class
+	ErrOwnClass = errors.New("teachers cannot join their own class")
+)
+
+// Store reads and writes classes, their students and assignments, and reports their progress.
+type Store struct {
+	classes       *mongo.Collection
+	members       *mongo.Collection
+	assignments   *mongo.Collection
+	sets          *mongo.Collection // Study sets, whose words make up their assignments
+	mastery       *mongo.Collection
+	stats         *mongo.Collection
+	contentClient pb_content.ContentServiceClient // Lists the words of assigned lessons
+}
+
+// NewStore creates a store over the quiz database.
+func NewStore(db *mongo.Database, contentClient pb_content.ContentServiceClient) *Store {
+	return &Store{
+		classes:       db.Collection("classrooms"),
+		members:       db.Collection("class_members"),
+		assignments:   db.Collection("class_assignments"),
+		sets:          db.Collection("study_sets"),
+		mastery:       db.Collection("mastery"),
+		stats:         db.Collection("answer_stats"),
+		contentClient: contentClient,
+	}
+}
+
+// Create starts a class taught by teacherID.
+func (s *Store) Create(ctx context.Context, teacherID, name string) (models.Classroom, error) {
+	count, err := s.classes.CountDocuments(ctx, bson.M{"teacher_id": teacherID})
+	if err != nil {
+		return models.Classroom{}, err
+	}
+	if count >= MaxTaught {
+		return models.Classroom{}, ErrLimit
+	}
+
+	class := models.Classroom{
+		TeacherID: teacherID,
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+		Teaching:  true,
+	}
+	for attempt := 0; ; attempt++ {
+		class.ID = primitive.NewObjectID()
+		class.JoinCode = newJoinCode()
+		err := database.InsertUnique(ctx, s.classes, class)
+		if !errors.Is(err, database.ErrDuplicate) || attempt == codeAttempts-1 {
+			return class, err
+		}
+	}
+}
+
+// List returns the classes userID teaches, newest first, followed by the classes they joined.
+func (s *Store) List(ctx context.Context, userID string) ([]models.Classroom, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	taught, err := database.FindAll[models.Classroom](ctx, s.classes, bson.M{"teacher_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range taught {
+		taught[i].Teaching = true
+	}
+
+	classIDs, err := s.joinedIDs(ctx, userID)
+	if err != nil || len(classIDs) == 0 {
+		return taught, err
+	}
+	joined, err := database.FindAll[models.Classroom](ctx, s.classes, bson.M{"_id": bson.M{"$in": classIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range joined {
+		joined[i].JoinCode = ""
+	}
+	return append(taught, joined...), nil
+}
+
+// Get returns a class its teacher or one of its students asks for. Only the teacher sees the join code.
+func (s *Store) Get(ctx context.Context, id primitive.ObjectID, userID string) (models.Classroom, error) {
+	class, err := s.findOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return class, err
+	}
+	if class.TeacherID == userID {
+		class.Teaching = true
+		return class, nil
+	}
+	if err := s.members.FindOne(ctx, bson.M{"class_id": id, "user_id": userID}).Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.Classroom{}, ErrNotFound
+		}
+		return models.Classroom{}, err
+	}
+	class.JoinCode = ""
+	return class, nil
+}
+
+// Taught returns one of teacherID's classes.
+func (s *Store) Taught(ctx context.Context, id primitive.ObjectID, teacherID string) (models.Classroom, error) {
+	class, err := s.findOne(ctx, bson.M{"_id": id, "teacher_id": teacherID})
+	class.Teaching = err == nil
+	return class, err
+}
+
+// Delete removes one of teacherID's classes with its students and assignments.
+func (s *Store) Delete(ctx context.Context, id primitive.ObjectID, teacherID string) error {
+	res, err := s.classes.DeleteOne(ctx, bson.M{"_id": id, "teacher_id": teacherID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	if _, err := s.members.DeleteMany(ctx, bson.M{"class_id": id}); err != nil {
+		return err
+	}
+	_, err = s.assignments.DeleteMany(ctx, bson.M{"class_id": id})
+	return err
+}
+
+// ResetJoinCode gives one of teacherID's classes a new join code; the old one stops working.
+// Students who already joined stay in the class.
+func (s *Store) ResetJoinCode(ctx context.Context, id primitive.ObjectID, teacherID string) (models.Classroom, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	for attempt := 0; ; attempt++ {
+		var class models.Classroom
+		update := bson.M{"$set": bson.M{"join_code": newJoinCode()}}
+		err := s.classes.FindOneAndUpdate(ctx, bson.M{"_id": id, "teacher_id": teacherID}, update, opts).Decode(&class)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return class, ErrNotFound
+		}
+		if !mongo.IsDuplicateKeyError(err) || attempt == codeAttempts-1 {
+			class.Teaching = err == nil
+			return class, err
+		}
+	}
+}
+
+// Join adds userID to the class with the join code, shown to the teacher as displayName. Joining
+// a class again returns it unchanged.
+func (s *Store) Join(ctx context.Context, code, userID, displayName string) (models.Classroom, error) {
+	class, err := s.findOne(ctx, bson.M{"join_code": NormalizeJoinCode(code)})
+	if err != nil {
+		return class, err
+	}
+	class.JoinCode = ""
+	if class.TeacherID == userID {
+		return class, ErrOwnClass
+	}
+
+	joined, err := s.joinedIDs(ctx, userID)
+	if err != nil {
+		return class, err
+	}
+	for _, id := range joined {
+		if id == class.ID {
+			return class, nil
+		}
+	}
+	if len(joined) >= MaxJoined {
+		return class, ErrLimit
+	}
+
+	// Take a seat first so that concurrent joins cannot overfill the class
+	res, err := s.classes.UpdateOne(ctx,
+		bson.M{"_id": class.ID, "student_count": bson.M{"$lt": MaxStudents}},
+		bson.M{"$inc": bson.M{"student_count": 1}})
+	if err != nil {
+		return class, err
+	}
+	if res.MatchedCount == 0 {
+		return class, ErrClassFull
+	}
+
+	member := models.ClassMember{
+		ID:          primitive.NewObjectID(),
+		ClassID:     class.ID,
+		UserID:      userID,
+		DisplayName: displayName,
+		JoinedAt:    time.Now().UTC(),
+	}
+	if err := database.InsertUnique(ctx, s.members, member); err != nil {
+		// Give the seat back; a duplicate means a concurrent request joined already
+		s.classes.UpdateOne(ctx, bson.M{"_id": class.ID}, bson.M{"$inc": bson.M{"student_count": -1}})
+		if !errors.Is(err, database.ErrDuplicate) {
+			return class, err
+		}
+		return class, nil
+	}
+	class.StudentCount++
+	return class, nil
+}
+
+// Leave removes userID from a class they joined.
+func (s *Store) Leave(ctx context.Context, id primitive.ObjectID, userID string) error {
+	err := s.removeMember(ctx, id, bson.M{"class_id": id, "user_id": userID})
+	if errors.Is(err, ErrStudentNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// Students lists the students of one of teacherID's classes in the order they joined.
+func (s *Store) Students(ctx context.Context, id primitive.ObjectID, teacherID string) ([]models.ClassMember, error) {
+	if _, err := s.Taught(ctx, id, teacherID); err != nil {
+		return nil, err
+	}
+	return s.students(ctx, id)
+}
+
+// RemoveStudent removes a student from one of teacherID's classes.
+func (s *Store) RemoveStudent(ctx context.Context, id primitive.ObjectID, teacherID string, memberID primitive.ObjectID) error {
+	if _, err := s.Taught(ctx, id, teacherID); err != nil {
+		return err
+	}
+	return s.removeMember(ctx, id, bson.M{"_id": memberID, "class_id": id})
+}
+
+// Assign adds an assignment to a class its teacher already looked up.
+func (s *Store) Assign(ctx context.Context, class models.Classroom, assignment *models.ClassAssignment) error {
+	count, err := s.assignments.CountDocuments(ctx, bson.M{"class_id": class.ID})
+	if err != nil {
+		return err
+	}
+	if count >= MaxAssignments {
+		return ErrLimit
+	}
+	assignment.ID = primitive.NewObjectID()
+	assignment.ClassID = class.ID
+	assignment.AssignedAt = time.Now().UTC()
+	_, err = s.assignments.InsertOne(ctx, assignment)
+	return err
+}
+
+// Assignments lists a class's assignments, newest first.
+func (s *Store) Assignments(ctx context.Context, classID primitive.ObjectID) ([]models.ClassAssignment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "assigned_at", Value: -1}})
+	return database.FindAll[models.ClassAssignment](ctx, s.assignments, bson.M{"class_id": classID}, opts)
+}
+
+// Unassign removes an assignment from one of teacherID's classes.
+func (s *Store) Unassign(ctx context.Context, id primitive.ObjectID, teacherID string, assignmentID primitive.ObjectID) error {
+	if _, err := s.Taught(ctx, id, teacherID); err != nil {
+		return err
+	}
+	res, err := s.assignments.DeleteOne(ctx, bson.M{"_id": assignmentID, "class_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrAssignmentNotFound
+	}
+	return nil
+}
+
+// Reschedule moves the due date of an assignment in one of teacherID's classes. Students are
+// reminded again ahead of the new date.
+func (s *Store) Reschedule(ctx context.Context, id primitive.ObjectID, teacherID string, assignmentID primitive.ObjectID, dueAt time.Time) (models.ClassAssignment, error) {
+	var assignment models.ClassAssignment
+	if _, err := s.Taught(ctx, id, teacherID); err != nil {
+		return assignment, err
+	}
+	update := bson.M{"$set": bson.M{"due_at": dueAt}, "$unset": bson.M{"reminded_at": ""}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := s.assignments.FindOneAndUpdate(ctx, bson.M{"_id": assignmentID, "class_id": id}, update, opts).Decode(&assignment)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return assignment, ErrAssignmentNotFound
+	}
+	return assignment, err
+}
+
+// NormalizeJoinCode uppercases a join code as typed and drops surrounding space.
+func NormalizeJoinCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// students lists a class's students in the order they joined
+func (s *Store) students(ctx context.Context, classID primitive.ObjectID) ([]models.ClassMember, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "joined_at", Value: 1}})
+	return database.FindAll[models.ClassMember](ctx, s.members, bson.M{"class_id": classID}, opts)
+}
+
+// joinedIDs returns the IDs of the classes userID joined
+func (s *Store) joinedIDs(ctx context.Context, userID string) ([]primitive.ObjectID, error) {
+	memberships, err := database.FindAll[models.ClassMember](ctx, s.members, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, len(memberships))
+	for i, membership := range memberships {
+		ids[i] = membership.ClassID
+	}
+	return ids, nil
+}
+
+// removeMember deletes the membership matching filter and frees its seat in class id
+func (s *Store) removeMember(ctx context.Context, id primitive.ObjectID, filter bson.M) error {
+	res, err := s.members.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrStudentNotFound
+	}
+	_, err = s.classes.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"student_count": -1}})
+	return err
+}
+
+// findOne decodes the class matching filter
+func (s *Store) findOne(ctx context.Context, filter bson.M) (models.Classroom, error) {
+	var class models.Classroom
+	err := s.classes.FindOne(ctx, filter).Decode(&class)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return class, ErrNotFound
+	}
+	return class, err
+}
+
+// newJoinCode returns a short random join code.
+func newJoinCode() string {
+	b := make([]byte, codeLength)
+	rand.Read(b)
+	for i := range b {
+		b[i] = codeAlphabet[int(b[i])%len(codeAlphabet)]
+	}
+	return string(b)
+}
@@ -0,0 +1,243 @@
+// FILE: services/quiz/internal/classrooms/progress.go
+// Class progress is aggregated from the students' answer totals and mastery levels, which the
+// quiz service keeps from quiz answers and SRS reviews.
+
+package classrooms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb_common "wise-owl/gen/proto/common/v1"
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/database"
+	"wise-owl/services/quiz/internal/mastery"
+	"wise-owl/services/quiz/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// lessonPageSize is the page size used to list a lesson's words, the most SearchVocabulary allows
+const lessonPageSize = 200
+
+// ErrContentUnavailable is returned when the words of an assigned lesson could not be listed
+var ErrContentUnavailable = errors.New("content service unavailable")
+
+// Progress is a class's progress, for its teacher.
+type Progress struct {
+	Students    []StudentProgress    `json:"students"`
+	Assignments []AssignmentProgress `json:"assignments"`
+}
+
+// StudentProgress sums up one student's practice across everything they study.
+type StudentProgress struct {
+	StudentID      primitive.ObjectID `json:"student_id"`
+	DisplayName    string             `json:"display_name"`
+	Answers        int64              `json:"answers"`
+	Accuracy       float64            `json:"accuracy"` // Share of answers that were correct, 0-1
+	WordsPracticed int                `json:"words_practiced"`
+	WordsMastered  int                `json:"words_mastered"`
+	ReviewsDue     int                `json:"reviews_due"`
+	LastActiveAt   *time.Time         `json:"last_active_at"` // Last answer or review; null before the first
+}
+
+// AssignmentProgress sums up the class's mastery of an assignment's words.
+type AssignmentProgress struct {
+	AssignmentID primitive.ObjectID `json:"assignment_id"`
+	Kind         string             `json:"kind"`
+	Title        string             `json:"title"`
+	Words        int                `json:"words"`
+	Started      int                `json:"started"`       // Students who practiced at least one of the words
+	Completed    int                `json:"completed"`     // Students who practiced every word since it was assigned
+	Mastered     int                `json:"mastered"`      // Students who mastered every word
+	AverageLevel float64            `json:"average_level"` // Mean level over every student and word, 0-5; unpracticed words count as 0
+}
+
+// Progress reports the progress of one of teacherID's classes.
+func (s *Store) Progress(ctx context.Context, id primitive.ObjectID, teacherID string, now time.Time) (Progress, error) {
+	if _, err := s.Taught(ctx, id, teacherID); err != nil {
+		return Progress{}, err
+	}
+	students, err := s.students(ctx, id)
+	if err != nil {
+		return Progress{}, err
+	}
+	assignments, err := s.Assignments(ctx, id)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	progress := Progress{
+		Students:    make([]StudentProgress, len(students)),
+		Assignments: make([]AssignmentProgress, len(assignments)),
+	}
+	userIDs := make([]string, len(students))
+	for i, student := range students {
+		userIDs[i] = student.UserID
+		progress.Students[i] = StudentProgress{StudentID: student.ID, DisplayName: student.DisplayName}
+	}
+	if len(students) > 0 {
+		if err := s.studentProgress(ctx, userIDs, now, progress.Students); err != nil {
+			return Progress{}, err
+		}
+	}
+
+	for i, assignment := range assignments {
+		progress.Assignments[i], err = s.assignmentProgress(ctx, assignment, userIDs)
+		if err != nil {
+			return Progress{}, err
+		}
+	}
+	return progress, nil
+}
+
+// studentProgress fills in the answer totals and mastery counts of the students of userIDs, in order
+func (s *Store) studentProgress(ctx context.Context, userIDs []string, now time.Time, out []StudentProgress) error {
+	totals, err := database.FindAll[models.AnswerStats](ctx, s.stats, bson.M{"user_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return err
+	}
+	byUser := make(map[string]models.AnswerStats, len(totals))
+	for _, total := range totals {
+		byUser[total.UserID] = total
+	}
+
+	type wordCounts struct {
+		UserID    string    `bson:"_id"`
+		Practiced int       `bson:"practiced"`
+		Mastered  int       `bson:"mastered"`
+		Due       int       `bson:"due"`
+		LastAt    time.Time `bson:"last_at"`
+	}
+	counts, err := database.AggregateAll[wordCounts](ctx, s.mastery, bson.A{
+		bson.M{"$match": bson.M{"user_id": bson.M{"$in": userIDs}}},
+		bson.M{"$group": bson.M{
+			"_id":       "$user_id",
+			"practiced": bson.M{"$sum": 1},
+			"mastered":  bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gte": bson.A{"$level", mastery.MaxLevel}}, 1, 0}}},
+			"due":       bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$lte": bson.A{"$due_at", now}}, 1, 0}}},
+			"last_at":   bson.M{"$max": "$updated_at"},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	wordsByUser := make(map[string]wordCounts, len(counts))
+	for _, c := range counts {
+		wordsByUser[c.UserID] = c
+	}
+
+	for i, userID := range userIDs {
+		var last time.Time
+		if total, ok := byUser[userID]; ok {
+			out[i].Answers = total.Total
+			if total.Total > 0 {
+				out[i].Accuracy = float64(total.Correct) / float64(total.Total)
+			}
+			last = total.LastAnsweredAt
+		}
+		if words, ok := wordsByUser[userID]; ok {
+			out[i].WordsPracticed = words.Practiced
+			out[i].WordsMastered = words.Mastered
+			out[i].ReviewsDue = words.Due
+			if words.LastAt.After(last) {
+				last = words.LastAt
+			}
+		}
+		if !last.IsZero() {
+			out[i].LastActiveAt = &last
+		}
+	}
+	return nil
+}
+
+// assignmentProgress aggregates the mastery of the students of userIDs over an assignment's words
+func (s *Store) assignmentProgress(ctx context.Context, assignment models.ClassAssignment, userIDs []string) (AssignmentProgress, error) {
+	progress := AssignmentProgress{AssignmentID: assignment.ID, Kind: assignment.Kind, Title: assignment.Title}
+	words, err := s.words(ctx, assignment)
+	if err != nil {
+		return progress, err
+	}
+	progress.Words = len(words)
+	if len(words) == 0 || len(userIDs) == 0 {
+		return progress, nil
+	}
+
+	type levelSums struct {
+		Levels   int `bson:"levels"`
+		Mastered int `bson:"mastered"`
+	}
+	sums, err := database.AggregateAll[levelSums](ctx, s.mastery, bson.A{
+		bson.M{"$match": bson.M{"user_id": bson.M{"$in": userIDs}, "vocabulary_id": bson.M{"$in": words}}},
+		bson.M{"$group": bson.M{
+			"_id":      "$user_id",
+			"levels":   bson.M{"$sum": "$level"},
+			"mastered": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gte": bson.A{"$level", mastery.MaxLevel}}, 1, 0}}},
+		}},
+	})
+	if err != nil {
+		return progress, err
+	}
+
+	var levels int
+	for _, sum := range sums {
+		progress.Started++
+		levels += sum.Levels
+		if sum.Mastered == len(words) {
+			progress.Mastered++
+		}
+	}
+	progress.AverageLevel = float64(levels) / float64(len(words)*len(userIDs))
+
+	practiced, err := s.practiced(ctx, words, userIDs, assignment.AssignedAt)
+	if err != nil {
+		return progress, err
+	}
+	for _, count := range practiced {
+		if count >= len(words) {
+			progress.Completed++
+		}
+	}
+	return progress, nil
+}
+
+// words returns the vocabulary IDs an assignment covers: the lesson's words, or the study set's
+// current list. A study set deleted since it was assigned covers none.
+func (s *Store) words(ctx context.Context, assignment models.ClassAssignment) ([]string, error) {
+	if assignment.Kind == models.AssignmentStudySet {
+		setID, err := primitive.ObjectIDFromHex(assignment.TargetID)
+		if err != nil {
+			return nil, nil
+		}
+		sets, err := database.FindAll[models.StudySet](ctx, s.sets, bson.M{"_id": setID})
+		if err != nil || len(sets) == 0 {
+			return nil, err
+		}
+		return sets[0].VocabularyIDs, nil
+	}
+
+	var ids []string
+	pageToken := ""
+	for {
+		grpcCtx, cancel := ctxutil.GRPC(ctx)
+		page, err := s.contentClient.SearchVocabulary(grpcCtx, &pb_content.SearchVocabularyRequest{
+			Filters:    &pb_content.VocabularyFilters{Lessons: []string{assignment.TargetID}},
+			Pagination: &pb_common.Pagination{PageSize: lessonPageSize, PageToken: pageToken},
+		})
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrContentUnavailable, err)
+		}
+		for _, item := range page.Items {
+			ids = append(ids, item.Id)
+		}
+		pageToken = page.GetPageInfo().GetNextPageToken()
+		if pageToken == "" {
+			return ids, nil
+		}
+	}
+}
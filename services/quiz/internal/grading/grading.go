@@ -0,0 +1,198 @@
+// FILE: services/quiz/internal/grading/grading.go
+// This package grades typed quiz answers against vocabulary, so clients no longer grade themselves.
+// Each question type has a rule choosing exact, typo-tolerant, or reading-aware matching.
+
+package grading
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/japanese"
+)
+
+// QuestionType says what the learner was asked for.
+type QuestionType string
+
+// Question types.
+const (
+	TypeMeaning QuestionType = "meaning" // Give the English meaning of a Japanese word
+	TypeBurmese QuestionType = "burmese" // Give the Burmese meaning of a Japanese word
+	TypeReading QuestionType = "reading" // Give the kana reading, in kana or romaji
+)
+
+// Mode is how an answer is compared with an expected answer.
+type Mode string
+
+// Matching modes.
+const (
+	ModeExact   Mode = "exact"   // Equal after normalization
+	ModeFuzzy   Mode = "fuzzy"   // Within an edit distance after normalization
+	ModeReading Mode = "reading" // Same reading in any script or romaji spelling, see japanese.ReadingKey
+)
+
+// Rule configures how one question type is graded.
+type Rule struct {
+	Mode Mode
+	// MaxDistance caps the typos forgiven in ModeFuzzy and ModeReading. Short answers get
+	// fewer: one typo per TypoEvery characters of the expected answer.
+	MaxDistance int
+	TypoEvery   int
+	// Separators split the expected answer into alternatives, any of which is accepted
+	// ("to eat; to have a meal"). Empty means the answer must match as a whole.
+	Separators string
+	// IgnoreSpaces compares answers with spaces removed, for scripts where word spacing varies.
+	IgnoreSpaces bool
+}
+
+// DefaultRules are the rules used for each question type.
+var DefaultRules = map[QuestionType]Rule{
+	TypeMeaning: {Mode: ModeFuzzy, MaxDistance: 2, TypoEvery: 5, Separators: ";,/、"},
+	TypeBurmese: {Mode: ModeExact, Separators: ";/၊", IgnoreSpaces: true},
+	TypeReading: {Mode: ModeReading},
+}
+
+// Result is the outcome of grading one answer.
+type Result struct {
+	Correct  bool   `json:"correct"`
+	Expected string `json:"expected"`          // The full expected answer, for showing to the learner
+	Matched  string `json:"matched,omitempty"` // The alternative the answer matched
+	Typos    int    `json:"typos"`             // Edits forgiven to accept the answer
+}
+
+// Grader grades answers with a rule per question type.
+type Grader struct {
+	rules map[QuestionType]Rule
+}
+
+// NewGrader creates a grader. Question types missing from rules use DefaultRules.
+func NewGrader(rules map[QuestionType]Rule) *Grader {
+	merged := make(map[QuestionType]Rule, len(DefaultRules))
+	for questionType, rule := range DefaultRules {
+		merged[questionType] = rule
+	}
+	for questionType, rule := range rules {
+		merged[questionType] = rule
+	}
+	return &Grader{rules: merged}
+}
+
+// Grade checks a typed answer to a question of the given type about vocab.
+func (g *Grader) Grade(questionType QuestionType, vocab *pb_content.Vocabulary, answer string) Result {
+	rule := g.rules[questionType]
+
+	var expected []string
+	switch questionType {
+	case TypeBurmese:
+		expected = []string{vocab.GetBurmese()}
+	case TypeReading:
+		// Writing the word in kanji is as good as giving its reading
+		expected = []string{vocab.GetKana(), vocab.GetKanji()}
+	default:
+		expected = []string{vocab.GetEnglish()}
+	}
+
+	result := Result{Expected: expected[0]}
+	for _, candidate := range alternatives(expected, rule.Separators) {
+		typos, ok := rule.match(candidate, answer)
+		if ok && (!result.Correct || typos < result.Typos) {
+			result.Correct, result.Matched, result.Typos = true, candidate, typos
+		}
+	}
+	return result
+}
+
+// match compares one expected alternative with the answer and returns the typos forgiven.
+func (r Rule) match(expected, answer string) (int, bool) {
+	var want, got string
+	switch r.Mode {
+	case ModeReading:
+		want, got = japanese.ReadingKey(expected), japanese.ReadingKey(answer)
+	default:
+		want, got = normalize(expected), normalize(answer)
+	}
+	if r.IgnoreSpaces {
+		want, got = strings.ReplaceAll(want, " ", ""), strings.ReplaceAll(got, " ", "")
+	}
+	if want == "" || got == "" {
+		return 0, false
+	}
+	if want == got {
+		return 0, true
+	}
+	if r.Mode == ModeExact {
+		return 0, false
+	}
+
+	allowed := r.MaxDistance
+	if r.TypoEvery > 0 {
+		allowed = min(allowed, len([]rune(want))/r.TypoEvery)
+	}
+	if allowed <= 0 {
+		return 0, false
+	}
+	distance := japanese.EditDistance(want, got)
+	return distance, distance <= allowed
+}
+
+var (
+	// asides are parenthesized notes in meanings, e.g. "Let's finish (the lesson)."
+	asides = regexp.MustCompile(`\([^)]*\)|\[[^\]]*\]|《[^》]*》`)
+	// spaceBeforePunct matches the gap an aside leaves before a full stop
+	spaceBeforePunct = regexp.MustCompile(`\s+([.,!?])`)
+	// fillers are words learners commonly add or leave out
+	fillers = map[string]bool{"a": true, "an": true, "the": true, "to": true}
+)
+
+// alternatives splits expected answers on the separators. Each alternative is also offered
+// without its asides, so "go (by vehicle)" accepts "go".
+func alternatives(expected []string, separators string) []string {
+	var out []string
+	add := func(s string) {
+		s = strings.Join(strings.Fields(s), " ")
+		if s = strings.TrimSpace(spaceBeforePunct.ReplaceAllString(s, "$1")); s != "" {
+			out = append(out, s)
+		}
+	}
+	for _, full := range expected {
+		if full == "" {
+			continue
+		}
+		add(full)
+		add(asides.ReplaceAllString(full, " "))
+		if separators == "" {
+			continue
+		}
+		// Split after removing asides so separators inside notes don't create alternatives
+		parts := strings.FieldsFunc(asides.ReplaceAllString(full, " "), func(r rune) bool {
+			return strings.ContainsRune(separators, r)
+		})
+		if len(parts) > 1 {
+			for _, part := range parts {
+				add(part)
+			}
+		}
+	}
+	return out
+}
+
+// normalize lowercases text, folds full-width characters, and drops punctuation, "~" placeholders, and filler words.
+func normalize(s string) string {
+	s = strings.ToLower(japanese.NormalizeWidth(s))
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '\'')
+	})
+
+	kept := words[:0]
+	for _, word := range words {
+		if !fillers[word] {
+			kept = append(kept, word)
+		}
+	}
+	if len(kept) == 0 {
+		kept = words // The answer is only fillers, e.g. "the"
+	}
+	return strings.Join(kept, " ")
+}
@@ -0,0 +1,120 @@
+// FILE: services/quiz/internal/grpc/server.go
+
+package grpc
+
+import (
+	"context"
+	"time"
+
+	pb "wise-owl/gen/proto/quiz/v1"
+	"wise-owl/services/quiz/internal/mastery"
+	"wise-owl/services/quiz/internal/stats"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxListLimit caps the words listed by GetDueReviews and GetRecentMisses
+const maxListLimit = 100
+
+// Server implements the gRPC QuizServiceServer interface.
+type Server struct {
+	pb.UnimplementedQuizServiceServer
+	stats   *stats.Store
+	mastery *mastery.Store
+}
+
+// NewServer creates a new gRPC server with its stats and mastery dependencies.
+func NewServer(store *stats.Store, masteryStore *mastery.Store) *Server {
+	return &Server{stats: store, mastery: masteryStore}
+}
+
+// GetUserQuizStats returns a user's answer totals and incorrect words count.
+func (s *Server) GetUserQuizStats(ctx context.Context, req *pb.GetUserQuizStatsRequest) (*pb.GetUserQuizStatsResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	answers, incorrectWords, err := s.stats.UserStats(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.GetUserQuizStatsResponse{
+		UserId:           req.GetUserId(),
+		TotalAnswers:     answers.Total,
+		CorrectAnswers:   answers.Correct,
+		IncorrectAnswers: answers.Incorrect,
+		IncorrectWords:   incorrectWords,
+	}
+	if !answers.LastAnsweredAt.IsZero() {
+		res.LastAnsweredAt = timestamppb.New(answers.LastAnsweredAt)
+	}
+	return res, nil
+}
+
+// GetIncorrectWordCounts returns miss counts keyed by vocabulary ID.
+func (s *Server) GetIncorrectWordCounts(ctx context.Context, req *pb.GetIncorrectWordCountsRequest) (*pb.GetIncorrectWordCountsResponse, error) {
+	if req.GetLimit() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	counts, err := s.stats.IncorrectWordCounts(ctx, req.GetUserId(), req.GetVocabularyIds(), int(req.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetIncorrectWordCountsResponse{Counts: counts}, nil
+}
+
+// GetMastery returns mastery levels keyed by vocabulary ID.
+func (s *Server) GetMastery(ctx context.Context, req *pb.GetMasteryRequest) (*pb.GetMasteryResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	levels, err := s.mastery.Levels(ctx, req.GetUserId(), req.GetVocabularyIds())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetMasteryResponse{Levels: levels}, nil
+}
+
+// GetDueReviews counts the words due for review and lists the most overdue ones.
+func (s *Server) GetDueReviews(ctx context.Context, req *pb.GetDueReviewsRequest) (*pb.GetDueReviewsResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.GetLimit() < 0 || req.GetLimit() > maxListLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 0 and %d", maxListLimit)
+	}
+
+	now := time.Now().UTC()
+	count, err := s.mastery.DueCount(ctx, req.GetUserId(), now)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.GetDueReviewsResponse{Count: count}
+	if req.GetLimit() > 0 && count > 0 {
+		if res.VocabularyIds, err = s.mastery.Due(ctx, req.GetUserId(), now, int(req.GetLimit())); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// GetRecentMisses lists the words the user missed most recently.
+func (s *Server) GetRecentMisses(ctx context.Context, req *pb.GetRecentMissesRequest) (*pb.GetRecentMissesResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.GetLimit() < 1 || req.GetLimit() > maxListLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", maxListLimit)
+	}
+
+	ids, err := s.stats.RecentMisses(ctx, req.GetUserId(), int(req.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetRecentMissesResponse{VocabularyIds: ids}, nil
+}
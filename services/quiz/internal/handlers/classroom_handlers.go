@@ -0,0 +1,476 @@
+// FILE: services/quiz/internal/handlers/classroom_handlers.go
+// This file contains the classroom endpoints: teachers run classes that students join with a
+// code, assign them lessons and study sets, and follow their progress.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/validation"
+	"wise-owl/services/quiz/internal/classrooms"
+	"wise-owl/services/quiz/internal/models"
+	"wise-owl/services/quiz/internal/studysets"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ClassroomHandler holds dependencies for the classroom handlers.
+type ClassroomHandler struct {
+	store         *classrooms.Store
+	sets          *studysets.Store
+	contentClient pb_content.ContentServiceClient // Checks assigned lessons exist
+}
+
+// NewClassroomHandler creates a new handler with its dependencies.
+func NewClassroomHandler(store *classrooms.Store, sets *studysets.Store, contentClient pb_content.ContentServiceClient) *ClassroomHandler {
+	return &ClassroomHandler{store: store, sets: sets, contentClient: contentClient}
+}
+
+// CreateClass starts a class taught by the caller. The response carries the code students join with.
+func (h *ClassroomHandler) CreateClass(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req struct {
+		Name string `json:"name" binding:"required,max=100"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.Error(apierror.BadRequest("invalid_request", "Name must not be blank."))
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	class, err := h.store.Create(ctx, userID.(string), name)
+	if errors.Is(err, classrooms.ErrLimit) {
+		c.Error(apierror.Conflict("class_limit_reached", "You have reached the maximum number of classes."))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, class)
+}
+
+// ListClasses lists the classes the caller teaches, newest first, then the classes they joined.
+func (h *ClassroomHandler) ListClasses(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	list, err := h.store.List(ctx, userID.(string))
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"classes": list})
+}
+
+// GetClass returns a class the caller teaches or joined.
+func (h *ClassroomHandler) GetClass(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, ok := objectIDParam(c, "classId")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	class, err := h.store.Get(ctx, id, userID.(string))
+	if err != nil {
+		respondClassError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, class)
+}
+
+// DeleteClass ends one of the caller's classes; its students and assignments go with it.
+func (h *ClassroomHandler) DeleteClass(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, ok := objectIDParam(c, "classId")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	if err := h.store.Delete(ctx, id, userID.(string)); err != nil {
+		respondClassError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResetJoinCode replaces the join code of one of the caller's classes, e.g. after it was shared too widely.
+func (h *ClassroomHandler) ResetJoinCode(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, ok := objectIDParam(c, "classId")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	class, err := h.store.ResetJoinCode(ctx, id, userID.(string))
+	if err != nil {
+		respondClassError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, class)
+}
+
+// JoinClass adds the caller to the class with the given join code. Joining a class again is a no-op.
+func (h *ClassroomHandler) JoinClass(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req struct {
+		Code        string `json:"code" binding:"required,max=20"`
+		DisplayName string `json:"display_name" binding:"required,max=50"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+	displayName := strings.TrimSpace(req.DisplayName)
+	if displayName == "" {
+		c.Error(apierror.BadRequest("invalid_request", "Display name must not be blank."))
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	class, err := h.store.Join(ctx, req.Code, userID.(string), displayName)
+	switch {
+	case errors.Is(err, classrooms.ErrNotFound):
+		c.Error(apierror.NotFound("invalid_join_code", "No class has this join code."))
+	case errors.Is(err, classrooms.ErrOwnClass):
+		c.Error(apierror.BadRequest("own_class", "You teach this class."))
+	case errors.Is(err, classrooms.ErrClassFull):
+		c.Error(apierror.Conflict("class_full", "This class is full."))
+	case errors.Is(err, classrooms.ErrLimit):
+		c.Error(apierror.Conflict("class_limit_reached", "You have reached the maximum number of classes."))
+	case err != nil:
+		c.Error(apierror.Internal("database_error", err))
+	default:
+		c.JSON(http.StatusOK, class)
+	}
+}
+
+// LeaveClass removes the caller from a class they joined.
+func (h *ClassroomHandler) LeaveClass(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, ok := objectIDParam(c, "classId")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	if err := h.store.Leave(ctx, id, userID.(string)); err != nil {
+		respondClassError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListStudents lists the students of one of the caller's classes in the order they joined.
+func (h *ClassroomHandler) ListStudents(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, ok := objectIDParam(c, "classId")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	students, err := h.store.Students(ctx, id, userID.(string))
+	if err != nil {
+		respondClassError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"students": students})
+}
+
+// RemoveStudent removes a student from one of the caller's classes.
+func (h *ClassroomHandler) RemoveStudent(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, ok := objectIDParam(c, "classId")
+	if !ok {
+		return
+	}
+	studentID, ok := objectIDParam(c, "studentId")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	if err := h.store.RemoveStudent(ctx, id, userID.(string), studentID); err != nil {
+		respondClassError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CreateAssignment assigns a lesson or a study set to one of the caller's classes, optionally due
+// by a date. Study sets must be public so that students can open them.
+func (h *ClassroomHandler) CreateAssignment(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, ok := objectIDParam(c, "classId")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Kind     string     `json:"kind" binding:"required,oneof=lesson study_set"`
+		TargetID string     `json:"target_id" binding:"required,max=100"`
+		DueAt    *time.Time `json:"due_at"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+	if req.DueAt != nil && !checkDueDate(c, *req.DueAt) {
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	class, err := h.store.Taught(ctx, id, userID.(string))
+	if err != nil {
+		respondClassError(c, err)
+		return
+	}
+
+	assignment := models.ClassAssignment{Kind: req.Kind, TargetID: req.TargetID, Title: req.TargetID}
+	if req.DueAt != nil {
+		dueAt := req.DueAt.UTC()
+		assignment.DueAt = &dueAt
+	}
+	if req.Kind == models.AssignmentLesson {
+		grpcCtx, cancel := ctxutil.GRPC(c)
+		res, err := h.contentClient.GetLessonBatch(grpcCtx, &pb_content.GetLessonBatchRequest{LessonIds: []string{req.TargetID}})
+		cancel()
+		if err != nil {
+			c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+			return
+		}
+		if _, ok := res.Lessons[req.TargetID]; !ok {
+			c.Error(apierror.NotFound("not_found", "Lesson not found."))
+			return
+		}
+	} else {
+		set, err := h.sets.Get(ctx, req.TargetID, userID.(string))
+		if errors.Is(err, studysets.ErrNotFound) {
+			c.Error(apierror.NotFound("not_found", "Study set not found."))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("database_error", err))
+			return
+		}
+		if !set.Public {
+			c.Error(apierror.BadRequest("study_set_private", "Make the study set public so that students can open it."))
+			return
+		}
+		assignment.Title = set.Name
+	}
+
+	if err := h.store.Assign(ctx, class, &assignment); err != nil {
+		if errors.Is(err, classrooms.ErrLimit) {
+			c.Error(apierror.Conflict("assignment_limit_reached", "This class has the maximum number of assignments."))
+			return
+		}
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, assignment)
+}
+
+// ListAssignments lists the assignments of a class the caller teaches or joined, newest first.
+func (h *ClassroomHandler) ListAssignments(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, ok := objectIDParam(c, "classId")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	if _, err := h.store.Get(ctx, id, userID.(string)); err != nil {
+		respondClassError(c, err)
+		return
+	}
+	assignments, err := h.store.Assignments(ctx, id)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"assignments": assignments})
+}
+
+// RescheduleAssignment moves the due date of an assignment in one of the caller's classes.
+func (h *ClassroomHandler) RescheduleAssignment(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, ok := objectIDParam(c, "classId")
+	if !ok {
+		return
+	}
+	assignmentID, ok := objectIDParam(c, "assignmentId")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		DueAt time.Time `json:"due_at" binding:"required"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+	if !checkDueDate(c, req.DueAt) {
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	assignment, err := h.store.Reschedule(ctx, id, userID.(string), assignmentID, req.DueAt.UTC())
+	if err != nil {
+		respondClassError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, assignment)
+}
+
+// DeleteAssignment removes an assignment from one of the caller's classes.
+func (h *ClassroomHandler) DeleteAssignment(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, ok := objectIDParam(c, "classId")
+	if !ok {
+		return
+	}
+	assignmentID, ok := objectIDParam(c, "assignmentId")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	if err := h.store.Unassign(ctx, id, userID.(string), assignmentID); err != nil {
+		respondClassError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetClassProgress reports each student's practice and the class's mastery of each assignment,
+// for the teacher of the class.
+func (h *ClassroomHandler) GetClassProgress(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, ok := objectIDParam(c, "classId")
+	if !ok {
+		return
+	}
+
+	progress, err := h.store.Progress(c, id, userID.(string), time.Now().UTC())
+	if errors.Is(err, classrooms.ErrContentUnavailable) {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+	if err != nil {
+		respondClassError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// ListMyAssignments lists the assignments of every class the caller joined with their completion
+// status: soonest due first, then those without a due date.
+func (h *ClassroomHandler) ListMyAssignments(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	assignments, err := h.store.ForStudent(c, userID.(string), time.Now().UTC())
+	if errors.Is(err, classrooms.ErrContentUnavailable) {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"assignments": assignments})
+}
+
+// checkDueDate reports a 400 unless dueAt is in the future.
+func checkDueDate(c *gin.Context, dueAt time.Time) bool {
+	if !dueAt.After(time.Now()) {
+		c.Error(apierror.BadRequest("invalid_due_date", "The due date must be in the future."))
+		return false
+	}
+	return true
+}
+
+// objectIDParam parses the ObjectID in the named path parameter, reporting a 400 when it is malformed.
+func objectIDParam(c *gin.Context, name string) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param(name))
+	if err != nil {
+		c.Error(apierror.BadRequest("invalid_id", "IDs must be 24-character hex ObjectIDs."))
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// respondClassError reports a classroom store error: a 404 for classes, students, and
+// assignments the caller cannot see, or a 500.
+func respondClassError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, classrooms.ErrNotFound):
+		c.Error(apierror.NotFound("not_found", "Class not found."))
+	case errors.Is(err, classrooms.ErrStudentNotFound):
+		c.Error(apierror.NotFound("not_found", "Student not found."))
+	case errors.Is(err, classrooms.ErrAssignmentNotFound):
+		c.Error(apierror.NotFound("not_found", "Assignment not found."))
+	default:
+		c.Error(apierror.Internal("database_error", err))
+	}
+}
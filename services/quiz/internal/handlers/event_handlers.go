@@ -0,0 +1,70 @@
+// FILE: services/quiz/internal/handlers/event_handlers.go
+// This file consumes the quiz answer and SRS review events that move mastery levels.
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"wise-owl/lib/database"
+	"wise-owl/lib/events"
+	"wise-owl/services/quiz/internal/mastery"
+	"wise-owl/services/quiz/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// EventHandler holds dependencies for the domain event handlers.
+type EventHandler struct {
+	processed database.CollectionInterface // Event IDs already applied
+	mastery   *mastery.Store
+}
+
+// NewEventHandler creates a new handler with its dependencies.
+func NewEventHandler(db *mongo.Database, mastery *mastery.Store) *EventHandler {
+	return &EventHandler{
+		processed: db.Collection("processed_events"),
+		mastery:   mastery,
+	}
+}
+
+// HandleEvent applies quiz answers and SRS reviews to the user's mastery of the word. Both carry
+// "vocabulary_id" and "correct"; a review is correct when the learner recalled the word.
+// Each event is applied once even if it is delivered again.
+func (h *EventHandler) HandleEvent(ctx context.Context, event events.Event) error {
+	var source mastery.Source
+	switch event.Type {
+	case events.TypeQuizAnswer:
+		source = mastery.SourceQuiz
+	case events.TypeSRSReview:
+		source = mastery.SourceSRS
+	default:
+		return nil
+	}
+	vocabularyID, _ := event.Data["vocabulary_id"].(string)
+	correct, ok := event.Data["correct"].(bool)
+	if event.UserID == "" || vocabularyID == "" || !ok {
+		return nil
+	}
+
+	marker := models.ProcessedEvent{EventID: event.ID, EventType: event.Type, ProcessedAt: time.Now().UTC()}
+	if err := database.InsertUnique(ctx, h.processed, marker); err != nil {
+		if errors.Is(err, database.ErrDuplicate) {
+			return nil
+		}
+		return err
+	}
+
+	if err := h.mastery.Record(ctx, event.UserID, vocabularyID, source, correct); err != nil {
+		// Forget the event so the publisher's retry can apply it
+		if _, delErr := h.processed.DeleteOne(ctx, bson.M{"_id": event.ID}); delErr != nil {
+			log.Printf("WARN: Failed to release event %s after error: %v", event.ID, delErr)
+		}
+		return err
+	}
+	return nil
+}
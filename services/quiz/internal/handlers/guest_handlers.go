@@ -0,0 +1,79 @@
+// FILE: services/quiz/internal/handlers/guest_handlers.go
+// This file starts anonymous guest sessions and moves their quiz data to an account after signup.
+
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/auth"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/validation"
+	"wise-owl/services/quiz/internal/stats"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GuestMergeGrace is how long after a guest token expires its data can still be merged into an account
+const GuestMergeGrace = 7 * 24 * time.Hour
+
+// GuestHandler holds dependencies for the guest session handlers.
+type GuestHandler struct {
+	guests *auth.GuestIssuer
+	stats  *stats.Store
+}
+
+// NewGuestHandler creates a new handler with its dependencies.
+func NewGuestHandler(guests *auth.GuestIssuer, stats *stats.Store) *GuestHandler {
+	return &GuestHandler{guests: guests, stats: stats}
+}
+
+// StartSession issues a guest token to an unauthenticated client. Answers recorded with it are kept
+// under the guest ID until the guest signs up and calls MergeSession.
+func (h *GuestHandler) StartSession(c *gin.Context) {
+	guest, err := h.guests.Issue()
+	if err != nil {
+		c.Error(apierror.Internal("guest_session_failed", err))
+		return
+	}
+	c.JSON(http.StatusCreated, guest)
+}
+
+// MergeSession moves a guest session's answer totals and incorrect words to the signed-in account.
+// The guest token proves the caller owned the session; it may have expired up to GuestMergeGrace ago.
+func (h *GuestHandler) MergeSession(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.Error(apierror.Unauthorized("authentication_required", "Sign in to keep your guest progress."))
+		return
+	}
+
+	var req struct {
+		GuestToken string `json:"guest_token" binding:"required"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	guestID, err := h.guests.GuestID(req.GuestToken, GuestMergeGrace)
+	if err != nil {
+		c.Error(apierror.BadRequest("invalid_guest_token", "The guest token is invalid or has expired."))
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	merged, err := h.stats.MergeUser(ctx, guestID, userID)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	log.Printf("Merged guest session %s into %s (%d incorrect words)", guestID, userID, merged)
+
+	c.JSON(http.StatusOK, gin.H{"incorrect_words_merged": merged})
+}
@@ -0,0 +1,123 @@
+// FILE: services/quiz/internal/handlers/pronunciation_handlers.go
+// This file scores spoken words. A scored attempt counts as a spaced repetition review of the word.
+
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/events"
+	"wise-owl/lib/stt"
+	"wise-owl/lib/validation"
+	"wise-owl/services/quiz/internal/pronunciation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PronunciationHandler holds dependencies for the pronunciation handlers.
+type PronunciationHandler struct {
+	recognizer    stt.Recognizer // nil when no speech recognition backend is configured
+	contentClient pb_content.ContentServiceClient
+	publisher     events.Publisher
+}
+
+// NewPronunciationHandler creates a new handler with its dependencies.
+func NewPronunciationHandler(recognizer stt.Recognizer, contentClient pb_content.ContentServiceClient, publisher events.Publisher) *PronunciationHandler {
+	return &PronunciationHandler{recognizer: recognizer, contentClient: contentClient, publisher: publisher}
+}
+
+// ScorePronunciation takes a recording of the caller saying a word, sent as the multipart form
+// field "audio" with the word's "vocabulary_id", and scores how closely what speech recognition
+// heard matches the word's reading. The grade is recorded as a spaced repetition review, so a
+// failed attempt brings the word back for practice sooner.
+func (h *PronunciationHandler) ScorePronunciation(c *gin.Context) {
+	if h.recognizer == nil {
+		c.Error(apierror.New(http.StatusServiceUnavailable, "pronunciation_disabled", "Pronunciation practice is not configured."))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, stt.MaxAudioBytes+multipartOverhead)
+	fileHeader, err := c.FormFile("audio")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.Error(apierror.New(http.StatusRequestEntityTooLarge, "audio_too_large", "Recordings can be at most 1 MB."))
+			return
+		}
+		c.Error(apierror.BadRequest("invalid_request", `Upload the recording as the multipart form field "audio".`))
+		return
+	}
+	var req struct {
+		VocabularyID string `form:"vocabulary_id" binding:"required,objectid"`
+		SampleRate   int    `form:"sample_rate" binding:"omitempty,min=8000,max=48000"`
+	}
+	if err := validation.BindForm(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.Error(apierror.Internal("upload_failed", err))
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.Error(apierror.Internal("upload_failed", err))
+		return
+	}
+
+	grpcCtx, cancel := ctxutil.GRPC(c)
+	defer cancel()
+	batch, err := h.contentClient.GetVocabularyBatch(grpcCtx, &pb_content.GetVocabularyBatchRequest{VocabularyIds: []string{req.VocabularyID}})
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+	vocab, ok := batch.Items[req.VocabularyID]
+	if !ok {
+		c.Error(apierror.NotFound("not_found", "Vocabulary not found."))
+		return
+	}
+
+	audio := stt.Audio{Data: data, ContentType: fileHeader.Header.Get("Content-Type"), SampleRate: req.SampleRate}
+	hints := []string{vocab.GetKana()}
+	if vocab.GetKanji() != "" {
+		hints = append(hints, vocab.GetKanji())
+	}
+	transcripts, err := h.recognizer.Transcribe(c, audio, hints)
+	if err != nil {
+		if errors.Is(err, stt.ErrUnsupportedFormat) {
+			c.Error(apierror.BadRequest("unsupported_audio", "Recordings must be WAV, FLAC, or Opus in Ogg or WebM."))
+			return
+		}
+		c.Error(apierror.ServiceUnavailable("speech_service_unavailable", "Speech recognition is unavailable.", err))
+		return
+	}
+
+	result := pronunciation.Score(vocab, transcripts)
+	// Only a recalled word counts as a review; failed attempts can be retried freely
+	if result.Passed() {
+		events.PublishAsync(h.publisher, events.New(events.TypeSRSReview, c.GetString("userID"), map[string]interface{}{
+			"vocabulary_id": req.VocabularyID,
+			"correct":       true,
+			"grade":         string(result.Grade),
+			"score":         result.Score,
+			"mode":          "pronunciation",
+		}))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"score":      result.Score,
+		"grade":      result.Grade,
+		"correct":    result.Passed(),
+		"transcript": result.Transcript,
+		"expected":   result.Expected,
+	})
+}
@@ -0,0 +1,84 @@
+// FILE: services/quiz/internal/handlers/question_handlers.go
+// This file serves multiple-choice questions for solo quizzes.
+
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/validation"
+	"wise-owl/services/quiz/internal/questions"
+	"wise-owl/services/quiz/internal/unlocks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultQuestionCount is used when the client does not ask for a specific number of questions.
+const defaultQuestionCount = 10
+
+// QuestionHandler holds dependencies for the question handlers.
+type QuestionHandler struct {
+	bank    *questions.Bank
+	unlocks *unlocks.Checker
+}
+
+// NewQuestionHandler creates a new handler with its dependencies.
+func NewQuestionHandler(bank *questions.Bank, unlocks *unlocks.Checker) *QuestionHandler {
+	return &QuestionHandler{bank: bank, unlocks: unlocks}
+}
+
+// BuildQuestions returns multiple-choice questions about the given vocabulary, answers included.
+// Listening questions are built for words with recorded audio and text questions for the rest.
+// Clients report each outcome through RecordAnswer. Words from lessons the caller has not unlocked are refused.
+func (h *QuestionHandler) BuildQuestions(c *gin.Context) {
+	var req struct {
+		VocabularyIDs []string `json:"vocabulary_ids" binding:"required,min=1,max=100,dive,objectid"`
+		Count         int      `json:"count" binding:"omitempty,min=1,max=50"`
+		Kind          string   `json:"kind" binding:"omitempty,oneof=text listening"`
+		Choices       string   `json:"choices" binding:"omitempty,oneof=meaning kana"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+	if req.Count == 0 {
+		req.Count = defaultQuestionCount
+	}
+	if !requireUnlocked(c, h.unlocks, req.VocabularyIDs) {
+		return
+	}
+
+	built, err := h.bank.Build(c, req.VocabularyIDs, req.Count, questions.Options{
+		Kind:    questions.Kind(req.Kind),
+		Choices: questions.Choice(req.Choices),
+	})
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+	if len(built) == 0 {
+		c.Error(apierror.BadRequest("invalid_request", "None of the vocabulary could be made into a question."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"questions": built})
+}
+
+// requireUnlocked reports whether the caller may be quizzed on the given words, and responds with 403
+// when some of them belong to lessons the caller has not unlocked. Unlocks pace learners rather than
+// protect anything, so the quiz goes ahead when they cannot be checked.
+func requireUnlocked(c *gin.Context, checker *unlocks.Checker, vocabularyIDs []string) bool {
+	locked, err := checker.Locked(c, c.GetString("userID"), vocabularyIDs)
+	if err != nil {
+		log.Printf("WARN: Could not check lesson unlocks: %v", err)
+		return true
+	}
+	if len(locked) > 0 {
+		c.Error(apierror.Forbidden("lesson_locked", "Complete the prerequisite lessons first.").
+			WithDetails(gin.H{"locked_lessons": locked}))
+		return false
+	}
+	return true
+}
@@ -8,58 +8,147 @@ import (
 	"net/http"
 	"time"
 
-	pb_content "wise-owl/gen/proto/content"
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/auth"
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/database"
+	"wise-owl/lib/events"
+	"wise-owl/lib/httpcache"
+	"wise-owl/lib/validation"
+	"wise-owl/services/quiz/internal/grading"
 	"wise-owl/services/quiz/internal/models"
+	"wise-owl/services/quiz/internal/stats"
+	"wise-owl/services/quiz/internal/vocabulary"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // QuizHandler holds dependencies for the quiz service handlers.
 type QuizHandler struct {
-	collection    *mongo.Collection
+	collection    database.CollectionInterface
+	tombstones    database.CollectionInterface    // Deletions reported to offline clients
 	contentClient pb_content.ContentServiceClient // gRPC client for the content service
+	publisher     events.Publisher                // Domain event publisher (e.g., for XP awards)
+	stats         *stats.Store                    // Per-user answer totals served over gRPC
+	grader        *grading.Grader                 // Grades typed answers
 }
 
 // NewQuizHandler creates a new handler with its dependencies.
-func NewQuizHandler(db *mongo.Database, contentClient pb_content.ContentServiceClient) *QuizHandler {
+func NewQuizHandler(db *mongo.Database, contentClient pb_content.ContentServiceClient, publisher events.Publisher, stats *stats.Store, grader *grading.Grader) *QuizHandler {
 	return &QuizHandler{
-		collection:    db.Collection("incorrect_words"),
+		collection:    database.NewUserScopedCollection(db.Collection("incorrect_words")),
+		tombstones:    db.Collection(changefeed.TombstoneCollection),
 		contentClient: contentClient,
+		publisher:     publisher,
+		stats:         stats,
+		grader:        grader,
 	}
 }
 
-// RecordIncorrectWord saves a record that a user answered a word incorrectly.
-func (h *QuizHandler) RecordIncorrectWord(c *gin.Context) {
+// RecordAnswer records the outcome of a single quiz question and publishes a quiz answer event.
+// Clients either send the typed answer, which is graded here and the result returned, or
+// report "correct" themselves. Incorrect answers are also added to the user's incorrect words list.
+// Guest sessions are recorded under their guest ID but publish no event.
+func (h *QuizHandler) RecordAnswer(c *gin.Context) {
 	userID, _ := c.Get("userID")
 
 	var req struct {
-		VocabularyID string `json:"vocabulary_id" binding:"required"`
+		VocabularyID string `json:"vocabulary_id" binding:"required,objectid"`
+		Correct      *bool  `json:"correct" binding:"required_without=Answer"`
+		Answer       string `json:"answer" binding:"required_without=Correct,max=200"`
+		QuestionType string `json:"question_type" binding:"omitempty,oneof=meaning burmese reading"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	var result *grading.Result
+	if req.Answer != "" {
+		graded, apiErr := h.grade(c, req.VocabularyID, grading.QuestionType(req.QuestionType), req.Answer)
+		if apiErr != nil {
+			c.Error(apiErr)
+			return
+		}
+		result = &graded
+		req.Correct = &graded.Correct
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	userIDStr, _ := userID.(string)
+	now := time.Now().UTC()
+	if !*req.Correct {
+		if err := h.stats.RecordMiss(ctx, userIDStr, req.VocabularyID, now); err != nil {
+			c.Error(apierror.Internal("database_error", err))
+			return
+		}
+	}
+
+	if err := h.stats.RecordAnswer(ctx, userIDStr, *req.Correct, now); err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	// Guests earn no XP, so nothing is published for them
+	if !auth.IsGuest(userIDStr) {
+		events.PublishAsync(h.publisher, events.New(events.TypeQuizAnswer, userIDStr, map[string]interface{}{
+			"vocabulary_id": req.VocabularyID,
+			"correct":       *req.Correct,
+			"graded":        result != nil, // Only answers graded here earn XP
+		}))
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+
+	if result != nil {
+		c.JSON(http.StatusCreated, result)
 		return
 	}
+	c.Status(http.StatusCreated)
+}
 
-	// Use an "upsert" operation to avoid creating duplicate entries.
-	// If a document with this user_id and vocabulary_id already exists, it does nothing.
-	// If it doesn't exist, it inserts a new one.
-	filter := bson.M{"user_id": userID, "vocabulary_id": req.VocabularyID}
-	update := bson.M{
-		"$setOnInsert": bson.M{
-			"_id":        primitive.NewObjectID(),
-			"created_at": time.Now().UTC(),
-		},
+// grade looks up the vocabulary item and grades a typed answer to it. Meaning questions are the default.
+func (h *QuizHandler) grade(ctx context.Context, vocabularyID string, questionType grading.QuestionType, answer string) (grading.Result, *apierror.Error) {
+	if questionType == "" {
+		questionType = grading.TypeMeaning
 	}
-	opts := options.Update().SetUpsert(true)
 
-	_, err := h.collection.UpdateOne(c, filter, update, opts)
+	ctx, cancel := ctxutil.GRPC(ctx)
+	defer cancel()
+
+	grpcRes, err := h.contentClient.GetVocabularyBatch(ctx, &pb_content.GetVocabularyBatchRequest{VocabularyIds: []string{vocabularyID}})
 	if err != nil {
-		log.Printf("Error recording incorrect word: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "database_error"})
+		return grading.Result{}, apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err)
+	}
+	vocab, ok := grpcRes.Items[vocabularyID]
+	if !ok {
+		return grading.Result{}, apierror.NotFound("not_found", "Vocabulary not found.")
+	}
+
+	return h.grader.Grade(questionType, vocab, answer), nil
+}
+
+// RecordIncorrectWord saves a record that a user answered a word incorrectly.
+func (h *QuizHandler) RecordIncorrectWord(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req struct {
+		VocabularyID string `json:"vocabulary_id" binding:"required,objectid"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	if err := h.stats.RecordMiss(ctx, userID.(string), req.VocabularyID, time.Now().UTC()); err != nil {
+		c.Error(apierror.Internal("database_error", err))
 		return
 	}
 
@@ -71,15 +160,18 @@ func (h *QuizHandler) GetIncorrectWords(c *gin.Context) {
 	userID, _ := c.Get("userID")
 
 	// 1. Find all incorrect word records for the user in our own database.
-	cursor, err := h.collection.Find(c, bson.M{"user_id": userID})
+	dbCtx, cancelDB := ctxutil.Database(c)
+	defer cancelDB()
+
+	cursor, err := h.collection.Find(dbCtx, bson.M{"user_id": userID})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "database_error"})
+		c.Error(apierror.Internal("database_error", err))
 		return
 	}
 
 	var incorrectWordRecords []models.IncorrectWord
-	if err = cursor.All(c, &incorrectWordRecords); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "deserialization_error"})
+	if err = cursor.All(dbCtx, &incorrectWordRecords); err != nil {
+		c.Error(apierror.Internal("deserialization_error", err))
 		return
 	}
 
@@ -94,29 +186,29 @@ func (h *QuizHandler) GetIncorrectWords(c *gin.Context) {
 		vocabIDs = append(vocabIDs, record.VocabularyID)
 	}
 
-	// 3. Make a single batch gRPC call to the content service.
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	grpcRes, err := h.contentClient.GetVocabularyBatch(ctx, &pb_content.GetVocabularyBatchRequest{VocabularyIds: vocabIDs})
+	// 3. Look them up in the content service, in parallel chunks for long lists.
+	items, err := vocabulary.Fetch(c, h.contentClient, vocabIDs)
 	if err != nil {
-		log.Printf("gRPC call to content service failed: %v", err)
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "content_service_unavailable"})
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
 		return
 	}
 
-	c.JSON(http.StatusOK, grpcRes.Items)
+	c.JSON(http.StatusOK, items)
 }
 
 // DeleteIncorrectWords performs a batch deletion of words from a user's incorrect list.
+// Words listed in "versions" are deleted only while they are still at that version, so a miss
+// recorded on another device since the client last synced is not cleared unseen; any such words
+// are kept and reported with a 412, after the rest are deleted.
 func (h *QuizHandler) DeleteIncorrectWords(c *gin.Context) {
 	userID, _ := c.Get("userID")
 
 	var req struct {
-		VocabularyIDs []string `json:"vocabulary_ids" binding:"required"`
+		VocabularyIDs []string         `json:"vocabulary_ids" binding:"required,dive,objectid"`
+		Versions      map[string]int64 `json:"versions"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -125,18 +217,58 @@ func (h *QuizHandler) DeleteIncorrectWords(c *gin.Context) {
 		return
 	}
 
-	// The filter will match documents for the current user WHERE the vocabulary_id
-	// is in the list provided in the request body.
-	filter := bson.M{
-		"user_id":       userID,
-		"vocabulary_id": bson.M{"$in": req.VocabularyIDs},
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	var unconditional, deleted, conflicts []string
+	for _, id := range req.VocabularyIDs {
+		version, ok := req.Versions[id]
+		if !ok {
+			unconditional = append(unconditional, id)
+			continue
+		}
+		key := bson.M{"user_id": userID, "vocabulary_id": id}
+		result, err := h.collection.DeleteOne(ctx, database.MatchVersion(bson.M{"user_id": userID, "vocabulary_id": id}, version))
+		if err != nil {
+			c.Error(apierror.Internal("delete_failed", err))
+			return
+		}
+		if result.DeletedCount == 0 {
+			remaining, err := h.collection.CountDocuments(ctx, key)
+			if err != nil {
+				c.Error(apierror.Internal("database_error", err))
+				return
+			}
+			if remaining > 0 {
+				conflicts = append(conflicts, id)
+				continue
+			}
+		}
+		deleted = append(deleted, id)
 	}
 
-	_, err := h.collection.DeleteMany(c, filter)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete_failed"})
-		return
+	if len(unconditional) > 0 {
+		// The filter will match documents for the current user WHERE the vocabulary_id
+		// is in the list provided in the request body.
+		filter := bson.M{
+			"user_id":       userID,
+			"vocabulary_id": bson.M{"$in": unconditional},
+		}
+		if _, err := h.collection.DeleteMany(ctx, filter); err != nil {
+			c.Error(apierror.Internal("delete_failed", err))
+			return
+		}
+		deleted = append(deleted, unconditional...)
+	}
+
+	// Offline clients learn of the removals from the sync feed
+	if err := changefeed.RecordDeletions(ctx, h.tombstones, userID.(string), "incorrect_words", deleted...); err != nil {
+		log.Printf("WARN: Failed to record incorrect word deletions for %v: %v", userID, err)
 	}
 
+	if len(conflicts) > 0 {
+		c.Error(httpcache.VersionConflict().WithDetails(gin.H{"conflicts": conflicts}))
+		return
+	}
 	c.Status(http.StatusNoContent)
 }
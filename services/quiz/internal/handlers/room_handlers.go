@@ -0,0 +1,312 @@
+// FILE: services/quiz/internal/handlers/room_handlers.go
+// This file contains the live quiz room endpoints and the server-sent event stream.
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/events"
+	"wise-owl/lib/validation"
+	"wise-owl/services/quiz/internal/live"
+	"wise-owl/services/quiz/internal/questions"
+	"wise-owl/services/quiz/internal/stats"
+	"wise-owl/services/quiz/internal/unlocks"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultRoomQuestions is used when the host does not ask for a specific number of questions.
+	defaultRoomQuestions = 10
+	// streamHeartbeat keeps idle event streams open through proxies.
+	streamHeartbeat = 15 * time.Second
+	// forwardedRoomHeader marks a request passed on to a room's instance, so it is not passed on again.
+	forwardedRoomHeader = "X-Live-Room-Forwarded"
+)
+
+// RoomHandler holds dependencies for the live quiz room handlers.
+type RoomHandler struct {
+	hub       *live.Hub
+	bank      *questions.Bank
+	publisher events.Publisher
+	stats     *stats.Store
+	unlocks   *unlocks.Checker
+}
+
+// NewRoomHandler creates a new handler with its dependencies.
+func NewRoomHandler(hub *live.Hub, bank *questions.Bank, publisher events.Publisher, stats *stats.Store, unlocks *unlocks.Checker) *RoomHandler {
+	return &RoomHandler{
+		hub:       hub,
+		bank:      bank,
+		publisher: publisher,
+		stats:     stats,
+		unlocks:   unlocks,
+	}
+}
+
+// CreateRoom builds questions from the given vocabulary and opens a room hosted by the caller.
+func (h *RoomHandler) CreateRoom(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDStr, _ := userID.(string)
+
+	var req struct {
+		VocabularyIDs []string `json:"vocabulary_ids" binding:"required,min=4,dive,objectid"`
+		QuestionCount int      `json:"question_count" binding:"omitempty,min=1,max=50"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+	if req.QuestionCount == 0 {
+		req.QuestionCount = defaultRoomQuestions
+	}
+	if !requireUnlocked(c, h.unlocks, req.VocabularyIDs) {
+		return
+	}
+
+	built, err := h.bank.Build(c, req.VocabularyIDs, req.QuestionCount, questions.Options{})
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+	if len(built) == 0 {
+		c.Error(apierror.BadRequest("invalid_request", "Not enough distinct vocabulary to build questions."))
+		return
+	}
+
+	roomQuestions := make([]live.Question, len(built))
+	for i, q := range built {
+		roomQuestions[i] = live.NewQuestion(q.VocabularyID, q.Prompt, q.Choices, q.Answer)
+	}
+	room, err := h.hub.Create(c, userIDStr, roomQuestions)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	c.JSON(http.StatusCreated, room.Snapshot())
+}
+
+// RouteToOwner passes a request for a room held by another instance on to that instance, event
+// streams included, and lets the rest through. It runs before authentication and metering, which
+// the owner does once.
+func (h *RoomHandler) RouteToOwner(c *gin.Context) {
+	roomID := c.Param("roomId")
+	if roomID == "" || c.GetHeader(forwardedRoomHeader) != "" {
+		c.Next()
+		return
+	}
+	owner, err := h.hub.Owner(c, roomID)
+	if err != nil {
+		apierror.Respond(c, apierror.Internal("database_error", err))
+		return
+	}
+	if owner == "" {
+		c.Next()
+		return
+	}
+	target, err := url.Parse(owner)
+	if err != nil {
+		apierror.Respond(c, apierror.Internal("invalid_room_owner", err))
+		return
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.FlushInterval = -1 // Flush every write so events are not held back
+	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		log.Printf("WARN: Failed to forward live room %s request to %s: %v", roomID, owner, err)
+		apierror.Respond(c, apierror.ServiceUnavailable("room_unavailable", "The room's server is unavailable.", err))
+	}
+	c.Request.Header.Set(forwardedRoomHeader, "1")
+	proxy.ServeHTTP(c.Writer, c.Request)
+	c.Abort()
+}
+
+// GetRoom returns the public state of a room.
+func (h *RoomHandler) GetRoom(c *gin.Context) {
+	room, ok := h.hub.Get(c.Param("roomId"))
+	if !ok {
+		c.Error(apierror.NotFound("not_found", "Room not found."))
+		return
+	}
+
+	c.JSON(http.StatusOK, room.Snapshot())
+}
+
+// JoinRoom adds the caller to a waiting room.
+func (h *RoomHandler) JoinRoom(c *gin.Context) {
+	h.withRoom(c, func(room *live.Room, userID string) error {
+		return room.Join(userID)
+	})
+}
+
+// LeaveRoom removes the caller from a room.
+func (h *RoomHandler) LeaveRoom(c *gin.Context) {
+	h.withRoom(c, func(room *live.Room, userID string) error {
+		return room.Leave(userID)
+	})
+}
+
+// StartRoom starts the race. Only the host can start a full room.
+func (h *RoomHandler) StartRoom(c *gin.Context) {
+	h.withRoom(c, func(room *live.Room, userID string) error {
+		return room.Start(userID)
+	})
+}
+
+// SubmitRoomAnswer scores the caller's answer to the current question.
+func (h *RoomHandler) SubmitRoomAnswer(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDStr, _ := userID.(string)
+
+	room, ok := h.hub.Get(c.Param("roomId"))
+	if !ok {
+		c.Error(apierror.NotFound("not_found", "Room not found."))
+		return
+	}
+
+	var req struct {
+		QuestionIndex *int `json:"question_index" binding:"required,min=0"`
+		Choice        *int `json:"choice" binding:"required,min=0"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := room.Answer(userIDStr, *req.QuestionIndex, *req.Choice)
+	if err != nil {
+		c.Error(roomError(err))
+		return
+	}
+
+	// The answer is already scored, so a stats failure must not fail the request
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+	if err := h.stats.RecordAnswer(ctx, userIDStr, result.Correct, time.Now().UTC()); err != nil {
+		log.Printf("WARN: Failed to record answer stats for %s: %v", userIDStr, err)
+	}
+
+	events.PublishAsync(h.publisher, events.New(events.TypeQuizAnswer, userIDStr, map[string]interface{}{
+		"vocabulary_id": result.VocabularyID,
+		"correct":       result.Correct,
+		"graded":        true,
+		"room_id":       room.ID,
+	}))
+
+	c.JSON(http.StatusOK, result)
+}
+
+// StreamRoom streams room events to the caller as server-sent events.
+// Clients that reconnect with a Last-Event-ID header receive the events they missed.
+func (h *RoomHandler) StreamRoom(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDStr, _ := userID.(string)
+
+	room, ok := h.hub.Get(c.Param("roomId"))
+	if !ok {
+		c.Error(apierror.NotFound("not_found", "Room not found."))
+		return
+	}
+
+	lastEventID, _ := strconv.ParseInt(c.GetHeader("Last-Event-ID"), 10, 64)
+	backlog, ch, err := room.Subscribe(userIDStr, lastEventID)
+	if err != nil {
+		c.Error(roomError(err))
+		return
+	}
+	defer room.Unsubscribe(ch)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable nginx response buffering
+
+	for _, event := range backlog {
+		writeRoomEvent(c.Writer, event)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			return true
+		case event, open := <-ch:
+			if !open {
+				// Dropped for falling behind or the room was closed; the client reconnects.
+				return false
+			}
+			writeRoomEvent(w, event)
+			return true
+		}
+	})
+}
+
+// withRoom runs a room action for the caller and responds with the resulting room state.
+func (h *RoomHandler) withRoom(c *gin.Context, action func(room *live.Room, userID string) error) {
+	userID, _ := c.Get("userID")
+	userIDStr, _ := userID.(string)
+
+	room, ok := h.hub.Get(c.Param("roomId"))
+	if !ok {
+		c.Error(apierror.NotFound("not_found", "Room not found."))
+		return
+	}
+
+	if err := action(room, userIDStr); err != nil {
+		c.Error(roomError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, room.Snapshot())
+}
+
+// roomError maps room errors to API errors.
+func roomError(err error) *apierror.Error {
+	switch err {
+	case live.ErrNotInRoom:
+		return apierror.Forbidden("not_in_room", "You are not a player in this room.")
+	case live.ErrNotHost:
+		return apierror.Forbidden("not_host", "Only the host can do this.")
+	case live.ErrRoomFull:
+		return apierror.Conflict("room_full", "The room is full.")
+	case live.ErrNotEnoughPlayers:
+		return apierror.Conflict("not_enough_players", "The room needs another player before it can start.")
+	case live.ErrWrongState:
+		return apierror.Conflict("invalid_room_state", "The room does not allow this right now.")
+	case live.ErrStaleQuestion:
+		return apierror.Conflict("question_closed", "This question is no longer open.")
+	case live.ErrAlreadyAnswered:
+		return apierror.Conflict("already_answered", "You already answered this question.")
+	case live.ErrInvalidChoice:
+		return apierror.BadRequest("invalid_choice", "The choice is out of range.")
+	default:
+		return apierror.Internal("internal_error", err)
+	}
+}
+
+// writeRoomEvent encodes a room event in the server-sent events wire format.
+func writeRoomEvent(w io.Writer, event live.Event) {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		log.Printf("Error encoding live room event %d: %v", event.ID, err)
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
@@ -0,0 +1,237 @@
+// FILE: services/quiz/internal/handlers/session_handlers.go
+// This file runs solo quizzes as sessions kept on the server, which grades each answer itself.
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/auth"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/events"
+	"wise-owl/lib/validation"
+	"wise-owl/services/quiz/internal/questions"
+	"wise-owl/services/quiz/internal/sessions"
+	"wise-owl/services/quiz/internal/stats"
+	"wise-owl/services/quiz/internal/unlocks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHandler holds dependencies for the quiz session handlers.
+type SessionHandler struct {
+	sessions  sessions.Store
+	bank      *questions.Bank
+	unlocks   *unlocks.Checker
+	stats     *stats.Store
+	publisher events.Publisher
+}
+
+// NewSessionHandler creates a new handler with its dependencies.
+func NewSessionHandler(store sessions.Store, bank *questions.Bank, unlocks *unlocks.Checker, stats *stats.Store, publisher events.Publisher) *SessionHandler {
+	return &SessionHandler{sessions: store, bank: bank, unlocks: unlocks, stats: stats, publisher: publisher}
+}
+
+// sessionQuestion is a session question as its player sees it; the answer is shown once answered.
+type sessionQuestion struct {
+	VocabularyID string         `json:"vocabulary_id"`
+	Kind         questions.Kind `json:"kind"`
+	Prompt       string         `json:"prompt,omitempty"`
+	AudioURL     string         `json:"audio_url,omitempty"`
+	Choices      []string       `json:"choices"`
+	Answer       *int           `json:"answer,omitempty"`
+}
+
+// sessionView is the response body describing a session.
+func sessionView(s *sessions.Session) gin.H {
+	answered := make(map[int]bool, len(s.Answers))
+	for _, given := range s.Answers {
+		answered[given.Question] = true
+	}
+	qs := make([]sessionQuestion, len(s.Questions))
+	for i, q := range s.Questions {
+		qs[i] = sessionQuestion{VocabularyID: q.VocabularyID, Kind: q.Kind, Prompt: q.Prompt, AudioURL: q.AudioURL, Choices: q.Choices}
+		if answered[i] {
+			qs[i].Answer = &s.Questions[i].Answer
+		}
+	}
+	answers := s.Answers
+	if answers == nil {
+		answers = []sessions.Answer{}
+	}
+	return gin.H{
+		"id":         s.ID,
+		"questions":  qs,
+		"answers":    answers,
+		"score":      s.Score(),
+		"finished":   s.Finished(),
+		"created_at": s.CreatedAt,
+		"expires_at": s.ExpiresAt,
+	}
+}
+
+// StartSession builds multiple-choice questions about the given vocabulary, as BuildQuestions
+// does, and keeps them in a new session. The correct choices are not sent; each is revealed once
+// the question is answered through AnswerSession. Sessions expire an hour after their last answer
+// by default (SESSION_TTL).
+func (h *SessionHandler) StartSession(c *gin.Context) {
+	var req struct {
+		VocabularyIDs []string `json:"vocabulary_ids" binding:"required,min=1,max=100,dive,objectid"`
+		Count         int      `json:"count" binding:"omitempty,min=1,max=50"`
+		Kind          string   `json:"kind" binding:"omitempty,oneof=text listening"`
+		Choices       string   `json:"choices" binding:"omitempty,oneof=meaning kana"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+	if req.Count == 0 {
+		req.Count = defaultQuestionCount
+	}
+	if !requireUnlocked(c, h.unlocks, req.VocabularyIDs) {
+		return
+	}
+
+	built, err := h.bank.Build(c, req.VocabularyIDs, req.Count, questions.Options{
+		Kind:    questions.Kind(req.Kind),
+		Choices: questions.Choice(req.Choices),
+	})
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+	if len(built) == 0 {
+		c.Error(apierror.BadRequest("invalid_request", "None of the vocabulary could be made into a question."))
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+	session := sessions.New(c.GetString("userID"), built, time.Now().UTC())
+	if err := h.sessions.Create(ctx, session); err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, sessionView(session))
+}
+
+// GetSession returns one of the caller's sessions with the answers given so far.
+func (h *SessionHandler) GetSession(c *gin.Context) {
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	session, apiErr := h.load(ctx, c)
+	if apiErr != nil {
+		c.Error(apiErr)
+		return
+	}
+	c.JSON(http.StatusOK, sessionView(session))
+}
+
+// AnswerSession grades the caller's choice for one question of a session and records it as
+// RecordAnswer records answers: in the caller's totals, in their incorrect words when wrong, and
+// as a quiz answer event (except for guests). Each question can be answered once.
+func (h *SessionHandler) AnswerSession(c *gin.Context) {
+	var req struct {
+		QuestionIndex *int `json:"question_index" binding:"required,min=0"`
+		Choice        *int `json:"choice" binding:"required,min=0"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	session, apiErr := h.load(ctx, c)
+	if apiErr != nil {
+		c.Error(apiErr)
+		return
+	}
+	now := time.Now().UTC()
+	answer, err := session.Answer(*req.QuestionIndex, *req.Choice, now)
+	switch {
+	case errors.Is(err, sessions.ErrInvalidQuestion):
+		c.Error(apierror.BadRequest("invalid_choice", "The question or choice is out of range."))
+		return
+	case errors.Is(err, sessions.ErrAlreadyAnswered):
+		c.Error(apierror.Conflict("already_answered", "You already answered this question."))
+		return
+	}
+	if err := h.sessions.Save(ctx, session); err != nil {
+		switch {
+		case errors.Is(err, sessions.ErrConflict):
+			c.Error(apierror.Conflict("session_changed", "The quiz session changed while answering. Try again."))
+		case errors.Is(err, sessions.ErrNotFound):
+			c.Error(apierror.NotFound("not_found", "Quiz session not found."))
+		default:
+			c.Error(apierror.Internal("database_error", err))
+		}
+		return
+	}
+
+	// The answer is already kept, so a stats failure must not fail the request
+	userID := session.UserID
+	question := session.Questions[answer.Question]
+	if !answer.Correct {
+		if err := h.stats.RecordMiss(ctx, userID, question.VocabularyID, now); err != nil {
+			log.Printf("WARN: Failed to record miss for %s: %v", userID, err)
+		}
+	}
+	if err := h.stats.RecordAnswer(ctx, userID, answer.Correct, now); err != nil {
+		log.Printf("WARN: Failed to record answer stats for %s: %v", userID, err)
+	}
+	if !auth.IsGuest(userID) {
+		events.PublishAsync(h.publisher, events.New(events.TypeQuizAnswer, userID, map[string]interface{}{
+			"vocabulary_id": question.VocabularyID,
+			"correct":       answer.Correct,
+			"graded":        true,
+		}))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"question_index": answer.Question,
+		"vocabulary_id":  question.VocabularyID,
+		"correct":        answer.Correct,
+		"answer":         question.Answer,
+		"score":          session.Score(),
+		"finished":       session.Finished(),
+	})
+}
+
+// EndSession discards one of the caller's sessions. Answers already given stay recorded.
+func (h *SessionHandler) EndSession(c *gin.Context) {
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	session, apiErr := h.load(ctx, c)
+	if apiErr != nil {
+		c.Error(apiErr)
+		return
+	}
+	if err := h.sessions.Delete(ctx, session.ID); err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// load returns the session named in the path if it belongs to the caller. Other users' sessions
+// are reported as missing, like expired ones.
+func (h *SessionHandler) load(ctx context.Context, c *gin.Context) (*sessions.Session, *apierror.Error) {
+	session, err := h.sessions.Get(ctx, c.Param("sessionId"))
+	if errors.Is(err, sessions.ErrNotFound) || (err == nil && session.UserID != c.GetString("userID")) {
+		return nil, apierror.NotFound("not_found", "Quiz session not found.")
+	}
+	if err != nil {
+		return nil, apierror.Internal("database_error", err)
+	}
+	return session, nil
+}
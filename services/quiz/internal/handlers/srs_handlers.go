@@ -0,0 +1,257 @@
+// FILE: services/quiz/internal/handlers/srs_handlers.go
+// This file moves spaced repetition progress between Wise Owl and other apps.
+
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/storage"
+	"wise-owl/lib/validation"
+	"wise-owl/services/quiz/internal/anki"
+	"wise-owl/services/quiz/internal/mastery"
+	"wise-owl/services/quiz/internal/vocabulary"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// multipartOverhead leaves room for form boundaries and headers around the deck itself
+	multipartOverhead = 64 << 10
+	// maxUnmatchedShown caps the unmatched cards listed back to the caller
+	maxUnmatchedShown = 50
+	// exportLinkTTL is how long the download link of an exported deck works
+	exportLinkTTL = time.Hour
+)
+
+// SRSHandler holds dependencies for the spaced repetition handlers.
+type SRSHandler struct {
+	mastery       *mastery.Store
+	contentClient pb_content.ContentServiceClient
+	store         storage.BlobStore // Holds exported decks until they are downloaded
+}
+
+// NewSRSHandler creates a new handler with its dependencies.
+func NewSRSHandler(masteryStore *mastery.Store, contentClient pb_content.ContentServiceClient, store storage.BlobStore) *SRSHandler {
+	return &SRSHandler{mastery: masteryStore, contentClient: contentClient, store: store}
+}
+
+// ImportDeck takes an Anki deck sent as the multipart form field "deck" (.apkg, .colpkg, .csv,
+// .tsv, or .txt) and carries the review state of each card it can match to a Wise Owl word into
+// the caller's mastery levels and review schedule. Cards never studied are skipped, and words the
+// caller already knows better in Wise Owl are left as they are.
+func (h *SRSHandler) ImportDeck(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, anki.MaxUploadBytes+multipartOverhead)
+	fileHeader, err := c.FormFile("deck")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.Error(apierror.New(http.StatusRequestEntityTooLarge, "deck_too_large", "Decks can be at most 100 MB."))
+			return
+		}
+		c.Error(apierror.BadRequest("invalid_request", `Upload the deck as the multipart form field "deck".`))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.Error(apierror.Internal("upload_failed", err))
+		return
+	}
+	defer file.Close()
+
+	var cards []anki.Card
+	switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+	case ".apkg", ".colpkg":
+		cards, err = anki.ReadPackage(c, file, fileHeader.Size)
+	case ".csv", ".tsv", ".txt":
+		cards, err = anki.ReadCSV(file)
+	default:
+		c.Error(apierror.BadRequest("unsupported_format", "Decks must be .apkg, .colpkg, .csv, .tsv, or .txt files."))
+		return
+	}
+	switch {
+	case errors.Is(err, anki.ErrUnsupported):
+		c.Error(apierror.BadRequest("unsupported_deck", `Export the deck from Anki with "Support older Anki versions" checked.`))
+		return
+	case errors.Is(err, anki.ErrInvalid):
+		c.Error(apierror.BadRequest("invalid_deck", "The file is not a readable Anki deck.").Wrap(err))
+		return
+	case err != nil:
+		c.Error(apierror.Internal("import_failed", err))
+		return
+	}
+
+	matcher, err := anki.LoadMatcher(c, h.contentClient)
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+
+	// Cards of the same word, such as its recognition and recall cards, are seeded by the weakest
+	now := time.Now().UTC()
+	seeds := make(map[string]mastery.Seed)
+	var fresh, ambiguous, unmatched int
+	unmatchedWords := []string{}
+	for _, card := range cards {
+		if card.New {
+			fresh++
+			continue
+		}
+		vocabID, tied := matcher.Match(card)
+		if vocabID == "" {
+			if tied {
+				ambiguous++
+				continue
+			}
+			unmatched++
+			if len(unmatchedWords) < maxUnmatchedShown && len(card.Fields) > 0 {
+				unmatchedWords = append(unmatchedWords, card.Fields[0])
+			}
+			continue
+		}
+
+		seed := mastery.Seed{
+			VocabularyID: vocabID,
+			Level:        mastery.ImportedLevel(card.Interval, card.Ease),
+			DueAt:        card.Due,
+			Hits:         max(card.Reviews-card.Lapses, 0),
+			Misses:       card.Lapses,
+		}
+		if seed.DueAt.IsZero() {
+			seed.DueAt = now.Add(card.Interval)
+		}
+		if existing, ok := seeds[vocabID]; ok && existing.Level <= seed.Level {
+			continue
+		}
+		seeds[vocabID] = seed
+	}
+
+	list := make([]mastery.Seed, 0, len(seeds))
+	for _, seed := range seeds {
+		list = append(list, seed)
+	}
+	applied, err := h.mastery.Import(c, c.GetString("userID"), list)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cards":     len(cards),
+		"words":     len(seeds), // Distinct words matched
+		"imported":  applied,
+		"new":       fresh,
+		"ambiguous": ambiguous,
+		"unmatched": unmatched,
+		// The first cards that named no Wise Owl word, by their first field
+		"unmatched_words": unmatchedWords,
+	})
+}
+
+// exportFormats maps each export format to its file name and content type
+var exportFormats = map[string]struct{ fileName, contentType string }{
+	"apkg": {"wise-owl.apkg", "application/octet-stream"},
+	"csv":  {"wise-owl.csv", "text/csv; charset=utf-8"},
+}
+
+// ExportDeck builds an Anki deck of every word the caller has practiced, scheduled as in Wise
+// Owl: each word's review interval follows its mastery level and it falls due when its next
+// review does. ?format=apkg (the default) gives a package to import into Anki, csv a text file.
+// The deck is stored and the response carries a download link that expires after exportLinkTTL.
+func (h *SRSHandler) ExportDeck(c *gin.Context) {
+	var query struct {
+		Format string `form:"format" binding:"omitempty,oneof=apkg csv"`
+	}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+	if query.Format == "" {
+		query.Format = "apkg"
+	}
+	format := exportFormats[query.Format]
+
+	items, err := h.mastery.Since(c, c.GetString("userID"), time.Time{})
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	if len(items) == 0 {
+		c.Error(apierror.NotFound("nothing_to_export", "Practice some words before exporting a deck."))
+		return
+	}
+
+	vocabIDs := make([]string, len(items))
+	for i, item := range items {
+		vocabIDs[i] = item.VocabularyID
+	}
+	words, err := vocabulary.Fetch(c, h.contentClient, vocabIDs)
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+
+	// Words since removed from the vocabulary are left out
+	cards := make([]anki.Card, 0, len(items))
+	for _, item := range items {
+		word, ok := words[item.VocabularyID]
+		if !ok {
+			continue
+		}
+		expression := word.Kana
+		if kanji := word.GetKanji(); kanji != "" {
+			expression = kanji
+		}
+		cards = append(cards, anki.Card{
+			Fields:   []string{expression, word.Kana, word.Romaji, word.English, word.Burmese, word.Id},
+			Interval: mastery.ReviewInterval(item.Level),
+			Due:      item.DueAt,
+			Reviews:  item.Hits + item.Misses,
+			Lapses:   item.Misses,
+		})
+	}
+
+	var deck bytes.Buffer
+	if query.Format == "csv" {
+		err = anki.WriteCSV(&deck, cards)
+	} else {
+		err = anki.WritePackage(c, &deck, cards, time.Now().UTC())
+	}
+	if err != nil {
+		c.Error(apierror.Internal("export_failed", err))
+		return
+	}
+
+	// A random key keeps one user's export from being found from another's
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		c.Error(apierror.Internal("export_failed", err))
+		return
+	}
+	key := "exports/srs/" + hex.EncodeToString(token) + "/" + format.fileName
+	if err := h.store.Put(c, key, &deck, int64(deck.Len()), format.contentType); err != nil {
+		c.Error(apierror.Internal("upload_failed", err))
+		return
+	}
+	url, err := h.store.PresignURL(c, key, exportLinkTTL)
+	if err != nil {
+		c.Error(apierror.Internal("upload_failed", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"url":        url,
+		"expires_at": time.Now().UTC().Add(exportLinkTTL),
+		"format":     query.Format,
+		"cards":      len(cards),
+	})
+}
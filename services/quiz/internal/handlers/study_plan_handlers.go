@@ -0,0 +1,43 @@
+// FILE: services/quiz/internal/handlers/study_plan_handlers.go
+// This file serves the daily study plan.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/services/quiz/internal/studyplan"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StudyPlanHandler holds dependencies for the study plan handlers.
+type StudyPlanHandler struct {
+	planner *studyplan.Planner
+}
+
+// NewStudyPlanHandler creates a new handler with its dependencies.
+func NewStudyPlanHandler(planner *studyplan.Planner) *StudyPlanHandler {
+	return &StudyPlanHandler{planner: planner}
+}
+
+// GetStudyPlan returns the caller's work for today: due reviews, new words from the next
+// incomplete lesson, and a review quiz of recent misses. The plan is composed on each request,
+// so it shrinks as the caller works through it.
+func (h *StudyPlanHandler) GetStudyPlan(c *gin.Context) {
+	plan, err := h.planner.Build(c, c.GetString("userID"), time.Now().UTC())
+	if err != nil {
+		var serviceErr *studyplan.Error
+		if errors.As(err, &serviceErr) {
+			c.Error(apierror.ServiceUnavailable(serviceErr.Service+"_service_unavailable", "The "+serviceErr.Service+" service is unavailable.", err))
+			return
+		}
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
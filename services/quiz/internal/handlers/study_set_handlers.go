@@ -0,0 +1,325 @@
+// FILE: services/quiz/internal/handlers/study_set_handlers.go
+// This file contains the study set endpoints: word lists users build themselves, share through
+// a public slug, and quiz themselves on.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/validation"
+	"wise-owl/services/quiz/internal/models"
+	"wise-owl/services/quiz/internal/questions"
+	"wise-owl/services/quiz/internal/studysets"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StudySetHandler holds dependencies for the study set handlers.
+type StudySetHandler struct {
+	store *studysets.Store
+	bank  *questions.Bank
+}
+
+// NewStudySetHandler creates a new handler with its dependencies.
+func NewStudySetHandler(store *studysets.Store, bank *questions.Bank) *StudySetHandler {
+	return &StudySetHandler{store: store, bank: bank}
+}
+
+// CreateStudySet saves a new study set for the caller. Repeated words are kept once, in their first position.
+func (h *StudySetHandler) CreateStudySet(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req struct {
+		Name          string   `json:"name" binding:"required,max=100"`
+		Description   string   `json:"description" binding:"max=500"`
+		VocabularyIDs []string `json:"vocabulary_ids" binding:"max=500,dive,objectid"`
+		Public        bool     `json:"public"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.Error(apierror.BadRequest("invalid_request", "Name must not be blank."))
+		return
+	}
+
+	set := models.StudySet{
+		OwnerID:       userID.(string),
+		Name:          name,
+		Description:   strings.TrimSpace(req.Description),
+		VocabularyIDs: uniqueIDs(req.VocabularyIDs),
+		Public:        req.Public,
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	if err := h.store.Create(ctx, &set); err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, set)
+}
+
+// ListStudySets lists the caller's study sets, most recently updated first.
+func (h *StudySetHandler) ListStudySets(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	sets, err := h.store.List(ctx, userID.(string))
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"study_sets": sets})
+}
+
+// GetStudySet returns one of the caller's study sets, or any public one.
+func (h *StudySetHandler) GetStudySet(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	set, err := h.store.Get(ctx, c.Param("setId"), userID.(string))
+	if err != nil {
+		c.Error(studySetError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, set)
+}
+
+// GetSharedStudySet returns a public study set by its slug. It needs no account, so links can be shared anywhere.
+func (h *StudySetHandler) GetSharedStudySet(c *gin.Context) {
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	set, err := h.store.GetShared(ctx, c.Param("slug"))
+	if err != nil {
+		c.Error(studySetError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, set)
+}
+
+// SearchPublicStudySets lists public study sets, most popular first unless ?sort=recent.
+// With ?q= it matches a case-insensitive part of the name or description. It needs no account.
+func (h *StudySetHandler) SearchPublicStudySets(c *gin.Context) {
+	query := struct {
+		Q      string `form:"q" binding:"omitempty,max=100"`
+		Sort   string `form:"sort" binding:"omitempty,oneof=popular recent"`
+		Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+		Offset int    `form:"offset" binding:"omitempty,min=0"`
+	}{Limit: 20}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	sets, total, err := h.store.SearchPublic(ctx, studysets.Search{
+		Query:  strings.TrimSpace(query.Q),
+		Sort:   query.Sort,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	})
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"study_sets": sets, "total": total, "limit": query.Limit, "offset": query.Offset})
+}
+
+// CloneStudySet copies a public study set, or one of the caller's own, into a new private set of the caller's.
+func (h *StudySetHandler) CloneStudySet(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	clone, err := h.store.Clone(ctx, c.Param("setId"), userID.(string))
+	if err != nil {
+		c.Error(studySetError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, clone)
+}
+
+// LikeStudySet adds the caller's like to a public study set and returns the set with its new count.
+func (h *StudySetHandler) LikeStudySet(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	set, err := h.store.Like(ctx, c.Param("setId"), userID.(string))
+	if err != nil {
+		c.Error(studySetError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, set)
+}
+
+// UnlikeStudySet removes the caller's like from a public study set and returns the set with its new count.
+func (h *StudySetHandler) UnlikeStudySet(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	set, err := h.store.Unlike(ctx, c.Param("setId"), userID.(string))
+	if err != nil {
+		c.Error(studySetError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, set)
+}
+
+// UpdateStudySet changes the fields given in the body of one of the caller's study sets.
+// A vocabulary_ids list replaces the set's words, in the new order.
+func (h *StudySetHandler) UpdateStudySet(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req struct {
+		Name          *string  `json:"name" binding:"omitempty,max=100"`
+		Description   *string  `json:"description" binding:"omitempty,max=500"`
+		VocabularyIDs []string `json:"vocabulary_ids" binding:"omitempty,max=500,dive,objectid"`
+		Public        *bool    `json:"public"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	update := studysets.Update{Public: req.Public}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			c.Error(apierror.BadRequest("invalid_request", "Name must not be blank."))
+			return
+		}
+		update.Name = &name
+	}
+	if req.Description != nil {
+		description := strings.TrimSpace(*req.Description)
+		update.Description = &description
+	}
+	if req.VocabularyIDs != nil {
+		update.VocabularyIDs = uniqueIDs(req.VocabularyIDs)
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	set, err := h.store.Update(ctx, c.Param("setId"), userID.(string), update)
+	if err != nil {
+		c.Error(studySetError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, set)
+}
+
+// DeleteStudySet deletes one of the caller's study sets.
+func (h *StudySetHandler) DeleteStudySet(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	if err := h.store.Delete(ctx, c.Param("setId"), userID.(string)); err != nil {
+		c.Error(studySetError(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// BuildStudySetQuiz returns multiple-choice questions about the words of one of the caller's
+// study sets, or of a public one. The body is optional and takes the options of BuildQuestions.
+func (h *StudySetHandler) BuildStudySetQuiz(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req struct {
+		Count   int    `json:"count" binding:"omitempty,min=1,max=50"`
+		Kind    string `json:"kind" binding:"omitempty,oneof=text listening"`
+		Choices string `json:"choices" binding:"omitempty,oneof=meaning kana"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := validation.BindJSON(c, &req); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if req.Count == 0 {
+		req.Count = defaultQuestionCount
+	}
+
+	dbCtx, cancelDB := ctxutil.Database(c)
+	defer cancelDB()
+
+	set, err := h.store.Get(dbCtx, c.Param("setId"), userID.(string))
+	if err != nil {
+		c.Error(studySetError(err))
+		return
+	}
+	if len(set.VocabularyIDs) == 0 {
+		c.Error(apierror.BadRequest("empty_study_set", "Add words to the study set before starting a quiz."))
+		return
+	}
+
+	built, err := h.bank.Build(c, set.VocabularyIDs, req.Count, questions.Options{
+		Kind:    questions.Kind(req.Kind),
+		Choices: questions.Choice(req.Choices),
+	})
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+	if len(built) == 0 {
+		c.Error(apierror.BadRequest("invalid_request", "None of the vocabulary could be made into a question."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"study_set_id": set.ID, "questions": built})
+}
+
+// studySetError maps a store error to an API error
+func studySetError(err error) *apierror.Error {
+	if errors.Is(err, studysets.ErrNotFound) {
+		return apierror.NotFound("not_found", "Study set not found.")
+	}
+	return apierror.Internal("database_error", err)
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence of each
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
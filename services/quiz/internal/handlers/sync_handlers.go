@@ -0,0 +1,96 @@
+// FILE: services/quiz/internal/handlers/sync_handlers.go
+// This file serves offline sync: change feed pulls and batched uploads of offline answers.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/validation"
+	"wise-owl/services/quiz/internal/offline"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SyncHandler holds dependencies for the sync handlers.
+type SyncHandler struct {
+	syncer *offline.Syncer
+}
+
+// NewSyncHandler creates a new handler with its dependencies.
+func NewSyncHandler(syncer *offline.Syncer) *SyncHandler {
+	return &SyncHandler{syncer: syncer}
+}
+
+// GetChanges returns what changed for the caller since ?since=, the cursor of their last pull,
+// or everything when it is absent. Clients apply the changes, drop the deletions, and keep the
+// new cursor. A cursor too old to resume from is answered with 410, after which the client
+// pulls again without one.
+func (h *SyncHandler) GetChanges(c *gin.Context) {
+	var query struct {
+		Since string `form:"since" binding:"omitempty,max=64"`
+	}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pull, err := h.syncer.Pull(c, c.GetString("userID"), query.Since, changefeed.Now())
+	switch {
+	case err == nil:
+	case errors.Is(err, changefeed.ErrInvalidCursor):
+		c.Error(apierror.BadRequest("invalid_sync_cursor", "The sync cursor is not valid."))
+		return
+	case errors.Is(err, changefeed.ErrExpiredCursor):
+		c.Error(apierror.New(http.StatusGone, "sync_cursor_expired", "The sync cursor has expired. Sync again from the start."))
+		return
+	case errors.Is(err, offline.ErrUsersUnavailable):
+		c.Error(apierror.ServiceUnavailable("users_service_unavailable", "The users service is unavailable.", err))
+		return
+	default:
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, pull)
+}
+
+// PushAnswers applies quiz answers the caller recorded offline and reports what became of each.
+// Uploading the same client_id again is harmless, so clients retry a failed upload as it was.
+// Up to 500 answers are taken at a time; clients with more upload them in several batches.
+func (h *SyncHandler) PushAnswers(c *gin.Context) {
+	var req struct {
+		Answers []struct {
+			ClientID     string    `json:"client_id" binding:"required,max=64"`
+			VocabularyID string    `json:"vocabulary_id" binding:"required,objectid"`
+			Correct      *bool     `json:"correct" binding:"required"`
+			AnsweredAt   time.Time `json:"answered_at" binding:"required"`
+		} `json:"answers" binding:"required,min=1,max=500,dive"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	answers := make([]offline.Answer, len(req.Answers))
+	for i, answer := range req.Answers {
+		answers[i] = offline.Answer{
+			ClientID:     answer.ClientID,
+			VocabularyID: answer.VocabularyID,
+			Correct:      *answer.Correct,
+			AnsweredAt:   answer.AnsweredAt.UTC(),
+		}
+	}
+
+	results, err := h.syncer.Push(c, c.GetString("userID"), answers, time.Now().UTC())
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
@@ -0,0 +1,131 @@
+// FILE: services/quiz/internal/live/hub.go
+// The hub owns every live room on this instance and cleans up abandoned ones. With a registry,
+// room IDs are unique across instances and each room's instance can be looked up.
+
+package live
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"wise-owl/lib/database"
+)
+
+// roomIdleTTL is how long a room with no connected players is kept before it is removed.
+const roomIdleTTL = 10 * time.Minute
+
+// maxClaimAttempts bounds how many room IDs Create tries when other instances hold them
+const maxClaimAttempts = 5
+
+// Hub tracks live rooms by ID.
+type Hub struct {
+	mu       sync.RWMutex
+	rooms    map[string]*Room
+	registry *Registry // nil when this is the only instance
+}
+
+// NewHub creates an empty hub. registry may be nil when a single instance serves every room.
+func NewHub(registry *Registry) *Hub {
+	return &Hub{rooms: make(map[string]*Room), registry: registry}
+}
+
+// Create opens a new waiting room hosted by hostID.
+func (h *Hub) Create(ctx context.Context, hostID string, questions []Question) (*Room, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for attempt := 0; attempt < maxClaimAttempts; attempt++ {
+		id := newRoomID()
+		if h.rooms[id] != nil {
+			continue
+		}
+		if h.registry != nil {
+			err := h.registry.claim(ctx, id)
+			if errors.Is(err, database.ErrDuplicate) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("registering live room: %v", err)
+			}
+		}
+		room := newRoom(id, hostID, questions)
+		h.rooms[id] = room
+		return room, nil
+	}
+	return nil, fmt.Errorf("no free live room ID after %d attempts", maxClaimAttempts)
+}
+
+// Get returns the room with the given ID. IDs are case-insensitive so they can be typed by hand.
+func (h *Hub) Get(id string) (*Room, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	room, ok := h.rooms[strings.ToUpper(id)]
+	return room, ok
+}
+
+// Owner returns the address of the instance holding a room this instance does not, or "" when
+// no other instance is known to hold it.
+func (h *Hub) Owner(ctx context.Context, id string) (string, error) {
+	if h.registry == nil {
+		return "", nil
+	}
+	if _, ok := h.Get(id); ok {
+		return "", nil
+	}
+	return h.registry.owner(ctx, strings.ToUpper(id))
+}
+
+// Run removes idle rooms every minute until ctx is cancelled.
+func (h *Hub) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.sweep(time.Now().UTC())
+		}
+	}
+}
+
+// sweep removes rooms nobody is connected to that have not changed for roomIdleTTL.
+func (h *Hub) sweep(now time.Time) {
+	h.mu.Lock()
+	var removed []string
+	for id, room := range h.rooms {
+		updatedAt, connected := room.idleSince()
+		if connected || now.Sub(updatedAt) < roomIdleTTL {
+			continue
+		}
+		room.close()
+		delete(h.rooms, id)
+		removed = append(removed, id)
+		log.Printf("Removed idle live quiz room %s", id)
+	}
+	h.mu.Unlock()
+
+	if h.registry == nil {
+		return
+	}
+	for _, id := range removed {
+		if err := h.registry.release(context.Background(), id); err != nil {
+			log.Printf("WARN: Failed to release live quiz room %s: %v", id, err)
+		}
+	}
+}
+
+// newRoomID returns a short, shareable room code.
+func newRoomID() string {
+	b := make([]byte, 3)
+	rand.Read(b)
+	return strings.ToUpper(hex.EncodeToString(b))
+}
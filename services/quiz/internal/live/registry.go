@@ -0,0 +1,74 @@
+// FILE: services/quiz/internal/live/registry.go
+// Rooms are held in the memory of the instance that created them. The registry records in MongoDB
+// which instance that is, so a request for a room that reaches another instance can be passed on.
+
+package live
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"wise-owl/lib/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RegistryCollection maps room IDs to the instance holding them.
+const RegistryCollection = "live_rooms"
+
+// registryTTL outlives any room; rooms swept by the hub are released before it runs out
+const registryTTL = 24 * time.Hour
+
+// RegistryIndex removes the entries of rooms whose instance stopped without releasing them.
+var RegistryIndex = database.Index{
+	Collection:  RegistryCollection,
+	Keys:        bson.D{{Key: "expires_at", Value: 1}},
+	ExpireAfter: time.Second,
+}
+
+// Registry records which instance holds each room.
+type Registry struct {
+	collection *mongo.Collection
+	self       string
+}
+
+// registration is one room's entry.
+type registration struct {
+	ID        string    `bson:"_id"`
+	Owner     string    `bson:"owner"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+// NewRegistry creates a registry for the instance that other instances reach at self, such as
+// http://10.0.1.23:8083.
+func NewRegistry(db *mongo.Database, self string) *Registry {
+	return &Registry{collection: db.Collection(RegistryCollection), self: self}
+}
+
+// claim registers a new room as held by this instance, or returns database.ErrDuplicate when
+// another instance already holds a room with that ID.
+func (r *Registry) claim(ctx context.Context, id string) error {
+	return database.InsertUnique(ctx, r.collection, registration{
+		ID:        id,
+		Owner:     r.self,
+		ExpiresAt: time.Now().UTC().Add(registryTTL),
+	})
+}
+
+// release removes a room this instance held.
+func (r *Registry) release(ctx context.Context, id string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "owner": r.self})
+	return err
+}
+
+// owner returns the address of the instance holding a room, or "" when no other instance does.
+func (r *Registry) owner(ctx context.Context, id string) (string, error) {
+	var entry registration
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "expires_at": bson.M{"$gt": time.Now().UTC()}}).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) || entry.Owner == r.self {
+		return "", nil
+	}
+	return entry.Owner, err
+}
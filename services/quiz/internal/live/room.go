@@ -0,0 +1,472 @@
+// FILE: services/quiz/internal/live/room.go
+// This package runs realtime quiz rooms where players race to answer the same questions.
+// Rooms live in the memory of the instance that created them; the server owns the question order, the timer, and the scores.
+
+package live
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Room states.
+const (
+	StateWaiting    = "waiting"
+	StateInProgress = "in_progress"
+	StateFinished   = "finished"
+)
+
+// Event types sent to room subscribers.
+const (
+	EventSnapshot           = "snapshot"
+	EventPlayerJoined       = "player_joined"
+	EventPlayerLeft         = "player_left"
+	EventPlayerDisconnected = "player_disconnected"
+	EventPlayerReconnected  = "player_reconnected"
+	EventQuestion           = "question"
+	EventAnswer             = "answer"
+	EventQuestionClosed     = "question_closed"
+	EventFinished           = "finished"
+)
+
+const (
+	// MaxPlayers is the number of players that race in a room.
+	MaxPlayers = 2
+	// PointsFirstCorrect is awarded to the first player to answer a question correctly.
+	PointsFirstCorrect = 10
+	// QuestionTimeout is how long players have to answer before the question closes unanswered.
+	QuestionTimeout = 20 * time.Second
+
+	// eventLogSize bounds how many past events are kept for reconnecting clients.
+	eventLogSize = 256
+	// subscriberBuffer is the per-connection event buffer; slow connections are dropped.
+	subscriberBuffer = 32
+)
+
+// Room errors returned to handlers.
+var (
+	ErrRoomFull         = errors.New("room is full")
+	ErrNotInRoom        = errors.New("player is not in the room")
+	ErrNotHost          = errors.New("only the host can do this")
+	ErrNotEnoughPlayers = errors.New("not enough players to start")
+	ErrWrongState       = errors.New("room is not in the right state")
+	ErrStaleQuestion    = errors.New("question is no longer open")
+	ErrAlreadyAnswered  = errors.New("player already answered this question")
+	ErrInvalidChoice    = errors.New("choice is out of range")
+)
+
+// Question is a multiple-choice question. The answer is never sent to clients.
+type Question struct {
+	VocabularyID string   `json:"vocabulary_id"`
+	Prompt       string   `json:"prompt"`
+	Choices      []string `json:"choices"`
+	answer       int
+}
+
+// NewQuestion creates a question whose correct choice is at index answer.
+func NewQuestion(vocabularyID, prompt string, choices []string, answer int) Question {
+	return Question{VocabularyID: vocabularyID, Prompt: prompt, Choices: choices, answer: answer}
+}
+
+// Player is a participant in a room.
+type Player struct {
+	UserID    string    `json:"user_id"`
+	Score     int       `json:"score"`
+	Connected bool      `json:"connected"`
+	JoinedAt  time.Time `json:"joined_at"`
+	LastSeen  time.Time `json:"last_seen"`
+
+	connections int
+}
+
+// Event is a single message on a room's stream. IDs increase monotonically per room.
+type Event struct {
+	ID   int64       `json:"id"`
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// Snapshot is the public view of a room.
+type Snapshot struct {
+	ID            string     `json:"id"`
+	HostID        string     `json:"host_id"`
+	State         string     `json:"state"`
+	Players       []Player   `json:"players"`
+	QuestionCount int        `json:"question_count"`
+	QuestionIndex int        `json:"question_index"`
+	Question      *Question  `json:"question,omitempty"`
+	Deadline      *time.Time `json:"deadline,omitempty"`
+	LastEventID   int64      `json:"last_event_id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// AnswerResult is returned to the player who submitted an answer.
+type AnswerResult struct {
+	VocabularyID  string `json:"vocabulary_id"`
+	Correct       bool   `json:"correct"`
+	Points        int    `json:"points"`
+	Score         int    `json:"score"`
+	CorrectChoice *int   `json:"correct_choice,omitempty"` // Revealed once the question closes
+}
+
+// Room holds the state of a single live quiz.
+type Room struct {
+	ID     string
+	HostID string
+
+	mu          sync.Mutex
+	state       string
+	players     []*Player
+	questions   []Question
+	current     int
+	answered    map[string]bool
+	deadline    time.Time
+	timer       *time.Timer
+	log         []Event
+	nextEventID int64
+	subscribers map[chan Event]string
+	createdAt   time.Time
+	updatedAt   time.Time
+}
+
+func newRoom(id, hostID string, questions []Question) *Room {
+	now := time.Now().UTC()
+	room := &Room{
+		ID:          id,
+		HostID:      hostID,
+		state:       StateWaiting,
+		questions:   questions,
+		current:     -1,
+		subscribers: make(map[chan Event]string),
+		createdAt:   now,
+		updatedAt:   now,
+	}
+	room.players = append(room.players, &Player{UserID: hostID, JoinedAt: now, LastSeen: now})
+	return room
+}
+
+// Snapshot returns the public view of the room.
+func (r *Room) Snapshot() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshotLocked()
+}
+
+// Join adds a player to a waiting room. Rejoining is a no-op.
+func (r *Room) Join(userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.player(userID) != nil {
+		return nil
+	}
+	if r.state != StateWaiting {
+		return ErrWrongState
+	}
+	if len(r.players) >= MaxPlayers {
+		return ErrRoomFull
+	}
+
+	now := time.Now().UTC()
+	r.players = append(r.players, &Player{UserID: userID, JoinedAt: now, LastSeen: now})
+	r.emitLocked(EventPlayerJoined, payload{"user_id": userID})
+	return nil
+}
+
+// Leave removes a player. A running game ends when fewer than two players remain.
+func (r *Room) Leave(userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, player := range r.players {
+		if player.UserID != userID {
+			continue
+		}
+		r.players = append(r.players[:i], r.players[i+1:]...)
+		r.emitLocked(EventPlayerLeft, payload{"user_id": userID})
+		if r.state == StateInProgress && len(r.players) < MaxPlayers {
+			r.finishLocked()
+		}
+		return nil
+	}
+	return ErrNotInRoom
+}
+
+// Start begins the game. Only the host can start it, and only once the room is full.
+func (r *Room) Start(userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if userID != r.HostID {
+		return ErrNotHost
+	}
+	if r.state != StateWaiting {
+		return ErrWrongState
+	}
+	if len(r.players) < MaxPlayers {
+		return ErrNotEnoughPlayers
+	}
+
+	r.state = StateInProgress
+	r.nextQuestionLocked()
+	return nil
+}
+
+// Answer scores a player's choice for the question at index.
+// The first correct answer wins the points and closes the question for everyone.
+func (r *Room) Answer(userID string, index, choice int) (AnswerResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	player := r.player(userID)
+	if player == nil {
+		return AnswerResult{}, ErrNotInRoom
+	}
+	if r.state != StateInProgress {
+		return AnswerResult{}, ErrWrongState
+	}
+	if index != r.current {
+		return AnswerResult{}, ErrStaleQuestion
+	}
+	if r.answered[userID] {
+		return AnswerResult{}, ErrAlreadyAnswered
+	}
+	question := r.questions[r.current]
+	if choice < 0 || choice >= len(question.Choices) {
+		return AnswerResult{}, ErrInvalidChoice
+	}
+
+	r.answered[userID] = true
+	player.LastSeen = time.Now().UTC()
+	result := AnswerResult{VocabularyID: question.VocabularyID, Correct: choice == question.answer}
+	if result.Correct {
+		result.Points = PointsFirstCorrect
+		player.Score += PointsFirstCorrect
+	}
+	result.Score = player.Score
+
+	r.emitLocked(EventAnswer, payload{
+		"user_id":        userID,
+		"question_index": index,
+		"correct":        result.Correct,
+		"score":          player.Score,
+	})
+
+	// Close the question once someone is right or nobody is left to answer.
+	if result.Correct || len(r.answered) >= len(r.players) {
+		answer := question.answer
+		result.CorrectChoice = &answer
+		winner := ""
+		if result.Correct {
+			winner = userID
+		}
+		r.closeQuestionLocked(winner)
+	}
+	return result, nil
+}
+
+// Subscribe registers a connection for a player and returns the events it missed and a live channel.
+// Events after lastEventID are replayed when still in the log; otherwise a snapshot is sent first.
+func (r *Room) Subscribe(userID string, lastEventID int64) ([]Event, chan Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	player := r.player(userID)
+	if player == nil {
+		return nil, nil, ErrNotInRoom
+	}
+
+	var backlog []Event
+	if lastEventID > 0 && len(r.log) > 0 && r.log[0].ID <= lastEventID+1 {
+		for _, event := range r.log {
+			if event.ID > lastEventID {
+				backlog = append(backlog, event)
+			}
+		}
+	} else {
+		backlog = append(backlog, Event{ID: r.nextEventID, Type: EventSnapshot, Data: r.snapshotLocked()})
+	}
+
+	ch := make(chan Event, subscriberBuffer)
+	r.subscribers[ch] = userID
+	player.connections++
+	player.LastSeen = time.Now().UTC()
+	if !player.Connected {
+		player.Connected = true
+		if lastEventID > 0 {
+			r.emitLocked(EventPlayerReconnected, payload{"user_id": userID})
+		}
+	}
+	return backlog, ch, nil
+}
+
+// Unsubscribe removes a connection. A player with no open connections is shown as disconnected
+// but keeps their seat and score so they can reconnect.
+func (r *Room) Unsubscribe(ch chan Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	userID, ok := r.subscribers[ch]
+	if !ok {
+		return
+	}
+	delete(r.subscribers, ch)
+	close(ch)
+
+	if player := r.player(userID); player != nil {
+		player.connections--
+		player.LastSeen = time.Now().UTC()
+		if player.connections <= 0 {
+			player.connections = 0
+			player.Connected = false
+			r.emitLocked(EventPlayerDisconnected, payload{"user_id": userID})
+		}
+	}
+}
+
+// idleSince reports when the room last changed and whether anyone is connected.
+func (r *Room) idleSince() (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.updatedAt, len(r.subscribers) > 0
+}
+
+// close stops the room's timer and disconnects every subscriber.
+func (r *Room) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	for ch := range r.subscribers {
+		delete(r.subscribers, ch)
+		close(ch)
+	}
+}
+
+// nextQuestionLocked opens the next question or finishes the game after the last one.
+func (r *Room) nextQuestionLocked() {
+	r.current++
+	if r.current >= len(r.questions) {
+		r.finishLocked()
+		return
+	}
+
+	r.answered = make(map[string]bool)
+	r.deadline = time.Now().UTC().Add(QuestionTimeout)
+	index := r.current
+	r.timer = time.AfterFunc(QuestionTimeout, func() { r.expire(index) })
+
+	r.emitLocked(EventQuestion, payload{
+		"question_index": index,
+		"question":       r.questions[index],
+		"deadline":       r.deadline,
+	})
+}
+
+// expire closes a question nobody answered correctly in time.
+func (r *Room) expire(index int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state != StateInProgress || r.current != index {
+		return
+	}
+	r.closeQuestionLocked("")
+}
+
+// closeQuestionLocked reveals the answer and moves on. winner is empty when nobody scored.
+func (r *Room) closeQuestionLocked(winner string) {
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	r.emitLocked(EventQuestionClosed, payload{
+		"question_index": r.current,
+		"correct_choice": r.questions[r.current].answer,
+		"winner":         winner,
+		"scores":         r.scoresLocked(),
+	})
+	r.nextQuestionLocked()
+}
+
+// finishLocked ends the game and announces the final scores.
+func (r *Room) finishLocked() {
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	r.state = StateFinished
+	r.deadline = time.Time{}
+	r.emitLocked(EventFinished, payload{"scores": r.scoresLocked()})
+}
+
+// emitLocked appends an event to the log and fans it out to every subscriber.
+// Subscribers that cannot keep up are disconnected; they recover by reconnecting.
+func (r *Room) emitLocked(eventType string, data interface{}) {
+	r.nextEventID++
+	event := Event{ID: r.nextEventID, Type: eventType, Data: data}
+	r.updatedAt = time.Now().UTC()
+
+	r.log = append(r.log, event)
+	if len(r.log) > eventLogSize {
+		r.log = r.log[len(r.log)-eventLogSize:]
+	}
+
+	for ch, userID := range r.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(r.subscribers, ch)
+			close(ch)
+			if player := r.player(userID); player != nil && player.connections > 0 {
+				player.connections--
+			}
+		}
+	}
+}
+
+func (r *Room) snapshotLocked() Snapshot {
+	snapshot := Snapshot{
+		ID:            r.ID,
+		HostID:        r.HostID,
+		State:         r.state,
+		Players:       make([]Player, 0, len(r.players)),
+		QuestionCount: len(r.questions),
+		QuestionIndex: r.current,
+		LastEventID:   r.nextEventID,
+		CreatedAt:     r.createdAt,
+		UpdatedAt:     r.updatedAt,
+	}
+	for _, player := range r.players {
+		snapshot.Players = append(snapshot.Players, *player)
+	}
+	if r.state == StateInProgress && r.current >= 0 && r.current < len(r.questions) {
+		question := r.questions[r.current]
+		deadline := r.deadline
+		snapshot.Question = &question
+		snapshot.Deadline = &deadline
+	}
+	return snapshot
+}
+
+func (r *Room) scoresLocked() map[string]int {
+	scores := make(map[string]int, len(r.players))
+	for _, player := range r.players {
+		scores[player.UserID] = player.Score
+	}
+	return scores
+}
+
+func (r *Room) player(userID string) *Player {
+	for _, player := range r.players {
+		if player.UserID == userID {
+			return player
+		}
+	}
+	return nil
+}
+
+// payload is the data of an event.
+type payload map[string]interface{}
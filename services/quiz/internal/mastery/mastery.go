@@ -0,0 +1,238 @@
+// FILE: services/quiz/internal/mastery/mastery.go
+// This package keeps a per-user mastery level for each vocabulary item, fed by quiz answers and
+// SRS reviews. Levels run from 0 (new) to 5 (mastered):
+//
+//   - A correct quiz answer raises the level by one, but multiple-choice recognition alone
+//     reaches at most RecognitionCap; a higher level is never lowered by it.
+//   - A passed SRS review, which asks the learner to recall the word, raises the level by one, up to MaxLevel.
+//   - A wrong quiz answer or a failed review lowers the level by two, down to 0.
+//
+// Losing more than a correct answer gains means a word has to be answered right twice to
+// recover from a miss, so a lucky guess does not hide a weak word.
+//
+// Each outcome also schedules the word's next review, further out the higher its level
+// (see reviewIntervals), which is what the daily study plan draws due reviews from.
+
+package mastery
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/database"
+	"wise-owl/services/quiz/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// MaxLevel is the level of a mastered word
+	MaxLevel = 5
+	// RecognitionCap is the highest level quiz answers alone can reach
+	RecognitionCap = 4
+	// missPenalty is how many levels a wrong answer or failed review costs
+	missPenalty = 2
+)
+
+// Source is the kind of practice an outcome comes from.
+type Source string
+
+// Sources of mastery updates.
+const (
+	SourceQuiz Source = "quiz" // Multiple-choice or typed quiz answers
+	SourceSRS  Source = "srs"  // Spaced repetition reviews
+)
+
+// reviewIntervals is how long after an outcome a word at each level is due for review
+var reviewIntervals = [MaxLevel + 1]time.Duration{
+	0:        0, // Missed or barely seen: review straight away
+	1:        24 * time.Hour,
+	2:        3 * 24 * time.Hour,
+	3:        7 * 24 * time.Hour,
+	4:        14 * 24 * time.Hour,
+	MaxLevel: 30 * 24 * time.Hour,
+}
+
+// maxUpdateAttempts bounds the retries when concurrent outcomes for the same word race
+const maxUpdateAttempts = 5
+
+// ErrConflict is returned when a level kept changing underneath an update
+var ErrConflict = errors.New("mastery level changed concurrently")
+
+// Next returns the level after an outcome, following the rules in the package comment.
+func Next(level int, source Source, correct bool) int {
+	if !correct {
+		return max(level-missPenalty, 0)
+	}
+	limit := MaxLevel
+	if source == SourceQuiz {
+		limit = RecognitionCap
+	}
+	return max(level, min(level+1, limit))
+}
+
+// ReviewInterval returns how long a word at level waits for its next review.
+func ReviewInterval(level int) time.Duration {
+	return reviewIntervals[min(max(level, 0), MaxLevel)]
+}
+
+// Store reads and writes mastery levels.
+type Store struct {
+	collection database.CollectionInterface
+}
+
+// NewStore creates a store over the quiz database.
+func NewStore(db *mongo.Database) *Store {
+	return &Store{collection: database.NewUserScopedCollection(db.Collection("mastery"))}
+}
+
+// Record applies one outcome to the user's level of a word.
+func (s *Store) Record(ctx context.Context, userID, vocabularyID string, source Source, correct bool) error {
+	counter := "misses"
+	if correct {
+		counter = "hits"
+	}
+	key := bson.M{"user_id": userID, "vocabulary_id": vocabularyID}
+
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		var current models.Mastery
+		err := s.collection.FindOne(ctx, key).Decode(&current)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+		exists := err == nil
+
+		// Only apply the new level if no other outcome was applied since we read it
+		level := Next(current.Level, source, correct)
+		now := changefeed.Now()
+		filter := database.MatchVersion(bson.M{"user_id": userID, "vocabulary_id": vocabularyID}, current.Version)
+		update := bson.M{
+			"$set": bson.M{"level": level, "updated_at": now, "due_at": now.Add(reviewIntervals[level])},
+			"$inc": bson.M{counter: 1},
+		}
+		result, err := s.collection.UpdateOne(ctx, filter, database.BumpVersion(update), options.Update().SetUpsert(!exists))
+		if err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				continue // Another outcome created the document first
+			}
+			return err
+		}
+		if result.MatchedCount > 0 || result.UpsertedCount > 0 {
+			return nil
+		}
+	}
+	return ErrConflict
+}
+
+// Levels returns the user's level of each word they have practiced, limited to vocabularyIDs when given.
+func (s *Store) Levels(ctx context.Context, userID string, vocabularyIDs []string) (map[string]int32, error) {
+	filter := bson.M{"user_id": userID}
+	if len(vocabularyIDs) > 0 {
+		filter["vocabulary_id"] = bson.M{"$in": vocabularyIDs}
+	}
+	items, err := database.FindAll[models.Mastery](ctx, s.collection, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make(map[string]int32, len(items))
+	for _, item := range items {
+		levels[item.VocabularyID] = int32(item.Level)
+	}
+	return levels, nil
+}
+
+// Due returns up to limit words the user should review at now, most overdue first.
+func (s *Store) Due(ctx context.Context, userID string, now time.Time, limit int) ([]string, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "due_at", Value: 1}}).SetLimit(int64(limit))
+	items, err := database.FindAll[models.Mastery](ctx, s.collection, bson.M{"user_id": userID, "due_at": bson.M{"$lte": now}}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.VocabularyID
+	}
+	return ids, nil
+}
+
+// DueCount returns how many words the user should review at now.
+func (s *Store) DueCount(ctx context.Context, userID string, now time.Time) (int64, error) {
+	return s.collection.CountDocuments(ctx, bson.M{"user_id": userID, "due_at": bson.M{"$lte": now}})
+}
+
+// Since returns the user's mastery of the words that changed at or after since; the zero time
+// returns all of them.
+func (s *Store) Since(ctx context.Context, userID string, since time.Time) ([]models.Mastery, error) {
+	return database.FindAll[models.Mastery](ctx, s.collection, changefeed.Filter("user_id", userID, "updated_at", since))
+}
+
+// lowEase is the SM-2 ease factor below which an imported word counts as one the learner struggles
+// with. Anki starts cards at 2.5 and takes 0.2 off for every lapse.
+const lowEase = 2.0
+
+// ImportedLevel returns the level for a word another SRS app scheduled interval apart, with the
+// given SM-2 ease factor (0 if unknown): the highest level whose interval it has reached, and one
+// lower for a struggling word. Words it has reviewed at least once start at level 1.
+func ImportedLevel(interval time.Duration, ease float64) int {
+	level := 0
+	for l := MaxLevel; l > 0; l-- {
+		if interval >= reviewIntervals[l] {
+			level = l
+			break
+		}
+	}
+	if ease > 0 && ease < lowEase {
+		level--
+	}
+	return max(level, 1)
+}
+
+// Seed is a word's state carried over from another SRS app.
+type Seed struct {
+	VocabularyID string
+	Level        int
+	DueAt        time.Time
+	Hits         int64 // Passed reviews
+	Misses       int64 // Lapses
+}
+
+// Import seeds the user's levels of words they practiced elsewhere. A seed only applies where the
+// user's level of the word is lower, so an import never undoes progress made in Wise Owl; its
+// review counts are added to theirs. It returns how many words it applied to.
+func (s *Store) Import(ctx context.Context, userID string, seeds []Seed) (int, error) {
+	if len(seeds) == 0 {
+		return 0, nil
+	}
+	now := changefeed.Now()
+	writes := make([]mongo.WriteModel, 0, len(seeds))
+	for _, seed := range seeds {
+		// Upserting where the level is not lower collides with the unique index and leaves the word be
+		filter := bson.M{"user_id": userID, "vocabulary_id": seed.VocabularyID, "level": bson.M{"$lt": seed.Level}}
+		update := bson.M{
+			"$set": bson.M{"level": seed.Level, "updated_at": now, "due_at": seed.DueAt},
+			"$inc": bson.M{"hits": seed.Hits, "misses": seed.Misses},
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(database.BumpVersion(update)).SetUpsert(true))
+	}
+
+	result, err := s.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		for _, writeErr := range bulkErr.WriteErrors {
+			if !mongo.IsDuplicateKeyError(writeErr) {
+				return 0, err
+			}
+		}
+		err = nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return int(result.UpsertedCount + result.ModifiedCount), nil
+}
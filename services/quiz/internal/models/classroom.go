@@ -0,0 +1,54 @@
+// FILE: services/quiz/internal/models/classroom.go
+
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Classroom is a class a teacher runs. Students join it with its join code.
+type Classroom struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TeacherID    string             `bson:"teacher_id" json:"-"` // Auth0 ID of the user who created the class
+	Name         string             `bson:"name" json:"name"`
+	JoinCode     string             `bson:"join_code" json:"join_code,omitempty"` // e.g. "K3X9Q2MF"; shown to the teacher only
+	StudentCount int                `bson:"student_count" json:"student_count"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	Teaching     bool               `bson:"-" json:"teaching"` // Whether the caller is the class's teacher
+}
+
+// ClassMember records that a student joined a class. There is one per class and student.
+type ClassMember struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClassID     primitive.ObjectID `bson:"class_id" json:"-"`
+	UserID      string             `bson:"user_id" json:"-"`                 // Auth0 ID
+	DisplayName string             `bson:"display_name" json:"display_name"` // The name the student chose to show the teacher
+	JoinedAt    time.Time          `bson:"joined_at" json:"joined_at"`
+}
+
+// Assignment kinds.
+const (
+	AssignmentLesson   = "lesson"
+	AssignmentStudySet = "study_set"
+)
+
+// Completion statuses of an assignment, for one student.
+const (
+	AssignmentPending   = "pending"
+	AssignmentCompleted = "completed"
+	AssignmentOverdue   = "overdue" // Past its due date and not completed
+)
+
+// ClassAssignment is a lesson or study set a teacher assigned to a class.
+type ClassAssignment struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClassID    primitive.ObjectID `bson:"class_id" json:"class_id"`
+	Kind       string             `bson:"kind" json:"kind"`           // AssignmentLesson or AssignmentStudySet
+	TargetID   string             `bson:"target_id" json:"target_id"` // Lesson ID, e.g. "lesson-1", or study set ID
+	Title      string             `bson:"title" json:"title"`         // Lesson or set name when assigned
+	AssignedAt time.Time          `bson:"assigned_at" json:"assigned_at"`
+	DueAt      *time.Time         `bson:"due_at,omitempty" json:"due_at"` // Null for assignments without a deadline
+	RemindedAt *time.Time         `bson:"reminded_at,omitempty" json:"-"` // When students were reminded of the due date
+}
@@ -11,8 +11,53 @@ import (
 // IncorrectWord represents the relationship between a user and a vocabulary item
 // they have answered incorrectly.
 type IncorrectWord struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty"`
-	UserID       string             `bson:"user_id"`       // The Auth0 ID of the user
-	VocabularyID string             `bson:"vocabulary_id"` // The ObjectID (as a string) of the vocab item
-	CreatedAt    time.Time          `bson:"created_at"`
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	UserID       string             `bson:"user_id" json:"-"`                   // The Auth0 ID of the user
+	VocabularyID string             `bson:"vocabulary_id" json:"vocabulary_id"` // The ObjectID (as a string) of the vocab item
+	MissCount    int64              `bson:"miss_count" json:"miss_count"`       // Times the word was answered incorrectly; absent on older records (count as 1)
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	LastMissedAt time.Time          `bson:"last_missed_at,omitempty" json:"last_missed_at"` // Absent on older records
+	UpdatedAt    time.Time          `bson:"updated_at,omitempty" json:"updated_at"`         // Last write; drives the sync feed, absent on older records
+	Version      int64              `bson:"version,omitempty" json:"version"`               // Counts writes; deletions can be made conditional on it. Absent (0) on older records
+}
+
+// AnswerStats holds a user's running quiz answer totals.
+type AnswerStats struct {
+	UserID         string    `bson:"user_id" json:"-"`
+	Total          int64     `bson:"total" json:"total"`
+	Correct        int64     `bson:"correct" json:"correct"`
+	Incorrect      int64     `bson:"incorrect" json:"incorrect"`
+	LastAnsweredAt time.Time `bson:"last_answered_at" json:"last_answered_at"`
+	UpdatedAt      time.Time `bson:"updated_at,omitempty" json:"updated_at"` // Absent on older records
+}
+
+// Mastery is how well a user knows a vocabulary item, from 0 (new) to 5 (mastered).
+// See the mastery package for how outcomes move the level.
+type Mastery struct {
+	UserID       string    `bson:"user_id" json:"-"`
+	VocabularyID string    `bson:"vocabulary_id" json:"vocabulary_id"`
+	Level        int       `bson:"level" json:"level"`
+	Hits         int64     `bson:"hits" json:"hits"`     // Correct answers and passed reviews
+	Misses       int64     `bson:"misses" json:"misses"` // Wrong answers and failed reviews
+	UpdatedAt    time.Time `bson:"updated_at" json:"updated_at"`
+	DueAt        time.Time `bson:"due_at" json:"due_at"`             // When the word is next due for review
+	Version      int64     `bson:"version,omitempty" json:"version"` // Counts outcomes applied; absent (0) on older records
+}
+
+// ProcessedEvent records that a domain event was applied, so redelivered events are skipped.
+type ProcessedEvent struct {
+	EventID     string    `bson:"_id"`
+	EventType   string    `bson:"event_type"`
+	ProcessedAt time.Time `bson:"processed_at"` // Expired by a TTL index once redelivery is no longer possible
+}
+
+// SyncedAnswer records an answer a client recorded offline and uploaded, so uploading it again
+// is not counted twice. Client IDs are chosen by the client and unique per user.
+type SyncedAnswer struct {
+	UserID       string    `bson:"user_id"`
+	ClientID     string    `bson:"client_id"`
+	VocabularyID string    `bson:"vocabulary_id"`
+	Correct      bool      `bson:"correct"`
+	AnsweredAt   time.Time `bson:"answered_at"` // Expired by a TTL index once the answer is too old to upload again
+	SyncedAt     time.Time `bson:"synced_at"`
 }
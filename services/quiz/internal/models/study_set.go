@@ -0,0 +1,33 @@
+// FILE: services/quiz/internal/models/study_set.go
+
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StudySet is a word list a user put together to study and quiz themselves on.
+// Public sets can be opened by anyone through their slug.
+type StudySet struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	OwnerID       string              `bson:"owner_id" json:"-"` // Auth0 ID; not shown, since public sets are shared
+	Name          string              `bson:"name" json:"name"`
+	Description   string              `bson:"description" json:"description"`
+	VocabularyIDs []string            `bson:"vocabulary_ids" json:"vocabulary_ids"` // In the owner's order
+	Public        bool                `bson:"public" json:"public"`
+	Slug          string              `bson:"slug" json:"slug"`                                   // Share code, e.g. "k3x9q2mf"; only resolves while the set is public
+	ClonedFrom    *primitive.ObjectID `bson:"cloned_from,omitempty" json:"cloned_from,omitempty"` // Set this one was copied from
+	LikeCount     int64               `bson:"like_count" json:"like_count"`
+	CloneCount    int64               `bson:"clone_count" json:"clone_count"` // Copies made by other users
+	CreatedAt     time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// StudySetLike records that a user liked a public study set. There is one per user and set.
+type StudySetLike struct {
+	SetID     primitive.ObjectID `bson:"set_id"`
+	UserID    string             `bson:"user_id"` // Auth0 ID
+	CreatedAt time.Time          `bson:"created_at"`
+}
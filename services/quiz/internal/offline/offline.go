@@ -0,0 +1,265 @@
+// FILE: services/quiz/internal/offline/offline.go
+// This package syncs mobile clients that study offline. A pull returns what changed since the
+// client's cursor, across the quiz service's own data and the user data the users service
+// keeps; a push applies the answers the client recorded while it was offline.
+
+package offline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"wise-owl/lib/auth"
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/database"
+	"wise-owl/lib/events"
+	"wise-owl/services/quiz/internal/mastery"
+	"wise-owl/services/quiz/internal/models"
+	"wise-owl/services/quiz/internal/stats"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrUsersUnavailable is returned when the users service's changes could not be fetched
+var ErrUsersUnavailable = errors.New("users service unavailable")
+
+// Pull is what changed for a user since their cursor.
+type Pull struct {
+	Cursor    string                 `json:"cursor"` // Sent back as ?since= on the next pull
+	Full      bool                   `json:"full"`   // Everything was sent: the client should replace its copy rather than merge
+	Changes   ChangeSet              `json:"changes"`
+	Deletions []changefeed.Tombstone `json:"deletions"` // Documents to drop, by collection and ID
+}
+
+// ChangeSet holds the changed documents of each synced collection. Single documents are null
+// when they did not change.
+type ChangeSet struct {
+	IncorrectWords    []models.IncorrectWord `json:"incorrect_words"`
+	Mastery           []models.Mastery       `json:"mastery"`
+	AnswerStats       *models.AnswerStats    `json:"answer_stats"`
+	Profile           json.RawMessage        `json:"profile"`            // As the users service serves it
+	LessonCompletions json.RawMessage        `json:"lesson_completions"` // As the users service serves them
+	Favorites         json.RawMessage        `json:"favorites"`          // As the users service serves them
+}
+
+// Answer is a quiz answer a client recorded offline.
+type Answer struct {
+	ClientID     string // Chosen by the client, unique per user; uploading an answer again is a no-op
+	VocabularyID string
+	Correct      bool
+	AnsweredAt   time.Time
+}
+
+// Status is what became of an uploaded answer.
+type Status string
+
+// Answer outcomes.
+const (
+	StatusApplied   Status = "applied"   // Counted in totals and mastery
+	StatusDuplicate Status = "duplicate" // Already uploaded; not counted again
+	StatusRejected  Status = "rejected"  // Answered before the sync window; not counted
+)
+
+// Result is the outcome of one uploaded answer.
+type Result struct {
+	ClientID string `json:"client_id"`
+	Status   Status `json:"status"`
+}
+
+// Syncer serves pulls and applies pushes.
+type Syncer struct {
+	stats      *stats.Store
+	mastery    *mastery.Store
+	tombstones database.CollectionInterface
+	synced     database.CollectionInterface // Client IDs of answers already uploaded
+	publisher  events.Publisher
+	usersURL   string
+	httpClient *http.Client
+}
+
+// NewSyncer creates a syncer over the quiz database that asks the users service at usersURL for
+// its side of the changes.
+func NewSyncer(db *mongo.Database, statsStore *stats.Store, masteryStore *mastery.Store, publisher events.Publisher, usersURL string) *Syncer {
+	return &Syncer{
+		stats:      statsStore,
+		mastery:    masteryStore,
+		tombstones: db.Collection(changefeed.TombstoneCollection),
+		synced:     db.Collection("synced_answers"),
+		publisher:  publisher,
+		usersURL:   usersURL,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Pull returns userID's changes since the cursor, or everything for an empty one. Cursors the
+// feed did not hand out fail with changefeed.ErrInvalidCursor and stale ones with
+// changefeed.ErrExpiredCursor; failures of the users service wrap ErrUsersUnavailable.
+func (s *Syncer) Pull(ctx context.Context, userID, cursor string, now time.Time) (Pull, error) {
+	since, err := changefeed.Since(cursor, now)
+	if err != nil {
+		return Pull{}, err
+	}
+	// The next cursor is taken before reading, so a write that lands mid-pull is sent next time
+	pull := Pull{Cursor: changefeed.Cursor(now), Full: since.IsZero()}
+
+	dbCtx, cancel := ctxutil.Database(ctx)
+	defer cancel()
+	if pull.Changes.IncorrectWords, err = s.stats.IncorrectWordsSince(dbCtx, userID, since); err != nil {
+		return Pull{}, err
+	}
+	if pull.Changes.Mastery, err = s.mastery.Since(dbCtx, userID, since); err != nil {
+		return Pull{}, err
+	}
+	if pull.Changes.AnswerStats, err = s.stats.AnswerStatsSince(dbCtx, userID, since); err != nil {
+		return Pull{}, err
+	}
+	if pull.Deletions, err = changefeed.Deletions(dbCtx, s.tombstones, userID, since); err != nil {
+		return Pull{}, err
+	}
+
+	users, err := s.userChanges(ctx, userID, since)
+	if err != nil {
+		return Pull{}, fmt.Errorf("%w: %v", ErrUsersUnavailable, err)
+	}
+	pull.Changes.Profile = users.Profile
+	pull.Changes.LessonCompletions = users.LessonCompletions
+	pull.Changes.Favorites = users.Favorites
+	pull.Deletions = append(pull.Deletions, users.Deletions...)
+	return pull, nil
+}
+
+// userChanges is the users service's side of a pull
+type userChanges struct {
+	Profile           json.RawMessage        `json:"profile"`
+	LessonCompletions json.RawMessage        `json:"lesson_completions"`
+	Favorites         json.RawMessage        `json:"favorites"`
+	Deletions         []changefeed.Tombstone `json:"deletions"`
+}
+
+// userChanges asks the users service what changed for userID since the given time
+func (s *Syncer) userChanges(ctx context.Context, userID string, since time.Time) (userChanges, error) {
+	endpoint := s.usersURL + "/internal/v1/users/" + url.PathEscape(userID) + "/changes"
+	if !since.IsZero() {
+		endpoint += "?since=" + url.QueryEscape(since.Format(time.RFC3339Nano))
+	}
+	var changes userChanges
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return changes, err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return changes, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return changes, fmt.Errorf("status %d: %s", resp.StatusCode, body)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 8<<20)).Decode(&changes); err != nil {
+		return changes, fmt.Errorf("decode changes: %v", err)
+	}
+	return changes, nil
+}
+
+// Push applies answers userID recorded offline, oldest first, and returns their outcomes in the
+// order given. Each answer counts as if it had been recorded when it was answered, with one
+// exception: a miss does not put a word back on the incorrect list if the learner removed it
+// after the miss, on this or another device.
+//
+// Answers from the future are taken as answered now. Answers older than changefeed.Retention
+// are rejected, since the removals they would be checked against may already be forgotten.
+// A failed push can be retried as a whole; answers it applied come back as duplicates.
+func (s *Syncer) Push(ctx context.Context, userID string, answers []Answer, now time.Time) ([]Result, error) {
+	order := make([]int, len(answers))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return answers[order[a]].AnsweredAt.Before(answers[order[b]].AnsweredAt)
+	})
+
+	results := make([]Result, len(answers))
+	for _, i := range order {
+		answer := answers[i]
+		if answer.AnsweredAt.After(now) {
+			answer.AnsweredAt = now
+		}
+		status := StatusRejected
+		if now.Sub(answer.AnsweredAt) <= changefeed.Retention {
+			dbCtx, cancel := ctxutil.Database(ctx)
+			applied, err := s.apply(dbCtx, userID, answer, now)
+			cancel()
+			if err != nil {
+				return nil, err
+			}
+			status = applied
+		}
+		results[i] = Result{ClientID: answer.ClientID, Status: status}
+	}
+	return results, nil
+}
+
+// apply counts one answer unless it was uploaded before
+func (s *Syncer) apply(ctx context.Context, userID string, answer Answer, now time.Time) (Status, error) {
+	marker := models.SyncedAnswer{
+		UserID:       userID,
+		ClientID:     answer.ClientID,
+		VocabularyID: answer.VocabularyID,
+		Correct:      answer.Correct,
+		AnsweredAt:   answer.AnsweredAt,
+		SyncedAt:     now,
+	}
+	if err := database.InsertUnique(ctx, s.synced, marker); err != nil {
+		if errors.Is(err, database.ErrDuplicate) {
+			return StatusDuplicate, nil
+		}
+		return "", err
+	}
+
+	if err := s.count(ctx, userID, answer); err != nil {
+		// Forget the answer so a retried push counts it
+		if _, delErr := s.synced.DeleteOne(ctx, bson.M{"user_id": userID, "client_id": answer.ClientID}); delErr != nil {
+			return "", fmt.Errorf("%v (and releasing it: %v)", err, delErr)
+		}
+		return "", err
+	}
+
+	// Mastery follows from the event, as it does for answers recorded online
+	if !auth.IsGuest(userID) {
+		event := events.New(events.TypeQuizAnswer, userID, map[string]interface{}{
+			"vocabulary_id": answer.VocabularyID,
+			"correct":       answer.Correct,
+			"offline":       true,
+		})
+		event.OccurredAt = answer.AnsweredAt
+		events.PublishAsync(s.publisher, event)
+	}
+	return StatusApplied, nil
+}
+
+// count adds the answer to the user's totals and, for a miss, their incorrect list
+func (s *Syncer) count(ctx context.Context, userID string, answer Answer) error {
+	if !answer.Correct {
+		removed, err := changefeed.DeletedAfter(ctx, s.tombstones, userID, "incorrect_words", answer.VocabularyID, answer.AnsweredAt)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			if err := s.stats.RecordMiss(ctx, userID, answer.VocabularyID, answer.AnsweredAt); err != nil {
+				return err
+			}
+		}
+	}
+	return s.stats.RecordAnswer(ctx, userID, answer.Correct, answer.AnsweredAt)
+}
@@ -0,0 +1,72 @@
+// FILE: services/quiz/internal/pronunciation/pronunciation.go
+// This package scores a spoken word against its reading. Speech recognition writes what it heard
+// the way a native text would, often in kanji, so a transcript is compared with both the word's
+// kana and its kanji, and the best of the recognizer's alternatives counts.
+
+package pronunciation
+
+import (
+	"math"
+
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/japanese"
+)
+
+// Grade is how a pronunciation attempt counts as a spaced repetition review.
+type Grade string
+
+// Grades, from failed to effortless.
+const (
+	GradeAgain Grade = "again" // Not recognized as the word; the review is failed
+	GradeHard  Grade = "hard"  // Recognizable with mistakes
+	GradeGood  Grade = "good"  // Heard as the word
+)
+
+// Lowest scores for each passing grade
+const (
+	goodScore = 90
+	hardScore = 70
+)
+
+// Result is the outcome of scoring one attempt.
+type Result struct {
+	Score      int    `json:"score"`      // 0 to 100
+	Grade      Grade  `json:"grade"`      // The review grade the score maps to
+	Transcript string `json:"transcript"` // What the recognizer heard, in the alternative that scored best
+	Expected   string `json:"expected"`   // The word's kana reading
+}
+
+// Passed reports whether the attempt counts as a recalled word.
+func (r Result) Passed() bool {
+	return r.Grade != GradeAgain
+}
+
+// Score compares the recognizer's transcripts, most likely first, with the word. A transcript
+// written exactly as the word's kanji scores 100; otherwise the score is its reading similarity
+// to the kana (see japanese.ReadingSimilarity), or to the kanji character by character.
+// No transcripts score 0.
+func Score(vocab *pb_content.Vocabulary, transcripts []string) Result {
+	result := Result{Grade: GradeAgain, Expected: vocab.GetKana()}
+	best := -1.0
+	for _, transcript := range transcripts {
+		similarity := japanese.ReadingSimilarity(vocab.GetKana(), transcript)
+		if kanji := vocab.GetKanji(); kanji != "" {
+			similarity = max(similarity, japanese.ReadingSimilarity(kanji, transcript))
+		}
+		if similarity > best {
+			best, result.Transcript = similarity, transcript
+		}
+	}
+	if best <= 0 {
+		return result
+	}
+
+	result.Score = int(math.Round(best * 100))
+	switch {
+	case result.Score >= goodScore:
+		result.Grade = GradeGood
+	case result.Score >= hardScore:
+		result.Grade = GradeHard
+	}
+	return result
+}
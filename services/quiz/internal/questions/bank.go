@@ -0,0 +1,183 @@
+// FILE: services/quiz/internal/questions/bank.go
+// This package builds multiple-choice questions about vocabulary. Wrong choices come from the
+// content service's distractor query, so they share the word's class, lesson, and length
+// instead of being random words that make the answer obvious.
+
+package questions
+
+import (
+	"context"
+	"math/rand/v2"
+
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/ctxutil"
+)
+
+// ChoicesPerQuestion is the number of answers offered for each question.
+const ChoicesPerQuestion = 4
+
+// Kind is how a question is presented.
+type Kind string
+
+// Question kinds.
+const (
+	KindText      Kind = "text"      // The prompt is written
+	KindListening Kind = "listening" // The prompt is the word's audio; Prompt is left empty
+)
+
+// Choice is what the learner picks among.
+type Choice string
+
+// Choice kinds.
+const (
+	ChoiceMeaning Choice = "meaning" // English meanings; text prompts show the word in Japanese
+	ChoiceKana    Choice = "kana"    // Kana spellings; text prompts show the English meaning
+)
+
+// Options select the kind of questions to build. The zero value builds text questions about meanings.
+type Options struct {
+	Kind    Kind
+	Choices Choice
+}
+
+// Question is a multiple-choice question about a word. Answer is the index of the correct choice.
+type Question struct {
+	VocabularyID string   `json:"vocabulary_id" bson:"vocabulary_id"`
+	Kind         Kind     `json:"kind" bson:"kind"`
+	Prompt       string   `json:"prompt,omitempty" bson:"prompt,omitempty"`
+	AudioURL     string   `json:"audio_url,omitempty" bson:"audio_url,omitempty"`
+	Choices      []string `json:"choices" bson:"choices"`
+	Answer       int      `json:"answer" bson:"answer"`
+}
+
+// Bank builds questions from content service vocabulary.
+type Bank struct {
+	contentClient pb_content.ContentServiceClient
+}
+
+// NewBank creates a question bank backed by the content service.
+func NewBank(contentClient pb_content.ContentServiceClient) *Bank {
+	return &Bank{contentClient: contentClient}
+}
+
+// Build returns up to count questions about the given words, in random order.
+// When the content service has too few distractors for a word, the other requested words
+// fill the remaining choices; words that still lack enough distinct choices are skipped.
+// Listening questions need recorded audio, so words without it fall back to text questions.
+// Each content service call gets its own gRPC budget within ctx (see lib/ctxutil).
+func (b *Bank) Build(ctx context.Context, vocabularyIDs []string, count int, opts Options) ([]Question, error) {
+	if opts.Kind == "" {
+		opts.Kind = KindText
+	}
+	if opts.Choices == "" {
+		opts.Choices = ChoiceMeaning
+	}
+
+	batchCtx, cancelBatch := ctxutil.GRPC(ctx)
+	defer cancelBatch()
+	batch, err := b.contentClient.GetVocabularyBatch(batchCtx, &pb_content.GetVocabularyBatchRequest{VocabularyIds: vocabularyIDs})
+	if err != nil {
+		return nil, err
+	}
+
+	var words []*pb_content.Vocabulary
+	for _, vocab := range batch.GetItems() {
+		if answerText(vocab, opts.Choices) != "" {
+			words = append(words, vocab)
+		}
+	}
+	if len(words) == 0 {
+		return nil, nil
+	}
+	rand.Shuffle(len(words), func(i, j int) { words[i], words[j] = words[j], words[i] })
+	if count > len(words) {
+		count = len(words)
+	}
+	words = words[:count]
+
+	ids := make([]string, len(words))
+	for i, word := range words {
+		ids[i] = word.GetId()
+	}
+	distractorsCtx, cancelDistractors := ctxutil.GRPC(ctx)
+	defer cancelDistractors()
+	distractors, err := b.contentClient.GetDistractors(distractorsCtx, &pb_content.GetDistractorsRequest{
+		VocabularyIds: ids,
+		Count:         ChoicesPerQuestion - 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	questions := make([]Question, 0, len(words))
+	for _, word := range words {
+		if question, ok := buildQuestion(word, distractors.GetDistractors()[word.GetId()].GetItems(), words, opts); ok {
+			questions = append(questions, question)
+		}
+	}
+	return questions, nil
+}
+
+// buildQuestion asks about word, taking wrong choices from distractors first and then from others.
+func buildQuestion(word *pb_content.Vocabulary, distractors, others []*pb_content.Vocabulary, opts Options) (Question, bool) {
+	correct := answerText(word, opts.Choices)
+	choices := []string{correct}
+	used := map[string]bool{correct: true}
+	add := func(candidates []*pb_content.Vocabulary) {
+		for _, candidate := range candidates {
+			if len(choices) == ChoicesPerQuestion {
+				return
+			}
+			if text := answerText(candidate, opts.Choices); text != "" && !used[text] {
+				used[text] = true
+				choices = append(choices, text)
+			}
+		}
+	}
+	add(distractors)
+	if len(choices) < ChoicesPerQuestion {
+		shuffled := append([]*pb_content.Vocabulary(nil), others...)
+		rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		add(shuffled)
+	}
+	if len(choices) < ChoicesPerQuestion {
+		return Question{}, false
+	}
+
+	rand.Shuffle(len(choices), func(i, j int) { choices[i], choices[j] = choices[j], choices[i] })
+	answer := 0
+	for i, choice := range choices {
+		if choice == correct {
+			answer = i
+		}
+	}
+
+	question := Question{VocabularyID: word.GetId(), Kind: KindText, Choices: choices, Answer: answer}
+	if opts.Kind == KindListening && word.GetAudioUrl() != "" {
+		question.Kind = KindListening
+		question.AudioURL = word.GetAudioUrl()
+		return question, true
+	}
+
+	switch opts.Choices {
+	case ChoiceKana:
+		question.Prompt = word.GetEnglish()
+	default:
+		question.Prompt = word.GetKanji()
+		if question.Prompt == "" {
+			question.Prompt = word.GetKana()
+		}
+	}
+	if question.Prompt == "" {
+		return Question{}, false
+	}
+	return question, true
+}
+
+// answerText is the text of a word shown as a choice.
+func answerText(vocab *pb_content.Vocabulary, choice Choice) string {
+	if choice == ChoiceKana {
+		return vocab.GetKana()
+	}
+	return vocab.GetEnglish()
+}
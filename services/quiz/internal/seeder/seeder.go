@@ -0,0 +1,69 @@
+// FILE: services/quiz/internal/seeder/seeder.go
+
+package seeder
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/database"
+	"wise-owl/services/quiz/internal/live"
+	"wise-owl/services/quiz/internal/sessions"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// processedEventRetention is how long event IDs are remembered for deduplication.
+const processedEventRetention = 7 * 24 * time.Hour
+
+// Indexes lists every index the quiz service relies on.
+var Indexes = append([]database.Index{
+	// Misses are upserted per user and word, and lists are loaded per user
+	{Collection: "incorrect_words", Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "vocabulary_id", Value: 1}}, Unique: true},
+	{Collection: "answer_stats", Keys: bson.D{{Key: "user_id", Value: 1}}, Unique: true},
+	// Study plans review the most recent misses
+	{Collection: "incorrect_words", Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "last_missed_at", Value: -1}}},
+	// One mastery level per user and word; level updates rely on it
+	{Collection: "mastery", Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "vocabulary_id", Value: 1}}, Unique: true},
+	{Collection: "mastery", Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "due_at", Value: 1}}},
+	{Collection: "processed_events", Keys: bson.D{{Key: "processed_at", Value: 1}}, ExpireAfter: processedEventRetention},
+	// Owners list their sets by last update; share slugs must resolve to a single set
+	{Collection: "study_sets", Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "updated_at", Value: -1}}},
+	{Collection: "study_sets", Keys: bson.D{{Key: "slug", Value: 1}}, Unique: true},
+	// Public set discovery sorts by popularity or recency; a user can like a set only once
+	{Collection: "study_sets", Keys: bson.D{{Key: "public", Value: 1}, {Key: "like_count", Value: -1}, {Key: "clone_count", Value: -1}, {Key: "_id", Value: -1}}},
+	{Collection: "study_sets", Keys: bson.D{{Key: "public", Value: 1}, {Key: "updated_at", Value: -1}, {Key: "_id", Value: -1}}},
+	{Collection: "study_set_likes", Keys: bson.D{{Key: "set_id", Value: 1}, {Key: "user_id", Value: 1}}, Unique: true},
+	// The sync feed reads each user's changes since a cursor
+	{Collection: "incorrect_words", Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "updated_at", Value: 1}}},
+	{Collection: "mastery", Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "updated_at", Value: 1}}},
+	// Uploaded offline answers are counted once per client ID, and remembered as long as they can be uploaded
+	{Collection: "synced_answers", Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "client_id", Value: 1}}, Unique: true},
+	{Collection: "synced_answers", Keys: bson.D{{Key: "answered_at", Value: 1}}, ExpireAfter: changefeed.Retention},
+	// Join codes must resolve to a single class; teachers list their classes newest first
+	{Collection: "classrooms", Keys: bson.D{{Key: "join_code", Value: 1}}, Unique: true},
+	{Collection: "classrooms", Keys: bson.D{{Key: "teacher_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	// A student joins a class once; students list the classes they joined
+	{Collection: "class_members", Keys: bson.D{{Key: "class_id", Value: 1}, {Key: "user_id", Value: 1}}, Unique: true},
+	{Collection: "class_members", Keys: bson.D{{Key: "user_id", Value: 1}}},
+	{Collection: "class_assignments", Keys: bson.D{{Key: "class_id", Value: 1}, {Key: "assigned_at", Value: -1}}},
+	// The reminder job looks for assignments coming due
+	{Collection: "class_assignments", Keys: bson.D{{Key: "due_at", Value: 1}}},
+	// Quiz sessions, when kept in MongoDB, expire by themselves
+	sessions.Index,
+	// Live rooms of instances that stopped without releasing them are forgotten
+	live.RegistryIndex,
+}, changefeed.Indexes...)
+
+// SeedDatabase ensures the declared indexes exist.
+// There is no seed data; quiz documents are written as users answer.
+func SeedDatabase(db *mongo.Database) {
+	if err := database.EnsureIndexes(context.Background(), db, Indexes); err != nil {
+		log.Printf("WARN: Failed to ensure quiz indexes: %v", err)
+	}
+
+	log.Println("Quiz service initialized successfully")
+}
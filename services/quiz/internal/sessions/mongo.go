@@ -0,0 +1,80 @@
+// FILE: services/quiz/internal/sessions/mongo.go
+// Sessions kept in MongoDB, removed by a TTL index once they expire
+
+package sessions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"wise-owl/lib/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Collection holds the mongo store's sessions.
+const Collection = "quiz_sessions"
+
+// Index removes sessions once they pass expires_at. A TTL index cannot expire at the date itself,
+// so it waits a second; reads skip expired sessions until the TTL monitor gets to them.
+var Index = database.Index{
+	Collection:  Collection,
+	Keys:        bson.D{{Key: "expires_at", Value: 1}},
+	ExpireAfter: time.Second,
+}
+
+// MongoStore keeps sessions in a MongoDB collection.
+type MongoStore struct {
+	collection *mongo.Collection
+	ttl        time.Duration
+}
+
+// NewMongoStore creates a store whose sessions expire ttl after they were last written.
+func NewMongoStore(db *mongo.Database, ttl time.Duration) *MongoStore {
+	return &MongoStore{collection: db.Collection(Collection), ttl: ttl}
+}
+
+// Create inserts the session.
+func (m *MongoStore) Create(ctx context.Context, s *Session) error {
+	s.ExpiresAt = time.Now().UTC().Add(m.ttl)
+	_, err := m.collection.InsertOne(ctx, s)
+	return err
+}
+
+// Get finds an unexpired session.
+func (m *MongoStore) Get(ctx context.Context, id string) (*Session, error) {
+	var s Session
+	err := m.collection.FindOne(ctx, bson.M{"_id": id, "expires_at": bson.M{"$gt": time.Now().UTC()}}).Decode(&s)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Save replaces the session if its version is unchanged.
+func (m *MongoStore) Save(ctx context.Context, s *Session) error {
+	read := s.Version
+	s.Version++
+	s.ExpiresAt = time.Now().UTC().Add(m.ttl)
+	res, err := m.collection.ReplaceOne(ctx, bson.M{"_id": s.ID, "version": read}, s)
+	if err != nil {
+		s.Version = read
+		return err
+	}
+	if res.MatchedCount == 0 {
+		s.Version = read
+		return ErrConflict
+	}
+	return nil
+}
+
+// Delete removes the session.
+func (m *MongoStore) Delete(ctx context.Context, id string) error {
+	_, err := m.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
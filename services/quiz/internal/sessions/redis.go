@@ -0,0 +1,128 @@
+// FILE: services/quiz/internal/sessions/redis.go
+// Sessions kept in Redis as JSON, expired by Redis itself
+
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix keeps session keys apart from anything else in a shared Redis
+const redisKeyPrefix = "quiz:session:"
+
+// RedisStore keeps sessions in Redis.
+type RedisStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisStore connects to the Redis at url (redis:// or rediss:// with TLS) and creates a
+// store whose sessions expire ttl after they were last written.
+func NewRedisStore(ctx context.Context, url string, ttl time.Duration) (*RedisStore, error) {
+	if url == "" {
+		return nil, fmt.Errorf("redis session store needs REDIS_URL")
+	}
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("parsing REDIS_URL: %v", err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("connecting to redis: %v", err)
+	}
+	return &RedisStore{client: client, ttl: ttl}, nil
+}
+
+// Create stores the session under a new key.
+func (r *RedisStore) Create(ctx context.Context, s *Session) error {
+	s.ExpiresAt = time.Now().UTC().Add(r.ttl)
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	created, err := r.client.SetNX(ctx, redisKeyPrefix+s.ID, data, r.ttl).Result()
+	if err != nil {
+		return err
+	}
+	if !created {
+		return fmt.Errorf("quiz session %s already exists", s.ID)
+	}
+	return nil
+}
+
+// Get reads a session.
+func (r *RedisStore) Get(ctx context.Context, id string) (*Session, error) {
+	data, err := r.client.Get(ctx, redisKeyPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("decoding quiz session %s: %v", id, err)
+	}
+	return &s, nil
+}
+
+// Save replaces the session in a transaction that fails if the stored version moved on.
+func (r *RedisStore) Save(ctx context.Context, s *Session) error {
+	key := redisKeyPrefix + s.ID
+	read := s.Version
+	err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		var stored struct {
+			Version int `json:"version"`
+		}
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return fmt.Errorf("decoding quiz session %s: %v", s.ID, err)
+		}
+		if stored.Version != read {
+			return ErrConflict
+		}
+
+		s.Version = read + 1
+		s.ExpiresAt = time.Now().UTC().Add(r.ttl)
+		updated, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, updated, r.ttl)
+			return nil
+		})
+		return err
+	}, key)
+	if err != nil {
+		s.Version = read
+		if errors.Is(err, redis.TxFailedErr) {
+			return ErrConflict
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete removes the session.
+func (r *RedisStore) Delete(ctx context.Context, id string) error {
+	return r.client.Del(ctx, redisKeyPrefix+id).Err()
+}
+
+// Close disconnects from Redis.
+func (r *RedisStore) Close() error {
+	return r.client.Close()
+}
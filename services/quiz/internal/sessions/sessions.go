@@ -0,0 +1,123 @@
+// FILE: services/quiz/internal/sessions/sessions.go
+// This package keeps solo quiz sessions: the questions of one quiz and the answers given so far.
+// Sessions last minutes and are read and written on every answer, so they live in a Store that
+// expires them by itself, Redis in production and MongoDB where no Redis is set up.
+
+package sessions
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"wise-owl/lib/config"
+	"wise-owl/services/quiz/internal/questions"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Supported session stores, selected with SESSION_STORE.
+const (
+	BackendMongo = "mongo"
+	BackendRedis = "redis"
+)
+
+var (
+	// ErrNotFound is returned for sessions that never existed or have expired.
+	ErrNotFound = errors.New("quiz session not found")
+	// ErrConflict is returned by Save when the session changed since it was read.
+	ErrConflict = errors.New("quiz session changed concurrently")
+	// ErrAlreadyAnswered is returned by Answer for a question that has an answer.
+	ErrAlreadyAnswered = errors.New("question already answered")
+	// ErrInvalidQuestion is returned by Answer for a question or choice out of range.
+	ErrInvalidQuestion = errors.New("question or choice is out of range")
+)
+
+// Session is one solo quiz. The correct choices stay on the server until a question is answered.
+type Session struct {
+	ID        string               `json:"id" bson:"_id"`
+	UserID    string               `json:"user_id" bson:"user_id"`
+	Questions []questions.Question `json:"questions" bson:"questions"`
+	Answers   []Answer             `json:"answers" bson:"answers"` // In the order they were given
+	Version   int                  `json:"version" bson:"version"` // Incremented by every Save
+	CreatedAt time.Time            `json:"created_at" bson:"created_at"`
+	ExpiresAt time.Time            `json:"expires_at" bson:"expires_at"` // Pushed back by every Save
+}
+
+// Answer is the caller's choice for one question.
+type Answer struct {
+	Question   int       `json:"question_index" bson:"question"`
+	Choice     int       `json:"choice" bson:"choice"`
+	Correct    bool      `json:"correct" bson:"correct"`
+	AnsweredAt time.Time `json:"answered_at" bson:"answered_at"`
+}
+
+// New starts a session for userID over the given questions. It is kept once passed to Store.Create.
+func New(userID string, qs []questions.Question, now time.Time) *Session {
+	return &Session{ID: newID(), UserID: userID, Questions: qs, CreatedAt: now}
+}
+
+// Answer records choice as the answer to question index and returns it graded.
+func (s *Session) Answer(index, choice int, now time.Time) (Answer, error) {
+	if index < 0 || index >= len(s.Questions) || choice < 0 || choice >= len(s.Questions[index].Choices) {
+		return Answer{}, ErrInvalidQuestion
+	}
+	for _, given := range s.Answers {
+		if given.Question == index {
+			return Answer{}, ErrAlreadyAnswered
+		}
+	}
+	answer := Answer{Question: index, Choice: choice, Correct: choice == s.Questions[index].Answer, AnsweredAt: now}
+	s.Answers = append(s.Answers, answer)
+	return answer, nil
+}
+
+// Score returns how many questions were answered correctly.
+func (s *Session) Score() int {
+	correct := 0
+	for _, given := range s.Answers {
+		if given.Correct {
+			correct++
+		}
+	}
+	return correct
+}
+
+// Finished reports whether every question has been answered.
+func (s *Session) Finished() bool {
+	return len(s.Answers) == len(s.Questions)
+}
+
+// Store keeps sessions until they expire. Every write sets ExpiresAt to the store's TTL from now.
+type Store interface {
+	// Create stores a new session.
+	Create(ctx context.Context, s *Session) error
+	// Get returns a session, or ErrNotFound.
+	Get(ctx context.Context, id string) (*Session, error)
+	// Save replaces a session read with Get, or returns ErrConflict if it was saved since.
+	Save(ctx context.Context, s *Session) error
+	// Delete removes a session. Deleting a missing session is not an error.
+	Delete(ctx context.Context, id string) error
+}
+
+// NewStore creates the Store selected by the configuration. db holds the mongo store's collection.
+func NewStore(ctx context.Context, cfg config.SessionConfig, db *mongo.Database) (Store, error) {
+	switch cfg.Backend {
+	case "", BackendMongo:
+		return NewMongoStore(db, cfg.TTL), nil
+	case BackendRedis:
+		return NewRedisStore(ctx, cfg.RedisURL, cfg.TTL)
+	default:
+		return nil, fmt.Errorf("unknown session store %q", cfg.Backend)
+	}
+}
+
+// newID returns a random session ID.
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,222 @@
+// FILE: services/quiz/internal/stats/stats.go
+// This package keeps per-user quiz answer totals and summarizes incorrect words for the gRPC API.
+
+package stats
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"wise-owl/lib/auth"
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/database"
+	"wise-owl/services/quiz/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Store reads and writes quiz statistics.
+type Store struct {
+	answers        database.CollectionInterface
+	incorrectWords database.CollectionInterface
+}
+
+// NewStore creates a store over the quiz database.
+func NewStore(db *mongo.Database) *Store {
+	return &Store{
+		answers:        database.NewUserScopedCollection(db.Collection("answer_stats")),
+		incorrectWords: database.NewUserScopedCollection(db.Collection("incorrect_words")),
+	}
+}
+
+// RecordAnswer adds one answer, given at answeredAt, to the user's totals.
+func (s *Store) RecordAnswer(ctx context.Context, userID string, correct bool, answeredAt time.Time) error {
+	field := "incorrect"
+	if correct {
+		field = "correct"
+	}
+	update := bson.M{
+		"$inc": bson.M{"total": 1, field: 1},
+		"$max": bson.M{"last_answered_at": answeredAt},
+		"$set": bson.M{"updated_at": changefeed.Now()},
+	}
+	_, err := s.answers.UpdateOne(ctx, bson.M{"user_id": userID}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// RecordMiss adds a word, missed at missedAt, to the user's incorrect list, or counts another miss
+// when it is already there.
+func (s *Store) RecordMiss(ctx context.Context, userID, vocabularyID string, missedAt time.Time) error {
+	// Upsert so a word is listed once however often it is missed
+	filter := bson.M{"user_id": userID, "vocabulary_id": vocabularyID}
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"_id":        primitive.NewObjectID(),
+			"created_at": missedAt,
+		},
+		"$inc": bson.M{"miss_count": 1},
+		"$max": bson.M{"last_missed_at": missedAt},
+		"$set": bson.M{"updated_at": changefeed.Now()},
+	}
+	_, err := s.incorrectWords.UpdateOne(ctx, filter, database.BumpVersion(update), options.Update().SetUpsert(true))
+	return err
+}
+
+// UserStats returns the user's answer totals and the size of their incorrect words list.
+// Users who have never answered get zero totals.
+func (s *Store) UserStats(ctx context.Context, userID string) (models.AnswerStats, int64, error) {
+	stats := models.AnswerStats{UserID: userID}
+	err := s.answers.FindOne(ctx, bson.M{"user_id": userID}).Decode(&stats)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return stats, 0, err
+	}
+
+	incorrectWords, err := s.incorrectWords.CountDocuments(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return stats, 0, err
+	}
+	return stats, incorrectWords, nil
+}
+
+// IncorrectWordCounts sums miss counts per vocabulary ID, for one user or for everyone when userID is empty.
+// Guest sessions are left out of the totals for everyone, so anonymous traffic cannot skew difficulty scores.
+// With no vocabularyIDs it covers all missed words, most-missed first, capped at limit when limit > 0.
+func (s *Store) IncorrectWordCounts(ctx context.Context, userID string, vocabularyIDs []string, limit int) (map[string]int64, error) {
+	match := bson.M{}
+	if userID != "" {
+		match["user_id"] = userID
+	} else {
+		match["user_id"] = bson.M{"$not": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(auth.GuestSubjectPrefix)}}
+		ctx = database.CrossUser(ctx)
+	}
+	if len(vocabularyIDs) > 0 {
+		match["vocabulary_id"] = bson.M{"$in": vocabularyIDs}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$vocabulary_id",
+			"count": bson.M{"$sum": bson.M{"$ifNull": bson.A{"$miss_count", 1}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+	if len(vocabularyIDs) == 0 && limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+	}
+
+	rows, err := database.AggregateAll[struct {
+		VocabularyID string `bson:"_id"`
+		Count        int64  `bson:"count"`
+	}](ctx, s.incorrectWords, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.VocabularyID] = row.Count
+	}
+	return counts, nil
+}
+
+// MergeUser moves a guest session's answer totals and incorrect words onto an account, adding them to
+// what the account already has, and returns how many incorrect words were moved. Each guest document is
+// claimed by deleting it before its counts are added, so concurrent merges of the same guest, such as a
+// double-tapped sign-in, add every document once. Documents are moved one at a time rather than in a
+// transaction; running the merge again moves anything an interrupted run left behind, though a run
+// interrupted between claiming and adding a document loses that document's counts.
+func (s *Store) MergeUser(ctx context.Context, fromUserID, toUserID string) (int, error) {
+	var guest models.AnswerStats
+	err := s.answers.FindOneAndDelete(ctx, bson.M{"user_id": fromUserID}).Decode(&guest)
+	switch {
+	case err == mongo.ErrNoDocuments:
+	case err != nil:
+		return 0, err
+	default:
+		update := bson.M{
+			"$inc": bson.M{"total": guest.Total, "correct": guest.Correct, "incorrect": guest.Incorrect},
+			"$max": bson.M{"last_answered_at": guest.LastAnsweredAt},
+			"$set": bson.M{"updated_at": changefeed.Now()},
+		}
+		if _, err := s.answers.UpdateOne(ctx, bson.M{"user_id": toUserID}, update, options.Update().SetUpsert(true)); err != nil {
+			return 0, err
+		}
+	}
+
+	words, err := database.FindAll[models.IncorrectWord](ctx, s.incorrectWords, bson.M{"user_id": fromUserID})
+	if err != nil {
+		return 0, err
+	}
+	moved := 0
+	for _, listed := range words {
+		// Another merge may have claimed the word since it was listed
+		var word models.IncorrectWord
+		err := s.incorrectWords.FindOneAndDelete(ctx, bson.M{"_id": listed.ID, "user_id": fromUserID}).Decode(&word)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return moved, err
+		}
+
+		missCount := word.MissCount
+		if missCount == 0 {
+			missCount = 1
+		}
+		filter := bson.M{"user_id": toUserID, "vocabulary_id": word.VocabularyID}
+		lastMissedAt := word.LastMissedAt
+		if lastMissedAt.IsZero() {
+			lastMissedAt = word.CreatedAt
+		}
+		update := bson.M{
+			"$setOnInsert": bson.M{"_id": primitive.NewObjectID(), "created_at": word.CreatedAt},
+			"$inc":         bson.M{"miss_count": missCount},
+			"$max":         bson.M{"last_missed_at": lastMissedAt},
+			"$set":         bson.M{"updated_at": changefeed.Now()},
+		}
+		if _, err := s.incorrectWords.UpdateOne(ctx, filter, database.BumpVersion(update), options.Update().SetUpsert(true)); err != nil {
+			return moved, err
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+// RecentMisses returns up to limit words the user answered incorrectly, most recently missed first.
+func (s *Store) RecentMisses(ctx context.Context, userID string, limit int) ([]string, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "last_missed_at", Value: -1}, {Key: "created_at", Value: -1}}).SetLimit(int64(limit))
+	words, err := database.FindAll[models.IncorrectWord](ctx, s.incorrectWords, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(words))
+	for i, word := range words {
+		ids[i] = word.VocabularyID
+	}
+	return ids, nil
+}
+
+// IncorrectWordsSince returns the user's incorrect words that changed at or after since; the zero
+// time returns all of them.
+func (s *Store) IncorrectWordsSince(ctx context.Context, userID string, since time.Time) ([]models.IncorrectWord, error) {
+	return database.FindAll[models.IncorrectWord](ctx, s.incorrectWords, changefeed.Filter("user_id", userID, "updated_at", since))
+}
+
+// AnswerStatsSince returns the user's answer totals if they changed at or after since, or nil.
+func (s *Store) AnswerStatsSince(ctx context.Context, userID string, since time.Time) (*models.AnswerStats, error) {
+	var stats models.AnswerStats
+	err := s.answers.FindOne(ctx, changefeed.Filter("user_id", userID, "updated_at", since)).Decode(&stats)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
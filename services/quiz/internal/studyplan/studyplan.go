@@ -0,0 +1,154 @@
+// FILE: services/quiz/internal/studyplan/studyplan.go
+// This package composes a learner's work for the day: the words due for review on the mastery
+// schedule, a capped batch of new words from the next lesson they have not completed, and a
+// review quiz of their most recent misses.
+
+package studyplan
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb_common "wise-owl/gen/proto/common/v1"
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/services/quiz/internal/mastery"
+	"wise-owl/services/quiz/internal/questions"
+	"wise-owl/services/quiz/internal/stats"
+	"wise-owl/services/quiz/internal/unlocks"
+)
+
+const (
+	// MaxReviews caps the due reviews in a day's plan; the rest stay due for the next one
+	MaxReviews = 50
+	// NewWordsPerDay caps the words introduced in a day
+	NewWordsPerDay = 10
+	// ReviewQuizSize is the number of recent misses the review quiz covers
+	ReviewQuizSize = 10
+	// lessonPageSize is the page size used to list a lesson's words, the most SearchVocabulary allows
+	lessonPageSize = 200
+)
+
+// Plan is a day's study work. Each part is empty when there is nothing to do for it.
+type Plan struct {
+	Reviews    []string             `json:"reviews"`              // Vocabulary IDs due for review, most overdue first
+	NewLesson  string               `json:"new_lesson,omitempty"` // The lesson new words come from; absent once every unlocked lesson is complete
+	NewWords   []string             `json:"new_words"`            // Vocabulary IDs not practiced yet, in lesson order
+	ReviewQuiz []questions.Question `json:"review_quiz"`          // Questions about the most recent misses
+}
+
+// Error says which service a plan could not be composed without.
+type Error struct {
+	Service string // "content" or "users"
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s service: %v", e.Service, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Planner composes study plans from the quiz service's own data and the content and users services.
+type Planner struct {
+	mastery       *mastery.Store
+	stats         *stats.Store
+	unlocks       *unlocks.Checker
+	bank          *questions.Bank
+	contentClient pb_content.ContentServiceClient
+}
+
+// NewPlanner creates a planner with its dependencies.
+func NewPlanner(masteryStore *mastery.Store, statsStore *stats.Store, checker *unlocks.Checker, bank *questions.Bank, contentClient pb_content.ContentServiceClient) *Planner {
+	return &Planner{
+		mastery:       masteryStore,
+		stats:         statsStore,
+		unlocks:       checker,
+		bank:          bank,
+		contentClient: contentClient,
+	}
+}
+
+// Build composes userID's plan as of now. Database errors are returned as they are; failures of
+// the other services come back as *Error.
+func (p *Planner) Build(ctx context.Context, userID string, now time.Time) (Plan, error) {
+	plan := Plan{Reviews: []string{}, NewWords: []string{}, ReviewQuiz: []questions.Question{}}
+
+	reviews, err := p.mastery.Due(ctx, userID, now, MaxReviews)
+	if err != nil {
+		return plan, err
+	}
+	plan.Reviews = append(plan.Reviews, reviews...)
+
+	lesson, err := p.unlocks.NextLesson(ctx, userID)
+	if err != nil {
+		return plan, &Error{Service: "users", Err: err}
+	}
+	if lesson != "" {
+		plan.NewLesson = lesson
+		words, err := p.newWords(ctx, userID, lesson)
+		if err != nil {
+			return plan, err
+		}
+		plan.NewWords = append(plan.NewWords, words...)
+	}
+
+	misses, err := p.stats.RecentMisses(ctx, userID, ReviewQuizSize)
+	if err != nil {
+		return plan, err
+	}
+	if len(misses) > 0 {
+		quiz, err := p.bank.Build(ctx, misses, len(misses), questions.Options{})
+		if err != nil {
+			return plan, &Error{Service: "content", Err: err}
+		}
+		plan.ReviewQuiz = append(plan.ReviewQuiz, quiz...)
+	}
+	return plan, nil
+}
+
+// newWords returns up to NewWordsPerDay words of lesson that userID has never practiced
+func (p *Planner) newWords(ctx context.Context, userID, lesson string) ([]string, error) {
+	var ids []string
+	pageToken := ""
+	for {
+		grpcCtx, cancel := ctxutil.GRPC(ctx)
+		page, err := p.contentClient.SearchVocabulary(grpcCtx, &pb_content.SearchVocabularyRequest{
+			Filters:    &pb_content.VocabularyFilters{Lessons: []string{lesson}},
+			Pagination: &pb_common.Pagination{PageSize: lessonPageSize, PageToken: pageToken},
+		})
+		cancel()
+		if err != nil {
+			return nil, &Error{Service: "content", Err: err}
+		}
+		for _, item := range page.Items {
+			ids = append(ids, item.Id)
+		}
+		pageToken = page.GetPageInfo().GetNextPageToken()
+		if pageToken == "" {
+			break
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	practiced, err := p.mastery.Levels(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	fresh := make([]string, 0, NewWordsPerDay)
+	for _, id := range ids {
+		if _, ok := practiced[id]; ok {
+			continue
+		}
+		fresh = append(fresh, id)
+		if len(fresh) == NewWordsPerDay {
+			break
+		}
+	}
+	return fresh, nil
+}
@@ -0,0 +1,139 @@
+// FILE: services/quiz/internal/studysets/community.go
+// Discovery of public study sets: search, likes, and copying a set into your own account.
+// Like and clone counters only ever change through $inc, so concurrent requests cannot lose updates.
+
+package studysets
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"wise-owl/lib/database"
+	"wise-owl/services/quiz/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Sort orders for public set searches.
+const (
+	SortPopular = "popular" // Most liked first, then most cloned
+	SortRecent  = "recent"  // Most recently updated first
+)
+
+// Search selects a page of public sets.
+type Search struct {
+	Query  string // Case-insensitive match on the name or description; empty matches all
+	Sort   string // SortPopular (default) or SortRecent
+	Limit  int
+	Offset int
+}
+
+// SearchPublic returns one page of the public sets matching search, and the total number of matches.
+func (s *Store) SearchPublic(ctx context.Context, search Search) ([]models.StudySet, int64, error) {
+	filter := bson.M{"public": true}
+	if search.Query != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(search.Query), Options: "i"}
+		filter["$or"] = bson.A{bson.M{"name": pattern}, bson.M{"description": pattern}}
+	}
+
+	total, err := s.sets.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sort := bson.D{{Key: "like_count", Value: -1}, {Key: "clone_count", Value: -1}, {Key: "_id", Value: -1}}
+	if search.Sort == SortRecent {
+		sort = bson.D{{Key: "updated_at", Value: -1}, {Key: "_id", Value: -1}}
+	}
+	opts := options.Find().SetSort(sort).SetSkip(int64(search.Offset)).SetLimit(int64(search.Limit))
+	sets, err := database.FindAll[models.StudySet](ctx, s.sets, filter, opts)
+	return sets, total, err
+}
+
+// Clone copies a set the user may see into their account as a new private set. Copies of other
+// users' sets count toward the original's clone count.
+func (s *Store) Clone(ctx context.Context, id, userID string) (models.StudySet, error) {
+	source, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return models.StudySet{}, err
+	}
+
+	clone := models.StudySet{
+		OwnerID:       userID,
+		Name:          source.Name,
+		Description:   source.Description,
+		VocabularyIDs: source.VocabularyIDs,
+		ClonedFrom:    &source.ID,
+	}
+	if err := s.Create(ctx, &clone); err != nil {
+		return models.StudySet{}, err
+	}
+
+	if source.OwnerID != userID {
+		if _, err := s.sets.UpdateOne(ctx, bson.M{"_id": source.ID}, bson.M{"$inc": bson.M{"clone_count": 1}}); err != nil {
+			return clone, err
+		}
+	}
+	return clone, nil
+}
+
+// Like records that the user likes a public set and returns the set. Liking a set twice counts once.
+func (s *Store) Like(ctx context.Context, id, userID string) (models.StudySet, error) {
+	set, err := s.getPublic(ctx, id)
+	if err != nil {
+		return set, err
+	}
+
+	like := models.StudySetLike{SetID: set.ID, UserID: userID, CreatedAt: time.Now().UTC()}
+	err = database.InsertUnique(ctx, s.likes, like)
+	if errors.Is(err, database.ErrDuplicate) {
+		return set, nil
+	}
+	if err != nil {
+		return set, err
+	}
+	return s.incLikes(ctx, set.ID, 1)
+}
+
+// Unlike removes the user's like from a public set and returns the set. Unliking a set the user
+// does not like changes nothing.
+func (s *Store) Unlike(ctx context.Context, id, userID string) (models.StudySet, error) {
+	set, err := s.getPublic(ctx, id)
+	if err != nil {
+		return set, err
+	}
+
+	res, err := s.likes.DeleteOne(ctx, bson.M{"set_id": set.ID, "user_id": userID})
+	if err != nil {
+		return set, err
+	}
+	if res.DeletedCount == 0 {
+		return set, nil
+	}
+	return s.incLikes(ctx, set.ID, -1)
+}
+
+// getPublic returns the public set with the given ID
+func (s *Store) getPublic(ctx context.Context, id string) (models.StudySet, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.StudySet{}, ErrNotFound
+	}
+	return s.findOne(ctx, bson.M{"_id": objectID, "public": true})
+}
+
+// incLikes adds delta to a set's like count and returns the updated set
+func (s *Store) incLikes(ctx context.Context, id primitive.ObjectID, delta int) (models.StudySet, error) {
+	var set models.StudySet
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := s.sets.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"like_count": delta}}, opts).Decode(&set)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return set, ErrNotFound // Deleted meanwhile
+	}
+	return set, err
+}
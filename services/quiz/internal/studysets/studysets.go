@@ -0,0 +1,153 @@
+// FILE: services/quiz/internal/studysets/studysets.go
+// This package stores user-created study sets: named, ordered word lists that can be kept private
+// or shared through a public slug.
+
+package studysets
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"time"
+
+	"wise-owl/lib/database"
+	"wise-owl/services/quiz/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrNotFound is returned for sets that do not exist or that the caller may not see
+var ErrNotFound = errors.New("study set not found")
+
+// slugAlphabet leaves out characters that are easy to confuse when a slug is typed by hand
+const slugAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+const (
+	slugLength = 8
+	// slugAttempts bounds retries after a slug collision, which the unique index reports
+	slugAttempts = 3
+)
+
+// Update holds the fields of a partial update; nil fields are left unchanged.
+type Update struct {
+	Name          *string
+	Description   *string
+	VocabularyIDs []string // Replaces the list when non-nil
+	Public        *bool
+}
+
+// Store reads and writes study sets.
+type Store struct {
+	sets  *mongo.Collection
+	likes *mongo.Collection
+}
+
+// NewStore creates a store over the quiz database.
+func NewStore(db *mongo.Database) *Store {
+	return &Store{sets: db.Collection("study_sets"), likes: db.Collection("study_set_likes")}
+}
+
+// Create saves a new set for its owner and assigns its ID and slug.
+func (s *Store) Create(ctx context.Context, set *models.StudySet) error {
+	now := time.Now().UTC()
+	set.CreatedAt, set.UpdatedAt = now, now
+	for attempt := 0; ; attempt++ {
+		set.ID = primitive.NewObjectID()
+		set.Slug = newSlug()
+		err := database.InsertUnique(ctx, s.sets, set)
+		if !errors.Is(err, database.ErrDuplicate) || attempt == slugAttempts-1 {
+			return err
+		}
+	}
+}
+
+// List returns the owner's sets, most recently updated first.
+func (s *Store) List(ctx context.Context, ownerID string) ([]models.StudySet, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}})
+	return database.FindAll[models.StudySet](ctx, s.sets, bson.M{"owner_id": ownerID}, opts)
+}
+
+// Get returns a set its owner may use, or a public set anyone may use.
+func (s *Store) Get(ctx context.Context, id, userID string) (models.StudySet, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.StudySet{}, ErrNotFound
+	}
+	filter := bson.M{"_id": objectID, "$or": bson.A{bson.M{"owner_id": userID}, bson.M{"public": true}}}
+	return s.findOne(ctx, filter)
+}
+
+// GetShared returns the public set with the given slug.
+func (s *Store) GetShared(ctx context.Context, slug string) (models.StudySet, error) {
+	return s.findOne(ctx, bson.M{"slug": slug, "public": true})
+}
+
+// Update applies a partial update to one of the owner's sets and returns the result.
+func (s *Store) Update(ctx context.Context, id, ownerID string, update Update) (models.StudySet, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.StudySet{}, ErrNotFound
+	}
+
+	set := bson.M{"updated_at": time.Now().UTC()}
+	if update.Name != nil {
+		set["name"] = *update.Name
+	}
+	if update.Description != nil {
+		set["description"] = *update.Description
+	}
+	if update.VocabularyIDs != nil {
+		set["vocabulary_ids"] = update.VocabularyIDs
+	}
+	if update.Public != nil {
+		set["public"] = *update.Public
+	}
+
+	var updated models.StudySet
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = s.sets.FindOneAndUpdate(ctx, bson.M{"_id": objectID, "owner_id": ownerID}, bson.M{"$set": set}, opts).Decode(&updated)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return updated, ErrNotFound
+	}
+	return updated, err
+}
+
+// Delete removes one of the owner's sets.
+func (s *Store) Delete(ctx context.Context, id, ownerID string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrNotFound
+	}
+	res, err := s.sets.DeleteOne(ctx, bson.M{"_id": objectID, "owner_id": ownerID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	_, err = s.likes.DeleteMany(ctx, bson.M{"set_id": objectID})
+	return err
+}
+
+// findOne decodes the set matching filter
+func (s *Store) findOne(ctx context.Context, filter bson.M) (models.StudySet, error) {
+	var set models.StudySet
+	err := s.sets.FindOne(ctx, filter).Decode(&set)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return set, ErrNotFound
+	}
+	return set, err
+}
+
+// newSlug returns a short random share code.
+func newSlug() string {
+	b := make([]byte, slugLength)
+	rand.Read(b)
+	for i := range b {
+		b[i] = slugAlphabet[int(b[i])%len(slugAlphabet)]
+	}
+	return string(b)
+}
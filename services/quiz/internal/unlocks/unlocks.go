@@ -0,0 +1,126 @@
+// FILE: services/quiz/internal/unlocks/unlocks.go
+// This package keeps quizzes to the lessons a learner has unlocked. The users service knows which
+// lessons are unlocked, from the learner's completions and the content service's prerequisites;
+// the content service knows which lesson each word belongs to.
+
+package unlocks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/ctxutil"
+)
+
+// Checker finds the lessons of a quiz's vocabulary that a learner has not unlocked yet, and the
+// lesson they should study next.
+type Checker struct {
+	usersURL      string
+	httpClient    *http.Client
+	contentClient pb_content.ContentServiceClient
+}
+
+// NewChecker creates a checker that asks the users service at usersURL for unlocked lessons.
+func NewChecker(usersURL string, contentClient pb_content.ContentServiceClient) *Checker {
+	return &Checker{
+		usersURL:      usersURL,
+		httpClient:    &http.Client{Timeout: 5 * time.Second},
+		contentClient: contentClient,
+	}
+}
+
+// Locked returns, sorted, the lessons of the given vocabulary that userID has not unlocked.
+// Words the content service does not know, and lessons outside the prerequisite graph, are not locked.
+func (c *Checker) Locked(ctx context.Context, userID string, vocabularyIDs []string) ([]string, error) {
+	unlocked, err := c.unlockedLessons(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	batchCtx, cancel := ctxutil.GRPC(ctx)
+	defer cancel()
+	batch, err := c.contentClient.GetVocabularyBatch(batchCtx, &pb_content.GetVocabularyBatchRequest{VocabularyIds: vocabularyIDs})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var locked []string
+	for _, vocab := range batch.Items {
+		if open, known := unlocked[vocab.Lesson]; known && !open && !seen[vocab.Lesson] {
+			seen[vocab.Lesson] = true
+			locked = append(locked, vocab.Lesson)
+		}
+	}
+	sort.Strings(locked)
+	return locked, nil
+}
+
+// lessonStatus is a lesson as the users service reports it, in study order
+type lessonStatus struct {
+	LessonID  string `json:"lesson_id"`
+	Completed bool   `json:"completed"`
+	Unlocked  bool   `json:"unlocked"`
+}
+
+// NextLesson returns the first lesson in study order that userID has unlocked but not completed,
+// or an empty string when every unlocked lesson is complete.
+func (c *Checker) NextLesson(ctx context.Context, userID string) (string, error) {
+	lessons, err := c.lessons(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	for _, lesson := range lessons {
+		if lesson.Unlocked && !lesson.Completed {
+			return lesson.LessonID, nil
+		}
+	}
+	return "", nil
+}
+
+// unlockedLessons maps every lesson in the prerequisite graph to whether userID has unlocked it
+func (c *Checker) unlockedLessons(ctx context.Context, userID string) (map[string]bool, error) {
+	lessons, err := c.lessons(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	unlocked := make(map[string]bool, len(lessons))
+	for _, lesson := range lessons {
+		unlocked[lesson.LessonID] = lesson.Unlocked
+	}
+	return unlocked, nil
+}
+
+// lessons asks the users service where userID stands on every lesson
+func (c *Checker) lessons(ctx context.Context, userID string) ([]lessonStatus, error) {
+	endpoint := c.usersURL + "/internal/v1/users/" + url.PathEscape(userID) + "/unlocked-lessons"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return nil, fmt.Errorf("users service: status %d: %s", resp.StatusCode, body)
+	}
+
+	var payload struct {
+		Lessons []lessonStatus `json:"lessons"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("users service: decode unlocked lessons: %v", err)
+	}
+	return payload.Lessons, nil
+}
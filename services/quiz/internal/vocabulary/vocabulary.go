@@ -0,0 +1,71 @@
+// FILE: services/quiz/internal/vocabulary/vocabulary.go
+// This package looks up any number of words in the content service. GetVocabularyBatch serves a
+// page of IDs per call, so long lists are split into chunks fetched a few at a time.
+
+package vocabulary
+
+import (
+	"context"
+	"sync"
+
+	pb_common "wise-owl/gen/proto/common/v1"
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/ctxutil"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// chunkSize is the IDs sent per call, below the content service's page size so each chunk
+	// normally takes a single call
+	chunkSize = 500
+	// parallelism caps the calls in flight for one lookup
+	parallelism = 4
+)
+
+// Fetch returns the words with the given IDs by ID. IDs the content service does not know are left
+// out. Each call gets its own gRPC budget within ctx (see lib/ctxutil); the first failure cancels
+// the rest and is returned.
+func Fetch(ctx context.Context, client pb_content.ContentServiceClient, vocabularyIDs []string) (map[string]*pb_content.Vocabulary, error) {
+	items := make(map[string]*pb_content.Vocabulary, len(vocabularyIDs))
+	var mu sync.Mutex
+
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(parallelism)
+	for start := 0; start < len(vocabularyIDs); start += chunkSize {
+		chunk := vocabularyIDs[start:min(start+chunkSize, len(vocabularyIDs))]
+		group.Go(func() error {
+			return fetchChunk(ctx, client, chunk, func(page map[string]*pb_content.Vocabulary) {
+				mu.Lock()
+				defer mu.Unlock()
+				for id, vocab := range page {
+					items[id] = vocab
+				}
+			})
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// fetchChunk looks up one chunk, following page tokens should the service serve it in several pages.
+func fetchChunk(ctx context.Context, client pb_content.ContentServiceClient, chunk []string, add func(map[string]*pb_content.Vocabulary)) error {
+	req := &pb_content.GetVocabularyBatchRequest{VocabularyIds: chunk, Pagination: &pb_common.Pagination{}}
+	for {
+		callCtx, cancel := ctxutil.GRPC(ctx)
+		res, err := client.GetVocabularyBatch(callCtx, req)
+		cancel()
+		if err != nil {
+			return err
+		}
+		add(res.GetItems())
+
+		next := res.GetPageInfo().GetNextPageToken()
+		if next == "" {
+			return nil
+		}
+		req.Pagination.PageToken = next
+	}
+}
@@ -0,0 +1,190 @@
+// FILE: services/status/cmd/main.go
+// Entry point for the Wise Owl Status Service.
+
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/auth"
+	"wise-owl/lib/compress"
+	"wise-owl/lib/config"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/database"
+	"wise-owl/lib/health"
+	"wise-owl/lib/metrics"
+	"wise-owl/lib/preset"
+	"wise-owl/lib/validation"
+	"wise-owl/services/status/internal/handlers"
+	"wise-owl/services/status/internal/seeder"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// defaultComponents matches the service names used in docker-compose.
+const defaultComponents = "Users Service=http://users-service:8080/health/," +
+	"Content Service=http://content-service:8080/health/," +
+	"Quiz Service=http://quiz-service:8080/health/," +
+	"Leaderboard Service=http://leaderboard-service:8080/health/"
+
+// OperatorScope must be granted to a token for it to publish incidents, the same scope the
+// admin service requires
+const OperatorScope = "admin"
+
+func main() {
+	// 1. Load Configuration (supports both local and AWS environments)
+	cfg, err := config.LoadConfig("status")
+	if err != nil {
+		log.Fatalf("FATAL: could not load config: %v", err)
+	}
+	stopMetrics := metrics.Start(cfg.Metrics, "status") // CloudWatch EMF metrics, when EMF_METRICS_ENABLED is set
+	defer stopMetrics()
+
+	dbName := cfg.DB_NAME
+	if dbName == "" {
+		dbName = "status_db"
+	}
+	log.Printf("Configuration loaded. Using database: %s (Type: %s)", dbName, cfg.DB_TYPE)
+
+	// 2. Connect to Database (supports MongoDB and DocumentDB)
+	db, err := database.CreateDatabase(cfg)
+	if err != nil {
+		log.Fatalf("FATAL: Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+	mongoClient := db.GetClient().(*mongo.Client)
+	mongoDatabase := mongoClient.Database(dbName)
+	log.Println("Database connection established.")
+	seeder.SeedDatabase(mongoDatabase)
+
+	// 3. Initialize health checker
+	healthChecker := health.New("Status Service", health.Options{
+		Database: mongoDatabase,
+		AWS:      config.IsAWSEnvironment(), // Deep checks and environment details for ECS
+	})
+
+	// 4. Start polling the health endpoints of every public component
+	components := parseComponents(getEnv("STATUS_COMPONENTS", defaultComponents))
+	aggregator := health.NewAggregator(components, 30*time.Second)
+	aggregatorCtx, stopAggregator := context.WithCancel(context.Background())
+	defer stopAggregator()
+	aggregator.Start(aggregatorCtx)
+	log.Printf("Aggregating health for %d components", len(components))
+
+	// 5. Initialize HTTP Router and Middleware
+	// Route objectives, reported at /health/slo and /metrics: the status page must outlast everything else
+	slos := metrics.NewSLOs("status",
+		metrics.Objective{Method: http.MethodGet, Route: "/api/v1/status", Availability: 0.9995, Latency: 200 * time.Millisecond, LatencyTarget: 0.99},
+	)
+	// Gin mode, request logs, and panic recovery suited to the environment (see lib/preset)
+	router := preset.For(cfg.Environment).Router()
+	router.ContextWithFallback = true // Handlers pass c to Mongo; let it carry the request's deadline
+	router.Use(slos.Middleware())     // Outside apierror.Middleware, so it records the status sent
+	router.Use(apierror.Middleware()) // Trace IDs and the standard error envelope
+	router.Use(metrics.Middleware())  // Request latency for CloudWatch
+	router.Use(compress.Gzip())       // Compress larger text and JSON responses
+	// Overall request deadline, answered with a 504 when it passes
+	router.Use(ctxutil.Timeout(cfg.RequestTimeout, nil))
+	router.Use(validation.SizeLimits(validation.DefaultLimits, nil)) // Reject oversized bodies and arrays before they are decoded
+
+	// Incident curation needs an operator's token, granted the admin scope (skip if Auth0 not configured)
+	var operatorAuth []gin.HandlerFunc
+	if cfg.Auth0Domain != "" && cfg.Auth0Audience != "" {
+		authMiddleware, err := auth.NewTokenMiddleware(auth.ConfigOptions(cfg))
+		if err != nil {
+			log.Fatalf("FATAL: could not set up token validation: %v", err)
+		}
+		operatorAuth = []gin.HandlerFunc{authMiddleware, auth.RequireScope(OperatorScope)}
+		log.Println("Auth0 authentication enabled")
+	} else {
+		log.Println("Authentication disabled for development")
+	}
+
+	statusHandler := handlers.NewStatusHandler(
+		mongoDatabase,
+		aggregator,
+		getEnv("STATUS_PAGE_NAME", "Wise Owl"),
+		getEnv("STATUS_PAGE_URL", ""),
+	)
+
+	// 6. Register health check routes; results are refreshed in the background
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	defer stopHealth()
+	healthChecker.Start(healthCtx)
+	healthChecker.RegisterRoutes(router)
+	slos.RegisterRoutes(router)
+	// Prometheus scrape endpoint for SLOs; the gateway does not route it
+	router.GET("/metrics", metrics.Handler(slos))
+
+	// 7. Define API Routes
+	apiV1 := router.Group("/api/v1")
+	{
+		statusRoutes := apiV1.Group("/status")
+		{
+			// Public, unauthenticated status page API
+			statusRoutes.GET("", statusHandler.GetStatus)
+			statusRoutes.GET("/components", statusHandler.GetComponents)
+			statusRoutes.GET("/summary.json", statusHandler.GetSummary)
+			statusRoutes.GET("/incidents", statusHandler.ListIncidents)
+			statusRoutes.GET("/incidents/:incidentId", statusHandler.GetIncident)
+
+			// Incident curation requires an authenticated operator
+			curation := statusRoutes.Group("/incidents")
+			curation.Use(operatorAuth...)
+			{
+				curation.POST("", statusHandler.CreateIncident)
+				curation.POST("/:incidentId/updates", statusHandler.AddIncidentUpdate)
+			}
+		}
+	}
+
+	// 8. Start HTTP Server with Graceful Shutdown
+	srv := &http.Server{Addr: ":" + cfg.ServerPort, Handler: router}
+	go func() {
+		log.Printf("Status HTTP server listening on port %s", cfg.ServerPort)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("FATAL: listen: %s\n", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down Status Service...")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv.Shutdown(ctx)
+}
+
+// parseComponents reads a comma-separated list of "Name=URL" pairs
+func parseComponents(raw string) []health.Component {
+	var components []health.Component
+	for _, entry := range strings.Split(raw, ",") {
+		name, url, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || name == "" || url == "" {
+			if entry != "" {
+				log.Printf("WARN: Ignoring malformed status component %q", entry)
+			}
+			continue
+		}
+		components = append(components, health.Component{Name: strings.TrimSpace(name), URL: strings.TrimSpace(url)})
+	}
+	return components
+}
+
+// getEnv gets environment variable with fallback
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
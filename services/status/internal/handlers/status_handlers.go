@@ -0,0 +1,321 @@
+// FILE: services/status/internal/handlers/status_handlers.go
+// This package contains the public status page API and the incident curation endpoints.
+
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/health"
+	"wise-owl/lib/validation"
+	"wise-owl/services/status/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// StatusHandler holds the incident collection and the component health aggregator.
+type StatusHandler struct {
+	incidents  *mongo.Collection
+	aggregator *health.Aggregator
+	pageName   string
+	pageURL    string
+}
+
+// NewStatusHandler creates a new handler with its dependencies.
+func NewStatusHandler(db *mongo.Database, aggregator *health.Aggregator, pageName, pageURL string) *StatusHandler {
+	return &StatusHandler{
+		incidents:  db.Collection("incidents"),
+		aggregator: aggregator,
+		pageName:   pageName,
+		pageURL:    pageURL,
+	}
+}
+
+// GetStatus returns the overall status, every component, and all unresolved incidents.
+func (h *StatusHandler) GetStatus(c *gin.Context) {
+	active, err := h.findIncidents(c, bson.M{"status": bson.M{"$ne": models.IncidentResolved}}, 0)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     h.aggregator.OverallStatus(),
+		"components": h.aggregator.States(),
+		"incidents":  active,
+		"timestamp":  time.Now().UTC(),
+	})
+}
+
+// GetComponents returns the latest probe result for every component.
+func (h *StatusHandler) GetComponents(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"components": h.aggregator.States()})
+}
+
+// GetSummary returns a summary document in the shape consumed by Statuspage-compatible frontends.
+func (h *StatusHandler) GetSummary(c *gin.Context) {
+	// Unresolved incidents plus anything resolved in the last week, like hosted status pages show.
+	since := time.Now().UTC().AddDate(0, 0, -7)
+	filter := bson.M{"$or": bson.A{
+		bson.M{"status": bson.M{"$ne": models.IncidentResolved}},
+		bson.M{"resolved_at": bson.M{"$gte": since}},
+	}}
+	incidents, err := h.findIncidents(c, filter, 0)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	states := h.aggregator.States()
+	updatedAt := time.Time{}
+	components := make([]gin.H, 0, len(states))
+	for i, state := range states {
+		if state.LastChecked.After(updatedAt) {
+			updatedAt = state.LastChecked
+		}
+		components = append(components, gin.H{
+			"id":         componentID(state.Name),
+			"name":       state.Name,
+			"status":     state.Status,
+			"position":   i + 1,
+			"updated_at": state.LastChanged,
+		})
+	}
+
+	feedIncidents := make([]gin.H, 0, len(incidents))
+	for _, incident := range incidents {
+		if incident.UpdatedAt.After(updatedAt) {
+			updatedAt = incident.UpdatedAt
+		}
+		updates := make([]gin.H, 0, len(incident.Updates))
+		for _, update := range incident.Updates {
+			updates = append(updates, gin.H{
+				"id":          update.ID.Hex(),
+				"incident_id": incident.ID.Hex(),
+				"status":      update.Status,
+				"body":        update.Body,
+				"created_at":  update.CreatedAt,
+				"display_at":  update.CreatedAt,
+			})
+		}
+		feedIncidents = append(feedIncidents, gin.H{
+			"id":               incident.ID.Hex(),
+			"name":             incident.Title,
+			"status":           incident.Status,
+			"impact":           incident.Impact,
+			"created_at":       incident.CreatedAt,
+			"updated_at":       incident.UpdatedAt,
+			"resolved_at":      incident.ResolvedAt,
+			"incident_updates": updates,
+			"components":       incident.Components,
+		})
+	}
+
+	indicator, description := statusIndicator(h.aggregator.OverallStatus())
+	c.JSON(http.StatusOK, gin.H{
+		"page": gin.H{
+			"id":         componentID(h.pageName),
+			"name":       h.pageName,
+			"url":        h.pageURL,
+			"updated_at": updatedAt,
+		},
+		"status": gin.H{
+			"indicator":   indicator,
+			"description": description,
+		},
+		"components":             components,
+		"incidents":              feedIncidents,
+		"scheduled_maintenances": []gin.H{},
+	})
+}
+
+// ListIncidents returns the most recent incidents, newest first.
+func (h *StatusHandler) ListIncidents(c *gin.Context) {
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	if err != nil || limit <= 0 || limit > 100 {
+		c.Error(apierror.BadRequest("invalid_limit", "Limit must be between 1 and 100."))
+		return
+	}
+
+	incidents, err := h.findIncidents(c, bson.M{}, limit)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"incidents": incidents})
+}
+
+// GetIncident returns a single incident with its full update history.
+func (h *StatusHandler) GetIncident(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("incidentId"))
+	if err != nil {
+		c.Error(apierror.BadRequest("invalid_incident_id", "Incident ID must be a 24-character hex ObjectID."))
+		return
+	}
+
+	var incident models.Incident
+	if err := h.incidents.FindOne(c, bson.M{"_id": id}).Decode(&incident); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.Error(apierror.NotFound("not_found", "Incident not found."))
+			return
+		}
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+// CreateIncident opens a new incident with its first update.
+func (h *StatusHandler) CreateIncident(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req struct {
+		Title      string   `json:"title" binding:"required"`
+		Status     string   `json:"status" binding:"omitempty,oneof=investigating identified monitoring resolved"`
+		Impact     string   `json:"impact" binding:"omitempty,oneof=none minor major critical"`
+		Components []string `json:"components"`
+		Message    string   `json:"message" binding:"required"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if req.Status == "" {
+		req.Status = models.IncidentInvestigating
+	}
+	if req.Impact == "" {
+		req.Impact = models.ImpactMinor
+	}
+	if req.Components == nil {
+		req.Components = []string{}
+	}
+
+	now := time.Now().UTC()
+	createdBy, _ := userID.(string)
+	incident := models.Incident{
+		ID:         primitive.NewObjectID(),
+		Title:      req.Title,
+		Status:     req.Status,
+		Impact:     req.Impact,
+		Components: req.Components,
+		Updates: []models.IncidentUpdate{{
+			ID:        primitive.NewObjectID(),
+			Status:    req.Status,
+			Body:      req.Message,
+			CreatedAt: now,
+		}},
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if req.Status == models.IncidentResolved {
+		incident.ResolvedAt = &now
+	}
+
+	if _, err := h.incidents.InsertOne(c, incident); err != nil {
+		c.Error(apierror.Internal("create_failed", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, incident)
+}
+
+// AddIncidentUpdate posts a progress note on an incident and moves it to the given status.
+func (h *StatusHandler) AddIncidentUpdate(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("incidentId"))
+	if err != nil {
+		c.Error(apierror.BadRequest("invalid_incident_id", "Incident ID must be a 24-character hex ObjectID."))
+		return
+	}
+
+	var req struct {
+		Status  string `json:"status" binding:"required,oneof=investigating identified monitoring resolved"`
+		Message string `json:"message" binding:"required"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	now := time.Now().UTC()
+	update := models.IncidentUpdate{
+		ID:        primitive.NewObjectID(),
+		Status:    req.Status,
+		Body:      req.Message,
+		CreatedAt: now,
+	}
+
+	set := bson.M{"status": req.Status, "updated_at": now}
+	updateDoc := bson.M{
+		"$set": set,
+		// Keep the newest update first so readers don't have to sort.
+		"$push": bson.M{"updates": bson.M{"$each": bson.A{update}, "$position": 0}},
+	}
+	if req.Status == models.IncidentResolved {
+		set["resolved_at"] = now
+	} else {
+		// An update that is not a resolution reopens a resolved incident
+		updateDoc["$unset"] = bson.M{"resolved_at": ""}
+	}
+
+	result, err := h.incidents.UpdateOne(c, bson.M{"_id": id}, updateDoc)
+	if err != nil {
+		c.Error(apierror.Internal("update_failed", err))
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.Error(apierror.NotFound("not_found", "Incident not found."))
+		return
+	}
+
+	c.JSON(http.StatusCreated, update)
+}
+
+// findIncidents loads incidents matching filter, newest first. A limit of 0 means no limit.
+func (h *StatusHandler) findIncidents(c *gin.Context, filter interface{}, limit int64) ([]models.Incident, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+
+	cursor, err := h.incidents.Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	incidents := []models.Incident{}
+	if err := cursor.All(c, &incidents); err != nil {
+		return nil, err
+	}
+	return incidents, nil
+}
+
+// statusIndicator maps the aggregated component status to a page-level indicator and description.
+func statusIndicator(status health.ComponentStatus) (string, string) {
+	switch status {
+	case health.ComponentMajorOutage:
+		return "critical", "Major System Outage"
+	case health.ComponentPartialOutage:
+		return "major", "Partial System Outage"
+	case health.ComponentDegraded:
+		return "minor", "Degraded Performance"
+	default:
+		return "none", "All Systems Operational"
+	}
+}
+
+// componentID derives a stable identifier from a display name (e.g., "Quiz Service" -> "quiz-service").
+func componentID(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "-")
+}
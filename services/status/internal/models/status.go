@@ -0,0 +1,47 @@
+// FILE: services/status/internal/models/status.go
+
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Incident lifecycle states, matching the vocabulary used by status-page frontends.
+const (
+	IncidentInvestigating = "investigating"
+	IncidentIdentified    = "identified"
+	IncidentMonitoring    = "monitoring"
+	IncidentResolved      = "resolved"
+)
+
+// Incident impact levels.
+const (
+	ImpactNone     = "none"
+	ImpactMinor    = "minor"
+	ImpactMajor    = "major"
+	ImpactCritical = "critical"
+)
+
+// Incident is a manually curated outage report shown on the status page.
+type Incident struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Title      string             `json:"title" bson:"title"`
+	Status     string             `json:"status" bson:"status"`
+	Impact     string             `json:"impact" bson:"impact"`
+	Components []string           `json:"components" bson:"components"` // Names of the affected components
+	Updates    []IncidentUpdate   `json:"updates" bson:"updates"`       // Newest update first
+	CreatedBy  string             `json:"-" bson:"created_by"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at" bson:"updated_at"`
+	ResolvedAt *time.Time         `json:"resolved_at,omitempty" bson:"resolved_at,omitempty"`
+}
+
+// IncidentUpdate is a single progress note posted on an incident.
+type IncidentUpdate struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	Status    string             `json:"status" bson:"status"`
+	Body      string             `json:"body" bson:"body"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,30 @@
+// FILE: services/status/internal/seeder/seeder.go
+
+package seeder
+
+import (
+	"context"
+	"log"
+
+	"wise-owl/lib/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Indexes lists every index the status service relies on.
+var Indexes = []database.Index{
+	// Incident lists are filtered by status and shown newest first
+	{Collection: "incidents", Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+	{Collection: "incidents", Keys: bson.D{{Key: "created_at", Value: -1}}},
+}
+
+// SeedDatabase ensures the declared indexes exist.
+// There is no seed data; incidents are created by operators.
+func SeedDatabase(db *mongo.Database) {
+	if err := database.EnsureIndexes(context.Background(), db, Indexes); err != nil {
+		log.Printf("WARN: Failed to ensure status indexes: %v", err)
+	}
+
+	log.Println("Status service initialized successfully")
+}
@@ -0,0 +1,18 @@
+// FILE: services/users/cmd/backup.go
+// Shared by main.go and main_aws.go
+
+package main
+
+import (
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/feedback"
+	"wise-owl/lib/webhooks"
+	"wise-owl/services/users/internal/subscriptions"
+)
+
+// backupCollections are the users service's collections of user data, exported by lib/backup.
+// Webhook deliveries are a retry queue and are left out.
+var backupCollections = []string{
+	"users", "activity", "lesson_completions", "favorites",
+	subscriptions.PurchasesCollection, feedback.Collection, webhooks.Collection, changefeed.TombstoneCollection,
+}
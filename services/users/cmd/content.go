@@ -0,0 +1,22 @@
+// FILE: services/users/cmd/content.go
+// Shared by main.go and main_aws.go
+
+package main
+
+import (
+	"os"
+
+	"wise-owl/lib/config"
+)
+
+// getContentServiceURL returns the appropriate content service URL based on environment
+func getContentServiceURL() string {
+	if url := os.Getenv("CONTENT_SERVICE_URL"); url != "" {
+		return url
+	}
+	if config.IsAWSEnvironment() {
+		// Default for ECS service discovery
+		return "content-service.wise-owl-cluster.local:50052"
+	}
+	return "content-service:50052"
+}
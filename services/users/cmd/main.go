@@ -10,28 +10,60 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	pb_content "wise-owl/gen/proto/content/v1"
+	pb_quiz "wise-owl/gen/proto/quiz/v1"
+	pb_users "wise-owl/gen/proto/users/v1"
+	"wise-owl/lib/apierror"
 	"wise-owl/lib/auth"
+	"wise-owl/lib/backup"
+	"wise-owl/lib/compress"
 	"wise-owl/lib/config"
+	"wise-owl/lib/ctxutil"
 	"wise-owl/lib/database"
+	"wise-owl/lib/events"
+	"wise-owl/lib/feedback"
+	"wise-owl/lib/grpcdebug"
+	"wise-owl/lib/grpcutil"
 	"wise-owl/lib/health"
+	"wise-owl/lib/jobs"
+	"wise-owl/lib/metrics"
+	"wise-owl/lib/openapi"
+	"wise-owl/lib/preset"
+	"wise-owl/lib/resilience"
+	"wise-owl/lib/romanize"
+	"wise-owl/lib/storage"
+	"wise-owl/lib/usage"
+	"wise-owl/lib/validation"
+	"wise-owl/lib/webhooks"
+	"wise-owl/services/users/internal/apidocs"
+	users_grpc "wise-owl/services/users/internal/grpc"
 	"wise-owl/services/users/internal/handlers"
+	"wise-owl/services/users/internal/seeder"
+	"wise-owl/services/users/internal/subscriptions"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
 	// 1. Load Configuration (supports both local and AWS environments)
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadConfig("users")
 	if err != nil {
 		log.Fatalf("FATAL: could not load config: %v", err)
 	}
+	stopMetrics := metrics.Start(cfg.Metrics, "users") // CloudWatch EMF metrics, when EMF_METRICS_ENABLED is set
+	defer stopMetrics()
 
 	// 2. Validate Auth0 configuration (optional for development)
 	if cfg.Auth0Domain == "" || cfg.Auth0Audience == "" {
@@ -45,48 +77,63 @@ func main() {
 	log.Printf("Configuration loaded. Using database: %s (Type: %s)", dbName, cfg.DB_TYPE)
 
 	// 3. Connect to Database (supports MongoDB and DocumentDB)
-	db := database.CreateDatabaseSingleton(cfg)
+	db, err := database.CreateDatabase(cfg)
+	if err != nil {
+		log.Fatalf("FATAL: Failed to connect to database: %v", err)
+	}
+	defer db.Close()
 	userCollection := db.GetCollection(dbName, "users")
 	log.Println("Database connection established.")
 
-	// 4. Initialize health checker (choose based on environment)
-	var healthChecker interface {
-		RegisterRoutes(*gin.Engine)
-		Handler() gin.HandlerFunc
-		ReadyHandler() gin.HandlerFunc
-	}
-
-	// Use AWS health checker if running in AWS environment
-	if config.IsAWSEnvironment() {
-		log.Println("AWS environment detected, using enhanced health checks")
-		if mongoClient, ok := db.GetClient().(*mongo.Client); ok {
-			mongoDatabase := mongoClient.Database(dbName)
-			awsHealthChecker := health.NewAWSHealthChecker("Users Service", mongoDatabase)
-			healthChecker = awsHealthChecker
-		} else {
-			log.Println("WARNING: Could not get mongo client for AWS health checker, falling back to simple health checker")
-			simpleHealthChecker := health.NewSimpleHealthChecker("Users Service")
-			if mongoClient, ok := db.GetClient().(*mongo.Client); ok {
-				simpleHealthChecker.SetMongoClient(mongoClient, dbName)
-			}
-			healthChecker = simpleHealthChecker
-		}
-	} else {
-		log.Println("Local environment detected, using simple health checks")
-		simpleHealthChecker := health.NewSimpleHealthChecker("Users Service")
-		if mongoClient, ok := db.GetClient().(*mongo.Client); ok {
-			simpleHealthChecker.SetMongoClient(mongoClient, dbName)
-		}
-		healthChecker = simpleHealthChecker
+	// Create indexes
+	var mongoDatabase *mongo.Database
+	if mongoClient, ok := db.GetClient().(*mongo.Client); ok {
+		mongoDatabase = mongoClient.Database(dbName)
+		seeder.SeedDatabase(mongoDatabase)
 	}
 
+	// Scheduled exports of user data; `users restore-backup` restores one and exits (see lib/backup)
+	backups, err := backup.New(context.Background(), cfg.Backup, "users", mongoDatabase, backupCollections)
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
+	if backup.Requested() {
+		os.Exit(backups.RunCommand(context.Background(), os.Args[2:]))
+	}
+
+	// 4. Initialize health checker
+	healthChecker := health.New("Users Service", health.Options{
+		Database: mongoDatabase,
+		AWS:      config.IsAWSEnvironment(), // Deep checks and environment details for ECS
+	})
+
 	// 5. Initialize HTTP Router and Middleware
-	router := gin.Default()
+	// Route objectives (see slo.go), reported at /health/slo and /metrics
+	slos := metrics.NewSLOs("users", sloObjectives...)
+	// Gin mode, request logs, and panic recovery suited to the environment (see lib/preset)
+	router := preset.For(cfg.Environment).Router()
+	router.ContextWithFallback = true // Handlers pass c to Mongo; let it carry the request's deadline
+	router.Use(slos.Middleware())     // Outside apierror.Middleware, so it records the status sent
+	router.Use(apierror.Middleware()) // Trace IDs and the standard error envelope
+	router.Use(metrics.Middleware())  // Request latency for CloudWatch
+	router.Use(compress.Gzip())       // Compress larger text and JSON responses
+	router.Use(romanize.Middleware()) // Romaji in the style the caller sends in X-Romanization
+	// Overall request deadline, answered with a 504 when it passes
+	router.Use(ctxutil.Timeout(cfg.RequestTimeout, map[string]time.Duration{
+		"/api/v1/users/me/avatar": 30 * time.Second, // Uploads from slow connections
+	}))
+	// Reject oversized bodies and arrays before they are decoded; avatar uploads limit their own size
+	router.Use(validation.SizeLimits(validation.DefaultLimits, map[string]validation.Limits{
+		"/api/v1/users/me/avatar": {MaxBytes: validation.Unlimited},
+	}))
 
 	// Initialize auth middleware (skip if Auth0 not configured)
 	var authMiddleware gin.HandlerFunc
 	if cfg.Auth0Domain != "" && cfg.Auth0Audience != "" {
-		authMiddleware = auth.EnsureValidToken(cfg.Auth0Domain, cfg.Auth0Audience)
+		authMiddleware, err = auth.NewTokenMiddleware(auth.ConfigOptions(cfg))
+		if err != nil {
+			log.Fatalf("FATAL: could not set up token validation: %v", err)
+		}
 		log.Println("Auth0 authentication enabled")
 	} else {
 		// No-op middleware for development
@@ -96,29 +143,216 @@ func main() {
 		log.Println("Authentication disabled for development")
 	}
 
-	// 6. Initialize user handler
+	// 6. Initialize avatar storage and the user handler
+	avatarStore, err := storage.New(context.Background(), cfg.Storage)
+	if err != nil {
+		log.Fatalf("FATAL: could not initialize storage: %v", err)
+	}
+	if localStore, ok := avatarStore.(*storage.LocalStore); ok {
+		// In development avatars are served by this service (S3 serves them in AWS)
+		router.GET(storage.LocalMediaPath+"/*filepath", gin.WrapH(http.StripPrefix(storage.LocalMediaPath, localStore.Handler())))
+	}
+
+	// Onboarding reads the verified email and profile from Auth0 when M2M credentials are configured
+	var identities handlers.IdentityProvider
+	if cfg.Auth0Domain != "" && cfg.Auth0ClientID != "" && cfg.Auth0ClientSecret != "" {
+		identities = auth.NewManagementClient(cfg.Auth0Domain, cfg.Auth0ClientID, cfg.Auth0ClientSecret)
+		log.Println("Auth0 Management API sync enabled for onboarding")
+	} else {
+		log.Println("WARN: AUTH0_M2M_CLIENT_ID/AUTH0_M2M_CLIENT_SECRET not set; onboarding trusts the client-provided email")
+	}
+
+	// Lesson completions and streak milestones go to users' and apps' webhooks; failed deliveries
+	// are retried on one instance at a time
+	dispatcher := webhooks.NewDispatcher(mongoDatabase)
+	scheduler := jobs.NewScheduler(jobs.NewMongoLocker(mongoDatabase.Collection(jobs.LeaseCollection)))
+	if err := scheduler.Add(jobs.Job{
+		Name:     "webhook-retries",
+		Schedule: webhooks.RetrySchedule,
+		Run:      dispatcher.RetryDue,
+	}); err != nil {
+		log.Fatalf("FATAL: Failed to schedule webhook retries: %v", err)
+	}
+	if cfg.Backup.Enabled {
+		if err := scheduler.Add(backups.Job()); err != nil {
+			log.Fatalf("FATAL: Failed to schedule backups: %v", err)
+		}
+	}
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	scheduler.Start(schedulerCtx)
+
 	var userHandler *handlers.UserHandler
 	if mongoCol, ok := userCollection.(*database.MongoCollection); ok {
-		userHandler = handlers.NewUserHandler(mongoCol.Collection)
+		userHandler = handlers.NewUserHandler(mongoCol.Collection, avatarStore, identities, dispatcher)
 	} else {
 		log.Fatal("FATAL: Failed to get mongo collection from database interface")
 	}
 
-	// 7. Register health check routes
+	// Lesson completions are checked against the content service's lessons over gRPC
+	contentServiceURL := getContentServiceURL()
+	contentBreaker := resilience.NewCircuitBreaker("content-grpc", resilience.Options{
+		IsFailure: resilience.IsGRPCFailure,
+	})
+	dialOpts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, resilience.DialOptions(contentBreaker)...)
+	dialOpts = append(dialOpts, grpcdebug.DialOptions(cfg.GRPCDebugLog)...)
+	dialOpts = append(dialOpts, grpcutil.ClientOptions()...)
+	conn, err := grpc.Dial(grpcutil.ClientTarget(contentServiceURL), dialOpts...)
+	if err != nil {
+		log.Fatalf("Did not connect to content-service: %v", err)
+	}
+	defer conn.Close()
+	log.Printf("Successfully connected to content-service gRPC at %s", contentServiceURL)
+
+	// Only lesson completion and favorites need content, so it does not take the users service out of rotation
+	if err := healthChecker.AddDependency(health.DependencyConfig{
+		Name:      "content-grpc",
+		CheckType: health.CheckGRPC,
+		Target:    contentServiceURL,
+		Conn:      conn,
+		Breaker:   contentBreaker,
+	}); err != nil {
+		log.Printf("WARN: Failed to register content-service health dependency: %v", err)
+	}
+	contentClient := pb_content.NewContentServiceClient(conn)
+	lessonHandler := handlers.NewLessonHandler(mongoDatabase.Collection("lesson_completions"), contentClient, dispatcher)
+	favoriteHandler := handlers.NewFavoriteHandler(mongoDatabase.Collection("favorites"), contentClient)
+	feedbackHandler := handlers.NewFeedbackHandler(feedback.NewStore(mongoDatabase), contentClient)
+	syncHandler := handlers.NewSyncHandler(mongoDatabase)
+	integrationHandler := handlers.NewIntegrationHandler(mongoDatabase)
+
+	// Mastery levels are kept by the quiz service, which sees every answer and review
+	quizServiceURL := getQuizServiceURL()
+	quizBreaker := resilience.NewCircuitBreaker("quiz-grpc", resilience.Options{
+		IsFailure: resilience.IsGRPCFailure,
+	})
+	quizDialOpts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, resilience.DialOptions(quizBreaker)...)
+	quizDialOpts = append(quizDialOpts, grpcdebug.DialOptions(cfg.GRPCDebugLog)...)
+	quizDialOpts = append(quizDialOpts, grpcutil.ClientOptions()...)
+	quizConn, err := grpc.Dial(grpcutil.ClientTarget(quizServiceURL), quizDialOpts...)
+	if err != nil {
+		log.Fatalf("Did not connect to quiz-service: %v", err)
+	}
+	defer quizConn.Close()
+	log.Printf("Successfully connected to quiz-service gRPC at %s", quizServiceURL)
+	if err := healthChecker.AddDependency(health.DependencyConfig{
+		Name:      "quiz-grpc",
+		CheckType: health.CheckGRPC,
+		Target:    quizServiceURL,
+		Conn:      quizConn,
+		Breaker:   quizBreaker,
+	}); err != nil {
+		log.Printf("WARN: Failed to register quiz-service health dependency: %v", err)
+	}
+	quizClient := pb_quiz.NewQuizServiceClient(quizConn)
+	masteryHandler := handlers.NewMasteryHandler(quizClient, contentClient)
+	dashboardHandler := handlers.NewDashboardHandler(mongoDatabase.Collection("users"), mongoDatabase.Collection("lesson_completions"), quizClient, contentClient)
+
+	// 7. Register health check routes; results are refreshed in the background
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	defer stopHealth()
+	healthChecker.Start(healthCtx)
 	healthChecker.RegisterRoutes(router)
+	slos.RegisterRoutes(router)
+
+	// Serve the API contract (and Swagger UI in development)
+	openapi.RegisterRoutes(router, apidocs.Spec, cfg.SwaggerUI)
+
+	// Store subscriptions decide users' tiers, which other services read over gRPC
+	subscriptionStore := subscriptions.NewStore(mongoDatabase)
+	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionStore, subscriptions.VerifiersFromEnv())
+
+	grpcPort := cfg.GRPCPort
+	if grpcPort == "" {
+		grpcPort = "50051" // Default for users service
+	}
+
+	// Logging, metrics, panic recovery, and message size limits for every gRPC call
+	grpcMetrics := grpcutil.NewMetrics()
+	serverOpts := append(grpcutil.ServerOptions(grpcutil.Options{Metrics: grpcMetrics}), grpcdebug.ServerOptions(cfg.GRPCDebugLog)...)
+
+	go func() {
+		lis, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			log.Fatalf("FATAL: Failed to listen for gRPC: %v", err)
+		}
+		s := grpc.NewServer(serverOpts...)
+		pb_users.RegisterUsersServiceServer(s, users_grpc.NewServer(subscriptionStore))
+		// Standard gRPC health service, probed by services that depend on users
+		healthpb.RegisterHealthServer(s, grpchealth.NewServer())
+		preset.For(cfg.Environment).RegisterReflection(s) // grpcurl in development
+
+		log.Printf("Users gRPC server listening at %v", lis.Addr())
+		if err := s.Serve(lis); err != nil {
+			log.Fatalf("FATAL: Failed to serve gRPC: %v", err)
+		}
+	}()
+	router.GET("/metrics", metrics.Handler(grpcMetrics, slos))
+
+	// API calls are counted per user and day, and held to the daily limit of the user's tier
+	limits := usage.LimitsFromEnv()
+	usageStore := usage.NewStore(mongoDatabase)
+	usageHandler := handlers.NewUsageHandler(usageStore, subscriptionStore, limits)
+	meter := usage.NewMeter(usageStore, "users", subscriptionStore, limits)
 
 	// 8. Define API Routes
 	apiV1 := router.Group("/api/v1")
 	{
 		userRoutes := apiV1.Group("/users")
-		// Apply auth middleware to all user routes
-		userRoutes.Use(authMiddleware)
+		// Apply auth middleware to all user routes; unverified accounts may only set up or delete their profile
+		userRoutes.Use(authMiddleware, auth.RequireVerifiedEmail(auth.VerifiedEmailOptions{
+			Lookup: identities,
+			Exempt: []string{
+				"POST /api/v1/users/onboarding",
+				"POST /api/v1/users/me/identities/link",
+				"DELETE /api/v1/users/me",
+			},
+		}), meter.Middleware())
 		{
 			userRoutes.POST("/onboarding", userHandler.OnboardUser)
 			userRoutes.GET("/me/profile", userHandler.GetUserProfile)
 			userRoutes.PATCH("/me/profile", userHandler.UpdateUserProfile)
 			userRoutes.DELETE("/me", userHandler.DeleteUserAccount)
+			userRoutes.POST("/me/avatar", userHandler.UploadAvatar)
+			userRoutes.POST("/me/identities/link", userHandler.LinkIdentity)
+			userRoutes.GET("/me/streak", userHandler.GetStreak)
+			userRoutes.POST("/me/activity", userHandler.RecordActivity)
+			userRoutes.GET("/me/lessons", lessonHandler.GetCompletedLessons)
+			userRoutes.POST("/me/lessons/:lessonId/complete", lessonHandler.CompleteLesson)
+			userRoutes.GET("/me/unlocked-lessons", lessonHandler.GetUnlockedLessons)
+			userRoutes.GET("/me/mastery", masteryHandler.GetMastery)
+			userRoutes.GET("/me/favorites", favoriteHandler.GetFavorites)
+			userRoutes.POST("/me/favorites/:vocabId", favoriteHandler.AddFavorite)
+			userRoutes.DELETE("/me/favorites/:vocabId", favoriteHandler.RemoveFavorite)
+			userRoutes.GET("/me/webhooks", integrationHandler.GetWebhooks)
+			userRoutes.POST("/me/webhooks", integrationHandler.CreateWebhook)
+			userRoutes.DELETE("/me/webhooks/:webhookId", integrationHandler.DeleteWebhook)
+			userRoutes.GET("/me/webhooks/:webhookId/deliveries", integrationHandler.GetWebhookDeliveries)
+			userRoutes.GET("/me/usage", usageHandler.GetUsage)
+			userRoutes.GET("/me/subscription", subscriptionHandler.GetSubscription)
+			userRoutes.POST("/me/subscription/purchases", subscriptionHandler.RecordPurchase)
 		}
+
+		// Everything the client's home screen shows, in one call
+		apiV1.GET("/dashboard", authMiddleware, auth.RequireVerifiedEmail(auth.VerifiedEmailOptions{Lookup: identities}), meter.Middleware(), dashboardHandler.GetDashboard)
+
+		// Feedback and content error reports, triaged through the admin service
+		apiV1.POST("/feedback", authMiddleware, auth.RequireVerifiedEmail(auth.VerifiedEmailOptions{Lookup: identities}), meter.Middleware(), feedbackHandler.SubmitFeedback)
+	}
+
+	// Auth0 Actions push user lifecycle events here; requests are authenticated by their HMAC signature
+	if cfg.Auth0WebhookSecret != "" {
+		apiV1.POST("/webhooks/auth0", auth.VerifyWebhook(cfg.Auth0WebhookSecret), userHandler.HandleAuth0Webhook)
+	} else {
+		log.Println("WARN: AUTH0_WEBHOOK_SECRET not set; the Auth0 webhook endpoint is disabled")
+	}
+
+	// Internal event ingestion and lookups for other services (not routed through the gateway)
+	internal := router.Group("/internal/v1")
+	{
+		internal.POST("/events", events.Handler(userHandler.HandleEvent))
+		internal.GET("/users/:userId/unlocked-lessons", lessonHandler.GetUserUnlockedLessons)
+		internal.GET("/users/:userId/changes", syncHandler.GetUserChanges)
 	}
 
 	// 9. Start HTTP Server with Graceful Shutdown
@@ -20,13 +20,38 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
+	pb_content "wise-owl/gen/proto/content/v1"
+	pb_quiz "wise-owl/gen/proto/quiz/v1"
+	pb_users "wise-owl/gen/proto/users/v1"
+	"wise-owl/lib/apierror"
 	"wise-owl/lib/auth"
+	"wise-owl/lib/backup"
+	"wise-owl/lib/compress"
 	"wise-owl/lib/config"
+	"wise-owl/lib/ctxutil"
 	"wise-owl/lib/database"
+	"wise-owl/lib/events"
+	"wise-owl/lib/feedback"
+	"wise-owl/lib/grpcutil"
 	"wise-owl/lib/health"
+	"wise-owl/lib/jobs"
+	"wise-owl/lib/metrics"
+	"wise-owl/lib/openapi"
+	"wise-owl/lib/preset"
+	"wise-owl/lib/resilience"
+	"wise-owl/lib/romanize"
+	"wise-owl/lib/storage"
+	"wise-owl/lib/usage"
+	"wise-owl/lib/webhooks"
+	"wise-owl/services/users/internal/apidocs"
+	users_grpc "wise-owl/services/users/internal/grpc"
 	"wise-owl/services/users/internal/handlers"
 	"wise-owl/services/users/internal/seeder"
+	"wise-owl/services/users/internal/subscriptions"
 )
 
 func main() {
@@ -35,10 +60,10 @@ func main() {
 	var err error
 
 	if os.Getenv("AWS_EXECUTION_ENV") != "" {
-		cfg, err = config.LoadConfigAWS()
+		cfg, err = config.LoadConfigAWS("users")
 	} else {
 		// Convert legacy config to new format for backward compatibility
-		legacyCfg, legacyErr := config.LoadConfig()
+		legacyCfg, legacyErr := config.LoadConfig("users")
 		if legacyErr != nil {
 			log.Fatalf("Failed to load configuration: %v", legacyErr)
 		}
@@ -46,30 +71,38 @@ func main() {
 			Port:        legacyCfg.ServerPort,
 			GRPCPort:    legacyCfg.GRPCPort,
 			LogLevel:    legacyCfg.LogLevel,
-			Environment: os.Getenv("ENVIRONMENT"),
+			Environment: legacyCfg.Environment,
 			Database: config.DatabaseConfig{
-				URI:  legacyCfg.MONGODB_URI,
-				Name: legacyCfg.DB_NAME,
-				Type: legacyCfg.DB_TYPE,
+				URI:     legacyCfg.MONGODB_URI,
+				Name:    legacyCfg.DB_NAME,
+				Type:    legacyCfg.DB_TYPE,
+				Options: legacyCfg.Mongo,
 			},
 			JWT: config.JWTConfig{
 				Secret: legacyCfg.JWT_SECRET,
 			},
 			Auth0: config.Auth0Config{
-				Domain:   legacyCfg.Auth0Domain,
-				Audience: legacyCfg.Auth0Audience,
+				Domain:        legacyCfg.Auth0Domain,
+				Audience:      legacyCfg.Auth0Audience,
+				ClientID:      legacyCfg.Auth0ClientID,
+				ClientSecret:  legacyCfg.Auth0ClientSecret,
+				WebhookSecret: legacyCfg.Auth0WebhookSecret,
+
+				ExtraIssuers:   legacyCfg.Auth0ExtraIssuers,
+				ExtraAudiences: legacyCfg.Auth0ExtraAudiences,
+				JWKSCacheDir:   legacyCfg.JWKSCacheDir,
 			},
+			Storage: legacyCfg.Storage,
+			Metrics: legacyCfg.Metrics,
+			Backup:  legacyCfg.Backup,
 		}
 	}
 
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
-
-	// Set Gin mode based on environment
-	if cfg.Environment == "production" {
-		gin.SetMode(gin.ReleaseMode)
-	}
+	stopMetrics := metrics.Start(cfg.Metrics, "users") // CloudWatch EMF metrics, when EMF_METRICS_ENABLED is set
+	defer stopMetrics()
 
 	// Connect to database (supports both MongoDB and DocumentDB)
 	var db *mongo.Database
@@ -79,7 +112,7 @@ func main() {
 	}
 
 	if cfg.Database.Type == "documentdb" {
-		client, err := database.CreateDocumentDBConnection(cfg.Database.URI)
+		client, err := database.CreateDocumentDBConnection(cfg.Database.URI, cfg.Database.Options)
 		if err != nil {
 			log.Fatalf("Failed to connect to DocumentDB: %v", err)
 		}
@@ -94,11 +127,15 @@ func main() {
 			MONGODB_URI:   cfg.Database.URI,
 			DB_NAME:       cfg.Database.Name,
 			DB_TYPE:       cfg.Database.Type,
+			Mongo:         cfg.Database.Options,
 			Auth0Domain:   cfg.Auth0.Domain,
 			Auth0Audience: cfg.Auth0.Audience,
 			JWT_SECRET:    cfg.JWT.Secret,
 		}
-		dbInterface := database.CreateDatabaseSingleton(legacyCfg)
+		dbInterface, err := database.CreateDatabase(legacyCfg)
+		if err != nil {
+			log.Fatalf("FATAL: Failed to connect to MongoDB: %v", err)
+		}
 		// For MongoDB, extract the underlying client and get the database
 		if mongoCol, ok := dbInterface.GetCollection(dbName, "temp").(*database.MongoCollection); ok {
 			db = mongoCol.Collection.Database()
@@ -111,27 +148,58 @@ func main() {
 	// Run seeder
 	seeder.SeedDatabase(db)
 
-	// Initialize health checker (choose based on environment)
-	var healthChecker interface {
-		RegisterRoutes(*gin.Engine)
+	// Scheduled exports of user data; `users restore-backup` restores one and exits (see lib/backup)
+	backups, err := backup.New(context.Background(), cfg.Backup, "users", db, backupCollections)
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
 	}
-
-	if os.Getenv("AWS_EXECUTION_ENV") != "" {
-		healthChecker = health.NewAWSEnhancedHealthChecker("users-service", db)
-	} else {
-		healthChecker = health.NewSimpleHealthChecker("users-service")
+	if backup.Requested() {
+		os.Exit(backups.RunCommand(context.Background(), os.Args[2:]))
 	}
 
+	// Initialize health checker
+	healthChecker := health.New("users-service", health.Options{
+		Database: db,
+		AWS:      os.Getenv("AWS_EXECUTION_ENV") != "",
+	})
+
+	// Route objectives (see slo.go), reported at /health/slo and /metrics
+	slos := metrics.NewSLOs("users", sloObjectives...)
+
 	// Setup HTTP router
-	router := gin.Default()
+	// Gin mode, request logs, and panic recovery suited to the environment (see lib/preset)
+	router := preset.For(cfg.Environment).Router()
+	router.ContextWithFallback = true // Handlers pass c to Mongo; let it carry the request's deadline
+	router.Use(slos.Middleware())     // Outside apierror.Middleware, so it records the status sent
+	router.Use(apierror.Middleware()) // Trace IDs and the standard error envelope
+	router.Use(metrics.Middleware())  // Request latency for CloudWatch
+	router.Use(compress.Gzip())       // Compress larger text and JSON responses
+	router.Use(romanize.Middleware()) // Romaji in the style the caller sends in X-Romanization
+	// Overall request deadline, answered with a 504 when it passes
+	router.Use(ctxutil.Timeout(cfg.RequestTimeout, map[string]time.Duration{
+		"/api/v1/users/avatar": 30 * time.Second, // Uploads from slow connections
+	}))
 
-	// Register health check routes
+	// Register health check routes; results are refreshed in the background
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	defer stopHealth()
+	healthChecker.Start(healthCtx)
 	healthChecker.RegisterRoutes(router)
+	slos.RegisterRoutes(router)
+	router.GET("/metrics", metrics.Handler(slos)) // Prometheus scrape endpoint; the gateway does not route it
+	openapi.RegisterRoutes(router, apidocs.Spec, false)
 
 	// Add auth middleware
 	var authMiddleware gin.HandlerFunc
 	if cfg.Auth0.Domain != "" && cfg.Auth0.Audience != "" {
-		authMiddleware = auth.EnsureValidToken(cfg.Auth0.Domain, cfg.Auth0.Audience)
+		authMiddleware, err = auth.NewTokenMiddleware(auth.ValidatorOptions{
+			Issuers:      append([]string{cfg.Auth0.Domain}, cfg.Auth0.ExtraIssuers...),
+			Audiences:    append([]string{cfg.Auth0.Audience}, cfg.Auth0.ExtraAudiences...),
+			JWKSCacheDir: cfg.Auth0.JWKSCacheDir,
+		})
+		if err != nil {
+			log.Fatalf("Failed to set up token validation: %v", err)
+		}
 		log.Println("Auth0 authentication enabled")
 	} else {
 		// Skip auth in development if no Auth0 is configured
@@ -139,9 +207,110 @@ func main() {
 		log.Println("WARNING: Auth0 not configured, skipping authentication")
 	}
 
+	// Initialize avatar storage
+	avatarStore, err := storage.New(context.Background(), cfg.Storage)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+	if localStore, ok := avatarStore.(*storage.LocalStore); ok {
+		router.GET(storage.LocalMediaPath+"/*filepath", gin.WrapH(http.StripPrefix(storage.LocalMediaPath, localStore.Handler())))
+	}
+
+	// Onboarding reads the verified email and profile from Auth0 when M2M credentials are configured
+	var identities handlers.IdentityProvider
+	if cfg.Auth0.Domain != "" && cfg.Auth0.ClientID != "" && cfg.Auth0.ClientSecret != "" {
+		identities = auth.NewManagementClient(cfg.Auth0.Domain, cfg.Auth0.ClientID, cfg.Auth0.ClientSecret)
+		log.Println("Auth0 Management API sync enabled for onboarding")
+	} else {
+		log.Println("WARNING: Auth0 M2M credentials not configured, onboarding trusts the client-provided email")
+	}
+
+	// Lesson completions and streak milestones go to users' and apps' webhooks; failed deliveries
+	// are retried on one instance at a time
+	dispatcher := webhooks.NewDispatcher(db)
+	scheduler := jobs.NewScheduler(jobs.NewMongoLocker(db.Collection(jobs.LeaseCollection)))
+	if err := scheduler.Add(jobs.Job{
+		Name:     "webhook-retries",
+		Schedule: webhooks.RetrySchedule,
+		Run:      dispatcher.RetryDue,
+	}); err != nil {
+		log.Fatalf("FATAL: Failed to schedule webhook retries: %v", err)
+	}
+	if cfg.Backup.Enabled {
+		if err := scheduler.Add(backups.Job()); err != nil {
+			log.Fatalf("FATAL: Failed to schedule backups: %v", err)
+		}
+	}
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	scheduler.Start(schedulerCtx)
+
 	// Initialize user handler
 	userCollection := db.Collection("users")
-	userHandler := handlers.NewUserHandler(userCollection)
+	userHandler := handlers.NewUserHandler(userCollection, avatarStore, identities, dispatcher)
+
+	// Lesson completions are checked against the content service's lessons over gRPC
+	contentServiceURL := getContentServiceURL()
+	contentBreaker := resilience.NewCircuitBreaker("content-grpc", resilience.Options{
+		IsFailure: resilience.IsGRPCFailure,
+	})
+	dialOpts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, resilience.DialOptions(contentBreaker)...)
+	dialOpts = append(dialOpts, grpcutil.ClientOptions()...)
+	conn, err := grpc.Dial(grpcutil.ClientTarget(contentServiceURL), dialOpts...)
+	if err != nil {
+		log.Fatalf("Did not connect to content-service: %v", err)
+	}
+	defer conn.Close()
+	if err := healthChecker.AddDependency(health.DependencyConfig{
+		Name:      "content-grpc",
+		CheckType: health.CheckGRPC,
+		Target:    contentServiceURL,
+		Conn:      conn,
+		Breaker:   contentBreaker,
+	}); err != nil {
+		log.Printf("WARN: Failed to register content-service health dependency: %v", err)
+	}
+	contentClient := pb_content.NewContentServiceClient(conn)
+	lessonHandler := handlers.NewLessonHandler(db.Collection("lesson_completions"), contentClient, dispatcher)
+	favoriteHandler := handlers.NewFavoriteHandler(db.Collection("favorites"), contentClient)
+	feedbackHandler := handlers.NewFeedbackHandler(feedback.NewStore(db), contentClient)
+	syncHandler := handlers.NewSyncHandler(db)
+	integrationHandler := handlers.NewIntegrationHandler(db)
+
+	// Mastery levels are kept by the quiz service, which sees every answer and review
+	quizServiceURL := getQuizServiceURL()
+	quizBreaker := resilience.NewCircuitBreaker("quiz-grpc", resilience.Options{
+		IsFailure: resilience.IsGRPCFailure,
+	})
+	quizDialOpts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, resilience.DialOptions(quizBreaker)...)
+	quizDialOpts = append(quizDialOpts, grpcutil.ClientOptions()...)
+	quizConn, err := grpc.Dial(grpcutil.ClientTarget(quizServiceURL), quizDialOpts...)
+	if err != nil {
+		log.Fatalf("Did not connect to quiz-service: %v", err)
+	}
+	defer quizConn.Close()
+	if err := healthChecker.AddDependency(health.DependencyConfig{
+		Name:      "quiz-grpc",
+		CheckType: health.CheckGRPC,
+		Target:    quizServiceURL,
+		Conn:      quizConn,
+		Breaker:   quizBreaker,
+	}); err != nil {
+		log.Printf("WARN: Failed to register quiz-service health dependency: %v", err)
+	}
+	quizClient := pb_quiz.NewQuizServiceClient(quizConn)
+	masteryHandler := handlers.NewMasteryHandler(quizClient, contentClient)
+	dashboardHandler := handlers.NewDashboardHandler(userCollection, db.Collection("lesson_completions"), quizClient, contentClient)
+
+	// Store subscriptions decide users' tiers, which other services read over gRPC
+	subscriptionStore := subscriptions.NewStore(db)
+	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionStore, subscriptions.VerifiersFromEnv())
+
+	// API calls are counted per user and day, and held to the daily limit of the user's tier
+	limits := usage.LimitsFromEnv()
+	usageStore := usage.NewStore(db)
+	usageHandler := handlers.NewUsageHandler(usageStore, subscriptionStore, limits)
+	meter := usage.NewMeter(usageStore, "users", subscriptionStore, limits)
 
 	// Setup API routes
 	api := router.Group("/api/v1/users")
@@ -156,16 +325,57 @@ func main() {
 
 		// Protected routes
 		protected := api.Group("/")
-		protected.Use(authMiddleware)
+		protected.Use(authMiddleware, auth.RequireVerifiedEmail(auth.VerifiedEmailOptions{
+			Lookup: identities,
+			Exempt: []string{"POST /api/v1/users/identities/link"},
+		}), meter.Middleware())
 		{
 			protected.GET("/profile", userHandler.GetUserProfile)
+			protected.GET("/streak", userHandler.GetStreak)
+			protected.POST("/activity", userHandler.RecordActivity)
+			protected.POST("/avatar", userHandler.UploadAvatar)
+			protected.POST("/identities/link", userHandler.LinkIdentity)
+			protected.GET("/lessons", lessonHandler.GetCompletedLessons)
+			protected.POST("/lessons/:lessonId/complete", lessonHandler.CompleteLesson)
+			protected.GET("/unlocked-lessons", lessonHandler.GetUnlockedLessons)
+			protected.GET("/mastery", masteryHandler.GetMastery)
+			protected.GET("/favorites", favoriteHandler.GetFavorites)
+			protected.POST("/favorites/:vocabId", favoriteHandler.AddFavorite)
+			protected.DELETE("/favorites/:vocabId", favoriteHandler.RemoveFavorite)
+			protected.GET("/webhooks", integrationHandler.GetWebhooks)
+			protected.POST("/webhooks", integrationHandler.CreateWebhook)
+			protected.DELETE("/webhooks/:webhookId", integrationHandler.DeleteWebhook)
+			protected.GET("/webhooks/:webhookId/deliveries", integrationHandler.GetWebhookDeliveries)
+			protected.GET("/usage", usageHandler.GetUsage)
+			protected.GET("/subscription", subscriptionHandler.GetSubscription)
+			protected.POST("/subscription/purchases", subscriptionHandler.RecordPurchase)
 			// Add other routes as needed
 		}
 	}
 
-	// Setup gRPC server (if needed)
-	grpcServer := grpc.NewServer()
-	// Register gRPC services here if you have them
+	// Everything the client's home screen shows, in one call
+	router.GET("/api/v1/dashboard", authMiddleware, auth.RequireVerifiedEmail(auth.VerifiedEmailOptions{Lookup: identities}), meter.Middleware(), dashboardHandler.GetDashboard)
+
+	// Feedback and content error reports, triaged through the admin service
+	router.POST("/api/v1/feedback", authMiddleware, auth.RequireVerifiedEmail(auth.VerifiedEmailOptions{Lookup: identities}), meter.Middleware(), feedbackHandler.SubmitFeedback)
+
+	// Auth0 Actions push user lifecycle events here; requests are authenticated by their HMAC signature
+	if cfg.Auth0.WebhookSecret != "" {
+		router.POST("/api/v1/webhooks/auth0", auth.VerifyWebhook(cfg.Auth0.WebhookSecret), userHandler.HandleAuth0Webhook)
+	} else {
+		log.Println("WARNING: Auth0 webhook secret not configured, webhook endpoint disabled")
+	}
+
+	// Event ingestion, which advances streaks, and lookups for other services (not routed through the load balancer)
+	router.POST("/internal/v1/events", events.Handler(userHandler.HandleEvent))
+	router.GET("/internal/v1/users/:userId/unlocked-lessons", lessonHandler.GetUserUnlockedLessons)
+	router.GET("/internal/v1/users/:userId/changes", syncHandler.GetUserChanges)
+
+	// Setup gRPC server: subscriptions for other services, and the standard health service
+	grpcServer := grpc.NewServer(grpcutil.ServerOptions(grpcutil.Options{})...)
+	pb_users.RegisterUsersServiceServer(grpcServer, users_grpc.NewServer(subscriptionStore))
+	healthpb.RegisterHealthServer(grpcServer, grpchealth.NewServer())
+	preset.For(cfg.Environment).RegisterReflection(grpcServer) // grpcurl in development
 
 	// Start servers
 	httpServer := &http.Server{
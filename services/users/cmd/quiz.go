@@ -0,0 +1,22 @@
+// FILE: services/users/cmd/quiz.go
+// Shared by main.go and main_aws.go
+
+package main
+
+import (
+	"os"
+
+	"wise-owl/lib/config"
+)
+
+// getQuizServiceURL returns the appropriate quiz service gRPC URL based on environment
+func getQuizServiceURL() string {
+	if url := os.Getenv("QUIZ_SERVICE_URL"); url != "" {
+		return url
+	}
+	if config.IsAWSEnvironment() {
+		// Default for ECS service discovery
+		return "quiz-service.wise-owl-cluster.local:50053"
+	}
+	return "quiz-service:50053"
+}
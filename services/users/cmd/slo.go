@@ -0,0 +1,18 @@
+// FILE: services/users/cmd/slo.go
+// Shared by main.go and main_aws.go
+
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"wise-owl/lib/metrics"
+)
+
+// sloObjectives are the users service's objectives: the profile every app screen loads, and the
+// dashboard, which fans out to other services and is allowed to be slower
+var sloObjectives = []metrics.Objective{
+	{Method: http.MethodGet, Route: "/api/v1/users/me/profile", Availability: 0.999, Latency: 300 * time.Millisecond, LatencyTarget: 0.99},
+	{Method: http.MethodGet, Route: "/api/v1/dashboard", Availability: 0.995, Latency: time.Second, LatencyTarget: 0.99},
+}
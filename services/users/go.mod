@@ -5,6 +5,9 @@ go 1.24.5
 require (
 	github.com/gin-gonic/gin v1.10.1
 	go.mongodb.org/mongo-driver v1.17.4
+	golang.org/x/image v0.23.0
+	golang.org/x/oauth2 v0.28.0
+	gopkg.in/go-jose/go-jose.v2 v2.6.3
 )
 
 require (
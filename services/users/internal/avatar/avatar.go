@@ -0,0 +1,85 @@
+// FILE: services/users/internal/avatar/avatar.go
+// Validates uploaded avatar images and normalizes them to a square JPEG.
+
+package avatar
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif" // Register GIF decoding
+	"image/jpeg"
+	_ "image/png" // Register PNG decoding
+	"io"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Register WebP decoding
+)
+
+const (
+	// MaxUploadBytes caps the size of an uploaded file.
+	MaxUploadBytes = 5 << 20
+	// MaxSourcePixels caps the decoded size of an upload so small files cannot expand into huge bitmaps.
+	MaxSourcePixels = 4096 * 4096
+	// Size is the width and height of stored avatars.
+	Size = 256
+	// ContentType is the type of every stored avatar.
+	ContentType = "image/jpeg"
+
+	jpegQuality = 85
+)
+
+var (
+	// ErrUnsupportedFormat is returned for files that are not JPEG, PNG, GIF, or WebP images.
+	ErrUnsupportedFormat = errors.New("avatar: unsupported image format")
+	// ErrTooLarge is returned for files or images over the size limits.
+	ErrTooLarge = errors.New("avatar: image too large")
+)
+
+// Process decodes an uploaded image, crops it to a centered square, scales it to Size x Size,
+// and re-encodes it as JPEG. Re-encoding also strips metadata such as EXIF location.
+func Process(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxUploadBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxUploadBytes {
+		return nil, ErrTooLarge
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, ErrUnsupportedFormat
+	}
+	if cfg.Width*cfg.Height > MaxSourcePixels {
+		return nil, ErrTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, Size, Size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, centerSquare(src.Bounds()), draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// centerSquare returns the largest square centered in bounds.
+func centerSquare(bounds image.Rectangle) image.Rectangle {
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+	return image.Rect(x, y, x+side, y+side)
+}
@@ -0,0 +1,52 @@
+// FILE: services/users/internal/grpc/server.go
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "wise-owl/gen/proto/users/v1"
+	"wise-owl/lib/usage"
+	"wise-owl/services/users/internal/subscriptions"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements the gRPC UsersServiceServer interface.
+type Server struct {
+	pb.UnimplementedUsersServiceServer
+	subscriptions *subscriptions.Store
+}
+
+// NewServer creates a new gRPC server with its subscription store.
+func NewServer(store *subscriptions.Store) *Server {
+	return &Server{subscriptions: store}
+}
+
+// GetSubscription returns a user's current tier and subscription. Users without a profile are on the free tier.
+func (s *Server) GetSubscription(ctx context.Context, req *pb.GetSubscriptionRequest) (*pb.GetSubscriptionResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	res := &pb.GetSubscriptionResponse{UserId: req.GetUserId(), Tier: usage.TierFree}
+	user, err := s.subscriptions.Find(ctx, req.GetUserId())
+	if errors.Is(err, subscriptions.ErrUserNotFound) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res.Tier = user.TierAt(time.Now())
+	if sub := user.Subscription; sub != nil {
+		res.Platform = sub.Platform
+		res.ProductId = sub.ProductID
+		res.RenewsAt = timestamppb.New(sub.RenewsAt)
+	}
+	return res, nil
+}
@@ -0,0 +1,131 @@
+// FILE: services/users/internal/handlers/avatar_handlers.go
+// This file contains the avatar upload endpoint.
+
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/database"
+	"wise-owl/lib/httpcache"
+	"wise-owl/services/users/internal/avatar"
+	"wise-owl/services/users/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// multipartOverhead leaves room for form boundaries and headers around the image itself.
+const multipartOverhead = 64 << 10
+
+// UploadAvatar replaces the caller's avatar with the image sent as the multipart form field "avatar".
+// The image is cropped to a square, resized, and stored as JPEG; the previous avatar is removed.
+// Like profile updates, the upload can be made conditional on the profile version with If-Match.
+func (h *UserHandler) UploadAvatar(c *gin.Context) {
+	auth0ID, _ := c.Get("userID")
+
+	var user models.User
+	err := h.collection.FindOne(c, byIdentity(auth0ID.(string))).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.Error(apierror.NotFound("not_found", "User profile not found."))
+			return
+		}
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	version, conditional, apiErr := httpcache.ExpectedVersion(c, nil)
+	if apiErr != nil {
+		c.Error(apiErr)
+		return
+	}
+	if conditional && version != user.Version {
+		c.Header("ETag", httpcache.VersionETag(user.Version))
+		c.Error(httpcache.VersionConflict())
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, avatar.MaxUploadBytes+multipartOverhead)
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.Error(avatarTooLarge())
+			return
+		}
+		c.Error(apierror.BadRequest("invalid_request", `Upload the image as the multipart form field "avatar".`))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.Error(apierror.Internal("upload_failed", err))
+		return
+	}
+	defer file.Close()
+
+	data, err := avatar.Process(file)
+	switch {
+	case errors.Is(err, avatar.ErrTooLarge):
+		c.Error(avatarTooLarge())
+		return
+	case errors.Is(err, avatar.ErrUnsupportedFormat):
+		c.Error(apierror.New(http.StatusUnsupportedMediaType, "unsupported_image", "The avatar must be a JPEG, PNG, GIF, or WebP image."))
+		return
+	case err != nil:
+		c.Error(apierror.Internal("image_processing_failed", err))
+		return
+	}
+
+	// A new key per upload lets clients and CDNs cache avatar URLs indefinitely
+	key := fmt.Sprintf("avatars/%s/%d.jpg", user.ID.Hex(), time.Now().UnixNano())
+	if err := h.store.Put(c, key, bytes.NewReader(data), int64(len(data)), avatar.ContentType); err != nil {
+		c.Error(apierror.Internal("upload_failed", err))
+		return
+	}
+
+	avatarURL := h.store.URL(key)
+	update := bson.M{"$set": bson.M{"avatar_url": avatarURL, "avatar_key": key, "updated_at": changefeed.Now()}}
+	filter := bson.M{"_id": user.ID}
+	if conditional {
+		filter = database.MatchVersion(filter, version)
+	}
+	var updated models.User
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{"version": 1})
+	if err := h.collection.FindOneAndUpdate(c, filter, database.BumpVersion(update), opts).Decode(&updated); err != nil {
+		h.deleteAvatar(c, key)
+		if err == mongo.ErrNoDocuments {
+			h.rejectUpdate(c, auth0ID.(string), conditional)
+			return
+		}
+		c.Error(apierror.Internal("update_failed", err))
+		return
+	}
+
+	if user.AvatarKey != "" {
+		h.deleteAvatar(c, user.AvatarKey)
+	}
+
+	c.Header("ETag", httpcache.VersionETag(updated.Version))
+	c.JSON(http.StatusOK, gin.H{"avatar_url": avatarURL})
+}
+
+// deleteAvatar removes a stored avatar. Failures only leave an orphaned object, so they are logged.
+func (h *UserHandler) deleteAvatar(c *gin.Context, key string) {
+	if err := h.store.Delete(c, key); err != nil {
+		log.Printf("WARN: Failed to delete avatar %s: %v", key, err)
+	}
+}
+
+func avatarTooLarge() *apierror.Error {
+	return apierror.New(http.StatusRequestEntityTooLarge, "avatar_too_large",
+		fmt.Sprintf("The avatar must be at most %d MB and %d megapixels.", avatar.MaxUploadBytes>>20, avatar.MaxSourcePixels/1_000_000))
+}
@@ -0,0 +1,173 @@
+// FILE: services/users/internal/handlers/dashboard_handlers.go
+// This file serves the client's home screen in one call, gathering the streak, last lesson, due
+// reviews, and recent misses from this service and the quiz and content services at once.
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	pb_content "wise-owl/gen/proto/content/v1"
+	pb_quiz "wise-owl/gen/proto/quiz/v1"
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/database"
+	"wise-owl/lib/fanout"
+	"wise-owl/services/users/internal/models"
+	"wise-owl/services/users/internal/streak"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// recentMissLimit is how many recently missed words the dashboard shows
+	recentMissLimit = 5
+	// dashboardSectionTimeout bounds each section, so one slow source cannot hold up the rest
+	dashboardSectionTimeout = 3 * time.Second
+)
+
+// DashboardHandler holds dependencies for the dashboard handler.
+type DashboardHandler struct {
+	users         database.CollectionInterface
+	completions   database.CollectionInterface
+	quizClient    pb_quiz.QuizServiceClient       // Keeps due reviews and incorrect words
+	contentClient pb_content.ContentServiceClient // Describes the missed words
+}
+
+// NewDashboardHandler creates a new handler with its dependencies.
+func NewDashboardHandler(users, completions *mongo.Collection, quizClient pb_quiz.QuizServiceClient, contentClient pb_content.ContentServiceClient) *DashboardHandler {
+	return &DashboardHandler{
+		users:         database.NewUserScopedCollection(users),
+		completions:   database.NewUserScopedCollection(completions),
+		quizClient:    quizClient,
+		contentClient: contentClient,
+	}
+}
+
+// dashboardReviews is the review count shown on the home screen
+type dashboardReviews struct {
+	Due int64 `json:"due"`
+}
+
+// GetDashboard returns what the home screen shows: the caller's streak, their most recently
+// completed lesson (null before the first), how many words are due for review, and the words they
+// missed most recently. The sections are fetched in parallel, each within dashboardSectionTimeout.
+// One that fails is null and named in "unavailable" rather than failing the request, so the
+// client can show the rest and retry later.
+func (h *DashboardHandler) GetDashboard(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var (
+		user       models.User
+		lastLesson *models.LessonCompletion
+		reviews    *dashboardReviews
+		misses     []*pb_content.Vocabulary
+	)
+	results := fanout.Run(ctxutil.Request(c), []fanout.Call{
+		{Name: "streak", Timeout: dashboardSectionTimeout, Run: func(ctx context.Context) error {
+			ctx, cancel := ctxutil.Database(ctx)
+			defer cancel()
+			return h.users.FindOne(ctx, byIdentity(userID)).Decode(&user)
+		}},
+		{Name: "last_lesson", Timeout: dashboardSectionTimeout, Run: func(ctx context.Context) (err error) {
+			lastLesson, err = h.lastLesson(ctx, userID)
+			return err
+		}},
+		{Name: "reviews", Timeout: dashboardSectionTimeout, Run: func(ctx context.Context) (err error) {
+			reviews, err = h.dueReviews(ctx, userID)
+			return err
+		}},
+		{Name: "recent_misses", Timeout: dashboardSectionTimeout, Run: func(ctx context.Context) (err error) {
+			misses, err = h.recentMisses(ctx, userID)
+			return err
+		}},
+	}...)
+
+	if errors.Is(results[0].Err, mongo.ErrNoDocuments) {
+		c.Error(apierror.NotFound("not_found", "User profile not found."))
+		return
+	}
+
+	response := gin.H{
+		"streak":        nil,
+		"last_lesson":   lastLesson,
+		"reviews":       reviews,
+		"recent_misses": misses,
+		"unavailable":   fanout.Failed(results),
+	}
+	if results[0].Err == nil {
+		response["streak"] = streak.Effective(user.Streak, streak.LocalDate(time.Now(), streak.Location(user.Timezone)))
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			log.Printf("WARN: Dashboard section %s unavailable for %s: %v", result.Name, userID, result.Err)
+			response[result.Name] = nil
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// lastLesson returns the lesson the user completed most recently, or nil if they have completed none.
+func (h *DashboardHandler) lastLesson(ctx context.Context, userID string) (*models.LessonCompletion, error) {
+	ctx, cancel := ctxutil.Database(ctx)
+	defer cancel()
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "last_completed_at", Value: -1}})
+	var completion models.LessonCompletion
+	err := h.completions.FindOne(ctx, bson.M{"user_id": userID}, opts).Decode(&completion)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &completion, nil
+}
+
+// dueReviews asks the quiz service how many words are due for review.
+func (h *DashboardHandler) dueReviews(ctx context.Context, userID string) (*dashboardReviews, error) {
+	ctx, cancel := ctxutil.GRPC(ctx)
+	defer cancel()
+
+	res, err := h.quizClient.GetDueReviews(ctx, &pb_quiz.GetDueReviewsRequest{UserId: userID})
+	if err != nil {
+		return nil, err
+	}
+	return &dashboardReviews{Due: res.GetCount()}, nil
+}
+
+// recentMisses returns the words the user missed most recently, most recent first. Words since
+// removed from the vocabulary are left out.
+func (h *DashboardHandler) recentMisses(ctx context.Context, userID string) ([]*pb_content.Vocabulary, error) {
+	quizCtx, cancel := ctxutil.GRPC(ctx)
+	defer cancel()
+	missed, err := h.quizClient.GetRecentMisses(quizCtx, &pb_quiz.GetRecentMissesRequest{UserId: userID, Limit: recentMissLimit})
+	if err != nil {
+		return nil, err
+	}
+
+	words := make([]*pb_content.Vocabulary, 0, len(missed.GetVocabularyIds()))
+	if len(missed.GetVocabularyIds()) == 0 {
+		return words, nil
+	}
+	contentCtx, cancel := ctxutil.GRPC(ctx)
+	defer cancel()
+	batch, err := h.contentClient.GetVocabularyBatch(contentCtx, &pb_content.GetVocabularyBatchRequest{VocabularyIds: missed.GetVocabularyIds()})
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range missed.GetVocabularyIds() {
+		if word, ok := batch.Items[id]; ok {
+			words = append(words, word)
+		}
+	}
+	return words, nil
+}
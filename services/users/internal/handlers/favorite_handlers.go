@@ -0,0 +1,176 @@
+// FILE: services/users/internal/handlers/favorite_handlers.go
+// This file contains the vocabulary favorites endpoints, the learner's own word list alongside
+// the quiz service's incorrect words.
+
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/database"
+	"wise-owl/services/users/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxFavorites caps a user's favorites, which are all hydrated in one content call when listed
+const maxFavorites = 1000
+
+// FavoriteHandler holds dependencies for the favorites handlers.
+type FavoriteHandler struct {
+	favorites     database.CollectionInterface
+	tombstones    *mongo.Collection               // Removals reported to offline clients
+	contentClient pb_content.ContentServiceClient // Validates and hydrates vocabulary IDs
+}
+
+// NewFavoriteHandler creates a new handler with its dependencies.
+// Removals are recorded for the sync feed alongside the favorites collection in the same database.
+func NewFavoriteHandler(favorites *mongo.Collection, contentClient pb_content.ContentServiceClient) *FavoriteHandler {
+	return &FavoriteHandler{
+		favorites:     database.NewUserScopedCollection(favorites),
+		tombstones:    favorites.Database().Collection(changefeed.TombstoneCollection),
+		contentClient: contentClient,
+	}
+}
+
+// favoriteResponse is a favorite with its vocabulary. Vocabulary is omitted for words the content
+// service no longer has, so clients can still remove them.
+type favoriteResponse struct {
+	models.Favorite
+	Vocabulary *pb_content.Vocabulary `json:"vocabulary,omitempty"`
+}
+
+// AddFavorite bookmarks a vocabulary word for the caller. Adding a word twice keeps the first bookmark.
+func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	vocabID := c.Param("vocabId")
+
+	if !primitive.IsValidObjectID(vocabID) {
+		c.Error(apierror.BadRequest("invalid_vocabulary_id", "Vocabulary ID must be a 24-character hex ID."))
+		return
+	}
+
+	filter := bson.M{"user_id": userID.(string), "vocabulary_id": vocabID}
+	dbCtx, cancelDB := ctxutil.Database(c)
+	defer cancelDB()
+
+	var existing models.Favorite
+	err := h.favorites.FindOne(dbCtx, filter).Decode(&existing)
+	if err == nil {
+		c.JSON(http.StatusOK, existing)
+		return
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	count, err := h.favorites.CountDocuments(dbCtx, bson.M{"user_id": userID.(string)})
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	if count >= maxFavorites {
+		c.Error(apierror.Conflict("favorites_limit", "You have reached the maximum number of favorites."))
+		return
+	}
+
+	ctx, cancel := ctxutil.GRPC(c)
+	defer cancel()
+
+	grpcRes, err := h.contentClient.GetVocabularyBatch(ctx, &pb_content.GetVocabularyBatchRequest{VocabularyIds: []string{vocabID}})
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+	if _, ok := grpcRes.Items[vocabID]; !ok {
+		c.Error(apierror.NotFound("not_found", "Vocabulary not found."))
+		return
+	}
+
+	// Upsert so a concurrent add of the same word keeps a single bookmark
+	update := bson.M{"$setOnInsert": bson.M{"created_at": time.Now().UTC()}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var favorite models.Favorite
+	if err := h.favorites.FindOneAndUpdate(dbCtx, filter, update, opts).Decode(&favorite); err != nil {
+		c.Error(apierror.Internal("update_failed", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, favorite)
+}
+
+// RemoveFavorite removes a word from the caller's favorites. Removing a word that is not there succeeds.
+func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	result, err := h.favorites.DeleteOne(ctx, bson.M{"user_id": userID.(string), "vocabulary_id": c.Param("vocabId")})
+	if err != nil {
+		c.Error(apierror.Internal("delete_failed", err))
+		return
+	}
+
+	// Offline clients learn of the removal from the sync feed
+	if result.DeletedCount > 0 {
+		if err := changefeed.RecordDeletions(ctx, h.tombstones, userID.(string), "favorites", c.Param("vocabId")); err != nil {
+			log.Printf("WARN: Failed to record favorite deletion for %v: %v", userID, err)
+		}
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetFavorites lists the caller's favorites with their vocabulary, most recently added first.
+func (h *FavoriteHandler) GetFavorites(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	dbCtx, cancelDB := ctxutil.Database(c)
+	defer cancelDB()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	favorites, err := database.FindAll[models.Favorite](dbCtx, h.favorites, bson.M{"user_id": userID.(string)}, opts)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	response := make([]favoriteResponse, 0, len(favorites))
+	if len(favorites) == 0 {
+		c.JSON(http.StatusOK, gin.H{"favorites": response})
+		return
+	}
+
+	vocabIDs := make([]string, 0, len(favorites))
+	for _, favorite := range favorites {
+		vocabIDs = append(vocabIDs, favorite.VocabularyID)
+	}
+
+	ctx, cancel := ctxutil.GRPC(c)
+	defer cancel()
+
+	grpcRes, err := h.contentClient.GetVocabularyBatch(ctx, &pb_content.GetVocabularyBatchRequest{VocabularyIds: vocabIDs})
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+
+	for _, favorite := range favorites {
+		response = append(response, favoriteResponse{Favorite: favorite, Vocabulary: grpcRes.Items[favorite.VocabularyID]})
+	}
+	c.JSON(http.StatusOK, gin.H{"favorites": response})
+}
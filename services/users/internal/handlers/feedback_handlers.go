@@ -0,0 +1,78 @@
+// FILE: services/users/internal/handlers/feedback_handlers.go
+// This file takes users' feedback and content error reports, which admins triage in the admin service.
+
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/feedback"
+	"wise-owl/lib/validation"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FeedbackHandler holds dependencies for the feedback handlers.
+type FeedbackHandler struct {
+	store         *feedback.Store
+	contentClient pb_content.ContentServiceClient // Checks reported words exist
+}
+
+// NewFeedbackHandler creates a new handler with its dependencies.
+func NewFeedbackHandler(store *feedback.Store, contentClient pb_content.ContentServiceClient) *FeedbackHandler {
+	return &FeedbackHandler{store: store, contentClient: contentClient}
+}
+
+// SubmitFeedback records a report from the caller, optionally about one vocabulary word.
+// A report is still taken when the content service cannot confirm the word, since it may be
+// about the very outage; admins see the ID as sent.
+func (h *FeedbackHandler) SubmitFeedback(c *gin.Context) {
+	var req struct {
+		Category     string `json:"category" binding:"required,oneof=translation audio content bug suggestion other"`
+		Message      string `json:"message" binding:"required,max=2000"`
+		VocabularyID string `json:"vocabulary_id"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if req.VocabularyID != "" {
+		if !primitive.IsValidObjectID(req.VocabularyID) {
+			c.Error(apierror.BadRequest("invalid_vocabulary_id", "Vocabulary ID must be a 24-character hex ID."))
+			return
+		}
+		ctx, cancel := ctxutil.GRPC(c)
+		grpcRes, err := h.contentClient.GetVocabularyBatch(ctx, &pb_content.GetVocabularyBatchRequest{VocabularyIds: []string{req.VocabularyID}})
+		cancel()
+		if err != nil {
+			log.Printf("WARN: Could not check reported vocabulary %s: %v", req.VocabularyID, err)
+		} else if _, ok := grpcRes.Items[req.VocabularyID]; !ok {
+			c.Error(apierror.NotFound("not_found", "Vocabulary not found."))
+			return
+		}
+	}
+
+	report, err := h.store.Create(c, feedback.Feedback{
+		UserID:       c.GetString("userID"),
+		Category:     req.Category,
+		Message:      req.Message,
+		VocabularyID: req.VocabularyID,
+		UserAgent:    c.Request.UserAgent(),
+	})
+	if errors.Is(err, feedback.ErrLimit) {
+		c.Error(apierror.New(http.StatusTooManyRequests, "feedback_limit_reached", "You have sent the maximum number of reports for today."))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("create_failed", err))
+		return
+	}
+	c.JSON(http.StatusCreated, report)
+}
@@ -0,0 +1,112 @@
+// FILE: services/users/internal/handlers/identity_handlers.go
+// This file contains the endpoint for linking additional Auth0 identities to a user.
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/auth"
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/database"
+	"wise-owl/lib/validation"
+	"wise-owl/services/users/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LinkIdentity links another identity (e.g. Google, Apple, or email) to the caller's account, so
+// signing in with either reaches the same profile. The caller proves they own the other identity
+// with an ID token issued to it; Auth0 verifies the token when it links the accounts.
+func (h *UserHandler) LinkIdentity(c *gin.Context) {
+	auth0ID, _ := c.Get("userID")
+
+	var req struct {
+		IDToken string `json:"id_token" binding:"required"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if h.identities == nil {
+		c.Error(apierror.ServiceUnavailable("account_linking_unavailable", "Account linking is not configured.", nil))
+		return
+	}
+
+	var user models.User
+	if err := h.collection.FindOne(c, byIdentity(auth0ID.(string))).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.Error(apierror.NotFound("not_found", "User profile not found."))
+			return
+		}
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	// Refuse before asking Auth0 to link, so an identity with its own profile is not merged in Auth0 only
+	secondary, err := auth.UnverifiedSubject(req.IDToken)
+	if err != nil {
+		c.Error(apierror.BadRequest("invalid_id_token", "The ID token is malformed."))
+		return
+	}
+	// The identity is looked up among other users' profiles, so the query is cross-user by design
+	taken, err := h.collection.CountDocuments(database.CrossUser(c), bson.M{"auth0_ids": secondary, "_id": bson.M{"$ne": user.ID}})
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	if taken > 0 {
+		c.Error(identityInUse())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	identities, err := h.identities.LinkIdentity(ctx, user.Auth0ID, req.IDToken)
+	if err != nil {
+		if errors.Is(err, auth.ErrLinkRejected) {
+			c.Error(apierror.BadRequest("link_rejected", "Auth0 rejected the link; the ID token may be invalid or expired."))
+			return
+		}
+		c.Error(apierror.ServiceUnavailable("identity_provider_unavailable", "The identity provider is unavailable.", err))
+		return
+	}
+
+	subjects := make([]string, 0, len(identities))
+	for _, identity := range identities {
+		subjects = append(subjects, identity.Subject())
+	}
+
+	var updated models.User
+	err = h.collection.FindOneAndUpdate(c,
+		bson.M{"_id": user.ID},
+		bson.M{
+			"$addToSet": bson.M{"auth0_ids": bson.M{"$each": subjects}},
+			"$set":      bson.M{"updated_at": changefeed.Now()},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.Error(identityInUse())
+			return
+		}
+		c.Error(apierror.Internal("update_failed", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"auth0_ids": updated.Auth0IDs})
+}
+
+func identityInUse() *apierror.Error {
+	return apierror.Conflict("identity_in_use", "That identity already belongs to another Wise Owl account.")
+}
@@ -0,0 +1,131 @@
+// FILE: services/users/internal/handlers/integration_handlers.go
+// This file lets users register webhooks for their own lesson and streak events, e.g. to feed a
+// teacher's dashboard, and read each webhook's delivery log.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/validation"
+	"wise-owl/lib/webhooks"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// IntegrationHandler holds dependencies for the webhook registration handlers.
+type IntegrationHandler struct {
+	store *webhooks.Store
+}
+
+// NewIntegrationHandler creates a new handler over the users database.
+func NewIntegrationHandler(db *mongo.Database) *IntegrationHandler {
+	return &IntegrationHandler{store: webhooks.NewStore(db)}
+}
+
+// CreateWebhook registers a webhook for the caller. The response is the only time its signing
+// secret is shown.
+func (h *IntegrationHandler) CreateWebhook(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req struct {
+		URL    string   `json:"url" binding:"required,url,max=2048"`
+		Events []string `json:"events" binding:"required,min=1,dive,oneof=lesson.completed streak.milestone"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	webhook, err := h.store.Create(c, userID.(string), "", req.URL, req.Events)
+	switch {
+	case errors.Is(err, webhooks.ErrInvalidURL):
+		c.Error(apierror.BadRequest("invalid_webhook_url", "Webhook URLs must use https and a public host."))
+		return
+	case errors.Is(err, webhooks.ErrLimit):
+		c.Error(apierror.Conflict("webhook_limit_reached", "You have reached the maximum number of webhooks."))
+		return
+	case err != nil:
+		c.Error(apierror.Internal("create_failed", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"webhook": webhook, "secret": webhook.Secret})
+}
+
+// GetWebhooks lists the caller's webhooks.
+func (h *IntegrationHandler) GetWebhooks(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	list, err := h.store.List(c, userID.(string))
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhooks": list})
+}
+
+// DeleteWebhook removes one of the caller's webhooks; pending deliveries to it are dropped.
+func (h *IntegrationHandler) DeleteWebhook(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.store.Delete(c, userID.(string), id)
+	if err != nil {
+		c.Error(apierror.Internal("delete_failed", err))
+		return
+	}
+	if !deleted {
+		c.Error(apierror.NotFound("not_found", "Webhook not found."))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries returns the latest deliveries to one of the caller's webhooks, newest first.
+func (h *IntegrationHandler) GetWebhookDeliveries(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	query := struct {
+		Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+	}{Limit: 20}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	deliveries, err := h.store.Deliveries(c, userID.(string), id, query.Limit)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.Error(apierror.NotFound("not_found", "Webhook not found."))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+// webhookID parses the webhook ID in the path, reporting a 400 when it is malformed.
+func webhookID(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("webhookId"))
+	if err != nil {
+		c.Error(apierror.BadRequest("invalid_webhook_id", "Webhook ID must be a 24-character hex ObjectID."))
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
@@ -0,0 +1,156 @@
+// FILE: services/users/internal/handlers/lesson_handlers.go
+// This file contains the lesson completion endpoints behind the client's course map, and the
+// lesson unlocks derived from them.
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	pb_content "wise-owl/gen/proto/content/v1"
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/database"
+	"wise-owl/lib/events"
+	"wise-owl/lib/validation"
+	"wise-owl/services/users/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LessonHandler holds dependencies for the lesson completion handlers.
+type LessonHandler struct {
+	completions   database.CollectionInterface
+	contentClient pb_content.ContentServiceClient // Validates lesson IDs and owns the prerequisites
+	webhooks      events.Publisher                // Receives completions for users' and apps' webhooks
+}
+
+// NewLessonHandler creates a new handler with its dependencies.
+func NewLessonHandler(completions *mongo.Collection, contentClient pb_content.ContentServiceClient, webhooks events.Publisher) *LessonHandler {
+	return &LessonHandler{
+		completions:   database.NewUserScopedCollection(completions),
+		contentClient: contentClient,
+		webhooks:      webhooks,
+	}
+}
+
+// CompleteLesson records that the caller finished a lesson with the given score.
+func (h *LessonHandler) CompleteLesson(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	lessonID := c.Param("lessonId")
+
+	var req struct {
+		Score *int `json:"score" binding:"required,min=0,max=100"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	grpcRes, err := h.contentClient.GetLessonBatch(ctx, &pb_content.GetLessonBatchRequest{LessonIds: []string{lessonID}})
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+	if _, ok := grpcRes.Lessons[lessonID]; !ok {
+		c.Error(apierror.NotFound("not_found", "Lesson not found."))
+		return
+	}
+
+	now := time.Now().UTC()
+	filter := bson.M{"user_id": userID.(string), "lesson_id": lessonID}
+	update := bson.M{
+		"$setOnInsert": bson.M{"completed_at": now},
+		"$set":         bson.M{"last_completed_at": now, "score": *req.Score},
+		"$max":         bson.M{"best_score": *req.Score},
+		"$inc":         bson.M{"completions": 1},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var completion models.LessonCompletion
+	if err := h.completions.FindOneAndUpdate(c, filter, update, opts).Decode(&completion); err != nil {
+		c.Error(apierror.Internal("update_failed", err))
+		return
+	}
+	events.PublishAsync(h.webhooks, events.New(events.TypeLessonCompleted, userID.(string), map[string]interface{}{
+		"lesson_id":   lessonID,
+		"score":       completion.Score,
+		"best_score":  completion.BestScore,
+		"completions": completion.Completions,
+	}))
+
+	c.JSON(http.StatusOK, completion)
+}
+
+// GetCompletedLessons lists the lessons the caller has finished, in the order they were first completed.
+func (h *LessonHandler) GetCompletedLessons(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	opts := options.Find().SetSort(bson.D{{Key: "completed_at", Value: 1}})
+	completions, err := database.FindAll[models.LessonCompletion](c, h.completions, bson.M{"user_id": userID.(string)}, opts)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"lessons": completions})
+}
+
+// GetUnlockedLessons lists every lesson in study order with its prerequisites, and whether the caller
+// has completed and unlocked it.
+func (h *LessonHandler) GetUnlockedLessons(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	h.writeUnlocks(c, userID.(string))
+}
+
+// GetUserUnlockedLessons is GetUnlockedLessons for another service, which names the user in the path.
+// The quiz service uses it to refuse quizzes over locked lessons.
+func (h *LessonHandler) GetUserUnlockedLessons(c *gin.Context) {
+	h.writeUnlocks(c, c.Param("userId"))
+}
+
+// writeUnlocks combines the user's completions with the content service's prerequisite graph.
+func (h *LessonHandler) writeUnlocks(c *gin.Context, userID string) {
+	completions, err := database.FindAll[models.LessonCompletion](c, h.completions, bson.M{"user_id": userID})
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	completed := make(map[string]bool, len(completions))
+	completedIDs := make([]string, len(completions))
+	for i, completion := range completions {
+		completed[completion.LessonID] = true
+		completedIDs[i] = completion.LessonID
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	grpcRes, err := h.contentClient.GetLessonAvailability(ctx, &pb_content.GetLessonAvailabilityRequest{CompletedLessonIds: completedIDs})
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+		return
+	}
+
+	lessons := make([]models.LessonUnlock, len(grpcRes.Lessons))
+	for i, lesson := range grpcRes.Lessons {
+		lessons[i] = models.LessonUnlock{
+			LessonID:      lesson.Id,
+			Prerequisites: lesson.PrerequisiteIds,
+			Completed:     completed[lesson.Id],
+			Unlocked:      lesson.Unlocked,
+		}
+		if lessons[i].Prerequisites == nil {
+			lessons[i].Prerequisites = []string{}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"lessons": lessons})
+}
@@ -0,0 +1,138 @@
+// FILE: services/users/internal/handlers/mastery_handlers.go
+// This file serves the per-word mastery levels behind the client's progress rings. The quiz
+// service keeps the levels; the content service says which words a lesson has.
+
+package handlers
+
+import (
+	"net/http"
+	"sort"
+
+	pb_common "wise-owl/gen/proto/common/v1"
+	pb_content "wise-owl/gen/proto/content/v1"
+	pb_quiz "wise-owl/gen/proto/quiz/v1"
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// masteredLevel is the level at which a word counts as mastered
+const masteredLevel = 5
+
+// lessonPageSize is the page size used to list a lesson's words, the most SearchVocabulary allows
+const lessonPageSize = 200
+
+// MasteryHandler holds dependencies for the mastery handlers.
+type MasteryHandler struct {
+	quizClient    pb_quiz.QuizServiceClient
+	contentClient pb_content.ContentServiceClient // Lists the words of a lesson
+}
+
+// NewMasteryHandler creates a new handler with its dependencies.
+func NewMasteryHandler(quizClient pb_quiz.QuizServiceClient, contentClient pb_content.ContentServiceClient) *MasteryHandler {
+	return &MasteryHandler{quizClient: quizClient, contentClient: contentClient}
+}
+
+// masteryItem is one word's mastery level, from 0 (new) to 5 (mastered)
+type masteryItem struct {
+	VocabularyID string `json:"vocabulary_id"`
+	Level        int32  `json:"level"`
+}
+
+// masterySummary condenses the levels into what a progress ring shows
+type masterySummary struct {
+	Words        int     `json:"words"`
+	Mastered     int     `json:"mastered"`
+	AverageLevel float64 `json:"average_level"`
+}
+
+// GetMastery returns the caller's mastery level of each word with a summary. With ?lesson= it
+// covers every word of that lesson, including ones never practiced (level 0); without it, the
+// words the caller has practiced.
+func (h *MasteryHandler) GetMastery(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var query struct {
+		Lesson string `form:"lesson" binding:"omitempty,max=100"`
+	}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	var vocabularyIDs []string
+	if query.Lesson != "" {
+		ids, err := h.lessonVocabulary(c, query.Lesson)
+		if err != nil {
+			c.Error(apierror.ServiceUnavailable("content_service_unavailable", "The content service is unavailable.", err))
+			return
+		}
+		if len(ids) == 0 {
+			c.Error(apierror.NotFound("not_found", "Lesson not found."))
+			return
+		}
+		vocabularyIDs = ids
+	}
+
+	ctx, cancel := ctxutil.GRPC(c)
+	defer cancel()
+
+	grpcRes, err := h.quizClient.GetMastery(ctx, &pb_quiz.GetMasteryRequest{UserId: userID.(string), VocabularyIds: vocabularyIDs})
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("quiz_service_unavailable", "The quiz service is unavailable.", err))
+		return
+	}
+
+	if vocabularyIDs == nil {
+		for id := range grpcRes.Levels {
+			vocabularyIDs = append(vocabularyIDs, id)
+		}
+		sort.Strings(vocabularyIDs)
+	}
+	items := make([]masteryItem, len(vocabularyIDs))
+	summary := masterySummary{Words: len(vocabularyIDs)}
+	total := 0
+	for i, id := range vocabularyIDs {
+		level := grpcRes.Levels[id]
+		items[i] = masteryItem{VocabularyID: id, Level: level}
+		total += int(level)
+		if level >= masteredLevel {
+			summary.Mastered++
+		}
+	}
+	if summary.Words > 0 {
+		summary.AverageLevel = float64(total) / float64(summary.Words)
+	}
+
+	response := gin.H{"items": items, "summary": summary}
+	if query.Lesson != "" {
+		response["lesson"] = query.Lesson
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// lessonVocabulary returns the IDs of a lesson's words in the content service's order
+func (h *MasteryHandler) lessonVocabulary(c *gin.Context, lesson string) ([]string, error) {
+	var ids []string
+	pageToken := ""
+	for {
+		ctx, cancel := ctxutil.GRPC(c)
+		page, err := h.contentClient.SearchVocabulary(ctx, &pb_content.SearchVocabularyRequest{
+			Filters:    &pb_content.VocabularyFilters{Lessons: []string{lesson}},
+			Pagination: &pb_common.Pagination{PageSize: lessonPageSize, PageToken: pageToken},
+		})
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Items {
+			ids = append(ids, item.Id)
+		}
+		pageToken = page.GetPageInfo().GetNextPageToken()
+		if pageToken == "" {
+			return ids, nil
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// FILE: services/users/internal/handlers/streak_handlers.go
+// This file contains the daily activity and streak endpoints.
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/events"
+	"wise-owl/services/users/internal/models"
+	"wise-owl/services/users/internal/streak"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxStreakUpdateAttempts bounds the retries when concurrent activity races on the same user.
+const maxStreakUpdateAttempts = 3
+
+// GetStreak returns the caller's streak as of today in their timezone.
+func (h *UserHandler) GetStreak(c *gin.Context) {
+	auth0ID, _ := c.Get("userID")
+
+	var user models.User
+	err := h.collection.FindOne(c, byIdentity(auth0ID.(string))).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.Error(apierror.NotFound("not_found", "User profile not found."))
+			return
+		}
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	loc := streak.Location(user.Timezone)
+	today := streak.LocalDate(time.Now(), loc)
+	c.JSON(http.StatusOK, gin.H{
+		"streak":   streak.Effective(user.Streak, today),
+		"timezone": loc.String(),
+		"today":    today,
+	})
+}
+
+// RecordActivity marks the caller as active today and returns the updated streak.
+func (h *UserHandler) RecordActivity(c *gin.Context) {
+	auth0ID, _ := c.Get("userID")
+
+	updated, err := h.recordActivity(c, auth0ID.(string), time.Now().UTC())
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.Error(apierror.NotFound("not_found", "User profile not found."))
+			return
+		}
+		c.Error(apierror.Internal("update_failed", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"streak": updated})
+}
+
+// HandleEvent records learning activity published by other services.
+// Events for users without a profile are ignored.
+func (h *UserHandler) HandleEvent(ctx context.Context, event events.Event) error {
+	switch event.Type {
+	case events.TypeQuizAnswer, events.TypeSRSReview, events.TypeLessonViewed:
+	default:
+		return nil
+	}
+	if event.UserID == "" {
+		return nil
+	}
+
+	occurredAt := event.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now().UTC()
+	}
+
+	_, err := h.recordActivity(ctx, event.UserID, occurredAt)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	return err
+}
+
+// recordActivity stores the day's activity and advances the user's streak.
+func (h *UserHandler) recordActivity(ctx context.Context, auth0ID string, at time.Time) (models.Streak, error) {
+	for attempt := 0; attempt < maxStreakUpdateAttempts; attempt++ {
+		var user models.User
+		if err := h.collection.FindOne(ctx, byIdentity(auth0ID)).Decode(&user); err != nil {
+			return models.Streak{}, err
+		}
+
+		date := streak.LocalDate(at, streak.Location(user.Timezone))
+		if attempt == 0 {
+			// Activity is kept under the primary identity so linked identities share one history
+			if err := h.upsertDailyActivity(ctx, user.Auth0ID, date, at); err != nil {
+				return models.Streak{}, err
+			}
+		}
+
+		updated := streak.Record(user.Streak, date)
+		if updated == user.Streak {
+			return updated, nil
+		}
+
+		// Only apply the new streak if no other request advanced it since we read it.
+		filter := bson.M{"_id": user.ID, "streak.last_active_date": user.Streak.LastActiveDate}
+		if user.Streak.LastActiveDate == "" {
+			filter["streak.last_active_date"] = bson.M{"$exists": false}
+		}
+		update := bson.M{"$set": bson.M{"streak": updated, "updated_at": changefeed.Now()}}
+		result, err := h.collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return models.Streak{}, err
+		}
+		if result.MatchedCount > 0 {
+			if updated.Current > user.Streak.Current && updated.Current%streak.FreezeMilestone == 0 {
+				events.PublishAsync(h.webhooks, events.New(events.TypeStreakMilestone, auth0ID, map[string]interface{}{
+					"current": updated.Current,
+					"longest": updated.Longest,
+				}))
+			}
+			return updated, nil
+		}
+	}
+	return models.Streak{}, errors.New("streak update conflicted with concurrent activity")
+}
+
+// upsertDailyActivity increments the activity counter for a user's local day.
+func (h *UserHandler) upsertDailyActivity(ctx context.Context, auth0ID, date string, at time.Time) error {
+	filter := bson.M{"user_id": auth0ID, "date": date}
+	update := bson.M{
+		"$inc": bson.M{"count": 1},
+		"$min": bson.M{"first_at": at},
+		"$max": bson.M{"last_at": at},
+	}
+	_, err := h.activity.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,94 @@
+// FILE: services/users/internal/handlers/subscription_handlers.go
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/validation"
+	"wise-owl/services/users/internal/models"
+	"wise-owl/services/users/internal/subscriptions"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SubscriptionHandler holds dependencies for the subscription handlers.
+type SubscriptionHandler struct {
+	store     *subscriptions.Store
+	verifiers map[string]subscriptions.Verifier // Keyed by platform; stores without one cannot be verified
+}
+
+// NewSubscriptionHandler creates a new handler with its dependencies.
+func NewSubscriptionHandler(store *subscriptions.Store, verifiers map[string]subscriptions.Verifier) *SubscriptionHandler {
+	return &SubscriptionHandler{store: store, verifiers: verifiers}
+}
+
+// subscriptionResponse reports a user's current tier and their latest store subscription.
+func subscriptionResponse(user *models.User) gin.H {
+	return gin.H{
+		"tier":         user.TierAt(time.Now()),
+		"subscription": user.Subscription, // null if the user never bought one
+	}
+}
+
+// GetSubscription reports the caller's tier and subscription.
+func (h *SubscriptionHandler) GetSubscription(c *gin.Context) {
+	user, err := h.store.Find(c, c.GetString("userID"))
+	if err != nil {
+		c.Error(subscriptionError(err))
+		return
+	}
+	c.JSON(http.StatusOK, subscriptionResponse(user))
+}
+
+// RecordPurchase verifies a receipt of a subscription bought in the App Store or Play Store with
+// that store and records it as the caller's subscription. Clients submit the receipt after each
+// purchase and renewal: a StoreKit 2 signed transaction, or a Play purchase token.
+func (h *SubscriptionHandler) RecordPurchase(c *gin.Context) {
+	var req struct {
+		Platform string `json:"platform" binding:"required,oneof=app_store play_store"`
+		Receipt  string `json:"receipt" binding:"required,max=20000"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	verifier, ok := h.verifiers[req.Platform]
+	if !ok {
+		c.Error(apierror.ServiceUnavailable("store_unavailable", "Purchases from this store cannot be verified right now.", fmt.Errorf("no %s verifier configured", req.Platform)))
+		return
+	}
+	verification, err := verifier.Verify(c, req.Receipt)
+	if errors.Is(err, subscriptions.ErrInvalidReceipt) {
+		c.Error(apierror.BadRequest("invalid_receipt", "The store did not confirm this purchase.").Wrap(err))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.ServiceUnavailable("store_unavailable", "Purchases from this store cannot be verified right now.", err))
+		return
+	}
+
+	user, err := h.store.Record(c, c.GetString("userID"), verification, time.Now().UTC())
+	if err != nil {
+		c.Error(subscriptionError(err))
+		return
+	}
+	c.JSON(http.StatusOK, subscriptionResponse(user))
+}
+
+// subscriptionError maps a subscription store error to its API error.
+func subscriptionError(err error) *apierror.Error {
+	switch {
+	case errors.Is(err, subscriptions.ErrUserNotFound):
+		return apierror.NotFound("not_found", "User profile not found.")
+	case errors.Is(err, subscriptions.ErrPurchaseInUse):
+		return apierror.Conflict("purchase_in_use", "This purchase belongs to another account.")
+	default:
+		return apierror.Internal("database_error", err)
+	}
+}
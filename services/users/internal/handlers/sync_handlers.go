@@ -0,0 +1,100 @@
+// FILE: services/users/internal/handlers/sync_handlers.go
+// This file serves the users service's side of offline sync. The quiz service hosts the sync API
+// and merges these changes into its pulls.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/ctxutil"
+	"wise-owl/lib/database"
+	"wise-owl/services/users/internal/models"
+	"wise-owl/services/users/internal/streak"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SyncHandler holds dependencies for the change feed handlers.
+type SyncHandler struct {
+	users       database.CollectionInterface
+	completions database.CollectionInterface
+	favorites   database.CollectionInterface
+	tombstones  *mongo.Collection
+}
+
+// NewSyncHandler creates a new handler over the users database.
+func NewSyncHandler(db *mongo.Database) *SyncHandler {
+	return &SyncHandler{
+		users:       database.NewUserScopedCollection(db.Collection("users")),
+		completions: database.NewUserScopedCollection(db.Collection("lesson_completions")),
+		favorites:   database.NewUserScopedCollection(db.Collection("favorites")),
+		tombstones:  db.Collection(changefeed.TombstoneCollection),
+	}
+}
+
+// GetUserChanges returns the profile, lesson completions, and favorites of the user in the path that
+// changed at or after ?since= (RFC 3339), or all of them when it is absent, along with the favorites
+// removed since. The profile is null when it did not change or the user has not onboarded.
+func (h *SyncHandler) GetUserChanges(c *gin.Context) {
+	userID := c.Param("userId")
+
+	var since time.Time
+	if raw := c.Query("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			c.Error(apierror.BadRequest("invalid_since", "since must be an RFC 3339 time."))
+			return
+		}
+		since = parsed
+	}
+
+	ctx, cancel := ctxutil.Database(c)
+	defer cancel()
+
+	var profile *models.User
+	var user models.User
+	filter := byIdentity(userID)
+	if !since.IsZero() {
+		filter["updated_at"] = bson.M{"$gte": since}
+	}
+	err := h.users.FindOne(ctx, filter).Decode(&user)
+	switch {
+	case err == nil:
+		// Report the streak as GetUserProfile does
+		user.Streak = streak.Effective(user.Streak, streak.LocalDate(time.Now(), streak.Location(user.Timezone)))
+		profile = &user
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	completions, err := database.FindAll[models.LessonCompletion](ctx, h.completions, changefeed.Filter("user_id", userID, "last_completed_at", since))
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	favorites, err := database.FindAll[models.Favorite](ctx, h.favorites, changefeed.Filter("user_id", userID, "created_at", since))
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	deletions, err := changefeed.Deletions(ctx, h.tombstones, userID, since)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"profile":            profile,
+		"lesson_completions": completions,
+		"favorites":          favorites,
+		"deletions":          deletions,
+	})
+}
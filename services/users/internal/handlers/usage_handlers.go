@@ -0,0 +1,81 @@
+// FILE: services/users/internal/handlers/usage_handlers.go
+// This file reports users' API usage, which every service counts into the users database.
+
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/usage"
+	"wise-owl/lib/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageHandler holds dependencies for the usage handlers.
+type UsageHandler struct {
+	usage  *usage.Store
+	tiers  usage.TierSource
+	limits usage.Limits
+}
+
+// NewUsageHandler creates a new handler with its dependencies.
+func NewUsageHandler(store *usage.Store, tiers usage.TierSource, limits usage.Limits) *UsageHandler {
+	return &UsageHandler{usage: store, tiers: tiers, limits: limits}
+}
+
+// usageDay is one UTC day of a user's API calls.
+type usageDay struct {
+	Day      string           `json:"day"`
+	Total    int64            `json:"total"`
+	Services map[string]int64 `json:"services"` // Calls per service, e.g. {"quiz": 120}
+}
+
+// GetUsage reports the caller's tier, its daily limit, and their calls per service over the last
+// days (default 7, at most 30), newest day first. Days without calls are left out.
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	userID := c.GetString("userID")
+	query := struct {
+		Days int `form:"days" binding:"omitempty,min=1,max=30"`
+	}{Days: 7}
+	if err := validation.BindQuery(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	tier, err := h.tiers.Tier(c, userID)
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+	now := time.Now().UTC()
+	counts, err := h.usage.Since(c, userID, now.AddDate(0, 0, 1-query.Days))
+	if err != nil {
+		c.Error(apierror.Internal("database_error", err))
+		return
+	}
+
+	days := []usageDay{}
+	for _, count := range counts {
+		if len(days) == 0 || days[len(days)-1].Day != count.Day {
+			days = append(days, usageDay{Day: count.Day, Services: map[string]int64{}})
+		}
+		day := &days[len(days)-1]
+		day.Services[count.Service] = count.Calls
+		day.Total += count.Calls
+	}
+
+	// A limit of 0 is unlimited, reported as null
+	var dailyLimit *int64
+	if limit := h.limits.For(tier); limit > 0 {
+		dailyLimit = &limit
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"tier":        tier,
+		"daily_limit": dailyLimit,
+		"resets_at":   usage.ResetAt(now),
+		"days":        days,
+	})
+}
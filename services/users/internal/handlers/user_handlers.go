@@ -4,100 +4,220 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"time"
 
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/auth"
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/database"
+	"wise-owl/lib/events"
+	"wise-owl/lib/httpcache"
+	"wise-owl/lib/i18n"
+	"wise-owl/lib/storage"
+	"wise-owl/lib/validation"
 	"wise-owl/services/users/internal/models"
+	"wise-owl/services/users/internal/streak"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// IdentityProvider looks up and links users at the identity provider. *auth.ManagementClient implements it.
+type IdentityProvider interface {
+	GetUser(ctx context.Context, userID string) (*auth.ManagementUser, error)
+	LinkIdentity(ctx context.Context, primaryID, idToken string) ([]auth.Identity, error)
+}
+
 // UserHandler holds dependencies, such as the database collection handle.
 type UserHandler struct {
-	collection *mongo.Collection
+	collection database.CollectionInterface // Users, checked against unscoped queries
+	activity   *mongo.Collection
+	store      storage.BlobStore
+	identities IdentityProvider
+	webhooks   events.Publisher // Receives streak milestones for users' and apps' webhooks
 }
 
 // NewUserHandler creates a new handler with its dependencies.
-func NewUserHandler(collection *mongo.Collection) *UserHandler {
-	return &UserHandler{collection: collection}
+// Daily activity is stored alongside the users collection in the same database; avatars go to store.
+// When identities is nil, onboarding trusts the email the client sends (development without Auth0).
+func NewUserHandler(collection *mongo.Collection, store storage.BlobStore, identities IdentityProvider, webhooks events.Publisher) *UserHandler {
+	return &UserHandler{
+		collection: database.NewUserScopedCollection(collection),
+		activity:   collection.Database().Collection("activity"),
+		store:      store,
+		identities: identities,
+		webhooks:   webhooks,
+	}
+}
+
+// byIdentity matches the user that any of their linked Auth0 identities belongs to.
+func byIdentity(auth0ID string) bson.M {
+	return bson.M{"auth0_ids": auth0ID}
 }
 
 // OnboardUser creates a user profile after initial Auth0 sign-up.
+// The email, its verification status, the picture, and the locale come from the Auth0 Management API;
+// an email in the request is only used when no identity provider is configured.
 func (h *UserHandler) OnboardUser(c *gin.Context) {
 	auth0ID, _ := c.Get("userID")
 
 	var req struct {
-		Username string `json:"username" binding:"required"`
-		Email    string `json:"email" binding:"required,email"`
+		Username string `json:"username" binding:"required,min=3,max=30"`
+		Email    string `json:"email" binding:"omitempty,email"`
+		Timezone string `json:"timezone" binding:"omitempty,timezone"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
-		return
-	}
-
-	// Check if user already exists
-	count, err := h.collection.CountDocuments(c, bson.M{"auth0_id": auth0ID.(string)})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "database_error"})
-		return
-	}
-	if count > 0 {
-		c.JSON(http.StatusConflict, gin.H{"error": "user_exists", "message": "User profile already exists."})
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
 	newUser := models.User{
 		ID:       primitive.NewObjectID(),
 		Auth0ID:  auth0ID.(string),
+		Auth0IDs: []string{auth0ID.(string)},
 		Username: req.Username,
 		Email:    req.Email,
+		Timezone: req.Timezone,
 		NotificationPrefs: models.NotificationPreferences{
 			Enabled: false, // Notifications are off by default
 		},
 		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
+		UpdatedAt: changefeed.Now(),
+		Version:   1,
 	}
 
-	_, err = h.collection.InsertOne(c, newUser)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "create_failed"})
+	if h.identities != nil {
+		ctx, cancel := context.WithTimeout(c, 5*time.Second)
+		defer cancel()
+
+		identity, err := h.identities.GetUser(ctx, newUser.Auth0ID)
+		if err != nil {
+			if errors.Is(err, auth.ErrUserNotFound) {
+				c.Error(apierror.Unauthorized("unknown_identity", "No Auth0 user matches this token."))
+				return
+			}
+			c.Error(apierror.ServiceUnavailable("identity_provider_unavailable", "The identity provider is unavailable.", err))
+			return
+		}
+		newUser.Email = identity.Email
+		newUser.EmailVerified = identity.EmailVerified
+		newUser.AvatarURL = identity.Picture
+		newUser.Locale = identity.PreferredLocale()
+	}
+	// Start in the language of the identity provider's locale, or else the one the app asks for
+	locale := newUser.Locale
+	if locale == "" {
+		locale = c.GetHeader("Accept-Language")
+	}
+	newUser.Language = i18n.Negotiate(locale)
+	if newUser.Email == "" {
+		c.Error(apierror.BadRequest("email_required", "An email address is required."))
 		return
 	}
 
+	// The unique auth0_id and auth0_ids indexes reject a second profile, even from concurrent requests
+	// or from an identity already linked to another user
+	if err := database.InsertUnique(c, h.collection, newUser); err != nil {
+		if !errors.Is(err, database.ErrDuplicate) {
+			c.Error(apierror.Internal("create_failed", err))
+			return
+		}
+		// A profile the Auth0 webhook created ahead of the client is completed instead of rejected
+		completed, err := h.completeProvisional(c, newUser)
+		if err == mongo.ErrNoDocuments {
+			c.Error(apierror.Conflict("user_exists", "User profile already exists."))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("create_failed", err))
+			return
+		}
+		newUser = *completed
+	}
+
 	c.JSON(http.StatusCreated, newUser)
 }
 
+// completeProvisional applies the onboarding details to a provisional profile and clears the flag.
+// It returns mongo.ErrNoDocuments if the user has no provisional profile.
+func (h *UserHandler) completeProvisional(ctx context.Context, onboarded models.User) (*models.User, error) {
+	updates := bson.M{
+		"username":   onboarded.Username,
+		"email":      onboarded.Email,
+		"updated_at": onboarded.UpdatedAt,
+	}
+	if onboarded.EmailVerified {
+		updates["email_verified"] = true
+	}
+	if onboarded.Timezone != "" {
+		updates["timezone"] = onboarded.Timezone
+	}
+	if onboarded.Locale != "" {
+		updates["locale"] = onboarded.Locale
+	}
+	if onboarded.Language != "" {
+		updates["language"] = onboarded.Language
+	}
+
+	var user models.User
+	err := h.collection.FindOneAndUpdate(ctx,
+		bson.M{"auth0_id": onboarded.Auth0ID, "provisional": true},
+		database.BumpVersion(bson.M{"$set": updates, "$unset": bson.M{"provisional": ""}}),
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 // GetUserProfile fetches the profile of the currently authenticated user.
 func (h *UserHandler) GetUserProfile(c *gin.Context) {
 	auth0ID, _ := c.Get("userID")
 
 	var user models.User
-	err := h.collection.FindOne(c, bson.M{"auth0_id": auth0ID.(string)}).Decode(&user)
+	err := h.collection.FindOne(c, byIdentity(auth0ID.(string))).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			c.JSON(http.StatusNotFound, gin.H{"error": "not_found", "message": "User profile not found."})
+			c.Error(apierror.NotFound("not_found", "User profile not found."))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "database_error"})
+		c.Error(apierror.Internal("database_error", err))
 		return
 	}
 
+	// Report the streak as of today in the user's timezone, not as of their last activity
+	today := streak.LocalDate(time.Now(), streak.Location(user.Timezone))
+	user.Streak = streak.Effective(user.Streak, today)
+
+	// Clients send the version back in If-Match to make their next edit conditional on it
+	c.Header("ETag", httpcache.VersionETag(user.Version))
 	c.JSON(http.StatusOK, user)
 }
 
 // UpdateUserProfile allows a user to update their own profile information.
+// An If-Match header, or "version" in the body, makes the update apply only to that version of
+// the profile, so an edit from a stale copy on another device is refused with 412 rather than
+// overwriting a newer one. The new version comes back in the ETag header.
 func (h *UserHandler) UpdateUserProfile(c *gin.Context) {
 	auth0ID, _ := c.Get("userID")
 
 	var req struct {
-		Username          *string                         `json:"username"`
+		Version           *int64                          `json:"version"`
+		Username          *string                         `json:"username" binding:"omitempty,min=3,max=30"`
 		NotificationPrefs *models.NotificationPreferences `json:"notification_preferences"`
+		Timezone          *string                         `json:"timezone" binding:"omitempty,timezone"`
+		Romanization      *string                         `json:"romanization" binding:"omitempty,oneof=hepburn kunrei none"`
+		Language          *string                         `json:"language" binding:"omitempty,oneof=en my"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -108,42 +228,85 @@ func (h *UserHandler) UpdateUserProfile(c *gin.Context) {
 	if req.NotificationPrefs != nil {
 		updates["notification_prefs"] = *req.NotificationPrefs
 	}
+	if req.Timezone != nil {
+		updates["timezone"] = *req.Timezone
+	}
+	if req.Romanization != nil {
+		updates["romanization"] = *req.Romanization
+	}
+	if req.Language != nil {
+		updates["language"] = *req.Language
+	}
 
 	if len(updates) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "no_updates_provided"})
+		c.Error(apierror.BadRequest("no_updates_provided", "No updatable fields were provided."))
 		return
 	}
 
-	updates["updated_at"] = time.Now().UTC()
-	filter := bson.M{"auth0_id": auth0ID.(string)}
-	updateDoc := bson.M{"$set": updates}
+	version, conditional, apiErr := httpcache.ExpectedVersion(c, req.Version)
+	if apiErr != nil {
+		c.Error(apiErr)
+		return
+	}
 
-	result, err := h.collection.UpdateOne(c, filter, updateDoc)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "update_failed"})
+	updates["updated_at"] = changefeed.Now()
+	filter := byIdentity(auth0ID.(string))
+	if conditional {
+		filter = database.MatchVersion(filter, version)
+	}
+	updateDoc := database.BumpVersion(bson.M{"$set": updates})
+
+	var updated models.User
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{"version": 1})
+	err := h.collection.FindOneAndUpdate(c, filter, updateDoc, opts).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		h.rejectUpdate(c, auth0ID.(string), conditional)
 		return
 	}
-	if result.MatchedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+	if err != nil {
+		c.Error(apierror.Internal("update_failed", err))
 		return
 	}
 
+	c.Header("ETag", httpcache.VersionETag(updated.Version))
 	c.Status(http.StatusNoContent)
 }
 
+// rejectUpdate reports why an update matched no profile: there is none, or, for a conditional
+// update, it has moved past the version the client named. A conflict carries the current ETag.
+func (h *UserHandler) rejectUpdate(c *gin.Context, auth0ID string, conditional bool) {
+	if conditional {
+		var current models.User
+		err := h.collection.FindOne(c, byIdentity(auth0ID), options.FindOne().SetProjection(bson.M{"version": 1})).Decode(&current)
+		if err == nil {
+			c.Header("ETag", httpcache.VersionETag(current.Version))
+			c.Error(httpcache.VersionConflict())
+			return
+		}
+		if err != mongo.ErrNoDocuments {
+			c.Error(apierror.Internal("database_error", err))
+			return
+		}
+	}
+	c.Error(apierror.NotFound("not_found", "User profile not found."))
+}
+
 // DeleteUserAccount handles the deletion of a user's account.
 func (h *UserHandler) DeleteUserAccount(c *gin.Context) {
 	auth0ID, _ := c.Get("userID")
 
-	filter := bson.M{"auth0_id": auth0ID.(string)}
-	result, err := h.collection.DeleteOne(c, filter)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete_failed"})
+	filter := byIdentity(auth0ID.(string))
+	var deleted models.User
+	if err := h.collection.FindOneAndDelete(c, filter).Decode(&deleted); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.Error(apierror.NotFound("not_found", "User profile not found."))
+			return
+		}
+		c.Error(apierror.Internal("delete_failed", err))
 		return
 	}
-	if result.DeletedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
-		return
+	if deleted.AvatarKey != "" {
+		h.deleteAvatar(c, deleted.AvatarKey)
 	}
 
 	// TODO: In a real system, you would publish a 'UserDeleted' event here
@@ -0,0 +1,121 @@
+// FILE: services/users/internal/handlers/webhook_handlers.go
+// This file receives user lifecycle webhooks pushed by Auth0 Actions.
+
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"wise-owl/lib/apierror"
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/validation"
+	"wise-owl/services/users/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Auth0 webhook event types, sent by the post-user-registration and post-login Actions.
+const (
+	Auth0UserCreated       = "user.created"
+	Auth0UserEmailVerified = "user.email_verified"
+)
+
+// auth0WebhookUser is the user object an Action sends, using Auth0's field names.
+type auth0WebhookUser struct {
+	UserID        string `json:"user_id" binding:"required"`
+	Email         string `json:"email" binding:"omitempty,email"`
+	EmailVerified bool   `json:"email_verified"`
+	Nickname      string `json:"nickname"`
+	Picture       string `json:"picture"`
+	Locale        string `json:"locale"`
+}
+
+// HandleAuth0Webhook creates a provisional profile when a user registers or verifies their email,
+// so the profile exists even if the client never calls /onboarding. Requests are verified by
+// auth.VerifyWebhook before they reach this handler. Both events are idempotent.
+func (h *UserHandler) HandleAuth0Webhook(c *gin.Context) {
+	var req struct {
+		Type string           `json:"type" binding:"required"`
+		User auth0WebhookUser `json:"user"`
+	}
+	if err := validation.BindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	switch req.Type {
+	case Auth0UserCreated, Auth0UserEmailVerified:
+	default:
+		// Acknowledge so Auth0 does not retry events this service does not handle
+		log.Printf("WARN: Ignoring Auth0 webhook of type %q", req.Type)
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	if err := h.provisionUser(c, req.User, req.Type == Auth0UserEmailVerified); err != nil {
+		c.Error(apierror.Internal("webhook_failed", err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// provisionUser creates a provisional profile for the Auth0 user unless one exists.
+// When emailVerified is set, an existing profile is marked verified as well.
+func (h *UserHandler) provisionUser(ctx context.Context, u auth0WebhookUser, emailVerified bool) error {
+	now := changefeed.Now()
+	onInsert := bson.M{
+		"_id":                primitive.NewObjectID(),
+		"auth0_id":           u.UserID,
+		"auth0_ids":          []string{u.UserID},
+		"username":           provisionalUsername(u.Nickname, u.Email),
+		"email":              u.Email,
+		"notification_prefs": models.NotificationPreferences{Enabled: false},
+		"streak":             models.Streak{},
+		"provisional":        true,
+		"created_at":         now,
+	}
+	if u.Picture != "" {
+		onInsert["avatar_url"] = u.Picture
+	}
+	if u.Locale != "" {
+		onInsert["locale"] = u.Locale
+	}
+
+	update := bson.M{}
+	if emailVerified {
+		update["$set"] = bson.M{"email_verified": true, "updated_at": now}
+	} else {
+		onInsert["email_verified"] = u.EmailVerified
+		onInsert["updated_at"] = now
+	}
+	update["$setOnInsert"] = onInsert
+
+	opts := options.Update().SetUpsert(true)
+	_, err := h.collection.UpdateOne(ctx, byIdentity(u.UserID), update, opts)
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent request created the profile first; apply the update to it
+		_, err = h.collection.UpdateOne(ctx, byIdentity(u.UserID), update)
+	}
+	return err
+}
+
+// provisionalUsername derives a placeholder username from the Auth0 nickname or the email's local part.
+// The user picks their own when they onboard.
+func provisionalUsername(nickname, email string) string {
+	name := nickname
+	if name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+	if runes := []rune(name); len(runes) > 30 {
+		name = string(runes[:30])
+	}
+	return name
+}
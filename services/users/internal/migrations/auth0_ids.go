@@ -0,0 +1,41 @@
+// FILE: services/users/internal/migrations/auth0_ids.go
+// Moves users from a single auth0_id to the auth0_ids list of linked identities.
+
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"wise-owl/lib/database"
+)
+
+// Auth0IDs seeds users.auth0_ids with the user's original auth0_id.
+// auth0_id stays as the primary identity, so only the backfill half of the migration applies:
+// once identities are linked the two fields are no longer expected to agree.
+var Auth0IDs = database.FieldMigration{
+	Name:        "users-auth0-ids",
+	LegacyField: "auth0_id",
+	TargetField: "auth0_ids",
+	Transform: func(legacy interface{}) (interface{}, error) {
+		auth0ID, ok := legacy.(string)
+		if !ok || auth0ID == "" {
+			return nil, fmt.Errorf("auth0_id is %T %v, not a non-empty string", legacy, legacy)
+		}
+		return []string{auth0ID}, nil
+	},
+}
+
+// RunAuth0IDs backfills auth0_ids on users created before account linking.
+// It must finish before the unique auth0_ids index is built, since users without the field would collide.
+func RunAuth0IDs(ctx context.Context, collection database.CollectionInterface) error {
+	result, err := Auth0IDs.Backfill(ctx, collection, database.BackfillOptions{BatchSize: 500})
+	if err != nil {
+		return err
+	}
+	if result.Failed > 0 {
+		log.Printf("WARN: %d users could not be given auth0_ids: %v", result.Failed, result.Errors)
+	}
+	return nil
+}
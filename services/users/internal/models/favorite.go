@@ -0,0 +1,17 @@
+// FILE: services/users/internal/models/favorite.go
+
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Favorite is a vocabulary word a user bookmarked. There is one per user and word.
+type Favorite struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID       string             `bson:"user_id" json:"user_id"`             // Auth0 ID
+	VocabularyID string             `bson:"vocabulary_id" json:"vocabulary_id"` // Content vocabulary ID
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,30 @@
+// FILE: services/users/internal/models/lesson_completion.go
+
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LessonCompletion records that a user finished a lesson. There is one per user and lesson;
+// finishing the lesson again updates the latest score and keeps the best one.
+type LessonCompletion struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID          string             `bson:"user_id" json:"user_id"`           // Auth0 ID
+	LessonID        string             `bson:"lesson_id" json:"lesson_id"`       // Content lesson identifier, e.g. "lesson-1"
+	CompletedAt     time.Time          `bson:"completed_at" json:"completed_at"` // First completion
+	LastCompletedAt time.Time          `bson:"last_completed_at" json:"last_completed_at"`
+	Score           int                `bson:"score" json:"score"`           // Latest score, 0-100
+	BestScore       int                `bson:"best_score" json:"best_score"` // Highest score, 0-100
+	Completions     int                `bson:"completions" json:"completions"`
+}
+
+// LessonUnlock is a lesson with its prerequisites and where the user stands on it.
+type LessonUnlock struct {
+	LessonID      string   `json:"lesson_id"`
+	Prerequisites []string `json:"prerequisites"`
+	Completed     bool     `json:"completed"`
+	Unlocked      bool     `json:"unlocked"` // Every prerequisite has been completed
+}
@@ -0,0 +1,25 @@
+// FILE: services/users/internal/models/purchase.go
+
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Purchase is a store subscription a user submitted a receipt of, as the store last reported it.
+// There is one per subscription, which keeps its original ID across renewals; a subscription
+// belongs to the user who first submitted it.
+type Purchase struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID        string             `bson:"user_id" json:"user_id"`   // Auth0 ID
+	Platform      string             `bson:"platform" json:"platform"` // "app_store" or "play_store"
+	ProductID     string             `bson:"product_id" json:"product_id"`
+	OriginalID    string             `bson:"original_id" json:"-"`                 // App Store original transaction ID, or Play purchase token
+	TransactionID string             `bson:"transaction_id" json:"transaction_id"` // The latest renewal: App Store transaction ID, or Play order ID
+	Environment   string             `bson:"environment" json:"environment"`       // "production" or "sandbox"
+	ExpiresAt     time.Time          `bson:"expires_at" json:"expires_at"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	VerifiedAt    time.Time          `bson:"verified_at" json:"verified_at"`
+}
@@ -5,22 +5,78 @@ package models
 import (
 	"time"
 
+	"wise-owl/lib/usage"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // User represents a user document in the database.
 type User struct {
 	ID                primitive.ObjectID      `bson:"_id,omitempty"`
-	Auth0ID           string                  `bson:"auth0_id"` // The 'sub' claim from the Auth0 JWT. Must be unique.
+	Auth0ID           string                  `bson:"auth0_id"`  // The 'sub' claim from the Auth0 JWT at onboarding. Must be unique.
+	Auth0IDs          []string                `bson:"auth0_ids"` // Every linked Auth0 identity, including Auth0ID. Each may belong to one user only.
 	Username          string                  `bson:"username"`
 	Email             string                  `bson:"email"`
+	EmailVerified     bool                    `bson:"email_verified"` // As reported by Auth0 at onboarding
 	NotificationPrefs NotificationPreferences `bson:"notification_prefs,omitempty"`
+	Timezone          string                  `bson:"timezone,omitempty"` // IANA name (e.g. "Asia/Yangon"); streak days follow this zone
+	AvatarURL         string                  `bson:"avatar_url,omitempty"`
+	AvatarKey         string                  `bson:"avatar_key,omitempty" json:"-"` // Storage key of the current avatar
+	Locale            string                  `bson:"locale,omitempty"`              // BCP 47 tag from the identity provider, e.g. "my-MM"
+	Language          string                  `bson:"language,omitempty"`            // UI language, "en" or "my"; clients send it as Accept-Language
+	Romanization      string                  `bson:"romanization,omitempty"`        // "hepburn" (when empty), "kunrei", or "none"; clients send it as X-Romanization
+	Streak            Streak                  `bson:"streak"`
+	Provisional       bool                    `bson:"provisional,omitempty"`  // Created by the Auth0 webhook; cleared when the client onboards
+	Tier              string                  `bson:"tier,omitempty"`         // Tier granted outside the app stores (e.g. to staff), usage.TierPremium or empty
+	Subscription      *Subscription           `bson:"subscription,omitempty"` // The latest store subscription; absent if the user never bought one
 	CreatedAt         time.Time               `bson:"created_at"`
 	UpdatedAt         time.Time               `bson:"updated_at"`
+	Version           int64                   `bson:"version,omitempty"` // Counts the user's own profile edits, which may be made conditional on it; absent (0) on older profiles
+}
+
+// TierAt returns the user's tier at now: a granted tier, else the tier of a subscription still paid
+// for, else the free tier.
+func (u *User) TierAt(now time.Time) string {
+	if u.Tier != "" {
+		return u.Tier
+	}
+	if u.Subscription != nil && u.Subscription.RenewsAt.After(now) {
+		return u.Subscription.Tier
+	}
+	return usage.TierFree
+}
+
+// Subscription is a user's store subscription as it stood when a receipt of it was last verified.
+type Subscription struct {
+	Tier       string             `bson:"tier" json:"tier"`             // usage.TierPremium for every product sold so far
+	Platform   string             `bson:"platform" json:"platform"`     // "app_store" or "play_store"
+	ProductID  string             `bson:"product_id" json:"product_id"` // The store's product (subscription) ID
+	RenewsAt   time.Time          `bson:"renews_at" json:"renews_at"`   // End of the paid period, or when the store revoked it
+	PurchaseID primitive.ObjectID `bson:"purchase_id" json:"-"`         // The purchases record holding the store's references
+	VerifiedAt time.Time          `bson:"verified_at" json:"verified_at"`
 }
 
 // NotificationPreferences defines the structure for user notification settings.
 type NotificationPreferences struct {
 	Enabled bool   `bson:"enabled"`
-	TimeUTC string `bson:"time_utc"` // Stored as "HH:MM" in UTC
+	TimeUTC string `bson:"time_utc" binding:"omitempty,hhmm"` // Stored as "HH:MM" in UTC
+}
+
+// Streak tracks consecutive days of learning activity in the user's timezone.
+type Streak struct {
+	Current          int    `bson:"current" json:"current"`
+	Longest          int    `bson:"longest" json:"longest"`
+	LastActiveDate   string `bson:"last_active_date,omitempty" json:"last_active_date,omitempty"` // "YYYY-MM-DD" in the user's timezone
+	FreezesAvailable int    `bson:"freezes_available" json:"freezes_available"`
+	FreezesUsed      int    `bson:"freezes_used" json:"freezes_used"`
+}
+
+// DailyActivity records that a user was active on a calendar day in their timezone.
+type DailyActivity struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID  string             `bson:"user_id" json:"user_id"` // Auth0 ID
+	Date    string             `bson:"date" json:"date"`       // "YYYY-MM-DD" in the user's timezone
+	Count   int                `bson:"count" json:"count"`
+	FirstAt time.Time          `bson:"first_at" json:"first_at"`
+	LastAt  time.Time          `bson:"last_at" json:"last_at"`
 }
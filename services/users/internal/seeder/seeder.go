@@ -5,66 +5,59 @@ package seeder
 import (
 	"context"
 	"log"
+	"slices"
+
+	"wise-owl/lib/changefeed"
+	"wise-owl/lib/database"
+	"wise-owl/lib/feedback"
+	"wise-owl/lib/usage"
+	"wise-owl/lib/webhooks"
+	"wise-owl/services/users/internal/migrations"
+	"wise-owl/services/users/internal/subscriptions"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// SeedDatabase initializes the users database if needed
-// Users service typically doesn't need pre-seeded data as users register themselves
+// Indexes lists every index the users service relies on.
+var Indexes = append([]database.Index{
+	// Every lookup is by Auth0 ID, and a user must only be onboarded once
+	{Collection: "users", Keys: bson.D{{Key: "auth0_id", Value: 1}}, Unique: true},
+	// Lookups match any linked identity, and an identity can only be linked to one user
+	{Collection: "users", Keys: bson.D{{Key: "auth0_ids", Value: 1}}, Unique: true},
+	// One activity document per user per day
+	{Collection: "activity", Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "date", Value: -1}}, Unique: true},
+	// One completion document per user per lesson; completing again upserts it
+	{Collection: "lesson_completions", Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "lesson_id", Value: 1}}, Unique: true},
+	// One favorite per user per word; listing sorts a user's favorites by when they were added
+	{Collection: "favorites", Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "vocabulary_id", Value: 1}}, Unique: true},
+	{Collection: "favorites", Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	// The sync feed reads a user's completions by when they last changed
+	{Collection: "lesson_completions", Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "last_completed_at", Value: 1}}},
+	// A store subscription can only be claimed by one user; exports and deletions find a user's purchases
+	{Collection: subscriptions.PurchasesCollection, Keys: bson.D{{Key: "platform", Value: 1}, {Key: "original_id", Value: 1}}, Unique: true},
+	{Collection: subscriptions.PurchasesCollection, Keys: bson.D{{Key: "user_id", Value: 1}}},
+}, slices.Concat(changefeed.Indexes, webhooks.Indexes, usage.Indexes, feedback.Indexes)...)
+
+// SeedDatabase ensures the declared indexes exist.
+// Users service doesn't need pre-seeded data as users register themselves
 func SeedDatabase(db interface{}) {
-	// Handle both database interfaces
-	var collection *mongo.Collection
-
-	switch v := db.(type) {
-	case *mongo.Database:
-		collection = v.Collection("users")
-	default:
+	mongoDB, ok := db.(*mongo.Database)
+	if !ok {
 		// For database.Database interface, we don't need to seed anything
 		// Users are created through the API when they register
 		log.Println("Users service: No seeding required - users register via API")
 		return
 	}
 
-	// Check if any users exist
-	count, err := collection.CountDocuments(context.Background(), bson.M{})
-	if err != nil {
-		log.Printf("WARN: Failed to count documents in users collection: %v", err)
-		return
+	// Users from before account linking need auth0_ids before its unique index can be built
+	if err := migrations.RunAuth0IDs(context.Background(), mongoDB.Collection("users")); err != nil {
+		log.Printf("WARN: Failed to backfill auth0_ids: %v", err)
 	}
 
-	if count > 0 {
-		log.Printf("Users collection already has %d documents. Skipping seed.", count)
-		return
-	}
-
-	// Create indexes for performance
-	err = createIndexes(collection)
-	if err != nil {
-		log.Printf("WARN: Failed to create indexes: %v", err)
+	if err := database.EnsureIndexes(context.Background(), mongoDB, Indexes); err != nil {
+		log.Printf("WARN: Failed to ensure users indexes: %v", err)
 	}
 
 	log.Println("Users service initialized successfully")
 }
-
-// createIndexes creates necessary indexes for the users collection
-func createIndexes(collection *mongo.Collection) error {
-	ctx := context.Background()
-
-	// Create unique index on auth0_id
-	indexModel := mongo.IndexModel{
-		Keys: bson.D{
-			{Key: "auth0_id", Value: 1},
-		},
-		Options: options.Index().SetUnique(true),
-	}
-
-	_, err := collection.Indexes().CreateOne(ctx, indexModel)
-	if err != nil {
-		return err
-	}
-
-	log.Println("Created unique index on auth0_id field")
-	return nil
-}
@@ -0,0 +1,114 @@
+// FILE: services/users/internal/streak/streak.go
+// This package computes daily learning streaks in the user's own timezone.
+
+package streak
+
+import (
+	"time"
+	_ "time/tzdata" // Container images do not ship a zoneinfo database
+
+	"wise-owl/services/users/internal/models"
+)
+
+// DateLayout is the layout of the calendar dates stored on streaks and activity records.
+const DateLayout = "2006-01-02"
+
+const (
+	// MaxFreezes is the most streak freezes a user can hold at once.
+	MaxFreezes = 2
+	// FreezeMilestone awards a streak freeze every time the streak reaches a multiple of this many days.
+	FreezeMilestone = 7
+)
+
+// Location resolves a user's IANA timezone, falling back to UTC when unset or unknown.
+func Location(timezone string) *time.Location {
+	if timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ValidTimezone reports whether timezone is a known IANA timezone name.
+func ValidTimezone(timezone string) bool {
+	if timezone == "" {
+		return false
+	}
+	_, err := time.LoadLocation(timezone)
+	return err == nil
+}
+
+// LocalDate returns the calendar date of t in loc.
+func LocalDate(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(DateLayout)
+}
+
+// Record applies activity on the given local date to a streak and returns the new state.
+// Missed days are bridged by spending streak freezes; if there are not enough, the streak restarts.
+// Activity on a date already counted, or on an earlier date, leaves the streak unchanged.
+func Record(s models.Streak, date string) models.Streak {
+	if s.LastActiveDate == "" {
+		s.Current = 1
+		s.LastActiveDate = date
+		return awardMilestone(s)
+	}
+
+	gap := daysBetween(s.LastActiveDate, date)
+	switch {
+	case gap <= 0:
+		return s
+	case gap == 1:
+		s.Current++
+	case gap-1 <= s.FreezesAvailable:
+		missed := gap - 1
+		s.FreezesAvailable -= missed
+		s.FreezesUsed += missed
+		s.Current++
+	default:
+		s.Current = 1
+	}
+
+	s.LastActiveDate = date
+	return awardMilestone(s)
+}
+
+// Effective returns the streak as it should be shown on the given local date.
+// A streak that can no longer be continued today, even with freezes, is shown as zero.
+func Effective(s models.Streak, today string) models.Streak {
+	if s.LastActiveDate == "" {
+		return s
+	}
+	// Missing yesterday is bridgeable until today ends, so only days before yesterday count.
+	if missed := daysBetween(s.LastActiveDate, today) - 1; missed > s.FreezesAvailable {
+		s.Current = 0
+	}
+	return s
+}
+
+// awardMilestone grants a freeze when the streak lands on a milestone.
+func awardMilestone(s models.Streak) models.Streak {
+	if s.Current > s.Longest {
+		s.Longest = s.Current
+	}
+	if s.Current%FreezeMilestone == 0 && s.FreezesAvailable < MaxFreezes {
+		s.FreezesAvailable++
+	}
+	return s
+}
+
+// daysBetween returns the number of calendar days from one date to another.
+// Unparseable dates are treated as far apart so the streak restarts.
+func daysBetween(from, to string) int {
+	start, err := time.Parse(DateLayout, from)
+	if err != nil {
+		return 1 << 30
+	}
+	end, err := time.Parse(DateLayout, to)
+	if err != nil {
+		return 1 << 30
+	}
+	return int(end.Sub(start).Hours() / 24)
+}
@@ -0,0 +1,125 @@
+// FILE: services/users/internal/subscriptions/appstore.go
+// App Store receipts are StoreKit 2 signed transactions: JWS documents signed by a key whose
+// certificate chain, carried in the x5c header, leads to Apple's root CA.
+
+package subscriptions
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"gopkg.in/go-jose/go-jose.v2"
+)
+
+// appStoreSubscription is the transaction type of auto-renewable subscriptions
+const appStoreSubscription = "Auto-Renewable Subscription"
+
+// Extensions Apple marks its certificates with. Other certificates under the same root, such as
+// developers' Apple Pay certificates, carry neither, so a chain must have both to sign transactions.
+var (
+	receiptSigningOID = asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 6, 11, 1} // App Store receipt signing (leaf)
+	wwdrOID           = asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 6, 2, 1}  // Worldwide Developer Relations (intermediate)
+)
+
+// AppStore verifies signed transactions of one app.
+type AppStore struct {
+	bundleID     string
+	roots        *x509.CertPool
+	allowSandbox bool
+}
+
+// NewAppStore creates a verifier for the app with bundleID, trusting the root certificate root
+// (Apple Root CA - G3, PEM or DER encoded). Sandbox and Xcode transactions are rejected unless
+// allowSandbox is set.
+func NewAppStore(bundleID string, root []byte, allowSandbox bool) (*AppStore, error) {
+	if block, _ := pem.Decode(root); block != nil {
+		root = block.Bytes
+	}
+	cert, err := x509.ParseCertificate(root)
+	if err != nil {
+		return nil, fmt.Errorf("parse App Store root certificate: %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	return &AppStore{bundleID: bundleID, roots: roots, allowSandbox: allowSandbox}, nil
+}
+
+// Verify checks the signed transaction receipt (a StoreKit 2 jwsRepresentation) and returns the
+// subscription it renews. A revoked subscription ends when Apple revoked it.
+func (a *AppStore) Verify(ctx context.Context, receipt string) (*Verification, error) {
+	jws, err := jose.ParseSigned(receipt)
+	if err != nil || len(jws.Signatures) != 1 {
+		return nil, ErrInvalidReceipt
+	}
+	header := jws.Signatures[0].Protected
+	if header.Algorithm != string(jose.ES256) {
+		return nil, ErrInvalidReceipt
+	}
+	chains, err := header.Certificates(x509.VerifyOptions{Roots: a.roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReceipt, err)
+	}
+	if chain := chains[0]; len(chain) < 3 || !hasExtension(chain[0], receiptSigningOID) || !hasExtension(chain[1], wwdrOID) {
+		return nil, fmt.Errorf("%w: not signed by the App Store", ErrInvalidReceipt)
+	}
+	payload, err := jws.Verify(chains[0][0].PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReceipt, err)
+	}
+
+	var transaction struct {
+		OriginalTransactionID string `json:"originalTransactionId"`
+		TransactionID         string `json:"transactionId"`
+		BundleID              string `json:"bundleId"`
+		ProductID             string `json:"productId"`
+		Type                  string `json:"type"`
+		ExpiresDate           int64  `json:"expiresDate"`    // Milliseconds since the epoch
+		RevocationDate        int64  `json:"revocationDate"` // Set when Apple refunded the transaction
+		Environment           string `json:"environment"`    // "Production", "Sandbox", or "Xcode"
+	}
+	if err := json.Unmarshal(payload, &transaction); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReceipt, err)
+	}
+	if transaction.BundleID != a.bundleID {
+		return nil, fmt.Errorf("%w: transaction of %q", ErrInvalidReceipt, transaction.BundleID)
+	}
+	if transaction.Type != appStoreSubscription || transaction.ExpiresDate == 0 {
+		return nil, fmt.Errorf("%w: %q transaction", ErrInvalidReceipt, transaction.Type)
+	}
+
+	expiresAt := time.UnixMilli(transaction.ExpiresDate).UTC()
+	if revokedAt := time.UnixMilli(transaction.RevocationDate).UTC(); transaction.RevocationDate != 0 && revokedAt.Before(expiresAt) {
+		expiresAt = revokedAt
+	}
+	// App Review and TestFlight purchase in the sandbox, whose subscriptions cost nothing; they are
+	// only accepted where sandbox purchases are allowed
+	environment := "sandbox"
+	if transaction.Environment == "Production" {
+		environment = "production"
+	} else if !a.allowSandbox {
+		return nil, fmt.Errorf("%w: %s transaction", ErrInvalidReceipt, transaction.Environment)
+	}
+	return &Verification{
+		Platform:      PlatformAppStore,
+		ProductID:     transaction.ProductID,
+		OriginalID:    transaction.OriginalTransactionID,
+		TransactionID: transaction.TransactionID,
+		Environment:   environment,
+		ExpiresAt:     expiresAt,
+	}, nil
+}
+
+// hasExtension reports whether cert carries the extension id
+func hasExtension(cert *x509.Certificate, id asn1.ObjectIdentifier) bool {
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
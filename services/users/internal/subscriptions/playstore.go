@@ -0,0 +1,152 @@
+// FILE: services/users/internal/subscriptions/playstore.go
+// Play Store receipts are purchase tokens, which are looked up with the Play Developer API using a
+// service account of the Play Console.
+
+package subscriptions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/oauth2/jwt"
+)
+
+const (
+	// playAPI is the base URL of the Play Developer API's applications
+	playAPI = "https://androidpublisher.googleapis.com/androidpublisher/v3/applications/"
+	// playScope is the OAuth scope of the Play Developer API
+	playScope = "https://www.googleapis.com/auth/androidpublisher"
+)
+
+// PlayStore verifies purchase tokens of one app.
+type PlayStore struct {
+	packageName string
+	httpClient  *http.Client // Authorizes requests as the service account
+}
+
+// NewPlayStore creates a verifier for the app with packageName from a service account's JSON key.
+func NewPlayStore(packageName string, credentials []byte) (*PlayStore, error) {
+	var key struct {
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+		TokenURI    string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(credentials, &key); err != nil {
+		return nil, fmt.Errorf("parse Play Store credentials: %v", err)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" || key.TokenURI == "" {
+		return nil, errors.New("parse Play Store credentials: not a service account key")
+	}
+
+	conf := &jwt.Config{
+		Email:      key.ClientEmail,
+		PrivateKey: []byte(key.PrivateKey),
+		TokenURL:   key.TokenURI,
+		Scopes:     []string{playScope},
+	}
+	httpClient := conf.Client(context.Background())
+	httpClient.Timeout = 10 * time.Second
+	return &PlayStore{packageName: packageName, httpClient: httpClient}, nil
+}
+
+// playSubscription is the subset of a Play Developer API SubscriptionPurchaseV2 the service uses.
+type playSubscription struct {
+	SubscriptionState    string    `json:"subscriptionState"`
+	AcknowledgementState string    `json:"acknowledgementState"`
+	LatestOrderID        string    `json:"latestOrderId"`
+	TestPurchase         *struct{} `json:"testPurchase"` // Present for license testers' purchases
+	LineItems            []struct {
+		ProductID  string    `json:"productId"`
+		ExpiryTime time.Time `json:"expiryTime"`
+	} `json:"lineItems"`
+}
+
+// Verify looks up the purchase token receipt and returns the subscription it is for. Subscriptions
+// that are on hold, paused, or expired end now; canceled ones run to the end of the paid period.
+// A new purchase is acknowledged, as Google refunds those left unacknowledged for three days.
+func (p *PlayStore) Verify(ctx context.Context, receipt string) (*Verification, error) {
+	tokenURL := playAPI + url.PathEscape(p.packageName) + "/purchases/subscriptionsv2/tokens/" + url.PathEscape(receipt)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("play developer api: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusGone:
+		return nil, fmt.Errorf("%w: play developer api: status %d", ErrInvalidReceipt, resp.StatusCode)
+	default:
+		return nil, fmt.Errorf("play developer api: status %d", resp.StatusCode)
+	}
+	var subscription playSubscription
+	if err := json.NewDecoder(resp.Body).Decode(&subscription); err != nil {
+		return nil, fmt.Errorf("play developer api: decode subscription: %v", err)
+	}
+	if len(subscription.LineItems) == 0 {
+		return nil, fmt.Errorf("%w: no subscription line items", ErrInvalidReceipt)
+	}
+
+	// A token covers one product, or several bought together; the last to expire decides
+	item := subscription.LineItems[0]
+	for _, other := range subscription.LineItems[1:] {
+		if other.ExpiryTime.After(item.ExpiryTime) {
+			item = other
+		}
+	}
+	expiresAt := item.ExpiryTime.UTC()
+	switch subscription.SubscriptionState {
+	case "SUBSCRIPTION_STATE_ACTIVE", "SUBSCRIPTION_STATE_IN_GRACE_PERIOD":
+		if subscription.AcknowledgementState == "ACKNOWLEDGEMENT_STATE_PENDING" {
+			p.acknowledge(ctx, item.ProductID, receipt)
+		}
+	case "SUBSCRIPTION_STATE_CANCELED":
+	default:
+		if now := time.Now().UTC(); now.Before(expiresAt) {
+			expiresAt = now
+		}
+	}
+
+	environment := "production"
+	if subscription.TestPurchase != nil {
+		environment = "sandbox"
+	}
+	return &Verification{
+		Platform:      PlatformPlayStore,
+		ProductID:     item.ProductID,
+		OriginalID:    receipt,
+		TransactionID: subscription.LatestOrderID,
+		Environment:   environment,
+		ExpiresAt:     expiresAt,
+	}, nil
+}
+
+// acknowledge acknowledges a new subscription purchase. A failure is only logged; the client
+// submitting the token again retries it.
+func (p *PlayStore) acknowledge(ctx context.Context, productID, token string) {
+	ackURL := playAPI + url.PathEscape(p.packageName) + "/purchases/subscriptions/" + url.PathEscape(productID) + "/tokens/" + url.PathEscape(token) + ":acknowledge"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ackURL, nil)
+	if err != nil {
+		log.Printf("WARN: Failed to acknowledge Play Store purchase of %s: %v", productID, err)
+		return
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		log.Printf("WARN: Failed to acknowledge Play Store purchase of %s: %v", productID, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("WARN: Failed to acknowledge Play Store purchase of %s: status %d", productID, resp.StatusCode)
+	}
+}
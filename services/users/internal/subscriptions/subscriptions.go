@@ -0,0 +1,190 @@
+// FILE: services/users/internal/subscriptions/subscriptions.go
+// This package verifies subscription receipts with the App Store and Play Store and keeps each
+// user's latest subscription on their profile, where it decides their tier. Stores are only asked
+// when a client submits a receipt, so clients submit the latest one again whenever the store
+// renews the subscription.
+
+package subscriptions
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"slices"
+	"time"
+
+	"wise-owl/lib/database"
+	"wise-owl/lib/usage"
+	"wise-owl/services/users/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The stores subscriptions are sold in
+const (
+	PlatformAppStore  = "app_store"
+	PlatformPlayStore = "play_store"
+)
+
+// PurchasesCollection keeps the store's references to every subscription submitted
+const PurchasesCollection = "purchases"
+
+var (
+	// ErrInvalidReceipt is returned when a store does not confirm a receipt as a subscription of this app
+	ErrInvalidReceipt = errors.New("subscriptions: invalid receipt")
+	// ErrPurchaseInUse is returned when a subscription was already submitted by another user
+	ErrPurchaseInUse = errors.New("subscriptions: purchase belongs to another user")
+	// ErrUserNotFound is returned when the user has no profile
+	ErrUserNotFound = errors.New("subscriptions: user not found")
+)
+
+// Verification is what a store confirmed about a subscription.
+type Verification struct {
+	Platform      string
+	ProductID     string
+	OriginalID    string // Stays the same across renewals
+	TransactionID string // The latest renewal
+	Environment   string // "production" or "sandbox"
+	ExpiresAt     time.Time
+}
+
+// Verifier confirms receipts with one store.
+type Verifier interface {
+	// Verify returns what the store confirms about receipt, or ErrInvalidReceipt.
+	Verify(ctx context.Context, receipt string) (*Verification, error)
+}
+
+// VerifiersFromEnv creates a verifier for each store configured in the environment, keyed by platform:
+// the App Store with APP_STORE_BUNDLE_ID and APP_STORE_ROOT_CERT (the path of Apple Root CA - G3),
+// accepting sandbox purchases only with APP_STORE_ALLOW_SANDBOX=true, and
+// the Play Store with PLAY_STORE_PACKAGE_NAME and PLAY_STORE_CREDENTIALS (the path of a service account
+// key with access to the Play Developer API).
+func VerifiersFromEnv() map[string]Verifier {
+	verifiers := make(map[string]Verifier)
+
+	if bundleID, rootPath := os.Getenv("APP_STORE_BUNDLE_ID"), os.Getenv("APP_STORE_ROOT_CERT"); bundleID != "" && rootPath != "" {
+		var verifier *AppStore
+		root, err := os.ReadFile(rootPath)
+		if err == nil {
+			verifier, err = NewAppStore(bundleID, root, os.Getenv("APP_STORE_ALLOW_SANDBOX") == "true")
+		}
+		if err != nil {
+			log.Printf("ERROR: App Store purchases cannot be verified: %v", err)
+		} else {
+			verifiers[PlatformAppStore] = verifier
+		}
+	} else {
+		log.Println("WARN: APP_STORE_BUNDLE_ID/APP_STORE_ROOT_CERT not set; App Store purchases cannot be verified")
+	}
+
+	if packageName, credentialsPath := os.Getenv("PLAY_STORE_PACKAGE_NAME"), os.Getenv("PLAY_STORE_CREDENTIALS"); packageName != "" && credentialsPath != "" {
+		var verifier *PlayStore
+		credentials, err := os.ReadFile(credentialsPath)
+		if err == nil {
+			verifier, err = NewPlayStore(packageName, credentials)
+		}
+		if err != nil {
+			log.Printf("ERROR: Play Store purchases cannot be verified: %v", err)
+		} else {
+			verifiers[PlatformPlayStore] = verifier
+		}
+	} else {
+		log.Println("WARN: PLAY_STORE_PACKAGE_NAME/PLAY_STORE_CREDENTIALS not set; Play Store purchases cannot be verified")
+	}
+	return verifiers
+}
+
+// Store reads users' tiers and records their subscriptions.
+type Store struct {
+	users     database.CollectionInterface
+	purchases *mongo.Collection
+}
+
+// NewStore creates a store over the users database.
+func NewStore(db *mongo.Database) *Store {
+	return &Store{users: database.NewUserScopedCollection(db.Collection("users")), purchases: db.Collection(PurchasesCollection)}
+}
+
+// Find returns the user userID is an identity of, or ErrUserNotFound.
+func (s *Store) Find(ctx context.Context, userID string) (*models.User, error) {
+	var user models.User
+	err := s.users.FindOne(ctx, bson.M{"auth0_ids": userID}).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Tier returns the current tier of the user userID is an identity of. Users without a profile are
+// on the free tier. It is the tier source of the users service's meter.
+func (s *Store) Tier(ctx context.Context, userID string) (string, error) {
+	user, err := s.Find(ctx, userID)
+	if errors.Is(err, ErrUserNotFound) {
+		return usage.TierFree, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return user.TierAt(time.Now()), nil
+}
+
+// Record records a verified subscription submitted by userID and returns their profile afterwards.
+// It becomes the user's subscription unless they have another one paid for longer, and a
+// subscription already submitted by another user is refused with ErrPurchaseInUse.
+func (s *Store) Record(ctx context.Context, userID string, v *Verification, now time.Time) (*models.User, error) {
+	user, err := s.Find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := bson.M{"platform": v.Platform, "original_id": v.OriginalID}
+	update := bson.M{
+		"$set": bson.M{
+			"product_id":     v.ProductID,
+			"transaction_id": v.TransactionID,
+			"environment":    v.Environment,
+			"expires_at":     v.ExpiresAt,
+			"verified_at":    now,
+		},
+		"$setOnInsert": bson.M{"user_id": userID, "created_at": now},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var purchase models.Purchase
+	err = s.purchases.FindOneAndUpdate(ctx, key, update, opts).Decode(&purchase)
+	if mongo.IsDuplicateKeyError(err) {
+		// The same receipt was submitted concurrently and the other request created the record
+		err = s.purchases.FindOneAndUpdate(ctx, key, update, opts).Decode(&purchase)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(user.Auth0IDs, purchase.UserID) {
+		return nil, ErrPurchaseInUse
+	}
+
+	subscription := models.Subscription{
+		Tier:       usage.TierPremium,
+		Platform:   v.Platform,
+		ProductID:  v.ProductID,
+		RenewsAt:   v.ExpiresAt,
+		PurchaseID: purchase.ID,
+		VerifiedAt: now,
+	}
+	// A receipt of the current subscription always updates it, even to end it early after a refund
+	filter := bson.M{"_id": user.ID, "$or": bson.A{
+		bson.M{"subscription": bson.M{"$exists": false}},
+		bson.M{"subscription.purchase_id": purchase.ID},
+		bson.M{"subscription.renews_at": bson.M{"$lt": v.ExpiresAt}},
+	}}
+	if _, err := s.users.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"subscription": subscription, "updated_at": now}}); err != nil {
+		return nil, err
+	}
+	return s.Find(ctx, userID)
+}